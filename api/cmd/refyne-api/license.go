@@ -0,0 +1,168 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/license"
+)
+
+const licenseUsage = `Usage: refyne-api license <command> [flags]
+
+Commands:
+  keygen   Generate a license signing keypair
+  issue    Issue a license signed with a local private key
+  verify   Verify a license key and print its claims
+
+Issued licenses are verified with REFYNE_LICENSE_PUBLIC_KEY (or the build-time key),
+so test licenses require the server to be started with the matching public key.
+`
+
+// runLicenseCommand runs a license subcommand and returns the process exit code.
+func runLicenseCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		_, _ = fmt.Fprint(stderr, licenseUsage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "keygen":
+		err = licenseKeygen(stdout)
+	case "issue":
+		err = licenseIssue(args[1:], stdout, stderr)
+	case "verify":
+		err = licenseVerify(args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		_, _ = fmt.Fprint(stdout, licenseUsage)
+		return 0
+	default:
+		_, _ = fmt.Fprintf(stderr, "unknown license command %q\n\n%s", args[0], licenseUsage)
+		return 2
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func licenseKeygen(stdout io.Writer) error {
+	publicKey, privateKey, err := license.GenerateKey()
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(stdout, "REFYNE_LICENSE_PUBLIC_KEY=%s\nREFYNE_LICENSE_SIGNING_KEY=%s\n", publicKey, privateKey)
+	return nil
+}
+
+func licenseIssue(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("license issue", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	signingKey := fs.String("key", os.Getenv("REFYNE_LICENSE_SIGNING_KEY"), "Base64 private key (default $REFYNE_LICENSE_SIGNING_KEY)")
+	org := fs.String("org", "", "Licensed organization name")
+	email := fs.String("email", "", "License contact email")
+	tier := fs.String("tier", "selfhosted", "Licensed tier")
+	maxUsers := fs.Int("max-users", 0, "Maximum users, recorded for information only (0 = unlimited)")
+	features := fs.String("features", "", "Comma-separated licensed features")
+	validFor := fs.Duration("valid-for", 30*24*time.Hour, "How long the license is valid (0 = perpetual)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *signingKey == "" {
+		return fmt.Errorf("a signing key is required (-key or REFYNE_LICENSE_SIGNING_KEY)")
+	}
+	if *org == "" {
+		return fmt.Errorf("-org is required")
+	}
+	key, err := license.ParsePrivateKey(*signingKey)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	claims := license.Claims{
+		ID:               ulid.Make().String(),
+		OrganizationName: *org,
+		Email:            *email,
+		Tier:             *tier,
+		MaxUsers:         *maxUsers,
+		Features:         splitFeatures(*features),
+		IssuedAt:         now.Unix(),
+	}
+	if *validFor > 0 {
+		claims.ExpiresAt = now.Add(*validFor).Unix()
+	}
+
+	token, err := license.Issue(claims, key)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintln(stdout, token)
+	return nil
+}
+
+func licenseVerify(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("license verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	publicKey := fs.String("public-key", os.Getenv("REFYNE_LICENSE_PUBLIC_KEY"), "Base64 public key (default $REFYNE_LICENSE_PUBLIC_KEY, then the build-time key)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: refyne-api license verify [-public-key KEY] LICENSE_KEY")
+	}
+
+	keyStr := *publicKey
+	if keyStr == "" {
+		keyStr = license.PublicKey
+	}
+	key, err := license.ParsePublicKey(keyStr)
+	if err != nil {
+		return err
+	}
+	claims, err := license.Verify(fs.Arg(0), key)
+	if err != nil {
+		return err
+	}
+
+	expires := "never"
+	if expiry := claims.Expiry(); expiry != nil {
+		expires = expiry.Format(time.RFC3339)
+	}
+	maxUsers := "unlimited"
+	if claims.MaxUsers > 0 {
+		maxUsers = fmt.Sprint(claims.MaxUsers)
+	}
+	_, _ = fmt.Fprintf(stdout, "license:      %s\norganization: %s\nemail:        %s\ntier:         %s\nmax users:    %s\nfeatures:     %s\nissued:       %s\nexpires:      %s\nstatus:       %s\n",
+		claims.ID,
+		claims.OrganizationName,
+		claims.Email,
+		claims.Tier,
+		maxUsers,
+		strings.Join(claims.Features, ", "),
+		time.Unix(claims.IssuedAt, 0).UTC().Format(time.RFC3339),
+		expires,
+		claims.StatusAt(time.Now(), 0),
+	)
+	return nil
+}
+
+// splitFeatures parses a comma-separated feature list, dropping empty entries.
+func splitFeatures(s string) []string {
+	var features []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			features = append(features, f)
+		}
+	}
+	return features
+}
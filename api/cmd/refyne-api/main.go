@@ -32,6 +32,7 @@ import (
 	"github.com/jmylchreest/refyne-api/internal/http/routes"
 	"github.com/jmylchreest/refyne-api/internal/llm"
 	"github.com/jmylchreest/refyne-api/internal/logging"
+	"github.com/jmylchreest/refyne-api/internal/observability"
 	"github.com/jmylchreest/refyne-api/internal/repository"
 	"github.com/jmylchreest/refyne-api/internal/service"
 	"github.com/jmylchreest/refyne-api/internal/shutdown"
@@ -40,6 +41,11 @@ import (
 )
 
 func main() {
+	// Subcommands run instead of the server
+	if len(os.Args) > 1 && os.Args[1] == "license" {
+		os.Exit(runLicenseCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Initialize logger with TTY detection, source paths, and format control
 	logger := logging.SetDefault()
 
@@ -167,6 +173,41 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	jobWorker.Start(ctx)
 
+	// Export trace spans to an OTLP collector when configured
+	shutdownTracing := func(context.Context) error { return nil }
+	if cfg.TracingEnabled {
+		shutdownTracing, err = observability.SetupTracing(ctx, observability.TracingConfig{
+			ServiceName:    "refyne-api",
+			ServiceVersion: v.Version,
+		})
+		if err != nil {
+			logger.Error("failed to set up tracing", "error", err)
+			shutdownTracing = func(context.Context) error { return nil }
+		} else {
+			logger.Info("OTLP tracing enabled")
+		}
+	}
+
+	// Worker utilisation and job queue depth are read when /metrics is scraped
+	observability.RegisterWorker(jobWorker.Concurrency(), jobWorker.ActiveJobs)
+	observability.RegisterJobQueue(func(ctx context.Context) (map[string]int, map[string]int, error) {
+		stats, err := repos.Analytics.GetJobQueueStats(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return stats.PendingByTier, stats.RunningByTier, nil
+	})
+
+	// Verify the self-hosted license, re-checking periodically so expiry applies without a restart
+	var licenseEntitlements mw.LicenseEntitlements
+	if services.License != nil {
+		if _, err := services.License.Load(ctx); err != nil {
+			logger.Error("failed to load license", "error", err)
+		}
+		services.License.Start(ctx, cfg.LicenseCheckInterval)
+		licenseEntitlements = services.License
+	}
+
 	// Start cleanup service if enabled
 	if cfg.CleanupEnabled {
 		cleanupSvc := service.NewCleanupService(
@@ -195,6 +236,7 @@ func main() {
 			"/livez",
 			"/readyz",
 			"/api/v1/health",
+			"/metrics", // Scrapes shouldn't keep an idle instance running
 		},
 		BackgroundWorkCheck: func() bool {
 			activeJobs := jobWorker.ActiveJobs() > 0
@@ -329,6 +371,9 @@ func main() {
 	humaConfig := routes.NewHumaConfig(cfg.BaseURL)
 	api := humachi.New(router, humaConfig)
 
+	// Request latency metrics and server spans (first, so they cover auth and rate limiting)
+	api.UseMiddleware(mw.HumaObservability())
+
 	// Add Huma middleware for authentication based on operation security requirements
 	api.UseMiddleware(mw.HumaAuth(api, mw.HumaAuthConfig{
 		ClerkVerifier:     clerkVerifier,
@@ -336,19 +381,26 @@ func main() {
 		SubscriptionCache: services.SubscriptionCache,
 		UsageService:      services.Usage,
 		JobService:        services.Job,
+		License:           licenseEntitlements,
 	}))
 
-	// Add user-based rate limiting for operations that need it
-	api.UseMiddleware(mw.HumaRateLimit(mw.DefaultRateLimitConfig()))
+	// Add per-key and per-user request limits (minute/hour/day), persisted so they survive restarts
+	rateLimitCfg := mw.DefaultRateLimitConfig()
+	rateLimitCfg.Store = repos.RateLimit
+	api.UseMiddleware(mw.HumaRateLimit(api, rateLimitCfg))
 
 	// Initialize all handlers
 	readyzHandler := handlers.NewReadyzHandler(db)
 	jobHandler := handlers.NewJobHandlerWithWebhook(services.Job, services.Storage, services.Webhook)
 	usageHandler := handlers.NewUsageHandler(services.Usage)
+	spendCapHandler := handlers.NewSpendCapHandler(services.SpendCap)
+	billingHandler := handlers.NewBillingHandler(services.Balance)
 	userLLMHandler := handlers.NewUserLLMHandler(services.UserLLM, services.Admin, providerRegistry)
-	adminHandler := handlers.NewAdminHandler(services.Admin, services.TierSync, providerRegistry)
+	proxyHandler := handlers.NewProxyHandler(services.Proxy)
+	authProfileHandler := handlers.NewAuthProfileHandler(services.AuthProfile)
+	adminHandler := handlers.NewAdminHandler(services.Admin, services.TierSync, providerRegistry, services.ProviderHealth)
 	adminAnalyticsHandler := handlers.NewAdminAnalyticsHandler(repos.Analytics, services.Storage)
-	metricsHandler := handlers.NewMetricsHandler(repos)
+	metricsHandler := handlers.NewMetricsHandler(repos, services.ProviderHealth)
 	schemaCatalogHandler := handlers.NewSchemaCatalogHandler(repos.SchemaCatalog)
 	savedSitesHandler := handlers.NewSavedSitesHandler(repos.SavedSites)
 	var webhookEncryptor *crypto.Encryptor
@@ -370,7 +422,11 @@ func main() {
 		Job:            jobHandler,
 		Crawl:          crawlHandler,
 		Usage:          usageHandler,
+		SpendCap:       spendCapHandler,
+		Billing:        billingHandler,
 		UserLLM:        userLLMHandler,
+		Proxy:          proxyHandler,
+		AuthProfile:    authProfileHandler,
 		SchemaCatalog:  schemaCatalogHandler,
 		SavedSites:     savedSitesHandler,
 		Webhook:        webhookHandler,
@@ -381,9 +437,11 @@ func main() {
 		Metrics:        metricsHandler,
 	}
 
-	// Add API key handler in hosted mode
+	// Add API key handler in hosted mode, and license admin in self-hosted mode
 	if !cfg.IsSelfHosted() {
 		routeHandlers.APIKey = handlers.NewAPIKeyHandler(services.APIKey)
+	} else {
+		routeHandlers.License = handlers.NewLicenseHandler(services.License)
 	}
 
 	// Register all routes using shared definitions
@@ -401,10 +459,18 @@ func main() {
 	// Raw HTTP handlers for format-aware responses (non-JSON content types)
 	// These use Chi middleware for auth since they're not Huma operations.
 	// RegisterRawEndpoints (called by routes.Register) adds them to OpenAPI with proper security.
-	chiAuthMiddleware := mw.Auth(clerkVerifier, services.Auth, services.SubscriptionCache)
+	chiAuthMiddleware := mw.Auth(clerkVerifier, services.Auth, services.SubscriptionCache, licenseEntitlements)
 	router.With(chiAuthMiddleware).Get("/api/v1/jobs/{id}/results", jobHandler.GetJobResultsRaw)
 	router.With(chiAuthMiddleware).Get("/api/v1/jobs/{id}/stream", jobHandler.StreamResults)
 
+	// Prometheus metrics (a plain route, so it stays out of the OpenAPI spec)
+	if cfg.MetricsEnabled {
+		if cfg.MetricsToken == "" {
+			logger.Warn("metrics are enabled without METRICS_TOKEN, so /metrics is publicly readable")
+		}
+		router.Handle("/metrics", observability.Handler(cfg.MetricsToken))
+	}
+
 	// Create server with h2c (HTTP/2 cleartext) support for Fly.io proxy
 	// WriteTimeout must be long enough for LLM requests (can take 60-120s for complex pages)
 	h2s := &http2.Server{}
@@ -455,6 +521,11 @@ func main() {
 		if err := server.Shutdown(shutdownCtx); err != nil {
 			logger.Error("server shutdown error", "error", err)
 		}
+
+		// Flush buffered spans
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Error("tracing shutdown error", "error", err)
+		}
 	}()
 
 	// Start server
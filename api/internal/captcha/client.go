@@ -10,6 +10,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/observability"
 )
 
 // Client communicates with the captcha service.
@@ -64,15 +66,88 @@ type ProxyConfig struct {
 	Password string `json:"password,omitempty"`
 }
 
+// Action is a scripted browser interaction the captcha service runs after the page
+// loads and before capturing it (click, scroll_to_bottom, type, select, hover, wait, evaluate).
+type Action struct {
+	Type     string `json:"type"`
+	Selector string `json:"selector,omitempty"` // CSS selector (click, type, select, hover, wait)
+	Text     string `json:"text,omitempty"`     // Text to type, or option text to select
+	Script   string `json:"script,omitempty"`   // Safe-listed snippet name (evaluate)
+	Repeat   int    `json:"repeat,omitempty"`   // Max repetitions (click, scroll_to_bottom)
+	Delay    int    `json:"delay,omitempty"`    // Delay in ms after the action (after each repetition)
+	Timeout  int    `json:"timeout,omitempty"`  // Max time in ms to wait for the selector
+	Optional bool   `json:"optional,omitempty"` // Continue if the action fails
+}
+
+// NetworkCapture selects XHR/fetch responses for the captcha service to record while
+// the page loads: responses whose URL matches one of URLPatterns (regular expressions)
+// or whose MIME type contains one of ContentTypes. With neither set, JSON responses are recorded.
+type NetworkCapture struct {
+	URLPatterns  []string `json:"urlPatterns,omitempty"`
+	ContentTypes []string `json:"contentTypes,omitempty"`
+	MaxResponses int      `json:"maxResponses,omitempty"` // Default 50
+	MaxBodySize  int      `json:"maxBodySize,omitempty"`  // Bytes per response body (default 1MB)
+}
+
+// ScreenshotCapture asks the captcha service for full-page screenshots, split into
+// segments of SegmentHeight CSS pixels from the top of the page.
+type ScreenshotCapture struct {
+	SegmentHeight int    `json:"segmentHeight,omitempty"` // Default 2000
+	MaxSegments   int    `json:"maxSegments,omitempty"`   // Default 8
+	Format        string `json:"format,omitempty"`        // "jpeg" (default) or "png"
+	Quality       int    `json:"quality,omitempty"`       // JPEG quality (default 80)
+}
+
+// Screenshot is one segment of a full-page screenshot.
+type Screenshot struct {
+	Data     string `json:"data"`     // Base64-encoded image
+	MimeType string `json:"mimeType"` // image/jpeg or image/png
+	Offset   int    `json:"offset"`   // CSS pixels from the top of the page
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// ResourcePolicy selects page resources for the captcha service to block while rendering:
+// a built-in profile ("text_only", "no_media" or "full") plus custom block lists.
+type ResourcePolicy struct {
+	Profile            string   `json:"profile,omitempty"`
+	BlockResourceTypes []string `json:"blockResourceTypes,omitempty"`
+	BlockDomains       []string `json:"blockDomains,omitempty"`
+	BlockURLPatterns   []string `json:"blockUrlPatterns,omitempty"` // URL globs
+}
+
+// NetworkResponse is an XHR/fetch response recorded during the page load.
+type NetworkResponse struct {
+	URL             string            `json:"url"`
+	Method          string            `json:"method"`
+	Status          int               `json:"status"`
+	StatusText      string            `json:"statusText,omitempty"`
+	MimeType        string            `json:"mimeType,omitempty"`
+	RequestHeaders  map[string]string `json:"requestHeaders,omitempty"`
+	RequestBody     string            `json:"requestBody,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	Body            string            `json:"body"`
+	BodyTruncated   bool              `json:"bodyTruncated,omitempty"`
+	StartedAt       int64             `json:"startedAt"`  // Unix timestamp ms
+	DurationMS      int64             `json:"durationMs"` // Time from request to response end
+}
+
 // SolveRequest is the request to solve a captcha challenge.
 type SolveRequest struct {
-	Cmd            string       `json:"cmd"`
-	URL            string       `json:"url,omitempty"`
-	Session        string       `json:"session,omitempty"`
-	MaxTimeout     int          `json:"maxTimeout,omitempty"`
-	Cookies        []Cookie     `json:"cookies,omitempty"`
-	Proxy          *ProxyConfig `json:"proxy,omitempty"`
-	ExternalAPIKey string       `json:"externalApiKey,omitempty"` // API key for external captcha services (2captcha, etc.)
+	Cmd            string          `json:"cmd"`
+	URL            string          `json:"url,omitempty"`
+	Session        string          `json:"session,omitempty"`
+	MaxTimeout     int             `json:"maxTimeout,omitempty"`
+	Cookies        []Cookie        `json:"cookies,omitempty"`
+	Proxy          *ProxyConfig    `json:"proxy,omitempty"`
+	Actions        []Action        `json:"actions,omitempty"`        // Browser actions run before capture
+	CaptureNetwork *NetworkCapture `json:"captureNetwork,omitempty"` // Record matching XHR/fetch responses
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"` // Resources to block while rendering
+	ExternalAPIKey string          `json:"externalApiKey,omitempty"` // API key for external captcha services (2captcha, etc.)
+
+	MaxSolverCostUSD *float64 `json:"maxSolverCostUsd,omitempty"` // Most all paid CAPTCHA solve attempts may cost together (omit for no limit)
+
+	CaptureScreenshots *ScreenshotCapture `json:"captureScreenshots,omitempty"` // Full-page segmented screenshots
 }
 
 // Solution contains the solved page data.
@@ -80,32 +155,53 @@ type Solution struct {
 	URL        string   `json:"url"`
 	Status     int      `json:"status"`
 	Headers    map[string]string `json:"headers,omitempty"`
+	Cookies    []Cookie          `json:"cookies"`
+	UserAgent  string            `json:"userAgent"`
+	Response   string            `json:"response"` // HTML content
+	Title      string            `json:"title,omitempty"`
+	Screenshot string            `json:"screenshot,omitempty"`
+	Network    []NetworkResponse `json:"network,omitempty"` // Recorded XHR/fetch responses
+	Screenshots []Screenshot     `json:"screenshots,omitempty"` // Full-page segments, top to bottom
+	Clearance  *Clearance        `json:"clearance,omitempty"`
+}
+
+// Clearance is the cookie set and user agent that passed a Cloudflare or DDoS-Guard
+// challenge. Sites bind it to the user agent and the exit IP that passed the challenge.
+type Clearance struct {
 	Cookies    []Cookie `json:"cookies"`
 	UserAgent  string   `json:"userAgent"`
-	Response   string   `json:"response"` // HTML content
-	Title      string   `json:"title,omitempty"`
-	Screenshot string   `json:"screenshot,omitempty"`
+	ObtainedAt int64    `json:"obtainedAt"` // Unix timestamp ms
+	ExpiresAt  int64    `json:"expiresAt"`  // Unix timestamp ms
 }
 
 // SolveResponse is the response from the captcha service.
 type SolveResponse struct {
-	Status         string    `json:"status"`
-	Message        string    `json:"message"`
-	Solution       *Solution `json:"solution,omitempty"`
-	Session        string    `json:"session,omitempty"`
-	Sessions       []string  `json:"sessions,omitempty"`
-	StartTimestamp int64     `json:"startTimestamp"`
-	EndTimestamp   int64     `json:"endTimestamp"`
-	Version        string    `json:"version"`
-	ChallengeType  string    `json:"challengeType,omitempty"`
-	SolverUsed     string    `json:"solverUsed,omitempty"`
-	Challenged     bool      `json:"challenged,omitempty"`
-	Solved         bool      `json:"solved,omitempty"`
-	Method         string    `json:"method,omitempty"`
+	Status         string     `json:"status"`
+	Message        string     `json:"message"`
+	Solution       *Solution  `json:"solution,omitempty"`
+	Session        string     `json:"session,omitempty"`
+	Sessions       []string   `json:"sessions,omitempty"`
+	StartTimestamp int64      `json:"startTimestamp"`
+	EndTimestamp   int64      `json:"endTimestamp"`
+	Version        string     `json:"version"`
+	ChallengeType  string     `json:"challengeType,omitempty"`
+	SolverUsed     string     `json:"solverUsed,omitempty"`
+	Challenged     bool       `json:"challenged,omitempty"`
+	Solved         bool       `json:"solved,omitempty"`
+	Method         string     `json:"method,omitempty"`
+	Usage          *UsageInfo `json:"usage,omitempty"`
 	// InstanceID is populated from fly-replay-src header for session affinity routing
 	InstanceID string `json:"-"`
 }
 
+// UsageInfo contains usage tracking data for a solve.
+type UsageInfo struct {
+	BrowserTimeMS   int64   `json:"browserTimeMs,omitempty"`
+	BrowserCostUSD  float64 `json:"browserCostUsd,omitempty"`
+	SolverCostUSD   float64 `json:"solverCostUsd,omitempty"`
+	BlockedRequests int     `json:"blockedRequests,omitempty"` // Requests blocked by the resource policy
+}
+
 // UserContext contains user information for the request.
 type UserContext struct {
 	UserID   string
@@ -192,6 +288,7 @@ func (c *Client) request(ctx context.Context, user UserContext, req SolveRequest
 
 	// Add headers
 	httpReq.Header.Set("Content-Type", "application/json")
+	observability.InjectHeaders(ctx, httpReq.Header) // Continue the caller's trace in the captcha service
 
 	// Sign the request (includes JobID in signature for integrity)
 	sig := c.signer.Sign(user.UserID, user.Tier, user.Features, user.JobID, body)
@@ -0,0 +1,235 @@
+package cleaners
+
+import (
+	"strings"
+	"testing"
+)
+
+// ========================================
+// Select Tests
+// ========================================
+
+func TestNewSelect_Validation(t *testing.T) {
+	if _, err := NewSelect(""); err == nil {
+		t.Error("expected error for empty selector")
+	}
+	if _, err := NewSelect("div[["); err == nil {
+		t.Error("expected error for invalid selector")
+	}
+	if _, err := NewSelect("#results, .product"); err != nil {
+		t.Errorf("unexpected error for selector group: %v", err)
+	}
+}
+
+func TestSelect_KeepsMatchingSubtrees(t *testing.T) {
+	c, _ := NewSelect(".product")
+	html := `<html><body><nav>Menu</nav>
+<div class="product"><h2>One</h2><div class="product">Nested</div></div>
+<aside>Ads</aside><div class="product"><h2>Two</h2></div></body></html>`
+
+	got, err := c.Clean(html)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if strings.Contains(got, "Menu") || strings.Contains(got, "Ads") {
+		t.Errorf("non-matching content should be removed, got %q", got)
+	}
+	if !strings.Contains(got, "One") || !strings.Contains(got, "Two") {
+		t.Errorf("matching content should be kept, got %q", got)
+	}
+	if strings.Count(got, "Nested") != 1 {
+		t.Errorf("nested matches should not be duplicated, got %q", got)
+	}
+}
+
+func TestSelect_NoMatchReturnsInput(t *testing.T) {
+	c, _ := NewSelect("#missing")
+	html := "<html><body><p>Content</p></body></html>"
+
+	got, err := c.Clean(html)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if got != html {
+		t.Errorf("expected unchanged input, got %q", got)
+	}
+}
+
+// ========================================
+// Readability Tests
+// ========================================
+
+const articlePage = `<html><body>
+<header class="site-header"><a href="/">Home</a> <a href="/shop">Shop</a></header>
+<nav><ul><li><a href="/a">Link A</a></li><li><a href="/b">Link B</a></li></ul></nav>
+<div class="sidebar"><p>Subscribe to our newsletter for weekly updates, offers, and news.</p></div>
+<div id="content" class="article-body">
+  <h1>Understanding Widgets</h1>
+  <p>Widgets are small, useful components, and they appear in many products across the world.</p>
+  <p>In this article, we explore how widgets are designed, manufactured, and tested before shipping.</p>
+  <p>Finally, we look at maintenance, common failure modes, and how to extend a widget's life.</p>
+</div>
+<footer><p>Copyright 2026 Example Corp. All rights reserved, everywhere, forever.</p></footer>
+</body></html>`
+
+func TestReadability_KeepsMainContent(t *testing.T) {
+	got, err := NewReadability(false).Clean(articlePage)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if !strings.Contains(got, "Widgets are small") || !strings.Contains(got, "maintenance") {
+		t.Errorf("main content missing, got %q", got)
+	}
+	for _, boilerplate := range []string{"newsletter", "Link A", "Copyright"} {
+		if strings.Contains(got, boilerplate) {
+			t.Errorf("boilerplate %q should be removed, got %q", boilerplate, got)
+		}
+	}
+	if !strings.HasPrefix(got, "<div") {
+		t.Errorf("HTML output should be the content container, got %q", got)
+	}
+}
+
+func TestReadability_TextOutput(t *testing.T) {
+	got, err := NewReadability(true).Clean(articlePage)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if strings.Contains(got, "<p>") {
+		t.Errorf("text output should not contain tags, got %q", got)
+	}
+	if !strings.Contains(got, "Understanding Widgets") {
+		t.Errorf("text output missing heading, got %q", got)
+	}
+}
+
+func TestReadability_FallsBackToBody(t *testing.T) {
+	got, err := NewReadability(true).Clean("<html><body><span>Short</span></body></html>")
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if got != "Short" {
+		t.Errorf("Clean() = %q, want body text", got)
+	}
+}
+
+// ========================================
+// ScriptData Tests
+// ========================================
+
+func TestScriptData_NextData(t *testing.T) {
+	html := `<html><body><div id="__next"></div>
+<script id="__NEXT_DATA__" type="application/json">
+  {"props": {"pageProps": {"products": [{"name": "Widget", "price": 9.99}]}}}
+</script></body></html>`
+
+	got, err := NewScriptData().Clean(html)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	want := "## __NEXT_DATA__\n" + `{"props":{"pageProps":{"products":[{"name":"Widget","price":9.99}]}}}`
+	if got != want {
+		t.Errorf("Clean() = %q, want %q", got, want)
+	}
+}
+
+func TestScriptData_StateAssignments(t *testing.T) {
+	html := `<html><body><script>
+window.__INITIAL_STATE__ = {"items": [{"title": "a } brace"}], "count": 1};
+window.__APOLLO_STATE__ = JSON.parse("{\"Product:1\":{\"name\":\"Gadget\"}}");
+var unrelated = {"x": 1};
+</script></body></html>`
+
+	got, err := NewScriptData().Clean(html)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if !strings.Contains(got, `## __INITIAL_STATE__`+"\n"+`{"items":[{"title":"a } brace"}],"count":1}`) {
+		t.Errorf("initial state missing or truncated, got %q", got)
+	}
+	if !strings.Contains(got, `## __APOLLO_STATE__`+"\n"+`{"Product:1":{"name":"Gadget"}}`) {
+		t.Errorf("JSON.parse state missing, got %q", got)
+	}
+	if strings.Contains(got, "unrelated") {
+		t.Errorf("unrelated variables should be ignored, got %q", got)
+	}
+}
+
+func TestScriptData_NoDataReturnsInput(t *testing.T) {
+	html := `<html><body><script>console.log("hi")</script><p>Static</p></body></html>`
+
+	got, err := NewScriptData().Clean(html)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if got != html {
+		t.Errorf("expected unchanged input, got %q", got)
+	}
+}
+
+// ========================================
+// Table Tests
+// ========================================
+
+const specTable = `<html><body><p>Intro</p>
+<table><caption>Specifications</caption>
+<thead><tr><th>Property</th><th>Value</th></tr></thead>
+<tbody>
+<tr><td>Weight</td><td>1.2 kg</td></tr>
+<tr><td>Size</td><td>10 | 20 cm</td></tr>
+<tr><td colspan="2">Ships worldwide, free</td></tr>
+</tbody></table></body></html>`
+
+func TestTable_Markdown(t *testing.T) {
+	c, err := NewTable("")
+	if err != nil {
+		t.Fatalf("NewTable() error = %v", err)
+	}
+	got, err := c.Clean(specTable)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+
+	want := `## Specifications
+| Property | Value |
+| --- | --- |
+| Weight | 1.2 kg |
+| Size | 10 \| 20 cm |
+| Ships worldwide, free | Ships worldwide, free |`
+	if got != want {
+		t.Errorf("Clean() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTable_CSV(t *testing.T) {
+	c, err := NewTable("csv")
+	if err != nil {
+		t.Fatalf("NewTable() error = %v", err)
+	}
+	got, err := c.Clean(specTable)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if !strings.Contains(got, "Property,Value\nWeight,1.2 kg") {
+		t.Errorf("CSV rows missing, got %q", got)
+	}
+	if !strings.Contains(got, `"Ships worldwide, free"`) {
+		t.Errorf("CSV values with commas should be quoted, got %q", got)
+	}
+}
+
+func TestTable_InvalidFormatAndNoTables(t *testing.T) {
+	if _, err := NewTable("xml"); err == nil {
+		t.Error("expected error for invalid format")
+	}
+
+	c, _ := NewTable("markdown")
+	html := "<html><body><p>No tables</p></body></html>"
+	got, err := c.Clean(html)
+	if err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if got != html {
+		t.Errorf("expected unchanged input, got %q", got)
+	}
+}
@@ -0,0 +1,190 @@
+package cleaners
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Readability extracts the main content of a page (article body, product details)
+// using readability-style heuristics: paragraphs are scored by length and punctuation,
+// scores propagate to their ancestors, and the highest scoring container adjusted for
+// link density is kept. Navigation, sidebars, footers and similar boilerplate are dropped.
+type Readability struct {
+	textOutput bool
+}
+
+// NewReadability creates a readability cleaner. When textOutput is true the main
+// content is returned as plain text; otherwise its HTML is returned so it can be
+// chained with another cleaner (e.g., refyne for markdown).
+func NewReadability(textOutput bool) *Readability {
+	return &Readability{textOutput: textOutput}
+}
+
+var (
+	// unlikelyCandidates matches class/id values of boilerplate containers.
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cookie|disqus|footer|header|menu|modal|nav|popup|related|remark|share|shoutbox|sidebar|social|sponsor|subscribe|ad-break|agegate|pagination|pager`)
+
+	// maybeCandidates protects containers that match unlikelyCandidates but are
+	// probably content (e.g., "main-header-content").
+	maybeCandidates = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow|product|post|entry`)
+
+	// positiveWeight and negativeWeight adjust a candidate's score by class/id.
+	positiveWeight = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|post|text|blog|story|product|description`)
+	negativeWeight = regexp.MustCompile(`(?i)hidden|combx|comment|com-|contact|foot|footer|footnote|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// minParagraphLength is the shortest text block that contributes to scoring.
+const minParagraphLength = 25
+
+// Clean returns the main content of the page.
+func (c *Readability) Clean(content string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	doc.Find("script, style, noscript, template, iframe, svg, nav, aside, body > header, body > footer").Remove()
+	doc.Find("*").Each(func(_ int, s *goquery.Selection) {
+		if s.Is("html, body, main, article") {
+			return
+		}
+		matchString := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if unlikelyCandidates.MatchString(matchString) && !maybeCandidates.MatchString(matchString) {
+			s.Remove()
+		}
+	})
+
+	top := c.topCandidate(doc)
+	if top == nil {
+		// Nothing scored - fall back to semantic containers or the body
+		for _, selector := range []string{"main", "article", "[role=main]", "body"} {
+			if s := doc.Find(selector).First(); s.Length() > 0 {
+				top = s
+				break
+			}
+		}
+	}
+	if top == nil {
+		return content, nil
+	}
+
+	if c.textOutput {
+		return collapseWhitespace(top.Text()), nil
+	}
+	return goquery.OuterHtml(top)
+}
+
+// topCandidate scores text blocks and returns the best container, or nil if no
+// block was long enough to score.
+func (c *Readability) topCandidate(doc *goquery.Document) *goquery.Selection {
+	scores := make(map[*html.Node]float64)
+	selections := make(map[*html.Node]*goquery.Selection)
+	var order []*html.Node // Candidates in discovery order so ties resolve deterministically
+
+	addScore := func(s *goquery.Selection, score float64) {
+		if s.Length() == 0 {
+			return
+		}
+		node := s.Get(0)
+		if _, ok := scores[node]; !ok {
+			scores[node] = initialScore(s)
+			selections[node] = s
+			order = append(order, node)
+		}
+		scores[node] += score
+	}
+
+	doc.Find("p, pre, td, blockquote, li, section > div").Each(func(_ int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if len(text) < minParagraphLength {
+			return
+		}
+
+		// One point for the block, one per comma, up to three for its length
+		score := 1.0 + float64(strings.Count(text, ","))
+		score += min(float64(len(text))/100, 3)
+
+		addScore(s.Parent(), score)
+		addScore(s.Parent().Parent(), score/2)
+	})
+
+	var best *html.Node
+	bestScore := 0.0
+	for _, node := range order {
+		adjusted := scores[node] * (1 - linkDensity(selections[node]))
+		if adjusted > bestScore {
+			best, bestScore = node, adjusted
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return selections[best]
+}
+
+// initialScore returns a candidate's starting score from its tag and class/id.
+func initialScore(s *goquery.Selection) float64 {
+	score := 0.0
+	switch goquery.NodeName(s) {
+	case "article", "main":
+		score += 10
+	case "div":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	for _, attr := range []string{"class", "id"} {
+		value := s.AttrOr(attr, "")
+		if value == "" {
+			continue
+		}
+		if negativeWeight.MatchString(value) {
+			score -= 25
+		}
+		if positiveWeight.MatchString(value) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity returns the fraction of a selection's text that is inside links.
+func linkDensity(s *goquery.Selection) float64 {
+	textLength := len(strings.TrimSpace(s.Text()))
+	if textLength == 0 {
+		return 0
+	}
+	linkLength := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLength += len(strings.TrimSpace(a.Text()))
+	})
+	return float64(linkLength) / float64(textLength)
+}
+
+// collapseWhitespace reduces runs of blank lines and spaces in extracted text.
+func collapseWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// Name returns the cleaner type.
+func (c *Readability) Name() string {
+	return "readability"
+}
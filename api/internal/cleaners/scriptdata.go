@@ -0,0 +1,160 @@
+package cleaners
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ScriptData extracts framework hydration data embedded in script tags, such as
+// Next.js __NEXT_DATA__, Nuxt state, Redux/Apollo preloaded state and other JSON
+// blobs. Single-page apps often render listings client-side from this data, so it
+// can contain far more than the static HTML.
+type ScriptData struct{}
+
+// NewScriptData creates a script data cleaner.
+func NewScriptData() *ScriptData {
+	return &ScriptData{}
+}
+
+// stateAssignment matches inline scripts that assign hydration state to a global,
+// e.g. window.__INITIAL_STATE__ = {...} or window.__NUXT__=(...).
+var stateAssignment = regexp.MustCompile(`(?:window|self|globalThis)\.(__[A-Za-z0-9_]+__|[A-Za-z_]*(?:STATE|State|DATA|Data))\s*=\s*`)
+
+// Clean returns the embedded data blobs, one section per script. If the page has
+// no hydration data the content is returned unchanged.
+func (c *ScriptData) Clean(html string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var b strings.Builder
+	writeBlob := func(label, data string) {
+		data = compactJSON(data)
+		if data == "" {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString("## ")
+		b.WriteString(label)
+		b.WriteString("\n")
+		b.WriteString(data)
+	}
+
+	doc.Find("script").Each(func(i int, s *goquery.Selection) {
+		scriptType := strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))
+		body := strings.TrimSpace(s.Text())
+		if body == "" {
+			return
+		}
+
+		switch scriptType {
+		case "application/json":
+			// __NEXT_DATA__, __NUXT_DATA__ and other JSON islands
+			label := s.AttrOr("id", "")
+			if label == "" {
+				label = fmt.Sprintf("script-%d", i+1)
+			}
+			writeBlob(label, body)
+
+		case "", "text/javascript", "application/javascript", "module":
+			for _, match := range stateAssignment.FindAllStringSubmatchIndex(body, -1) {
+				if value := balancedValue(body[match[1]:]); value != "" {
+					writeBlob(body[match[2]:match[3]], value)
+				}
+			}
+		}
+	})
+
+	if b.Len() == 0 {
+		return html, nil
+	}
+	return b.String(), nil
+}
+
+// balancedValue returns the object or array literal at the start of s (optionally
+// wrapped in JSON.parse("...")), tracking string literals so braces inside strings
+// are ignored. Returns "" if no complete literal is found.
+func balancedValue(s string) string {
+	s = strings.TrimSpace(s)
+
+	// JSON.parse("...") / JSON.parse('...') wraps the data in a string literal
+	if strings.HasPrefix(s, "JSON.parse(") {
+		rest := strings.TrimSpace(strings.TrimPrefix(s, "JSON.parse("))
+		if len(rest) > 0 && (rest[0] == '"' || rest[0] == '\'') {
+			if end := stringLiteralEnd(rest); end > 0 {
+				var decoded string
+				literal := rest[:end+1]
+				if rest[0] == '\'' {
+					literal = `"` + strings.ReplaceAll(rest[1:end], `"`, `\"`) + `"`
+				}
+				if json.Unmarshal([]byte(literal), &decoded) == nil {
+					return decoded
+				}
+			}
+		}
+		return ""
+	}
+
+	if len(s) == 0 || (s[0] != '{' && s[0] != '[') {
+		return ""
+	}
+
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'', '`':
+			end := stringLiteralEnd(s[i:])
+			if end < 0 {
+				return ""
+			}
+			i += end
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return s[:i+1]
+			}
+		}
+	}
+	return ""
+}
+
+// stringLiteralEnd returns the index of the closing quote of the string literal
+// starting at s[0], or -1 if it is unterminated.
+func stringLiteralEnd(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// compactJSON removes insignificant whitespace from valid JSON to save tokens.
+// Non-JSON values (e.g., JavaScript object literals) are returned trimmed.
+func compactJSON(data string) string {
+	data = strings.TrimSpace(data)
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(data)); err == nil {
+		return buf.String()
+	}
+	return data
+}
+
+// Name returns the cleaner type.
+func (c *ScriptData) Name() string {
+	return "script_data"
+}
@@ -0,0 +1,69 @@
+// Package cleaners provides content cleaners that complement the refyne library's
+// built-in cleaners. Each cleaner implements refyne's cleaner.Cleaner interface so
+// it can be used on its own or chained with other cleaners.
+package cleaners
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// Select keeps only the subtrees matching a CSS selector, discarding the rest of
+// the page. Useful for narrowing extraction to a known container (e.g., "#results").
+type Select struct {
+	selector string
+}
+
+// NewSelect creates a select cleaner for the given CSS selector.
+// Comma-separated selectors are supported; matches are kept in document order.
+func NewSelect(selector string) (*Select, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return nil, fmt.Errorf("select cleaner requires a selector")
+	}
+	if _, err := cascadia.ParseGroup(selector); err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+	return &Select{selector: selector}, nil
+}
+
+// Clean returns the outer HTML of every element matching the selector.
+// If nothing matches, the content is returned unchanged so extraction can still
+// proceed on the full page.
+func (c *Select) Clean(html string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	matches := doc.Find(c.selector)
+	if matches.Length() == 0 {
+		return html, nil
+	}
+
+	var b strings.Builder
+	matches.Each(func(_ int, s *goquery.Selection) {
+		// Skip elements nested inside an earlier match to avoid duplicate content
+		if s.ParentsFiltered(c.selector).Length() > 0 {
+			return
+		}
+		outer, err := goquery.OuterHtml(s)
+		if err != nil {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(outer)
+	})
+
+	return b.String(), nil
+}
+
+// Name returns the cleaner type.
+func (c *Select) Name() string {
+	return "select"
+}
@@ -0,0 +1,147 @@
+package cleaners
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxColspan caps colspan expansion so malformed markup cannot blow up the output.
+const maxColspan = 50
+
+// Table output formats.
+const (
+	TableFormatMarkdown = "markdown"
+	TableFormatCSV      = "csv"
+)
+
+// Table converts the HTML tables on a page into markdown or CSV. Specification
+// sheets, price lists and comparison tables extract far more reliably from a
+// compact grid than from nested table markup.
+type Table struct {
+	format string
+}
+
+// NewTable creates a table cleaner. format is "markdown" (default) or "csv".
+func NewTable(format string) (*Table, error) {
+	switch strings.ToLower(format) {
+	case "", TableFormatMarkdown:
+		return &Table{format: TableFormatMarkdown}, nil
+	case TableFormatCSV:
+		return &Table{format: TableFormatCSV}, nil
+	default:
+		return nil, fmt.Errorf("invalid table format %q (valid formats: markdown, csv)", format)
+	}
+}
+
+// Clean returns every table on the page in the configured format, separated by
+// blank lines. If the page has no tables the content is returned unchanged.
+func (c *Table) Clean(html string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	var sections []string
+	doc.Find("table").Each(func(_ int, table *goquery.Selection) {
+		// Nested tables are flattened into the text of their parent table's cells
+		if table.ParentsFiltered("table").Length() > 0 {
+			return
+		}
+
+		rows := tableRows(table)
+		if len(rows) == 0 {
+			return
+		}
+
+		title := strings.TrimSpace(table.Find("caption").First().Text())
+		if title == "" {
+			title = "Table " + strconv.Itoa(len(sections)+1)
+		}
+
+		var rendered string
+		if c.format == TableFormatCSV {
+			rendered = renderCSV(rows)
+		} else {
+			rendered = renderMarkdownTable(rows)
+		}
+		sections = append(sections, "## "+title+"\n"+rendered)
+	})
+
+	if len(sections) == 0 {
+		return html, nil
+	}
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// tableRows returns the table's cell text row by row, expanding colspans and
+// padding short rows so every row has the same number of columns.
+func tableRows(table *goquery.Selection) [][]string {
+	var rows [][]string
+	maxCols := 0
+
+	table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		// Skip rows belonging to nested tables
+		if tr.ParentsFiltered("table").First().Get(0) != table.Get(0) {
+			return
+		}
+
+		var row []string
+		tr.ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+			text := strings.Join(strings.Fields(cell.Text()), " ")
+			span, _ := strconv.Atoi(cell.AttrOr("colspan", "1"))
+			for range min(max(span, 1), maxColspan) {
+				row = append(row, text)
+			}
+		})
+		if len(row) == 0 {
+			return
+		}
+		maxCols = max(maxCols, len(row))
+		rows = append(rows, row)
+	})
+
+	for i := range rows {
+		for len(rows[i]) < maxCols {
+			rows[i] = append(rows[i], "")
+		}
+	}
+	return rows
+}
+
+// renderMarkdownTable renders rows as a markdown table. The first row is the header.
+func renderMarkdownTable(rows [][]string) string {
+	var b strings.Builder
+	for i, row := range rows {
+		escaped := make([]string, len(row))
+		for j, cell := range row {
+			escaped[j] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(escaped, " | ") + " |\n")
+
+		if i == 0 {
+			separators := make([]string, len(row))
+			for j := range separators {
+				separators[j] = "---"
+			}
+			b.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderCSV renders rows as CSV.
+func renderCSV(rows [][]string) string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	_ = w.WriteAll(rows) // Writing to a strings.Builder cannot fail
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Name returns the cleaner type.
+func (c *Table) Name() string {
+	return "table"
+}
@@ -74,6 +74,11 @@ type APIKeyRateLimits struct {
 	RequestsPerDay    int `json:"requests_per_day"`
 }
 
+// IsSet returns true if any request limit is configured.
+func (l APIKeyRateLimits) IsSet() bool {
+	return l.RequestsPerMinute > 0 || l.RequestsPerHour > 0 || l.RequestsPerDay > 0
+}
+
 // APIKeysJSON represents the JSON structure from S3.
 type APIKeysJSON struct {
 	APIKeys []APIKeyConfig `json:"api_keys"`
@@ -53,13 +53,20 @@ type Config struct {
 	AdminEnabled   bool
 
 	// Self-hosted
-	LicenseKey     string
-	DataDir        string // If set, enables persistence
-	APIKeyHash     string // Pre-hashed API key for self-hosted auth
+	LicenseKey           string
+	LicensePublicKey     string        // Base64 Ed25519 key licenses are verified against (defaults to the build-time key)
+	LicenseGracePeriod   time.Duration // How long an expired license keeps its features (default 14 days)
+	LicenseCheckInterval time.Duration // How often the license is re-checked (default 1h)
+	DataDir              string        // If set, enables persistence
+	APIKeyHash           string        // Hex SHA-256 of the self-hosted operator's rf_ API key, which has superadmin access
 
 	// Telemetry
 	TelemetryDisabled bool
-	TelemetryEndpoint string
+
+	// Observability
+	MetricsEnabled bool   // Serve Prometheus metrics at /metrics (default false)
+	MetricsToken   string // If set, /metrics requires this bearer token
+	TracingEnabled bool   // Export OTLP trace spans (enabled when OTEL_EXPORTER_OTLP_ENDPOINT is set)
 
 	// Object Storage (Tigris/S3-compatible)
 	StorageEnabled   bool
@@ -86,8 +93,16 @@ type Config struct {
 	CaptchaSecret         string // HMAC secret for signing requests to captcha service
 	CaptchaExternalAPIKey string // API key for external captcha services (2captcha, etc.) when native solving fails
 
+	// Paid CAPTCHA solver spend caps per UTC day (0 = unlimited)
+	SolverDailyBudgetUSD     float64 // Across all users
+	SolverUserDailyBudgetUSD float64 // Per user
+
 	// Idle shutdown settings (for scale-to-zero on Fly.io)
 	IdleTimeout time.Duration // Time before shutting down when idle (0 = disabled)
+
+	// LLM provider circuit breaker
+	LLMCircuitFailureThreshold int           // Consecutive provider failures before a model's circuit opens (default 3)
+	LLMCircuitCooldown         time.Duration // How long an open circuit stays at the back of the chain before probing (default 1m)
 }
 
 // Load reads configuration from environment variables.
@@ -123,7 +138,6 @@ func Load() (*Config, error) {
 		DataDir:           getEnv("REFYNE_DATA_DIR", ""),
 		APIKeyHash:        getEnv("REFYNE_API_KEY_HASH", ""),
 		TelemetryDisabled: getEnvBool("REFYNE_TELEMETRY_DISABLED", false),
-		TelemetryEndpoint: getEnv("REFYNE_TELEMETRY_ENDPOINT", "https://api.refyne.uk/api/v1/telemetry/ingest"),
 
 		// Object Storage (Tigris/S3-compatible) - uses Fly's standard env vars
 		// BUCKET_NAME is set automatically by `fly storage create`
@@ -155,10 +169,29 @@ func Load() (*Config, error) {
 	cfg.CaptchaServiceURL = getEnv("CAPTCHA_SERVICE_URL", "")
 	cfg.CaptchaSecret = getEnv("CAPTCHA_SECRET", "")
 	cfg.CaptchaExternalAPIKey = getEnv("CAPTCHA_EXTERNAL_API_KEY", "")
+	cfg.SolverDailyBudgetUSD = getEnvFloat("SOLVER_DAILY_BUDGET_USD", 0)
+	cfg.SolverUserDailyBudgetUSD = getEnvFloat("SOLVER_USER_DAILY_BUDGET_USD", 0)
 
 	// Idle shutdown configuration (for Fly.io scale-to-zero)
 	cfg.IdleTimeout = getEnvDuration("IDLE_TIMEOUT", 0) // 0 = disabled
 
+	// LLM provider circuit breaker configuration
+	cfg.LLMCircuitFailureThreshold = getEnvInt("LLM_CIRCUIT_FAILURE_THRESHOLD", 3)
+	cfg.LLMCircuitCooldown = getEnvDuration("LLM_CIRCUIT_COOLDOWN", time.Minute)
+
+	// Observability configuration. The OTLP exporter reads the standard
+	// OTEL_EXPORTER_OTLP_* variables itself; we only decide whether to enable it.
+	// Metrics are served on the public router, so they are opt-in.
+	cfg.MetricsEnabled = getEnvBool("METRICS_ENABLED", false)
+	cfg.MetricsToken = getEnv("METRICS_TOKEN", "")
+	cfg.TracingEnabled = (getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "") != "" || getEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "") != "") &&
+		!getEnvBool("OTEL_SDK_DISABLED", false)
+
+	// Self-hosted license configuration
+	cfg.LicensePublicKey = getEnv("REFYNE_LICENSE_PUBLIC_KEY", "")
+	cfg.LicenseGracePeriod = getEnvDuration("REFYNE_LICENSE_GRACE_PERIOD", 14*24*time.Hour)
+	cfg.LicenseCheckInterval = getEnvDuration("REFYNE_LICENSE_CHECK_INTERVAL", time.Hour)
+
 	// Validate required fields for hosted mode
 	if cfg.DeploymentMode == "hosted" {
 		if cfg.ClerkIssuerURL == "" {
@@ -217,6 +250,15 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		lower := strings.ToLower(value)
@@ -79,6 +79,35 @@ func TestGetEnvInt(t *testing.T) {
 	})
 }
 
+func TestGetEnvFloat(t *testing.T) {
+	t.Run("valid float", func(t *testing.T) {
+		os.Setenv("TEST_FLOAT", "2.5")
+		defer os.Unsetenv("TEST_FLOAT")
+
+		result := getEnvFloat("TEST_FLOAT", 0)
+		if result != 2.5 {
+			t.Errorf("getEnvFloat() = %v, want 2.5", result)
+		}
+	})
+
+	t.Run("invalid float", func(t *testing.T) {
+		os.Setenv("TEST_FLOAT_INVALID", "not-a-number")
+		defer os.Unsetenv("TEST_FLOAT_INVALID")
+
+		result := getEnvFloat("TEST_FLOAT_INVALID", 1.5)
+		if result != 1.5 {
+			t.Errorf("getEnvFloat() = %v, want 1.5 (default)", result)
+		}
+	})
+
+	t.Run("missing env var", func(t *testing.T) {
+		result := getEnvFloat("TEST_FLOAT_MISSING", 3)
+		if result != 3 {
+			t.Errorf("getEnvFloat() = %v, want 3 (default)", result)
+		}
+	})
+}
+
 func TestGetEnvBool(t *testing.T) {
 	tests := []struct {
 		name     string
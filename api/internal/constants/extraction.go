@@ -192,3 +192,119 @@ func GetSanitizedErrorMessage(category ErrorCategory) string {
 	}
 	return SanitizedErrorMessages[ErrorCategoryUnknown]
 }
+
+// Structured data fast path configuration.
+const (
+	// StructuredDataSkipCoverage is the default fraction of schema fields that must be
+	// filled from embedded structured data (JSON-LD, microdata, OpenGraph) before the
+	// LLM call is skipped entirely. The default requires every field to be covered.
+	StructuredDataSkipCoverage = 1.0
+
+	// StructuredDataPartialCoverage is the minimum fraction of schema fields filled from
+	// structured data before the LLM prompt is reduced to only the missing fields.
+	// Below this, the page is extracted normally with the full schema.
+	StructuredDataPartialCoverage = 0.5
+)
+
+// Preprocessor hint configuration for extraction.
+const (
+	// HintItemCountRetryRatio is the fraction of detected listing items an extraction
+	// must return. When the LLM returns fewer (e.g., 5 of 48 products), the extraction
+	// is retried once with stronger guidance to extract every item.
+	HintItemCountRetryRatio = 0.5
+
+	// HintItemCountMinExpected is the minimum number of detected items before the
+	// item-count check runs. Small listings are too noisy to judge.
+	HintItemCountMinExpected = 5
+)
+
+// Document input configuration (PDF, DOCX, XLSX and CSV).
+const (
+	// MaxDocumentSize is the largest document download accepted for extraction (25MB).
+	MaxDocumentSize = 25 * 1024 * 1024
+
+	// DocumentFetchTimeout is the timeout for downloading a document.
+	DocumentFetchTimeout = 60 * time.Second
+
+	// DocumentChunkSize is the maximum cleaned content size sent to the LLM in one call
+	// for a document. Longer documents are split on page boundaries and extracted chunk
+	// by chunk, leaving headroom under refyne's 100KB content limit for the prompt.
+	DocumentChunkSize = 80000
+
+	// MaxDocumentChunks caps the number of LLM calls made for a single document.
+	// Pages beyond the last chunk are not extracted.
+	MaxDocumentChunks = 20
+)
+
+// Direct content submission (extraction without fetching).
+const (
+	// MaxSubmittedContentSize is the largest HTML, markdown or text content accepted
+	// for direct extraction (5MB).
+	MaxSubmittedContentSize = 5 * 1024 * 1024
+
+	// MaxSubmittedContentRequestSize is the request body limit for endpoints that accept
+	// submitted content. It allows for JSON escaping of the content on top of
+	// MaxSubmittedContentSize.
+	MaxSubmittedContentRequestSize = 16 * 1024 * 1024
+)
+
+// Network capture (XHR/fetch responses recorded during browser rendering).
+const (
+	// NetworkPromptMaxSize is the largest captured-responses section added to the
+	// extraction prompt. Responses that don't fit are left out of the prompt.
+	NetworkPromptMaxSize = 20000
+
+	// NetworkPromptMaxPageSize is the page content kept alongside the captured-responses
+	// section, so both fit under refyne's 100KB content limit.
+	NetworkPromptMaxPageSize = 80000
+)
+
+// Proxy pool selection and health scoring.
+const (
+	// ProxyMaxAttempts is how many proxies a fetch tries before giving up. A fetch moves
+	// to another proxy when the page is blocked or the proxy fails.
+	ProxyMaxAttempts = 3
+
+	// ProxyBanCooldown is how long a proxy isn't used for a domain after being blocked on it.
+	ProxyBanCooldown = 30 * time.Minute
+
+	// ProxyBanWeight is how many failures a block counts as when scoring a proxy.
+	ProxyBanWeight = 3
+)
+
+// Auth profiles (stored logins for sites that require one).
+const (
+	// MaxAuthProfileCookies is the most cookies an auth profile's cookie jar may hold.
+	MaxAuthProfileCookies = 200
+
+	// MaxAuthLoginFields is the most form fields a login script may fill in.
+	MaxAuthLoginFields = 10
+
+	// AuthLoginTimeout is how long the browser has to load the login page, sign in
+	// and show the success selector.
+	AuthLoginTimeout = 90 * time.Second
+
+	// AuthLoginWaitTimeout is how long the browser waits for the success selector after submitting.
+	AuthLoginWaitTimeout = 30 * time.Second
+)
+
+// Visual extraction (full-page screenshots read by a vision-capable model).
+const (
+	// VisualSegmentHeight is the height in CSS pixels of each screenshot segment. Vision
+	// models downscale large images, so tall pages are split to keep text legible.
+	VisualSegmentHeight = 2000
+
+	// VisualMaxSegments caps the screenshot segments taken per page, from the top.
+	VisualMaxSegments = 6
+
+	// VisualJPEGQuality is the JPEG quality of screenshot segments.
+	VisualJPEGQuality = 80
+
+	// VisualMaxTokens is the output token limit of a visual extraction call when the
+	// model config doesn't set one.
+	VisualMaxTokens = 8192
+
+	// VisualTimeout is the timeout of a visual extraction call. Image inputs take longer
+	// to process than text.
+	VisualTimeout = 180 * time.Second
+)
@@ -25,6 +25,8 @@ type TierLimitsJSON struct {
 	MaxConcurrentJobs    int     `json:"max_concurrent_jobs"`
 	JobPriority          int     `json:"job_priority,omitempty"` // Scheduling priority (higher = higher priority)
 	RequestsPerMinute    int     `json:"requests_per_minute"`
+	RequestsPerHour      int     `json:"requests_per_hour,omitempty"`
+	RequestsPerDay       int     `json:"requests_per_day,omitempty"`
 	CreditAllocationUSD  float64 `json:"credit_allocation_usd,omitempty"`
 	CreditRolloverMonths int     `json:"credit_rollover_months,omitempty"`
 	MarkupPercentage     float64 `json:"markup_percentage,omitempty"`
@@ -126,6 +128,8 @@ func (t *TierSettingsLoader) refresh(ctx context.Context) {
 			MaxConcurrentJobs:    limits.MaxConcurrentJobs,
 			JobPriority:          jobPriority,
 			RequestsPerMinute:    limits.RequestsPerMinute,
+			RequestsPerHour:      limits.RequestsPerHour,
+			RequestsPerDay:       limits.RequestsPerDay,
 			CreditAllocationUSD:  limits.CreditAllocationUSD,
 			CreditRolloverMonths: limits.CreditRolloverMonths,
 			MarkupPercentage:     limits.MarkupPercentage,
@@ -28,6 +28,7 @@ const (
 	FeatureModelsPremium  = "models_premium"  // Access to premium/charged models with budget-based fallback
 	FeatureContentDynamic = "content_dynamic" // JavaScript/real browser support for dynamic content
 	FeatureSkipCreditCheck = "skip_credit_check" // Skip pre-flight credit balance check (user limited by quota instead)
+	FeatureProxyPool       = "proxy_pool"        // Route fetches through the managed proxy pool
 )
 
 // TierLimits defines the numeric limits for a subscription tier.
@@ -53,6 +54,10 @@ type TierLimits struct {
 	JobPriority int
 	// RequestsPerMinute is the rate limit for API requests (0 = unlimited)
 	RequestsPerMinute int
+	// RequestsPerHour is the hourly rate limit for API requests (0 = unlimited)
+	RequestsPerHour int
+	// RequestsPerDay is the daily rate limit for API requests (0 = unlimited)
+	RequestsPerDay int
 	// CreditAllocationUSD is the monthly USD credit for premium model calls (0 = none)
 	CreditAllocationUSD float64
 	// CreditRolloverMonths controls credit expiry:
@@ -275,6 +280,8 @@ func FeatureNotAvailableMessage(feature string) string {
 		return "Ollama (local LLM provider) is not available on your current plan. This feature is available for self-hosted deployments."
 	case "models_custom":
 		return "Custom model selection is not available on your current plan. Upgrade to configure your own model fallback chain."
+	case "proxy_pool":
+		return "The managed proxy pool is not available on your current plan. Upgrade to route fetches through managed proxies."
 	case "antibot":
 		return "Anti-bot features are not available on your current plan. Upgrade for advanced anti-bot capabilities."
 	default:
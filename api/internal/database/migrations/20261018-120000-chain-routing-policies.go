@@ -0,0 +1,25 @@
+package migrations
+
+func init() {
+	Register(Migration{
+		Timestamp:   "20261018-120000",
+		Description: "Add fallback chain routing policies",
+		Up: []string{
+			// Per-entry traffic share for the weighted routing policy (0 = never picked first)
+			`ALTER TABLE fallback_chain ADD COLUMN weight INTEGER NOT NULL DEFAULT 0`,
+			`ALTER TABLE user_fallback_chain ADD COLUMN weight INTEGER NOT NULL DEFAULT 0`,
+
+			// Routing policy per chain scope ("tier:default", "tier:<name>", "user:<id>").
+			// Chains without a row use the ordered policy.
+			`CREATE TABLE IF NOT EXISTS chain_routing_policies (
+				scope TEXT PRIMARY KEY,
+				policy TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			)`,
+
+			// Record the routing decision with each usage insight
+			`ALTER TABLE usage_insights ADD COLUMN routing_policy TEXT`,
+			`ALTER TABLE usage_insights ADD COLUMN routing_reason TEXT`,
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package migrations
+
+func init() {
+	Register(Migration{
+		Timestamp:   "20261018-130000",
+		Description: "Add spend caps with per-period spend counters and alerts",
+		Up: []string{
+			// Caps per user (api_key_id = '') or per API key, one per period
+			`CREATE TABLE IF NOT EXISTS spend_caps (
+				id TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL,
+				api_key_id TEXT NOT NULL DEFAULT '',
+				period TEXT NOT NULL,
+				max_cost_usd REAL,
+				max_tokens INTEGER,
+				max_pages INTEGER,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL,
+				UNIQUE(user_id, api_key_id, period)
+			)`,
+
+			// Spend per scope ("user:<id>" or "key:<id>") and period, including BYOK LLM cost
+			`CREATE TABLE IF NOT EXISTS spend_counters (
+				user_id TEXT NOT NULL,
+				scope TEXT NOT NULL,
+				period TEXT NOT NULL,
+				period_start TEXT NOT NULL,
+				cost_usd REAL NOT NULL DEFAULT 0,
+				tokens INTEGER NOT NULL DEFAULT 0,
+				pages INTEGER NOT NULL DEFAULT 0,
+				updated_at TEXT NOT NULL,
+				PRIMARY KEY (scope, period, period_start)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_spend_counters_user ON spend_counters(user_id)`,
+
+			// Thresholds already notified per cap and period, so each alert fires once
+			`CREATE TABLE IF NOT EXISTS spend_cap_alerts (
+				cap_id TEXT NOT NULL,
+				period_start TEXT NOT NULL,
+				threshold INTEGER NOT NULL,
+				created_at TEXT NOT NULL,
+				PRIMARY KEY (cap_id, period_start, threshold)
+			)`,
+		},
+	})
+}
@@ -0,0 +1,19 @@
+package migrations
+
+func init() {
+	Register(Migration{
+		Timestamp:   "20261018-140000",
+		Description: "Add request_rate_counters table for sliding window request limits",
+		Up: []string{
+			// Request counts per key hash and window (minute, hour, day), bucketed by window start
+			`CREATE TABLE IF NOT EXISTS request_rate_counters (
+				key_hash TEXT NOT NULL,
+				window_seconds INTEGER NOT NULL,
+				bucket_start INTEGER NOT NULL,
+				count INTEGER NOT NULL DEFAULT 0,
+				PRIMARY KEY (key_hash, window_seconds, bucket_start)
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_request_rate_counters_bucket ON request_rate_counters(bucket_start)`,
+		},
+	})
+}
@@ -0,0 +1,11 @@
+package migrations
+
+func init() {
+	Register(Migration{
+		Timestamp:   "20261018-150000",
+		Description: "Add trace_parent to jobs for continuing request traces in the worker",
+		Up: []string{
+			`ALTER TABLE jobs ADD COLUMN trace_parent TEXT`,
+		},
+	})
+}
@@ -0,0 +1,35 @@
+package migrations
+
+func init() {
+	Register(Migration{
+		Timestamp:   "20261018-160000",
+		Description: "Add managed and user proxies with per-domain health scoring",
+		Up: []string{
+			// Proxies in the managed pool (user_id = '') or owned by a user (BYOK)
+			`CREATE TABLE IF NOT EXISTS proxies (
+				id TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL DEFAULT '',
+				name TEXT NOT NULL,
+				endpoint TEXT NOT NULL,
+				url_encrypted TEXT NOT NULL,
+				country TEXT NOT NULL DEFAULT '',
+				is_enabled INTEGER NOT NULL DEFAULT 1,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_proxies_user ON proxies(user_id)`,
+
+			// Fetch outcomes per proxy and domain ('' for all domains)
+			`CREATE TABLE IF NOT EXISTS proxy_health (
+				proxy_id TEXT NOT NULL,
+				domain TEXT NOT NULL,
+				successes INTEGER NOT NULL DEFAULT 0,
+				failures INTEGER NOT NULL DEFAULT 0,
+				bans INTEGER NOT NULL DEFAULT 0,
+				last_banned_at TEXT,
+				last_used_at TEXT NOT NULL,
+				PRIMARY KEY (proxy_id, domain)
+			)`,
+		},
+	})
+}
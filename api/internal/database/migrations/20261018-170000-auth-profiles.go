@@ -0,0 +1,23 @@
+package migrations
+
+func init() {
+	Register(Migration{
+		Timestamp:   "20261018-170000",
+		Description: "Add auth profiles for fetching pages that require a login",
+		Up: []string{
+			`CREATE TABLE IF NOT EXISTS auth_profiles (
+				id TEXT PRIMARY KEY,
+				user_id TEXT NOT NULL,
+				name TEXT NOT NULL,
+				type TEXT NOT NULL,
+				login_url TEXT NOT NULL DEFAULT '',
+				cookies_encrypted TEXT NOT NULL DEFAULT '',
+				script_encrypted TEXT NOT NULL DEFAULT '',
+				cookies_updated_at TEXT,
+				created_at TEXT NOT NULL,
+				updated_at TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_auth_profiles_user ON auth_profiles(user_id)`,
+		},
+	})
+}
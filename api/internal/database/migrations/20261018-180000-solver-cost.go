@@ -0,0 +1,11 @@
+package migrations
+
+func init() {
+	Register(Migration{
+		Timestamp:   "20261018-180000",
+		Description: "Add solver_cost_usd to usage_insights for CAPTCHA solver charges",
+		Up: []string{
+			`ALTER TABLE usage_insights ADD COLUMN solver_cost_usd REAL NOT NULL DEFAULT 0`,
+		},
+	})
+}
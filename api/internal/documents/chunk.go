@@ -0,0 +1,84 @@
+package documents
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pageHeading matches the "Page N" headings written by Document.HTML as they
+// appear after cleaning: a markdown heading, an HTML h2, or a bare text line.
+var pageHeading = regexp.MustCompile(`(?m)^[ \t]*(?:#{1,6}[ \t]+|<h2[^>]*>)?Page \d+(?:: [^\n<]*)?(?:</h2>)?[ \t]*$`)
+
+// Chunk splits cleaned document content into chunks of at most maxBytes,
+// breaking on page boundaries so no page is split unless it is larger than
+// maxBytes on its own (then it is split on paragraph boundaries). Content
+// before the first page (title, frontmatter) stays with the first chunk.
+func Chunk(content string, maxBytes int) []string {
+	if maxBytes <= 0 || len(content) <= maxBytes {
+		return []string{content}
+	}
+
+	var segments []string
+	last := 0
+	for _, loc := range pageHeading.FindAllStringIndex(content, -1) {
+		if loc[0] > last {
+			segments = append(segments, content[last:loc[0]])
+		}
+		last = loc[0]
+	}
+	segments = append(segments, content[last:])
+
+	var (
+		chunks  []string
+		current strings.Builder
+	)
+	flush := func() {
+		if strings.TrimSpace(current.String()) != "" {
+			chunks = append(chunks, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+	}
+
+	for _, segment := range segments {
+		for _, part := range splitOversized(segment, maxBytes) {
+			if current.Len()+len(part) > maxBytes {
+				flush()
+			}
+			current.WriteString(part)
+		}
+	}
+	flush()
+	return chunks
+}
+
+// splitOversized splits text larger than maxBytes on paragraph boundaries, then
+// on line boundaries, and finally at maxBytes for text with no line breaks.
+func splitOversized(text string, maxBytes int) []string {
+	if len(text) <= maxBytes {
+		return []string{text}
+	}
+
+	for _, sep := range []string{"\n\n", "\n"} {
+		// Trailing separators don't split anything (and would recurse forever)
+		if !strings.Contains(strings.TrimRight(text, "\n"), sep) {
+			continue
+		}
+		var parts []string
+		for _, piece := range strings.SplitAfter(text, sep) {
+			parts = append(parts, splitOversized(piece, maxBytes)...)
+		}
+		return parts
+	}
+
+	var parts []string
+	for len(text) > maxBytes {
+		cut := maxBytes
+		// Don't split a multi-byte UTF-8 character
+		for cut > 0 && text[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		parts = append(parts, text[:cut])
+		text = text[cut:]
+	}
+	return append(parts, text)
+}
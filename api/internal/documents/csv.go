@@ -0,0 +1,78 @@
+package documents
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// tableRowsPerPage is the number of data rows per page for CSV files and
+// spreadsheet sheets, so large tables can be chunked on page boundaries.
+const tableRowsPerPage = 200
+
+// parseCSV parses CSV data into a single table, paginated by rows.
+// The delimiter (comma, semicolon or tab) is detected from the first line.
+func parseCSV(data []byte) (*Document, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM
+	if !utf8.Valid(data) {
+		return nil, errors.New("CSV is not valid UTF-8")
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = detectDelimiter(data)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.TrimLeadingSpace = true
+
+	var rows [][]string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isBlankRow(record) {
+			continue
+		}
+		rows = append(rows, record)
+	}
+	if len(rows) == 0 {
+		return &Document{}, nil
+	}
+
+	doc := &Document{}
+	for i, pageRows := range paginateRows(padRows(rows), tableRowsPerPage) {
+		doc.Pages = append(doc.Pages, Page{
+			Number: i + 1,
+			Blocks: []Block{{Rows: pageRows}},
+		})
+	}
+	return doc, nil
+}
+
+// detectDelimiter picks the most frequent candidate delimiter on the first line.
+func detectDelimiter(data []byte) rune {
+	firstLine, _, _ := strings.Cut(string(data[:min(len(data), 4096)]), "\n")
+	best, bestCount := ',', 0
+	for _, delim := range []rune{',', ';', '\t', '|'} {
+		if n := strings.Count(firstLine, string(delim)); n > bestCount {
+			best, bestCount = delim, n
+		}
+	}
+	return best
+}
+
+// isBlankRow reports whether every cell in a row is empty.
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
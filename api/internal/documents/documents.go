@@ -0,0 +1,334 @@
+// Package documents converts non-HTML documents (PDF, DOCX, XLSX and CSV) into
+// page-sectioned HTML so they can flow through the same cleaner chain and LLM
+// extraction as fetched web pages. Parsing is pure Go.
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Format identifies a supported document format.
+type Format string
+
+// Supported document formats.
+const (
+	FormatPDF  Format = "pdf"
+	FormatDOCX Format = "docx"
+	FormatXLSX Format = "xlsx"
+	FormatCSV  Format = "csv"
+)
+
+// maxPages caps the number of pages parsed from a single document so a
+// pathological file cannot exhaust memory.
+const maxPages = 1000
+
+var (
+	// ErrUnsupportedFormat is returned when content is not a supported document.
+	ErrUnsupportedFormat = errors.New("unsupported document format")
+
+	// ErrNoText is returned when a document has no extractable text (e.g., a scanned PDF).
+	ErrNoText = errors.New("document contains no extractable text")
+)
+
+// contentTypes maps MIME types to document formats.
+var contentTypes = map[string]Format{
+	"application/pdf":   FormatPDF,
+	"application/x-pdf": FormatPDF,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": FormatDOCX,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":       FormatXLSX,
+	"text/csv":                    FormatCSV,
+	"application/csv":             FormatCSV,
+	"text/comma-separated-values": FormatCSV,
+}
+
+// extensions maps file extensions to document formats.
+var extensions = map[string]Format{
+	".pdf":  FormatPDF,
+	".docx": FormatDOCX,
+	".xlsx": FormatXLSX,
+	".csv":  FormatCSV,
+}
+
+// MIMEType returns the canonical MIME type for a format.
+func (f Format) MIMEType() string {
+	switch f {
+	case FormatPDF:
+		return "application/pdf"
+	case FormatDOCX:
+		return "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatCSV:
+		return "text/csv"
+	}
+	return ""
+}
+
+// FormatFromContentType returns the document format for a Content-Type header value,
+// or "" if it is not a supported document type.
+func FormatFromContentType(contentType string) Format {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return contentTypes[mediaType]
+}
+
+// FormatFromURL returns the document format implied by a URL's file extension,
+// or "" if the URL does not point to a supported document.
+func FormatFromURL(rawURL string) Format {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return extensions[strings.ToLower(path.Ext(u.Path))]
+}
+
+// Detect determines whether fetched content is a supported document. The response
+// body is sniffed first because servers often send documents as
+// application/octet-stream; the Content-Type header and URL extension are used for
+// formats without a signature (CSV). Returns "" for HTML and other content.
+func Detect(contentType, rawURL string, body []byte) Format {
+	switch {
+	case bytes.HasPrefix(body, []byte("%PDF-")):
+		return FormatPDF
+	case bytes.HasPrefix(body, []byte("PK\x03\x04")):
+		return detectOOXML(body)
+	}
+
+	// Never treat markup as a document, whatever the headers claim
+	if looksLikeHTML(body) {
+		return ""
+	}
+
+	format := FormatFromContentType(contentType)
+	if format == "" {
+		format = FormatFromURL(rawURL)
+	}
+	if format == FormatCSV {
+		return FormatCSV
+	}
+	return ""
+}
+
+// detectOOXML distinguishes Word and Excel documents by their zip entries.
+func detectOOXML(body []byte) Format {
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return ""
+	}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return FormatDOCX
+		case "xl/workbook.xml":
+			return FormatXLSX
+		}
+	}
+	return ""
+}
+
+// looksLikeHTML reports whether the start of body is an HTML document.
+func looksLikeHTML(body []byte) bool {
+	head := bytes.ToLower(bytes.TrimSpace(body[:min(len(body), 512)]))
+	return bytes.HasPrefix(head, []byte("<!doctype html")) || bytes.HasPrefix(head, []byte("<html"))
+}
+
+// Document is a parsed document made up of pages.
+type Document struct {
+	Format Format
+	Title  string
+	Pages  []Page
+}
+
+// Page is a single page of a document. Spreadsheet sheets and large CSV files are
+// split into pages of rows so long tables can be chunked like long PDFs.
+type Page struct {
+	Number int
+	Label  string // Optional page label (e.g., sheet name)
+	Blocks []Block
+}
+
+// Block is a paragraph, heading or table within a page.
+type Block struct {
+	Heading int        // Heading level 1-6, or 0 for body text
+	Text    string     // Paragraph or heading text
+	Rows    [][]string // Table rows when the block is a table (first row is the header)
+}
+
+// Parse parses document bytes in the given format.
+func Parse(format Format, data []byte) (*Document, error) {
+	var (
+		doc *Document
+		err error
+	)
+	switch format {
+	case FormatPDF:
+		doc, err = parsePDF(data)
+	case FormatDOCX:
+		doc, err = parseDOCX(data)
+	case FormatXLSX:
+		doc, err = parseXLSX(data)
+	case FormatCSV:
+		doc, err = parseCSV(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+	}
+
+	doc.Format = format
+	if !doc.hasText() {
+		return nil, ErrNoText
+	}
+	return doc, nil
+}
+
+// hasText reports whether any page contains text.
+func (d *Document) hasText() bool {
+	for _, p := range d.Pages {
+		for _, b := range p.Blocks {
+			if strings.TrimSpace(b.Text) != "" {
+				return true
+			}
+			for _, row := range b.Rows {
+				for _, cell := range row {
+					if strings.TrimSpace(cell) != "" {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// PageHeading returns the heading that introduces a page in the rendered HTML.
+// Chunk uses these headings to split cleaned content on page boundaries.
+func (p Page) PageHeading() string {
+	heading := "Page " + strconv.Itoa(p.Number)
+	if p.Label != "" {
+		heading += ": " + p.Label
+	}
+	return heading
+}
+
+// HTML renders the document as simple semantic HTML: one section per page, each
+// introduced by a "Page N" heading, with paragraphs, headings and tables.
+func (d *Document) HTML() string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	if d.Title != "" {
+		b.WriteString("<title>" + html.EscapeString(d.Title) + "</title>\n")
+	}
+	b.WriteString("</head>\n<body>\n")
+	if d.Title != "" {
+		b.WriteString("<h1>" + html.EscapeString(d.Title) + "</h1>\n")
+	}
+
+	for _, p := range d.Pages {
+		fmt.Fprintf(&b, "<section data-page=\"%d\">\n", p.Number)
+		b.WriteString("<h2>" + html.EscapeString(p.PageHeading()) + "</h2>\n")
+		for _, block := range p.Blocks {
+			switch {
+			case block.Rows != nil:
+				writeHTMLTable(&b, block.Rows)
+			case block.Heading > 0:
+				// Page headings are h2, so document headings start at h3
+				level := min(block.Heading+2, 6)
+				fmt.Fprintf(&b, "<h%d>%s</h%d>\n", level, html.EscapeString(block.Text), level)
+			default:
+				b.WriteString("<p>" + strings.ReplaceAll(html.EscapeString(block.Text), "\n", "<br>") + "</p>\n")
+			}
+		}
+		b.WriteString("</section>\n")
+	}
+
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// writeHTMLTable renders rows as an HTML table with the first row as the header.
+func writeHTMLTable(b *strings.Builder, rows [][]string) {
+	b.WriteString("<table>\n")
+	for i, row := range rows {
+		cellTag := "td"
+		if i == 0 {
+			cellTag = "th"
+			b.WriteString("<thead>\n")
+		}
+		b.WriteString("<tr>")
+		for _, cell := range row {
+			b.WriteString("<" + cellTag + ">" + html.EscapeString(cell) + "</" + cellTag + ">")
+		}
+		b.WriteString("</tr>\n")
+		if i == 0 {
+			b.WriteString("</thead>\n<tbody>\n")
+		}
+	}
+	if len(rows) > 0 {
+		b.WriteString("</tbody>\n")
+	}
+	b.WriteString("</table>\n")
+}
+
+// Text renders the document as plain text, used when a cleaner fails.
+func (d *Document) Text() string {
+	var b strings.Builder
+	for _, p := range d.Pages {
+		b.WriteString(p.PageHeading() + "\n\n")
+		for _, block := range p.Blocks {
+			if block.Rows != nil {
+				for _, row := range block.Rows {
+					b.WriteString(strings.Join(row, "\t") + "\n")
+				}
+			} else {
+				b.WriteString(block.Text + "\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// paginateRows splits table rows into pages of at most rowsPerPage data rows,
+// repeating the header row on every page.
+func paginateRows(rows [][]string, rowsPerPage int) [][][]string {
+	if len(rows) <= rowsPerPage+1 {
+		return [][][]string{rows}
+	}
+	header, data := rows[0], rows[1:]
+	var pages [][][]string
+	for start := 0; start < len(data); start += rowsPerPage {
+		end := min(start+rowsPerPage, len(data))
+		page := make([][]string, 0, end-start+1)
+		page = append(page, header)
+		page = append(page, data[start:end]...)
+		pages = append(pages, page)
+	}
+	return pages
+}
+
+// padRows pads every row to the same number of columns.
+func padRows(rows [][]string) [][]string {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row))
+	}
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], "")
+		}
+	}
+	return rows
+}
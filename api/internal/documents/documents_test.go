@@ -0,0 +1,459 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ========================================
+// Fixtures
+// ========================================
+
+// buildPDF assembles a minimal PDF with one content stream per page using the
+// standard Helvetica font (which has no width table, like many generated PDFs).
+func buildPDF(title string, pages ...string) []byte {
+	numPages := len(pages)
+	fontObj := 3 + 2*numPages
+	infoObj := fontObj + 1
+
+	var objects []string
+	kids := make([]string, numPages)
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 3+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), numPages),
+	)
+	for i, content := range pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>", fontObj, 4+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		)
+	}
+	objects = append(objects,
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Title (%s) >>", title),
+	)
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, infoObj, xref)
+	return b.Bytes()
+}
+
+// pdfText returns a content stream that draws text at a position.
+func pdfText(size, x, y int, text string) string {
+	return fmt.Sprintf("BT /F1 %d Tf %d %d Td (%s) Tj ET\n", size, x, y, text)
+}
+
+// buildZip creates a zip archive from name -> content pairs.
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+const coreXML = `<?xml version="1.0" encoding="UTF-8"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Widget Spec Sheet</dc:title></cp:coreProperties>`
+
+func wordCell(text string) string {
+	return `<w:tc><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:tc>`
+}
+
+func buildDOCX(t *testing.T) []byte {
+	t.Helper()
+	document := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Specifications</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Rated for </w:t></w:r><w:r><w:t>outdoor use.</w:t></w:r></w:p>
+<w:tbl>
+<w:tr>` + wordCell("Property") + wordCell("Value") + `</w:tr>
+<w:tr>` + wordCell("Weight") + wordCell("1.2 kg") + `</w:tr>
+<w:tr><w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr><w:p><w:r><w:t>Ships worldwide</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+<w:p><w:r><w:br w:type="page"/><w:t>Warranty</w:t></w:r></w:p>
+<w:p><w:r><w:delText>Removed clause.</w:delText></w:r><w:r><w:t>Two years.</w:t></w:r></w:p>
+</w:body></w:document>`
+
+	return buildZip(t, map[string]string{
+		"word/document.xml": document,
+		"docProps/core.xml": coreXML,
+	})
+}
+
+func buildXLSX(t *testing.T) []byte {
+	t.Helper()
+	return buildZip(t, map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Products" sheetId="1" r:id="rId1"/><sheet name="Internal" sheetId="2" state="hidden" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/products.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/internal.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Product</t></si><si><t>Price</t></si><si><t>Released</t></si>
+<si><r><t>Wid</t></r><r><t>get</t></r><rPh><t>ignored</t></rPh></si></sst>`,
+		"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts><numFmt numFmtId="164" formatCode="&quot;Due &quot;#,##0"/></numFmts>
+<cellStyleXfs><xf numFmtId="14"/></cellStyleXfs>
+<cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/></cellXfs></styleSheet>`,
+		"xl/worksheets/products.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>
+<row r="2"><c r="A2" t="s"><v>3</v></c><c r="B2"><f>0.1+0.2</f><v>0.30000000000000004</v></c><c r="C2" s="1"><v>45000</v></c></row>
+<row r="3"/>
+<row r="4"><c r="A4" t="inlineStr"><is><t>Gadget</t></is></c><c r="C4" t="b"><v>1</v></c><c r="B4" s="2"><v>12</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/internal.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Secret</t></is></c></row></sheetData></worksheet>`,
+		"docProps/core.xml": coreXML,
+	})
+}
+
+// ========================================
+// Detection Tests
+// ========================================
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	pdfBytes := buildPDF("T", pdfText(12, 72, 700, "Hello"))
+	docxBytes := buildDOCX(t)
+	xlsxBytes := buildXLSX(t)
+
+	tests := []struct {
+		name        string
+		contentType string
+		url         string
+		body        []byte
+		want        Format
+	}{
+		{"pdf by signature", "application/octet-stream", "https://example.com/download?id=1", pdfBytes, FormatPDF},
+		{"docx by zip entries", "application/octet-stream", "https://example.com/spec", docxBytes, FormatDOCX},
+		{"xlsx by zip entries", "", "https://example.com/prices", xlsxBytes, FormatXLSX},
+		{"csv by content type", "text/csv; charset=utf-8", "https://example.com/export", []byte("a,b\n1,2\n"), FormatCSV},
+		{"csv by extension", "application/octet-stream", "https://example.com/prices.CSV?v=2", []byte("a,b\n1,2\n"), FormatCSV},
+		{"html served as csv", "text/csv", "https://example.com/prices.csv", []byte("<!DOCTYPE html><html><body>Not found</body></html>"), ""},
+		{"html page", "text/html", "https://example.com/", []byte("<html><body>Hi</body></html>"), ""},
+		{"non-office zip", "application/zip", "https://example.com/a.zip", buildZip(t, map[string]string{"readme.txt": "hi"}), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.contentType, tt.url, tt.body); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFromURL(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]Format{
+		"https://example.com/catalogue.pdf":         FormatPDF,
+		"https://example.com/files/Spec.DOCX?dl=1":  FormatDOCX,
+		"https://example.com/prices.xlsx#sheet1":    FormatXLSX,
+		"https://example.com/products":              "",
+		"https://example.com/pdf":                   "",
+		"https://example.com/products.html?f=a.pdf": "",
+	}
+	for url, want := range tests {
+		if got := FormatFromURL(url); got != want {
+			t.Errorf("FormatFromURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+// ========================================
+// Parser Tests
+// ========================================
+
+func TestParse_PDF(t *testing.T) {
+	t.Parallel()
+
+	page1 := pdfText(18, 72, 740, "Widget Catalogue") +
+		pdfText(11, 72, 700, "Our widgets are built to last.") +
+		pdfText(11, 72, 660, "SKU") + pdfText(11, 250, 660, "Price") +
+		pdfText(11, 72, 646, "W-100") + pdfText(11, 250, 646, "9.99") +
+		pdfText(11, 72, 632, "W-200") + pdfText(11, 250, 632, "14.50")
+	page2 := pdfText(11, 72, 740, "Terms and conditions apply.")
+
+	doc, err := Parse(FormatPDF, buildPDF("Widget Catalogue 2026", page1, page2))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if doc.Title != "Widget Catalogue 2026" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	if len(doc.Pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(doc.Pages))
+	}
+
+	want := []Block{
+		{Text: "Widget Catalogue"},
+		{Text: "Our widgets are built to last."},
+		{Rows: [][]string{{"SKU", "Price"}, {"W-100", "9.99"}, {"W-200", "14.50"}}},
+	}
+	if !reflect.DeepEqual(doc.Pages[0].Blocks, want) {
+		t.Errorf("page 1 blocks = %#v, want %#v", doc.Pages[0].Blocks, want)
+	}
+	if doc.Pages[1].Number != 2 || doc.Pages[1].Blocks[0].Text != "Terms and conditions apply." {
+		t.Errorf("unexpected page 2: %#v", doc.Pages[1])
+	}
+}
+
+func TestParse_PDFWithoutText(t *testing.T) {
+	t.Parallel()
+
+	// A scanned page draws images, not text
+	_, err := Parse(FormatPDF, buildPDF("Scan", "0 0 612 792 re f\n"))
+	if !errors.Is(err, ErrNoText) {
+		t.Errorf("expected ErrNoText, got %v", err)
+	}
+}
+
+func TestParse_MalformedPDF(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Parse(FormatPDF, []byte("%PDF-1.4\ngarbage")); err == nil {
+		t.Error("expected error for malformed PDF")
+	}
+}
+
+func TestParse_DOCX(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Parse(FormatDOCX, buildDOCX(t))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if doc.Title != "Widget Spec Sheet" {
+		t.Errorf("Title = %q", doc.Title)
+	}
+	want := []Page{
+		{Number: 1, Blocks: []Block{
+			{Heading: 1, Text: "Specifications"},
+			{Text: "Rated for outdoor use."},
+			{Rows: [][]string{{"Property", "Value"}, {"Weight", "1.2 kg"}, {"Ships worldwide", "Ships worldwide"}}},
+		}},
+		{Number: 2, Blocks: []Block{
+			{Text: "Warranty"},
+			{Text: "Two years."},
+		}},
+	}
+	if !reflect.DeepEqual(doc.Pages, want) {
+		t.Errorf("Pages = %#v, want %#v", doc.Pages, want)
+	}
+}
+
+func TestParse_XLSX(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Parse(FormatXLSX, buildXLSX(t))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(doc.Pages) != 1 {
+		t.Fatalf("expected hidden sheet to be skipped, got %d pages", len(doc.Pages))
+	}
+	page := doc.Pages[0]
+	if page.Label != "Products" || page.PageHeading() != "Page 1: Products" {
+		t.Errorf("unexpected page label %q", page.Label)
+	}
+
+	want := [][]string{
+		{"Product", "Price", "Released"},
+		{"Widget", "0.3", "2023-03-15"},
+		{"Gadget", "12", "TRUE"},
+	}
+	if !reflect.DeepEqual(page.Blocks[0].Rows, want) {
+		t.Errorf("Rows = %#v, want %#v", page.Blocks[0].Rows, want)
+	}
+}
+
+func TestParse_CSV(t *testing.T) {
+	t.Parallel()
+
+	data := "\xef\xbb\xbfname;price;notes\nWidget;9,99;\"Ships; fast\"\n\n;;\nGadget;12\n"
+	doc, err := Parse(FormatCSV, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := [][]string{
+		{"name", "price", "notes"},
+		{"Widget", "9,99", "Ships; fast"},
+		{"Gadget", "12", ""},
+	}
+	if len(doc.Pages) != 1 || !reflect.DeepEqual(doc.Pages[0].Blocks[0].Rows, want) {
+		t.Errorf("Pages = %#v, want rows %#v", doc.Pages, want)
+	}
+}
+
+func TestParse_CSVPaginatesLargeTables(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	b.WriteString("sku,price\n")
+	for i := range 450 {
+		fmt.Fprintf(&b, "SKU-%d,%d\n", i, i)
+	}
+
+	doc, err := Parse(FormatCSV, []byte(b.String()))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(doc.Pages) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(doc.Pages))
+	}
+	for i, page := range doc.Pages {
+		rows := page.Blocks[0].Rows
+		if !reflect.DeepEqual(rows[0], []string{"sku", "price"}) {
+			t.Errorf("page %d should repeat the header, got %v", i+1, rows[0])
+		}
+	}
+	if got := len(doc.Pages[2].Blocks[0].Rows); got != 51 {
+		t.Errorf("last page should hold the remaining 50 rows plus header, got %d", got)
+	}
+}
+
+func TestParse_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Parse("pptx", []byte("data")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+// ========================================
+// Rendering Tests
+// ========================================
+
+func TestDocumentHTML(t *testing.T) {
+	t.Parallel()
+
+	doc := &Document{
+		Title: "Specs & Prices",
+		Pages: []Page{
+			{Number: 1, Blocks: []Block{
+				{Heading: 1, Text: "Overview"},
+				{Text: "Line one\nLine <two>"},
+				{Rows: [][]string{{"SKU", "Price"}, {"W-1", "9.99"}}},
+			}},
+			{Number: 2, Label: "Sheet2", Blocks: []Block{{Text: "More"}}},
+		},
+	}
+
+	got := doc.HTML()
+	for _, want := range []string{
+		"<title>Specs &amp; Prices</title>",
+		"<h1>Specs &amp; Prices</h1>",
+		`<section data-page="1">`,
+		"<h2>Page 1</h2>",
+		"<h3>Overview</h3>",
+		"<p>Line one<br>Line &lt;two&gt;</p>",
+		"<thead>\n<tr><th>SKU</th><th>Price</th></tr>\n</thead>",
+		"<tr><td>W-1</td><td>9.99</td></tr>",
+		"<h2>Page 2: Sheet2</h2>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML() missing %q in:\n%s", want, got)
+		}
+	}
+
+	if text := doc.Text(); !strings.Contains(text, "Page 2: Sheet2\n\nMore") || !strings.Contains(text, "W-1\t9.99") {
+		t.Errorf("Text() = %q", text)
+	}
+}
+
+// ========================================
+// Chunking Tests
+// ========================================
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+
+	page := func(n int, size int) string {
+		return fmt.Sprintf("## Page %d\n\n%s\n\n", n, strings.Repeat("x", size))
+	}
+
+	t.Run("small content is a single chunk", func(t *testing.T) {
+		content := "# Title\n\n" + page(1, 10) + page(2, 10)
+		if got := Chunk(content, 1000); len(got) != 1 || got[0] != content {
+			t.Errorf("Chunk() = %q", got)
+		}
+	})
+
+	t.Run("packs whole pages into chunks", func(t *testing.T) {
+		content := "# Title\n\n" + page(1, 40) + page(2, 40) + page(3, 40)
+		got := Chunk(content, 120)
+		if len(got) != 2 {
+			t.Fatalf("expected 2 chunks, got %d: %q", len(got), got)
+		}
+		if !strings.HasPrefix(got[0], "# Title") || !strings.Contains(got[0], "## Page 2") {
+			t.Errorf("first chunk should hold the title and pages 1-2, got %q", got[0])
+		}
+		if !strings.HasPrefix(got[1], "## Page 3") {
+			t.Errorf("second chunk should start at page 3, got %q", got[1])
+		}
+	})
+
+	t.Run("recognises html and text page headings", func(t *testing.T) {
+		content := "<h2>Page 1</h2>\n<p>" + strings.Repeat("a", 50) + "</p>\nPage 2: Sheet1\n" + strings.Repeat("b", 50)
+		got := Chunk(content, 80)
+		if len(got) != 2 || !strings.HasPrefix(got[1], "Page 2: Sheet1") {
+			t.Errorf("Chunk() = %q", got)
+		}
+	})
+
+	t.Run("splits oversized pages on paragraphs", func(t *testing.T) {
+		content := "## Page 1\n\n" + strings.Repeat("x", 60) + "\n\n" + strings.Repeat("y", 60) + "\n\n" + strings.Repeat("z", 200)
+		got := Chunk(content, 100)
+		for _, chunk := range got {
+			if len(chunk) > 100 {
+				t.Errorf("chunk exceeds limit (%d bytes)", len(chunk))
+			}
+		}
+		if joined := strings.Join(got, ""); strings.Count(joined, "x")+strings.Count(joined, "y")+strings.Count(joined, "z") != 320 {
+			t.Error("chunks should not drop content")
+		}
+	})
+}
@@ -0,0 +1,283 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxZipEntrySize caps the decompressed size of a single entry in an OOXML
+// package to protect against zip bombs.
+const maxZipEntrySize = 64 * 1024 * 1024
+
+// parseDOCX extracts paragraphs, headings and tables from a Word document.
+// Page boundaries come from explicit page breaks and the rendered page breaks
+// Word records when it saves a document.
+func parseDOCX(data []byte) (*Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DOCX archive: %w", err)
+	}
+
+	body, err := readZipEntry(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	p := &docxParser{}
+	if err := p.parse(body); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Pages: p.pages}
+	if core, err := readZipEntry(zr, "docProps/core.xml"); err == nil {
+		doc.Title = firstElementText(core, "title")
+	}
+	return doc, nil
+}
+
+// docxParser walks word/document.xml and accumulates blocks into pages.
+type docxParser struct {
+	pages  []Page
+	blocks []Block
+
+	para    strings.Builder
+	heading int
+
+	// Table state. Nested tables are flattened into the outer table's cell text.
+	tableDepth int
+	rows       [][]string
+	row        []string
+	cell       strings.Builder
+	span       int
+
+	pageBreakPending bool
+}
+
+func (p *docxParser) parse(data []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid document XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := p.start(dec, t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			p.end(t)
+		}
+	}
+
+	p.flushParagraph()
+	p.newPage()
+	return nil
+}
+
+func (p *docxParser) start(dec *xml.Decoder, t xml.StartElement) error {
+	switch t.Name.Local {
+	case "p":
+		if p.tableDepth == 0 {
+			p.para.Reset()
+			p.heading = 0
+		}
+	case "pStyle":
+		if p.tableDepth == 0 {
+			p.heading = headingLevel(attr(t, "val"))
+		}
+	case "t":
+		var text string
+		if err := dec.DecodeElement(&text, &t); err != nil {
+			return fmt.Errorf("invalid text element: %w", err)
+		}
+		p.text().WriteString(text)
+	case "delText", "instrText":
+		// Deleted revisions and field codes are not document text
+		return dec.Skip()
+	case "tab":
+		p.text().WriteString(" ")
+	case "br":
+		if attr(t, "type") == "page" {
+			p.markPageBreak()
+		} else {
+			p.text().WriteString("\n")
+		}
+	case "cr":
+		p.text().WriteString("\n")
+	case "lastRenderedPageBreak":
+		p.markPageBreak()
+	case "tbl":
+		p.tableDepth++
+		if p.tableDepth == 1 {
+			p.flushParagraph()
+			p.rows = nil
+		}
+	case "tr":
+		if p.tableDepth == 1 {
+			p.row = nil
+		}
+	case "tc":
+		if p.tableDepth == 1 {
+			p.cell.Reset()
+			p.span = 1
+		}
+	case "gridSpan":
+		if p.tableDepth == 1 {
+			if n, err := strconv.Atoi(attr(t, "val")); err == nil && n > 1 {
+				p.span = min(n, 50)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *docxParser) end(t xml.EndElement) {
+	switch t.Name.Local {
+	case "p":
+		if p.tableDepth > 0 {
+			p.cell.WriteString(" ")
+			return
+		}
+		p.flushParagraph()
+		if p.pageBreakPending {
+			p.newPage()
+		}
+	case "tc":
+		if p.tableDepth == 1 {
+			text := strings.Join(strings.Fields(p.cell.String()), " ")
+			for range p.span {
+				p.row = append(p.row, text)
+			}
+		}
+	case "tr":
+		if p.tableDepth == 1 && !isBlankRow(p.row) {
+			p.rows = append(p.rows, p.row)
+		}
+	case "tbl":
+		if p.tableDepth == 1 && len(p.rows) > 0 {
+			p.blocks = append(p.blocks, Block{Rows: padRows(p.rows)})
+		}
+		p.tableDepth--
+		if p.tableDepth == 0 && p.pageBreakPending {
+			p.newPage()
+		}
+	}
+}
+
+// text returns the builder that receives text at the current position.
+func (p *docxParser) text() *strings.Builder {
+	if p.tableDepth > 0 {
+		return &p.cell
+	}
+	return &p.para
+}
+
+// markPageBreak records a page break. A break before any text in the current
+// paragraph starts the new page immediately; otherwise it takes effect once the
+// paragraph (or table) ends.
+func (p *docxParser) markPageBreak() {
+	if p.tableDepth == 0 && strings.TrimSpace(p.para.String()) == "" {
+		p.newPage()
+		return
+	}
+	p.pageBreakPending = true
+}
+
+// flushParagraph adds the current paragraph as a block.
+func (p *docxParser) flushParagraph() {
+	text := strings.TrimSpace(p.para.String())
+	p.para.Reset()
+	if text == "" {
+		return
+	}
+	p.blocks = append(p.blocks, Block{Heading: p.heading, Text: text})
+}
+
+// newPage closes the current page. Empty pages are dropped.
+func (p *docxParser) newPage() {
+	p.pageBreakPending = false
+	if len(p.blocks) == 0 || len(p.pages) >= maxPages {
+		p.blocks = nil
+		return
+	}
+	p.pages = append(p.pages, Page{Number: len(p.pages) + 1, Blocks: p.blocks})
+	p.blocks = nil
+}
+
+// headingLevel returns the heading level for a Word paragraph style ID
+// ("Title", "Heading1" ... "Heading9"), or 0 for body styles.
+func headingLevel(style string) int {
+	lower := strings.ToLower(style)
+	if lower == "title" {
+		return 1
+	}
+	if level, ok := strings.CutPrefix(lower, "heading"); ok {
+		if n, err := strconv.Atoi(level); err == nil && n > 0 {
+			return min(n, 6)
+		}
+	}
+	return 0
+}
+
+// attr returns the value of an attribute by local name.
+func attr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// readZipEntry reads a named entry from a zip archive, enforcing maxZipEntrySize.
+func readZipEntry(zr *zip.Reader, name string) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", name, err)
+		}
+		defer func() { _ = rc.Close() }()
+
+		data, err := io.ReadAll(io.LimitReader(rc, maxZipEntrySize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if len(data) > maxZipEntrySize {
+			return nil, fmt.Errorf("%s exceeds maximum size", name)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("missing %s", name)
+}
+
+// firstElementText returns the text of the first element with the given local name.
+func firstElementText(data []byte, local string) string {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Local == local {
+			var text string
+			if dec.DecodeElement(&text, &t) != nil {
+				return ""
+			}
+			return strings.TrimSpace(text)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package documents
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Layout thresholds for rebuilding lines and tables from positioned glyphs,
+// as multiples of the font size.
+const (
+	// pdfLineTolerance is the vertical distance within which glyphs share a line.
+	pdfLineTolerance = 0.5
+
+	// pdfWordGap is the horizontal gap that separates two words.
+	pdfWordGap = 0.2
+
+	// pdfColumnGap is the horizontal gap that separates two table cells.
+	pdfColumnGap = 2.0
+
+	// pdfParagraphGap is the vertical gap between lines that starts a new paragraph.
+	pdfParagraphGap = 1.8
+
+	// pdfFallbackGlyphWidth estimates glyph width when a font has no width table.
+	pdfFallbackGlyphWidth = 0.5
+)
+
+// parsePDF extracts text from each page of a PDF. Lines are rebuilt from glyph
+// positions; runs of consecutive lines split into widely spaced columns are
+// emitted as tables, everything else as paragraphs.
+func parsePDF(data []byte) (doc *Document, err error) {
+	// The PDF reader panics on some malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = nil, fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	doc = &Document{}
+	if info := reader.Trailer().Key("Info"); !info.IsNull() {
+		doc.Title = strings.TrimSpace(info.Key("Title").Text())
+	}
+
+	numPages := min(reader.NumPage(), maxPages)
+	for i := 1; i <= numPages; i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		lines := pdfLines(page.Content().Text)
+		doc.Pages = append(doc.Pages, Page{
+			Number: i,
+			Blocks: pdfBlocks(lines),
+		})
+	}
+	return doc, nil
+}
+
+// pdfLine is a line of text split into cells at wide horizontal gaps.
+type pdfLine struct {
+	y        float64
+	fontSize float64
+	cells    []string
+}
+
+// pdfLines groups glyphs into lines (top to bottom) and cells (left to right).
+func pdfLines(glyphs []pdf.Text) []pdfLine {
+	if len(glyphs) == 0 {
+		return nil
+	}
+
+	// Group glyphs into lines, preserving content-stream order within a line
+	type lineGlyphs struct {
+		y      float64
+		size   float64
+		glyphs []pdf.Text
+	}
+	var groups []*lineGlyphs
+	for _, g := range glyphs {
+		if g.S == "" {
+			continue
+		}
+		size := math.Max(g.FontSize, 1)
+		var line *lineGlyphs
+		for _, l := range groups {
+			if math.Abs(l.y-g.Y) <= pdfLineTolerance*math.Max(size, l.size) {
+				line = l
+				break
+			}
+		}
+		if line == nil {
+			line = &lineGlyphs{y: g.Y, size: size}
+			groups = append(groups, line)
+		}
+		line.size = math.Max(line.size, size)
+		line.glyphs = append(line.glyphs, g)
+	}
+
+	// PDF y coordinates increase upwards
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].y > groups[j].y })
+
+	lines := make([]pdfLine, 0, len(groups))
+	for _, l := range groups {
+		sort.SliceStable(l.glyphs, func(i, j int) bool { return l.glyphs[i].X < l.glyphs[j].X })
+
+		var (
+			cells []string
+			cell  strings.Builder
+			end   float64
+		)
+		for i, g := range l.glyphs {
+			width := g.W
+			if width <= 0 {
+				width = pdfFallbackGlyphWidth * math.Max(g.FontSize, 1)
+			}
+			// Without a width table the reader does not advance glyph positions,
+			// so every glyph in a text run shares the run's X; estimate the advance.
+			if g.W <= 0 && i > 0 && g.X <= l.glyphs[i-1].X {
+				cell.WriteString(g.S)
+				end += width
+				continue
+			}
+			if i > 0 {
+				gap := g.X - end
+				switch {
+				case gap > pdfColumnGap*l.size:
+					cells = append(cells, strings.TrimSpace(cell.String()))
+					cell.Reset()
+				case gap > pdfWordGap*l.size && !strings.HasSuffix(cell.String(), " "):
+					cell.WriteString(" ")
+				}
+			}
+			cell.WriteString(g.S)
+			end = math.Max(end, g.X+width)
+		}
+		cells = append(cells, strings.TrimSpace(cell.String()))
+
+		line := pdfLine{y: l.y, fontSize: l.size}
+		for _, c := range cells {
+			if c != "" {
+				line.cells = append(line.cells, strings.Join(strings.Fields(c), " "))
+			}
+		}
+		if len(line.cells) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// pdfBlocks turns lines into paragraphs and tables. Runs of two or more
+// consecutive multi-cell lines become a table.
+func pdfBlocks(lines []pdfLine) []Block {
+	var (
+		blocks []Block
+		para   []string
+		table  [][]string
+	)
+	flushPara := func() {
+		if len(para) > 0 {
+			blocks = append(blocks, Block{Text: strings.Join(para, "\n")})
+			para = nil
+		}
+	}
+	flushTable := func() {
+		switch {
+		case len(table) >= 2:
+			blocks = append(blocks, Block{Rows: padRows(table)})
+		case len(table) == 1:
+			// A single multi-column line is just spaced-out text
+			para = append(para, strings.Join(table[0], " "))
+		}
+		table = nil
+	}
+
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			if prev.y-line.y > pdfParagraphGap*math.Max(prev.fontSize, line.fontSize) {
+				flushTable()
+				flushPara()
+			}
+		}
+
+		if len(line.cells) > 1 {
+			if len(table) == 0 {
+				flushPara()
+			}
+			table = append(table, line.cells)
+			continue
+		}
+
+		flushTable()
+		para = append(para, line.cells[0])
+	}
+	flushTable()
+	flushPara()
+	return blocks
+}
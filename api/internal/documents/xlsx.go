@@ -0,0 +1,480 @@
+package documents
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parseXLSX extracts every visible sheet of an Excel workbook as a table.
+// Each sheet becomes one or more pages labelled with the sheet name.
+func parseXLSX(data []byte) (*Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX archive: %w", err)
+	}
+
+	workbook, err := readZipEntry(zr, "xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheets, date1904, err := parseWorkbook(workbook)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := map[string]string{}
+	if rels, err := readZipEntry(zr, "xl/_rels/workbook.xml.rels"); err == nil {
+		targets = parseRelationships(rels)
+	}
+
+	var shared []string
+	if sst, err := readZipEntry(zr, "xl/sharedStrings.xml"); err == nil {
+		if shared, err = parseSharedStrings(sst); err != nil {
+			return nil, err
+		}
+	}
+
+	styles := &xlsxStyles{}
+	if stylesXML, err := readZipEntry(zr, "xl/styles.xml"); err == nil {
+		styles = parseStyles(stylesXML)
+	}
+
+	doc := &Document{}
+	if core, err := readZipEntry(zr, "docProps/core.xml"); err == nil {
+		doc.Title = firstElementText(core, "title")
+	}
+
+	for i, sheet := range sheets {
+		if sheet.hidden {
+			continue
+		}
+		target, ok := targets[sheet.relID]
+		if !ok {
+			// Fall back to the conventional part name
+			target = "worksheets/sheet" + strconv.Itoa(i+1) + ".xml"
+		}
+		sheetXML, err := readZipEntry(zr, resolvePartName(target))
+		if err != nil {
+			return nil, err
+		}
+
+		cr := &cellReader{shared: shared, styles: styles, date1904: date1904}
+		rows, err := cr.readSheet(sheetXML)
+		if err != nil {
+			return nil, fmt.Errorf("sheet %q: %w", sheet.name, err)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		parts := paginateRows(rows, tableRowsPerPage)
+		for j, pageRows := range parts {
+			if len(doc.Pages) >= maxPages {
+				return doc, nil
+			}
+			label := sheet.name
+			if len(parts) > 1 {
+				label += " (part " + strconv.Itoa(j+1) + ")"
+			}
+			doc.Pages = append(doc.Pages, Page{
+				Number: len(doc.Pages) + 1,
+				Label:  label,
+				Blocks: []Block{{Rows: pageRows}},
+			})
+		}
+	}
+	return doc, nil
+}
+
+// xlsxSheet is a sheet entry from workbook.xml.
+type xlsxSheet struct {
+	name   string
+	relID  string
+	hidden bool
+}
+
+// parseWorkbook returns the workbook's sheets in order and whether it uses the
+// 1904 date system.
+func parseWorkbook(data []byte) ([]xlsxSheet, bool, error) {
+	var sheets []xlsxSheet
+	date1904 := false
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid workbook XML: %w", err)
+		}
+		t, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch t.Name.Local {
+		case "workbookPr":
+			v := attr(t, "date1904")
+			date1904 = v == "1" || v == "true"
+		case "sheet":
+			state := attr(t, "state")
+			sheets = append(sheets, xlsxSheet{
+				name:   attr(t, "name"),
+				relID:  attr(t, "id"),
+				hidden: state == "hidden" || state == "veryHidden",
+			})
+		}
+	}
+	return sheets, date1904, nil
+}
+
+// parseRelationships maps relationship IDs to their targets.
+func parseRelationships(data []byte) map[string]string {
+	targets := map[string]string{}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return targets
+		}
+		if t, ok := tok.(xml.StartElement); ok && t.Name.Local == "Relationship" {
+			targets[attr(t, "Id")] = attr(t, "Target")
+		}
+	}
+}
+
+// resolvePartName resolves a workbook relationship target to a zip entry name.
+// Targets are relative to xl/ unless they are absolute.
+func resolvePartName(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+// parseSharedStrings returns the shared string table. Rich text runs are
+// concatenated; phonetic hints are skipped.
+func parseSharedStrings(data []byte) ([]string, error) {
+	var (
+		strs []string
+		cur  strings.Builder
+	)
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return strs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid shared strings XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				cur.Reset()
+			case "t":
+				var text string
+				if err := dec.DecodeElement(&text, &t); err != nil {
+					return nil, fmt.Errorf("invalid shared string: %w", err)
+				}
+				cur.WriteString(text)
+			case "rPh":
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "si" {
+				strs = append(strs, cur.String())
+			}
+		}
+	}
+}
+
+// xlsxStyles records which cell formats display numbers as dates.
+type xlsxStyles struct {
+	dateStyles map[int]bool // cellXfs index -> formats as a date/time
+}
+
+// isDate reports whether the cell style index formats numbers as dates.
+func (s *xlsxStyles) isDate(style int) bool {
+	return s.dateStyles[style]
+}
+
+// parseStyles reads number formats and cell formats from styles.xml.
+func parseStyles(data []byte) *xlsxStyles {
+	customFormats := map[int]string{}
+	var xfFormats []int
+	inCellXfs := false
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "numFmt":
+				if id, err := strconv.Atoi(attr(t, "numFmtId")); err == nil {
+					customFormats[id] = attr(t, "formatCode")
+				}
+			case "cellXfs":
+				inCellXfs = true
+			case "xf":
+				if inCellXfs {
+					id, _ := strconv.Atoi(attr(t, "numFmtId"))
+					xfFormats = append(xfFormats, id)
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "cellXfs" {
+				inCellXfs = false
+			}
+		}
+	}
+
+	styles := &xlsxStyles{dateStyles: map[int]bool{}}
+	for i, fmtID := range xfFormats {
+		if isDateFormat(fmtID, customFormats[fmtID]) {
+			styles.dateStyles[i] = true
+		}
+	}
+	return styles
+}
+
+// isDateFormat reports whether a number format displays a date or time.
+// Built-in IDs 14-22 and 45-47 are date/time formats; custom formats are
+// dates when they contain date or time tokens outside quoted literals.
+func isDateFormat(id int, code string) bool {
+	if (id >= 14 && id <= 22) || (id >= 45 && id <= 47) {
+		return true
+	}
+	if code == "" {
+		return false
+	}
+
+	var b strings.Builder
+	inQuote, inBracket := false, false
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			inBracket = true
+		case c == ']':
+			inBracket = false
+		case inBracket:
+		case c == '\\':
+			i++ // Escaped literal character
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.ContainsAny(strings.ToLower(b.String()), "ydhs") ||
+		strings.Contains(strings.ToLower(b.String()), "mm")
+}
+
+// cellReader converts sheet cells to display text.
+type cellReader struct {
+	shared   []string
+	styles   *xlsxStyles
+	date1904 bool
+}
+
+// readSheet returns the sheet's rows with columns positioned by cell reference.
+// Empty rows and trailing empty columns are dropped.
+func (r *cellReader) readSheet(data []byte) ([][]string, error) {
+	var (
+		rows [][]string
+		row  []string
+
+		cellType  string
+		cellStyle int
+		cellCol   int
+		cellValue string
+		inline    strings.Builder
+		inCell    bool
+	)
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid sheet XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row = nil
+			case "c":
+				inCell = true
+				cellType = attr(t, "t")
+				cellStyle, _ = strconv.Atoi(attr(t, "s"))
+				cellCol = columnIndex(attr(t, "r"))
+				if cellCol < 0 {
+					cellCol = len(row)
+				}
+				cellValue = ""
+				inline.Reset()
+			case "v":
+				if inCell {
+					if err := dec.DecodeElement(&cellValue, &t); err != nil {
+						return nil, fmt.Errorf("invalid cell value: %w", err)
+					}
+				}
+			case "t":
+				// Inline string (<is><t>...</t></is>)
+				if inCell {
+					var text string
+					if err := dec.DecodeElement(&text, &t); err != nil {
+						return nil, fmt.Errorf("invalid inline string: %w", err)
+					}
+					inline.WriteString(text)
+				}
+			case "f", "rPh":
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "c":
+				inCell = false
+				text := r.cellText(cellType, cellStyle, cellValue, inline.String())
+				if text == "" || cellCol > 16383 {
+					continue
+				}
+				for len(row) <= cellCol {
+					row = append(row, "")
+				}
+				row[cellCol] = text
+			case "row":
+				if !isBlankRow(row) {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+
+	return trimEmptyColumns(padRows(rows)), nil
+}
+
+// cellText returns the display text of a cell.
+func (r *cellReader) cellText(cellType string, style int, value, inline string) string {
+	switch cellType {
+	case "s":
+		idx, err := strconv.Atoi(value)
+		if err != nil || idx < 0 || idx >= len(r.shared) {
+			return ""
+		}
+		return strings.TrimSpace(r.shared[idx])
+	case "inlineStr":
+		return strings.TrimSpace(inline)
+	case "b":
+		if value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "str", "e":
+		return strings.TrimSpace(value)
+	}
+
+	// Numeric cell
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return strings.TrimSpace(value)
+	}
+	if r.styles.isDate(style) {
+		return excelDate(f, r.date1904)
+	}
+	// Round away binary floating point noise (e.g., 0.30000000000000004)
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// excelDate converts an Excel serial date to ISO 8601.
+func excelDate(serial float64, date1904 bool) string {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	t := epoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+
+	switch {
+	case days == 0 && seconds > 0:
+		return t.Format("15:04:05")
+	case seconds > 0:
+		return t.Format("2006-01-02 15:04:05")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// columnIndex converts the column letters of a cell reference ("B3") to a
+// zero-based index. Returns -1 if the reference has no column letters.
+func columnIndex(ref string) int {
+	col := 0
+	n := 0
+	for _, c := range ref {
+		if c >= 'a' && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		col = col*26 + int(c-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return -1
+	}
+	return col - 1
+}
+
+// trimEmptyColumns removes trailing columns that are empty in every row.
+func trimEmptyColumns(rows [][]string) [][]string {
+	if len(rows) == 0 {
+		return rows
+	}
+	width := len(rows[0])
+	for width > 0 {
+		empty := true
+		for _, row := range rows {
+			if strings.TrimSpace(row[width-1]) != "" {
+				empty = false
+				break
+			}
+		}
+		if !empty {
+			break
+		}
+		width--
+	}
+	for i := range rows {
+		rows[i] = rows[i][:width]
+	}
+	return rows
+}
@@ -18,14 +18,16 @@ type AdminHandler struct {
 	adminSvc    *service.AdminService
 	tierSyncSvc *service.TierSyncService
 	registry    *llm.Registry
+	health      *llm.HealthTracker
 }
 
 // NewAdminHandler creates a new admin handler.
-func NewAdminHandler(adminSvc *service.AdminService, tierSyncSvc *service.TierSyncService, registry *llm.Registry) *AdminHandler {
+func NewAdminHandler(adminSvc *service.AdminService, tierSyncSvc *service.TierSyncService, registry *llm.Registry, health *llm.HealthTracker) *AdminHandler {
 	return &AdminHandler{
 		adminSvc:    adminSvc,
 		tierSyncSvc: tierSyncSvc,
 		registry:    registry,
+		health:      health,
 	}
 }
 
@@ -172,6 +174,7 @@ type FallbackChainEntryResponse struct {
 	Model       string   `json:"model"`
 	Temperature *float64 `json:"temperature,omitempty"`
 	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Weight      int      `json:"weight,omitempty"`
 	IsEnabled   bool     `json:"is_enabled"`
 	CreatedAt   string   `json:"created_at"`
 	UpdatedAt   string   `json:"updated_at"`
@@ -185,8 +188,9 @@ type GetFallbackChainInput struct {
 // GetFallbackChainOutput represents the get fallback chain response.
 type GetFallbackChainOutput struct {
 	Body struct {
-		Chain []FallbackChainEntryResponse `json:"chain"`
-		Tiers []string                     `json:"tiers"` // List of tiers with custom chains
+		Chain           []FallbackChainEntryResponse    `json:"chain"`
+		Tiers           []string                        `json:"tiers"`            // List of tiers with custom chains
+		RoutingPolicies map[string]models.RoutingPolicy `json:"routing_policies"` // Non-default routing policies keyed by tier ("default" for the default chain)
 	}
 }
 
@@ -221,6 +225,11 @@ func (h *AdminHandler) GetFallbackChain(ctx context.Context, input *GetFallbackC
 		return nil, huma.Error500InternalServerError("failed to get tiers: " + err.Error())
 	}
 
+	policies, err := h.adminSvc.GetRoutingPolicies(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get routing policies: " + err.Error())
+	}
+
 	responses := make([]FallbackChainEntryResponse, 0, len(entries))
 	for _, e := range entries {
 		responses = append(responses, FallbackChainEntryResponse{
@@ -231,6 +240,7 @@ func (h *AdminHandler) GetFallbackChain(ctx context.Context, input *GetFallbackC
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 			CreatedAt:   e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:   e.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -239,9 +249,10 @@ func (h *AdminHandler) GetFallbackChain(ctx context.Context, input *GetFallbackC
 
 	return &GetFallbackChainOutput{
 		Body: struct {
-			Chain []FallbackChainEntryResponse `json:"chain"`
-			Tiers []string                     `json:"tiers"`
-		}{Chain: responses, Tiers: tiers},
+			Chain           []FallbackChainEntryResponse    `json:"chain"`
+			Tiers           []string                        `json:"tiers"`
+			RoutingPolicies map[string]models.RoutingPolicy `json:"routing_policies"`
+		}{Chain: responses, Tiers: tiers, RoutingPolicies: policies},
 	}, nil
 }
 
@@ -251,14 +262,16 @@ type FallbackChainEntryInput struct {
 	Model       string   `json:"model" minLength:"1" doc:"Model identifier"`
 	Temperature *float64 `json:"temperature,omitempty" doc:"Temperature setting (0.0-1.0, nil for default)"`
 	MaxTokens   *int     `json:"max_tokens,omitempty" doc:"Max output tokens (nil for default)"`
+	Weight      int      `json:"weight,omitempty" minimum:"0" maximum:"100" doc:"Share of traffic routed to this entry first under the weighted policy (percentage)"`
 	IsEnabled   bool     `json:"is_enabled" doc:"Whether this entry is enabled"`
 }
 
 // SetFallbackChainInput represents the set fallback chain request.
 type SetFallbackChainInput struct {
 	Body struct {
-		Tier  *string                   `json:"tier,omitempty" doc:"Tier to set chain for (null for default chain)"`
-		Chain []FallbackChainEntryInput `json:"chain" doc:"Ordered list of provider:model pairs"`
+		Tier          *string                   `json:"tier,omitempty" doc:"Tier to set chain for (null for default chain)"`
+		Chain         []FallbackChainEntryInput `json:"chain" doc:"Ordered list of provider:model pairs"`
+		RoutingPolicy string                    `json:"routing_policy,omitempty" enum:"ordered,cheapest_capable,fastest,weighted" doc:"How the chain picks a model: ordered, cheapest_capable, fastest or weighted (omit to keep the current policy)"`
 	}
 }
 
@@ -285,8 +298,9 @@ func (h *AdminHandler) SetFallbackChain(ctx context.Context, input *SetFallbackC
 
 	// Convert to service input
 	svcInput := service.FallbackChainInput{
-		Tier:    input.Body.Tier,
-		Entries: make([]service.FallbackChainEntryInput, 0, len(input.Body.Chain)),
+		Tier:          input.Body.Tier,
+		Entries:       make([]service.FallbackChainEntryInput, 0, len(input.Body.Chain)),
+		RoutingPolicy: models.RoutingPolicy(input.Body.RoutingPolicy),
 	}
 	for _, e := range input.Body.Chain {
 		svcInput.Entries = append(svcInput.Entries, service.FallbackChainEntryInput{
@@ -294,6 +308,7 @@ func (h *AdminHandler) SetFallbackChain(ctx context.Context, input *SetFallbackC
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 		})
 	}
@@ -313,6 +328,7 @@ func (h *AdminHandler) SetFallbackChain(ctx context.Context, input *SetFallbackC
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 			CreatedAt:   e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:   e.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -592,3 +608,61 @@ func (h *AdminHandler) SyncTiers(ctx context.Context, input *struct{}) (*SyncTie
 		}{Message: "tier metadata synced from Clerk Commerce"},
 	}, nil
 }
+
+// ListProviderHealthOutput represents the provider health response.
+type ListProviderHealthOutput struct {
+	Body struct {
+		Providers []llm.HealthStatus `json:"providers" doc:"Circuit breaker state per provider/model that has handled requests"`
+	}
+}
+
+// ListProviderHealth returns the circuit breaker state of every provider/model.
+func (h *AdminHandler) ListProviderHealth(ctx context.Context, input *struct{}) (*ListProviderHealthOutput, error) {
+	claims := mw.GetUserClaims(ctx)
+	if claims == nil || !claims.GlobalSuperadmin {
+		return nil, huma.Error403Forbidden("superadmin access required")
+	}
+
+	output := &ListProviderHealthOutput{}
+	output.Body.Providers = h.health.Snapshot()
+	if output.Body.Providers == nil {
+		output.Body.Providers = []llm.HealthStatus{}
+	}
+	return output, nil
+}
+
+// ResetProviderHealthInput represents the reset provider health request.
+type ResetProviderHealthInput struct {
+	Body struct {
+		Provider string `json:"provider,omitempty" doc:"Provider to reset (empty resets all providers)"`
+		Model    string `json:"model,omitempty" doc:"Model to reset (empty resets all models of the provider)"`
+	}
+}
+
+// ResetProviderHealthOutput represents the reset provider health response.
+type ResetProviderHealthOutput struct {
+	Body struct {
+		Reset int `json:"reset" doc:"Number of provider/model circuits closed"`
+	}
+}
+
+// ResetProviderHealth manually closes circuits, e.g. after a provider outage is resolved.
+func (h *AdminHandler) ResetProviderHealth(ctx context.Context, input *ResetProviderHealthInput) (*ResetProviderHealthOutput, error) {
+	claims := mw.GetUserClaims(ctx)
+	if claims == nil || !claims.GlobalSuperadmin {
+		return nil, huma.Error403Forbidden("superadmin access required")
+	}
+
+	if input.Body.Provider == "" && input.Body.Model != "" {
+		return nil, huma.Error400BadRequest("provider is required when model is set")
+	}
+	if input.Body.Provider != "" {
+		if err := h.validateProvider(input.Body.Provider); err != nil {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+	}
+
+	output := &ResetProviderHealthOutput{}
+	output.Body.Reset = h.health.Reset(input.Body.Provider, input.Body.Model)
+	return output, nil
+}
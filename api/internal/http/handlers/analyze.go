@@ -93,6 +93,7 @@ func (h *AnalyzeHandler) Analyze(ctx context.Context, input *AnalyzeInput) (*Ana
 			URL:       input.Body.URL,
 			Depth:     depth,
 			FetchMode: input.Body.FetchMode,
+			APIKeyID:  uc.APIKeyID,
 		},
 		uc.UserID,
 		uc.Tier,
@@ -133,6 +134,7 @@ func (h *AnalyzeHandler) Analyze(ctx context.Context, input *AnalyzeInput) (*Ana
 			URL:       input.Body.URL,
 			Depth:     depth,
 			FetchMode: input.Body.FetchMode,
+			APIKeyID:  uc.APIKeyID,
 		}, uc.Tier, uc.BYOKAllowed, uc.ModelsCustomAllowed, uc.ContentDynamicAllowed, uc.SkipCreditCheckAllowed)
 		if directErr != nil {
 			return nil, NewJobError(directErr, uc.BYOKAllowed)
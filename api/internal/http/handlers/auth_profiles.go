@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/service"
+)
+
+// AuthProfileHandler handles auth profile endpoints. Auth profiles are users' stored
+// logins, referenced by ID from extractions and crawls of pages that require one.
+type AuthProfileHandler struct {
+	authProfileSvc *service.AuthProfileService
+}
+
+// NewAuthProfileHandler creates a new auth profile handler.
+func NewAuthProfileHandler(authProfileSvc *service.AuthProfileService) *AuthProfileHandler {
+	return &AuthProfileHandler{authProfileSvc: authProfileSvc}
+}
+
+// AuthProfileResponse represents an auth profile. Cookies and login scripts are never returned.
+type AuthProfileResponse struct {
+	ID               string `json:"id" doc:"Auth profile ID"`
+	Name             string `json:"name" doc:"Display name"`
+	Type             string `json:"type" doc:"cookies (uploaded cookie jar) or login (login script replayed in the browser)"`
+	LoginURL         string `json:"login_url,omitempty" doc:"Login page - fetches redirected here refresh the cookies"`
+	HasCookies       bool   `json:"has_cookies" doc:"Whether the profile holds session cookies"`
+	CookiesUpdatedAt string `json:"cookies_updated_at,omitempty" doc:"When the cookies were uploaded or last refreshed by signing in"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+// AuthCookieInput is a cookie in an uploaded cookie jar.
+type AuthCookieInput struct {
+	Name     string `json:"name" minLength:"1" doc:"Cookie name"`
+	Value    string `json:"value" doc:"Cookie value"`
+	Domain   string `json:"domain,omitempty" example:".supplier.example.com" doc:"Domain the cookie is sent to (default: the login_url host; required without a login_url)"`
+	Path     string `json:"path,omitempty" doc:"Path the cookie is sent to"`
+	Expires  int64  `json:"expires,omitempty" doc:"Expiry as a Unix timestamp in seconds"`
+	HTTPOnly bool   `json:"http_only,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// AuthLoginFieldInput is a login form field and the value typed into it.
+type AuthLoginFieldInput struct {
+	Selector string `json:"selector" minLength:"1" example:"#username" doc:"CSS selector of the input"`
+	Value    string `json:"value" minLength:"1" doc:"Value to type"`
+}
+
+// AuthLoginScriptInput is a login form to fill in and submit on the login page.
+type AuthLoginScriptInput struct {
+	Fields          []AuthLoginFieldInput `json:"fields" minItems:"1" maxItems:"10" doc:"Fields to fill in, in order"`
+	SubmitSelector  string                `json:"submit_selector" minLength:"1" example:"button[type=submit]" doc:"CSS selector of the submit button"`
+	SuccessSelector string                `json:"success_selector" minLength:"1" example:".account-menu" doc:"CSS selector of an element that only appears once signed in"`
+}
+
+// AuthProfileInputBody is the body for creating or updating an auth profile.
+type AuthProfileInputBody struct {
+	Name     string                `json:"name" minLength:"1" maxLength:"100" doc:"Display name"`
+	Type     string                `json:"type" enum:"cookies,login" doc:"cookies (uploaded cookie jar) or login (login script replayed in the browser, requires the content_dynamic feature)"`
+	LoginURL string                `json:"login_url,omitempty" format:"uri" doc:"Login page. Required for login profiles; for cookie profiles, fetches redirected here fail with auth_session_expired"`
+	Cookies  []AuthCookieInput     `json:"cookies,omitempty" maxItems:"200" doc:"Cookie jar. Required for cookie profiles on create; omit on update to keep the current cookies"`
+	Script   *AuthLoginScriptInput `json:"script,omitempty" doc:"Login script. Required for login profiles on create; omit on update to keep the current script"`
+}
+
+// ListAuthProfilesOutput represents the auth profile list response.
+type ListAuthProfilesOutput struct {
+	Body struct {
+		AuthProfiles []AuthProfileResponse `json:"auth_profiles"`
+	}
+}
+
+// CreateAuthProfileInput represents an auth profile creation request.
+type CreateAuthProfileInput struct {
+	Body AuthProfileInputBody
+}
+
+// UpdateAuthProfileInput represents an auth profile update request.
+type UpdateAuthProfileInput struct {
+	ID   string `path:"id" doc:"Auth profile ID"`
+	Body AuthProfileInputBody
+}
+
+// AuthProfileOutput represents a single auth profile response.
+type AuthProfileOutput struct {
+	Body AuthProfileResponse
+}
+
+// AuthProfileIDInput identifies an auth profile.
+type AuthProfileIDInput struct {
+	ID string `path:"id" doc:"Auth profile ID"`
+}
+
+// DeleteAuthProfileOutput represents the auth profile deletion response.
+type DeleteAuthProfileOutput struct {
+	Body struct {
+		Success bool `json:"success"`
+	}
+}
+
+// ListAuthProfiles handles listing the user's auth profiles.
+func (h *AuthProfileHandler) ListAuthProfiles(ctx context.Context, input *struct{}) (*ListAuthProfilesOutput, error) {
+	uc := ExtractUserContext(ctx)
+	if !uc.IsAuthenticated() {
+		return nil, huma.Error401Unauthorized("authentication required")
+	}
+
+	profiles, err := h.authProfileSvc.List(ctx, uc.UserID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list auth profiles")
+	}
+
+	output := &ListAuthProfilesOutput{}
+	output.Body.AuthProfiles = make([]AuthProfileResponse, 0, len(profiles))
+	for _, p := range profiles {
+		output.Body.AuthProfiles = append(output.Body.AuthProfiles, authProfileToResponse(p))
+	}
+	return output, nil
+}
+
+// CreateAuthProfile handles adding an auth profile.
+func (h *AuthProfileHandler) CreateAuthProfile(ctx context.Context, input *CreateAuthProfileInput) (*AuthProfileOutput, error) {
+	uc := ExtractUserContext(ctx)
+	if err := authProfileAllowed(uc, input.Body.Type); err != nil {
+		return nil, err
+	}
+
+	profile, err := h.authProfileSvc.Create(ctx, uc.UserID, authProfileInput(input.Body))
+	if err != nil {
+		return nil, authProfileError(err, "failed to create auth profile")
+	}
+	return &AuthProfileOutput{Body: authProfileToResponse(profile)}, nil
+}
+
+// UpdateAuthProfile handles updating an auth profile.
+func (h *AuthProfileHandler) UpdateAuthProfile(ctx context.Context, input *UpdateAuthProfileInput) (*AuthProfileOutput, error) {
+	uc := ExtractUserContext(ctx)
+	if err := authProfileAllowed(uc, input.Body.Type); err != nil {
+		return nil, err
+	}
+
+	profile, err := h.authProfileSvc.Update(ctx, uc.UserID, input.ID, authProfileInput(input.Body))
+	if err != nil {
+		return nil, authProfileError(err, "failed to update auth profile")
+	}
+	return &AuthProfileOutput{Body: authProfileToResponse(profile)}, nil
+}
+
+// DeleteAuthProfile handles deleting an auth profile.
+func (h *AuthProfileHandler) DeleteAuthProfile(ctx context.Context, input *AuthProfileIDInput) (*DeleteAuthProfileOutput, error) {
+	uc := ExtractUserContext(ctx)
+	if !uc.IsAuthenticated() {
+		return nil, huma.Error401Unauthorized("authentication required")
+	}
+
+	if err := h.authProfileSvc.Delete(ctx, uc.UserID, input.ID); err != nil {
+		return nil, authProfileError(err, "failed to delete auth profile")
+	}
+
+	output := &DeleteAuthProfileOutput{}
+	output.Body.Success = true
+	return output, nil
+}
+
+// LoginAuthProfile handles signing in with a login profile now, to check its script
+// or refresh its cookies ahead of a crawl.
+func (h *AuthProfileHandler) LoginAuthProfile(ctx context.Context, input *AuthProfileIDInput) (*AuthProfileOutput, error) {
+	uc := ExtractUserContext(ctx)
+	if err := authProfileAllowed(uc, string(models.AuthProfileLogin)); err != nil {
+		return nil, err
+	}
+
+	profile, err := h.authProfileSvc.Login(ctx, uc.UserID, uc.Tier, input.ID)
+	if err != nil {
+		return nil, authProfileError(err, "failed to sign in")
+	}
+	return &AuthProfileOutput{Body: authProfileToResponse(profile)}, nil
+}
+
+// authProfileAllowed checks that the user is authenticated and, for login profiles,
+// has the content_dynamic feature needed to sign in with the browser.
+func authProfileAllowed(uc UserContext, profileType string) error {
+	if !uc.IsAuthenticated() {
+		return huma.Error401Unauthorized("authentication required")
+	}
+	if profileType == string(models.AuthProfileLogin) && !uc.ContentDynamicAllowed {
+		return huma.Error403Forbidden(constants.FeatureNotAvailableMessage(constants.FeatureContentDynamic))
+	}
+	return nil
+}
+
+// authProfileInput converts a request body to service input.
+func authProfileInput(body AuthProfileInputBody) service.AuthProfileInput {
+	input := service.AuthProfileInput{
+		Name:     body.Name,
+		Type:     models.AuthProfileType(body.Type),
+		LoginURL: body.LoginURL,
+	}
+	if body.Cookies != nil {
+		input.Cookies = make([]captcha.Cookie, 0, len(body.Cookies))
+		for _, c := range body.Cookies {
+			input.Cookies = append(input.Cookies, captcha.Cookie{
+				Name:     c.Name,
+				Value:    c.Value,
+				Domain:   c.Domain,
+				Path:     c.Path,
+				Expires:  c.Expires,
+				HTTPOnly: c.HTTPOnly,
+				Secure:   c.Secure,
+			})
+		}
+	}
+	if body.Script != nil {
+		script := &models.AuthLoginScript{
+			SubmitSelector:  body.Script.SubmitSelector,
+			SuccessSelector: body.Script.SuccessSelector,
+		}
+		for _, f := range body.Script.Fields {
+			script.Fields = append(script.Fields, models.AuthLoginField{Selector: f.Selector, Value: f.Value})
+		}
+		input.Script = script
+	}
+	return input
+}
+
+// authProfileError maps auth profile service errors to HTTP errors.
+func authProfileError(err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidAuthProfile):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, service.ErrAuthProfileNotFound):
+		return huma.Error404NotFound("auth profile not found")
+	case errors.Is(err, service.ErrAuthSessionExpired):
+		return huma.Error422UnprocessableEntity(err.Error())
+	case errors.Is(err, service.ErrDynamicFetchNotConfigured):
+		return huma.Error503ServiceUnavailable(err.Error())
+	default:
+		return huma.Error500InternalServerError(message)
+	}
+}
+
+// authProfileToResponse converts an auth profile to its API representation.
+func authProfileToResponse(p *models.AuthProfile) AuthProfileResponse {
+	resp := AuthProfileResponse{
+		ID:         p.ID,
+		Name:       p.Name,
+		Type:       string(p.Type),
+		LoginURL:   p.LoginURL,
+		HasCookies: p.CookiesEncrypted != "",
+		CreatedAt:  p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  p.UpdatedAt.Format(time.RFC3339),
+	}
+	if p.CookiesUpdatedAt != nil {
+		resp.CookiesUpdatedAt = p.CookiesUpdatedAt.Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/service"
+)
+
+// BillingHandler handles credit balance, transaction ledger and statement endpoints.
+type BillingHandler struct {
+	balanceSvc *service.BalanceService
+}
+
+// NewBillingHandler creates a new billing handler.
+func NewBillingHandler(balanceSvc *service.BalanceService) *BillingHandler {
+	return &BillingHandler{balanceSvc: balanceSvc}
+}
+
+// GetBalanceOutput represents the credit balance response.
+type GetBalanceOutput struct {
+	Body struct {
+		BalanceUSD          float64 `json:"balance_usd" doc:"Current credit balance in USD"`
+		AvailableBalanceUSD float64 `json:"available_balance_usd" doc:"Balance usable now, excluding expired credits"`
+		LifetimeAddedUSD    float64 `json:"lifetime_added_usd" doc:"Total credits ever added"`
+		LifetimeSpentUSD    float64 `json:"lifetime_spent_usd" doc:"Total credits ever spent"`
+		MonthlySpendUSD     float64 `json:"monthly_spend_usd" doc:"Amount charged for usage this calendar month (UTC)"`
+		PeriodStart         string  `json:"period_start,omitempty" doc:"Start of the current subscription period"`
+		PeriodEnd           string  `json:"period_end,omitempty" doc:"End of the current subscription period"`
+	}
+}
+
+// GetBalance handles getting the user's credit balance.
+func (h *BillingHandler) GetBalance(ctx context.Context, input *struct{}) (*GetBalanceOutput, error) {
+	userID := getUserID(ctx)
+	if userID == "" {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
+
+	balance, err := h.balanceSvc.GetBalance(ctx, userID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get balance")
+	}
+	available, err := h.balanceSvc.GetAvailableBalance(ctx, userID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get available balance")
+	}
+	monthlySpend, err := h.balanceSvc.GetMonthlySpend(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get monthly spend")
+	}
+
+	output := &GetBalanceOutput{}
+	output.Body.AvailableBalanceUSD = available
+	output.Body.MonthlySpendUSD = monthlySpend
+	// Users who have never been credited have no balance record
+	if balance != nil {
+		output.Body.BalanceUSD = balance.BalanceUSD
+		output.Body.LifetimeAddedUSD = balance.LifetimeAdded
+		output.Body.LifetimeSpentUSD = balance.LifetimeSpent
+		if balance.PeriodStart != nil {
+			output.Body.PeriodStart = balance.PeriodStart.Format(time.RFC3339)
+		}
+		if balance.PeriodEnd != nil {
+			output.Body.PeriodEnd = balance.PeriodEnd.Format(time.RFC3339)
+		}
+	}
+	return output, nil
+}
+
+// ListTransactionsInput represents a transaction ledger request.
+type ListTransactionsInput struct {
+	Limit  int `query:"limit" default:"50" minimum:"1" maximum:"100" doc:"Transactions per page"`
+	Offset int `query:"offset" default:"0" minimum:"0" doc:"Offset for pagination"`
+}
+
+// TransactionResponse represents a credit transaction in API responses.
+type TransactionResponse struct {
+	ID              string  `json:"id"`
+	Type            string  `json:"type" doc:"Transaction type (subscription, topup, usage, refund, expiry, adjustment)"`
+	AmountUSD       float64 `json:"amount_usd" doc:"Amount in USD (positive for credits, negative for debits)"`
+	BalanceAfterUSD float64 `json:"balance_after_usd" doc:"Balance after this transaction"`
+	ExpiresAt       string  `json:"expires_at,omitempty" doc:"When these credits expire (empty if they never expire)"`
+	IsExpired       bool    `json:"is_expired"`
+	JobID           string  `json:"job_id,omitempty" doc:"Job charged, for usage transactions"`
+	Description     string  `json:"description"`
+	CreatedAt       string  `json:"created_at"`
+}
+
+// ListTransactionsOutput represents the transaction ledger response.
+type ListTransactionsOutput struct {
+	Body struct {
+		Transactions []TransactionResponse `json:"transactions" doc:"Credit transactions, newest first"`
+		Limit        int                   `json:"limit"`
+		Offset       int                   `json:"offset"`
+		HasMore      bool                  `json:"has_more" doc:"Whether more transactions follow this page"`
+	}
+}
+
+// ListTransactions handles listing the user's credit transaction ledger.
+func (h *BillingHandler) ListTransactions(ctx context.Context, input *ListTransactionsInput) (*ListTransactionsOutput, error) {
+	userID := getUserID(ctx)
+	if userID == "" {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
+
+	// Fetch one extra transaction to know whether there's another page
+	txs, err := h.balanceSvc.GetTransactionHistory(ctx, userID, input.Limit+1, input.Offset)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list transactions")
+	}
+
+	output := &ListTransactionsOutput{}
+	output.Body.Limit = input.Limit
+	output.Body.Offset = input.Offset
+	if len(txs) > input.Limit {
+		output.Body.HasMore = true
+		txs = txs[:input.Limit]
+	}
+	output.Body.Transactions = make([]TransactionResponse, 0, len(txs))
+	for _, tx := range txs {
+		resp := TransactionResponse{
+			ID:              tx.ID,
+			Type:            string(tx.Type),
+			AmountUSD:       tx.AmountUSD,
+			BalanceAfterUSD: tx.BalanceAfter,
+			IsExpired:       tx.IsExpired,
+			Description:     tx.Description,
+			CreatedAt:       tx.CreatedAt.Format(time.RFC3339),
+		}
+		if tx.ExpiresAt != nil {
+			resp.ExpiresAt = tx.ExpiresAt.Format(time.RFC3339)
+		}
+		if tx.JobID != nil {
+			resp.JobID = *tx.JobID
+		}
+		output.Body.Transactions = append(output.Body.Transactions, resp)
+	}
+	return output, nil
+}
+
+// GetStatementInput represents a billing statement request.
+type GetStatementInput struct {
+	Period string `path:"period" example:"2026-10" doc:"Calendar month (YYYY-MM, UTC) or 'current' for the current subscription period"`
+	Format string `query:"format" default:"json" enum:"json,csv" doc:"Response format"`
+}
+
+// StatementLineResponse represents one charged job on a statement.
+type StatementLineResponse struct {
+	Date              string  `json:"date" doc:"Usage date (YYYY-MM-DD)"`
+	UsageID           string  `json:"usage_id"`
+	JobID             string  `json:"job_id,omitempty"`
+	Type              string  `json:"type" doc:"Job type (extract, crawl, analyze)"`
+	Status            string  `json:"status" doc:"Outcome (success, failed, partial)"`
+	URL               string  `json:"url,omitempty"`
+	Provider          string  `json:"provider,omitempty"`
+	Model             string  `json:"model,omitempty"`
+	BYOK              bool    `json:"byok" doc:"Whether the job used the user's own LLM key (not charged)"`
+	TokensInput       int     `json:"tokens_input"`
+	TokensOutput      int     `json:"tokens_output"`
+	LLMCostUSD        float64 `json:"llm_cost_usd" doc:"LLM provider cost"`
+	MarkupRate        float64 `json:"markup_rate" doc:"Markup rate applied to the LLM cost"`
+	MarkupUSD         float64 `json:"markup_usd"`
+	PerTransactionUSD float64 `json:"per_transaction_usd" doc:"Flat per-transaction fee"`
+	SolverCostUSD     float64 `json:"solver_cost_usd" doc:"CAPTCHA solver cost, charged at cost (also for BYOK jobs)"`
+	ChargedUSD        float64 `json:"charged_usd" doc:"Credits deducted (LLM cost + markup + per-transaction fee + solver cost)"`
+	CreatedAt         string  `json:"created_at"`
+}
+
+// StatementTotalsResponse represents the totals of a statement.
+type StatementTotalsResponse struct {
+	Jobs              int     `json:"jobs"`
+	BYOKJobs          int     `json:"byok_jobs"`
+	TokensInput       int     `json:"tokens_input"`
+	TokensOutput      int     `json:"tokens_output"`
+	LLMCostUSD        float64 `json:"llm_cost_usd" doc:"LLM cost of charged jobs"`
+	MarkupUSD         float64 `json:"markup_usd"`
+	PerTransactionUSD float64 `json:"per_transaction_usd"`
+	SolverCostUSD     float64 `json:"solver_cost_usd" doc:"CAPTCHA solver cost of all jobs"`
+	ChargedUSD        float64 `json:"charged_usd" doc:"Total credits deducted"`
+	BYOKLLMCostUSD    float64 `json:"byok_llm_cost_usd" doc:"LLM cost of BYOK jobs, billed by the provider"`
+}
+
+// StatementResponse represents a billing statement.
+type StatementResponse struct {
+	Period      string                  `json:"period"`
+	PeriodStart string                  `json:"period_start" doc:"Start of the statement period (inclusive)"`
+	PeriodEnd   string                  `json:"period_end" doc:"End of the statement period (exclusive)"`
+	Lines       []StatementLineResponse `json:"lines" doc:"Every job in the period, oldest first"`
+	Totals      StatementTotalsResponse `json:"totals"`
+}
+
+// GetStatementOutput represents the statement response.
+// Body is a StatementResponse for JSON, or the CSV document bytes.
+type GetStatementOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               any
+}
+
+// GetStatement handles getting an itemized billing statement as JSON or CSV.
+func (h *BillingHandler) GetStatement(ctx context.Context, input *GetStatementInput) (*GetStatementOutput, error) {
+	userID := getUserID(ctx)
+	if userID == "" {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
+
+	statement, err := h.balanceSvc.GetStatement(ctx, userID, input.Period)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidStatementPeriod) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError("failed to get statement")
+	}
+
+	resp := statementToResponse(statement)
+	filename := "refyne-statement-" + statement.PeriodStart.Format("2006-01-02")
+
+	if input.Format == "csv" {
+		data, err := statementCSV(resp)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("failed to write statement")
+		}
+		return &GetStatementOutput{
+			ContentType:        "text/csv; charset=utf-8",
+			ContentDisposition: fmt.Sprintf(`attachment; filename="%s.csv"`, filename),
+			Body:               data,
+		}, nil
+	}
+
+	return &GetStatementOutput{
+		ContentType:        "application/json",
+		ContentDisposition: fmt.Sprintf(`inline; filename="%s.json"`, filename),
+		Body:               resp,
+	}, nil
+}
+
+// statementToResponse converts a statement to its API representation.
+func statementToResponse(statement *service.Statement) StatementResponse {
+	resp := StatementResponse{
+		Period:      statement.Period,
+		PeriodStart: statement.PeriodStart.Format(time.RFC3339),
+		PeriodEnd:   statement.PeriodEnd.Format(time.RFC3339),
+		Lines:       make([]StatementLineResponse, 0, len(statement.Lines)),
+		Totals: StatementTotalsResponse{
+			Jobs:              statement.Totals.Jobs,
+			BYOKJobs:          statement.Totals.BYOKJobs,
+			TokensInput:       statement.Totals.TokensInput,
+			TokensOutput:      statement.Totals.TokensOutput,
+			LLMCostUSD:        statement.Totals.LLMCostUSD,
+			MarkupUSD:         statement.Totals.MarkupUSD,
+			PerTransactionUSD: statement.Totals.PerTransactionUSD,
+			SolverCostUSD:     statement.Totals.SolverCostUSD,
+			ChargedUSD:        statement.Totals.ChargedUSD,
+			BYOKLLMCostUSD:    statement.Totals.BYOKLLMCostUSD,
+		},
+	}
+	for _, line := range statement.Lines {
+		resp.Lines = append(resp.Lines, StatementLineResponse{
+			Date:              line.Date,
+			UsageID:           line.UsageID,
+			JobID:             line.JobID,
+			Type:              string(line.Type),
+			Status:            line.Status,
+			URL:               line.TargetURL,
+			Provider:          line.LLMProvider,
+			Model:             line.LLMModel,
+			BYOK:              line.IsBYOK,
+			TokensInput:       line.TokensInput,
+			TokensOutput:      line.TokensOutput,
+			LLMCostUSD:        line.LLMCostUSD,
+			MarkupRate:        line.MarkupRate,
+			MarkupUSD:         line.MarkupUSD,
+			PerTransactionUSD: line.PerTransactionUSD,
+			SolverCostUSD:     line.SolverCostUSD,
+			ChargedUSD:        line.ChargedUSD,
+			CreatedAt:         line.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp
+}
+
+// statementCSVHeader is the column order of CSV statements.
+var statementCSVHeader = []string{
+	"date", "usage_id", "job_id", "type", "status", "url", "provider", "model", "byok",
+	"tokens_input", "tokens_output", "llm_cost_usd", "markup_rate", "markup_usd",
+	"per_transaction_usd", "solver_cost_usd", "charged_usd", "created_at",
+}
+
+// statementCSV renders a statement as CSV, one row per job followed by a totals row.
+func statementCSV(resp StatementResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(statementCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, line := range resp.Lines {
+		if err := w.Write([]string{
+			line.Date, line.UsageID, line.JobID, line.Type, line.Status, line.URL, line.Provider, line.Model,
+			strconv.FormatBool(line.BYOK),
+			strconv.Itoa(line.TokensInput), strconv.Itoa(line.TokensOutput),
+			formatUSD(line.LLMCostUSD), strconv.FormatFloat(line.MarkupRate, 'f', -1, 64), formatUSD(line.MarkupUSD),
+			formatUSD(line.PerTransactionUSD), formatUSD(line.SolverCostUSD), formatUSD(line.ChargedUSD), line.CreatedAt,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	totals := resp.Totals
+	if err := w.Write([]string{
+		"total", "", "", "", "", "", "", "", "",
+		strconv.Itoa(totals.TokensInput), strconv.Itoa(totals.TokensOutput),
+		formatUSD(totals.LLMCostUSD), "", formatUSD(totals.MarkupUSD),
+		formatUSD(totals.PerTransactionUSD), formatUSD(totals.SolverCostUSD), formatUSD(totals.ChargedUSD), "",
+	}); err != nil {
+		return nil, err
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatUSD formats an amount with enough precision for sub-cent per-job costs.
+func formatUSD(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 8, 64)
+}
@@ -35,6 +35,30 @@ func ExtractErrorInfo(err error, isBYOK bool) ErrorInfo {
 		return info
 	}
 
+	// Auth profile errors are for the user to fix: a missing profile, or a login that stopped working
+	switch {
+	case errors.Is(err, service.ErrAuthProfileNotFound):
+		info.UserMessage = service.ErrAuthProfileNotFound.Error()
+		info.Details = err.Error()
+		info.Category = "auth_profile_not_found"
+		info.StatusCode = http.StatusNotFound
+		return info
+	case errors.Is(err, service.ErrAuthSessionExpired):
+		info.UserMessage = service.ErrAuthSessionExpired.Error()
+		info.Details = err.Error()
+		info.Category = "auth_session_expired"
+		info.StatusCode = http.StatusUnprocessableEntity
+		return info
+	}
+
+	if errors.Is(err, service.ErrSolverBudgetExceeded) {
+		info.UserMessage = service.ErrSolverBudgetExceeded.Error()
+		info.Details = err.Error()
+		info.Category = "solver_budget_exceeded"
+		info.StatusCode = http.StatusTooManyRequests
+		return info
+	}
+
 	var llmErr *llm.LLMError
 	if errors.As(err, &llmErr) {
 		info.UserMessage = llmErr.UserMessage
@@ -63,6 +87,8 @@ func CategorizeError(llmErr *llm.LLMError) string {
 		return "invalid_api_key"
 	case errors.Is(llmErr.Err, llm.ErrInsufficientCredits):
 		return "insufficient_credits"
+	case errors.Is(llmErr.Err, llm.ErrSpendCapExceeded):
+		return "spend_cap_exceeded"
 	case errors.Is(llmErr.Err, llm.ErrTierQuotaExceeded):
 		return "quota_exceeded"
 	case errors.Is(llmErr.Err, llm.ErrTierFeatureDisabled):
@@ -93,6 +119,8 @@ func DetermineStatusCode(llmErr *llm.LLMError) int {
 		return http.StatusForbidden
 	case errors.Is(llmErr.Err, llm.ErrInsufficientCredits):
 		return http.StatusPaymentRequired
+	case errors.Is(llmErr.Err, llm.ErrSpendCapExceeded):
+		return http.StatusTooManyRequests
 	case errors.Is(llmErr.Err, llm.ErrFreeTierRateLimited):
 		return http.StatusTooManyRequests
 	case errors.Is(llmErr.Err, llm.ErrFreeTierQuotaExhausted):
@@ -3,9 +3,14 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/jmylchreest/refyne-api/internal/constants"
 	"github.com/jmylchreest/refyne-api/internal/service"
 )
 
@@ -25,11 +30,11 @@ func NewExtractionHandler(extractionSvc *service.ExtractionService, jobSvc *serv
 
 // CleanerConfigInput represents a cleaner in the chain.
 type CleanerConfigInput struct {
-	Name    string                 `json:"name" minLength:"1" doc:"Cleaner name (noop, refyne)"`
-	Options *CleanerOptionsInput   `json:"options,omitempty" doc:"Cleaner-specific options"`
+	Name    string               `json:"name" minLength:"1" doc:"Cleaner name (noop, refyne, select, readability, script_data, table)"`
+	Options *CleanerOptionsInput `json:"options,omitempty" doc:"Cleaner-specific options"`
 }
 
-// CleanerOptionsInput represents cleaner configuration options for refyne cleaner.
+// CleanerOptionsInput represents cleaner configuration options.
 type CleanerOptionsInput struct {
 	// Output format
 	Output  string `json:"output,omitempty" enum:"html,text,markdown" default:"html" doc:"Output format: html, text, or markdown"`
@@ -45,26 +50,93 @@ type CleanerOptionsInput struct {
 	ExtractImages      bool `json:"extract_images,omitempty" doc:"Extract images to frontmatter with {{IMG_001}} placeholders (markdown)"`
 	ExtractHeadings    bool `json:"extract_headings,omitempty" doc:"Extract heading structure to frontmatter (markdown)"`
 	ResolveURLs        bool `json:"resolve_urls,omitempty" doc:"Resolve relative URLs to absolute using base_url"`
+
+	// Options for other cleaners
+	Selector    string `json:"selector,omitempty" doc:"CSS selector of the elements to keep (select cleaner, required)"`
+	TableFormat string `json:"table_format,omitempty" enum:"markdown,csv" doc:"Table output format: markdown or csv (table cleaner)"`
+}
+
+// PreprocessorConfigInput represents a preprocessor in the chain.
+type PreprocessorConfigInput struct {
+	Name    string                    `json:"name" minLength:"1" enum:"noop,hint_repeats,hint_feedback" doc:"Preprocessor name (noop, hint_repeats, hint_feedback)"`
+	Options *PreprocessorOptionsInput `json:"options,omitempty" doc:"Preprocessor-specific options"`
+}
+
+// PreprocessorOptionsInput represents preprocessor configuration options.
+type PreprocessorOptionsInput struct {
+	MinRepeats int     `json:"min_repeats,omitempty" minimum:"0" doc:"Minimum number of repeated elements to report (hint_repeats, hint_feedback)"`
+	MinScore   float64 `json:"min_score,omitempty" minimum:"0" maximum:"1" doc:"Minimum aggregate signal score to report feedback (hint_feedback)"`
+}
+
+// StructuredDataInput configures the structured data fast path.
+type StructuredDataInput struct {
+	Mode        string  `json:"mode,omitempty" enum:"auto,off" default:"auto" doc:"auto: map embedded JSON-LD, microdata and OpenGraph data onto the schema before calling the LLM; off: always use the LLM"`
+	MinCoverage float64 `json:"min_coverage,omitempty" minimum:"0" maximum:"1" default:"1" doc:"Fraction of schema fields that must be filled from structured data to skip the LLM call (default 1.0 = all fields)"`
+}
+
+// BrowserActionInput is a scripted browser interaction run after the page loads and
+// before it is captured. Actions run in order and require browser rendering.
+type BrowserActionInput struct {
+	Type     string `json:"type" enum:"click,scroll_to_bottom,type,select,hover,wait,evaluate" doc:"Action: click, scroll_to_bottom, type, select, hover, wait or evaluate"`
+	Selector string `json:"selector,omitempty" doc:"CSS selector of the target element (click, type, select, hover; wait waits for it to appear)"`
+	Text     string `json:"text,omitempty" doc:"Text to type (type) or the option text to choose (select)"`
+	Script   string `json:"script,omitempty" enum:"remove_overlays,expand_details,load_lazy_images,expand_accordions" doc:"Safe-listed snippet to run (evaluate) - arbitrary JavaScript is not accepted"`
+	Repeat   int    `json:"repeat,omitempty" minimum:"0" maximum:"50" doc:"Repeat up to this many times: click stops when the element disappears, scroll_to_bottom when the page stops growing"`
+	Delay    int    `json:"delay,omitempty" minimum:"0" maximum:"10000" doc:"Milliseconds to wait after the action (after each repeat when repeating)"`
+	Timeout  int    `json:"timeout,omitempty" minimum:"0" maximum:"30000" doc:"Milliseconds to wait for the selector (default 10000)"`
+	Optional bool   `json:"optional,omitempty" doc:"Continue with the remaining actions if this one fails"`
+}
+
+// NetworkCaptureInput records the XHR/fetch responses a page makes while it is rendered,
+// so data loaded from the site's own JSON APIs can be used for extraction.
+type NetworkCaptureInput struct {
+	URLPatterns     []string `json:"url_patterns,omitempty" maxItems:"20" example:"[\"/api/products\"]" doc:"Regular expressions - responses whose URL matches one are recorded"`
+	ContentTypes    []string `json:"content_types,omitempty" maxItems:"20" example:"[\"json\"]" doc:"MIME type substrings - matching responses are recorded (default: json when no url_patterns are given)"`
+	MaxResponses    int      `json:"max_responses,omitempty" minimum:"0" maximum:"200" doc:"Maximum responses recorded per page (default 50)"`
+	IncludeInPrompt *bool    `json:"include_in_prompt,omitempty" doc:"Add the captured responses to the extraction prompt as a separate section (default true); when false they are only kept for debug capture"`
+}
+
+// FetchOptionsInput tunes how pages are fetched. The resource policy applies when a page
+// is rendered in the browser, where blocking unneeded resources speeds up rendering.
+// Proxies apply to every fetch, static or rendered.
+type FetchOptionsInput struct {
+	ResourcePolicy     string   `json:"resource_policy,omitempty" enum:"text_only,no_media,full" doc:"Resources to load when rendering in the browser: text_only (block images, media, fonts, stylesheets and trackers), no_media (block images, media, fonts and trackers) or full (default)"`
+	BlockResourceTypes []string `json:"block_resource_types,omitempty" maxItems:"14" enum:"stylesheet,image,media,font,script,texttrack,xhr,fetch,prefetch,eventsource,websocket,manifest,ping,other" doc:"Additional resource types to block"`
+	BlockDomains       []string `json:"block_domains,omitempty" maxItems:"200" example:"[\"ads.example.com\"]" doc:"Hosts to block, including their subdomains"`
+	BlockURLPatterns   []string `json:"block_url_patterns,omitempty" maxItems:"50" example:"[\"*/tracking/*\"]" doc:"URL globs to block (* matches anything, ? one character)"`
+	Proxy              string   `json:"proxy,omitempty" enum:"pool,own" doc:"Fetch through a proxy: pool (managed proxy pool, requires the proxy_pool feature) or own (your proxies, requires the provider_byok feature). Blocked pages are retried through another proxy"`
+	ProxyCountry       string   `json:"proxy_country,omitempty" pattern:"^[A-Za-z]{2}$" example:"DE" doc:"Only use proxies exiting in this ISO 3166-1 alpha-2 country"`
+	ProxySession       string   `json:"proxy_session,omitempty" maxLength:"100" doc:"Sticky session key - fetches with the same key use the same proxy while it stays healthy"`
 }
 
 // ExtractInput represents extraction request.
 type ExtractInput struct {
 	Body struct {
-		URL          string               `json:"url" minLength:"1" doc:"URL to extract data from"`
-		Schema       json.RawMessage      `json:"schema" minLength:"1" doc:"Extraction instructions - either a structured schema (YAML/JSON with 'name' and 'fields') or freeform natural language prompt. The API auto-detects the format and returns 'input_format' in the response."`
-		FetchMode    string               `json:"fetch_mode,omitempty" enum:"auto,static,dynamic" default:"auto" doc:"Fetch mode: auto, static, or dynamic"`
-		LLMConfig    *LLMConfigInput      `json:"llm_config,omitempty" doc:"Optional LLM configuration override"`
-		CleanerChain []CleanerConfigInput `json:"cleaner_chain,omitempty" doc:"Content cleaner chain (default: [markdown])"`
-		CaptureDebug bool                 `json:"capture_debug,omitempty" doc:"Enable debug capture to store raw LLM request/response for troubleshooting"`
-		WebhookID    string               `json:"webhook_id,omitempty" doc:"ID of a saved webhook to call on completion"`
-		Webhook      *InlineWebhookInput  `json:"webhook,omitempty" doc:"Inline ephemeral webhook configuration"`
-		WebhookURL   string               `json:"webhook_url,omitempty" format:"uri" doc:"Simple webhook URL (backward compatible)"`
+		URL            string                    `json:"url,omitempty" doc:"URL to extract data from (required unless content is provided)"`
+		Content        string                    `json:"content,omitempty" doc:"Page content to extract from instead of fetching a URL (max 5MB)"`
+		ContentType    string                    `json:"content_type,omitempty" enum:"html,markdown,text" default:"html" doc:"Format of content: html, markdown or text"`
+		BaseURL        string                    `json:"base_url,omitempty" doc:"URL the content came from, used to resolve relative links and reported as the extraction URL"`
+		Schema         json.RawMessage           `json:"schema" minLength:"1" doc:"Extraction instructions - either a structured schema (YAML/JSON with 'name' and 'fields') or freeform natural language prompt. The API auto-detects the format and returns 'input_format' in the response."`
+		FetchMode      string                    `json:"fetch_mode,omitempty" enum:"auto,static,dynamic" default:"auto" doc:"Fetch mode: auto, static, or dynamic"`
+		LLMConfig      *LLMConfigInput           `json:"llm_config,omitempty" doc:"Optional LLM configuration override"`
+		CleanerChain   []CleanerConfigInput      `json:"cleaner_chain,omitempty" doc:"Content cleaner chain (default: [markdown])"`
+		Preprocessors  []PreprocessorConfigInput `json:"preprocessors,omitempty" doc:"Preprocessor chain that adds hints such as detected item counts to the prompt (default: [hint_repeats, hint_feedback]; use [noop] to disable)"`
+		StructuredData *StructuredDataInput      `json:"structured_data,omitempty" doc:"Structured data fast path options (schema extraction only)"`
+		Actions        []BrowserActionInput      `json:"actions,omitempty" maxItems:"25" doc:"Browser actions (click, scroll, type, wait, ...) to run before the page is captured - implies browser rendering and requires the content_dynamic feature"`
+		CaptureNetwork *NetworkCaptureInput      `json:"capture_network,omitempty" doc:"Record XHR/fetch responses made while rendering the page - implies browser rendering and requires the content_dynamic feature"`
+		InputMode      string                    `json:"input_mode,omitempty" enum:"text,visual,hybrid" default:"text" doc:"What schema extraction reads: text (page content), visual (full-page screenshots, for data in images or canvas) or hybrid (page content, with fields it missed filled from screenshots). Screenshot modes imply browser rendering, require the content_dynamic feature and use only vision-capable models"`
+		FetchOptions   *FetchOptionsInput        `json:"fetch_options,omitempty" doc:"Fetch options such as the resource policy used when the page is rendered in the browser and proxies to fetch through"`
+		AuthProfileID  string                    `json:"auth_profile_id,omitempty" doc:"ID of an auth profile whose login cookies are sent with the fetch; login profiles sign in again when the page redirects to the login page"`
+		CaptureDebug   bool                      `json:"capture_debug,omitempty" doc:"Enable debug capture to store raw LLM request/response for troubleshooting"`
+		WebhookID      string                    `json:"webhook_id,omitempty" doc:"ID of a saved webhook to call on completion"`
+		Webhook        *InlineWebhookInput       `json:"webhook,omitempty" doc:"Inline ephemeral webhook configuration"`
+		WebhookURL     string                    `json:"webhook_url,omitempty" format:"uri" doc:"Simple webhook URL (backward compatible)"`
 	}
 }
 
 // LLMConfigInput represents LLM config in request.
 type LLMConfigInput struct {
-	Provider       string `json:"provider,omitempty" enum:"anthropic,openai,openrouter,ollama,helicone,credits" doc:"LLM provider"`
+	Provider       string `json:"provider,omitempty" enum:"anthropic,openai,openrouter,ollama,helicone,gemini,mistral,groq,together,openai_compatible,credits" doc:"LLM provider"`
 	APIKey         string `json:"api_key,omitempty" doc:"API key for the provider"`
 	BaseURL        string `json:"base_url,omitempty" doc:"Custom base URL (for Ollama or self-hosted Helicone)"`
 	Model          string `json:"model,omitempty" doc:"Model to use"`
@@ -117,18 +189,37 @@ type UsageResponse struct {
 	CostUSD      float64 `json:"cost_usd" doc:"Total USD cost charged for this extraction"`
 	LLMCostUSD   float64 `json:"llm_cost_usd" doc:"Actual LLM cost from provider"`
 	IsBYOK       bool    `json:"is_byok" doc:"True if user's own API key was used (no charge)"`
+
+	BlockedRequests int     `json:"blocked_requests,omitempty" doc:"Requests blocked by the resource policy while rendering the page"`
+	SolverCostUSD   float64 `json:"solver_cost_usd,omitempty" doc:"CAPTCHA solver cost, included in cost_usd and charged at cost (also for BYOK)"`
 }
 
 // MetadataResponse represents metadata in response.
 type MetadataResponse struct {
-	FetchDurationMs   int    `json:"fetch_duration_ms" doc:"Time to fetch the page in milliseconds"`
-	ExtractDurationMs int    `json:"extract_duration_ms" doc:"Time to extract data in milliseconds"`
-	Model             string `json:"model" doc:"Model used for extraction"`
-	Provider          string `json:"provider" doc:"LLM provider used"`
+	FetchDurationMs   int                     `json:"fetch_duration_ms" doc:"Time to fetch the page in milliseconds"`
+	ExtractDurationMs int                     `json:"extract_duration_ms" doc:"Time to extract data in milliseconds"`
+	Model             string                  `json:"model" doc:"Model used for extraction"`
+	Provider          string                  `json:"provider" doc:"LLM provider used"`
+	StructuredData    *StructuredDataResponse `json:"structured_data,omitempty" doc:"Fields filled from embedded structured data (omitted if none were used)"`
+	Document          *DocumentResponse       `json:"document,omitempty" doc:"Document input details (omitted for web pages)"`
 }
 
-// Extract handles single-page extraction.
-// Uses the unified JobService.RunJob for consistent job lifecycle management including webhooks.
+// StructuredDataResponse describes how embedded structured data contributed to an extraction.
+type StructuredDataResponse struct {
+	Sources    []string `json:"sources" doc:"Structured data formats used: json-ld, microdata, opengraph"`
+	Fields     []string `json:"fields" doc:"Schema fields filled from structured data"`
+	Coverage   float64  `json:"coverage" doc:"Fraction of schema fields filled from structured data (0-1)"`
+	LLMSkipped bool     `json:"llm_skipped" doc:"True if the extraction was answered without an LLM call"`
+}
+
+// DocumentResponse describes a document (PDF, DOCX, XLSX or CSV) extracted in place of a web page.
+type DocumentResponse struct {
+	Format    string `json:"format" doc:"Document format: pdf, docx, xlsx or csv"`
+	Chunks    int    `json:"chunks" doc:"Number of page-aligned chunks sent to the LLM"`
+	Truncated bool   `json:"truncated,omitempty" doc:"True if the document was too long and only the leading chunks were extracted"`
+}
+
+// Extract handles single-page extraction from a URL or from content submitted in the request.
 func (h *ExtractionHandler) Extract(ctx context.Context, input *ExtractInput) (*ExtractOutput, error) {
 	// Extract user context from JWT claims
 	uc := ExtractUserContext(ctx)
@@ -141,27 +232,257 @@ func (h *ExtractionHandler) Extract(ctx context.Context, input *ExtractInput) (*
 		return nil, huma.Error400BadRequest("'schema' is required - provide either a structured schema (YAML/JSON) or freeform extraction instructions")
 	}
 
-	// Convert cleaner chain using shared utility
-	cleanerChain := ConvertCleanerChain(input.Body.CleanerChain)
+	// Exactly one of url or content selects what is extracted
+	if err := validateExtractTarget(input.Body.URL, input.Body.Content, input.Body.ContentType, input.Body.BaseURL); err != nil {
+		return nil, err
+	}
+	if err := validateBrowserActions(input.Body.Actions, input.Body.Content != "", uc.ContentDynamicAllowed); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkCapture(input.Body.CaptureNetwork, input.Body.Content != "", uc.ContentDynamicAllowed); err != nil {
+		return nil, err
+	}
+	if err := validateInputMode(input.Body.InputMode, input.Body.Schema, input.Body.Content != "", uc.ContentDynamicAllowed); err != nil {
+		return nil, err
+	}
+	if input.Body.FetchOptions != nil && input.Body.Content != "" {
+		return nil, huma.Error400BadRequest("'fetch_options' require a 'url' - submitted content isn't fetched")
+	}
+	if err := validateFetchOptions(input.Body.FetchOptions, uc); err != nil {
+		return nil, err
+	}
+	if input.Body.AuthProfileID != "" && input.Body.Content != "" {
+		return nil, huma.Error400BadRequest("'auth_profile_id' requires a 'url' - submitted content isn't fetched")
+	}
+
+	return h.runExtraction(ctx, uc, extractRequest{
+		input: service.ExtractInput{
+			URL:            input.Body.URL,
+			Content:        input.Body.Content,
+			ContentType:    input.Body.ContentType,
+			BaseURL:        input.Body.BaseURL,
+			Schema:         input.Body.Schema,
+			FetchMode:      input.Body.FetchMode,
+			CleanerChain:   ConvertCleanerChain(input.Body.CleanerChain),
+			Preprocessors:  ConvertPreprocessors(input.Body.Preprocessors),
+			StructuredData: ConvertStructuredData(input.Body.StructuredData),
+			Actions:        ConvertBrowserActions(input.Body.Actions),
+			CaptureNetwork: ConvertNetworkCapture(input.Body.CaptureNetwork),
+			FetchOptions:   ConvertFetchOptions(input.Body.FetchOptions),
+			AuthProfileID:  input.Body.AuthProfileID,
+			InputMode:      service.InputMode(input.Body.InputMode),
+		},
+		llmConfig:    input.Body.LLMConfig,
+		captureDebug: input.Body.CaptureDebug,
+		webhookID:    input.Body.WebhookID,
+		webhook:      input.Body.Webhook,
+		webhookURL:   input.Body.WebhookURL,
+	})
+}
+
+// ExtractUploadInput represents an extraction request with the content uploaded as a file.
+type ExtractUploadInput struct {
+	RawBody huma.MultipartFormFiles[struct {
+		File        huma.FormFile `form:"file" contentType:"text/html,text/markdown,text/plain,application/octet-stream" required:"true" doc:"HTML, markdown or text file to extract from (max 5MB)"`
+		Schema      string        `form:"schema" required:"true" doc:"Extraction instructions - either a structured schema (YAML/JSON) or freeform natural language prompt"`
+		ContentType string        `form:"content_type" enum:"html,markdown,text" doc:"Format of the file: html, markdown or text (default: from the file's content type or extension)"`
+		BaseURL     string        `form:"base_url" doc:"URL the content came from, used to resolve relative links and reported as the extraction URL"`
+		Options     string        `form:"options" doc:"JSON object of further extract options: llm_config, cleaner_chain, preprocessors, structured_data, capture_debug, webhook_id, webhook, webhook_url"`
+	}]
+}
+
+// ExtractUploadOptions holds the extract options accepted in the upload form's options field.
+type ExtractUploadOptions struct {
+	LLMConfig      *LLMConfigInput           `json:"llm_config,omitempty"`
+	CleanerChain   []CleanerConfigInput      `json:"cleaner_chain,omitempty"`
+	Preprocessors  []PreprocessorConfigInput `json:"preprocessors,omitempty"`
+	StructuredData *StructuredDataInput      `json:"structured_data,omitempty"`
+	CaptureDebug   bool                      `json:"capture_debug,omitempty"`
+	WebhookID      string                    `json:"webhook_id,omitempty"`
+	Webhook        *InlineWebhookInput       `json:"webhook,omitempty"`
+	WebhookURL     string                    `json:"webhook_url,omitempty"`
+}
+
+// ExtractUpload handles single-page extraction from an uploaded HTML, markdown or text file.
+// The file is extracted exactly as if it had been submitted as content to Extract.
+func (h *ExtractionHandler) ExtractUpload(ctx context.Context, input *ExtractUploadInput) (*ExtractOutput, error) {
+	uc := ExtractUserContext(ctx)
+	if !uc.IsAuthenticated() {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
 
+	form := input.RawBody.Data()
+	if strings.TrimSpace(form.Schema) == "" {
+		return nil, huma.Error400BadRequest("'schema' is required - provide either a structured schema (YAML/JSON) or freeform extraction instructions")
+	}
+
+	var opts ExtractUploadOptions
+	if form.Options != "" {
+		if err := json.Unmarshal([]byte(form.Options), &opts); err != nil {
+			return nil, huma.Error400BadRequest("invalid 'options': " + err.Error())
+		}
+	}
+
+	if form.File.Size > constants.MaxSubmittedContentSize {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("file exceeds maximum size of %d bytes", constants.MaxSubmittedContentSize))
+	}
+	body, err := io.ReadAll(io.LimitReader(form.File, constants.MaxSubmittedContentSize+1))
+	if err != nil {
+		return nil, huma.Error400BadRequest("failed to read file: " + err.Error())
+	}
+
+	contentType := form.ContentType
+	if contentType == "" {
+		contentType = uploadContentType(form.File.ContentType, form.File.Filename)
+	}
+	if err := validateExtractTarget("", string(body), contentType, form.BaseURL); err != nil {
+		return nil, err
+	}
+
+	return h.runExtraction(ctx, uc, extractRequest{
+		input: service.ExtractInput{
+			Content:        string(body),
+			ContentType:    contentType,
+			BaseURL:        form.BaseURL,
+			Schema:         json.RawMessage(form.Schema),
+			CleanerChain:   ConvertCleanerChain(opts.CleanerChain),
+			Preprocessors:  ConvertPreprocessors(opts.Preprocessors),
+			StructuredData: ConvertStructuredData(opts.StructuredData),
+		},
+		llmConfig:    opts.LLMConfig,
+		captureDebug: opts.CaptureDebug,
+		webhookID:    opts.WebhookID,
+		webhook:      opts.Webhook,
+		webhookURL:   opts.WebhookURL,
+	})
+}
+
+// uploadContentType infers the content type of an uploaded file from its MIME type,
+// falling back to the file extension. Unrecognised files are treated as HTML.
+func uploadContentType(mimeType, filename string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "text/markdown"):
+		return service.SubmittedContentMarkdown
+	case strings.HasPrefix(mimeType, "text/plain"):
+		return service.SubmittedContentText
+	case strings.HasPrefix(mimeType, "text/html"):
+		return service.SubmittedContentHTML
+	}
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".md", ".markdown":
+		return service.SubmittedContentMarkdown
+	case ".txt":
+		return service.SubmittedContentText
+	}
+	return service.SubmittedContentHTML
+}
+
+// validateExtractTarget checks that exactly one of a URL or submitted content is given,
+// and that submitted content is valid.
+func validateExtractTarget(url, content, contentType, baseURL string) error {
+	switch {
+	case url == "" && content == "":
+		return huma.Error400BadRequest("either 'url' or 'content' is required")
+	case url != "" && content != "":
+		return huma.Error400BadRequest("'url' and 'content' are mutually exclusive - provide one or the other")
+	case content == "":
+		return nil
+	}
+
+	submitted := service.SubmittedContent{Body: content, Type: contentType, BaseURL: baseURL}
+	if err := submitted.Validate(); err != nil {
+		return huma.Error400BadRequest(err.Error())
+	}
+	return nil
+}
+
+// validateBrowserActions checks that browser actions can run: they need a page to
+// render, so they can't be combined with submitted content, and browser rendering
+// must be enabled for the user.
+func validateBrowserActions(actions []BrowserActionInput, hasContent, contentDynamicAllowed bool) error {
+	switch {
+	case len(actions) == 0:
+		return nil
+	case hasContent:
+		return huma.Error400BadRequest("'actions' require a 'url' - they can't be run against submitted content")
+	case !contentDynamicAllowed:
+		return huma.Error403Forbidden(service.ErrDynamicFetchNotAllowed.Error())
+	}
+	return nil
+}
+
+// validateNetworkCapture checks that network capture can run. Like browser actions it
+// records a rendered page, so it needs a URL and browser rendering.
+func validateNetworkCapture(capture *NetworkCaptureInput, hasContent, contentDynamicAllowed bool) error {
+	switch {
+	case capture == nil:
+		return nil
+	case hasContent:
+		return huma.Error400BadRequest("'capture_network' requires a 'url' - submitted content has no network traffic")
+	case !contentDynamicAllowed:
+		return huma.Error403Forbidden(service.ErrDynamicFetchNotAllowed.Error())
+	}
+	return nil
+}
+
+// validateInputMode checks that screenshot input modes can be used: they need a page
+// to render, the content_dynamic feature and a structured schema.
+func validateInputMode(mode string, schema json.RawMessage, hasContent, contentDynamicAllowed bool) error {
+	if !service.InputMode(mode).UsesScreenshots() {
+		return nil
+	}
+	switch format, _, _ := service.DetectInputFormat(schema); {
+	case hasContent:
+		return huma.Error400BadRequest("'input_mode' " + mode + " requires a 'url' - submitted content can't be screenshotted")
+	case format == service.InputFormatPrompt:
+		return huma.Error400BadRequest("'input_mode' " + mode + " requires a structured schema, not a freeform prompt")
+	case !contentDynamicAllowed:
+		return huma.Error403Forbidden(service.ErrDynamicFetchNotAllowed.Error())
+	}
+	return nil
+}
+
+// validateFetchOptions checks that the requested proxies are available to the user:
+// the managed pool needs the proxy_pool feature and own proxies need provider_byok.
+func validateFetchOptions(opts *FetchOptionsInput, uc UserContext) error {
+	switch {
+	case opts == nil:
+		return nil
+	case opts.Proxy == "" && (opts.ProxyCountry != "" || opts.ProxySession != ""):
+		return huma.Error400BadRequest("'proxy_country' and 'proxy_session' require 'proxy'")
+	case opts.Proxy == service.ProxySourcePool && !uc.ProxyPoolAllowed:
+		return huma.Error403Forbidden(constants.FeatureNotAvailableMessage(constants.FeatureProxyPool))
+	case opts.Proxy == service.ProxySourceOwn && !uc.BYOKAllowed:
+		return huma.Error403Forbidden(constants.FeatureNotAvailableMessage(constants.FeatureProviderBYOK))
+	}
+	return nil
+}
+
+// extractRequest is a validated single-page extraction request from Extract or ExtractUpload.
+type extractRequest struct {
+	input        service.ExtractInput
+	llmConfig    *LLMConfigInput
+	captureDebug bool
+	webhookID    string
+	webhook      *InlineWebhookInput
+	webhookURL   string
+}
+
+// runExtraction runs a single-page extraction as a job and builds the response.
+// Uses the unified JobService.RunJob for consistent job lifecycle management including webhooks.
+func (h *ExtractionHandler) runExtraction(ctx context.Context, uc UserContext, req extractRequest) (*ExtractOutput, error) {
 	// Build extraction context
-	ectx := BuildExtractContext(uc, input.Body.LLMConfig)
+	ectx := BuildExtractContext(uc, req.llmConfig)
 
 	// Convert LLM config
-	llmCfg := ConvertLLMConfig(input.Body.LLMConfig)
-	isBYOK := IsBYOKFromLLMConfig(input.Body.LLMConfig)
+	req.input.LLMConfig = ConvertLLMConfig(req.llmConfig)
+	isBYOK := IsBYOKFromLLMConfig(req.llmConfig)
 
 	// Create executor
-	executor := service.NewExtractExecutor(h.extractionSvc, service.ExtractInput{
-		URL:          input.Body.URL,
-		Schema:       input.Body.Schema,
-		FetchMode:    input.Body.FetchMode,
-		LLMConfig:    llmCfg,
-		CleanerChain: cleanerChain,
-	}, ectx)
+	executor := service.NewExtractExecutor(h.extractionSvc, req.input, ectx)
 
 	// Build ephemeral webhook config if provided
-	ephemeralWebhook := BuildEphemeralWebhook(input.Body.Webhook, input.Body.WebhookURL)
+	ephemeralWebhook := BuildEphemeralWebhook(req.webhook, req.webhookURL)
 
 	// Run job with full lifecycle management (creates job, executes, handles webhooks)
 	var jobID string
@@ -171,9 +492,9 @@ func (h *ExtractionHandler) Extract(ctx context.Context, input *ExtractInput) (*
 		runResult, err := h.jobSvc.RunJob(ctx, executor, &service.RunJobOptions{
 			UserID:           uc.UserID,
 			Tier:             uc.Tier,
-			CaptureDebug:     input.Body.CaptureDebug,
+			CaptureDebug:     req.captureDebug,
 			EphemeralWebhook: ephemeralWebhook,
-			WebhookID:        input.Body.WebhookID,
+			WebhookID:        req.webhookID,
 		})
 		if err != nil {
 			return nil, NewJobError(err, isBYOK)
@@ -192,13 +513,7 @@ func (h *ExtractionHandler) Extract(ctx context.Context, input *ExtractInput) (*
 
 	// Fallback: direct extraction without job tracking (if jobSvc is nil or result extraction failed)
 	if result == nil {
-		directResult, directErr := h.extractionSvc.ExtractWithContext(ctx, uc.UserID, service.ExtractInput{
-			URL:          input.Body.URL,
-			Schema:       input.Body.Schema,
-			FetchMode:    input.Body.FetchMode,
-			LLMConfig:    llmCfg,
-			CleanerChain: cleanerChain,
-		}, ectx)
+		directResult, directErr := h.extractionSvc.ExtractWithContext(ctx, uc.UserID, req.input, ectx)
 		if directErr != nil {
 			return nil, NewJobError(directErr, isBYOK)
 		}
@@ -226,12 +541,17 @@ func (h *ExtractionHandler) Extract(ctx context.Context, input *ExtractInput) (*
 				CostUSD:      result.Usage.CostUSD,
 				LLMCostUSD:   result.Usage.LLMCostUSD,
 				IsBYOK:       result.Usage.IsBYOK,
+
+				BlockedRequests: result.Usage.BlockedRequests,
+				SolverCostUSD:   result.Usage.SolverCostUSD,
 			},
 			Metadata: MetadataResponse{
 				FetchDurationMs:   result.Metadata.FetchDurationMs,
 				ExtractDurationMs: result.Metadata.ExtractDurationMs,
 				Model:             result.Metadata.Model,
 				Provider:          result.Metadata.Provider,
+				StructuredData:    ConvertStructuredDataMeta(result.Metadata.StructuredData),
+				Document:          ConvertDocumentMeta(result.Metadata.Document),
 			},
 		},
 	}, nil
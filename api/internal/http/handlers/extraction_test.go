@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/service"
+)
+
+// ========================================
+// validateExtractTarget Tests
+// ========================================
+
+func TestValidateExtractTarget(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		content     string
+		contentType string
+		baseURL     string
+		wantErr     bool
+	}{
+		{"url only", "https://example.com", "", "", "", false},
+		{"content only", "", "<p>Hi</p>", "html", "", false},
+		{"content with base url", "", "# Hi", "markdown", "https://example.com/a", false},
+		{"neither", "", "", "", "", true},
+		{"both", "https://example.com", "<p>Hi</p>", "", "", true},
+		{"blank content", "", "   ", "", "", true},
+		{"relative base url", "", "Hi", "text", "/a", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateExtractTarget(tt.url, tt.content, tt.contentType, tt.baseURL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateExtractTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var statusErr huma.StatusError
+			if err != nil && (!errors.As(err, &statusErr) || statusErr.GetStatus() != 400) {
+				t.Errorf("validateExtractTarget() error = %v, want 400", err)
+			}
+		})
+	}
+}
+
+// ========================================
+// uploadContentType Tests
+// ========================================
+
+func TestUploadContentType(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		filename string
+		expected string
+	}{
+		{"text/html; charset=utf-8", "page.txt", "html"},
+		{"text/markdown", "notes", "markdown"},
+		{"text/plain", "README.md", "text"},
+		{"application/octet-stream", "README.md", "markdown"},
+		{"application/octet-stream", "notes.TXT", "text"},
+		{"", "page.htm", "html"},
+		{"", "", "html"},
+	}
+
+	for _, tt := range tests {
+		if got := uploadContentType(tt.mimeType, tt.filename); got != tt.expected {
+			t.Errorf("uploadContentType(%q, %q) = %q, want %q", tt.mimeType, tt.filename, got, tt.expected)
+		}
+	}
+}
+
+// ========================================
+// validateBrowserActions Tests
+// ========================================
+
+func TestValidateBrowserActions(t *testing.T) {
+	actions := []BrowserActionInput{{Type: "scroll_to_bottom", Repeat: 10}}
+
+	tests := []struct {
+		name                  string
+		actions               []BrowserActionInput
+		hasContent            bool
+		contentDynamicAllowed bool
+		wantStatus            int
+	}{
+		{"no actions", nil, true, false, 0},
+		{"allowed", actions, false, true, 0},
+		{"with submitted content", actions, true, true, 400},
+		{"without content_dynamic", actions, false, false, 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBrowserActions(tt.actions, tt.hasContent, tt.contentDynamicAllowed)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("validateBrowserActions() error = %v, want nil", err)
+				}
+				return
+			}
+			var statusErr huma.StatusError
+			if !errors.As(err, &statusErr) || statusErr.GetStatus() != tt.wantStatus {
+				t.Errorf("validateBrowserActions() error = %v, want %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidateNetworkCapture(t *testing.T) {
+	capture := &NetworkCaptureInput{ContentTypes: []string{"json"}}
+
+	tests := []struct {
+		name                  string
+		capture               *NetworkCaptureInput
+		hasContent            bool
+		contentDynamicAllowed bool
+		wantStatus            int
+	}{
+		{"no capture", nil, true, false, 0},
+		{"allowed", capture, false, true, 0},
+		{"with submitted content", capture, true, true, 400},
+		{"without content_dynamic", capture, false, false, 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNetworkCapture(tt.capture, tt.hasContent, tt.contentDynamicAllowed)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("validateNetworkCapture() error = %v, want nil", err)
+				}
+				return
+			}
+			var statusErr huma.StatusError
+			if !errors.As(err, &statusErr) || statusErr.GetStatus() != tt.wantStatus {
+				t.Errorf("validateNetworkCapture() error = %v, want %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidateInputMode(t *testing.T) {
+	sch := json.RawMessage(`{"name": "Product", "fields": [{"name": "title", "type": "string"}]}`)
+	prompt := json.RawMessage(`"Extract the product title"`)
+
+	tests := []struct {
+		name                  string
+		mode                  string
+		schema                json.RawMessage
+		hasContent            bool
+		contentDynamicAllowed bool
+		wantStatus            int
+	}{
+		{"default", "", prompt, true, false, 0},
+		{"text", "text", prompt, true, false, 0},
+		{"visual", "visual", sch, false, true, 0},
+		{"hybrid", "hybrid", sch, false, true, 0},
+		{"with submitted content", "visual", sch, true, true, 400},
+		{"with prompt", "hybrid", prompt, false, true, 400},
+		{"without content_dynamic", "visual", sch, false, false, 403},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateInputMode(tt.mode, tt.schema, tt.hasContent, tt.contentDynamicAllowed)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("validateInputMode() error = %v, want nil", err)
+				}
+				return
+			}
+			var statusErr huma.StatusError
+			if !errors.As(err, &statusErr) || statusErr.GetStatus() != tt.wantStatus {
+				t.Errorf("validateInputMode() error = %v, want %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidateFetchOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       *FetchOptionsInput
+		uc         UserContext
+		wantStatus int
+	}{
+		{"no options", nil, UserContext{}, 0},
+		{"no proxy", &FetchOptionsInput{ResourcePolicy: "text_only"}, UserContext{}, 0},
+		{"pool allowed", &FetchOptionsInput{Proxy: "pool", ProxyCountry: "DE"}, UserContext{ProxyPoolAllowed: true}, 0},
+		{"pool without proxy_pool", &FetchOptionsInput{Proxy: "pool"}, UserContext{BYOKAllowed: true}, 403},
+		{"own allowed", &FetchOptionsInput{Proxy: "own", ProxySession: "s1"}, UserContext{BYOKAllowed: true}, 0},
+		{"own without provider_byok", &FetchOptionsInput{Proxy: "own"}, UserContext{ProxyPoolAllowed: true}, 403},
+		{"country without proxy", &FetchOptionsInput{ProxyCountry: "DE"}, UserContext{ProxyPoolAllowed: true}, 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFetchOptions(tt.opts, tt.uc)
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("validateFetchOptions() error = %v, want nil", err)
+				}
+				return
+			}
+			var statusErr huma.StatusError
+			if !errors.As(err, &statusErr) || statusErr.GetStatus() != tt.wantStatus {
+				t.Errorf("validateFetchOptions() error = %v, want %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestExtractErrorInfo_AuthProfile(t *testing.T) {
+	tests := []struct {
+		err          error
+		wantCategory string
+		wantStatus   int
+	}{
+		{service.ErrAuthProfileNotFound, "auth_profile_not_found", 404},
+		{fmt.Errorf("page 3: %w", service.ErrAuthSessionExpired), "auth_session_expired", 422},
+	}
+
+	for _, tt := range tests {
+		info := ExtractErrorInfo(tt.err, false)
+		if info.Category != tt.wantCategory || info.StatusCode != tt.wantStatus {
+			t.Errorf("ExtractErrorInfo(%v) = %s/%d, want %s/%d", tt.err, info.Category, info.StatusCode, tt.wantCategory, tt.wantStatus)
+		}
+	}
+}
+
+func TestConvertNetworkCapture(t *testing.T) {
+	if got := ConvertNetworkCapture(nil); got != nil {
+		t.Errorf("ConvertNetworkCapture(nil) = %v, want nil", got)
+	}
+
+	no := false
+	got := ConvertNetworkCapture(&NetworkCaptureInput{
+		URLPatterns:     []string{"/api/products"},
+		MaxResponses:    20,
+		IncludeInPrompt: &no,
+	})
+	if len(got.URLPatterns) != 1 || got.MaxResponses != 20 || got.IncludeInPrompt == nil || *got.IncludeInPrompt {
+		t.Errorf("ConvertNetworkCapture() = %+v", got)
+	}
+}
+
+func TestConvertFetchOptions(t *testing.T) {
+	if got := ConvertFetchOptions(nil); got != nil {
+		t.Errorf("ConvertFetchOptions(nil) = %v, want nil", got)
+	}
+
+	got := ConvertFetchOptions(&FetchOptionsInput{
+		ResourcePolicy:     "no_media",
+		BlockResourceTypes: []string{"script"},
+		BlockDomains:       []string{"ads.example.com"},
+		BlockURLPatterns:   []string{"*/tracking/*"},
+		Proxy:              "pool",
+		ProxyCountry:       "de",
+	})
+	if got.ResourcePolicy != service.ResourcePolicyNoMedia || len(got.BlockResourceTypes) != 1 || len(got.BlockDomains) != 1 || len(got.BlockURLPatterns) != 1 {
+		t.Errorf("ConvertFetchOptions() = %+v", got)
+	}
+	if got.Proxy != service.ProxySourcePool || got.ProxyCountry != "DE" {
+		t.Errorf("ConvertFetchOptions() proxy = %q/%q, want pool/DE", got.Proxy, got.ProxyCountry)
+	}
+}
+
+func TestConvertBrowserActions(t *testing.T) {
+	if got := ConvertBrowserActions(nil); got != nil {
+		t.Errorf("ConvertBrowserActions(nil) = %v, want nil", got)
+	}
+
+	got := ConvertBrowserActions([]BrowserActionInput{
+		{Type: "click", Selector: "button.more", Repeat: 3, Delay: 500, Optional: true},
+		{Type: "evaluate", Script: "remove_overlays"},
+	})
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2", len(got))
+	}
+	if got[0].Type != "click" || got[0].Selector != "button.more" || got[0].Repeat != 3 || got[0].Delay != 500 || !got[0].Optional {
+		t.Errorf("action 0 = %+v", got[0])
+	}
+	if got[1].Type != "evaluate" || got[1].Script != "remove_overlays" {
+		t.Errorf("action 1 = %+v", got[1])
+	}
+}
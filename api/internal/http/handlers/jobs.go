@@ -73,11 +73,11 @@ type CrawlInlineWebhookInput struct {
 
 // JobCleanerConfigInput represents a cleaner in the chain (duplicated to avoid import cycle).
 type JobCleanerConfigInput struct {
-	Name    string                  `json:"name" minLength:"1" doc:"Cleaner name (noop, refyne)"`
+	Name    string                  `json:"name" minLength:"1" doc:"Cleaner name (noop, refyne, select, readability, script_data, table)"`
 	Options *JobCleanerOptionsInput `json:"options,omitempty" doc:"Cleaner-specific options"`
 }
 
-// JobCleanerOptionsInput represents cleaner configuration options for refyne cleaner.
+// JobCleanerOptionsInput represents cleaner configuration options.
 type JobCleanerOptionsInput struct {
 	// Output format
 	Output  string `json:"output,omitempty" enum:"html,text,markdown" default:"html" doc:"Output format: html, text, or markdown"`
@@ -93,21 +93,26 @@ type JobCleanerOptionsInput struct {
 	ExtractImages      bool `json:"extract_images,omitempty" doc:"Extract images to frontmatter with {{IMG_001}} placeholders (markdown)"`
 	ExtractHeadings    bool `json:"extract_headings,omitempty" doc:"Extract heading structure to frontmatter (markdown)"`
 	ResolveURLs        bool `json:"resolve_urls,omitempty" doc:"Resolve relative URLs to absolute using base_url"`
+
+	// Options for other cleaners
+	Selector    string `json:"selector,omitempty" doc:"CSS selector of the elements to keep (select cleaner, required)"`
+	TableFormat string `json:"table_format,omitempty" enum:"markdown,csv" doc:"Table output format: markdown or csv (table cleaner)"`
 }
 
 type CreateCrawlJobInput struct {
 	Wait    bool `query:"wait" default:"false" doc:"Block until job completes and return results directly. Max wait time is 2 minutes. Returns 202 if timeout exceeded."`
 	Timeout int  `query:"timeout" default:"120" minimum:"10" maximum:"120" doc:"Maximum seconds to wait when wait=true (default 120s, max 120s/2min). For longer jobs, use async mode."`
 	Body    struct {
-		URL          string                   `json:"url" minLength:"1" example:"https://example.com/products" doc:"Seed URL to start crawling from"`
-		Schema       json.RawMessage          `json:"schema" minLength:"1" doc:"Extraction instructions - either a structured schema (YAML/JSON with 'name' and 'fields') or freeform natural language prompt. The API auto-detects the format."`
-		Options      CrawlOptions             `json:"options,omitempty" doc:"Crawl configuration options"`
-		CleanerChain []JobCleanerConfigInput  `json:"cleaner_chain,omitempty" doc:"Content cleaner chain (default: [markdown])"`
-		CaptureDebug *bool                    `json:"capture_debug,omitempty" doc:"Enable debug capture to store raw LLM request/response for troubleshooting"`
-		WebhookID    string                   `json:"webhook_id,omitempty" doc:"ID of a saved webhook to call on job events"`
-		Webhook      *CrawlInlineWebhookInput `json:"webhook,omitempty" doc:"Inline ephemeral webhook configuration"`
-		WebhookURL   string                   `json:"webhook_url,omitempty" format:"uri" example:"https://my-app.com/webhook/crawl-complete" doc:"Simple webhook URL (backward compatible)"`
-		LLMConfig    *LLMConfigInput          `json:"llm_config,omitempty" doc:"Optional LLM configuration override (BYOK)"`
+		URL           string                    `json:"url" minLength:"1" example:"https://example.com/products" doc:"Seed URL to start crawling from"`
+		Schema        json.RawMessage           `json:"schema" minLength:"1" doc:"Extraction instructions - either a structured schema (YAML/JSON with 'name' and 'fields') or freeform natural language prompt. The API auto-detects the format."`
+		Options       CrawlOptions              `json:"options,omitempty" doc:"Crawl configuration options"`
+		CleanerChain  []JobCleanerConfigInput   `json:"cleaner_chain,omitempty" doc:"Content cleaner chain (default: [markdown])"`
+		Preprocessors []PreprocessorConfigInput `json:"preprocessors,omitempty" doc:"Preprocessor chain that adds hints such as detected item counts to the prompt (default: [hint_repeats, hint_feedback]; use [noop] to disable)"`
+		CaptureDebug  *bool                     `json:"capture_debug,omitempty" doc:"Enable debug capture to store raw LLM request/response for troubleshooting"`
+		WebhookID     string                    `json:"webhook_id,omitempty" doc:"ID of a saved webhook to call on job events"`
+		Webhook       *CrawlInlineWebhookInput  `json:"webhook,omitempty" doc:"Inline ephemeral webhook configuration"`
+		WebhookURL    string                    `json:"webhook_url,omitempty" format:"uri" example:"https://my-app.com/webhook/crawl-complete" doc:"Simple webhook URL (backward compatible)"`
+		LLMConfig     *LLMConfigInput           `json:"llm_config,omitempty" doc:"Optional LLM configuration override (BYOK)"`
 	}
 }
 
@@ -119,18 +124,24 @@ type CreateCrawlJobInput struct {
 // 3. Filtering URLs by follow_pattern regex (if provided)
 // 4. Respecting max_pages, max_depth, and same_domain_only limits
 type CrawlOptions struct {
-	FollowSelector   string `json:"follow_selector,omitempty" example:"a.product-link, a[href*='/product/']" doc:"CSS selector(s) for links to follow. Comma-separated or newline-separated."`
-	FollowPattern    string `json:"follow_pattern,omitempty" example:"/product/.*|/item/.*" doc:"Regex pattern to filter URLs. Only matching URLs are crawled."`
-	MaxDepth         int    `json:"max_depth,omitempty" default:"1" maximum:"5" example:"2" doc:"Maximum crawl depth from seed URL (1 = seed + direct links)"`
-	NextSelector     string `json:"next_selector,omitempty" example:"a.pagination-next" doc:"CSS selector for pagination 'next' link"`
-	MaxPages         int    `json:"max_pages,omitempty" default:"10" maximum:"100" example:"20" doc:"Maximum total pages to crawl (0 = no limit, up to tier max)"`
-	MaxURLs          int    `json:"max_urls,omitempty" default:"50" maximum:"500" example:"100" doc:"Maximum URLs to discover and queue"`
-	Delay            string `json:"delay,omitempty" default:"500ms" example:"1s" doc:"Delay between requests (e.g., 500ms, 1s, 2s)"`
-	Concurrency      int    `json:"concurrency,omitempty" default:"3" maximum:"10" example:"5" doc:"Concurrent extraction requests"`
-	SameDomainOnly   bool   `json:"same_domain_only,omitempty" default:"true" doc:"Only follow links on the same domain as seed URL"`
-	ExtractFromSeeds bool   `json:"extract_from_seeds,omitempty" example:"true" doc:"Extract data from the seed URL (not just discovered pages)"`
-	UseSitemap       bool   `json:"use_sitemap,omitempty" doc:"Discover URLs from sitemap.xml instead of CSS selectors"`
-	FetchMode        string `json:"fetch_mode,omitempty" enum:"auto,static,dynamic" default:"auto" doc:"Page fetching mode: auto (detect and retry with browser if needed), static (fast, Colly-based), dynamic (browser rendering for JS-heavy sites, requires content_dynamic feature)"`
+	FollowSelector   string               `json:"follow_selector,omitempty" example:"a.product-link, a[href*='/product/']" doc:"CSS selector(s) for links to follow. Comma-separated or newline-separated."`
+	FollowPattern    string               `json:"follow_pattern,omitempty" example:"/product/.*|/item/.*" doc:"Regex pattern to filter URLs. Only matching URLs are crawled."`
+	MaxDepth         int                  `json:"max_depth,omitempty" default:"1" maximum:"5" example:"2" doc:"Maximum crawl depth from seed URL (1 = seed + direct links)"`
+	NextSelector     string               `json:"next_selector,omitempty" example:"a.pagination-next" doc:"CSS selector for pagination 'next' link"`
+	MaxPages         int                  `json:"max_pages,omitempty" default:"10" maximum:"100" example:"20" doc:"Maximum total pages to crawl (0 = no limit, up to tier max)"`
+	MaxURLs          int                  `json:"max_urls,omitempty" default:"50" maximum:"500" example:"100" doc:"Maximum URLs to discover and queue"`
+	Delay            string               `json:"delay,omitempty" default:"500ms" example:"1s" doc:"Delay between requests (e.g., 500ms, 1s, 2s)"`
+	Concurrency      int                  `json:"concurrency,omitempty" default:"3" maximum:"10" example:"5" doc:"Concurrent extraction requests"`
+	SameDomainOnly   bool                 `json:"same_domain_only,omitempty" default:"true" doc:"Only follow links on the same domain as seed URL"`
+	ExtractFromSeeds bool                 `json:"extract_from_seeds,omitempty" example:"true" doc:"Extract data from the seed URL (not just discovered pages)"`
+	UseSitemap       bool                 `json:"use_sitemap,omitempty" doc:"Discover URLs from sitemap.xml instead of CSS selectors"`
+	FetchMode        string               `json:"fetch_mode,omitempty" enum:"auto,static,dynamic" default:"auto" doc:"Page fetching mode: auto (detect and retry with browser if needed), static (fast, Colly-based), dynamic (browser rendering for JS-heavy sites, requires content_dynamic feature)"`
+	StructuredData   *StructuredDataInput `json:"structured_data,omitempty" doc:"Structured data fast path options - pages with JSON-LD, microdata or OpenGraph data covering the schema skip the LLM"`
+	Actions          []BrowserActionInput `json:"actions,omitempty" maxItems:"25" doc:"Browser actions (click, scroll, type, wait, ...) run on each extracted page before capture - implies browser rendering and requires the content_dynamic feature"`
+	CaptureNetwork   *NetworkCaptureInput `json:"capture_network,omitempty" doc:"Record XHR/fetch responses made while rendering each extracted page - implies browser rendering and requires the content_dynamic feature"`
+	InputMode        string               `json:"input_mode,omitempty" enum:"text,visual,hybrid" default:"text" doc:"What schema extraction reads on each page: text (page content), visual (full-page screenshots, for data in images or canvas) or hybrid (page content, with fields it missed filled from screenshots). Screenshot modes imply browser rendering, require the content_dynamic feature and use only vision-capable models"`
+	FetchOptions     *FetchOptionsInput   `json:"fetch_options,omitempty" doc:"Fetch options such as the resource policy used when pages are rendered in the browser and proxies to fetch through"`
+	AuthProfileID    string               `json:"auth_profile_id,omitempty" doc:"ID of an auth profile whose login cookies are sent with every fetch; login profiles sign in again when a page redirects to the login page"`
 }
 
 // TokenUsage represents LLM token consumption for a job.
@@ -181,6 +192,18 @@ func (h *JobHandler) CreateCrawlJob(ctx context.Context, input *CreateCrawlJobIn
 	if !uc.IsAuthenticated() {
 		return nil, huma.Error401Unauthorized("unauthorized")
 	}
+	if err := validateBrowserActions(input.Body.Options.Actions, false, uc.ContentDynamicAllowed); err != nil {
+		return nil, err
+	}
+	if err := validateNetworkCapture(input.Body.Options.CaptureNetwork, false, uc.ContentDynamicAllowed); err != nil {
+		return nil, err
+	}
+	if err := validateInputMode(input.Body.Options.InputMode, input.Body.Schema, false, uc.ContentDynamicAllowed); err != nil {
+		return nil, err
+	}
+	if err := validateFetchOptions(input.Body.Options.FetchOptions, uc); err != nil {
+		return nil, err
+	}
 
 	// Resolve LLM config chain at job creation time
 	// Check for S3 API key LLM configs first - these bypass the tier-based fallback chain
@@ -233,15 +256,25 @@ func (h *JobHandler) CreateCrawlJob(ctx context.Context, input *CreateCrawlJobIn
 			ExtractFromSeeds:      input.Body.Options.ExtractFromSeeds,
 			UseSitemap:            input.Body.Options.UseSitemap,
 			FetchMode:             input.Body.Options.FetchMode,
+			StructuredData:        ConvertStructuredData(input.Body.Options.StructuredData),
+			Actions:               ConvertBrowserActions(input.Body.Options.Actions),
+			CaptureNetwork:        ConvertNetworkCapture(input.Body.Options.CaptureNetwork),
+			FetchOptions:          ConvertFetchOptions(input.Body.Options.FetchOptions),
+			AuthProfileID:         input.Body.Options.AuthProfileID,
+			InputMode:             service.InputMode(input.Body.Options.InputMode),
 			ContentDynamicAllowed: uc.ContentDynamicAllowed,
 			SkipCreditCheck:       uc.SkipCreditCheckAllowed,
+			APIKeyID:              uc.APIKeyID,
+			RoutingPolicy:         llmChain.Routing().Policy,
+			RoutingReason:         llmChain.Routing().Reason,
 		},
-		CleanerChain: cleanerChain,
-		WebhookURL:   input.Body.WebhookURL,
-		LLMConfigs:   llmChain.All(),
-		Tier:         uc.Tier,
-		IsBYOK:       llmChain.IsBYOK(),
-		CaptureDebug: input.Body.CaptureDebug,
+		CleanerChain:  cleanerChain,
+		Preprocessors: ConvertPreprocessors(input.Body.Preprocessors),
+		WebhookURL:    input.Body.WebhookURL,
+		LLMConfigs:    llmChain.All(),
+		Tier:          uc.Tier,
+		IsBYOK:        llmChain.IsBYOK(),
+		CaptureDebug:  input.Body.CaptureDebug,
 	})
 	if err != nil {
 		return nil, huma.Error500InternalServerError("failed to create crawl job: " + err.Error())
@@ -1308,3 +1341,62 @@ func (h *JobHandler) DownloadJobDebugCapture(ctx context.Context, input *Downloa
 	}, nil
 }
 
+// GetJobDebugCaptureHARInput represents a debug capture HAR request.
+type GetJobDebugCaptureHARInput struct {
+	ID string `path:"id" doc:"Job ID"`
+}
+
+// GetJobDebugCaptureHAROutput represents a debug capture HAR response.
+type GetJobDebugCaptureHAROutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               *service.HAR
+}
+
+// GetJobDebugCaptureHAR returns the XHR/fetch responses captured while rendering a job's
+// pages as a HAR file, for opening in browser devtools or other HTTP debugging tools.
+// Only jobs run with both capture_debug and capture_network have network captures.
+func (h *JobHandler) GetJobDebugCaptureHAR(ctx context.Context, input *GetJobDebugCaptureHARInput) (*GetJobDebugCaptureHAROutput, error) {
+	claims := mw.GetUserClaims(ctx)
+	if claims == nil {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
+
+	var job *models.Job
+	var err error
+
+	// Superadmins can access any job
+	if claims.GlobalSuperadmin {
+		job, err = h.jobSvc.GetJobAdmin(ctx, input.ID)
+	} else {
+		job, err = h.jobSvc.GetJob(ctx, claims.UserID, input.ID)
+	}
+
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get job: " + err.Error())
+	}
+	if job == nil {
+		return nil, huma.Error404NotFound("job not found")
+	}
+
+	// Check if storage is enabled
+	if h.storageSvc == nil || !h.storageSvc.IsEnabled() {
+		return nil, huma.Error503ServiceUnavailable("debug capture storage is not configured")
+	}
+
+	capture, err := h.storageSvc.GetDebugCapture(ctx, input.ID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get debug capture: " + err.Error())
+	}
+
+	har := capture.HAR()
+	if har == nil {
+		return nil, huma.Error404NotFound("no network responses were captured for this job")
+	}
+
+	return &GetJobDebugCaptureHAROutput{
+		ContentType:        "application/json",
+		ContentDisposition: fmt.Sprintf(`attachment; filename="debug-%s.har"`, input.ID),
+		Body:               har,
+	}, nil
+}
@@ -40,6 +40,17 @@ type SSEResultEvent struct {
 	LLMModel      string `json:"llm_model,omitempty" doc:"LLM model used"`
 }
 
+// SSEPartialEvent is sent for each array item parsed from an LLM response while the model
+// is still writing it. Partial items are provisional: the page's result event and stored
+// results are authoritative (a page may be retried or fall back to another model).
+type SSEPartialEvent struct {
+	JobID string `json:"job_id" doc:"Job ID"`
+	URL   string `json:"url" doc:"Source URL of the page being extracted"`
+	Field string `json:"field,omitempty" doc:"Top-level property holding the array (omitted for a root array)"`
+	Index int    `json:"index" doc:"Position of the item in the array"`
+	Item  any    `json:"item" doc:"The extracted item"`
+}
+
 // SSECompleteEvent is sent when the job completes.
 type SSECompleteEvent struct {
 	JobID         string  `json:"job_id" doc:"Job ID"`
@@ -152,6 +163,10 @@ func (h *JobHandler) StreamResults(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Receive provisional items from LLM responses as they stream in
+	partials, unsubscribe := h.jobSvc.PartialResults().Subscribe(jobID)
+	defer unsubscribe()
+
 	// Poll for results with heartbeat to prevent proxy timeouts
 	// Track by ULID which is lexicographically time-ordered
 	var lastResultID string
@@ -170,6 +185,8 @@ func (h *JobHandler) StreamResults(w http.ResponseWriter, r *http.Request) {
 		case <-heartbeatTicker.C:
 			// Send heartbeat to keep connection alive
 			sendSSEHeartbeat(w, flusher)
+		case partial := <-partials:
+			sendSSEEvent(w, flusher, "partial", partial)
 		case <-pollTicker.C:
 			// Check for new results (ULIDs are time-ordered so id > lastID works correctly)
 			results, err := h.jobSvc.GetJobResultsAfterID(ctx, userID, jobID, lastResultID)
@@ -268,6 +285,7 @@ func (h *JobHandler) RegisterRawEndpoints(api huma.API) {
 
 Events sent:
 - **status**: Initial job status and progress updates
+- **partial**: Provisional array items parsed while the LLM is still writing a page's response (superseded by the page's result)
 - **result**: Each extracted result as it completes
 - **complete**: Final status when job finishes
 - **error**: Error notifications
@@ -285,6 +303,7 @@ curl -H "Authorization: Bearer rf_your_key" \
 		Security: []map[string][]string{{mw.SecurityScheme: {}}},
 	}, map[string]any{
 		"status":   SSEStatusEvent{},
+		"partial":  SSEPartialEvent{},
 		"result":   SSEResultEvent{},
 		"complete": SSECompleteEvent{},
 		"error":    SSEErrorEvent{},
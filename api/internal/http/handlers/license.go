@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/service"
+)
+
+// LicenseHandler handles self-hosted license admin endpoints.
+type LicenseHandler struct {
+	licenseSvc *service.LicenseService
+}
+
+// NewLicenseHandler creates a new license handler.
+func NewLicenseHandler(licenseSvc *service.LicenseService) *LicenseHandler {
+	return &LicenseHandler{licenseSvc: licenseSvc}
+}
+
+// LicenseResponse represents the installed license and its status.
+type LicenseResponse struct {
+	Status           string   `json:"status" enum:"valid,grace,expired,invalid,missing" doc:"License status"`
+	LicenseID        string   `json:"license_id,omitempty" doc:"Installed license ID"`
+	OrganizationName string   `json:"organization_name,omitempty" doc:"Licensed organization"`
+	Email            string   `json:"email,omitempty" doc:"License contact email"`
+	Tier             string   `json:"tier,omitempty" doc:"Licensed tier"`
+	MaxUsers         int      `json:"max_users" doc:"Maximum licensed users (0 = unlimited). Informational only - the number of users isn't limited by it"`
+	Features         []string `json:"features" doc:"Licensed features"`
+	IssuedAt         string   `json:"issued_at,omitempty" doc:"When the license was issued"`
+	ExpiresAt        string   `json:"expires_at,omitempty" doc:"When the license expires (omitted for perpetual licenses)"`
+	GraceEndsAt      string   `json:"grace_ends_at,omitempty" doc:"When an expired license stops granting features"`
+	Error            string   `json:"error,omitempty" doc:"Why the license is invalid"`
+	CheckedAt        string   `json:"checked_at" doc:"When the license was last checked"`
+}
+
+// LicenseOutput represents the license response.
+type LicenseOutput struct {
+	Body LicenseResponse
+}
+
+// GetLicense handles getting the installed license.
+func (h *LicenseHandler) GetLicense(ctx context.Context, input *struct{}) (*LicenseOutput, error) {
+	return &LicenseOutput{Body: licenseResponse(h.licenseSvc.Status())}, nil
+}
+
+// ReplaceLicenseInput represents the request to replace the license.
+type ReplaceLicenseInput struct {
+	Body struct {
+		LicenseKey string `json:"license_key" minLength:"1" doc:"Signed license key"`
+	}
+}
+
+// ReplaceLicense handles installing a new license, revoking the current one.
+func (h *LicenseHandler) ReplaceLicense(ctx context.Context, input *ReplaceLicenseInput) (*LicenseOutput, error) {
+	status, err := h.licenseSvc.Replace(ctx, input.Body.LicenseKey)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLicense) || errors.Is(err, service.ErrLicenseExpired) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError("failed to replace license")
+	}
+	return &LicenseOutput{Body: licenseResponse(status)}, nil
+}
+
+func licenseResponse(status service.LicenseStatus) LicenseResponse {
+	resp := LicenseResponse{
+		Status:    string(status.Status),
+		Features:  []string{},
+		Error:     status.Error,
+		CheckedAt: status.CheckedAt.Format(time.RFC3339),
+	}
+	if status.GraceEndsAt != nil {
+		resp.GraceEndsAt = status.GraceEndsAt.Format(time.RFC3339)
+	}
+
+	lic := status.License
+	if lic == nil {
+		return resp
+	}
+	resp.LicenseID = lic.ID
+	resp.OrganizationName = lic.OrganizationName
+	resp.Email = lic.Email
+	resp.Tier = lic.Tier
+	resp.MaxUsers = lic.MaxUsers
+	if lic.Features != nil {
+		resp.Features = lic.Features
+	}
+	if !lic.IssuedAt.IsZero() {
+		resp.IssuedAt = lic.IssuedAt.Format(time.RFC3339)
+	}
+	if lic.ExpiresAt != nil {
+		resp.ExpiresAt = lic.ExpiresAt.Format(time.RFC3339)
+	}
+	return resp
+}
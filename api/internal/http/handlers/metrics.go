@@ -6,17 +6,19 @@ import (
 	"github.com/danielgtaylor/huma/v2"
 
 	"github.com/jmylchreest/refyne-api/internal/http/mw"
+	"github.com/jmylchreest/refyne-api/internal/llm"
 	"github.com/jmylchreest/refyne-api/internal/repository"
 )
 
 // MetricsHandler handles internal metrics endpoints (superadmin only).
 type MetricsHandler struct {
-	repos *repository.Repositories
+	repos  *repository.Repositories
+	health *llm.HealthTracker
 }
 
 // NewMetricsHandler creates a new metrics handler.
-func NewMetricsHandler(repos *repository.Repositories) *MetricsHandler {
-	return &MetricsHandler{repos: repos}
+func NewMetricsHandler(repos *repository.Repositories, health *llm.HealthTracker) *MetricsHandler {
+	return &MetricsHandler{repos: repos, health: health}
 }
 
 // JobQueueStats represents job queue statistics.
@@ -34,10 +36,18 @@ type RateLimitStats struct {
 	TotalEntries      int `json:"total_entries" doc:"Total rate limit entries in database"`
 }
 
+// ProviderHealthStats represents LLM provider circuit breaker statistics.
+type ProviderHealthStats struct {
+	OpenCircuits     int                `json:"open_circuits" doc:"Provider/models currently moved to the back of fallback chains"`
+	HalfOpenCircuits int                `json:"half_open_circuits" doc:"Provider/models currently being probed"`
+	Models           []llm.HealthStatus `json:"models" doc:"Circuit breaker state per provider/model"`
+}
+
 // SystemMetrics represents overall system metrics.
 type SystemMetrics struct {
-	JobQueue   JobQueueStats  `json:"job_queue" doc:"Job queue statistics"`
-	RateLimits RateLimitStats `json:"rate_limits" doc:"API key rate limit statistics"`
+	JobQueue       JobQueueStats       `json:"job_queue" doc:"Job queue statistics"`
+	RateLimits     RateLimitStats      `json:"rate_limits" doc:"API key rate limit statistics"`
+	ProviderHealth ProviderHealthStats `json:"provider_health" doc:"LLM provider health and circuit breaker state"`
 }
 
 // GetMetricsOutput represents the metrics response.
@@ -81,5 +91,19 @@ func (h *MetricsHandler) GetMetrics(ctx context.Context, input *struct{}) (*GetM
 		}
 	}
 
+	// Get provider health from the in-process circuit breaker
+	metrics.ProviderHealth.Models = h.health.Snapshot()
+	if metrics.ProviderHealth.Models == nil {
+		metrics.ProviderHealth.Models = []llm.HealthStatus{}
+	}
+	for _, status := range metrics.ProviderHealth.Models {
+		switch status.State {
+		case llm.CircuitOpen:
+			metrics.ProviderHealth.OpenCircuits++
+		case llm.CircuitHalfOpen:
+			metrics.ProviderHealth.HalfOpenCircuits++
+		}
+	}
+
 	return &GetMetricsOutput{Body: metrics}, nil
 }
@@ -17,6 +17,8 @@ type TierLimitsResponse struct {
 	MaxConcurrentJobs    int     `json:"max_concurrent_jobs" doc:"Max concurrent jobs (0 = unlimited)"`
 	MaxPagesPerCrawl     int     `json:"max_pages_per_crawl" doc:"Max pages per crawl job (0 = unlimited)"`
 	RequestsPerMinute    int     `json:"requests_per_minute" doc:"API requests per minute limit (0 = unlimited)"`
+	RequestsPerHour      int     `json:"requests_per_hour" doc:"API requests per hour limit (0 = unlimited)"`
+	RequestsPerDay       int     `json:"requests_per_day" doc:"API requests per day limit (0 = unlimited)"`
 	CreditAllocationUSD  float64 `json:"credit_allocation_usd" doc:"Monthly USD credit for premium model calls (0 = none)"`
 	CreditRolloverMonths int     `json:"credit_rollover_months" doc:"Credit expiry: -1 = never, 0 = current period, N = N additional periods"`
 }
@@ -75,6 +77,8 @@ func ListTierLimits(ctx context.Context, _ *struct{}) (*ListTierLimitsOutput, er
 				MaxConcurrentJobs:    limits.MaxConcurrentJobs,
 				MaxPagesPerCrawl:     limits.MaxPagesPerCrawl,
 				RequestsPerMinute:    limits.RequestsPerMinute,
+				RequestsPerHour:      limits.RequestsPerHour,
+				RequestsPerDay:       limits.RequestsPerDay,
 				CreditAllocationUSD:  limits.CreditAllocationUSD,
 				CreditRolloverMonths: limits.CreditRolloverMonths,
 			},
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/http/mw"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/service"
+)
+
+// ProxyHandler handles proxy endpoints. Users manage their own proxies;
+// superadmins manage the shared pool through the admin endpoints.
+type ProxyHandler struct {
+	proxySvc *service.ProxyService
+}
+
+// NewProxyHandler creates a new proxy handler.
+func NewProxyHandler(proxySvc *service.ProxyService) *ProxyHandler {
+	return &ProxyHandler{proxySvc: proxySvc}
+}
+
+// ProxyResponse represents a proxy. Credentials are never returned.
+type ProxyResponse struct {
+	ID        string `json:"id" doc:"Proxy ID"`
+	Name      string `json:"name" doc:"Display name"`
+	Endpoint  string `json:"endpoint" doc:"Proxy scheme, host and port (without credentials)"`
+	Country   string `json:"country,omitempty" doc:"ISO 3166-1 alpha-2 exit country"`
+	IsEnabled bool   `json:"is_enabled" doc:"Whether the proxy is used for fetches"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// ProxyHealthResponse represents a proxy's fetch outcomes for a domain.
+type ProxyHealthResponse struct {
+	Domain       string  `json:"domain" doc:"Domain fetched (empty for all domains)"`
+	Successes    int64   `json:"successes" doc:"Pages fetched"`
+	Failures     int64   `json:"failures" doc:"Proxy or network errors"`
+	Bans         int64   `json:"bans" doc:"Pages blocked by bot protection"`
+	Score        float64 `json:"score" doc:"Health score from 0 to 1, used to weight proxy selection"`
+	Banned       bool    `json:"banned" doc:"Whether the proxy is resting after a recent block"`
+	LastBannedAt string  `json:"last_banned_at,omitempty"`
+	LastUsedAt   string  `json:"last_used_at"`
+}
+
+// ListProxiesOutput represents the proxy list response.
+type ListProxiesOutput struct {
+	Body struct {
+		Proxies []ProxyResponse `json:"proxies"`
+	}
+}
+
+// ProxyInputBody is the body for creating or updating a proxy.
+type ProxyInputBody struct {
+	Name      string `json:"name" minLength:"1" maxLength:"100" doc:"Display name"`
+	URL       string `json:"url,omitempty" doc:"Proxy URL (http, https or socks5), including any credentials. Required on create; omit on update to keep the current URL"`
+	Country   string `json:"country,omitempty" doc:"ISO 3166-1 alpha-2 exit country, for geo-targeted fetches"`
+	IsEnabled bool   `json:"is_enabled" doc:"Whether the proxy is used for fetches"`
+}
+
+// CreateProxyInput represents a proxy creation request.
+type CreateProxyInput struct {
+	Body ProxyInputBody
+}
+
+// UpdateProxyInput represents a proxy update request.
+type UpdateProxyInput struct {
+	ID   string `path:"id" doc:"Proxy ID"`
+	Body ProxyInputBody
+}
+
+// ProxyOutput represents a single proxy response.
+type ProxyOutput struct {
+	Body ProxyResponse
+}
+
+// ProxyIDInput identifies a proxy.
+type ProxyIDInput struct {
+	ID string `path:"id" doc:"Proxy ID"`
+}
+
+// DeleteProxyOutput represents the proxy deletion response.
+type DeleteProxyOutput struct {
+	Body struct {
+		Success bool `json:"success"`
+	}
+}
+
+// ProxyHealthOutput represents a proxy's health response.
+type ProxyHealthOutput struct {
+	Body struct {
+		Health []ProxyHealthResponse `json:"health" doc:"Outcomes across all domains first, then per domain"`
+	}
+}
+
+// ListProxies handles listing the user's own proxies.
+func (h *ProxyHandler) ListProxies(ctx context.Context, input *struct{}) (*ListProxiesOutput, error) {
+	userID, err := h.proxyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.list(ctx, userID)
+}
+
+// CreateProxy handles adding one of the user's own proxies.
+func (h *ProxyHandler) CreateProxy(ctx context.Context, input *CreateProxyInput) (*ProxyOutput, error) {
+	userID, err := h.proxyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.create(ctx, userID, input)
+}
+
+// UpdateProxy handles updating one of the user's own proxies.
+func (h *ProxyHandler) UpdateProxy(ctx context.Context, input *UpdateProxyInput) (*ProxyOutput, error) {
+	userID, err := h.proxyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.update(ctx, userID, input)
+}
+
+// DeleteProxy handles deleting one of the user's own proxies.
+func (h *ProxyHandler) DeleteProxy(ctx context.Context, input *ProxyIDInput) (*DeleteProxyOutput, error) {
+	userID, err := h.proxyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.delete(ctx, userID, input)
+}
+
+// GetProxyHealth handles getting the health of one of the user's own proxies.
+func (h *ProxyHandler) GetProxyHealth(ctx context.Context, input *ProxyIDInput) (*ProxyHealthOutput, error) {
+	userID, err := h.proxyOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return h.health(ctx, userID, input)
+}
+
+// AdminListProxies handles listing the managed proxy pool.
+func (h *ProxyHandler) AdminListProxies(ctx context.Context, input *struct{}) (*ListProxiesOutput, error) {
+	return h.list(ctx, "")
+}
+
+// AdminCreateProxy handles adding a proxy to the managed pool.
+func (h *ProxyHandler) AdminCreateProxy(ctx context.Context, input *CreateProxyInput) (*ProxyOutput, error) {
+	return h.create(ctx, "", input)
+}
+
+// AdminUpdateProxy handles updating a proxy in the managed pool.
+func (h *ProxyHandler) AdminUpdateProxy(ctx context.Context, input *UpdateProxyInput) (*ProxyOutput, error) {
+	return h.update(ctx, "", input)
+}
+
+// AdminDeleteProxy handles removing a proxy from the managed pool.
+func (h *ProxyHandler) AdminDeleteProxy(ctx context.Context, input *ProxyIDInput) (*DeleteProxyOutput, error) {
+	return h.delete(ctx, "", input)
+}
+
+// AdminGetProxyHealth handles getting the health of a proxy in the managed pool.
+func (h *ProxyHandler) AdminGetProxyHealth(ctx context.Context, input *ProxyIDInput) (*ProxyHealthOutput, error) {
+	return h.health(ctx, "", input)
+}
+
+// proxyOwner returns the authenticated user, who must have the provider_byok feature
+// to bring their own proxies.
+func (h *ProxyHandler) proxyOwner(ctx context.Context) (string, error) {
+	claims := mw.GetUserClaims(ctx)
+	if claims == nil || claims.UserID == "" {
+		return "", huma.Error401Unauthorized("authentication required")
+	}
+	if !claims.HasFeature(constants.FeatureProviderBYOK) {
+		return "", huma.Error403Forbidden(constants.FeatureNotAvailableMessage(constants.FeatureProviderBYOK))
+	}
+	return claims.UserID, nil
+}
+
+func (h *ProxyHandler) list(ctx context.Context, ownerID string) (*ListProxiesOutput, error) {
+	proxies, err := h.proxySvc.List(ctx, ownerID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list proxies")
+	}
+
+	output := &ListProxiesOutput{}
+	output.Body.Proxies = make([]ProxyResponse, 0, len(proxies))
+	for _, p := range proxies {
+		output.Body.Proxies = append(output.Body.Proxies, proxyToResponse(p))
+	}
+	return output, nil
+}
+
+func (h *ProxyHandler) create(ctx context.Context, ownerID string, input *CreateProxyInput) (*ProxyOutput, error) {
+	proxy, err := h.proxySvc.Create(ctx, ownerID, proxyInput(input.Body))
+	if err != nil {
+		return nil, proxyError(err, "failed to create proxy")
+	}
+	return &ProxyOutput{Body: proxyToResponse(proxy)}, nil
+}
+
+func (h *ProxyHandler) update(ctx context.Context, ownerID string, input *UpdateProxyInput) (*ProxyOutput, error) {
+	proxy, err := h.proxySvc.Update(ctx, ownerID, input.ID, proxyInput(input.Body))
+	if err != nil {
+		return nil, proxyError(err, "failed to update proxy")
+	}
+	return &ProxyOutput{Body: proxyToResponse(proxy)}, nil
+}
+
+func (h *ProxyHandler) delete(ctx context.Context, ownerID string, input *ProxyIDInput) (*DeleteProxyOutput, error) {
+	if err := h.proxySvc.Delete(ctx, ownerID, input.ID); err != nil {
+		return nil, proxyError(err, "failed to delete proxy")
+	}
+
+	output := &DeleteProxyOutput{}
+	output.Body.Success = true
+	return output, nil
+}
+
+func (h *ProxyHandler) health(ctx context.Context, ownerID string, input *ProxyIDInput) (*ProxyHealthOutput, error) {
+	health, err := h.proxySvc.Health(ctx, ownerID, input.ID)
+	if err != nil {
+		return nil, proxyError(err, "failed to get proxy health")
+	}
+
+	now := time.Now()
+	output := &ProxyHealthOutput{}
+	output.Body.Health = make([]ProxyHealthResponse, 0, len(health))
+	for _, ph := range health {
+		resp := ProxyHealthResponse{
+			Domain:     ph.Domain,
+			Successes:  ph.Successes,
+			Failures:   ph.Failures,
+			Bans:       ph.Bans,
+			Score:      ph.Score(constants.ProxyBanWeight),
+			Banned:     ph.BannedAt(now, constants.ProxyBanCooldown),
+			LastUsedAt: ph.LastUsedAt.Format(time.RFC3339),
+		}
+		if ph.LastBannedAt != nil {
+			resp.LastBannedAt = ph.LastBannedAt.Format(time.RFC3339)
+		}
+		output.Body.Health = append(output.Body.Health, resp)
+	}
+	return output, nil
+}
+
+// proxyInput converts a request body to service input.
+func proxyInput(body ProxyInputBody) service.ProxyInput {
+	return service.ProxyInput{
+		Name:      body.Name,
+		URL:       body.URL,
+		Country:   body.Country,
+		IsEnabled: body.IsEnabled,
+	}
+}
+
+// proxyError maps proxy service errors to HTTP errors.
+func proxyError(err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidProxy):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, service.ErrProxyNotFound):
+		return huma.Error404NotFound("proxy not found")
+	default:
+		return huma.Error500InternalServerError(message)
+	}
+}
+
+// proxyToResponse converts a proxy to its API representation.
+func proxyToResponse(p *models.Proxy) ProxyResponse {
+	return ProxyResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		Endpoint:  p.Endpoint,
+		Country:   p.Country,
+		IsEnabled: p.IsEnabled,
+		CreatedAt: p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+}
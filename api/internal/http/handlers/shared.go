@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"strings"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 	"github.com/jmylchreest/refyne-api/internal/models"
 	"github.com/jmylchreest/refyne-api/internal/service"
 )
@@ -26,12 +29,120 @@ func ConvertCleanerChain(input []CleanerConfigInput) []service.CleanerConfig {
 				ExtractImages:      c.Options.ExtractImages,
 				ExtractHeadings:    c.Options.ExtractHeadings,
 				ResolveURLs:        c.Options.ResolveURLs,
+				Selector:           c.Options.Selector,
+				TableFormat:        c.Options.TableFormat,
+			}
+		}
+	}
+	return chain
+}
+
+// ConvertPreprocessors converts handler preprocessor chain input to service preprocessor configs.
+func ConvertPreprocessors(input []PreprocessorConfigInput) []service.PreprocessorConfig {
+	if len(input) == 0 {
+		return nil
+	}
+
+	chain := make([]service.PreprocessorConfig, len(input))
+	for i, p := range input {
+		chain[i] = service.PreprocessorConfig{Name: p.Name}
+		if p.Options != nil {
+			chain[i].Options = &service.PreprocessorOptions{
+				MinRepeats: p.Options.MinRepeats,
+				MinScore:   p.Options.MinScore,
 			}
 		}
 	}
 	return chain
 }
 
+// ConvertStructuredData converts handler structured data options to service config.
+func ConvertStructuredData(input *StructuredDataInput) *service.StructuredDataConfig {
+	if input == nil {
+		return nil
+	}
+	return &service.StructuredDataConfig{
+		Mode:        input.Mode,
+		MinCoverage: input.MinCoverage,
+	}
+}
+
+// ConvertBrowserActions converts handler browser actions to the captcha service's actions.
+func ConvertBrowserActions(input []BrowserActionInput) []captcha.Action {
+	if len(input) == 0 {
+		return nil
+	}
+
+	actions := make([]captcha.Action, len(input))
+	for i, a := range input {
+		actions[i] = captcha.Action{
+			Type:     a.Type,
+			Selector: a.Selector,
+			Text:     a.Text,
+			Script:   a.Script,
+			Repeat:   a.Repeat,
+			Delay:    a.Delay,
+			Timeout:  a.Timeout,
+			Optional: a.Optional,
+		}
+	}
+	return actions
+}
+
+// ConvertNetworkCapture converts handler network capture options to service options.
+func ConvertNetworkCapture(input *NetworkCaptureInput) *service.NetworkCaptureConfig {
+	if input == nil {
+		return nil
+	}
+	return &service.NetworkCaptureConfig{
+		URLPatterns:     input.URLPatterns,
+		ContentTypes:    input.ContentTypes,
+		MaxResponses:    input.MaxResponses,
+		IncludeInPrompt: input.IncludeInPrompt,
+	}
+}
+
+// ConvertFetchOptions converts handler fetch options to service options.
+func ConvertFetchOptions(input *FetchOptionsInput) *service.FetchOptions {
+	if input == nil {
+		return nil
+	}
+	return &service.FetchOptions{
+		ResourcePolicy:     input.ResourcePolicy,
+		BlockResourceTypes: input.BlockResourceTypes,
+		BlockDomains:       input.BlockDomains,
+		BlockURLPatterns:   input.BlockURLPatterns,
+		Proxy:              input.Proxy,
+		ProxyCountry:       strings.ToUpper(input.ProxyCountry),
+		ProxySession:       input.ProxySession,
+	}
+}
+
+// ConvertStructuredDataMeta converts service structured data metadata to the response type.
+func ConvertStructuredDataMeta(meta *service.StructuredDataMeta) *StructuredDataResponse {
+	if meta == nil {
+		return nil
+	}
+	return &StructuredDataResponse{
+		Sources:    meta.Sources,
+		Fields:     meta.Fields,
+		Coverage:   meta.Coverage,
+		LLMSkipped: meta.LLMSkipped,
+	}
+}
+
+// ConvertDocumentMeta converts service document metadata to the response type.
+func ConvertDocumentMeta(meta *service.DocumentMeta) *DocumentResponse {
+	if meta == nil {
+		return nil
+	}
+	return &DocumentResponse{
+		Format:    meta.Format,
+		Chunks:    meta.Chunks,
+		Truncated: meta.Truncated,
+	}
+}
+
 // ConvertJobCleanerChain converts job handler cleaner chain input to service cleaner chain.
 // This handles the JobCleanerConfigInput type used in the jobs handler.
 func ConvertJobCleanerChain(input []JobCleanerConfigInput) []service.CleanerConfig {
@@ -53,6 +164,8 @@ func ConvertJobCleanerChain(input []JobCleanerConfigInput) []service.CleanerConf
 				ExtractImages:      c.Options.ExtractImages,
 				ExtractHeadings:    c.Options.ExtractHeadings,
 				ResolveURLs:        c.Options.ResolveURLs,
+				Selector:           c.Options.Selector,
+				TableFormat:        c.Options.TableFormat,
 			}
 		}
 	}
@@ -63,6 +176,7 @@ func ConvertJobCleanerChain(input []JobCleanerConfigInput) []service.CleanerConf
 func BuildExtractContext(uc UserContext, llmConfig *LLMConfigInput) *service.ExtractContext {
 	ectx := &service.ExtractContext{
 		UserID:                 uc.UserID,
+		APIKeyID:               uc.APIKeyID,
 		Tier:                   uc.Tier,
 		BYOKAllowed:            uc.BYOKAllowed,
 		ModelsCustomAllowed:    uc.ModelsCustomAllowed,
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/service"
+)
+
+// SpendCapHandler handles spend cap endpoints.
+type SpendCapHandler struct {
+	spendCapSvc *service.SpendCapService
+}
+
+// NewSpendCapHandler creates a new spend cap handler.
+func NewSpendCapHandler(spendCapSvc *service.SpendCapService) *SpendCapHandler {
+	return &SpendCapHandler{spendCapSvc: spendCapSvc}
+}
+
+// SpendCapResponse represents a spend cap and its current period usage.
+type SpendCapResponse struct {
+	ID          string            `json:"id" doc:"Spend cap ID"`
+	APIKeyID    string            `json:"api_key_id,omitempty" doc:"API key the cap applies to (empty for all usage)"`
+	Period      string            `json:"period" doc:"Cap period (daily, monthly)"`
+	MaxCostUSD  *float64          `json:"max_cost_usd,omitempty" doc:"Maximum spend in USD (credits charged, or LLM cost for BYOK)"`
+	MaxTokens   *int64            `json:"max_tokens,omitempty" doc:"Maximum input + output tokens"`
+	MaxPages    *int64            `json:"max_pages,omitempty" doc:"Maximum pages extracted"`
+	Usage       models.SpendUsage `json:"usage" doc:"Usage in the current period"`
+	PeriodStart string            `json:"period_start" doc:"Start of the current period (UTC date)"`
+	Percent     float64           `json:"percent" doc:"Largest share of any limit used in the current period"`
+	CreatedAt   string            `json:"created_at"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+// ListSpendCapsOutput represents the spend cap list response.
+type ListSpendCapsOutput struct {
+	Body struct {
+		SpendCaps []SpendCapResponse `json:"spend_caps" doc:"User's spend caps"`
+	}
+}
+
+// ListSpendCaps handles listing the user's spend caps.
+func (h *SpendCapHandler) ListSpendCaps(ctx context.Context, input *struct{}) (*ListSpendCapsOutput, error) {
+	userID := getUserID(ctx)
+	if userID == "" {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
+
+	statuses, err := h.spendCapSvc.List(ctx, userID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to list spend caps")
+	}
+
+	output := &ListSpendCapsOutput{}
+	output.Body.SpendCaps = make([]SpendCapResponse, 0, len(statuses))
+	for _, status := range statuses {
+		output.Body.SpendCaps = append(output.Body.SpendCaps, spendCapToResponse(status))
+	}
+	return output, nil
+}
+
+// SetSpendCapInput represents a request to create or replace a spend cap.
+type SetSpendCapInput struct {
+	Body struct {
+		APIKeyID   string   `json:"api_key_id,omitempty" doc:"API key to cap (omit to cap all usage)"`
+		Period     string   `json:"period" enum:"daily,monthly" doc:"Cap period"`
+		MaxCostUSD *float64 `json:"max_cost_usd,omitempty" exclusiveMinimum:"0" doc:"Maximum spend in USD (credits charged, or LLM cost for BYOK)"`
+		MaxTokens  *int64   `json:"max_tokens,omitempty" minimum:"1" doc:"Maximum input + output tokens"`
+		MaxPages   *int64   `json:"max_pages,omitempty" minimum:"1" doc:"Maximum pages extracted"`
+	}
+}
+
+// SetSpendCapOutput represents the set spend cap response.
+type SetSpendCapOutput struct {
+	Body SpendCapResponse
+}
+
+// SetSpendCap handles creating or replacing a spend cap.
+// A user has at most one cap per API key (or all usage) and period; setting it again replaces its limits.
+func (h *SpendCapHandler) SetSpendCap(ctx context.Context, input *SetSpendCapInput) (*SetSpendCapOutput, error) {
+	userID := getUserID(ctx)
+	if userID == "" {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
+
+	spendCap, err := h.spendCapSvc.Set(ctx, userID, service.SpendCapInput{
+		APIKeyID:   input.Body.APIKeyID,
+		Period:     models.SpendCapPeriod(input.Body.Period),
+		MaxCostUSD: input.Body.MaxCostUSD,
+		MaxTokens:  input.Body.MaxTokens,
+		MaxPages:   input.Body.MaxPages,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSpendCap):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, service.ErrSpendCapNotFound):
+			return nil, huma.Error404NotFound("API key not found")
+		default:
+			return nil, huma.Error500InternalServerError("failed to set spend cap")
+		}
+	}
+
+	// Return the cap with its current usage so clients can show progress immediately
+	statuses, err := h.spendCapSvc.List(ctx, userID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get spend cap usage")
+	}
+	for _, status := range statuses {
+		if status.Cap.ID == spendCap.ID {
+			return &SetSpendCapOutput{Body: spendCapToResponse(status)}, nil
+		}
+	}
+	return &SetSpendCapOutput{Body: spendCapToResponse(service.SpendCapStatus{
+		Cap:         spendCap,
+		PeriodStart: spendCap.Period.Start(time.Now()),
+	})}, nil
+}
+
+// DeleteSpendCapInput represents a spend cap deletion request.
+type DeleteSpendCapInput struct {
+	ID string `path:"id" doc:"Spend cap ID"`
+}
+
+// DeleteSpendCapOutput represents the spend cap deletion response.
+type DeleteSpendCapOutput struct {
+	Body struct {
+		Success bool `json:"success"`
+	}
+}
+
+// DeleteSpendCap handles deleting a spend cap.
+func (h *SpendCapHandler) DeleteSpendCap(ctx context.Context, input *DeleteSpendCapInput) (*DeleteSpendCapOutput, error) {
+	userID := getUserID(ctx)
+	if userID == "" {
+		return nil, huma.Error401Unauthorized("unauthorized")
+	}
+
+	if err := h.spendCapSvc.Delete(ctx, userID, input.ID); err != nil {
+		if errors.Is(err, service.ErrSpendCapNotFound) {
+			return nil, huma.Error404NotFound("spend cap not found")
+		}
+		return nil, huma.Error500InternalServerError("failed to delete spend cap")
+	}
+
+	output := &DeleteSpendCapOutput{}
+	output.Body.Success = true
+	return output, nil
+}
+
+// spendCapToResponse converts a spend cap status to its API representation.
+func spendCapToResponse(status service.SpendCapStatus) SpendCapResponse {
+	return SpendCapResponse{
+		ID:          status.Cap.ID,
+		APIKeyID:    status.Cap.APIKeyID,
+		Period:      string(status.Cap.Period),
+		MaxCostUSD:  status.Cap.MaxCostUSD,
+		MaxTokens:   status.Cap.MaxTokens,
+		MaxPages:    status.Cap.MaxPages,
+		Usage:       status.Usage,
+		PeriodStart: status.PeriodStart.Format("2006-01-02"),
+		Percent:     status.Percent,
+		CreatedAt:   status.Cap.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   status.Cap.UpdatedAt.Format(time.RFC3339),
+	}
+}
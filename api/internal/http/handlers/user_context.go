@@ -13,14 +13,16 @@ import (
 // Used across handlers to avoid repeating the same extraction logic.
 type UserContext struct {
 	UserID                 string
+	APIKeyID               string // ID of the API key used to authenticate (empty for session auth)
 	Tier                   string
 	BYOKAllowed            bool
 	ModelsCustomAllowed    bool
-	ModelsPremiumAllowed   bool   // Access to premium/charged models with budget-based fallback
-	ContentDynamicAllowed  bool   // JavaScript/real browser support for dynamic content
-	SkipCreditCheckAllowed bool   // Skip pre-flight credit balance check (limited by quota instead)
-	LLMProvider            string // For S3 API keys: forced LLM provider (deprecated, use LLMConfigs)
-	LLMModel               string // For S3 API keys: forced LLM model (deprecated, use LLMConfigs)
+	ModelsPremiumAllowed   bool                     // Access to premium/charged models with budget-based fallback
+	ContentDynamicAllowed  bool                     // JavaScript/real browser support for dynamic content
+	SkipCreditCheckAllowed bool                     // Skip pre-flight credit balance check (limited by quota instead)
+	ProxyPoolAllowed       bool                     // Fetch through the managed proxy pool
+	LLMProvider            string                   // For S3 API keys: forced LLM provider (deprecated, use LLMConfigs)
+	LLMModel               string                   // For S3 API keys: forced LLM model (deprecated, use LLMConfigs)
 	LLMConfigs             []config.APIKeyLLMConfig // For S3 API keys: fallback chain of LLM configs
 }
 
@@ -41,6 +43,8 @@ func ExtractUserContext(ctx context.Context) UserContext {
 		uc.ModelsPremiumAllowed = claims.HasFeature(constants.FeatureModelsPremium)
 		uc.ContentDynamicAllowed = claims.HasFeature(constants.FeatureContentDynamic)
 		uc.SkipCreditCheckAllowed = claims.HasFeature(constants.FeatureSkipCreditCheck)
+		uc.ProxyPoolAllowed = claims.HasFeature(constants.FeatureProxyPool)
+		uc.APIKeyID = claims.APIKeyID
 		uc.LLMProvider = claims.LLMProvider
 		uc.LLMModel = claims.LLMModel
 		uc.LLMConfigs = claims.LLMConfigs
@@ -8,6 +8,7 @@ import (
 	"github.com/jmylchreest/refyne-api/internal/constants"
 	"github.com/jmylchreest/refyne-api/internal/http/mw"
 	"github.com/jmylchreest/refyne-api/internal/llm"
+	"github.com/jmylchreest/refyne-api/internal/models"
 	"github.com/jmylchreest/refyne-api/internal/service"
 )
 
@@ -208,6 +209,7 @@ type UserFallbackChainEntryResponse struct {
 	Model       string   `json:"model"`
 	Temperature *float64 `json:"temperature,omitempty"`
 	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Weight      int      `json:"weight,omitempty"`
 	IsEnabled   bool     `json:"is_enabled"`
 	CreatedAt   string   `json:"created_at"`
 	UpdatedAt   string   `json:"updated_at"`
@@ -216,7 +218,8 @@ type UserFallbackChainEntryResponse struct {
 // GetUserFallbackChainOutput represents the get fallback chain response.
 type GetUserFallbackChainOutput struct {
 	Body struct {
-		Chain []UserFallbackChainEntryResponse `json:"chain"`
+		Chain         []UserFallbackChainEntryResponse `json:"chain"`
+		RoutingPolicy models.RoutingPolicy             `json:"routing_policy"`
 	}
 }
 
@@ -232,6 +235,11 @@ func (h *UserLLMHandler) GetFallbackChain(ctx context.Context, input *struct{})
 		return nil, huma.Error500InternalServerError("failed to get fallback chain: " + err.Error())
 	}
 
+	policy, err := h.userLLMSvc.GetRoutingPolicy(ctx, claims.UserID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("failed to get routing policy: " + err.Error())
+	}
+
 	responses := make([]UserFallbackChainEntryResponse, 0, len(entries))
 	for _, e := range entries {
 		responses = append(responses, UserFallbackChainEntryResponse{
@@ -241,6 +249,7 @@ func (h *UserLLMHandler) GetFallbackChain(ctx context.Context, input *struct{})
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 			CreatedAt:   e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:   e.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -249,8 +258,9 @@ func (h *UserLLMHandler) GetFallbackChain(ctx context.Context, input *struct{})
 
 	return &GetUserFallbackChainOutput{
 		Body: struct {
-			Chain []UserFallbackChainEntryResponse `json:"chain"`
-		}{Chain: responses},
+			Chain         []UserFallbackChainEntryResponse `json:"chain"`
+			RoutingPolicy models.RoutingPolicy             `json:"routing_policy"`
+		}{Chain: responses, RoutingPolicy: policy},
 	}, nil
 }
 
@@ -260,13 +270,15 @@ type UserFallbackChainEntryInput struct {
 	Model       string   `json:"model" minLength:"1" doc:"Model identifier"`
 	Temperature *float64 `json:"temperature,omitempty" doc:"Temperature setting (0.0-1.0, nil for default)"`
 	MaxTokens   *int     `json:"max_tokens,omitempty" doc:"Max output tokens (nil for default)"`
+	Weight      int      `json:"weight,omitempty" minimum:"0" maximum:"100" doc:"Share of traffic routed to this entry first under the weighted policy (percentage)"`
 	IsEnabled   bool     `json:"is_enabled" doc:"Whether this entry is enabled"`
 }
 
 // SetUserFallbackChainInput represents the set fallback chain request.
 type SetUserFallbackChainInput struct {
 	Body struct {
-		Chain []UserFallbackChainEntryInput `json:"chain" doc:"Ordered list of provider:model pairs"`
+		Chain         []UserFallbackChainEntryInput `json:"chain" doc:"Ordered list of provider:model pairs"`
+		RoutingPolicy string                        `json:"routing_policy,omitempty" enum:"ordered,cheapest_capable,fastest,weighted" doc:"How the chain picks a model: ordered, cheapest_capable, fastest or weighted (omit to keep the current policy)"`
 	}
 }
 
@@ -309,6 +321,7 @@ func (h *UserLLMHandler) SetFallbackChain(ctx context.Context, input *SetUserFal
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 		})
 	}
@@ -317,6 +330,11 @@ func (h *UserLLMHandler) SetFallbackChain(ctx context.Context, input *SetUserFal
 	if err != nil {
 		return nil, huma.Error400BadRequest(err.Error())
 	}
+	if input.Body.RoutingPolicy != "" {
+		if err := h.userLLMSvc.SetRoutingPolicy(ctx, claims.UserID, models.RoutingPolicy(input.Body.RoutingPolicy)); err != nil {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+	}
 
 	responses := make([]UserFallbackChainEntryResponse, 0, len(entries))
 	for _, e := range entries {
@@ -327,6 +345,7 @@ func (h *UserLLMHandler) SetFallbackChain(ctx context.Context, input *SetUserFal
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 			CreatedAt:   e.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 			UpdatedAt:   e.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
@@ -25,14 +25,17 @@ type UserClaims struct {
 	UserID           string   // Clerk user ID (sub claim)
 	Email            string
 	Name             string
-	Tier             string   // From Clerk public_metadata.subscription.tier
-	GlobalSuperadmin bool     // From Clerk public_metadata.global_superadmin
-	Features         []string // From Clerk Commerce "fea" claim
-	Scopes           []string // For API keys
-	IsAPIKey         bool     // True if authenticated via API key
-	LLMProvider      string   // For S3 API keys: forced LLM provider (deprecated, use LLMConfigs)
-	LLMModel         string   // For S3 API keys: forced LLM model (deprecated, use LLMConfigs)
+	Tier             string                   // From Clerk public_metadata.subscription.tier
+	GlobalSuperadmin bool                     // From Clerk public_metadata.global_superadmin
+	Features         []string                 // From Clerk Commerce "fea" claim
+	Scopes           []string                 // For API keys
+	IsAPIKey         bool                     // True if authenticated via API key
+	APIKeyID         string                   // ID of the database API key used (empty for JWT and S3 keys)
+	LLMProvider      string                   // For S3 API keys: forced LLM provider (deprecated, use LLMConfigs)
+	LLMModel         string                   // For S3 API keys: forced LLM model (deprecated, use LLMConfigs)
 	LLMConfigs       []config.APIKeyLLMConfig // For S3 API keys: fallback chain of LLM configs
+	RateLimits       *config.APIKeyRateLimits // For S3 API keys: per-key request limits (nil uses tier limits)
+	RateLimitKey     string                   // For S3 API keys: identifies the key its request limits are counted against
 }
 
 // HasFeature checks if the user has a specific feature.
@@ -89,7 +92,8 @@ func (c *UserClaims) HasAnyFeature(features []string) bool {
 
 // Auth returns an authentication middleware that supports both Clerk JWTs and API keys.
 // If subCache is provided, API key auth will fetch tier/features from Clerk.
-func Auth(clerkVerifier *auth.ClerkVerifier, authSvc *service.AuthService, subCache *auth.SubscriptionCache) func(http.Handler) http.Handler {
+// If lic is provided (self-hosted mode), tier/features come from the license instead.
+func Auth(clerkVerifier *auth.ClerkVerifier, authSvc *service.AuthService, subCache *auth.SubscriptionCache, lic LicenseEntitlements) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Get token from Authorization header
@@ -131,6 +135,7 @@ func Auth(clerkVerifier *auth.ClerkVerifier, authSvc *service.AuthService, subCa
 				http.Error(w, `{"error":"invalid token"}`, http.StatusUnauthorized)
 				return
 			}
+			applyLicense(claims, lic)
 
 			// Add claims to context
 			ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
@@ -139,6 +144,27 @@ func Auth(clerkVerifier *auth.ClerkVerifier, authSvc *service.AuthService, subCa
 	}
 }
 
+// LicenseEntitlements supplies the tier and features granted by a self-hosted license.
+type LicenseEntitlements interface {
+	// Entitlements returns the licensed tier and features; ok is false without an active license.
+	Entitlements() (tier string, features []string, ok bool)
+}
+
+// applyLicense replaces a user's tier and features with those granted by the license.
+// Without an active license, the tier is kept but no features are granted.
+func applyLicense(claims *UserClaims, lic LicenseEntitlements) {
+	if lic == nil {
+		return
+	}
+	tier, features, ok := lic.Entitlements()
+	if !ok {
+		claims.Features = nil
+		return
+	}
+	claims.Tier = tier
+	claims.Features = features
+}
+
 // validateClerkToken validates a Clerk JWT and converts to UserClaims.
 func validateClerkToken(verifier *auth.ClerkVerifier, tokenString string) (*UserClaims, error) {
 	if verifier == nil {
@@ -205,6 +231,7 @@ func validateAPIKey(ctx context.Context, authSvc *service.AuthService, subCache
 		GlobalSuperadmin: tokenClaims.GlobalSuperadmin,
 		Scopes:           tokenClaims.Scopes,
 		IsAPIKey:         true,
+		APIKeyID:         tokenClaims.KeyID,
 	}
 
 	// If we have a subscription cache, hydrate tier/features from Clerk
@@ -293,6 +320,7 @@ func validateS3APIKey(r *http.Request, apiKey string) *UserClaims {
 		IsAPIKey:         true,
 		GlobalSuperadmin: false,
 	}
+	setS3KeyRateLimits(claims, apiKey, keyConfig.RateLimits)
 
 	// Inject LLM config if specified (bypasses fallback chain)
 	if keyConfig.LLMConfig != nil {
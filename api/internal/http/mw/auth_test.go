@@ -2,9 +2,19 @@ package mw
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/jmylchreest/refyne-api/internal/config"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+	"github.com/jmylchreest/refyne-api/internal/service"
 )
 
 // ========================================
@@ -180,6 +190,47 @@ func TestUserClaims_HasAnyFeature(t *testing.T) {
 // GetUserClaims Tests
 // ========================================
 
+// stubLicense implements LicenseEntitlements for testing.
+type stubLicense struct {
+	tier     string
+	features []string
+	ok       bool
+}
+
+func (s stubLicense) Entitlements() (string, []string, bool) {
+	return s.tier, s.features, s.ok
+}
+
+func TestApplyLicense(t *testing.T) {
+	tests := []struct {
+		name         string
+		license      LicenseEntitlements
+		wantTier     string
+		wantFeatures []string
+	}{
+		{"hosted mode keeps claims", nil, "free", []string{"clerk_feature"}},
+		{"active license replaces tier and features", stubLicense{"selfhosted", []string{"webhooks"}, true}, "selfhosted", []string{"webhooks"}},
+		{"inactive license grants no features", stubLicense{ok: false}, "free", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &UserClaims{Tier: "free", Features: []string{"clerk_feature"}}
+			applyLicense(claims, tt.license)
+			if claims.Tier != tt.wantTier {
+				t.Errorf("Tier = %q, want %q", claims.Tier, tt.wantTier)
+			}
+			if len(claims.Features) != len(tt.wantFeatures) {
+				t.Fatalf("Features = %v, want %v", claims.Features, tt.wantFeatures)
+			}
+			for i, f := range tt.wantFeatures {
+				if claims.Features[i] != f {
+					t.Errorf("Features = %v, want %v", claims.Features, tt.wantFeatures)
+				}
+			}
+		})
+	}
+}
+
 func TestGetUserClaims(t *testing.T) {
 	t.Run("claims present", func(t *testing.T) {
 		expected := &UserClaims{
@@ -366,7 +417,7 @@ func TestRequireScope(t *testing.T) {
 // ========================================
 
 func TestAuth_MissingAuthHeader(t *testing.T) {
-	handler := Auth(nil, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(nil, nil, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 
@@ -382,7 +433,7 @@ func TestAuth_MissingAuthHeader(t *testing.T) {
 
 func TestAuth_InvalidToken(t *testing.T) {
 	// Test with a token that's neither a valid API key nor Clerk JWT
-	handler := Auth(nil, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := Auth(nil, nil, nil, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 
@@ -398,6 +449,29 @@ func TestAuth_InvalidToken(t *testing.T) {
 	}
 }
 
+func TestHumaAuth_SelfHostedOperatorCanReplaceLicense(t *testing.T) {
+	operatorKey := "rf_operator_key_12345"
+	hash := sha256.Sum256([]byte(operatorKey))
+	cfg := &config.Config{DeploymentMode: "selfhosted", APIKeyHash: hex.EncodeToString(hash[:])}
+	authSvc := service.NewAuthService(cfg, &repository.Repositories{}, slog.Default())
+
+	_, api := humatest.New(t)
+	api.UseMiddleware(HumaAuth(api, HumaAuthConfig{AuthService: authSvc}))
+	var replacedBy string
+	ProtectedPut(api, "/api/v1/admin/license", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		replacedBy = GetUserClaims(ctx).UserID
+		return nil, nil
+	}, WithSuperadmin())
+
+	resp := api.Put("/api/v1/admin/license", "Authorization: Bearer "+operatorKey, strings.NewReader("{}"))
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", resp.Code, http.StatusNoContent, resp.Body.String())
+	}
+	if replacedBy != service.OperatorUserID {
+		t.Errorf("license replaced by %q, want the operator", replacedBy)
+	}
+}
+
 // Note: Full Auth middleware testing with actual Clerk token validation
 // would require mocking the ClerkVerifier, which depends on external JWKS.
 // The unit tests above cover the middleware logic paths.
@@ -21,6 +21,7 @@ type HumaAuthConfig struct {
 	SubscriptionCache *auth.SubscriptionCache
 	UsageService      *service.UsageService
 	JobService        JobService
+	License           LicenseEntitlements // Self-hosted only: tier/features come from the license
 }
 
 // SecurityScheme is the name of the security scheme used in OpenAPI.
@@ -103,6 +104,7 @@ func HumaAuth(api huma.API, cfg HumaAuthConfig) func(ctx huma.Context, next func
 			huma.WriteErr(api, ctx, http.StatusUnauthorized, "invalid token")
 			return
 		}
+		applyLicense(claims, cfg.License)
 
 		// Check superadmin requirement
 		if requiresSuperadmin(op) {
@@ -313,19 +315,6 @@ func writeConcurrencyError(api huma.API, ctx huma.Context, claims *UserClaims, l
 		fmt.Errorf("limit: %d, active: %d, tier: %s", limits.MaxConcurrentJobs, active, claims.Tier))
 }
 
-// HumaRateLimit returns a Huma middleware that applies user-based rate limiting.
-// Rate limiting is only applied to authenticated requests with user claims.
-// This is a passthrough that delegates to the Chi rate limiter via the
-// underlying request since httprate works with Chi's http.Request.
-func HumaRateLimit(_ RateLimitConfig) func(ctx huma.Context, next func(huma.Context)) {
-	// Rate limiting is handled by the Chi middleware layer (RateLimitByUser)
-	// for operations that need it. This Huma middleware is a passthrough
-	// that could be extended for more granular Huma-specific rate limiting.
-	return func(ctx huma.Context, next func(huma.Context)) {
-		next(ctx)
-	}
-}
-
 // validateS3APIKeyHuma validates an S3-configured API key and its restrictions for Huma contexts.
 // Returns nil if the key is not found in S3 config or restrictions fail.
 func validateS3APIKeyHuma(ctx huma.Context, apiKey string) *UserClaims {
@@ -379,6 +368,7 @@ func validateS3APIKeyHuma(ctx huma.Context, apiKey string) *UserClaims {
 		IsAPIKey:         true,
 		GlobalSuperadmin: false,
 	}
+	setS3KeyRateLimits(claims, apiKey, keyConfig.RateLimits)
 
 	// Inject LLM config if specified (bypasses fallback chain)
 	if keyConfig.LLMConfig != nil {
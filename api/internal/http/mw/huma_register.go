@@ -85,6 +85,13 @@ func WithHidden() OperationOption {
 	}
 }
 
+// WithMaxBodyBytes overrides the default 1MB request body limit.
+func WithMaxBodyBytes(n int64) OperationOption {
+	return func(op *huma.Operation) {
+		op.MaxBodyBytes = n
+	}
+}
+
 // PublicGet registers a public GET endpoint (no auth required).
 func PublicGet[I, O any](api huma.API, path string, handler func(ctx context.Context, input *I) (*O, error), opts ...OperationOption) {
 	op := huma.Operation{
@@ -0,0 +1,62 @@
+package mw
+
+import (
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/jmylchreest/refyne-api/internal/observability"
+)
+
+// HumaObservability returns Huma middleware that records request latency per operation
+// and starts a server span for each request, continuing any incoming W3C trace context.
+// Register it first so the span covers auth and rate limiting.
+func HumaObservability() func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		start := time.Now()
+
+		op := ctx.Operation()
+		operation := "unknown"
+		route := ""
+		if op != nil {
+			operation = op.OperationID
+			route = op.Path
+		}
+
+		spanCtx, span := observability.StartServerSpan(ctx.Context(), operation, humaHeaderCarrier{ctx},
+			attribute.String("http.request.method", ctx.Method()),
+			attribute.String("http.route", route),
+		)
+		defer span.End()
+
+		next(huma.WithContext(ctx, spanCtx))
+
+		status := ctx.Status()
+		if status == 0 {
+			status = 200
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		observability.ObserveHTTPRequest(operation, ctx.Method(), status, time.Since(start))
+	}
+}
+
+// humaHeaderCarrier adapts request headers for trace context extraction.
+type humaHeaderCarrier struct {
+	ctx huma.Context
+}
+
+func (c humaHeaderCarrier) Get(key string) string {
+	return c.ctx.Header(key)
+}
+
+// Set is a no-op; request headers are only read.
+func (c humaHeaderCarrier) Set(key, value string) {}
+
+func (c humaHeaderCarrier) Keys() []string {
+	var keys []string
+	c.ctx.EachHeader(func(name, value string) {
+		keys = append(keys, name)
+	})
+	return keys
+}
@@ -0,0 +1,62 @@
+package mw
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jmylchreest/refyne-api/internal/observability"
+)
+
+func TestHumaObservability(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(HumaObservability())
+
+	var gotTraceID string
+	huma.Register(api, huma.Operation{
+		OperationID: "observability-test",
+		Method:      http.MethodGet,
+		Path:        "/observed",
+	}, func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		gotTraceID = trace.SpanContextFromContext(ctx).TraceID().String()
+		return nil, huma.Error404NotFound("missing")
+	})
+
+	resp := api.Get("/observed", "traceparent: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", resp.Code, http.StatusNotFound)
+	}
+
+	// The handler continues the caller's trace
+	if gotTraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %q, want the incoming trace", gotTraceID)
+	}
+
+	// Latency is recorded against the operation ID and response status
+	families, err := observability.Registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	var count uint64
+	for _, mf := range families {
+		if mf.GetName() != "refyne_http_request_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["operation"] == "observability-test" && labels["method"] == http.MethodGet && labels["status"] == "404" {
+				count = m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("observations for observability-test GET 404 = %d, want 1", count)
+	}
+}
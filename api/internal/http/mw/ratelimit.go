@@ -1,10 +1,21 @@
 package mw
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/danielgtaylor/huma/v2"
 	"github.com/go-chi/httprate"
+	"github.com/jmylchreest/refyne-api/internal/config"
 	"github.com/jmylchreest/refyne-api/internal/constants"
 )
 
@@ -15,6 +26,18 @@ type RateLimitConfig struct {
 	TierLimits map[string]int
 	// IPRequestsPerMinute is a fallback rate limit by IP for unauthenticated requests
 	IPRequestsPerMinute int
+	// Store persists the sliding window request counts used by HumaRateLimit.
+	// Without a store, HumaRateLimit doesn't limit requests.
+	Store RequestCountStore
+}
+
+// RequestCountStore persists sliding window request counts so limits survive restarts
+// and are shared between instances. Implemented by repository.RateLimitRepository.
+type RequestCountStore interface {
+	// CountRequest counts a request in every window and returns the current and previous
+	// bucket counts including it, atomically. The request is kept only if admit accepts.
+	CountRequest(ctx context.Context, key string, windows []time.Duration, now time.Time, admit func(current, previous []int) bool) (current, previous []int, err error)
+	CleanupExpired(ctx context.Context) (int64, error)
 }
 
 // DefaultRateLimitConfig returns defaults from the constants package.
@@ -111,3 +134,242 @@ func RateLimitGlobal(requestsPerMinute int) func(http.Handler) http.Handler {
 		}),
 	)
 }
+
+// rateLimitCleanupInterval is how often HumaRateLimit removes stale request counts.
+const rateLimitCleanupInterval = time.Hour
+
+// rateLimitWindow is one window of a multi-window request limit.
+type rateLimitWindow struct {
+	Name   string // minute, hour or day
+	Period time.Duration
+	Limit  int
+}
+
+// rateLimitWindows returns the windows with a limit set (0 = unlimited).
+func rateLimitWindows(perMinute, perHour, perDay int) []rateLimitWindow {
+	var windows []rateLimitWindow
+	for _, w := range []rateLimitWindow{
+		{Name: "minute", Period: time.Minute, Limit: perMinute},
+		{Name: "hour", Period: time.Hour, Limit: perHour},
+		{Name: "day", Period: 24 * time.Hour, Limit: perDay},
+	} {
+		if w.Limit > 0 {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+// setS3KeyRateLimits applies an S3-configured API key's request limits to its claims.
+// Keys without limits fall back to the limits of their identity's tier.
+func setS3KeyRateLimits(claims *UserClaims, apiKey string, limits config.APIKeyRateLimits) {
+	if !limits.IsSet() {
+		return
+	}
+	h := sha256.Sum256([]byte(apiKey))
+	claims.RateLimits = &limits
+	claims.RateLimitKey = "apikey:" + hex.EncodeToString(h[:])
+}
+
+// requestLimits returns the key requests are counted against and the windows that apply.
+// S3-configured API keys with their own limits are limited per key; everyone else per user,
+// using their tier's limits.
+func requestLimits(ctx context.Context, claims *UserClaims) (string, []rateLimitWindow) {
+	if claims.RateLimits != nil && claims.RateLimitKey != "" {
+		return claims.RateLimitKey, rateLimitWindows(
+			claims.RateLimits.RequestsPerMinute,
+			claims.RateLimits.RequestsPerHour,
+			claims.RateLimits.RequestsPerDay,
+		)
+	}
+
+	limits, ok := ctx.Value(TierLimitsKey).(TierLimits)
+	if !ok {
+		limits = GetTierLimits(ctx, claims.Tier)
+	}
+	return "user:" + claims.UserID, rateLimitWindows(limits.RequestsPerMinute, limits.RequestsPerHour, limits.RequestsPerDay)
+}
+
+// rateLimitDecision is the outcome of checking a request against its windows.
+type rateLimitDecision struct {
+	Allowed    bool
+	Window     rateLimitWindow // The exceeded window, or the one with the fewest requests remaining
+	Remaining  int
+	Reset      time.Duration // Until the window's current bucket ends
+	RetryAfter time.Duration // Until the exceeded window admits another request
+	Policy     string        // RateLimit-Policy value describing every window
+}
+
+// requestLimiter enforces sliding window request limits over a RequestCountStore.
+// Each window keeps a count for its current and previous fixed bucket; the previous
+// bucket's count is weighted by how much of it the sliding window still overlaps.
+type requestLimiter struct {
+	store       RequestCountStore
+	now         func() time.Time
+	lastCleanup atomic.Int64
+}
+
+// slidingCount estimates the requests in the sliding window ending elapsed into the current bucket.
+func slidingCount(current, previous int, elapsed, period time.Duration) float64 {
+	return float64(previous)*(1-float64(elapsed)/float64(period)) + float64(current)
+}
+
+// retryAfter returns how long until a window that is full admits another request.
+func retryAfter(w rateLimitWindow, current, previous int, elapsed time.Duration) time.Duration {
+	limit := float64(w.Limit)
+	var wait time.Duration
+	if float64(current)+1 > limit {
+		// The current bucket alone is full: wait for the next bucket, where this
+		// bucket's count becomes the previous one and decays
+		fraction := 1 - (limit-1)/float64(current)
+		wait = w.Period - elapsed + time.Duration(fraction*float64(w.Period))
+	} else {
+		// Wait for the previous bucket's weight to decay enough
+		fraction := 1 - (limit-1-float64(current))/float64(previous)
+		wait = time.Duration(fraction*float64(w.Period)) - elapsed
+	}
+	return max(wait, time.Second)
+}
+
+// allow counts a request against every window and keeps it counted if all of them
+// admit it. The store counts and decides in one step, so concurrent requests can't
+// all pass a check made before any of them was counted.
+func (l *requestLimiter) allow(ctx context.Context, key string, windows []rateLimitWindow) (rateLimitDecision, error) {
+	now := l.now()
+	l.maybeCleanup(now)
+
+	periods := make([]time.Duration, len(windows))
+	for i, w := range windows {
+		periods[i] = w.Period
+	}
+
+	var decision rateLimitDecision
+	_, _, err := l.store.CountRequest(ctx, key, periods, now, func(current, previous []int) bool {
+		decision = decide(windows, current, previous, now)
+		return decision.Allowed
+	})
+	if err != nil {
+		return rateLimitDecision{}, err
+	}
+	return decision, nil
+}
+
+// decide checks each window's counts, which include the request being decided.
+func decide(windows []rateLimitWindow, current, previous []int, now time.Time) rateLimitDecision {
+	decision := rateLimitDecision{Allowed: true, Remaining: -1, Policy: rateLimitPolicy(windows)}
+	for i, w := range windows {
+		elapsed := now.Sub(now.Truncate(w.Period))
+		count := slidingCount(current[i], previous[i], elapsed, w.Period)
+		if count > float64(w.Limit) {
+			decision.Allowed = false
+			decision.Window = w
+			decision.Remaining = 0
+			decision.Reset = w.Period - elapsed
+			decision.RetryAfter = retryAfter(w, current[i]-1, previous[i], elapsed)
+			return decision
+		}
+
+		remaining := int(float64(w.Limit) - count)
+		if decision.Remaining == -1 || remaining < decision.Remaining {
+			decision.Window = w
+			decision.Remaining = remaining
+			decision.Reset = w.Period - elapsed
+		}
+	}
+	return decision
+}
+
+// maybeCleanup removes stale request counts in the background, at most once per interval.
+func (l *requestLimiter) maybeCleanup(now time.Time) {
+	last := l.lastCleanup.Load()
+	if now.Unix()-last < int64(rateLimitCleanupInterval.Seconds()) || !l.lastCleanup.CompareAndSwap(last, now.Unix()) {
+		return
+	}
+	go func() {
+		if _, err := l.store.CleanupExpired(context.Background()); err != nil {
+			slog.Warn("failed to clean up rate limit counters", "error", err)
+		}
+	}()
+}
+
+// rateLimitPolicy formats windows as a RateLimit-Policy header value, e.g. "60;w=60, 1000;w=3600".
+func rateLimitPolicy(windows []rateLimitWindow) string {
+	policies := make([]string, 0, len(windows))
+	for _, w := range windows {
+		policies = append(policies, fmt.Sprintf("%d;w=%d", w.Limit, int(w.Period.Seconds())))
+	}
+	return strings.Join(policies, ", ")
+}
+
+// ceilSeconds rounds a duration up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// HumaRateLimit returns a Huma middleware that enforces per-minute, per-hour and per-day
+// request limits on authenticated requests, using sliding windows persisted in cfg.Store.
+// S3-configured API keys with rate limits are limited per key; other callers per user by tier.
+// Responses carry RateLimit-* headers; rejected requests get a 429 with Retry-After.
+// Unauthenticated requests are left to the router's IP limit. If the store fails, requests are allowed.
+func HumaRateLimit(api huma.API, cfg RateLimitConfig) func(ctx huma.Context, next func(huma.Context)) {
+	if cfg.Store == nil {
+		return func(ctx huma.Context, next func(huma.Context)) {
+			next(ctx)
+		}
+	}
+
+	limiter := &requestLimiter{store: cfg.Store, now: time.Now}
+
+	return func(ctx huma.Context, next func(huma.Context)) {
+		claims := GetUserClaims(ctx.Context())
+		if claims == nil || claims.UserID == "" {
+			next(ctx)
+			return
+		}
+
+		key, windows := requestLimits(ctx.Context(), claims)
+		if len(windows) == 0 {
+			next(ctx)
+			return
+		}
+
+		decision, err := limiter.allow(ctx.Context(), key, windows)
+		if err != nil {
+			slog.Warn("rate limit check failed, allowing request", "user_id", claims.UserID, "error", err)
+			next(ctx)
+			return
+		}
+
+		ctx.SetHeader("RateLimit-Policy", decision.Policy)
+		ctx.SetHeader("RateLimit-Limit", strconv.Itoa(decision.Window.Limit))
+		ctx.SetHeader("RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+		ctx.SetHeader("RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+
+		if !decision.Allowed {
+			writeRateLimitError(api, ctx, claims, decision, limiter.now())
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+// writeRateLimitError writes a 429 saying which limit was hit and when to retry.
+func writeRateLimitError(api huma.API, ctx huma.Context, claims *UserClaims, decision rateLimitDecision, now time.Time) {
+	retrySeconds := ceilSeconds(decision.RetryAfter)
+	retryAt := now.Add(time.Duration(retrySeconds) * time.Second).UTC()
+	ctx.SetHeader("Retry-After", strconv.Itoa(retrySeconds))
+
+	slog.Info("request rate limit exceeded",
+		"user_id", claims.UserID,
+		"window", decision.Window.Name,
+		"limit", decision.Window.Limit,
+		"retry_after_seconds", retrySeconds,
+	)
+
+	huma.WriteErr(api, ctx, http.StatusTooManyRequests,
+		fmt.Sprintf("rate limit exceeded: %d requests per %s. Retry after %d seconds (at %s)",
+			decision.Window.Limit, decision.Window.Name, retrySeconds, retryAt.Format(time.RFC3339)),
+		fmt.Errorf("limit: %d, window: %s, retry_after_seconds: %d, retry_at: %s",
+			decision.Window.Limit, decision.Window.Name, retrySeconds, retryAt.Format(time.RFC3339)))
+}
@@ -2,10 +2,19 @@ package mw
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/humatest"
+
+	"github.com/jmylchreest/refyne-api/internal/config"
 	"github.com/jmylchreest/refyne-api/internal/constants"
 )
 
@@ -216,6 +225,248 @@ func TestRateLimitGlobal(t *testing.T) {
 	}
 }
 
-// Note: Full rate limiting tests would require simulating many requests
+// Note: Full RateLimitByUser tests would require simulating many requests
 // within a short time window and checking for 429 responses.
 // These tests verify the middleware construction and basic pass-through behavior.
+
+// ========================================
+// HumaRateLimit Tests
+// ========================================
+
+// memoryRequestCountStore is an in-memory RequestCountStore for testing.
+type memoryRequestCountStore struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newMemoryRequestCountStore() *memoryRequestCountStore {
+	return &memoryRequestCountStore{counts: make(map[string]int)}
+}
+
+func (s *memoryRequestCountStore) bucketKey(key string, window time.Duration, start time.Time) string {
+	return fmt.Sprintf("%s|%s|%d", key, window, start.Unix())
+}
+
+func (s *memoryRequestCountStore) CountRequest(_ context.Context, key string, windows []time.Duration, now time.Time, admit func(current, previous []int) bool) ([]int, []int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := make([]int, len(windows))
+	previous := make([]int, len(windows))
+	for i, window := range windows {
+		start := now.Truncate(window)
+		current[i] = s.counts[s.bucketKey(key, window, start)] + 1
+		previous[i] = s.counts[s.bucketKey(key, window, start.Add(-window))]
+	}
+	if admit(current, previous) {
+		for i, window := range windows {
+			s.counts[s.bucketKey(key, window, now.Truncate(window))] = current[i]
+		}
+	}
+	return current, previous, nil
+}
+
+// count returns the requests counted for key in the bucket of window containing at.
+func (s *memoryRequestCountStore) count(key string, window time.Duration, at time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[s.bucketKey(key, window, at.Truncate(window))]
+}
+
+func (s *memoryRequestCountStore) CleanupExpired(_ context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestRequestLimiter_SlidingWindow(t *testing.T) {
+	store := newMemoryRequestCountStore()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	limiter := &requestLimiter{store: store, now: func() time.Time { return now }}
+	windows := rateLimitWindows(3, 0, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		decision, err := limiter.allow(ctx, "user:1", windows)
+		if err != nil {
+			t.Fatalf("allow() error = %v", err)
+		}
+		if !decision.Allowed {
+			t.Fatalf("request %d rejected, want allowed", i+1)
+		}
+		if decision.Remaining != 2-i {
+			t.Errorf("request %d remaining = %d, want %d", i+1, decision.Remaining, 2-i)
+		}
+	}
+
+	decision, err := limiter.allow(ctx, "user:1", windows)
+	if err != nil {
+		t.Fatalf("allow() error = %v", err)
+	}
+	if decision.Allowed {
+		t.Fatal("4th request allowed, want rejected")
+	}
+	if decision.Window.Name != "minute" {
+		t.Errorf("exceeded window = %q, want minute", decision.Window.Name)
+	}
+
+	// Other keys are counted separately
+	if decision, _ := limiter.allow(ctx, "user:2", windows); !decision.Allowed {
+		t.Error("other key rejected, want allowed")
+	}
+
+	// Halfway into the next minute the previous minute's 3 requests weigh 1.5,
+	// so one more request fits but a second doesn't
+	now = now.Add(90 * time.Second)
+	if decision, _ := limiter.allow(ctx, "user:1", windows); !decision.Allowed {
+		t.Error("request after window slid rejected, want allowed")
+	}
+	decision, _ = limiter.allow(ctx, "user:1", windows)
+	if decision.Allowed {
+		t.Fatal("request over sliding limit allowed, want rejected")
+	}
+	// 3*(1-f) + 1 + 1 <= 3 once f >= 2/3, i.e. 40s into the minute: 10s from now
+	if decision.RetryAfter != 10*time.Second {
+		t.Errorf("RetryAfter = %v, want 10s", decision.RetryAfter)
+	}
+}
+
+func TestRequestLimiter_MostRestrictiveWindow(t *testing.T) {
+	store := newMemoryRequestCountStore()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	limiter := &requestLimiter{store: store, now: func() time.Time { return now }}
+	windows := rateLimitWindows(10, 2, 100)
+	ctx := context.Background()
+
+	decision, _ := limiter.allow(ctx, "user:1", windows)
+	if !decision.Allowed || decision.Window.Name != "hour" || decision.Remaining != 1 {
+		t.Errorf("decision = %+v, want allowed with 1 remaining in the hour window", decision)
+	}
+	if decision.Policy != "10;w=60, 2;w=3600, 100;w=86400" {
+		t.Errorf("Policy = %q", decision.Policy)
+	}
+
+	limiter.allow(ctx, "user:1", windows)
+	now = now.Add(5 * time.Minute)
+	decision, _ = limiter.allow(ctx, "user:1", windows)
+	if decision.Allowed || decision.Window.Name != "hour" {
+		t.Fatalf("decision = %+v, want rejected by the hour window", decision)
+	}
+	if decision.RetryAfter != 55*time.Minute+30*time.Minute {
+		t.Errorf("RetryAfter = %v, want 1h25m", decision.RetryAfter)
+	}
+
+	// Rejected requests aren't counted
+	if current := store.count("user:1", time.Minute, now.Add(-5*time.Minute)); current != 2 {
+		t.Errorf("minute count = %d, want 2", current)
+	}
+}
+
+func TestRequestLimiter_Concurrent(t *testing.T) {
+	store := newMemoryRequestCountStore()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	limiter := &requestLimiter{store: store, now: func() time.Time { return now }}
+	windows := rateLimitWindows(10, 0, 0)
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decision, err := limiter.allow(context.Background(), "user:1", windows)
+			if err != nil {
+				t.Errorf("allow() error = %v", err)
+				return
+			}
+			if decision.Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != 10 {
+		t.Errorf("allowed %d of 50 concurrent requests, want exactly 10", got)
+	}
+	if got := store.count("user:1", time.Minute, now); got != 10 {
+		t.Errorf("counted %d requests, want 10", got)
+	}
+}
+
+func TestRequestLimits(t *testing.T) {
+	ctx := context.WithValue(context.Background(), TierLimitsKey, TierLimits{RequestsPerMinute: 60, RequestsPerDay: 5000})
+
+	key, windows := requestLimits(ctx, &UserClaims{UserID: "user-1", Tier: "standard"})
+	if key != "user:user-1" {
+		t.Errorf("key = %q, want user:user-1", key)
+	}
+	if len(windows) != 2 || windows[0].Limit != 60 || windows[1].Name != "day" || windows[1].Limit != 5000 {
+		t.Errorf("windows = %+v, want 60/minute and 5000/day", windows)
+	}
+
+	// S3 keys with their own limits are limited per key
+	claims := &UserClaims{UserID: "partner", Tier: "standard"}
+	setS3KeyRateLimits(claims, "rfs_secret", config.APIKeyRateLimits{RequestsPerHour: 500})
+	key, windows = requestLimits(ctx, claims)
+	if !strings.HasPrefix(key, "apikey:") || strings.Contains(key, "rfs_secret") {
+		t.Errorf("key = %q, want hashed apikey key", key)
+	}
+	if len(windows) != 1 || windows[0].Name != "hour" || windows[0].Limit != 500 {
+		t.Errorf("windows = %+v, want 500/hour", windows)
+	}
+
+	// S3 keys without limits use their tier's limits
+	claims = &UserClaims{UserID: "partner", Tier: "standard"}
+	setS3KeyRateLimits(claims, "rfs_secret", config.APIKeyRateLimits{})
+	if key, _ := requestLimits(ctx, claims); key != "user:partner" {
+		t.Errorf("key = %q, want user:partner", key)
+	}
+}
+
+func TestHumaRateLimit(t *testing.T) {
+	_, api := humatest.New(t)
+	claims := &UserClaims{UserID: "user-1", Tier: "free"}
+	api.UseMiddleware(func(ctx huma.Context, next func(huma.Context)) {
+		newCtx := context.WithValue(ctx.Context(), UserClaimsKey, claims)
+		newCtx = context.WithValue(newCtx, TierLimitsKey, TierLimits{RequestsPerMinute: 2})
+		next(huma.WithContext(ctx, newCtx))
+	})
+	api.UseMiddleware(HumaRateLimit(api, RateLimitConfig{Store: newMemoryRequestCountStore()}))
+	huma.Get(api, "/test", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		resp := api.Get("/test")
+		if resp.Code != http.StatusNoContent {
+			t.Fatalf("request %d status = %d, want %d", i+1, resp.Code, http.StatusNoContent)
+		}
+		if got := resp.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("RateLimit-Limit = %q, want 2", got)
+		}
+		if got := resp.Header().Get("RateLimit-Remaining"); got != fmt.Sprint(1-i) {
+			t.Errorf("RateLimit-Remaining = %q, want %d", got, 1-i)
+		}
+	}
+
+	resp := api.Get("/test")
+	if resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", resp.Code, http.StatusTooManyRequests)
+	}
+	if resp.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+	if body := resp.Body.String(); !strings.Contains(body, "2 requests per minute") || !strings.Contains(body, "Retry after") {
+		t.Errorf("body = %s, want limit and retry time", body)
+	}
+}
+
+func TestHumaRateLimit_NoStore(t *testing.T) {
+	_, api := humatest.New(t)
+	api.UseMiddleware(HumaRateLimit(api, RateLimitConfig{}))
+	huma.Get(api, "/test", func(ctx context.Context, input *struct{}) (*struct{}, error) {
+		return nil, nil
+	})
+
+	if resp := api.Get("/test"); resp.Code != http.StatusNoContent || resp.Header().Get("RateLimit-Limit") != "" {
+		t.Errorf("status = %d, headers = %v; want passthrough", resp.Code, resp.Header())
+	}
+}
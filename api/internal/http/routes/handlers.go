@@ -20,6 +20,7 @@ type JobHandlers interface {
 	GetJobWebhookDeliveries(ctx context.Context, input *handlers.GetJobWebhookDeliveriesInput) (*handlers.GetJobWebhookDeliveriesOutput, error)
 	GetJobDebugCapture(ctx context.Context, input *handlers.GetJobDebugCaptureInput) (*handlers.GetJobDebugCaptureOutput, error)
 	DownloadJobDebugCapture(ctx context.Context, input *handlers.DownloadJobDebugCaptureInput) (*handlers.DownloadJobDebugCaptureOutput, error)
+	GetJobDebugCaptureHAR(ctx context.Context, input *handlers.GetJobDebugCaptureHARInput) (*handlers.GetJobDebugCaptureHAROutput, error)
 	// RegisterRawEndpoints registers SSE/multi-format endpoints for OpenAPI documentation.
 	RegisterRawEndpoints(api huma.API)
 }
@@ -74,6 +75,44 @@ type SavedSitesHandlers interface {
 	DeleteSavedSite(ctx context.Context, input *handlers.DeleteSavedSiteInput) (*handlers.DeleteSavedSiteOutput, error)
 }
 
+// SpendCapHandlers defines the interface for spend cap operations.
+type SpendCapHandlers interface {
+	ListSpendCaps(ctx context.Context, input *struct{}) (*handlers.ListSpendCapsOutput, error)
+	SetSpendCap(ctx context.Context, input *handlers.SetSpendCapInput) (*handlers.SetSpendCapOutput, error)
+	DeleteSpendCap(ctx context.Context, input *handlers.DeleteSpendCapInput) (*handlers.DeleteSpendCapOutput, error)
+}
+
+// ProxyHandlers defines the interface for proxy operations.
+type ProxyHandlers interface {
+	ListProxies(ctx context.Context, input *struct{}) (*handlers.ListProxiesOutput, error)
+	CreateProxy(ctx context.Context, input *handlers.CreateProxyInput) (*handlers.ProxyOutput, error)
+	UpdateProxy(ctx context.Context, input *handlers.UpdateProxyInput) (*handlers.ProxyOutput, error)
+	DeleteProxy(ctx context.Context, input *handlers.ProxyIDInput) (*handlers.DeleteProxyOutput, error)
+	GetProxyHealth(ctx context.Context, input *handlers.ProxyIDInput) (*handlers.ProxyHealthOutput, error)
+	// Admin only
+	AdminListProxies(ctx context.Context, input *struct{}) (*handlers.ListProxiesOutput, error)
+	AdminCreateProxy(ctx context.Context, input *handlers.CreateProxyInput) (*handlers.ProxyOutput, error)
+	AdminUpdateProxy(ctx context.Context, input *handlers.UpdateProxyInput) (*handlers.ProxyOutput, error)
+	AdminDeleteProxy(ctx context.Context, input *handlers.ProxyIDInput) (*handlers.DeleteProxyOutput, error)
+	AdminGetProxyHealth(ctx context.Context, input *handlers.ProxyIDInput) (*handlers.ProxyHealthOutput, error)
+}
+
+// AuthProfileHandlers defines the interface for auth profile operations.
+type AuthProfileHandlers interface {
+	ListAuthProfiles(ctx context.Context, input *struct{}) (*handlers.ListAuthProfilesOutput, error)
+	CreateAuthProfile(ctx context.Context, input *handlers.CreateAuthProfileInput) (*handlers.AuthProfileOutput, error)
+	UpdateAuthProfile(ctx context.Context, input *handlers.UpdateAuthProfileInput) (*handlers.AuthProfileOutput, error)
+	DeleteAuthProfile(ctx context.Context, input *handlers.AuthProfileIDInput) (*handlers.DeleteAuthProfileOutput, error)
+	LoginAuthProfile(ctx context.Context, input *handlers.AuthProfileIDInput) (*handlers.AuthProfileOutput, error)
+}
+
+// BillingHandlers defines the interface for credit balance and statement operations.
+type BillingHandlers interface {
+	GetBalance(ctx context.Context, input *struct{}) (*handlers.GetBalanceOutput, error)
+	ListTransactions(ctx context.Context, input *handlers.ListTransactionsInput) (*handlers.ListTransactionsOutput, error)
+	GetStatement(ctx context.Context, input *handlers.GetStatementInput) (*handlers.GetStatementOutput, error)
+}
+
 // WebhookHandlers defines the interface for webhook operations.
 type WebhookHandlers interface {
 	ListWebhooks(ctx context.Context, input *struct{}) (*handlers.ListWebhooksOutput, error)
@@ -92,6 +131,7 @@ type AnalyzeHandlers interface {
 // ExtractionHandlers defines the interface for extraction operations.
 type ExtractionHandlers interface {
 	Extract(ctx context.Context, input *handlers.ExtractInput) (*handlers.ExtractOutput, error)
+	ExtractUpload(ctx context.Context, input *handlers.ExtractUploadInput) (*handlers.ExtractOutput, error)
 }
 
 // AdminHandlers defines the interface for admin operations.
@@ -107,6 +147,8 @@ type AdminHandlers interface {
 	ListTiers(ctx context.Context, input *struct{}) (*handlers.ListTiersOutput, error)
 	ValidateTiers(ctx context.Context, input *handlers.ValidateTiersInput) (*handlers.ValidateTiersOutput, error)
 	SyncTiers(ctx context.Context, input *struct{}) (*handlers.SyncTiersOutput, error)
+	ListProviderHealth(ctx context.Context, input *struct{}) (*handlers.ListProviderHealthOutput, error)
+	ResetProviderHealth(ctx context.Context, input *handlers.ResetProviderHealthInput) (*handlers.ResetProviderHealthOutput, error)
 }
 
 // AdminAnalyticsHandlers defines the interface for admin analytics operations.
@@ -119,6 +161,13 @@ type AdminAnalyticsHandlers interface {
 	GetJobResults(ctx context.Context, input *handlers.AdminJobResultsInput) (*handlers.AdminJobResultsOutput, error)
 }
 
+// LicenseHandlers defines the interface for self-hosted license administration.
+// This is only used in self-hosted mode.
+type LicenseHandlers interface {
+	GetLicense(ctx context.Context, input *struct{}) (*handlers.LicenseOutput, error)
+	ReplaceLicense(ctx context.Context, input *handlers.ReplaceLicenseInput) (*handlers.LicenseOutput, error)
+}
+
 // MetricsHandlers defines the interface for internal metrics operations.
 // These endpoints are superadmin-only and hidden from public documentation.
 type MetricsHandlers interface {
@@ -142,7 +191,11 @@ type Handlers struct {
 	Job            JobHandlers
 	Crawl          CrawlHandlers
 	Usage          UsageHandlers
+	SpendCap       SpendCapHandlers
+	Billing        BillingHandlers
 	UserLLM        UserLLMHandlers
+	Proxy          ProxyHandlers
+	AuthProfile    AuthProfileHandlers
 	APIKey         APIKeyHandlers // May be nil in self-hosted mode
 	SchemaCatalog  SchemaCatalogHandlers
 	SavedSites     SavedSitesHandlers
@@ -151,6 +204,7 @@ type Handlers struct {
 	Extraction     ExtractionHandlers
 	Admin          AdminHandlers
 	AdminAnalytics AdminAnalyticsHandlers
+	License        LicenseHandlers // May be nil in hosted mode
 	Metrics        MetricsHandlers
 }
 
@@ -159,3 +213,9 @@ type Handlers struct {
 func (h *Handlers) IncludeAPIKeys() bool {
 	return h.APIKey != nil
 }
+
+// IncludeLicense returns true if license admin endpoints should be registered.
+// Licenses only apply in self-hosted mode.
+func (h *Handlers) IncludeLicense() bool {
+	return h.License != nil
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"github.com/danielgtaylor/huma/v2"
 
+	"github.com/jmylchreest/refyne-api/internal/constants"
 	"github.com/jmylchreest/refyne-api/internal/http/mw"
 )
 
@@ -66,6 +67,11 @@ func Register(api huma.API, h *Handlers) {
 		mw.WithSummary("Download debug capture file"),
 		mw.WithDescription("Returns a signed URL to download the raw debug capture JSON file for sharing or offline analysis"),
 		mw.WithOperationID("downloadJobDebugCapture"))
+	mw.ProtectedGet(api, "/api/v1/jobs/{id}/debug-capture/har", h.Job.GetJobDebugCaptureHAR,
+		mw.WithTags("Jobs"),
+		mw.WithSummary("Download captured network responses as HAR"),
+		mw.WithDescription("Returns the XHR/fetch responses captured while rendering the job's pages (capture_network) as a HAR file"),
+		mw.WithOperationID("getJobDebugCaptureHAR"))
 
 	// Raw HTTP handlers for format-aware responses (non-JSON content types)
 	// RegisterRawEndpoints adds them to OpenAPI with proper security requirements.
@@ -77,6 +83,35 @@ func Register(api huma.API, h *Handlers) {
 		mw.WithSummary("Get usage statistics"),
 		mw.WithOperationID("getUsage"))
 
+	// --- Spend Caps ---
+	mw.ProtectedGet(api, "/api/v1/spend-caps", h.SpendCap.ListSpendCaps,
+		mw.WithTags("Usage"),
+		mw.WithSummary("List spend caps"),
+		mw.WithOperationID("listSpendCaps"))
+	mw.ProtectedPut(api, "/api/v1/spend-caps", h.SpendCap.SetSpendCap,
+		mw.WithTags("Usage"),
+		mw.WithSummary("Create or replace spend cap"),
+		mw.WithOperationID("setSpendCap"))
+	mw.ProtectedDelete(api, "/api/v1/spend-caps/{id}", h.SpendCap.DeleteSpendCap,
+		mw.WithTags("Usage"),
+		mw.WithSummary("Delete spend cap"),
+		mw.WithOperationID("deleteSpendCap"))
+
+	// --- Billing ---
+	mw.ProtectedGet(api, "/api/v1/billing/balance", h.Billing.GetBalance,
+		mw.WithTags("Usage"),
+		mw.WithSummary("Get credit balance"),
+		mw.WithOperationID("getBalance"))
+	mw.ProtectedGet(api, "/api/v1/billing/transactions", h.Billing.ListTransactions,
+		mw.WithTags("Usage"),
+		mw.WithSummary("List credit transactions"),
+		mw.WithOperationID("listTransactions"))
+	mw.ProtectedGet(api, "/api/v1/billing/statements/{period}", h.Billing.GetStatement,
+		mw.WithTags("Usage"),
+		mw.WithSummary("Get billing statement"),
+		mw.WithDescription("Itemizes every job in a calendar month (YYYY-MM) or the current subscription period with its tokens, LLM cost, markup and per-transaction fee. Use format=csv to download the statement as CSV."),
+		mw.WithOperationID("getStatement"))
+
 	// --- Configuration ---
 	mw.ProtectedGet(api, "/api/v1/cleaners", h.ListCleaners,
 		mw.WithTags("Configuration"),
@@ -118,6 +153,54 @@ func Register(api huma.API, h *Handlers) {
 		mw.WithSummary("Set LLM fallback chain"),
 		mw.WithOperationID("setLlmChain"))
 
+	// --- Proxies ---
+	mw.ProtectedGet(api, "/api/v1/proxies", h.Proxy.ListProxies,
+		mw.WithTags("Proxies"),
+		mw.WithSummary("List own proxies"),
+		mw.WithOperationID("listProxies"))
+	mw.ProtectedPost(api, "/api/v1/proxies", h.Proxy.CreateProxy,
+		mw.WithTags("Proxies"),
+		mw.WithSummary("Add own proxy"),
+		mw.WithDescription("Adds a proxy that fetches can use with fetch_options.proxy set to \"own\". The URL, including credentials, is stored encrypted and never returned."),
+		mw.WithOperationID("createProxy"))
+	mw.ProtectedPut(api, "/api/v1/proxies/{id}", h.Proxy.UpdateProxy,
+		mw.WithTags("Proxies"),
+		mw.WithSummary("Update own proxy"),
+		mw.WithOperationID("updateProxy"))
+	mw.ProtectedDelete(api, "/api/v1/proxies/{id}", h.Proxy.DeleteProxy,
+		mw.WithTags("Proxies"),
+		mw.WithSummary("Delete own proxy"),
+		mw.WithOperationID("deleteProxy"))
+	mw.ProtectedGet(api, "/api/v1/proxies/{id}/health", h.Proxy.GetProxyHealth,
+		mw.WithTags("Proxies"),
+		mw.WithSummary("Get own proxy health"),
+		mw.WithDescription("Returns fetch successes, failures and blocks through the proxy, across all domains and per domain."),
+		mw.WithOperationID("getProxyHealth"))
+
+	// --- Auth Profiles ---
+	mw.ProtectedGet(api, "/api/v1/auth-profiles", h.AuthProfile.ListAuthProfiles,
+		mw.WithTags("Auth Profiles"),
+		mw.WithSummary("List auth profiles"),
+		mw.WithOperationID("listAuthProfiles"))
+	mw.ProtectedPost(api, "/api/v1/auth-profiles", h.AuthProfile.CreateAuthProfile,
+		mw.WithTags("Auth Profiles"),
+		mw.WithSummary("Create auth profile"),
+		mw.WithDescription("Adds a login that extractions and crawls can use with auth_profile_id, either as an uploaded cookie jar or as a login script replayed in the browser. Cookies and scripts are stored encrypted and never returned."),
+		mw.WithOperationID("createAuthProfile"))
+	mw.ProtectedPut(api, "/api/v1/auth-profiles/{id}", h.AuthProfile.UpdateAuthProfile,
+		mw.WithTags("Auth Profiles"),
+		mw.WithSummary("Update auth profile"),
+		mw.WithOperationID("updateAuthProfile"))
+	mw.ProtectedDelete(api, "/api/v1/auth-profiles/{id}", h.AuthProfile.DeleteAuthProfile,
+		mw.WithTags("Auth Profiles"),
+		mw.WithSummary("Delete auth profile"),
+		mw.WithOperationID("deleteAuthProfile"))
+	mw.ProtectedPost(api, "/api/v1/auth-profiles/{id}/login", h.AuthProfile.LoginAuthProfile,
+		mw.WithTags("Auth Profiles"),
+		mw.WithSummary("Sign in with auth profile"),
+		mw.WithDescription("Runs a login profile's script now and stores the session cookies, to check the script or refresh the session ahead of a crawl."),
+		mw.WithOperationID("loginAuthProfile"))
+
 	// --- API Keys (hosted mode only) ---
 	if h.IncludeAPIKeys() {
 		mw.ProtectedGet(api, "/api/v1/keys", h.APIKey.ListKeys,
@@ -195,6 +278,68 @@ func Register(api huma.API, h *Handlers) {
 		mw.WithOperationID("adminSyncTiers"),
 		mw.WithSuperadmin(),
 		mw.WithHidden())
+	mw.ProtectedGet(api, "/api/v1/admin/provider-health", h.Admin.ListProviderHealth,
+		mw.WithTags("Admin"),
+		mw.WithSummary("List LLM provider health"),
+		mw.WithDescription("Returns circuit breaker state per provider/model. Models with an open circuit are tried last in fallback chains until a probe succeeds."),
+		mw.WithOperationID("adminListProviderHealth"),
+		mw.WithSuperadmin(),
+		mw.WithHidden())
+	mw.ProtectedPost(api, "/api/v1/admin/provider-health/reset", h.Admin.ResetProviderHealth,
+		mw.WithTags("Admin"),
+		mw.WithSummary("Reset LLM provider health"),
+		mw.WithDescription("Closes circuits for a provider/model (or all), restoring normal fallback chain order."),
+		mw.WithOperationID("adminResetProviderHealth"),
+		mw.WithSuperadmin(),
+		mw.WithHidden())
+	mw.ProtectedGet(api, "/api/v1/admin/proxies", h.Proxy.AdminListProxies,
+		mw.WithTags("Admin"),
+		mw.WithSummary("List pool proxies"),
+		mw.WithOperationID("adminListProxies"),
+		mw.WithSuperadmin(),
+		mw.WithHidden())
+	mw.ProtectedPost(api, "/api/v1/admin/proxies", h.Proxy.AdminCreateProxy,
+		mw.WithTags("Admin"),
+		mw.WithSummary("Add pool proxy"),
+		mw.WithDescription("Adds a proxy to the managed pool used by fetches with fetch_options.proxy set to \"pool\"."),
+		mw.WithOperationID("adminCreateProxy"),
+		mw.WithSuperadmin(),
+		mw.WithHidden())
+	mw.ProtectedPut(api, "/api/v1/admin/proxies/{id}", h.Proxy.AdminUpdateProxy,
+		mw.WithTags("Admin"),
+		mw.WithSummary("Update pool proxy"),
+		mw.WithOperationID("adminUpdateProxy"),
+		mw.WithSuperadmin(),
+		mw.WithHidden())
+	mw.ProtectedDelete(api, "/api/v1/admin/proxies/{id}", h.Proxy.AdminDeleteProxy,
+		mw.WithTags("Admin"),
+		mw.WithSummary("Delete pool proxy"),
+		mw.WithOperationID("adminDeleteProxy"),
+		mw.WithSuperadmin(),
+		mw.WithHidden())
+	mw.ProtectedGet(api, "/api/v1/admin/proxies/{id}/health", h.Proxy.AdminGetProxyHealth,
+		mw.WithTags("Admin"),
+		mw.WithSummary("Get pool proxy health"),
+		mw.WithDescription("Returns fetch successes, failures and blocks through the proxy per domain, with its selection score and whether it is resting after a block."),
+		mw.WithOperationID("adminGetProxyHealth"),
+		mw.WithSuperadmin(),
+		mw.WithHidden())
+	if h.IncludeLicense() {
+		mw.ProtectedGet(api, "/api/v1/admin/license", h.License.GetLicense,
+			mw.WithTags("Admin"),
+			mw.WithSummary("Get license"),
+			mw.WithDescription("Returns the installed self-hosted license, its entitlements and verification status."),
+			mw.WithOperationID("adminGetLicense"),
+			mw.WithSuperadmin(),
+			mw.WithHidden())
+		mw.ProtectedPut(api, "/api/v1/admin/license", h.License.ReplaceLicense,
+			mw.WithTags("Admin"),
+			mw.WithSummary("Replace license"),
+			mw.WithDescription("Verifies and installs a self-hosted license key, revoking the current license."),
+			mw.WithOperationID("adminReplaceLicense"),
+			mw.WithSuperadmin(),
+			mw.WithHidden())
+	}
 	mw.ProtectedGet(api, "/api/v1/admin/schemas", h.SchemaCatalog.ListAllSchemas,
 		mw.WithTags("Admin"),
 		mw.WithSummary("List all schemas (admin)"),
@@ -250,7 +395,7 @@ func Register(api huma.API, h *Handlers) {
 	mw.ProtectedGet(api, "/api/v1/internal/metrics", h.Metrics.GetMetrics,
 		mw.WithTags("Internal"),
 		mw.WithSummary("Get system metrics"),
-		mw.WithDescription("Returns job queue and rate limit statistics for monitoring"),
+		mw.WithDescription("Returns job queue, rate limit and LLM provider health statistics for monitoring"),
 		mw.WithOperationID("getSystemMetrics"),
 		mw.WithSuperadmin(),
 		mw.WithHidden())
@@ -342,6 +487,14 @@ func Register(api huma.API, h *Handlers) {
 		mw.WithTags("Extraction"),
 		mw.WithSummary("Extract data from URL"),
 		mw.WithOperationID("extract"),
+		mw.WithMaxBodyBytes(constants.MaxSubmittedContentRequestSize),
+		mw.WithQuotaCheck(),
+		mw.WithConcurrencyCheck())
+	mw.ProtectedPost(api, "/api/v1/extract/upload", h.Extraction.ExtractUpload,
+		mw.WithTags("Extraction"),
+		mw.WithSummary("Extract data from uploaded file"),
+		mw.WithOperationID("extractUpload"),
+		mw.WithMaxBodyBytes(constants.MaxSubmittedContentRequestSize),
 		mw.WithQuotaCheck(),
 		mw.WithConcurrencyCheck())
 	mw.ProtectedPost(api, "/api/v1/crawl", h.Crawl.CreateCrawlJob,
@@ -26,7 +26,11 @@ func StubHandlers() *Handlers {
 		Job:            &stubJobHandlers{},
 		Crawl:          &stubCrawlHandlers{},
 		Usage:          &stubUsageHandlers{},
+		SpendCap:       &stubSpendCapHandlers{},
+		Billing:        &stubBillingHandlers{},
 		UserLLM:        &stubUserLLMHandlers{},
+		Proxy:          &stubProxyHandlers{},
+		AuthProfile:    &stubAuthProfileHandlers{},
 		APIKey:         &stubAPIKeyHandlers{},
 		SchemaCatalog:  &stubSchemaCatalogHandlers{},
 		SavedSites:     &stubSavedSitesHandlers{},
@@ -35,6 +39,7 @@ func StubHandlers() *Handlers {
 		Extraction:     &stubExtractionHandlers{},
 		Admin:          &stubAdminHandlers{},
 		AdminAnalytics: &stubAdminAnalyticsHandlers{},
+		License:        &stubLicenseHandlers{},
 		Metrics:        &stubMetricsHandlers{},
 	}
 }
@@ -93,6 +98,10 @@ func (s *stubJobHandlers) DownloadJobDebugCapture(_ context.Context, _ *handlers
 	return nil, nil
 }
 
+func (s *stubJobHandlers) GetJobDebugCaptureHAR(_ context.Context, _ *handlers.GetJobDebugCaptureHARInput) (*handlers.GetJobDebugCaptureHAROutput, error) {
+	return nil, nil
+}
+
 // RegisterRawEndpoints calls the real handler's RegisterRawEndpoints method.
 // The real method already registers placeholder handlers, so it works for OpenAPI generation.
 // This avoids duplicating the Operation definitions.
@@ -223,6 +232,106 @@ func (s *stubSavedSitesHandlers) DeleteSavedSite(_ context.Context, _ *handlers.
 	return nil, nil
 }
 
+// --- Spend cap handlers stub ---
+
+type stubSpendCapHandlers struct{}
+
+func (s *stubSpendCapHandlers) ListSpendCaps(_ context.Context, _ *struct{}) (*handlers.ListSpendCapsOutput, error) {
+	return nil, nil
+}
+
+func (s *stubSpendCapHandlers) SetSpendCap(_ context.Context, _ *handlers.SetSpendCapInput) (*handlers.SetSpendCapOutput, error) {
+	return nil, nil
+}
+
+func (s *stubSpendCapHandlers) DeleteSpendCap(_ context.Context, _ *handlers.DeleteSpendCapInput) (*handlers.DeleteSpendCapOutput, error) {
+	return nil, nil
+}
+
+// --- Proxy handlers stub ---
+
+type stubProxyHandlers struct{}
+
+func (s *stubProxyHandlers) ListProxies(_ context.Context, _ *struct{}) (*handlers.ListProxiesOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) CreateProxy(_ context.Context, _ *handlers.CreateProxyInput) (*handlers.ProxyOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) UpdateProxy(_ context.Context, _ *handlers.UpdateProxyInput) (*handlers.ProxyOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) DeleteProxy(_ context.Context, _ *handlers.ProxyIDInput) (*handlers.DeleteProxyOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) GetProxyHealth(_ context.Context, _ *handlers.ProxyIDInput) (*handlers.ProxyHealthOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) AdminListProxies(_ context.Context, _ *struct{}) (*handlers.ListProxiesOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) AdminCreateProxy(_ context.Context, _ *handlers.CreateProxyInput) (*handlers.ProxyOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) AdminUpdateProxy(_ context.Context, _ *handlers.UpdateProxyInput) (*handlers.ProxyOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) AdminDeleteProxy(_ context.Context, _ *handlers.ProxyIDInput) (*handlers.DeleteProxyOutput, error) {
+	return nil, nil
+}
+
+func (s *stubProxyHandlers) AdminGetProxyHealth(_ context.Context, _ *handlers.ProxyIDInput) (*handlers.ProxyHealthOutput, error) {
+	return nil, nil
+}
+
+// --- Auth profile handlers stub ---
+
+type stubAuthProfileHandlers struct{}
+
+func (s *stubAuthProfileHandlers) ListAuthProfiles(_ context.Context, _ *struct{}) (*handlers.ListAuthProfilesOutput, error) {
+	return nil, nil
+}
+
+func (s *stubAuthProfileHandlers) CreateAuthProfile(_ context.Context, _ *handlers.CreateAuthProfileInput) (*handlers.AuthProfileOutput, error) {
+	return nil, nil
+}
+
+func (s *stubAuthProfileHandlers) UpdateAuthProfile(_ context.Context, _ *handlers.UpdateAuthProfileInput) (*handlers.AuthProfileOutput, error) {
+	return nil, nil
+}
+
+func (s *stubAuthProfileHandlers) DeleteAuthProfile(_ context.Context, _ *handlers.AuthProfileIDInput) (*handlers.DeleteAuthProfileOutput, error) {
+	return nil, nil
+}
+
+func (s *stubAuthProfileHandlers) LoginAuthProfile(_ context.Context, _ *handlers.AuthProfileIDInput) (*handlers.AuthProfileOutput, error) {
+	return nil, nil
+}
+
+// --- Billing handlers stub ---
+
+type stubBillingHandlers struct{}
+
+func (s *stubBillingHandlers) GetBalance(_ context.Context, _ *struct{}) (*handlers.GetBalanceOutput, error) {
+	return nil, nil
+}
+
+func (s *stubBillingHandlers) ListTransactions(_ context.Context, _ *handlers.ListTransactionsInput) (*handlers.ListTransactionsOutput, error) {
+	return nil, nil
+}
+
+func (s *stubBillingHandlers) GetStatement(_ context.Context, _ *handlers.GetStatementInput) (*handlers.GetStatementOutput, error) {
+	return nil, nil
+}
+
 // --- Webhook handlers stub ---
 
 type stubWebhookHandlers struct{}
@@ -267,6 +376,10 @@ func (s *stubExtractionHandlers) Extract(_ context.Context, _ *handlers.ExtractI
 	return nil, nil
 }
 
+func (s *stubExtractionHandlers) ExtractUpload(_ context.Context, _ *handlers.ExtractUploadInput) (*handlers.ExtractOutput, error) {
+	return nil, nil
+}
+
 // --- Admin handlers stub ---
 
 type stubAdminHandlers struct{}
@@ -311,6 +424,26 @@ func (s *stubAdminHandlers) SyncTiers(_ context.Context, _ *struct{}) (*handlers
 	return nil, nil
 }
 
+func (s *stubAdminHandlers) ListProviderHealth(_ context.Context, _ *struct{}) (*handlers.ListProviderHealthOutput, error) {
+	return nil, nil
+}
+
+func (s *stubAdminHandlers) ResetProviderHealth(_ context.Context, _ *handlers.ResetProviderHealthInput) (*handlers.ResetProviderHealthOutput, error) {
+	return nil, nil
+}
+
+// --- License handlers stub ---
+
+type stubLicenseHandlers struct{}
+
+func (s *stubLicenseHandlers) GetLicense(_ context.Context, _ *struct{}) (*handlers.LicenseOutput, error) {
+	return nil, nil
+}
+
+func (s *stubLicenseHandlers) ReplaceLicense(_ context.Context, _ *handlers.ReplaceLicenseInput) (*handlers.LicenseOutput, error) {
+	return nil, nil
+}
+
 // --- Admin Analytics handlers stub ---
 
 type stubAdminAnalyticsHandlers struct{}
@@ -0,0 +1,183 @@
+// Package license issues and verifies offline licenses for self-hosted deployments.
+//
+// A license is an Ed25519-signed token of the form:
+//
+//	rfl1.<base64url(claims JSON)>.<base64url(signature)>
+//
+// The signature covers the encoded claims segment, so a license can be verified
+// without contacting a licensing server. The public key used for verification is
+// set at build time using ldflags, or overridden with REFYNE_LICENSE_PUBLIC_KEY:
+//
+//	go build -ldflags "-X github.com/jmylchreest/refyne-api/internal/license.PublicKey=<base64 key>"
+package license
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenPrefix identifies a version 1 license token.
+const TokenPrefix = "rfl1."
+
+// PublicKey is the base64-encoded Ed25519 key licenses are verified against by default.
+// It is set at build time via ldflags.
+var PublicKey = ""
+
+var (
+	// ErrMalformed is returned when a token is not a well-formed license.
+	ErrMalformed = errors.New("malformed license token")
+	// ErrInvalidSignature is returned when a token was not signed by the license key.
+	ErrInvalidSignature = errors.New("invalid license signature")
+	// ErrNoPublicKey is returned when no license public key is configured.
+	ErrNoPublicKey = errors.New("no license public key configured")
+)
+
+// Status describes whether a license currently grants its entitlements.
+type Status string
+
+const (
+	// StatusValid means the license is signed and unexpired.
+	StatusValid Status = "valid"
+	// StatusGrace means the license has expired but is within its grace period.
+	StatusGrace Status = "grace"
+	// StatusExpired means the license has expired and its grace period has ended.
+	StatusExpired Status = "expired"
+	// StatusInvalid means the configured license failed verification.
+	StatusInvalid Status = "invalid"
+	// StatusMissing means no license is installed.
+	StatusMissing Status = "missing"
+)
+
+// Active returns true if the status grants the license's entitlements.
+func (s Status) Active() bool {
+	return s == StatusValid || s == StatusGrace
+}
+
+// Claims are the signed contents of a license.
+type Claims struct {
+	ID               string   `json:"lid"`
+	OrganizationName string   `json:"org"`
+	Email            string   `json:"email,omitempty"`
+	Tier             string   `json:"tier"`
+	MaxUsers         int      `json:"max_users,omitempty"` // Informational only, not enforced; 0 = unlimited
+	Features         []string `json:"features,omitempty"`
+	IssuedAt         int64    `json:"iat"`
+	ExpiresAt        int64    `json:"exp,omitempty"` // 0 = perpetual
+}
+
+// Expiry returns when the license expires, or nil for a perpetual license.
+func (c *Claims) Expiry() *time.Time {
+	if c.ExpiresAt == 0 {
+		return nil
+	}
+	t := time.Unix(c.ExpiresAt, 0).UTC()
+	return &t
+}
+
+// StatusAt returns the license status at now, allowing grace after expiry.
+func (c *Claims) StatusAt(now time.Time, grace time.Duration) Status {
+	expiry := c.Expiry()
+	switch {
+	case expiry == nil || now.Before(*expiry):
+		return StatusValid
+	case now.Before(expiry.Add(grace)):
+		return StatusGrace
+	default:
+		return StatusExpired
+	}
+}
+
+// GenerateKey creates a new signing keypair, returned base64-encoded.
+// The private key is encoded as its 32-byte seed.
+func GenerateKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate license key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv.Seed()), nil
+}
+
+// ParsePublicKey decodes a base64-encoded Ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	if s == "" {
+		return nil, ErrNoPublicKey
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("license public key must be a base64-encoded %d-byte Ed25519 key", ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParsePrivateKey decodes a base64-encoded Ed25519 private key, given as either
+// its 32-byte seed or the full 64-byte key.
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("license private key must be base64-encoded: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("license private key must be a %d-byte seed or %d-byte key", ed25519.SeedSize, ed25519.PrivateKeySize)
+	}
+}
+
+// Issue signs claims into a license token.
+func Issue(claims Claims, key ed25519.PrivateKey) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode license claims: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	sig := ed25519.Sign(key, []byte(encoded))
+	return TokenPrefix + encoded + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// Verify checks a license token's signature and returns its claims.
+// Expiry is not checked; use Claims.StatusAt.
+func Verify(token string, key ed25519.PublicKey) (*Claims, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, ErrNoPublicKey
+	}
+
+	rest, ok := strings.CutPrefix(strings.TrimSpace(token), TokenPrefix)
+	if !ok {
+		return nil, ErrMalformed
+	}
+	encoded, encodedSig, ok := strings.Cut(rest, ".")
+	if !ok {
+		return nil, ErrMalformed
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if !ed25519.Verify(key, []byte(encoded), sig) {
+		return nil, ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ErrMalformed
+	}
+	if claims.Tier == "" {
+		return nil, fmt.Errorf("%w: missing tier", ErrMalformed)
+	}
+
+	return &claims, nil
+}
@@ -0,0 +1,152 @@
+package license
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKeys(t *testing.T) (string, string) {
+	t.Helper()
+	pub, priv, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return pub, priv
+}
+
+func TestIssueAndVerify(t *testing.T) {
+	pubStr, privStr := testKeys(t)
+	priv, err := ParsePrivateKey(privStr)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	pub, err := ParsePublicKey(pubStr)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+
+	expires := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	token, err := Issue(Claims{
+		ID:               "lic_1",
+		OrganizationName: "Acme",
+		Tier:             "selfhosted",
+		MaxUsers:         25,
+		Features:         []string{"content_dynamic", "webhooks"},
+		IssuedAt:         time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		ExpiresAt:        expires.Unix(),
+	}, priv)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if !strings.HasPrefix(token, TokenPrefix) {
+		t.Errorf("token = %q, want prefix %q", token, TokenPrefix)
+	}
+
+	claims, err := Verify(token, pub)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.OrganizationName != "Acme" || claims.MaxUsers != 25 || len(claims.Features) != 2 {
+		t.Errorf("claims = %+v", claims)
+	}
+	if got := claims.Expiry(); got == nil || !got.Equal(expires) {
+		t.Errorf("Expiry() = %v, want %v", got, expires)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	pubStr, privStr := testKeys(t)
+	otherPubStr, _ := testKeys(t)
+	priv, _ := ParsePrivateKey(privStr)
+	pub, _ := ParsePublicKey(pubStr)
+	otherPub, _ := ParsePublicKey(otherPubStr)
+
+	token, err := Issue(Claims{ID: "lic_1", Tier: "selfhosted"}, priv)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	forged, err := Issue(Claims{ID: "lic_1", Tier: "selfhosted", Features: []string{"everything"}}, priv)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	// Swap the claims of one token onto the other's signature
+	parts := strings.Split(strings.TrimPrefix(token, TokenPrefix), ".")
+	forgedParts := strings.Split(strings.TrimPrefix(forged, TokenPrefix), ".")
+	spliced := TokenPrefix + forgedParts[0] + "." + parts[1]
+
+	tests := []struct {
+		name  string
+		token string
+		key   []byte
+		want  error
+	}{
+		{"wrong key", token, otherPub, ErrInvalidSignature},
+		{"spliced claims", spliced, pub, ErrInvalidSignature},
+		{"missing prefix", strings.TrimPrefix(token, TokenPrefix), pub, ErrMalformed},
+		{"missing signature", TokenPrefix + parts[0], pub, ErrMalformed},
+		{"no public key", token, nil, ErrNoPublicKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Verify(tt.token, tt.key); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimsStatusAt(t *testing.T) {
+	expires := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	grace := 7 * 24 * time.Hour
+	claims := &Claims{Tier: "selfhosted", ExpiresAt: expires.Unix()}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want Status
+	}{
+		{"before expiry", expires.Add(-time.Hour), StatusValid},
+		{"within grace", expires.Add(3 * 24 * time.Hour), StatusGrace},
+		{"after grace", expires.Add(grace + time.Hour), StatusExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := claims.StatusAt(tt.now, grace); got != tt.want {
+				t.Errorf("StatusAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	perpetual := &Claims{Tier: "selfhosted"}
+	if got := perpetual.StatusAt(time.Now().AddDate(50, 0, 0), 0); got != StatusValid {
+		t.Errorf("perpetual StatusAt() = %v, want %v", got, StatusValid)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	_, privStr := testKeys(t)
+	seeded, err := ParsePrivateKey(privStr)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey(seed) error = %v", err)
+	}
+	full, err := ParsePrivateKey(base64.StdEncoding.EncodeToString(seeded))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey(full) error = %v", err)
+	}
+	if !seeded.Equal(full) {
+		t.Error("seed and full key encodings parsed to different keys")
+	}
+
+	if _, err := ParsePrivateKey("not base64!"); err == nil {
+		t.Error("ParsePrivateKey() accepted invalid input")
+	}
+	if _, err := ParsePublicKey(""); !errors.Is(err, ErrNoPublicKey) {
+		t.Errorf("ParsePublicKey(\"\") error = %v, want %v", err, ErrNoPublicKey)
+	}
+	if _, err := ParsePublicKey(base64.StdEncoding.EncodeToString([]byte("short"))); err == nil {
+		t.Error("ParsePublicKey() accepted a short key")
+	}
+}
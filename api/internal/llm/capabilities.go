@@ -12,6 +12,7 @@ type ModelCapabilities struct {
 	SupportsStreaming         bool `json:"supports_streaming"`          // Streaming responses
 	SupportsReasoning         bool `json:"supports_reasoning"`          // Reasoning tokens (o1-style)
 	SupportsResponseFormat    bool `json:"supports_response_format"`    // response_format parameter
+	SupportsVision            bool `json:"supports_vision"`             // Image inputs (visual extraction)
 
 	// Token limits - populated by provider APIs or static defaults
 	MaxCompletionTokens int `json:"max_completion_tokens,omitempty"` // Max output tokens (0 = unknown, use default)
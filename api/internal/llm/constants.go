@@ -21,6 +21,22 @@ const (
 
 	// ProviderHelicone is the Helicone provider name.
 	ProviderHelicone = "helicone"
+
+	// ProviderGemini is the Google Gemini provider name.
+	ProviderGemini = "gemini"
+
+	// ProviderMistral is the Mistral AI provider name.
+	ProviderMistral = "mistral"
+
+	// ProviderGroq is the Groq provider name.
+	ProviderGroq = "groq"
+
+	// ProviderTogether is the Together AI provider name.
+	ProviderTogether = "together"
+
+	// ProviderOpenAICompatible is the generic provider for self-hosted OpenAI-compatible
+	// servers (vLLM, LM Studio, llama.cpp).
+	ProviderOpenAICompatible = "openai_compatible"
 )
 
 // Helicone URL constants.
@@ -30,6 +46,25 @@ const (
 	HeliconeCloudBaseURL = "https://ai-gateway.helicone.ai"
 )
 
+// Default base URLs for providers without a refyne library implementation.
+// Base URLs exclude the API version; endpoints add it.
+const (
+	// GeminiBaseURL is the Google Gemini API (generateContent format).
+	GeminiBaseURL = "https://generativelanguage.googleapis.com"
+
+	// MistralBaseURL is the Mistral AI API.
+	MistralBaseURL = "https://api.mistral.ai"
+
+	// GroqBaseURL is Groq's OpenAI-compatible API.
+	GroqBaseURL = "https://api.groq.com/openai"
+
+	// TogetherBaseURL is the Together AI API.
+	TogetherBaseURL = "https://api.together.xyz"
+
+	// OpenAICompatibleBaseURL is the default for self-hosted OpenAI-compatible servers (vLLM's default port).
+	OpenAICompatibleBaseURL = "http://localhost:8000"
+)
+
 // Timeout constants for LLM operations.
 const (
 	// LLMTimeout is the timeout for LLM completion requests.
@@ -45,15 +80,32 @@ func ValidProviders() []string {
 		ProviderOpenAI,
 		ProviderOllama,
 		ProviderHelicone,
+		ProviderGemini,
+		ProviderMistral,
+		ProviderGroq,
+		ProviderTogether,
+		ProviderOpenAICompatible,
 	}
 }
 
 // IsValidProvider returns true if the provider name is valid.
 func IsValidProvider(provider string) bool {
 	switch provider {
-	case ProviderOpenRouter, ProviderAnthropic, ProviderOpenAI, ProviderOllama, ProviderHelicone:
+	case ProviderOpenRouter, ProviderAnthropic, ProviderOpenAI, ProviderOllama, ProviderHelicone,
+		ProviderGemini, ProviderMistral, ProviderGroq, ProviderTogether, ProviderOpenAICompatible:
 		return true
 	default:
 		return false
 	}
 }
+
+// RequiresAPIKey returns true if calls to the provider need an API key.
+// Self-hosted providers (Ollama, OpenAI-compatible servers) work without one.
+func RequiresAPIKey(provider string) bool {
+	switch provider {
+	case ProviderOllama, ProviderOpenAICompatible:
+		return false
+	default:
+		return true
+	}
+}
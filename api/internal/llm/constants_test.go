@@ -4,8 +4,8 @@ import "testing"
 
 func TestValidProviders(t *testing.T) {
 	providers := ValidProviders()
-	if len(providers) != 5 {
-		t.Errorf("expected 5 providers, got %d", len(providers))
+	if len(providers) != 10 {
+		t.Errorf("expected 10 providers, got %d", len(providers))
 	}
 
 	expected := map[string]bool{
@@ -14,6 +14,12 @@ func TestValidProviders(t *testing.T) {
 		ProviderOpenAI:     true,
 		ProviderOllama:     true,
 		ProviderHelicone:   true,
+		ProviderGemini:     true,
+		ProviderMistral:    true,
+		ProviderGroq:       true,
+		ProviderTogether:   true,
+
+		ProviderOpenAICompatible: true,
 	}
 
 	for _, p := range providers {
@@ -38,6 +44,11 @@ func TestIsValidProvider(t *testing.T) {
 		{"openai", true},
 		{"ollama", true},
 		{"helicone", true},
+		{"gemini", true},
+		{"mistral", true},
+		{"groq", true},
+		{"together", true},
+		{"openai_compatible", true},
 		{"invalid", false},
 		{"", false},
 		{"OpenRouter", false}, // case sensitive
@@ -72,3 +83,12 @@ func TestProviderConstants(t *testing.T) {
 		t.Errorf("ProviderHelicone = %q, want %q", ProviderHelicone, "helicone")
 	}
 }
+
+func TestRequiresAPIKey(t *testing.T) {
+	for _, provider := range ValidProviders() {
+		want := provider != ProviderOllama && provider != ProviderOpenAICompatible
+		if got := RequiresAPIKey(provider); got != want {
+			t.Errorf("RequiresAPIKey(%q) = %v, want %v", provider, got, want)
+		}
+	}
+}
@@ -46,6 +46,7 @@ func loadEmbeddedProviderModels(logger *slog.Logger) map[string][]ModelInfo {
 					SupportsTools:             m.Capabilities.SupportsTools,
 					SupportsStreaming:         m.Capabilities.SupportsStreaming,
 					SupportsReasoning:         m.Capabilities.SupportsReasoning,
+					SupportsVision:            m.Capabilities.SupportsVision,
 				},
 				DefaultTemp:      GetDefaultSettings(providerName, m.ID).Temperature,
 				DefaultMaxTokens: GetDefaultSettings(providerName, m.ID).MaxTokens,
@@ -42,6 +42,9 @@ var (
 	// ErrInsufficientCredits indicates the user doesn't have enough credits for the operation.
 	ErrInsufficientCredits = errors.New("insufficient credits")
 
+	// ErrSpendCapExceeded indicates a user-configured spend cap has been reached.
+	ErrSpendCapExceeded = errors.New("spend cap exceeded")
+
 	// ErrNoModelsConfigured indicates no valid LLM models are available in the user's configuration.
 	ErrNoModelsConfigured = errors.New("no models configured")
 )
@@ -516,6 +519,28 @@ func NewInsufficientCreditsError(tier string, required, available int) *LLMError
 	}
 }
 
+// NewSpendCapExceededError creates an error for when an operation would exceed one of the
+// user's spend caps. scope describes the cap ("account" or "API key"), period is daily or
+// monthly, and limit names the exhausted limit (cost, tokens or pages).
+func NewSpendCapExceededError(scope, period, limit string) *LLMError {
+	return &LLMError{
+		Err:         ErrSpendCapExceeded,
+		StatusCode:  http.StatusTooManyRequests,
+		UserMessage: fmt.Sprintf("Your %s %s spend cap on %s has been reached. Raise or remove the cap to continue.", period, scope, limit),
+		Category:    "spend_cap_exceeded",
+		Retryable:   false,
+	}
+}
+
+// IsSpendCapExceeded checks if an error is a spend cap exceeded error.
+func IsSpendCapExceeded(err error) bool {
+	var llmErr *LLMError
+	if errors.As(err, &llmErr) {
+		return errors.Is(llmErr.Err, ErrSpendCapExceeded)
+	}
+	return errors.Is(err, ErrSpendCapExceeded)
+}
+
 // IsTierQuotaExceeded checks if an error is a tier quota exceeded error.
 func IsTierQuotaExceeded(err error) bool {
 	var llmErr *LLMError
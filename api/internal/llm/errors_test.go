@@ -511,6 +511,25 @@ func TestNewInsufficientCreditsError(t *testing.T) {
 	}
 }
 
+func TestNewSpendCapExceededError(t *testing.T) {
+	err := NewSpendCapExceededError("API key", "monthly", "cost")
+	if !errors.Is(err.Err, ErrSpendCapExceeded) {
+		t.Error("expected ErrSpendCapExceeded")
+	}
+	if err.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("StatusCode = %d, want %d", err.StatusCode, http.StatusTooManyRequests)
+	}
+	if err.Retryable || err.ShouldFallback {
+		t.Error("spend cap errors should not retry or fall back")
+	}
+	if !IsSpendCapExceeded(err) {
+		t.Error("expected IsSpendCapExceeded to be true")
+	}
+	if IsSpendCapExceeded(&LLMError{Err: ErrInsufficientCredits}) {
+		t.Error("expected IsSpendCapExceeded to be false for other errors")
+	}
+}
+
 func TestIsTierQuotaExceeded(t *testing.T) {
 	llmErr := &LLMError{Err: ErrTierQuotaExceeded}
 	other := &LLMError{Err: ErrProviderError}
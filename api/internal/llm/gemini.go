@@ -0,0 +1,208 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	refynellm "github.com/jmylchreest/refyne/pkg/llm"
+)
+
+// geminiAPIVersion is the Gemini API version used for generation and model listing.
+const geminiAPIVersion = "/v1beta"
+
+// GeminiChatEndpoint is the generateContent path; "{model}" is replaced with the model.
+const GeminiChatEndpoint = geminiAPIVersion + "/models/{model}:generateContent"
+
+// GeminiStreamURL converts a generateContent URL into its SSE streaming equivalent.
+func GeminiStreamURL(url string) string {
+	return strings.Replace(url, ":generateContent", ":streamGenerateContent?alt=sse", 1)
+}
+
+// GeminiRequestBody builds a generateContent request. Structured output is added
+// separately with ApplyStructuredOutput(StructuredOutputResponseSchema).
+func GeminiRequestBody(system, prompt string, temperature float64, maxTokens int) map[string]any {
+	body := map[string]any{
+		"contents": []map[string]any{{
+			"role":  "user",
+			"parts": []map[string]string{{"text": prompt}},
+		}},
+		"generationConfig": map[string]any{
+			"temperature":     temperature,
+			"maxOutputTokens": maxTokens,
+		},
+	}
+	if system != "" {
+		body["systemInstruction"] = map[string]any{
+			"parts": []map[string]string{{"text": system}},
+		}
+	}
+	return body
+}
+
+// GeminiResponse is a generateContent response. Streamed responses send one per SSE event.
+type GeminiResponse struct {
+	Candidates     []GeminiCandidate   `json:"candidates"`
+	UsageMetadata  GeminiUsageMetadata `json:"usageMetadata"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+// GeminiCandidate is one generated response.
+type GeminiCandidate struct {
+	Content struct {
+		Parts []struct {
+			Text string `json:"text"`
+		} `json:"parts"`
+	} `json:"content"`
+	FinishReason string `json:"finishReason"` // "STOP", "MAX_TOKENS", "SAFETY", ...
+}
+
+// GeminiUsageMetadata holds token usage (cumulative in streamed responses).
+type GeminiUsageMetadata struct {
+	PromptTokenCount     int `json:"promptTokenCount"`
+	CandidatesTokenCount int `json:"candidatesTokenCount"`
+}
+
+// ParseGeminiResponse parses a generateContent response body.
+func ParseGeminiResponse(body []byte) (*GeminiResponse, error) {
+	var resp GeminiResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse Gemini response: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("gemini API error (%s): %s", resp.Error.Status, resp.Error.Message)
+	}
+	if resp.PromptFeedback.BlockReason != "" {
+		return nil, fmt.Errorf("gemini blocked the prompt: %s", resp.PromptFeedback.BlockReason)
+	}
+	return &resp, nil
+}
+
+// Text returns the text of the first candidate.
+func (r *GeminiResponse) Text() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range r.Candidates[0].Content.Parts {
+		sb.WriteString(part.Text)
+	}
+	return sb.String()
+}
+
+// FinishReason returns the first candidate's finish reason as an OpenAI-style finish_reason.
+func (r *GeminiResponse) FinishReason() string {
+	if len(r.Candidates) == 0 {
+		return ""
+	}
+	switch reason := r.Candidates[0].FinishReason; reason {
+	case "MAX_TOKENS":
+		return "length"
+	case "STOP":
+		return "stop"
+	default:
+		return strings.ToLower(reason)
+	}
+}
+
+// geminiProvider implements refyne's Provider for the Gemini generateContent API,
+// so refyne extraction can use Gemini models.
+type geminiProvider struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// newGeminiProvider creates a Gemini provider. The API key is only required to execute,
+// so the provider can also be created for cost estimation.
+func newGeminiProvider(cfg refynellm.ProviderConfig) (refynellm.Provider, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = GeminiBaseURL
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = LLMTimeout
+	}
+	return &geminiProvider{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   cfg.Model,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Execute sends a generateContent request.
+func (p *geminiProvider) Execute(ctx context.Context, req refynellm.Request) (*refynellm.Response, error) {
+	if p.apiKey == "" {
+		return nil, fmt.Errorf("gemini API key required")
+	}
+	start := time.Now()
+
+	system, prompt := splitMessages(req.Messages)
+	body := GeminiRequestBody(system, prompt, req.Temperature, req.MaxTokens)
+	if req.JSONSchema != nil && getGeminiCapabilities(ctx, p.model).SupportsStructuredOutputs {
+		ApplyStructuredOutput(body, StructuredOutputResponseSchema, req.JSONSchema)
+	}
+
+	url := p.baseURL + strings.Replace(GeminiChatEndpoint, "{model}", p.model, 1)
+	respBody, err := postJSON(ctx, p.client, url, body, map[string]string{"x-goog-api-key": p.apiKey})
+	if err != nil {
+		return nil, fmt.Errorf("gemini API error: %w", err)
+	}
+
+	resp, err := ParseGeminiResponse(respBody)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Candidates) == 0 {
+		return nil, fmt.Errorf("no candidates in Gemini response")
+	}
+
+	usage := refynellm.Usage{
+		InputTokens:  resp.UsageMetadata.PromptTokenCount,
+		OutputTokens: resp.UsageMetadata.CandidatesTokenCount,
+	}
+	cost, _ := p.EstimateCost(ctx, p.model, usage.InputTokens, usage.OutputTokens)
+
+	return &refynellm.Response{
+		Content:      resp.Text(),
+		FinishReason: resp.FinishReason(),
+		Usage:        usage,
+		Model:        p.model,
+		Cost:         cost,
+		CostIncluded: true,
+		Duration:     time.Since(start),
+	}, nil
+}
+
+// Name returns the provider identifier.
+func (p *geminiProvider) Name() string {
+	return ProviderGemini
+}
+
+// Model returns the configured model name.
+func (p *geminiProvider) Model() string {
+	return p.model
+}
+
+// EstimateCost calculates cost from the model pricing configuration.
+func (p *geminiProvider) EstimateCost(_ context.Context, modelID string, inputTokens, outputTokens int) (float64, error) {
+	return GlobalModelPricing().EstimateCost(ProviderGemini, modelID, inputTokens, outputTokens), nil
+}
+
+var (
+	_ refynellm.Provider      = (*geminiProvider)(nil)
+	_ refynellm.CostEstimator = (*geminiProvider)(nil)
+)
@@ -0,0 +1,352 @@
+package llm
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a provider/model circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed means the model is healthy and used in chain order.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen means the model failed repeatedly and is moved to the back of the chain.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen means the cooldown elapsed and a single probe request is allowed through.
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// Default circuit breaker settings.
+const (
+	DefaultCircuitFailureThreshold = 3
+	DefaultCircuitCooldown         = time.Minute
+
+	// latencySampleSize is the number of recent successful call latencies kept per model.
+	latencySampleSize = 50
+)
+
+// HealthConfig configures a HealthTracker.
+type HealthConfig struct {
+	FailureThreshold int           // Consecutive provider failures before the circuit opens
+	Cooldown         time.Duration // Time an open circuit waits before allowing a probe
+}
+
+// HealthStatus is a snapshot of one provider/model's health for metrics and admin APIs.
+type HealthStatus struct {
+	Provider            string       `json:"provider"`
+	Model               string       `json:"model"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	TotalSuccesses      int64        `json:"total_successes"`
+	TotalFailures       int64        `json:"total_failures"`
+	TimesOpened         int64        `json:"times_opened"`
+	LastErrorCategory   string       `json:"last_error_category,omitempty"`
+	LastFailureAt       *time.Time   `json:"last_failure_at,omitempty"`
+	LastSuccessAt       *time.Time   `json:"last_success_at,omitempty"`
+	OpenedAt            *time.Time   `json:"opened_at,omitempty"`
+	ProbeAt             *time.Time   `json:"probe_at,omitempty"` // When an open circuit becomes eligible for a probe
+	P50LatencyMs        int64        `json:"p50_latency_ms,omitempty"`
+	LatencySamples      int          `json:"latency_samples,omitempty"`
+}
+
+// modelHealth is the mutable health state for one provider/model.
+type modelHealth struct {
+	state               CircuitState
+	consecutiveFailures int
+	totalSuccesses      int64
+	totalFailures       int64
+	timesOpened         int64
+	lastErrorCategory   string
+	lastFailureAt       time.Time
+	lastSuccessAt       time.Time
+	openedAt            time.Time
+	probeStartedAt      time.Time       // Zero when no half-open probe is in flight
+	latencies           []time.Duration // Ring buffer of recent successful call latencies
+	latencyNext         int             // Next ring buffer slot to overwrite once full
+}
+
+// p50 returns the median of the recorded latencies.
+func (h *modelHealth) p50() (time.Duration, bool) {
+	if len(h.latencies) == 0 {
+		return 0, false
+	}
+	sorted := slices.Clone(h.latencies)
+	slices.Sort(sorted)
+	return sorted[(len(sorted)-1)/2], true
+}
+
+// HealthTracker tracks provider/model health across all requests and implements a
+// circuit breaker: after repeated rate limits, 5xx errors or timeouts a model's circuit
+// opens and fallback chains try it last. After the cooldown one probe request is let
+// through (half-open, see BeginCall); success closes the circuit, failure re-opens it.
+//
+// A nil *HealthTracker is valid and treats every model as healthy.
+type HealthTracker struct {
+	mu      sync.Mutex
+	cfg     HealthConfig
+	models  map[string]*modelHealth
+	nowFunc func() time.Time
+}
+
+// NewHealthTracker creates a health tracker, applying defaults for unset config values.
+func NewHealthTracker(cfg HealthConfig) *HealthTracker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultCircuitFailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultCircuitCooldown
+	}
+	return &HealthTracker{
+		cfg:     cfg,
+		models:  make(map[string]*modelHealth),
+		nowFunc: time.Now,
+	}
+}
+
+// IsHealthFailure reports whether an error says the provider itself is unhealthy
+// (rate limited, erroring or timing out) rather than the request being bad.
+func IsHealthFailure(err *LLMError) bool {
+	if err == nil {
+		return false
+	}
+	switch err.Category {
+	case "rate_limit", "provider_error", "timeout":
+		return true
+	}
+	return err.StatusCode >= http.StatusInternalServerError
+}
+
+// healthKey returns the map key for a provider/model.
+func healthKey(provider, model string) string {
+	return provider + "/" + model
+}
+
+// get returns the health entry for a provider/model, creating it if needed. Caller holds mu.
+func (t *HealthTracker) get(provider, model string) *modelHealth {
+	key := healthKey(provider, model)
+	h, ok := t.models[key]
+	if !ok {
+		h = &modelHealth{state: CircuitClosed}
+		t.models[key] = h
+	}
+	return h
+}
+
+// Allow reports whether a request to the model should be attempted now. Closed circuits
+// are always allowed. An open circuit is allowed once its cooldown has elapsed, as long as
+// no half-open probe is in flight (or the probe has taken longer than the cooldown).
+// Allow only checks; BeginCall claims the probe when the request is actually made.
+func (t *HealthTracker) Allow(provider, model string) bool {
+	if t == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.models[healthKey(provider, model)]
+	if !ok {
+		return true
+	}
+	return t.probeAllowed(h, t.nowFunc())
+}
+
+// BeginCall records that a request to the model is being made. An open circuit whose
+// cooldown has elapsed moves to half-open with this request as its probe, and other
+// callers are refused until the probe reports back.
+func (t *HealthTracker) BeginCall(provider, model string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.models[healthKey(provider, model)]
+	if !ok || h.state == CircuitClosed {
+		return
+	}
+	now := t.nowFunc()
+	if !t.probeAllowed(h, now) {
+		// Only unhealthy configs were left, so the chain tried this one anyway
+		return
+	}
+	h.state = CircuitHalfOpen
+	h.probeStartedAt = now
+}
+
+// probeAllowed reports whether a request may be made to a model in its current state. Caller holds mu.
+func (t *HealthTracker) probeAllowed(h *modelHealth, now time.Time) bool {
+	switch h.state {
+	case CircuitOpen:
+		return now.Sub(h.openedAt) >= t.cfg.Cooldown
+	case CircuitHalfOpen:
+		return h.probeStartedAt.IsZero() || now.Sub(h.probeStartedAt) >= t.cfg.Cooldown
+	}
+	return true
+}
+
+// RecordSuccess records a successful provider response, closing the model's circuit.
+func (t *HealthTracker) RecordSuccess(provider, model string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.get(provider, model)
+	h.state = CircuitClosed
+	h.consecutiveFailures = 0
+	h.probeStartedAt = time.Time{}
+	h.totalSuccesses++
+	h.lastSuccessAt = t.nowFunc()
+}
+
+// RecordLatency records how long a successful call to a model took, for latency-aware routing.
+func (t *HealthTracker) RecordLatency(provider, model string, latency time.Duration) {
+	if t == nil || latency <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.get(provider, model)
+	if len(h.latencies) < latencySampleSize {
+		h.latencies = append(h.latencies, latency)
+		return
+	}
+	h.latencies[h.latencyNext] = latency
+	h.latencyNext = (h.latencyNext + 1) % latencySampleSize
+}
+
+// P50Latency returns the median of the model's recent successful call latencies.
+// Returns false if no latency has been recorded for the model.
+func (t *HealthTracker) P50Latency(provider, model string) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.models[healthKey(provider, model)]
+	if !ok {
+		return 0, false
+	}
+	return h.p50()
+}
+
+// RecordError records a failed request to a model, classified by ClassifyError. Only health
+// failures (see IsHealthFailure) count towards opening the circuit; other errors release
+// a half-open probe without changing the state, so only a success closes the circuit.
+func (t *HealthTracker) RecordError(provider, model string, err *LLMError) {
+	if t == nil || err == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h := t.get(provider, model)
+	if !IsHealthFailure(err) {
+		// The request failed for its own reasons (e.g. schema validation), which says
+		// nothing about whether the provider recovered; let another probe through
+		h.probeStartedAt = time.Time{}
+		return
+	}
+
+	now := t.nowFunc()
+	h.consecutiveFailures++
+	h.totalFailures++
+	h.lastErrorCategory = err.Category
+	h.lastFailureAt = now
+
+	// A failed probe re-opens immediately; a closed circuit opens at the threshold
+	if h.state == CircuitHalfOpen || (h.state == CircuitClosed && h.consecutiveFailures >= t.cfg.FailureThreshold) {
+		h.state = CircuitOpen
+		h.openedAt = now
+		h.probeStartedAt = time.Time{}
+		h.timesOpened++
+	}
+}
+
+// Reset closes circuits and clears failure counts. An empty model resets every model of the
+// provider; an empty provider resets everything. Returns the number of entries reset.
+func (t *HealthTracker) Reset(provider, model string) int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	count := 0
+	for key, h := range t.models {
+		p, m := splitHealthKey(key)
+		if (provider != "" && p != provider) || (model != "" && m != model) {
+			continue
+		}
+		h.state = CircuitClosed
+		h.consecutiveFailures = 0
+		h.probeStartedAt = time.Time{}
+		count++
+	}
+	return count
+}
+
+// Snapshot returns the health of every tracked provider/model, sorted by provider and model.
+func (t *HealthTracker) Snapshot() []HealthStatus {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timePtr := func(ts time.Time) *time.Time {
+		if ts.IsZero() {
+			return nil
+		}
+		return &ts
+	}
+
+	statuses := make([]HealthStatus, 0, len(t.models))
+	for key, h := range t.models {
+		provider, model := splitHealthKey(key)
+		status := HealthStatus{
+			Provider:            provider,
+			Model:               model,
+			State:               h.state,
+			ConsecutiveFailures: h.consecutiveFailures,
+			TotalSuccesses:      h.totalSuccesses,
+			TotalFailures:       h.totalFailures,
+			TimesOpened:         h.timesOpened,
+			LastErrorCategory:   h.lastErrorCategory,
+			LastFailureAt:       timePtr(h.lastFailureAt),
+			LastSuccessAt:       timePtr(h.lastSuccessAt),
+		}
+		if p50, ok := h.p50(); ok {
+			status.P50LatencyMs = p50.Milliseconds()
+			status.LatencySamples = len(h.latencies)
+		}
+		if h.state != CircuitClosed {
+			status.OpenedAt = timePtr(h.openedAt)
+			status.ProbeAt = timePtr(h.openedAt.Add(t.cfg.Cooldown))
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Provider != statuses[j].Provider {
+			return statuses[i].Provider < statuses[j].Provider
+		}
+		return statuses[i].Model < statuses[j].Model
+	})
+	return statuses
+}
+
+// splitHealthKey splits a health key into provider and model. Models may contain "/"
+// (e.g. OpenRouter's "openai/gpt-4o"), providers never do.
+func splitHealthKey(key string) (provider, model string) {
+	provider, model, _ = strings.Cut(key, "/")
+	return provider, model
+}
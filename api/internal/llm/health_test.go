@@ -0,0 +1,286 @@
+package llm
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newTestHealthTracker returns a tracker with a controllable clock.
+func newTestHealthTracker(threshold int, cooldown time.Duration) (*HealthTracker, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	t := NewHealthTracker(HealthConfig{FailureThreshold: threshold, Cooldown: cooldown})
+	t.nowFunc = func() time.Time { return now }
+	return t, &now
+}
+
+func healthErr(status int) *LLMError {
+	return ClassifyError(errors.New("provider failure"), "openai", "gpt-4o", status, false)
+}
+
+func TestIsHealthFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *LLMError
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limit", healthErr(http.StatusTooManyRequests), true},
+		{"service unavailable", healthErr(http.StatusServiceUnavailable), true},
+		{"bad gateway", healthErr(http.StatusBadGateway), true},
+		{"internal server error", healthErr(http.StatusInternalServerError), true},
+		{"timeout", ClassifyError(errors.New("context deadline exceeded"), "openai", "gpt-4o", 0, false), true},
+		{"invalid key", healthErr(http.StatusUnauthorized), false},
+		{"content too long", ClassifyError(errors.New("maximum context length exceeded"), "openai", "gpt-4o", 0, false), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsHealthFailure(tt.err); got != tt.want {
+				t.Errorf("IsHealthFailure() = %v, want %v (category %q)", got, tt.want, tt.err.Category)
+			}
+		})
+	}
+}
+
+func TestHealthTracker_OpensAfterThreshold(t *testing.T) {
+	tracker, _ := newTestHealthTracker(3, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+	}
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Fatal("circuit should stay closed below the threshold")
+	}
+
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+	if tracker.Allow("openai", "gpt-4o") {
+		t.Fatal("circuit should open at the threshold")
+	}
+	if !tracker.Allow("openai", "gpt-4o-mini") {
+		t.Error("other models of the provider should be unaffected")
+	}
+
+	status := tracker.Snapshot()[0]
+	if status.State != CircuitOpen || status.TimesOpened != 1 || status.ConsecutiveFailures != 3 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	if status.LastErrorCategory != "provider_error" {
+		t.Errorf("LastErrorCategory = %q, want provider_error", status.LastErrorCategory)
+	}
+	if status.OpenedAt == nil || status.ProbeAt == nil || status.ProbeAt.Sub(*status.OpenedAt) != time.Minute {
+		t.Errorf("probe should be scheduled one cooldown after opening: %+v", status)
+	}
+}
+
+func TestHealthTracker_SuccessResetsFailures(t *testing.T) {
+	tracker, _ := newTestHealthTracker(3, time.Minute)
+
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusTooManyRequests))
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusTooManyRequests))
+	tracker.RecordSuccess("openai", "gpt-4o")
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusTooManyRequests))
+
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Error("non-consecutive failures should not open the circuit")
+	}
+}
+
+func TestHealthTracker_IgnoresNonHealthErrors(t *testing.T) {
+	tracker, _ := newTestHealthTracker(1, time.Minute)
+
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusUnauthorized))
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Error("invalid key errors should not open the circuit")
+	}
+}
+
+func TestHealthTracker_HalfOpenProbe(t *testing.T) {
+	tracker, now := newTestHealthTracker(1, time.Minute)
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+
+	*now = now.Add(30 * time.Second)
+	if tracker.Allow("openai", "gpt-4o") {
+		t.Fatal("circuit should stay open during the cooldown")
+	}
+
+	*now = now.Add(31 * time.Second)
+	if !tracker.Allow("openai", "gpt-4o") || !tracker.Allow("openai", "gpt-4o") {
+		t.Fatal("a probe should be allowed after the cooldown until one is made")
+	}
+	tracker.BeginCall("openai", "gpt-4o")
+	if tracker.Allow("openai", "gpt-4o") {
+		t.Fatal("only one probe should be in flight")
+	}
+	if state := tracker.Snapshot()[0].State; state != CircuitHalfOpen {
+		t.Fatalf("state = %q, want half_open", state)
+	}
+
+	tracker.RecordSuccess("openai", "gpt-4o")
+	if state := tracker.Snapshot()[0].State; state != CircuitClosed {
+		t.Fatalf("state = %q, want closed after a successful probe", state)
+	}
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Error("closed circuit should allow requests")
+	}
+}
+
+func TestHealthTracker_FailedProbeReopens(t *testing.T) {
+	tracker, now := newTestHealthTracker(3, time.Minute)
+	for i := 0; i < 3; i++ {
+		tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+	}
+
+	*now = now.Add(time.Minute)
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Fatal("probe should be allowed after the cooldown")
+	}
+	tracker.BeginCall("openai", "gpt-4o")
+	// A single failure re-opens a half-open circuit, regardless of the threshold
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+
+	status := tracker.Snapshot()[0]
+	if status.State != CircuitOpen || status.TimesOpened != 2 {
+		t.Fatalf("unexpected status after failed probe: %+v", status)
+	}
+	if tracker.Allow("openai", "gpt-4o") {
+		t.Error("re-opened circuit should wait for a new cooldown")
+	}
+}
+
+func TestHealthTracker_StaleProbe(t *testing.T) {
+	tracker, now := newTestHealthTracker(1, time.Minute)
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+
+	*now = now.Add(time.Minute)
+	tracker.BeginCall("openai", "gpt-4o") // Probe that never reports back
+
+	*now = now.Add(time.Minute)
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Error("a new probe should be allowed when the previous one never reported")
+	}
+}
+
+func TestHealthTracker_NonHealthErrorKeepsProbing(t *testing.T) {
+	tracker, now := newTestHealthTracker(1, time.Minute)
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+
+	*now = now.Add(time.Minute)
+	tracker.BeginCall("openai", "gpt-4o")
+	// The probe failed for its own reasons (e.g. the response didn't match the schema)
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusBadRequest))
+
+	if state := tracker.Snapshot()[0].State; state != CircuitHalfOpen {
+		t.Fatalf("state = %q, want half_open until a request succeeds", state)
+	}
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Error("the probe should be released for another request")
+	}
+}
+
+func TestHealthTracker_BeginCallOnClosedCircuit(t *testing.T) {
+	tracker, _ := newTestHealthTracker(3, time.Minute)
+	tracker.BeginCall("openai", "gpt-4o")
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+	tracker.BeginCall("openai", "gpt-4o")
+
+	if state := tracker.Snapshot()[0].State; state != CircuitClosed {
+		t.Errorf("state = %q, want closed", state)
+	}
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Error("closed circuit should allow requests")
+	}
+}
+
+func TestHealthTracker_Reset(t *testing.T) {
+	tracker, _ := newTestHealthTracker(1, time.Minute)
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+	tracker.RecordError("openai", "gpt-4o-mini", healthErr(http.StatusServiceUnavailable))
+	tracker.RecordError("openrouter", "openai/gpt-4o", healthErr(http.StatusServiceUnavailable))
+
+	if n := tracker.Reset("openai", "gpt-4o"); n != 1 {
+		t.Errorf("Reset(model) = %d, want 1", n)
+	}
+	if !tracker.Allow("openai", "gpt-4o") || tracker.Allow("openai", "gpt-4o-mini") {
+		t.Error("only the named model should be reset")
+	}
+
+	if n := tracker.Reset("openai", ""); n != 2 {
+		t.Errorf("Reset(provider) = %d, want 2", n)
+	}
+	if tracker.Allow("openrouter", "openai/gpt-4o") {
+		t.Error("other providers should not be reset")
+	}
+
+	if n := tracker.Reset("", ""); n != 3 {
+		t.Errorf("Reset(all) = %d, want 3", n)
+	}
+	if !tracker.Allow("openrouter", "openai/gpt-4o") {
+		t.Error("all circuits should be closed")
+	}
+}
+
+func TestHealthTracker_SnapshotSplitsModelsWithSlashes(t *testing.T) {
+	tracker, _ := newTestHealthTracker(3, time.Minute)
+	tracker.RecordSuccess("openrouter", "meta-llama/llama-3.3-70b-instruct")
+	tracker.RecordSuccess("anthropic", "claude-sonnet-4-5")
+
+	snapshot := tracker.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("len(snapshot) = %d, want 2", len(snapshot))
+	}
+	if snapshot[0].Provider != "anthropic" {
+		t.Errorf("snapshot should be sorted by provider, got %q first", snapshot[0].Provider)
+	}
+	if snapshot[1].Provider != "openrouter" || snapshot[1].Model != "meta-llama/llama-3.3-70b-instruct" {
+		t.Errorf("unexpected entry: %+v", snapshot[1])
+	}
+	if snapshot[1].TotalSuccesses != 1 || snapshot[1].LastSuccessAt == nil {
+		t.Errorf("success should be counted: %+v", snapshot[1])
+	}
+}
+
+func TestHealthTracker_P50Latency(t *testing.T) {
+	tracker, _ := newTestHealthTracker(3, time.Minute)
+
+	if _, ok := tracker.P50Latency("openai", "gpt-4o"); ok {
+		t.Fatal("expected no latency before any samples")
+	}
+
+	for _, ms := range []int{900, 100, 300, 200, 5000} {
+		tracker.RecordLatency("openai", "gpt-4o", time.Duration(ms)*time.Millisecond)
+	}
+	p50, ok := tracker.P50Latency("openai", "gpt-4o")
+	if !ok || p50 != 300*time.Millisecond {
+		t.Errorf("P50Latency() = %v, %v; want 300ms", p50, ok)
+	}
+	if status := tracker.Snapshot()[0]; status.P50LatencyMs != 300 || status.LatencySamples != 5 {
+		t.Errorf("snapshot latency = %dms over %d samples, want 300ms over 5", status.P50LatencyMs, status.LatencySamples)
+	}
+
+	// Old samples roll out of the window
+	for i := 0; i < latencySampleSize; i++ {
+		tracker.RecordLatency("openai", "gpt-4o", 50*time.Millisecond)
+	}
+	if p50, _ := tracker.P50Latency("openai", "gpt-4o"); p50 != 50*time.Millisecond {
+		t.Errorf("P50Latency() after window rolled = %v, want 50ms", p50)
+	}
+}
+
+func TestHealthTracker_NilIsHealthy(t *testing.T) {
+	var tracker *HealthTracker
+	tracker.RecordError("openai", "gpt-4o", healthErr(http.StatusServiceUnavailable))
+	tracker.RecordSuccess("openai", "gpt-4o")
+	tracker.RecordLatency("openai", "gpt-4o", time.Second)
+	tracker.BeginCall("openai", "gpt-4o")
+	if !tracker.Allow("openai", "gpt-4o") {
+		t.Error("nil tracker should allow every model")
+	}
+	if _, ok := tracker.P50Latency("openai", "gpt-4o"); ok {
+		t.Error("nil tracker should have no latency")
+	}
+	if tracker.Snapshot() != nil || tracker.Reset("", "") != 0 {
+		t.Error("nil tracker should have no state")
+	}
+}
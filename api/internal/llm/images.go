@@ -0,0 +1,74 @@
+package llm
+
+// Image is an image input for a vision-capable model.
+type Image struct {
+	MimeType string // "image/jpeg" or "image/png"
+	Data     string // Base64-encoded image
+}
+
+// UserMessage builds a chat message holding a prompt and images in the message
+// format of the API. Images come before the text, as providers recommend.
+// Gemini requests are built with GeminiRequestBody and GeminiAddImages instead.
+func UserMessage(format APIFormat, prompt string, images []Image) map[string]any {
+	if len(images) == 0 {
+		return map[string]any{"role": "user", "content": prompt}
+	}
+
+	switch format {
+	case APIFormatAnthropic:
+		content := make([]map[string]any, 0, len(images)+1)
+		for _, img := range images {
+			content = append(content, map[string]any{
+				"type": "image",
+				"source": map[string]any{
+					"type":       "base64",
+					"media_type": img.MimeType,
+					"data":       img.Data,
+				},
+			})
+		}
+		content = append(content, map[string]any{"type": "text", "text": prompt})
+		return map[string]any{"role": "user", "content": content}
+
+	case APIFormatOllama:
+		data := make([]string, 0, len(images))
+		for _, img := range images {
+			data = append(data, img.Data)
+		}
+		return map[string]any{"role": "user", "content": prompt, "images": data}
+
+	default:
+		content := make([]map[string]any, 0, len(images)+1)
+		for _, img := range images {
+			content = append(content, map[string]any{
+				"type":      "image_url",
+				"image_url": map[string]any{"url": "data:" + img.MimeType + ";base64," + img.Data},
+			})
+		}
+		content = append(content, map[string]any{"type": "text", "text": prompt})
+		return map[string]any{"role": "user", "content": content}
+	}
+}
+
+// GeminiAddImages adds images to the user turn of a GeminiRequestBody, ahead of the prompt.
+func GeminiAddImages(body map[string]any, images []Image) {
+	if len(images) == 0 {
+		return
+	}
+	contents, ok := body["contents"].([]map[string]any)
+	if !ok || len(contents) == 0 {
+		return
+	}
+	textParts, _ := contents[0]["parts"].([]map[string]string)
+
+	parts := make([]map[string]any, 0, len(images)+len(textParts))
+	for _, img := range images {
+		parts = append(parts, map[string]any{
+			"inline_data": map[string]any{"mime_type": img.MimeType, "data": img.Data},
+		})
+	}
+	for _, p := range textParts {
+		parts = append(parts, map[string]any{"text": p["text"]})
+	}
+	contents[0]["parts"] = parts
+}
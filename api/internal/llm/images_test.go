@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var testImages = []Image{{MimeType: "image/jpeg", Data: "aW1n"}}
+
+// roundTrip marshals v to JSON and back, so tests compare wire formats rather than Go types.
+func roundTrip(t *testing.T, v any) any {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return out
+}
+
+func TestUserMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		format APIFormat
+		images []Image
+		want   string
+	}{
+		{"text only", APIFormatOpenAI, nil, `{"role":"user","content":"extract"}`},
+		{"openai", APIFormatOpenAI, testImages,
+			`{"role":"user","content":[{"type":"image_url","image_url":{"url":"data:image/jpeg;base64,aW1n"}},{"type":"text","text":"extract"}]}`},
+		{"anthropic", APIFormatAnthropic, testImages,
+			`{"role":"user","content":[{"type":"image","source":{"type":"base64","media_type":"image/jpeg","data":"aW1n"}},{"type":"text","text":"extract"}]}`},
+		{"ollama", APIFormatOllama, testImages, `{"role":"user","content":"extract","images":["aW1n"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want any
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if got := roundTrip(t, UserMessage(tt.format, "extract", tt.images)); !reflect.DeepEqual(got, want) {
+				t.Errorf("UserMessage() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestGeminiAddImages(t *testing.T) {
+	body := GeminiRequestBody("", "extract", 0.2, 1024)
+	GeminiAddImages(body, testImages)
+
+	var want any
+	if err := json.Unmarshal([]byte(`[{"role":"user","parts":[{"inline_data":{"mime_type":"image/jpeg","data":"aW1n"}},{"text":"extract"}]}]`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if got := roundTrip(t, body["contents"]); !reflect.DeepEqual(got, want) {
+		t.Errorf("contents = %v, want %v", got, want)
+	}
+}
@@ -53,6 +53,12 @@ var defaultProviderPricing = map[string]ModelPricing{
 	"anthropic":  {PromptPricePer1M: 3.00, CompletionPricePer1M: 15.0},
 	"ollama":     {PromptPricePer1M: 0.0, CompletionPricePer1M: 0.0, IsFree: true},
 	"helicone":   {PromptPricePer1M: 0.50, CompletionPricePer1M: 1.50},
+	"gemini":     {PromptPricePer1M: 0.30, CompletionPricePer1M: 2.50},
+	"mistral":    {PromptPricePer1M: 0.40, CompletionPricePer1M: 2.00},
+	"groq":       {PromptPricePer1M: 0.59, CompletionPricePer1M: 0.79},
+	"together":   {PromptPricePer1M: 0.88, CompletionPricePer1M: 0.88},
+
+	"openai_compatible": {PromptPricePer1M: 0.0, CompletionPricePer1M: 0.0, IsFree: true},
 }
 
 var defaultModelPricing = map[string]ModelPricing{
@@ -86,6 +92,27 @@ var defaultModelPricing = map[string]ModelPricing{
 	"google/gemini-pro-1.5":            {PromptPricePer1M: 1.25, CompletionPricePer1M: 5.0},
 	"google/gemini-2.0-flash-exp:free": {PromptPricePer1M: 0.0, CompletionPricePer1M: 0.0, IsFree: true},
 	"google/gemma-3-27b-it:free":       {PromptPricePer1M: 0.0, CompletionPricePer1M: 0.0, IsFree: true},
+	"gemini-2.5-pro":                   {PromptPricePer1M: 1.25, CompletionPricePer1M: 10.0},
+	"gemini-2.5-flash":                 {PromptPricePer1M: 0.30, CompletionPricePer1M: 2.50},
+	"gemini-2.5-flash-lite":            {PromptPricePer1M: 0.10, CompletionPricePer1M: 0.40},
+	"gemini-2.0-flash":                 {PromptPricePer1M: 0.10, CompletionPricePer1M: 0.40},
+
+	// Mistral models
+	"mistral-large-latest":  {PromptPricePer1M: 2.0, CompletionPricePer1M: 6.0},
+	"mistral-medium-latest": {PromptPricePer1M: 0.40, CompletionPricePer1M: 2.0},
+	"mistral-small-latest":  {PromptPricePer1M: 0.10, CompletionPricePer1M: 0.30},
+	"ministral-8b-latest":   {PromptPricePer1M: 0.10, CompletionPricePer1M: 0.10},
+
+	// Groq models
+	"llama-3.3-70b-versatile": {PromptPricePer1M: 0.59, CompletionPricePer1M: 0.79},
+	"llama-3.1-8b-instant":    {PromptPricePer1M: 0.05, CompletionPricePer1M: 0.08},
+	"openai/gpt-oss-120b":     {PromptPricePer1M: 0.15, CompletionPricePer1M: 0.75},
+	"openai/gpt-oss-20b":      {PromptPricePer1M: 0.10, CompletionPricePer1M: 0.50},
+
+	// Together models
+	"meta-llama/Llama-3.3-70B-Instruct-Turbo": {PromptPricePer1M: 0.88, CompletionPricePer1M: 0.88},
+	"Qwen/Qwen2.5-72B-Instruct-Turbo":         {PromptPricePer1M: 1.20, CompletionPricePer1M: 1.20},
+	"deepseek-ai/DeepSeek-V3":                 {PromptPricePer1M: 1.25, CompletionPricePer1M: 1.25},
 }
 
 // NewModelPricingLoader creates a new model pricing loader.
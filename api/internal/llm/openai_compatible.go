@@ -0,0 +1,246 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	refynellm "github.com/jmylchreest/refyne/pkg/llm"
+)
+
+// openAICompatibleChatEndpoint is the chat completions path shared by OpenAI-compatible APIs.
+const openAICompatibleChatEndpoint = "/v1/chat/completions"
+
+// openAICompatibleSpec describes an OpenAI-compatible provider for the refyne library.
+type openAICompatibleSpec struct {
+	baseURL         string
+	getCapabilities CapabilitiesLookup
+}
+
+// openAICompatibleSpecs lists the OpenAI-compatible providers refyne doesn't implement itself.
+var openAICompatibleSpecs = map[string]openAICompatibleSpec{
+	ProviderMistral:          {baseURL: MistralBaseURL, getCapabilities: getMistralCapabilities},
+	ProviderGroq:             {baseURL: GroqBaseURL, getCapabilities: getGroqCapabilities},
+	ProviderTogether:         {baseURL: TogetherBaseURL, getCapabilities: getTogetherCapabilities},
+	ProviderOpenAICompatible: {baseURL: OpenAICompatibleBaseURL, getCapabilities: getOpenAICompatibleCapabilities},
+}
+
+// Register providers with the refyne library so refyne extraction can use them by name.
+func init() {
+	for name, spec := range openAICompatibleSpecs {
+		refynellm.RegisterProvider(name, func(cfg refynellm.ProviderConfig) (refynellm.Provider, error) {
+			return newOpenAICompatibleProvider(name, spec, cfg), nil
+		})
+	}
+	refynellm.RegisterProvider(ProviderGemini, newGeminiProvider)
+}
+
+// openAICompatibleProvider implements refyne's Provider for OpenAI-compatible chat
+// completion APIs, choosing json_schema or json_object per model capabilities.
+type openAICompatibleProvider struct {
+	name    string
+	spec    openAICompatibleSpec
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// newOpenAICompatibleProvider creates a provider. Keys are checked on execute so the
+// provider can also be created for cost estimation.
+func newOpenAICompatibleProvider(name string, spec openAICompatibleSpec, cfg refynellm.ProviderConfig) *openAICompatibleProvider {
+	baseURL := spec.baseURL
+	if cfg.BaseURL != "" {
+		baseURL = cfg.BaseURL
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = LLMTimeout
+	}
+	return &openAICompatibleProvider{
+		name:    name,
+		spec:    spec,
+		apiKey:  cfg.APIKey,
+		baseURL: TrimOpenAIBaseURL(baseURL),
+		model:   cfg.Model,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Execute sends a chat completion request.
+func (p *openAICompatibleProvider) Execute(ctx context.Context, req refynellm.Request) (*refynellm.Response, error) {
+	if p.apiKey == "" && RequiresAPIKey(p.name) {
+		return nil, fmt.Errorf("%s API key required", p.name)
+	}
+	start := time.Now()
+
+	messages := make([]map[string]string, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, map[string]string{"role": string(m.Role), "content": m.Content})
+	}
+	body := map[string]any{
+		"model":       p.model,
+		"messages":    messages,
+		"temperature": req.Temperature,
+	}
+	if req.MaxTokens > 0 {
+		body["max_tokens"] = req.MaxTokens
+	}
+	if req.JSONSchema != nil {
+		strategy := ResolveStructuredOutput(StructuredOutputJSONSchema, p.spec.getCapabilities(ctx, p.model))
+		if strategy == StructuredOutputJSONSchema && !req.StrictMode {
+			strategy = StructuredOutputJSONObject
+		}
+		ApplyStructuredOutput(body, strategy, req.JSONSchema)
+	}
+
+	respBody, err := postJSON(ctx, p.client, p.baseURL+openAICompatibleChatEndpoint, body, bearerHeaders(p.apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("%s API error: %w", p.name, err)
+	}
+
+	var resp struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse %s response: %w", p.name, err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("no choices in %s response", p.name)
+	}
+
+	usage := refynellm.Usage{
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+	}
+	cost, _ := p.EstimateCost(ctx, p.model, usage.InputTokens, usage.OutputTokens)
+
+	model := resp.Model
+	if model == "" {
+		model = p.model
+	}
+	return &refynellm.Response{
+		Content:      resp.Choices[0].Message.Content,
+		FinishReason: resp.Choices[0].FinishReason,
+		Usage:        usage,
+		Model:        model,
+		Cost:         cost,
+		CostIncluded: true,
+		Duration:     time.Since(start),
+	}, nil
+}
+
+// Name returns the provider identifier.
+func (p *openAICompatibleProvider) Name() string {
+	return p.name
+}
+
+// Model returns the configured model name.
+func (p *openAICompatibleProvider) Model() string {
+	return p.model
+}
+
+// EstimateCost calculates cost from the model pricing configuration.
+func (p *openAICompatibleProvider) EstimateCost(_ context.Context, modelID string, inputTokens, outputTokens int) (float64, error) {
+	return GlobalModelPricing().EstimateCost(p.name, modelID, inputTokens, outputTokens), nil
+}
+
+var (
+	_ refynellm.Provider      = (*openAICompatibleProvider)(nil)
+	_ refynellm.CostEstimator = (*openAICompatibleProvider)(nil)
+)
+
+// openAIModelList is the GET /v1/models response shared by OpenAI-compatible APIs.
+// Providers add their own fields (context_window, max_context_length, ...).
+type openAIModelList struct {
+	Data []openAIModelEntry `json:"data"`
+}
+
+// openAIModelEntry is a model from an OpenAI-compatible models endpoint.
+type openAIModelEntry struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`               // Mistral
+	ContextWindow    int    `json:"context_window"`     // Groq
+	MaxContextLength int    `json:"max_context_length"` // Mistral
+	MaxModelLen      int    `json:"max_model_len"`      // vLLM
+	Active           *bool  `json:"active"`             // Groq
+	Capabilities     *struct {
+		CompletionChat  bool `json:"completion_chat"`
+		FunctionCalling bool `json:"function_calling"`
+		Vision          bool `json:"vision"`
+	} `json:"capabilities"` // Mistral
+}
+
+// contextWindow returns whichever context length field the provider populated.
+func (m openAIModelEntry) contextWindow() int {
+	switch {
+	case m.ContextWindow > 0:
+		return m.ContextWindow
+	case m.MaxContextLength > 0:
+		return m.MaxContextLength
+	default:
+		return m.MaxModelLen
+	}
+}
+
+// fetchOpenAICompatibleModels lists chat models from an OpenAI-compatible /v1/models endpoint.
+func fetchOpenAICompatibleModels(ctx context.Context, provider, baseURL, apiKey string, getCaps CapabilitiesLookup) ([]ModelInfo, error) {
+	var list openAIModelList
+	if err := getJSON(ctx, TrimOpenAIBaseURL(baseURL)+"/v1/models", bearerHeaders(apiKey), &list); err != nil {
+		return nil, err
+	}
+
+	result := make([]ModelInfo, 0, len(list.Data))
+	for _, m := range list.Data {
+		if m.Active != nil && !*m.Active {
+			continue
+		}
+		if m.Capabilities != nil && !m.Capabilities.CompletionChat {
+			continue // Embedding, OCR and moderation models
+		}
+		if isNonChatModel(m.ID) {
+			continue
+		}
+
+		name := m.Name
+		if name == "" {
+			name = m.ID
+		}
+		settings := GetDefaultSettings(provider, m.ID)
+		result = append(result, ModelInfo{
+			ID:               m.ID,
+			Name:             name,
+			Provider:         provider,
+			ContextWindow:    m.contextWindow(),
+			Capabilities:     getCaps(ctx, m.ID),
+			DefaultTemp:      settings.Temperature,
+			DefaultMaxTokens: settings.MaxTokens,
+		})
+	}
+	return result, nil
+}
+
+// isNonChatModel reports whether a model ID names a model that can't do chat completions
+// (speech, embedding, moderation), for providers whose model lists don't say.
+func isNonChatModel(id string) bool {
+	id = strings.ToLower(id)
+	for _, marker := range []string{"whisper", "embed", "tts", "guard", "moderation"} {
+		if strings.Contains(id, marker) {
+			return true
+		}
+	}
+	return false
+}
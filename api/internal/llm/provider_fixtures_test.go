@@ -0,0 +1,503 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	refynellm "github.com/jmylchreest/refyne/pkg/llm"
+
+	"github.com/jmylchreest/refyne-api/internal/config"
+)
+
+// ========================================
+// Recorded HTTP Fixture Helpers
+// ========================================
+
+// fixture reads a recorded provider response from testdata.
+func fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+// recordedRequest captures what a fixture server received.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   map[string]any
+}
+
+// fixtureServer serves recorded responses keyed by request path, recording requests.
+func fixtureServer(t *testing.T, routes map[string]func(r *http.Request) []byte) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recordedRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Header: r.Header.Clone()}
+		if body, _ := io.ReadAll(r.Body); len(body) > 0 {
+			_ = json.Unmarshal(body, &rec.Body)
+		}
+		requests = append(requests, rec)
+
+		route, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(route(r))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// serve returns a route handler that always responds with the named fixture.
+func serve(t *testing.T, name string) func(*http.Request) []byte {
+	data := fixture(t, name)
+	return func(*http.Request) []byte { return data }
+}
+
+func modelIDs(models []ModelInfo) []string {
+	ids := make([]string, len(models))
+	for i, m := range models {
+		ids[i] = m.ID
+	}
+	return ids
+}
+
+func assertModelIDs(t *testing.T, models []ModelInfo, want ...string) {
+	t.Helper()
+	got := modelIDs(models)
+	if len(got) != len(want) {
+		t.Fatalf("models = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("models = %v, want %v", got, want)
+		}
+	}
+}
+
+// ========================================
+// Model Listing Tests
+// ========================================
+
+func TestListGeminiModels_Fixture(t *testing.T) {
+	server, requests := fixtureServer(t, map[string]func(*http.Request) []byte{
+		"/v1beta/models": func(r *http.Request) []byte {
+			if r.URL.Query().Get("pageToken") == "page-2" {
+				return fixture(t, "gemini_models_page2.json")
+			}
+			return fixture(t, "gemini_models.json")
+		},
+	})
+
+	models, err := listGeminiModels(context.Background(), server.URL, "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Embedding models are dropped, "models/" is stripped and both pages are read
+	assertModelIDs(t, models, "gemini-2.5-flash", "gemma-3-27b-it", "gemini-2.5-pro")
+	if len(*requests) != 2 {
+		t.Fatalf("expected 2 paginated requests, got %d", len(*requests))
+	}
+	if got := (*requests)[0].Header.Get("x-goog-api-key"); got != "test-key" {
+		t.Errorf("x-goog-api-key = %q, want %q", got, "test-key")
+	}
+
+	flash := models[0]
+	if flash.Name != "Gemini 2.5 Flash" || flash.Provider != ProviderGemini {
+		t.Errorf("unexpected model info: %+v", flash)
+	}
+	if flash.ContextWindow != 1048576 || flash.MaxCompletionTokens != 65536 {
+		t.Errorf("token limits = %d/%d, want 1048576/65536", flash.ContextWindow, flash.MaxCompletionTokens)
+	}
+	if !flash.Capabilities.SupportsStructuredOutputs {
+		t.Error("gemini-2.5-flash should support structured outputs")
+	}
+	if models[1].Capabilities.SupportsStructuredOutputs {
+		t.Error("gemma models should not support structured outputs")
+	}
+}
+
+func TestListMistralModels_Fixture(t *testing.T) {
+	server, requests := fixtureServer(t, map[string]func(*http.Request) []byte{
+		"/v1/models": serve(t, "mistral_models.json"),
+	})
+
+	models, err := listMistralModels(context.Background(), server.URL, "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Embedding models (completion_chat=false) are dropped; results are sorted by ID
+	assertModelIDs(t, models, "magistral-medium-latest", "mistral-small-latest")
+	if got := (*requests)[0].Header.Get("Authorization"); got != "Bearer test-key" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer test-key")
+	}
+	if models[1].Name != "mistral-small-2506" || models[1].ContextWindow != 131072 {
+		t.Errorf("unexpected model info: %+v", models[1])
+	}
+	if !models[0].Capabilities.SupportsReasoning {
+		t.Error("magistral should support reasoning")
+	}
+}
+
+func TestListGroqModels_Fixture(t *testing.T) {
+	server, _ := fixtureServer(t, map[string]func(*http.Request) []byte{
+		"/openai/v1/models": serve(t, "groq_models.json"),
+	})
+
+	models, err := listGroqModels(context.Background(), server.URL+"/openai", "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Inactive and speech models are dropped
+	assertModelIDs(t, models, "llama-3.3-70b-versatile", "openai/gpt-oss-120b")
+	if models[0].ContextWindow != 131072 {
+		t.Errorf("ContextWindow = %d, want 131072", models[0].ContextWindow)
+	}
+	if models[0].Capabilities.SupportsStructuredOutputs {
+		t.Error("llama-3.3-70b-versatile should not support json_schema on Groq")
+	}
+	if !models[1].Capabilities.SupportsStructuredOutputs {
+		t.Error("openai/gpt-oss-120b should support json_schema on Groq")
+	}
+}
+
+func TestListTogetherModels_Fixture(t *testing.T) {
+	server, _ := fixtureServer(t, map[string]func(*http.Request) []byte{
+		"/v1/models": serve(t, "together_models.json"),
+	})
+
+	models, err := listTogetherModels(context.Background(), server.URL, "test-key")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertModelIDs(t, models, "deepseek-ai/DeepSeek-R1", "meta-llama/Llama-3.3-70B-Instruct-Turbo")
+	if models[1].Name != "Meta Llama 3.3 70B Instruct Turbo" || models[1].ContextWindow != 131072 {
+		t.Errorf("unexpected model info: %+v", models[1])
+	}
+	if !models[0].Capabilities.SupportsReasoning {
+		t.Error("DeepSeek-R1 should support reasoning")
+	}
+}
+
+func TestListOpenAICompatibleModels_Fixture(t *testing.T) {
+	server, requests := fixtureServer(t, map[string]func(*http.Request) []byte{
+		"/v1/models": serve(t, "openai_compatible_models.json"),
+	})
+
+	// Users commonly paste the base URL with /v1; it must not be doubled
+	models, err := listOpenAICompatibleModels(context.Background(), server.URL+"/v1/", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertModelIDs(t, models, "Qwen/Qwen2.5-7B-Instruct")
+	if models[0].ContextWindow != 32768 {
+		t.Errorf("ContextWindow = %d, want 32768", models[0].ContextWindow)
+	}
+	if got := (*requests)[0].Header.Get("Authorization"); got != "" {
+		t.Errorf("expected no Authorization header without a key, got %q", got)
+	}
+}
+
+func TestListModels_NoKeyFallsBack(t *testing.T) {
+	server, requests := fixtureServer(t, nil)
+
+	listers := map[string]ModelLister{
+		"gemini":   listGeminiModels,
+		"mistral":  listMistralModels,
+		"groq":     listGroqModels,
+		"together": listTogetherModels,
+	}
+	for name, list := range listers {
+		t.Run(name, func(t *testing.T) {
+			if _, err := list(context.Background(), server.URL, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+	if len(*requests) != 0 {
+		t.Errorf("expected no requests without an API key, got %d", len(*requests))
+	}
+}
+
+func TestListModels_ErrorFallsBack(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"invalid api key"}}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	if _, err := listGroqModels(context.Background(), server.URL, "bad-key"); err != nil {
+		t.Errorf("expected fallback without error, got %v", err)
+	}
+	if _, err := listGeminiModels(context.Background(), server.URL, "bad-key"); err != nil {
+		t.Errorf("expected fallback without error, got %v", err)
+	}
+}
+
+// ========================================
+// Provider Registry Tests
+// ========================================
+
+func TestNewProviders_Registered(t *testing.T) {
+	r := InitRegistry(&config.Config{}, slog.Default())
+	for _, name := range []string{ProviderGemini, ProviderMistral, ProviderGroq, ProviderTogether, ProviderOpenAICompatible} {
+		t.Run(name, func(t *testing.T) {
+			reg, ok := r.GetProvider(name)
+			if !ok {
+				t.Fatalf("provider %q not in registry", name)
+			}
+			if reg.Info.RequiresKey != RequiresAPIKey(name) {
+				t.Errorf("RequiresKey = %v, want %v", reg.Info.RequiresKey, RequiresAPIKey(name))
+			}
+			if !refynellm.IsRegistered(name) {
+				t.Errorf("provider %q not registered with refyne", name)
+			}
+		})
+	}
+
+	if cfg := r.GetProviderAPIConfig(ProviderGemini); cfg == nil || cfg.AuthHeader != "x-goog-api-key" {
+		t.Errorf("gemini should authenticate with x-goog-api-key, got %+v", cfg)
+	}
+	if cfg := r.GetProviderAPIConfig(ProviderGroq); cfg == nil || cfg.AuthType != AuthTypeBearer {
+		t.Errorf("groq should authenticate with a bearer token, got %+v", cfg)
+	}
+	if reg, _ := r.GetProvider(ProviderOpenAICompatible); !reg.Info.AllowBaseURLOverride {
+		t.Error("openai_compatible should allow base URL override")
+	}
+	if r.SupportsPricing(ProviderOpenAICompatible) {
+		t.Error("openai_compatible should not support pricing")
+	}
+	if !r.SupportsPricing(ProviderGemini) {
+		t.Error("gemini should support pricing")
+	}
+}
+
+// ========================================
+// refyne Provider Adapter Tests
+// ========================================
+
+func extractionRequest(strict bool) refynellm.Request {
+	return refynellm.Request{
+		Messages: []refynellm.Message{
+			{Role: refynellm.RoleSystem, Content: "Extract product data."},
+			{Role: refynellm.RoleUser, Content: "<h1>Blue Widget</h1> $19.99"},
+		},
+		MaxTokens:   1024,
+		Temperature: 0.2,
+		JSONSchema:  map[string]any{"type": "object", "properties": map[string]any{"title": map[string]any{"type": "string"}}},
+		StrictMode:  strict,
+	}
+}
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-12
+}
+
+func TestGeminiProvider_Execute(t *testing.T) {
+	server, requests := fixtureServer(t, map[string]func(*http.Request) []byte{
+		"/v1beta/models/gemini-2.5-flash:generateContent": serve(t, "gemini_generate.json"),
+	})
+
+	provider, err := refynellm.NewProvider(ProviderGemini, refynellm.ProviderConfig{
+		APIKey:  "test-key",
+		BaseURL: server.URL,
+		Model:   "gemini-2.5-flash",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+
+	resp, err := provider.Execute(context.Background(), extractionRequest(false))
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if resp.Content != `{"title": "Blue Widget", "price": 19.99}` {
+		t.Errorf("Content = %q", resp.Content)
+	}
+	if resp.FinishReason != "stop" {
+		t.Errorf("FinishReason = %q, want stop", resp.FinishReason)
+	}
+	if resp.Usage.InputTokens != 1200 || resp.Usage.OutputTokens != 24 {
+		t.Errorf("Usage = %+v, want 1200/24", resp.Usage)
+	}
+	// gemini-2.5-flash: $0.30/1M input, $2.50/1M output
+	if want := 1200*0.30/1e6 + 24*2.50/1e6; !resp.CostIncluded || !floatEquals(resp.Cost, want) {
+		t.Errorf("Cost = %v (included %v), want %v", resp.Cost, resp.CostIncluded, want)
+	}
+
+	req := (*requests)[0]
+	if got := req.Header.Get("x-goog-api-key"); got != "test-key" {
+		t.Errorf("x-goog-api-key = %q, want test-key", got)
+	}
+	if _, ok := req.Body["systemInstruction"]; !ok {
+		t.Error("system message should be sent as systemInstruction")
+	}
+	genConfig, _ := req.Body["generationConfig"].(map[string]any)
+	if genConfig["responseMimeType"] != "application/json" || genConfig["responseJsonSchema"] == nil {
+		t.Errorf("expected JSON schema output in generationConfig, got %v", genConfig)
+	}
+	if genConfig["maxOutputTokens"] != float64(1024) {
+		t.Errorf("maxOutputTokens = %v, want 1024", genConfig["maxOutputTokens"])
+	}
+}
+
+func TestGeminiProvider_RequiresKey(t *testing.T) {
+	provider, err := refynellm.NewProvider(ProviderGemini, refynellm.ProviderConfig{Model: "gemini-2.5-flash"})
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	if _, err := provider.Execute(context.Background(), extractionRequest(false)); err == nil {
+		t.Error("expected error without API key")
+	}
+}
+
+func TestOpenAICompatibleProvider_Execute(t *testing.T) {
+	tests := []struct {
+		name          string
+		provider      string
+		model         string
+		apiKey        string
+		strict        bool
+		wantFormat    string
+		wantAuth      string
+		wantCostMatch float64
+	}{
+		{
+			name:          "mistral strict uses json_schema",
+			provider:      ProviderMistral,
+			model:         "mistral-small-latest",
+			apiKey:        "test-key",
+			strict:        true,
+			wantFormat:    "json_schema",
+			wantAuth:      "Bearer test-key",
+			wantCostMatch: 1000*0.10/1e6 + 20*0.30/1e6,
+		},
+		{
+			name:          "mistral non-strict downgrades to json_object",
+			provider:      ProviderMistral,
+			model:         "mistral-small-latest",
+			apiKey:        "test-key",
+			strict:        false,
+			wantFormat:    "json_object",
+			wantAuth:      "Bearer test-key",
+			wantCostMatch: 1000*0.10/1e6 + 20*0.30/1e6,
+		},
+		{
+			name:          "groq model without json_schema uses json_object",
+			provider:      ProviderGroq,
+			model:         "llama-3.3-70b-versatile",
+			apiKey:        "gsk_test",
+			strict:        true,
+			wantFormat:    "json_object",
+			wantAuth:      "Bearer gsk_test",
+			wantCostMatch: 1000*0.59/1e6 + 20*0.79/1e6,
+		},
+		{
+			name:          "self-hosted without key",
+			provider:      ProviderOpenAICompatible,
+			model:         "Qwen/Qwen2.5-7B-Instruct",
+			strict:        true,
+			wantFormat:    "json_schema",
+			wantAuth:      "",
+			wantCostMatch: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := fixtureServer(t, map[string]func(*http.Request) []byte{
+				"/v1/chat/completions": serve(t, "openai_chat_completion.json"),
+			})
+
+			provider, err := refynellm.NewProvider(tt.provider, refynellm.ProviderConfig{
+				APIKey:  tt.apiKey,
+				BaseURL: server.URL + "/v1",
+				Model:   tt.model,
+			})
+			if err != nil {
+				t.Fatalf("NewProvider failed: %v", err)
+			}
+
+			resp, err := provider.Execute(context.Background(), extractionRequest(tt.strict))
+			if err != nil {
+				t.Fatalf("Execute failed: %v", err)
+			}
+			if resp.Content != `{"title": "Blue Widget", "price": 19.99}` || resp.FinishReason != "stop" {
+				t.Errorf("unexpected response: %+v", resp)
+			}
+			if resp.Usage.InputTokens != 1000 || resp.Usage.OutputTokens != 20 {
+				t.Errorf("Usage = %+v, want 1000/20", resp.Usage)
+			}
+			if !floatEquals(resp.Cost, tt.wantCostMatch) {
+				t.Errorf("Cost = %v, want %v", resp.Cost, tt.wantCostMatch)
+			}
+
+			req := (*requests)[0]
+			if got := req.Header.Get("Authorization"); got != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", got, tt.wantAuth)
+			}
+			if req.Body["model"] != tt.model {
+				t.Errorf("model = %v, want %s", req.Body["model"], tt.model)
+			}
+			format, _ := req.Body["response_format"].(map[string]any)
+			if format["type"] != tt.wantFormat {
+				t.Errorf("response_format.type = %v, want %s", format["type"], tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestOpenAICompatibleProvider_RequiresKey(t *testing.T) {
+	provider, err := refynellm.NewProvider(ProviderTogether, refynellm.ProviderConfig{Model: "meta-llama/Llama-3.3-70B-Instruct-Turbo"})
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	if _, err := provider.Execute(context.Background(), extractionRequest(true)); err == nil {
+		t.Error("expected error without API key")
+	}
+}
+
+func TestOpenAICompatibleProvider_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":{"message":"rate limit exceeded"}}`, http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	provider, err := refynellm.NewProvider(ProviderGroq, refynellm.ProviderConfig{
+		APIKey:  "gsk_test",
+		BaseURL: server.URL,
+		Model:   "llama-3.1-8b-instant",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+
+	_, err = provider.Execute(context.Background(), extractionRequest(true))
+	if err == nil {
+		t.Fatal("expected error for 429 response")
+	}
+	// The status must survive so error classification can detect rate limits
+	if llmErr := WrapError(err, ProviderGroq, "llama-3.1-8b-instant", false); llmErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("classified status = %d, want 429 (err: %v)", llmErr.StatusCode, err)
+	}
+}
@@ -0,0 +1,108 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	refynellm "github.com/jmylchreest/refyne/pkg/llm"
+)
+
+// maxProviderResponseSize caps provider API responses read into memory.
+const maxProviderResponseSize = 32 * 1024 * 1024
+
+// providerHTTPClient is used for model listing, which should fail fast and fall back
+// to configured models.
+var providerHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// getJSON performs a GET request and decodes the JSON response into out.
+func getJSON(ctx context.Context, url string, headers map[string]string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := providerHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxProviderResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status: %d: %s", resp.StatusCode, string(body))
+	}
+	return json.Unmarshal(body, out)
+}
+
+// postJSON performs a POST request with a JSON body and returns the response body.
+// Non-200 responses are returned as errors including the status code.
+func postJSON(ctx context.Context, client *http.Client, url string, body any, headers map[string]string) ([]byte, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxProviderResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status: %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, nil
+}
+
+// bearerHeaders returns an Authorization header for the key, or none without a key.
+func bearerHeaders(apiKey string) map[string]string {
+	if apiKey == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "Bearer " + apiKey}
+}
+
+// splitMessages joins system messages and non-system messages into a system
+// instruction and a prompt, for APIs that take them separately.
+func splitMessages(messages []refynellm.Message) (system, prompt string) {
+	var sys, user []string
+	for _, m := range messages {
+		if m.Role == refynellm.RoleSystem {
+			sys = append(sys, m.Content)
+		} else {
+			user = append(user, m.Content)
+		}
+	}
+	return strings.Join(sys, "\n\n"), strings.Join(user, "\n\n")
+}
+
+// TrimOpenAIBaseURL removes a trailing slash and "/v1" from a user-supplied base URL,
+// since endpoints add the API version themselves.
+func TrimOpenAIBaseURL(baseURL string) string {
+	return strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1")
+}
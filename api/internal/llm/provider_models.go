@@ -146,6 +146,7 @@ func (p *ProviderModelsLoader) refresh(ctx context.Context) {
 					SupportsTools:             m.Capabilities.SupportsTools,
 					SupportsStreaming:         m.Capabilities.SupportsStreaming,
 					SupportsReasoning:         m.Capabilities.SupportsReasoning,
+					SupportsVision:            m.Capabilities.SupportsVision,
 				},
 				DefaultTemp:      GetDefaultSettings(providerName, m.ID).Temperature,
 				DefaultMaxTokens: GetDefaultSettings(providerName, m.ID).MaxTokens,
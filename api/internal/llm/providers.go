@@ -3,7 +3,9 @@ package llm
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/jmylchreest/refyne-api/internal/config"
 	"github.com/jmylchreest/refyne-api/internal/constants"
@@ -33,6 +35,7 @@ func InitRegistry(cfg *config.Config, logger *slog.Logger) *Registry {
 			ChatEndpoint:         "/v1/chat/completions",
 			AuthType:             AuthTypeBearer,
 			APIFormat:            APIFormatOpenAI,
+			StructuredOutput:     StructuredOutputJSONSchema,
 			AllowBaseURLOverride: false,
 			ExtraHeaders: map[string]string{
 				"HTTP-Referer": "https://refyne.io",
@@ -65,6 +68,7 @@ func InitRegistry(cfg *config.Config, logger *slog.Logger) *Registry {
 			AuthType:             AuthTypeAPIKey,
 			AuthHeader:           "x-api-key",
 			APIFormat:            APIFormatAnthropic,
+			StructuredOutput:     StructuredOutputToolUse,
 			AllowBaseURLOverride: false,
 			ExtraHeaders: map[string]string{
 				"anthropic-version": "2023-06-01",
@@ -95,6 +99,7 @@ func InitRegistry(cfg *config.Config, logger *slog.Logger) *Registry {
 			ChatEndpoint:         "/v1/chat/completions",
 			AuthType:             AuthTypeBearer,
 			APIFormat:            APIFormatOpenAI,
+			StructuredOutput:     StructuredOutputJSONSchema,
 			AllowBaseURLOverride: false,
 			// OpenAI has static pricing (no public pricing API)
 			SupportsPricing:        true,
@@ -122,6 +127,7 @@ func InitRegistry(cfg *config.Config, logger *slog.Logger) *Registry {
 			ChatEndpoint:         "/api/chat",
 			AuthType:             AuthTypeNone,
 			APIFormat:            APIFormatOllama,
+			StructuredOutput:     StructuredOutputOllamaFormat,
 			AllowBaseURLOverride: true, // Self-hosted, allow custom URLs
 			// Ollama is free (local), no pricing needed
 			SupportsPricing:        false,
@@ -150,6 +156,7 @@ func InitRegistry(cfg *config.Config, logger *slog.Logger) *Registry {
 			ChatEndpoint:         "/v1/chat/completions",
 			AuthType:             AuthTypeBearer,
 			APIFormat:            APIFormatOpenAI,
+			StructuredOutput:     StructuredOutputJSONSchema,
 			AllowBaseURLOverride: true, // Self-hostable
 			// Helicone credits mode has full pricing support (like OpenRouter)
 			// Models API: GET /v1/public/model-registry/models (public, no auth)
@@ -161,6 +168,149 @@ func InitRegistry(cfg *config.Config, logger *slog.Logger) *Registry {
 		Status: ProviderStatusActive,
 	})
 
+	// Google Gemini - native generateContent API
+	r.Register("gemini", ProviderRegistration{
+		Info: ProviderInfo{
+			Name:           "gemini",
+			DisplayName:    "Google Gemini",
+			Description:    "Gemini 2.5 Pro, Gemini 2.5 Flash, and other Google models",
+			RequiresKey:    true,
+			KeyPlaceholder: "AIza...",
+			DocsURL:        "https://ai.google.dev/gemini-api/docs",
+		},
+		RequiredFeatures: nil,
+		ListModels:       listGeminiModels,
+		GetCapabilities:  getGeminiCapabilities,
+		APIConfig: ProviderAPIConfig{
+			BaseURL:              GeminiBaseURL,
+			ChatEndpoint:         GeminiChatEndpoint,
+			AuthType:             AuthTypeAPIKey,
+			AuthHeader:           "x-goog-api-key",
+			APIFormat:            APIFormatGemini,
+			StructuredOutput:     StructuredOutputResponseSchema,
+			AllowBaseURLOverride: false,
+			// Gemini has static pricing (no public pricing API)
+			SupportsPricing:        true,
+			SupportsGenerationCost: false,
+			SupportsDynamicPricing: false,
+		},
+		Status: ProviderStatusActive,
+	})
+
+	// Mistral AI - OpenAI-compatible API
+	r.Register("mistral", ProviderRegistration{
+		Info: ProviderInfo{
+			Name:           "mistral",
+			DisplayName:    "Mistral AI",
+			Description:    "Mistral Large, Mistral Small, and other Mistral models",
+			RequiresKey:    true,
+			KeyPlaceholder: "...",
+			DocsURL:        "https://docs.mistral.ai",
+		},
+		RequiredFeatures: nil,
+		ListModels:       listMistralModels,
+		GetCapabilities:  getMistralCapabilities,
+		APIConfig: ProviderAPIConfig{
+			BaseURL:              MistralBaseURL,
+			ChatEndpoint:         openAICompatibleChatEndpoint,
+			AuthType:             AuthTypeBearer,
+			APIFormat:            APIFormatOpenAI,
+			StructuredOutput:     StructuredOutputJSONSchema,
+			AllowBaseURLOverride: false,
+			// Mistral has static pricing (no public pricing API)
+			SupportsPricing:        true,
+			SupportsGenerationCost: false,
+			SupportsDynamicPricing: false,
+		},
+		Status: ProviderStatusActive,
+	})
+
+	// Groq - OpenAI-compatible API on LPU inference hardware
+	r.Register("groq", ProviderRegistration{
+		Info: ProviderInfo{
+			Name:           "groq",
+			DisplayName:    "Groq",
+			Description:    "Fast inference for Llama, GPT-OSS, Qwen, and other open models",
+			RequiresKey:    true,
+			KeyPlaceholder: "gsk_...",
+			DocsURL:        "https://console.groq.com/docs",
+		},
+		RequiredFeatures: nil,
+		ListModels:       listGroqModels,
+		GetCapabilities:  getGroqCapabilities,
+		APIConfig: ProviderAPIConfig{
+			BaseURL:              GroqBaseURL,
+			ChatEndpoint:         openAICompatibleChatEndpoint,
+			AuthType:             AuthTypeBearer,
+			APIFormat:            APIFormatOpenAI,
+			StructuredOutput:     StructuredOutputJSONSchema,
+			AllowBaseURLOverride: false,
+			// Groq has static pricing (no public pricing API)
+			SupportsPricing:        true,
+			SupportsGenerationCost: false,
+			SupportsDynamicPricing: false,
+		},
+		Status: ProviderStatusActive,
+	})
+
+	// Together AI - OpenAI-compatible API for open models
+	r.Register("together", ProviderRegistration{
+		Info: ProviderInfo{
+			Name:           "together",
+			DisplayName:    "Together AI",
+			Description:    "Llama, Qwen, DeepSeek, and other open models",
+			RequiresKey:    true,
+			KeyPlaceholder: "tgp_v1_...",
+			DocsURL:        "https://docs.together.ai",
+		},
+		RequiredFeatures: nil,
+		ListModels:       listTogetherModels,
+		GetCapabilities:  getTogetherCapabilities,
+		APIConfig: ProviderAPIConfig{
+			BaseURL:              TogetherBaseURL,
+			ChatEndpoint:         openAICompatibleChatEndpoint,
+			AuthType:             AuthTypeBearer,
+			APIFormat:            APIFormatOpenAI,
+			StructuredOutput:     StructuredOutputJSONSchema,
+			AllowBaseURLOverride: false,
+			// Together publishes per-model pricing in its models API; estimates use the
+			// model pricing configuration
+			SupportsPricing:        true,
+			SupportsGenerationCost: false,
+			SupportsDynamicPricing: false,
+		},
+		Status: ProviderStatusActive,
+	})
+
+	// OpenAI-compatible - self-hosted servers (vLLM, LM Studio, llama.cpp)
+	// Shares the provider_ollama feature with the other local LLM provider
+	r.Register("openai_compatible", ProviderRegistration{
+		Info: ProviderInfo{
+			Name:        "openai_compatible",
+			DisplayName: "OpenAI-Compatible",
+			Description: "Self-hosted OpenAI-compatible servers (vLLM, LM Studio, llama.cpp)",
+			RequiresKey: false,
+			BaseURLHint: "http://localhost:8000 (vLLM), http://localhost:1234 (LM Studio), http://localhost:8080 (llama.cpp)",
+			DocsURL:     "https://platform.openai.com/docs/api-reference/chat",
+		},
+		RequiredFeatures: []string{constants.FeatureProviderOllama},
+		ListModels:       listOpenAICompatibleModels,
+		GetCapabilities:  getOpenAICompatibleCapabilities,
+		APIConfig: ProviderAPIConfig{
+			BaseURL:              OpenAICompatibleBaseURL,
+			ChatEndpoint:         openAICompatibleChatEndpoint,
+			AuthType:             AuthTypeBearer, // Optional; sent only when a key is configured
+			APIFormat:            APIFormatOpenAI,
+			StructuredOutput:     StructuredOutputJSONSchema,
+			AllowBaseURLOverride: true, // Self-hosted, allow custom URLs
+			// Self-hosted, no pricing needed
+			SupportsPricing:        false,
+			SupportsGenerationCost: false,
+			SupportsDynamicPricing: false,
+		},
+		Status: ProviderStatusActive,
+	})
+
 	return r
 }
 
@@ -217,9 +367,23 @@ func getStaticOpenRouterCapabilities(model string) ModelCapabilities {
 		"google/gemini-2.0-flash-001":   true,
 	}
 
+	// Until pricing is fetched, assume vision from the upstream provider's naming
+	vision := false
+	if upstream, id, ok := strings.Cut(model, "/"); ok {
+		switch upstream {
+		case "anthropic":
+			vision = true
+		case "openai":
+			vision = isOpenAIVisionModel(id)
+		case "google":
+			vision = strings.HasPrefix(id, "gemini")
+		}
+	}
+
 	return ModelCapabilities{
 		SupportsStructuredOutputs: structuredOutputModels[model],
 		SupportsStreaming:         true, // OpenRouter always supports streaming
+		SupportsVision:            vision,
 	}
 }
 
@@ -285,6 +449,7 @@ func getAnthropicCapabilities(_ context.Context, _ string) ModelCapabilities {
 		SupportsStreaming:         true,
 		SupportsReasoning:         false,
 		SupportsResponseFormat:    false,
+		SupportsVision:            true, // All current Claude models accept images
 	}
 }
 
@@ -309,21 +474,43 @@ func getOpenAICapabilities(_ context.Context, model string) ModelCapabilities {
 		SupportsStreaming:         true,
 		SupportsReasoning:         reasoningModels[model],
 		SupportsResponseFormat:    true,
+		SupportsVision:            isOpenAIVisionModel(model),
+	}
+}
+
+// isOpenAIVisionModel reports whether an OpenAI model accepts image inputs.
+// GPT-4o and later accept images, as do the full o-series reasoning models.
+func isOpenAIVisionModel(model string) bool {
+	for _, prefix := range []string{"gpt-4o", "gpt-4.1", "gpt-4-turbo", "gpt-4.5", "gpt-5", "o4-mini", "chatgpt-4o"} {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
 	}
+	return model == "o1" || model == "o3" || strings.HasPrefix(model, "o1-2") || strings.HasPrefix(model, "o3-2")
 }
 
 // getOllamaCapabilities returns capabilities for Ollama models.
-// Most Ollama models support streaming but not structured outputs.
-func getOllamaCapabilities(_ context.Context, _ string) ModelCapabilities {
+// Ollama constrains output to a JSON Schema passed as the format parameter.
+func getOllamaCapabilities(_ context.Context, model string) ModelCapabilities {
 	return ModelCapabilities{
-		SupportsStructuredOutputs: false,
+		SupportsStructuredOutputs: true, // JSON Schema via format
 		SupportsTools:             false,
 		SupportsStreaming:         true,
 		SupportsReasoning:         false,
 		SupportsResponseFormat:    false,
+		SupportsVision:            isOllamaVisionModel(model),
 	}
 }
 
+// ollamaVisionFamilies are Ollama model families that accept images.
+var ollamaVisionFamilies = []string{"llava", "bakllava", "llama3.2-vision", "llama4", "gemma3", "qwen2.5vl", "qwen3-vl", "minicpm-v", "moondream", "granite3.2-vision", "mistral-small3.1"}
+
+// isOllamaVisionModel reports whether an Ollama model (e.g., "llava:13b") accepts images.
+func isOllamaVisionModel(model string) bool {
+	family, _, _ := strings.Cut(strings.ToLower(model), ":")
+	return slices.Contains(ollamaVisionFamilies, family)
+}
+
 // listHeliconeModels fetches models from Helicone's public model registry.
 // Uses refyne's ListHeliconeModels which calls the public API (no auth required).
 func listHeliconeModels(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
@@ -354,3 +541,235 @@ func getHeliconeCapabilities(_ context.Context, model string) ModelCapabilities
 		SupportsResponseFormat:    true,
 	}
 }
+
+// listGeminiModels fetches models from the Gemini API, keeping those that support
+// generateContent. Falls back to S3-backed config without a key or on error.
+func listGeminiModels(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
+	if apiKey == "" {
+		return GlobalProviderModels().GetModels("gemini"), nil
+	}
+	if baseURL == "" {
+		baseURL = GeminiBaseURL
+	}
+
+	var result []ModelInfo
+	pageToken := ""
+	for {
+		var page struct {
+			Models []struct {
+				Name                       string   `json:"name"` // "models/gemini-2.5-flash"
+				DisplayName                string   `json:"displayName"`
+				InputTokenLimit            int      `json:"inputTokenLimit"`
+				OutputTokenLimit           int      `json:"outputTokenLimit"`
+				SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+			} `json:"models"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		url := strings.TrimRight(baseURL, "/") + geminiAPIVersion + "/models?pageSize=1000"
+		if pageToken != "" {
+			url += "&pageToken=" + pageToken
+		}
+		if err := getJSON(ctx, url, map[string]string{"x-goog-api-key": apiKey}, &page); err != nil {
+			return GlobalProviderModels().GetModels("gemini"), nil
+		}
+
+		for _, m := range page.Models {
+			if !slices.Contains(m.SupportedGenerationMethods, "generateContent") {
+				continue
+			}
+			id := strings.TrimPrefix(m.Name, "models/")
+			settings := GetDefaultSettings("gemini", id)
+			result = append(result, ModelInfo{
+				ID:                  id,
+				Name:                m.DisplayName,
+				Provider:            "gemini",
+				ContextWindow:       m.InputTokenLimit,
+				MaxCompletionTokens: m.OutputTokenLimit,
+				Capabilities:        getGeminiCapabilities(ctx, id),
+				DefaultTemp:         settings.Temperature,
+				DefaultMaxTokens:    settings.MaxTokens,
+			})
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return result, nil
+}
+
+// listMistralModels fetches chat models from Mistral's models API (requires auth).
+func listMistralModels(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
+	return listOpenAICompatibleProviderModels(ctx, "mistral", MistralBaseURL, baseURL, apiKey, getMistralCapabilities)
+}
+
+// listGroqModels fetches active chat models from Groq's models API (requires auth).
+func listGroqModels(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
+	return listOpenAICompatibleProviderModels(ctx, "groq", GroqBaseURL, baseURL, apiKey, getGroqCapabilities)
+}
+
+// listOpenAICompatibleProviderModels lists models for a hosted OpenAI-compatible provider.
+// Falls back to S3-backed config without a key or on error.
+func listOpenAICompatibleProviderModels(ctx context.Context, provider, defaultBaseURL, baseURL, apiKey string, getCaps CapabilitiesLookup) ([]ModelInfo, error) {
+	if apiKey == "" {
+		return GlobalProviderModels().GetModels(provider), nil
+	}
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	models, err := fetchOpenAICompatibleModels(ctx, provider, baseURL, apiKey, getCaps)
+	if err != nil || len(models) == 0 {
+		return GlobalProviderModels().GetModels(provider), nil
+	}
+	sortModelsByID(models)
+	return models, nil
+}
+
+// listTogetherModels fetches chat models from Together's models API (requires auth).
+// Unlike other OpenAI-compatible APIs, it returns a bare array that includes pricing.
+func listTogetherModels(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
+	if apiKey == "" {
+		return GlobalProviderModels().GetModels("together"), nil
+	}
+	if baseURL == "" {
+		baseURL = TogetherBaseURL
+	}
+
+	var models []struct {
+		ID            string `json:"id"`
+		DisplayName   string `json:"display_name"`
+		Type          string `json:"type"` // "chat", "language", "embedding", "image", ...
+		ContextLength int    `json:"context_length"`
+	}
+	if err := getJSON(ctx, TrimOpenAIBaseURL(baseURL)+"/v1/models", bearerHeaders(apiKey), &models); err != nil || len(models) == 0 {
+		return GlobalProviderModels().GetModels("together"), nil
+	}
+
+	result := make([]ModelInfo, 0, len(models))
+	for _, m := range models {
+		if m.Type != "chat" {
+			continue
+		}
+		name := m.DisplayName
+		if name == "" {
+			name = m.ID
+		}
+		settings := GetDefaultSettings("together", m.ID)
+		result = append(result, ModelInfo{
+			ID:               m.ID,
+			Name:             name,
+			Provider:         "together",
+			ContextWindow:    m.ContextLength,
+			Capabilities:     getTogetherCapabilities(ctx, m.ID),
+			DefaultTemp:      settings.Temperature,
+			DefaultMaxTokens: settings.MaxTokens,
+		})
+	}
+	sortModelsByID(result)
+	return result, nil
+}
+
+// listOpenAICompatibleModels fetches models from a self-hosted server's /v1/models endpoint.
+func listOpenAICompatibleModels(ctx context.Context, baseURL, apiKey string) ([]ModelInfo, error) {
+	if baseURL == "" {
+		baseURL = OpenAICompatibleBaseURL
+	}
+	models, err := fetchOpenAICompatibleModels(ctx, "openai_compatible", baseURL, apiKey, getOpenAICompatibleCapabilities)
+	if err != nil || len(models) == 0 {
+		// Server not running or no models loaded - return models from S3-backed config
+		return GlobalProviderModels().GetModels("openai_compatible"), nil
+	}
+	sortModelsByID(models)
+	return models, nil
+}
+
+// sortModelsByID sorts models by ID for stable listings.
+func sortModelsByID(models []ModelInfo) {
+	sort.Slice(models, func(i, j int) bool {
+		return models[i].ID < models[j].ID
+	})
+}
+
+// getGeminiCapabilities returns capabilities for Gemini models.
+// Gemini constrains output to a JSON Schema via generationConfig.responseJsonSchema.
+func getGeminiCapabilities(_ context.Context, model string) ModelCapabilities {
+	// Gemma models served by the Gemini API don't support JSON mode
+	structured := !strings.HasPrefix(model, "gemma")
+
+	return ModelCapabilities{
+		SupportsStructuredOutputs: structured,
+		SupportsTools:             structured,
+		SupportsStreaming:         true,
+		SupportsReasoning:         strings.HasPrefix(model, "gemini-2.5") || strings.HasPrefix(model, "gemini-3"),
+		SupportsResponseFormat:    false,
+		SupportsVision:            strings.HasPrefix(model, "gemini"),
+	}
+}
+
+// getMistralCapabilities returns capabilities for Mistral models.
+// Mistral supports response_format json_schema on its chat models.
+func getMistralCapabilities(_ context.Context, model string) ModelCapabilities {
+	return ModelCapabilities{
+		SupportsStructuredOutputs: true,
+		SupportsTools:             true,
+		SupportsStreaming:         true,
+		SupportsReasoning:         strings.HasPrefix(model, "magistral"),
+		SupportsResponseFormat:    true,
+		SupportsVision:            strings.HasPrefix(model, "pixtral") || strings.HasPrefix(model, "mistral-medium") || strings.HasPrefix(model, "mistral-small"),
+	}
+}
+
+// getGroqCapabilities returns capabilities for Groq models.
+// All models support json_object; json_schema is limited to a subset of models.
+func getGroqCapabilities(_ context.Context, model string) ModelCapabilities {
+	structuredOutputModels := map[string]bool{
+		"openai/gpt-oss-120b":                           true,
+		"openai/gpt-oss-20b":                            true,
+		"moonshotai/kimi-k2-instruct":                   true,
+		"moonshotai/kimi-k2-instruct-0905":              true,
+		"meta-llama/llama-4-maverick-17b-128e-instruct": true,
+		"meta-llama/llama-4-scout-17b-16e-instruct":     true,
+	}
+	reasoningModels := map[string]bool{
+		"openai/gpt-oss-120b": true,
+		"openai/gpt-oss-20b":  true,
+		"qwen/qwen3-32b":      true,
+	}
+
+	return ModelCapabilities{
+		SupportsStructuredOutputs: structuredOutputModels[model],
+		SupportsTools:             true,
+		SupportsStreaming:         true,
+		SupportsReasoning:         reasoningModels[model],
+		SupportsResponseFormat:    true,
+		SupportsVision:            strings.HasPrefix(model, "meta-llama/llama-4"),
+	}
+}
+
+// getTogetherCapabilities returns capabilities for Together models.
+// Together supports JSON Schema-constrained output on its serverless chat models.
+func getTogetherCapabilities(_ context.Context, model string) ModelCapabilities {
+	lower := strings.ToLower(model)
+	return ModelCapabilities{
+		SupportsStructuredOutputs: true,
+		SupportsTools:             true,
+		SupportsStreaming:         true,
+		SupportsReasoning:         strings.Contains(lower, "deepseek-r1") || strings.Contains(lower, "qwq"),
+		SupportsResponseFormat:    true,
+		SupportsVision:            strings.Contains(lower, "vision") || strings.Contains(lower, "-vl") || strings.Contains(lower, "llama-4"),
+	}
+}
+
+// getOpenAICompatibleCapabilities returns capabilities for self-hosted OpenAI-compatible servers.
+// vLLM, LM Studio and llama.cpp all accept response_format json_schema (via guided decoding
+// or grammars), regardless of the model.
+func getOpenAICompatibleCapabilities(_ context.Context, _ string) ModelCapabilities {
+	return ModelCapabilities{
+		SupportsStructuredOutputs: true,
+		SupportsTools:             false,
+		SupportsStreaming:         true,
+		SupportsReasoning:         false,
+		SupportsResponseFormat:    true,
+	}
+}
@@ -82,8 +82,8 @@ func TestGetOllamaCapabilities(t *testing.T) {
 		t.Run(model, func(t *testing.T) {
 			caps := getOllamaCapabilities(ctx, model)
 
-			if caps.SupportsStructuredOutputs {
-				t.Error("Ollama should not support structured outputs")
+			if !caps.SupportsStructuredOutputs {
+				t.Error("Ollama should support structured outputs via format")
 			}
 			if caps.SupportsTools {
 				t.Error("Ollama should not support tools")
@@ -154,6 +154,42 @@ func TestGetStaticOpenRouterCapabilities(t *testing.T) {
 	}
 }
 
+func TestCapabilities_SupportsVision(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		lookup CapabilitiesLookup
+		model  string
+		expect bool
+	}{
+		{"anthropic", getAnthropicCapabilities, "claude-sonnet-4-5", true},
+		{"openai gpt-4o", getOpenAICapabilities, "gpt-4o-mini", true},
+		{"openai gpt-4.1", getOpenAICapabilities, "gpt-4.1", true},
+		{"openai o1", getOpenAICapabilities, "o1", true},
+		{"openai o3-mini", getOpenAICapabilities, "o3-mini", false},
+		{"openai gpt-3.5", getOpenAICapabilities, "gpt-3.5-turbo", false},
+		{"gemini", getGeminiCapabilities, "gemini-2.5-flash", true},
+		{"gemma", getGeminiCapabilities, "gemma-3-27b-it", false},
+		{"ollama llava", getOllamaCapabilities, "llava:13b", true},
+		{"ollama llama3.2", getOllamaCapabilities, "llama3.2", false},
+		{"mistral pixtral", getMistralCapabilities, "pixtral-large-latest", true},
+		{"mistral codestral", getMistralCapabilities, "codestral-latest", false},
+		{"groq llama-4", getGroqCapabilities, "meta-llama/llama-4-scout-17b-16e-instruct", true},
+		{"groq gpt-oss", getGroqCapabilities, "openai/gpt-oss-120b", false},
+		{"openrouter anthropic", func(_ context.Context, m string) ModelCapabilities { return getStaticOpenRouterCapabilities(m) }, "anthropic/claude-sonnet-4", true},
+		{"openrouter unknown", func(_ context.Context, m string) ModelCapabilities { return getStaticOpenRouterCapabilities(m) }, "unknown/model", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lookup(ctx, tt.model).SupportsVision; got != tt.expect {
+				t.Errorf("SupportsVision(%s) = %v, want %v", tt.model, got, tt.expect)
+			}
+		})
+	}
+}
+
 // ========================================
 // Model Listing Tests
 // ========================================
@@ -21,6 +21,7 @@ func ConvertModelInfo(provider string, rm refynellm.ModelInfo) ModelInfo {
 			SupportsStreaming:         rm.Capabilities.SupportsStreaming,
 			SupportsReasoning:         rm.Capabilities.SupportsReasoning,
 			SupportsResponseFormat:    rm.Capabilities.SupportsResponseFormat,
+			SupportsVision:            rm.Capabilities.SupportsVision,
 		},
 		DefaultTemp:      settings.Temperature,
 		DefaultMaxTokens: settings.MaxTokens,
@@ -44,5 +45,6 @@ func ConvertCapabilities(rc refynellm.ModelCapabilities) ModelCapabilities {
 		SupportsStreaming:         rc.SupportsStreaming,
 		SupportsReasoning:         rc.SupportsReasoning,
 		SupportsResponseFormat:    rc.SupportsResponseFormat,
+		SupportsVision:            rc.SupportsVision,
 	}
 }
@@ -32,6 +32,8 @@ const (
 	APIFormatAnthropic APIFormat = "anthropic"
 	// APIFormatOllama is for Ollama format (message.content).
 	APIFormatOllama APIFormat = "ollama"
+	// APIFormatGemini is for the Gemini generateContent format (candidates[].content.parts[].text).
+	APIFormatGemini APIFormat = "gemini"
 )
 
 // AuthType defines authentication method for a provider.
@@ -61,13 +63,17 @@ const (
 // ProviderAPIConfig contains all API interaction configuration for a provider.
 type ProviderAPIConfig struct {
 	BaseURL              string            // Default base URL (can be overridden by user)
-	ChatEndpoint         string            // Chat completions path (e.g., "/v1/chat/completions")
+	ChatEndpoint         string            // Chat completions path (e.g., "/v1/chat/completions"); "{model}" is replaced with the model
 	AuthType             AuthType          // How to authenticate
 	AuthHeader           string            // Custom auth header name (for AuthTypeAPIKey)
 	ExtraHeaders         map[string]string // Additional static headers
 	APIFormat            APIFormat         // Response parsing format
 	AllowBaseURLOverride bool              // True for self-hostable providers (Ollama, Helicone)
 
+	// StructuredOutput is the provider's native mechanism for constraining output to a
+	// JSON Schema. ModelCapabilities decide whether a given model can use it.
+	StructuredOutput StructuredOutputStrategy
+
 	// Pricing capabilities (uses refyne's CostEstimator interface)
 	SupportsPricing        bool // Provider can estimate costs via refyne's EstimateCost
 	SupportsGenerationCost bool // Provider can fetch actual generation costs
@@ -412,6 +418,11 @@ func (r *Registry) SupportsStructuredOutputs(ctx context.Context, provider, mode
 	return r.GetModelCapabilities(ctx, provider, model).SupportsStructuredOutputs
 }
 
+// SupportsVision is a convenience method to check if a model accepts image inputs.
+func (r *Registry) SupportsVision(ctx context.Context, provider, model string) bool {
+	return r.GetModelCapabilities(ctx, provider, model).SupportsVision
+}
+
 // GetMaxCompletionTokens returns the max output tokens for a model.
 // Returns 0 if unknown (caller should use a default).
 // This is populated by provider APIs (e.g., OpenRouter) or static defaults.
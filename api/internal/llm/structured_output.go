@@ -0,0 +1,216 @@
+package llm
+
+import (
+	"context"
+	"sort"
+)
+
+// StructuredOutputStrategy is how a provider constrains a model to produce JSON.
+type StructuredOutputStrategy string
+
+const (
+	// StructuredOutputNone relies on prompt instructions alone.
+	StructuredOutputNone StructuredOutputStrategy = "none"
+	// StructuredOutputJSONObject sends response_format: json_object (valid JSON, any shape).
+	StructuredOutputJSONObject StructuredOutputStrategy = "json_object"
+	// StructuredOutputJSONSchema sends response_format: json_schema with strict mode (OpenAI-compatible).
+	StructuredOutputJSONSchema StructuredOutputStrategy = "json_schema"
+	// StructuredOutputToolUse forces a tool call whose input_schema is the JSON Schema (Anthropic).
+	StructuredOutputToolUse StructuredOutputStrategy = "tool_use"
+	// StructuredOutputOllamaFormat sends the JSON Schema as Ollama's format parameter.
+	StructuredOutputOllamaFormat StructuredOutputStrategy = "ollama_format"
+	// StructuredOutputResponseSchema sends the JSON Schema as Gemini's generationConfig.responseJsonSchema.
+	StructuredOutputResponseSchema StructuredOutputStrategy = "response_schema"
+)
+
+// ResolveStructuredOutput returns the strategy to use for a model, given the strategy its
+// provider declares and the model's capabilities. Models that lack the capability the
+// declared strategy needs fall back to a weaker strategy, or to prompt instructions.
+func ResolveStructuredOutput(declared StructuredOutputStrategy, caps ModelCapabilities) StructuredOutputStrategy {
+	switch declared {
+	case StructuredOutputJSONSchema:
+		if caps.SupportsStructuredOutputs {
+			return StructuredOutputJSONSchema
+		}
+		if caps.SupportsResponseFormat {
+			return StructuredOutputJSONObject
+		}
+	case StructuredOutputJSONObject:
+		if caps.SupportsResponseFormat {
+			return StructuredOutputJSONObject
+		}
+	case StructuredOutputToolUse:
+		if caps.SupportsTools {
+			return StructuredOutputToolUse
+		}
+	case StructuredOutputOllamaFormat:
+		if caps.SupportsStructuredOutputs {
+			return StructuredOutputOllamaFormat
+		}
+	case StructuredOutputResponseSchema:
+		if caps.SupportsStructuredOutputs {
+			return StructuredOutputResponseSchema
+		}
+	}
+	return StructuredOutputNone
+}
+
+// StructuredOutputStrategy returns the structured output strategy for a provider and model.
+// Unknown providers and providers that declare no strategy use prompt instructions.
+func (r *Registry) StructuredOutputStrategy(ctx context.Context, provider, model string) StructuredOutputStrategy {
+	r.mu.RLock()
+	reg, ok := r.providers[provider]
+	r.mu.RUnlock()
+
+	if !ok || reg.APIConfig.StructuredOutput == "" {
+		return StructuredOutputNone
+	}
+	return ResolveStructuredOutput(reg.APIConfig.StructuredOutput, r.GetModelCapabilities(ctx, provider, model))
+}
+
+// StructuredOutputTool is the tool name used to force structured output via tool_use.
+const StructuredOutputTool = "extract_data"
+
+// ApplyStructuredOutput adds the request parameters for a structured output strategy.
+// Without a JSON Schema, each strategy asks for any valid JSON object.
+func ApplyStructuredOutput(reqBody map[string]any, strategy StructuredOutputStrategy, jsonSchema map[string]any) {
+	switch strategy {
+	case StructuredOutputJSONSchema:
+		if jsonSchema == nil {
+			reqBody["response_format"] = map[string]string{"type": "json_object"}
+			return
+		}
+		reqBody["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   "extraction",
+				"strict": true,
+				"schema": StrictJSONSchema(jsonSchema),
+			},
+		}
+	case StructuredOutputJSONObject:
+		reqBody["response_format"] = map[string]string{"type": "json_object"}
+	case StructuredOutputToolUse:
+		inputSchema := jsonSchema
+		if inputSchema == nil {
+			inputSchema = map[string]any{"type": "object"}
+		}
+		reqBody["tools"] = []map[string]any{{
+			"name":         StructuredOutputTool,
+			"description":  "Return the extracted data",
+			"input_schema": inputSchema,
+		}}
+		reqBody["tool_choice"] = map[string]string{"type": "tool", "name": StructuredOutputTool}
+	case StructuredOutputOllamaFormat:
+		if jsonSchema == nil {
+			reqBody["format"] = "json"
+			return
+		}
+		reqBody["format"] = jsonSchema
+	case StructuredOutputResponseSchema:
+		genCfg, _ := reqBody["generationConfig"].(map[string]any)
+		if genCfg == nil {
+			genCfg = make(map[string]any)
+			reqBody["generationConfig"] = genCfg
+		}
+		genCfg["responseMimeType"] = "application/json"
+		if jsonSchema != nil {
+			genCfg["responseJsonSchema"] = jsonSchema
+		}
+	}
+}
+
+// StrictJSONSchema returns a copy of a JSON Schema that satisfies OpenAI's strict mode:
+// every object lists all of its properties as required and disallows additional
+// properties. Properties that were optional become nullable so the model can omit them.
+// Array items, anyOf alternatives and $defs definitions are made strict as well.
+func StrictJSONSchema(schema map[string]any) map[string]any {
+	out := make(map[string]any, len(schema)+2)
+	for k, v := range schema {
+		out[k] = v
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		out["items"] = StrictJSONSchema(items)
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		strictAnyOf := make([]any, len(anyOf))
+		for i, sub := range anyOf {
+			if subSchema, ok := sub.(map[string]any); ok {
+				sub = StrictJSONSchema(subSchema)
+			}
+			strictAnyOf[i] = sub
+		}
+		out["anyOf"] = strictAnyOf
+	}
+	if defs, ok := schema["$defs"].(map[string]any); ok {
+		strictDefs := make(map[string]any, len(defs))
+		for name, def := range defs {
+			if defSchema, ok := def.(map[string]any); ok {
+				def = StrictJSONSchema(defSchema)
+			}
+			strictDefs[name] = def
+		}
+		out["$defs"] = strictDefs
+	}
+
+	properties, ok := schema["properties"].(map[string]any)
+	if !ok {
+		return out
+	}
+
+	required := make(map[string]bool)
+	switch req := schema["required"].(type) {
+	case []string:
+		for _, name := range req {
+			required[name] = true
+		}
+	case []any:
+		for _, name := range req {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	strictProps := make(map[string]any, len(properties))
+	allRequired := make([]string, 0, len(properties))
+	for name, prop := range properties {
+		propSchema, ok := prop.(map[string]any)
+		if !ok {
+			strictProps[name] = prop
+			allRequired = append(allRequired, name)
+			continue
+		}
+		propSchema = StrictJSONSchema(propSchema)
+		if !required[name] {
+			propSchema = nullable(propSchema)
+		}
+		strictProps[name] = propSchema
+		allRequired = append(allRequired, name)
+	}
+	sort.Strings(allRequired)
+
+	out["properties"] = strictProps
+	out["required"] = allRequired
+	out["additionalProperties"] = false
+	return out
+}
+
+// nullable widens a schema's type to also allow null.
+func nullable(schema map[string]any) map[string]any {
+	switch t := schema["type"].(type) {
+	case string:
+		if t != "null" {
+			schema["type"] = []any{t, "null"}
+		}
+	case []any:
+		for _, v := range t {
+			if v == "null" {
+				return schema
+			}
+		}
+		schema["type"] = append(append([]any{}, t...), "null")
+	}
+	return schema
+}
@@ -0,0 +1,164 @@
+package llm
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/jmylchreest/refyne-api/internal/config"
+)
+
+// ========================================
+// ResolveStructuredOutput Tests
+// ========================================
+
+func TestResolveStructuredOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared StructuredOutputStrategy
+		caps     ModelCapabilities
+		want     StructuredOutputStrategy
+	}{
+		{"json_schema supported", StructuredOutputJSONSchema, ModelCapabilities{SupportsStructuredOutputs: true, SupportsResponseFormat: true}, StructuredOutputJSONSchema},
+		{"json_schema falls back to json_object", StructuredOutputJSONSchema, ModelCapabilities{SupportsResponseFormat: true}, StructuredOutputJSONObject},
+		{"json_schema unsupported", StructuredOutputJSONSchema, ModelCapabilities{}, StructuredOutputNone},
+		{"tool_use supported", StructuredOutputToolUse, ModelCapabilities{SupportsTools: true}, StructuredOutputToolUse},
+		{"tool_use unsupported", StructuredOutputToolUse, ModelCapabilities{SupportsStructuredOutputs: true}, StructuredOutputNone},
+		{"ollama format supported", StructuredOutputOllamaFormat, ModelCapabilities{SupportsStructuredOutputs: true}, StructuredOutputOllamaFormat},
+		{"ollama format unsupported", StructuredOutputOllamaFormat, ModelCapabilities{}, StructuredOutputNone},
+		{"response schema supported", StructuredOutputResponseSchema, ModelCapabilities{SupportsStructuredOutputs: true}, StructuredOutputResponseSchema},
+		{"response schema unsupported", StructuredOutputResponseSchema, ModelCapabilities{}, StructuredOutputNone},
+		{"none declared", "", ModelCapabilities{SupportsStructuredOutputs: true, SupportsTools: true}, StructuredOutputNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveStructuredOutput(tt.declared, tt.caps); got != tt.want {
+				t.Errorf("ResolveStructuredOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_StructuredOutputStrategy(t *testing.T) {
+	r := InitRegistry(&config.Config{}, slog.Default())
+	ctx := context.Background()
+
+	tests := []struct {
+		provider string
+		model    string
+		want     StructuredOutputStrategy
+	}{
+		{"openai", "gpt-4o", StructuredOutputJSONSchema},
+		{"openai", "gpt-3.5-turbo", StructuredOutputJSONObject},
+		{"anthropic", "claude-sonnet-4-20250514", StructuredOutputToolUse},
+		{"ollama", "llama3.2", StructuredOutputOllamaFormat},
+		{"helicone", "gpt-4o", StructuredOutputJSONSchema},
+		{"gemini", "gemini-2.5-flash", StructuredOutputResponseSchema},
+		{"mistral", "mistral-large-latest", StructuredOutputJSONSchema},
+		{"groq", "llama-3.1-8b-instant", StructuredOutputJSONObject},
+		{"together", "meta-llama/Llama-3.3-70B-Instruct-Turbo", StructuredOutputJSONSchema},
+		{"openai_compatible", "qwen2.5-7b-instruct", StructuredOutputJSONSchema},
+		{"unknown", "model", StructuredOutputNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+tt.model, func(t *testing.T) {
+			if got := r.StructuredOutputStrategy(ctx, tt.provider, tt.model); got != tt.want {
+				t.Errorf("StructuredOutputStrategy() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// ========================================
+// StrictJSONSchema Tests
+// ========================================
+
+func TestStrictJSONSchema(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"title": map[string]any{"type": "string"},
+			"price": map[string]any{"type": "number"},
+			"tags": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+					},
+				},
+			},
+		},
+		"required": []any{"title"},
+	}
+
+	got := StrictJSONSchema(schema)
+
+	if got["additionalProperties"] != false {
+		t.Error("additionalProperties should be false")
+	}
+	if want := []string{"price", "tags", "title"}; !reflect.DeepEqual(got["required"], want) {
+		t.Errorf("required = %v, want %v", got["required"], want)
+	}
+
+	props := got["properties"].(map[string]any)
+	if typ := props["title"].(map[string]any)["type"]; typ != "string" {
+		t.Errorf("required property type = %v, want string", typ)
+	}
+	if typ := props["price"].(map[string]any)["type"]; !reflect.DeepEqual(typ, []any{"number", "null"}) {
+		t.Errorf("optional property type = %v, want [number null]", typ)
+	}
+
+	items := props["tags"].(map[string]any)["items"].(map[string]any)
+	if items["additionalProperties"] != false || !reflect.DeepEqual(items["required"], []string{"name"}) {
+		t.Errorf("nested object not made strict: %v", items)
+	}
+
+	// The input schema is not modified
+	if _, ok := schema["additionalProperties"]; ok {
+		t.Error("StrictJSONSchema should not modify its input")
+	}
+	if typ := schema["properties"].(map[string]any)["price"].(map[string]any)["type"]; typ != "number" {
+		t.Errorf("input property type modified to %v", typ)
+	}
+}
+
+func TestStrictJSONSchema_Definitions(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"root": map[string]any{"$ref": "#/$defs/node"},
+		},
+		"required": []any{"root"},
+		"$defs": map[string]any{
+			"node": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name": map[string]any{"type": "string"},
+					"child": map[string]any{"anyOf": []any{
+						map[string]any{"$ref": "#/$defs/node"},
+						map[string]any{"type": "null"},
+					}},
+				},
+				"required": []any{"child"},
+			},
+		},
+	}
+
+	got := StrictJSONSchema(schema)
+
+	node := got["$defs"].(map[string]any)["node"].(map[string]any)
+	if node["additionalProperties"] != false || !reflect.DeepEqual(node["required"], []string{"child", "name"}) {
+		t.Errorf("definition not made strict: %v", node)
+	}
+	child := node["properties"].(map[string]any)["child"].(map[string]any)
+	if want := []any{map[string]any{"$ref": "#/$defs/node"}, map[string]any{"type": "null"}}; !reflect.DeepEqual(child["anyOf"], want) {
+		t.Errorf("anyOf = %v, want %v", child["anyOf"], want)
+	}
+	if typ := node["properties"].(map[string]any)["name"].(map[string]any)["type"]; !reflect.DeepEqual(typ, []any{"string", "null"}) {
+		t.Errorf("optional definition property type = %v, want [string null]", typ)
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// AuthProfileType is how an auth profile signs in.
+type AuthProfileType string
+
+const (
+	AuthProfileCookies AuthProfileType = "cookies" // Uploaded cookie jar
+	AuthProfileLogin   AuthProfileType = "login"   // Login script replayed in the browser
+)
+
+// AuthProfile is a user's stored login for sites that require one. Its cookies are
+// sent with every fetch that references the profile; login profiles sign in again
+// in the browser when a fetch lands on the login page.
+type AuthProfile struct {
+	ID               string          `json:"id"`
+	UserID           string          `json:"user_id"` // Clerk user ID
+	Name             string          `json:"name"`
+	Type             AuthProfileType `json:"type"`
+	LoginURL         string          `json:"login_url,omitempty"` // Login page; fetches redirected here mean the session expired
+	CookiesEncrypted string          `json:"-"`                   // Encrypted JSON cookie jar
+	ScriptEncrypted  string          `json:"-"`                   // Encrypted JSON AuthLoginScript (login profiles)
+	CookiesUpdatedAt *time.Time      `json:"cookies_updated_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// AuthLoginScript fills in and submits a login form on the profile's login page.
+type AuthLoginScript struct {
+	Fields          []AuthLoginField `json:"fields"`
+	SubmitSelector  string           `json:"submit_selector"`
+	SuccessSelector string           `json:"success_selector"` // Element that only appears once signed in
+}
+
+// AuthLoginField is a login form input and the value typed into it.
+type AuthLoginField struct {
+	Selector string `json:"selector"`
+	Value    string `json:"value"`
+}
@@ -13,10 +13,10 @@ type UserBalance struct {
 	BalanceUSD    float64    `json:"balance_usd"`
 	LifetimeAdded float64    `json:"lifetime_added"`
 	LifetimeSpent float64    `json:"lifetime_spent"`
-	Tier          string     `json:"tier,omitempty"`           // User's subscription tier (synced from Clerk)
-	Features      []string   `json:"features,omitempty"`       // User's feature flags (synced from Clerk Commerce)
-	PeriodStart   *time.Time `json:"period_start,omitempty"`   // Current billing period start (from Clerk subscription)
-	PeriodEnd     *time.Time `json:"period_end,omitempty"`     // Current billing period end (from Clerk subscription)
+	Tier          string     `json:"tier,omitempty"`            // User's subscription tier (synced from Clerk)
+	Features      []string   `json:"features,omitempty"`        // User's feature flags (synced from Clerk Commerce)
+	PeriodStart   *time.Time `json:"period_start,omitempty"`    // Current billing period start (from Clerk subscription)
+	PeriodEnd     *time.Time `json:"period_end,omitempty"`      // Current billing period end (from Clerk subscription)
 	ClerkSyncedAt *time.Time `json:"clerk_synced_at,omitempty"` // When tier/features were last synced from Clerk
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
@@ -101,12 +101,19 @@ type UsageInsight struct {
 	MarkupRate float64 `json:"markup_rate"`  // The rate applied (e.g., 0.25)
 	MarkupUSD  float64 `json:"markup_usd"`   // LLMCostUSD * MarkupRate
 
+	// SolverCostUSD is what paid CAPTCHA solvers charged, passed through at cost
+	SolverCostUSD float64 `json:"solver_cost_usd,omitempty"`
+
 	// LLM details
 	LLMProvider  string `json:"llm_provider"`
 	LLMModel     string `json:"llm_model"`
 	GenerationID string `json:"generation_id,omitempty"` // OpenRouter generation ID
 	BYOKProvider string `json:"byok_provider,omitempty"` // Provider if BYOK
 
+	// Model routing
+	RoutingPolicy string `json:"routing_policy,omitempty"` // Fallback chain routing policy (ordered, cheapest_capable, ...)
+	RoutingReason string `json:"routing_reason,omitempty"` // Why the policy ordered the chain the way it did
+
 	// Execution metrics
 	PagesAttempted    int `json:"pages_attempted"`
 	PagesSuccessful   int `json:"pages_successful"`
@@ -135,3 +142,144 @@ type CrawlConfig struct {
 	SameDomainOnly   bool   `json:"same_domain_only"`
 	ExtractFromSeeds bool   `json:"extract_from_seeds"`
 }
+
+// ========================================
+// Statements
+// ========================================
+
+// StatementItem is a usage record joined with its insight's cost breakdown,
+// one line of a billing statement.
+type StatementItem struct {
+	UsageID         string    `json:"usage_id"`
+	JobID           string    `json:"job_id,omitempty"`
+	Date            string    `json:"date"` // YYYY-MM-DD
+	Type            JobType   `json:"type"`
+	Status          string    `json:"status"`
+	IsBYOK          bool      `json:"is_byok"`
+	TotalChargedUSD float64   `json:"total_charged_usd"`
+	TargetURL       string    `json:"target_url,omitempty"`
+	TokensInput     int       `json:"tokens_input"`
+	TokensOutput    int       `json:"tokens_output"`
+	LLMCostUSD      float64   `json:"llm_cost_usd"`
+	MarkupRate      float64   `json:"markup_rate"`
+	MarkupUSD       float64   `json:"markup_usd"`
+	SolverCostUSD   float64   `json:"solver_cost_usd"`
+	LLMProvider     string    `json:"llm_provider,omitempty"`
+	LLMModel        string    `json:"llm_model,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ========================================
+// Spend Caps
+// ========================================
+
+// SpendCapPeriod is the window a spend cap applies to. Periods are calendar based in UTC.
+type SpendCapPeriod string
+
+const (
+	SpendCapDaily   SpendCapPeriod = "daily"
+	SpendCapMonthly SpendCapPeriod = "monthly"
+)
+
+// SpendCapPeriods lists the periods spend is tracked for.
+var SpendCapPeriods = []SpendCapPeriod{SpendCapDaily, SpendCapMonthly}
+
+// IsValid returns true if the period is a known spend cap period.
+func (p SpendCapPeriod) IsValid() bool {
+	return p == SpendCapDaily || p == SpendCapMonthly
+}
+
+// Start returns the start of the period containing t.
+func (p SpendCapPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == SpendCapMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// SpendScope returns the counter scope for a user's spend, or an API key's spend
+// when apiKeyID is set.
+func SpendScope(userID, apiKeyID string) string {
+	if apiKeyID != "" {
+		return "key:" + apiKeyID
+	}
+	return "user:" + userID
+}
+
+// SolverSpendScope returns the counter scope for a user's paid CAPTCHA solver spend,
+// or for all users' solver spend when userID is empty.
+func SolverSpendScope(userID string) string {
+	if userID == "" {
+		return "solver:global"
+	}
+	return "solver:user:" + userID
+}
+
+// SpendCap limits how much a user, or one of their API keys, can spend per period.
+// Any combination of limits can be set; a nil limit is not enforced.
+type SpendCap struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	APIKeyID   string         `json:"api_key_id,omitempty"` // Empty for caps on all of the user's usage
+	Period     SpendCapPeriod `json:"period"`
+	MaxCostUSD *float64       `json:"max_cost_usd,omitempty"` // Credits charged, or LLM cost for BYOK usage
+	MaxTokens  *int64         `json:"max_tokens,omitempty"`   // Input + output tokens
+	MaxPages   *int64         `json:"max_pages,omitempty"`    // Pages extracted
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// Scope returns the spend counter scope the cap is measured against.
+func (c *SpendCap) Scope() string {
+	return SpendScope(c.UserID, c.APIKeyID)
+}
+
+// Fraction returns the largest share of any configured limit that usage has consumed
+// (1.0 means the cap has been reached).
+func (c *SpendCap) Fraction(u SpendUsage) float64 {
+	var fraction float64
+	if c.MaxCostUSD != nil && *c.MaxCostUSD > 0 {
+		fraction = max(fraction, u.CostUSD / *c.MaxCostUSD)
+	}
+	if c.MaxTokens != nil && *c.MaxTokens > 0 {
+		fraction = max(fraction, float64(u.Tokens)/float64(*c.MaxTokens))
+	}
+	if c.MaxPages != nil && *c.MaxPages > 0 {
+		fraction = max(fraction, float64(u.Pages)/float64(*c.MaxPages))
+	}
+	return fraction
+}
+
+// Blocks returns the name of the first limit that stops an operation expected to add
+// pending usage, or "" if the operation is allowed. A limit blocks once it has been
+// reached, or when the operation would take usage past it.
+func (c *SpendCap) Blocks(used, pending SpendUsage) string {
+	next := used.Add(pending)
+	switch {
+	case c.MaxCostUSD != nil && (used.CostUSD >= *c.MaxCostUSD || next.CostUSD > *c.MaxCostUSD):
+		return "cost"
+	case c.MaxTokens != nil && (used.Tokens >= *c.MaxTokens || next.Tokens > *c.MaxTokens):
+		return "tokens"
+	case c.MaxPages != nil && (used.Pages >= *c.MaxPages || next.Pages > *c.MaxPages):
+		return "pages"
+	}
+	return ""
+}
+
+// SpendUsage is spend accumulated against a scope within a period.
+type SpendUsage struct {
+	CostUSD float64 `json:"cost_usd"`
+	Tokens  int64   `json:"tokens"`
+	Pages   int64   `json:"pages"`
+}
+
+// Add returns the sum of two usages.
+func (u SpendUsage) Add(o SpendUsage) SpendUsage {
+	return SpendUsage{CostUSD: u.CostUSD + o.CostUSD, Tokens: u.Tokens + o.Tokens, Pages: u.Pages + o.Pages}
+}
+
+// Sub returns u less o.
+func (u SpendUsage) Sub(o SpendUsage) SpendUsage {
+	return SpendUsage{CostUSD: u.CostUSD - o.CostUSD, Tokens: u.Tokens - o.Tokens, Pages: u.Pages - o.Pages}
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// ========================================
+// Spend Cap Tests
+// ========================================
+
+func TestSpendCapPeriod_Start(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)
+
+	if got := SpendCapDaily.Start(now); !got.Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("daily start = %v", got)
+	}
+	if got := SpendCapMonthly.Start(now); !got.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("monthly start = %v", got)
+	}
+	if SpendCapPeriod("weekly").IsValid() {
+		t.Error("expected weekly to be invalid")
+	}
+}
+
+func TestSpendScope(t *testing.T) {
+	if got := SpendScope("user-1", ""); got != "user:user-1" {
+		t.Errorf("SpendScope() = %q, want user:user-1", got)
+	}
+	if got := SpendScope("user-1", "key-1"); got != "key:key-1" {
+		t.Errorf("SpendScope() = %q, want key:key-1", got)
+	}
+}
+
+func TestSpendCap_Blocks(t *testing.T) {
+	maxCost := 10.0
+	maxPages := int64(5)
+	spendCap := &SpendCap{MaxCostUSD: &maxCost, MaxPages: &maxPages}
+
+	tests := []struct {
+		name    string
+		used    SpendUsage
+		pending SpendUsage
+		want    string
+	}{
+		{"under limits", SpendUsage{CostUSD: 5, Pages: 2}, SpendUsage{CostUSD: 1, Pages: 1}, ""},
+		{"exactly reaches limit", SpendUsage{CostUSD: 9}, SpendUsage{CostUSD: 1}, ""},
+		{"would exceed cost", SpendUsage{CostUSD: 9.5}, SpendUsage{CostUSD: 1}, "cost"},
+		{"cost reached", SpendUsage{CostUSD: 10}, SpendUsage{}, "cost"},
+		{"pages reached", SpendUsage{Pages: 5}, SpendUsage{Pages: 1}, "pages"},
+		{"tokens not capped", SpendUsage{Tokens: 1 << 40}, SpendUsage{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := spendCap.Blocks(tt.used, tt.pending); got != tt.want {
+				t.Errorf("Blocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpendCap_Fraction(t *testing.T) {
+	maxCost := 10.0
+	maxTokens := int64(1000)
+	spendCap := &SpendCap{MaxCostUSD: &maxCost, MaxTokens: &maxTokens}
+
+	// The most consumed limit wins
+	if got := spendCap.Fraction(SpendUsage{CostUSD: 2, Tokens: 800}); got != 0.8 {
+		t.Errorf("Fraction() = %v, want 0.8", got)
+	}
+	if got := (&SpendCap{}).Fraction(SpendUsage{CostUSD: 100}); got != 0 {
+		t.Errorf("Fraction() with no limits = %v, want 0", got)
+	}
+}
@@ -70,6 +70,7 @@ type Job struct {
 	WebhookURL       string     `json:"webhook_url,omitempty"`
 	WebhookStatus    string     `json:"webhook_status,omitempty"`
 	WebhookAttempts  int        `json:"webhook_attempts"`
+	TraceParent      string     `json:"-"` // W3C traceparent of the request that created the job, so the worker continues its trace
 	StartedAt        *time.Time `json:"started_at,omitempty"`
 	CompletedAt      *time.Time `json:"completed_at,omitempty"`
 	CreatedAt        time.Time  `json:"created_at"`
@@ -175,11 +176,48 @@ type FallbackChainEntry struct {
 	Temperature *float64  `json:"temperature,omitempty"` // nil = use default for model/provider
 	MaxTokens   *int      `json:"max_tokens,omitempty"`  // nil = use default for model/provider
 	StrictMode  *bool     `json:"strict_mode,omitempty"` // nil = use default for model (most models: false)
+	Weight      int       `json:"weight,omitempty"`      // Traffic share (percent) under the weighted routing policy
 	IsEnabled   bool      `json:"is_enabled"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// RoutingPolicy controls how a fallback chain orders its entries for each request.
+type RoutingPolicy string
+
+const (
+	// RoutingOrdered tries entries in position order.
+	RoutingOrdered RoutingPolicy = "ordered"
+	// RoutingCheapestCapable tries the cheapest entry whose context window fits the input first.
+	RoutingCheapestCapable RoutingPolicy = "cheapest_capable"
+	// RoutingFastest tries entries by observed p50 latency, fastest first.
+	RoutingFastest RoutingPolicy = "fastest"
+	// RoutingWeighted picks the first entry at random by weight (A/B traffic split).
+	RoutingWeighted RoutingPolicy = "weighted"
+)
+
+// IsValid returns true if the routing policy is recognized.
+func (p RoutingPolicy) IsValid() bool {
+	switch p {
+	case RoutingOrdered, RoutingCheapestCapable, RoutingFastest, RoutingWeighted:
+		return true
+	}
+	return false
+}
+
+// TierRoutingScope returns the routing policy scope for a system chain (nil tier = default chain).
+func TierRoutingScope(tier *string) string {
+	if tier == nil {
+		return "tier:default"
+	}
+	return "tier:" + *tier
+}
+
+// UserRoutingScope returns the routing policy scope for a user's personal chain.
+func UserRoutingScope(userID string) string {
+	return "user:" + userID
+}
+
 // UserServiceKey represents a user-configured LLM provider API key.
 // Similar to ServiceKey but per-user. Models are specified in UserFallbackChainEntry.
 type UserServiceKey struct {
@@ -205,6 +243,7 @@ type UserFallbackChainEntry struct {
 	Temperature *float64  `json:"temperature,omitempty"` // nil = use default for model/provider
 	MaxTokens   *int      `json:"max_tokens,omitempty"`  // nil = use default for model/provider
 	StrictMode  *bool     `json:"strict_mode,omitempty"` // nil = use default for model (most models: false)
+	Weight      int       `json:"weight,omitempty"`      // Traffic share (percent) under the weighted routing policy
 	IsEnabled   bool      `json:"is_enabled"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -221,6 +260,8 @@ const (
 	WebhookEventJobProgress    WebhookEventType = "job.progress"
 	WebhookEventExtractSuccess WebhookEventType = "extract.success"
 	WebhookEventExtractFailed  WebhookEventType = "extract.failed"
+	// WebhookEventSpendCapThreshold fires when usage crosses 50/80/100% of a spend cap
+	WebhookEventSpendCapThreshold WebhookEventType = "spend_cap.threshold"
 )
 
 // WebhookDeliveryStatus represents the status of a webhook delivery.
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Proxy is an outbound proxy that pages are fetched through. Proxies without a
+// user belong to the admin-managed pool; the rest are a user's own (BYOK) proxies.
+type Proxy struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id,omitempty"` // Clerk user ID, empty for the managed pool
+	Name         string    `json:"name"`
+	Endpoint     string    `json:"endpoint"`          // scheme://host:port, without credentials
+	URLEncrypted string    `json:"-"`                 // Encrypted proxy URL, including credentials
+	Country      string    `json:"country,omitempty"` // ISO 3166-1 alpha-2 exit country
+	IsEnabled    bool      `json:"is_enabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ProxyOutcome is the result of fetching a page through a proxy.
+type ProxyOutcome string
+
+const (
+	ProxyOutcomeSuccess ProxyOutcome = "success" // Page fetched
+	ProxyOutcomeFailure ProxyOutcome = "failure" // Proxy or network error
+	ProxyOutcomeBan     ProxyOutcome = "ban"     // Page blocked by bot protection
+)
+
+// ProxyHealth counts fetch outcomes through a proxy for a domain.
+// The row with an empty domain counts outcomes across all domains.
+type ProxyHealth struct {
+	ProxyID      string     `json:"proxy_id"`
+	Domain       string     `json:"domain"`
+	Successes    int64      `json:"successes"`
+	Failures     int64      `json:"failures"`
+	Bans         int64      `json:"bans"`
+	LastBannedAt *time.Time `json:"last_banned_at,omitempty"`
+	LastUsedAt   time.Time  `json:"last_used_at"`
+}
+
+// Score rates the proxy from 0 to 1 by its share of successful fetches, with each ban
+// counting as banWeight failures. Untried proxies score 0.5 so they still get picked.
+func (h *ProxyHealth) Score(banWeight int64) float64 {
+	if h == nil {
+		return 0.5
+	}
+	return float64(h.Successes+1) / float64(h.Successes+h.Failures+h.Bans*banWeight+2)
+}
+
+// BannedAt reports whether the proxy was blocked within cooldown of now.
+func (h *ProxyHealth) BannedAt(now time.Time, cooldown time.Duration) bool {
+	return h != nil && h.LastBannedAt != nil && now.Sub(*h.LastBannedAt) < cooldown
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// ========================================
+// Proxy Health Tests
+// ========================================
+
+func TestProxyHealth_Score(t *testing.T) {
+	var untried *ProxyHealth
+	if got := untried.Score(3); got != 0.5 {
+		t.Errorf("untried score = %v, want 0.5", got)
+	}
+
+	healthy := &ProxyHealth{Successes: 98, Failures: 2}
+	banned := &ProxyHealth{Successes: 98, Failures: 2, Bans: 10}
+	if healthy.Score(3) <= banned.Score(3) {
+		t.Errorf("healthy score %v should exceed banned score %v", healthy.Score(3), banned.Score(3))
+	}
+	if got := (&ProxyHealth{Failures: 10}).Score(3); got >= untried.Score(3) {
+		t.Errorf("failing score = %v, want below untried", got)
+	}
+}
+
+func TestProxyHealth_BannedAt(t *testing.T) {
+	now := time.Date(2026, 10, 18, 16, 0, 0, 0, time.UTC)
+	recent := now.Add(-10 * time.Minute)
+	old := now.Add(-2 * time.Hour)
+
+	if (*ProxyHealth)(nil).BannedAt(now, time.Hour) {
+		t.Error("nil health should not be banned")
+	}
+	if !(&ProxyHealth{LastBannedAt: &recent}).BannedAt(now, time.Hour) {
+		t.Error("expected recent ban to be within cooldown")
+	}
+	if (&ProxyHealth{LastBannedAt: &old}).BannedAt(now, time.Hour) {
+		t.Error("expected old ban to be past cooldown")
+	}
+}
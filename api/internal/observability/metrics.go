@@ -0,0 +1,210 @@
+// Package observability provides Prometheus metrics and OpenTelemetry tracing.
+//
+// Metrics are registered on a dedicated registry served by Handler. Tracing is
+// exported over OTLP/HTTP when configured (see SetupTracing); otherwise spans are
+// no-ops, so instrumented code never needs to check whether tracing is enabled.
+package observability
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "refyne"
+
+// Outcome label values.
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Registry holds all refyne-api metrics, plus Go runtime and process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration is API request latency per Huma operation.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "API request latency by operation, method and status code.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"operation", "method", "status"})
+
+	// FetchDuration is page fetch latency by fetch mode.
+	FetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "fetch",
+		Name:      "duration_seconds",
+		Help:      "Page fetch latency by fetch mode and outcome.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+	}, []string{"mode", "outcome"})
+
+	// LLMRequestDuration is LLM call latency by provider and model.
+	LLMRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "request_duration_seconds",
+		Help:      "LLM call latency by provider, model and outcome.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 90, 120, 180},
+	}, []string{"provider", "model", "outcome"})
+
+	// LLMTokens counts LLM tokens by provider, model and direction (input or output).
+	LLMTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "tokens_total",
+		Help:      "LLM tokens consumed by provider, model and direction.",
+	}, []string{"provider", "model", "direction"})
+
+	// LLMErrors counts failed LLM calls by provider, model and error category.
+	LLMErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "llm",
+		Name:      "errors_total",
+		Help:      "Failed LLM calls by provider, model and error category.",
+	}, []string{"provider", "model", "category"})
+
+	// WebhookDeliveries counts webhook delivery attempts by outcome.
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Webhook delivery attempts by outcome (success, retrying, failed).",
+	}, []string{"outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		FetchDuration,
+		LLMRequestDuration,
+		LLMTokens,
+		LLMErrors,
+		WebhookDeliveries,
+	)
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+// If token is set, scrapes must send it as a bearer token.
+func Handler(token string) http.Handler {
+	metrics := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return metrics
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		metrics.ServeHTTP(w, r)
+	})
+}
+
+// ObserveHTTPRequest records the latency of an API request.
+func ObserveHTTPRequest(operation, method string, status int, d time.Duration) {
+	HTTPRequestDuration.WithLabelValues(operation, method, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+// ObserveFetch records the latency of a page fetch.
+func ObserveFetch(mode string, d time.Duration, err error) {
+	FetchDuration.WithLabelValues(mode, outcome(err)).Observe(d.Seconds())
+}
+
+// ObserveLLMCall records the latency and token usage of an LLM call.
+// errCategory is the LLM error category, and is only used when err is non-nil.
+func ObserveLLMCall(provider, model string, d time.Duration, inputTokens, outputTokens int, err error, errCategory string) {
+	LLMRequestDuration.WithLabelValues(provider, model, outcome(err)).Observe(d.Seconds())
+	if inputTokens > 0 {
+		LLMTokens.WithLabelValues(provider, model, "input").Add(float64(inputTokens))
+	}
+	if outputTokens > 0 {
+		LLMTokens.WithLabelValues(provider, model, "output").Add(float64(outputTokens))
+	}
+	if err != nil {
+		if errCategory == "" {
+			errCategory = "unknown"
+		}
+		LLMErrors.WithLabelValues(provider, model, errCategory).Inc()
+	}
+}
+
+// ObserveWebhookDelivery records the outcome of a webhook delivery attempt.
+func ObserveWebhookDelivery(outcome string) {
+	WebhookDeliveries.WithLabelValues(outcome).Inc()
+}
+
+// RegisterWorker exposes worker utilisation: the number of job slots and how many are busy.
+func RegisterWorker(concurrency int, activeJobs func() int64) {
+	Registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "concurrency",
+			Help:      "Number of concurrent job slots.",
+		}, func() float64 { return float64(concurrency) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "worker",
+			Name:      "active_jobs",
+			Help:      "Number of jobs currently being processed.",
+		}, func() float64 { return float64(activeJobs()) }),
+	)
+}
+
+// JobQueueStatsFunc returns the number of pending and running jobs by tier.
+type JobQueueStatsFunc func(ctx context.Context) (pending, running map[string]int, err error)
+
+// RegisterJobQueue exposes job queue depth by tier and status, read at scrape time.
+func RegisterJobQueue(stats JobQueueStatsFunc) {
+	Registry.MustRegister(&jobQueueCollector{stats: stats})
+}
+
+var jobQueueDepthDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "job_queue", "depth"),
+	"Number of jobs by tier and status (pending or running).",
+	[]string{"tier", "status"}, nil,
+)
+
+// jobQueueCollector reads job queue depth from the database on each scrape.
+type jobQueueCollector struct {
+	stats JobQueueStatsFunc
+}
+
+// Describe implements prometheus.Collector.
+func (c *jobQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jobQueueDepthDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *jobQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pending, running, err := c.stats(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(jobQueueDepthDesc, err)
+		return
+	}
+	for tier, n := range pending {
+		ch <- prometheus.MustNewConstMetric(jobQueueDepthDesc, prometheus.GaugeValue, float64(n), tier, "pending")
+	}
+	for tier, n := range running {
+		ch <- prometheus.MustNewConstMetric(jobQueueDepthDesc, prometheus.GaugeValue, float64(n), tier, "running")
+	}
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
@@ -0,0 +1,121 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestHandler_Token(t *testing.T) {
+	ObserveWebhookDelivery("success")
+
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{"no token configured", "", "", http.StatusOK},
+		{"missing token", "secret", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "Bearer wrong", http.StatusUnauthorized},
+		{"correct token", "secret", "Bearer secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			Handler(tt.token).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && !strings.Contains(rec.Body.String(), "refyne_webhook_deliveries_total") {
+				t.Error("response missing refyne_webhook_deliveries_total")
+			}
+		})
+	}
+}
+
+func TestObserveLLMCall(t *testing.T) {
+	ObserveLLMCall("openrouter", "test/model-a", 2*time.Second, 100, 20, nil, "")
+	ObserveLLMCall("openrouter", "test/model-a", time.Second, 50, 0, errors.New("429"), "rate_limit")
+	ObserveLLMCall("openrouter", "test/model-a", time.Second, 0, 0, errors.New("boom"), "")
+
+	if got := testutil.ToFloat64(LLMTokens.WithLabelValues("openrouter", "test/model-a", "input")); got != 150 {
+		t.Errorf("input tokens = %v, want 150", got)
+	}
+	if got := testutil.ToFloat64(LLMTokens.WithLabelValues("openrouter", "test/model-a", "output")); got != 20 {
+		t.Errorf("output tokens = %v, want 20", got)
+	}
+	if got := testutil.ToFloat64(LLMErrors.WithLabelValues("openrouter", "test/model-a", "rate_limit")); got != 1 {
+		t.Errorf("rate_limit errors = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(LLMErrors.WithLabelValues("openrouter", "test/model-a", "unknown")); got != 1 {
+		t.Errorf("unknown errors = %v, want 1", got)
+	}
+}
+
+func TestJobQueueCollector(t *testing.T) {
+	c := &jobQueueCollector{stats: func(ctx context.Context) (map[string]int, map[string]int, error) {
+		return map[string]int{"free": 3, "pro": 1}, map[string]int{"pro": 2}, nil
+	}}
+
+	expected := `
+# HELP refyne_job_queue_depth Number of jobs by tier and status (pending or running).
+# TYPE refyne_job_queue_depth gauge
+refyne_job_queue_depth{status="pending",tier="free"} 3
+refyne_job_queue_depth{status="pending",tier="pro"} 1
+refyne_job_queue_depth{status="running",tier="pro"} 2
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+
+	failing := &jobQueueCollector{stats: func(ctx context.Context) (map[string]int, map[string]int, error) {
+		return nil, nil, errors.New("database unavailable")
+	}}
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(failing)
+	if _, err := reg.Gather(); err == nil {
+		t.Error("Gather() error = nil, want collector error")
+	}
+}
+
+func TestTraceParentRoundTrip(t *testing.T) {
+	if got := TraceParent(context.Background()); got != "" {
+		t.Errorf("TraceParent() without span = %q, want empty", got)
+	}
+
+	provider := sdktrace.NewTracerProvider()
+	defer func() { _ = provider.Shutdown(context.Background()) }()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	traceParent := TraceParent(ctx)
+	if traceParent == "" {
+		t.Fatal("TraceParent() = empty, want traceparent")
+	}
+
+	// A span started from the restored context (e.g., in the worker) joins the same trace
+	restored := ContextWithTraceParent(context.Background(), traceParent)
+	_, child := provider.Tracer("test").Start(restored, "worker")
+	defer child.End()
+	if child.SpanContext().TraceID() != span.SpanContext().TraceID() {
+		t.Errorf("child trace ID = %s, want %s", child.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+
+	if got := ContextWithTraceParent(context.Background(), ""); got != context.Background() {
+		t.Error("ContextWithTraceParent(\"\") should return ctx unchanged")
+	}
+}
@@ -126,6 +126,62 @@ func (h *Hints) ToPromptSection() string {
 	return result
 }
 
+// ToExtractionPromptSection formats hints as guidance for data extraction.
+// Unlike ToPromptSection (used for schema generation), this tells the LLM how
+// many items to expect so that listing pages are extracted completely.
+func (h *Hints) ToExtractionPromptSection() string {
+	if h == nil || len(h.DetectedTypes) == 0 {
+		return ""
+	}
+
+	result := "\n## Detected Content Structure\n"
+	if len(h.DetectedTypes) == 1 {
+		dt := h.DetectedTypes[0]
+		result += "This page contains approximately **" + strconv.Itoa(dt.Count) + " " + dt.Name + "**.\n"
+		result += "Extract ALL " + strconv.Itoa(dt.Count) + " " + dt.Name + " - do not stop after the first few.\n"
+	} else {
+		result += "This page contains approximately:\n"
+		for _, dt := range h.DetectedTypes {
+			result += "- " + strconv.Itoa(dt.Count) + " " + dt.Name + "\n"
+		}
+		result += "Extract ALL matching items for each list - do not stop after the first few.\n"
+	}
+
+	return result
+}
+
+// MaxDetectedCount returns the highest item count across detected content types.
+func (h *Hints) MaxDetectedCount() int {
+	if h == nil {
+		return 0
+	}
+	maxCount := 0
+	for _, dt := range h.DetectedTypes {
+		if dt.Count > maxCount {
+			maxCount = dt.Count
+		}
+	}
+	return maxCount
+}
+
+// ToMap flattens hints into string key/value pairs for debug capture.
+func (h *Hints) ToMap() map[string]string {
+	if h == nil {
+		return nil
+	}
+	m := make(map[string]string, len(h.Custom)+len(h.DetectedTypes)+1)
+	for k, v := range h.Custom {
+		m[k] = v
+	}
+	for _, dt := range h.DetectedTypes {
+		m["detected_"+dt.Name] = strconv.Itoa(dt.Count)
+	}
+	if h.PageStructure != "" {
+		m["page_structure"] = h.PageStructure
+	}
+	return m
+}
+
 // LLMPreProcessor analyzes content and generates hints for LLM prompts.
 type LLMPreProcessor interface {
 	// Process analyzes content and returns hints.
@@ -241,6 +241,90 @@ func TestHints_ToPromptSection_CustomHints(t *testing.T) {
 	}
 }
 
+// ========================================
+// Extraction Guidance Tests
+// ========================================
+
+func TestHints_ToExtractionPromptSection_Empty(t *testing.T) {
+	hints := NewHints()
+	hints.PageStructure = "Listing page"
+
+	if result := hints.ToExtractionPromptSection(); result != "" {
+		t.Errorf("expected empty string without detected types, got %q", result)
+	}
+
+	var nilHints *Hints
+	if result := nilHints.ToExtractionPromptSection(); result != "" {
+		t.Errorf("expected empty string for nil hints, got %q", result)
+	}
+}
+
+func TestHints_ToExtractionPromptSection_SingleDetectedType(t *testing.T) {
+	hints := NewHints()
+	hints.DetectedTypes = []DetectedContentType{{Name: "products", Count: 48}}
+
+	result := hints.ToExtractionPromptSection()
+
+	if !strings.Contains(result, "48 products") {
+		t.Error("should contain count and name")
+	}
+	if !strings.Contains(result, "Extract ALL 48 products") {
+		t.Error("should instruct the LLM to extract every item")
+	}
+	if strings.Contains(result, "schema") {
+		t.Error("extraction guidance should not mention schema design")
+	}
+}
+
+func TestHints_ToExtractionPromptSection_MultipleDetectedTypes(t *testing.T) {
+	hints := NewHints()
+	hints.DetectedTypes = []DetectedContentType{
+		{Name: "products", Count: 24},
+		{Name: "reviews", Count: 6},
+	}
+
+	result := hints.ToExtractionPromptSection()
+
+	if !strings.Contains(result, "- 24 products") || !strings.Contains(result, "- 6 reviews") {
+		t.Errorf("should list each detected type, got %q", result)
+	}
+}
+
+func TestHints_MaxDetectedCount(t *testing.T) {
+	var nilHints *Hints
+	if got := nilHints.MaxDetectedCount(); got != 0 {
+		t.Errorf("MaxDetectedCount() on nil = %d, want 0", got)
+	}
+
+	hints := NewHints()
+	hints.DetectedTypes = []DetectedContentType{
+		{Name: "reviews", Count: 6},
+		{Name: "products", Count: 24},
+	}
+	if got := hints.MaxDetectedCount(); got != 24 {
+		t.Errorf("MaxDetectedCount() = %d, want 24", got)
+	}
+}
+
+func TestHints_ToMap(t *testing.T) {
+	hints := NewHints()
+	hints.PageStructure = "Listing page with repeated products elements"
+	hints.DetectedTypes = []DetectedContentType{{Name: "products", Count: 12}}
+	hints.Custom["feedback_detected"] = "true"
+
+	m := hints.ToMap()
+
+	if m["detected_products"] != "12" {
+		t.Errorf("detected_products = %q, want 12", m["detected_products"])
+	}
+	if m["feedback_detected"] != "true" {
+		t.Error("should include custom hints")
+	}
+	if m["page_structure"] == "" {
+		t.Error("should include page structure")
+	}
+}
+
 // ========================================
 // DetectedContentType Tests
 // ========================================
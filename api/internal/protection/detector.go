@@ -363,6 +363,21 @@ func (r DetectionResult) IsRetryable() bool {
 	return r.SuggestDynamic
 }
 
+// IsBlock returns true if the request itself was refused (a challenge, denial or rate
+// limit), so retrying from another IP address might get through. Empty or
+// JavaScript-only pages aren't blocks.
+func (r DetectionResult) IsBlock() bool {
+	if !r.Detected {
+		return false
+	}
+	switch r.Signal {
+	case SignalCloudflare, SignalCaptcha, SignalAccessDenied, SignalRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
 // UserMessage returns a user-friendly message describing the detection.
 func (r DetectionResult) UserMessage() string {
 	if !r.Detected {
@@ -254,3 +254,24 @@ func TestDetectionResult_UserMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectionResult_IsBlock(t *testing.T) {
+	tests := []struct {
+		result DetectionResult
+		want   bool
+	}{
+		{DetectionResult{}, false},
+		{DetectionResult{Detected: true, Signal: SignalCloudflare}, true},
+		{DetectionResult{Detected: true, Signal: SignalCaptcha}, true},
+		{DetectionResult{Detected: true, Signal: SignalAccessDenied}, true},
+		{DetectionResult{Detected: true, Signal: SignalRateLimited}, true},
+		{DetectionResult{Detected: true, Signal: SignalEmptyContent}, false},
+		{DetectionResult{Detected: true, Signal: SignalJavaScriptRequired}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.result.IsBlock(); got != tt.want {
+			t.Errorf("IsBlock() for %q = %v, want %v", tt.result.Signal, got, tt.want)
+		}
+	}
+}
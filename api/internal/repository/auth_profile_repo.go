@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// SQLiteAuthProfileRepository implements AuthProfileRepository for SQLite/libsql.
+type SQLiteAuthProfileRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteAuthProfileRepository creates a new SQLite auth profile repository.
+func NewSQLiteAuthProfileRepository(db *sql.DB) *SQLiteAuthProfileRepository {
+	return &SQLiteAuthProfileRepository{db: db}
+}
+
+const authProfileColumns = `id, user_id, name, type, login_url, cookies_encrypted, script_encrypted, cookies_updated_at, created_at, updated_at`
+
+// scanAuthProfile scans an auth profile from a row.
+func scanAuthProfile(row interface{ Scan(...any) error }) (*models.AuthProfile, error) {
+	profile := &models.AuthProfile{}
+	var profileType, createdAt, updatedAt string
+	var cookiesUpdatedAt sql.NullString
+	if err := row.Scan(
+		&profile.ID,
+		&profile.UserID,
+		&profile.Name,
+		&profileType,
+		&profile.LoginURL,
+		&profile.CookiesEncrypted,
+		&profile.ScriptEncrypted,
+		&cookiesUpdatedAt,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	profile.Type = models.AuthProfileType(profileType)
+	if cookiesUpdatedAt.Valid {
+		if t, err := time.Parse(time.RFC3339Nano, cookiesUpdatedAt.String); err == nil {
+			profile.CookiesUpdatedAt = &t
+		}
+	}
+	profile.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	profile.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return profile, nil
+}
+
+// formatCookiesUpdatedAt formats a cookie refresh time. Nanoseconds are kept so
+// concurrent fetches can tell whether the cookies changed since they loaded them.
+func formatCookiesUpdatedAt(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339Nano)
+	return &s
+}
+
+// Create stores a new auth profile, setting its ID and timestamps.
+func (r *SQLiteAuthProfileRepository) Create(ctx context.Context, profile *models.AuthProfile) error {
+	now := time.Now().UTC()
+	if profile.ID == "" {
+		profile.ID = ulid.Make().String()
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO auth_profiles (`+authProfileColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, profile.ID, profile.UserID, profile.Name, string(profile.Type), profile.LoginURL,
+		profile.CookiesEncrypted, profile.ScriptEncrypted, formatCookiesUpdatedAt(profile.CookiesUpdatedAt),
+		now.Format(time.RFC3339), now.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	profile.CreatedAt = now
+	profile.UpdatedAt = now
+	return nil
+}
+
+// Update saves an auth profile's settings, cookies and login script.
+func (r *SQLiteAuthProfileRepository) Update(ctx context.Context, profile *models.AuthProfile) error {
+	now := time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE auth_profiles
+		SET name = ?, type = ?, login_url = ?, cookies_encrypted = ?, script_encrypted = ?, cookies_updated_at = ?, updated_at = ?
+		WHERE id = ?
+	`, profile.Name, string(profile.Type), profile.LoginURL, profile.CookiesEncrypted, profile.ScriptEncrypted,
+		formatCookiesUpdatedAt(profile.CookiesUpdatedAt), now.Format(time.RFC3339), profile.ID)
+	if err != nil {
+		return err
+	}
+	profile.UpdatedAt = now
+	return nil
+}
+
+// UpdateCookies replaces an auth profile's cookies after signing in again.
+func (r *SQLiteAuthProfileRepository) UpdateCookies(ctx context.Context, id, cookiesEncrypted string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE auth_profiles SET cookies_encrypted = ?, cookies_updated_at = ? WHERE id = ?
+	`, cookiesEncrypted, formatCookiesUpdatedAt(&at), id)
+	return err
+}
+
+// GetByID returns an auth profile by ID, or nil if it doesn't exist.
+func (r *SQLiteAuthProfileRepository) GetByID(ctx context.Context, id string) (*models.AuthProfile, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+authProfileColumns+` FROM auth_profiles WHERE id = ?`, id)
+	profile, err := scanAuthProfile(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return profile, err
+}
+
+// GetByUserID returns a user's auth profiles.
+func (r *SQLiteAuthProfileRepository) GetByUserID(ctx context.Context, userID string) ([]*models.AuthProfile, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+authProfileColumns+` FROM auth_profiles WHERE user_id = ? ORDER BY name, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var profiles []*models.AuthProfile
+	for rows.Next() {
+		profile, err := scanAuthProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
+
+// Delete removes an auth profile.
+func (r *SQLiteAuthProfileRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM auth_profiles WHERE id = ?`, id)
+	return err
+}
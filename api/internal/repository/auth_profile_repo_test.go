@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// ========================================
+// AuthProfileRepository Tests
+// ========================================
+
+func TestAuthProfileRepository_CreateGetUpdateDelete(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+
+	profile := &models.AuthProfile{
+		UserID:           "user-1",
+		Name:             "supplier portal",
+		Type:             models.AuthProfileLogin,
+		LoginURL:         "https://portal.example.com/login",
+		CookiesEncrypted: "enc-cookies",
+		ScriptEncrypted:  "enc-script",
+	}
+	if err := repos.AuthProfile.Create(ctx, profile); err != nil {
+		t.Fatalf("failed to create auth profile: %v", err)
+	}
+	if profile.ID == "" {
+		t.Fatal("expected ID to be set")
+	}
+
+	got, err := repos.AuthProfile.GetByID(ctx, profile.ID)
+	if err != nil {
+		t.Fatalf("failed to get auth profile: %v", err)
+	}
+	if got == nil || got.Type != models.AuthProfileLogin || got.LoginURL != profile.LoginURL ||
+		got.CookiesEncrypted != "enc-cookies" || got.ScriptEncrypted != "enc-script" || got.CookiesUpdatedAt != nil {
+		t.Errorf("unexpected auth profile: %+v", got)
+	}
+
+	profile.Name = "renamed"
+	if err := repos.AuthProfile.Update(ctx, profile); err != nil {
+		t.Fatalf("failed to update auth profile: %v", err)
+	}
+
+	list, err := repos.AuthProfile.GetByUserID(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("failed to list auth profiles: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "renamed" {
+		t.Errorf("unexpected auth profiles: %+v", list)
+	}
+	if other, _ := repos.AuthProfile.GetByUserID(ctx, "user-2"); len(other) != 0 {
+		t.Errorf("expected no auth profiles for another user, got %d", len(other))
+	}
+
+	if err := repos.AuthProfile.Delete(ctx, profile.ID); err != nil {
+		t.Fatalf("failed to delete auth profile: %v", err)
+	}
+	if got, _ := repos.AuthProfile.GetByID(ctx, profile.ID); got != nil {
+		t.Errorf("expected auth profile to be deleted, got %+v", got)
+	}
+}
+
+func TestAuthProfileRepository_UpdateCookies(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+
+	profile := &models.AuthProfile{UserID: "user-1", Name: "portal", Type: models.AuthProfileCookies, CookiesEncrypted: "old"}
+	if err := repos.AuthProfile.Create(ctx, profile); err != nil {
+		t.Fatalf("failed to create auth profile: %v", err)
+	}
+
+	at := time.Date(2026, 10, 18, 12, 0, 0, 123456789, time.UTC)
+	if err := repos.AuthProfile.UpdateCookies(ctx, profile.ID, "new", at); err != nil {
+		t.Fatalf("failed to update cookies: %v", err)
+	}
+
+	got, err := repos.AuthProfile.GetByID(ctx, profile.ID)
+	if err != nil {
+		t.Fatalf("failed to get auth profile: %v", err)
+	}
+	if got.CookiesEncrypted != "new" {
+		t.Errorf("CookiesEncrypted = %q, want new", got.CookiesEncrypted)
+	}
+	if got.CookiesUpdatedAt == nil || !got.CookiesUpdatedAt.Equal(at) {
+		t.Errorf("CookiesUpdatedAt = %v, want %v", got.CookiesUpdatedAt, at)
+	}
+}
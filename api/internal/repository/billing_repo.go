@@ -397,8 +397,8 @@ func (r *SQLiteUsageInsightRepository) Create(ctx context.Context, insight *mode
 	query := `INSERT INTO usage_insights (id, usage_id, target_url, schema_id, crawl_config_json, error_message, error_code,
 		tokens_input, tokens_output, llm_cost_usd, markup_rate, markup_usd, llm_provider, llm_model, generation_id, byok_provider,
 		pages_attempted, pages_successful, fetch_duration_ms, extract_duration_ms, total_duration_ms,
-		request_id, user_agent, ip_country, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		request_id, user_agent, ip_country, routing_policy, routing_reason, solver_cost_usd, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	_, err := r.db.ExecContext(ctx, query,
 		insight.ID, insight.UsageID, insight.TargetURL, nullString(insight.SchemaID), nullString(insight.CrawlConfigJSON),
@@ -407,6 +407,7 @@ func (r *SQLiteUsageInsightRepository) Create(ctx context.Context, insight *mode
 		nullString(insight.LLMProvider), nullString(insight.LLMModel), nullString(insight.GenerationID), nullString(insight.BYOKProvider),
 		insight.PagesAttempted, insight.PagesSuccessful, insight.FetchDurationMs, insight.ExtractDurationMs, insight.TotalDurationMs,
 		nullString(insight.RequestID), nullString(insight.UserAgent), nullString(insight.IPCountry),
+		nullString(insight.RoutingPolicy), nullString(insight.RoutingReason), insight.SolverCostUSD,
 		insight.CreatedAt.Format(time.RFC3339))
 	return err
 }
@@ -415,11 +416,11 @@ func (r *SQLiteUsageInsightRepository) GetByUsageID(ctx context.Context, usageID
 	query := `SELECT id, usage_id, target_url, schema_id, crawl_config_json, error_message, error_code,
 		tokens_input, tokens_output, llm_cost_usd, markup_rate, markup_usd, llm_provider, llm_model, generation_id, byok_provider,
 		pages_attempted, pages_successful, fetch_duration_ms, extract_duration_ms, total_duration_ms,
-		request_id, user_agent, ip_country, created_at
+		request_id, user_agent, ip_country, routing_policy, routing_reason, solver_cost_usd, created_at
 		FROM usage_insights WHERE usage_id = ?`
 
 	var insight models.UsageInsight
-	var schemaID, crawlConfig, errorMsg, errorCode, provider, model, genID, byokProvider, reqID, userAgent, ipCountry sql.NullString
+	var schemaID, crawlConfig, errorMsg, errorCode, provider, model, genID, byokProvider, reqID, userAgent, ipCountry, routingPolicy, routingReason sql.NullString
 	var createdAt string
 
 	err := r.db.QueryRowContext(ctx, query, usageID).Scan(
@@ -427,7 +428,7 @@ func (r *SQLiteUsageInsightRepository) GetByUsageID(ctx context.Context, usageID
 		&insight.TokensInput, &insight.TokensOutput, &insight.LLMCostUSD, &insight.MarkupRate, &insight.MarkupUSD,
 		&provider, &model, &genID, &byokProvider,
 		&insight.PagesAttempted, &insight.PagesSuccessful, &insight.FetchDurationMs, &insight.ExtractDurationMs, &insight.TotalDurationMs,
-		&reqID, &userAgent, &ipCountry, &createdAt)
+		&reqID, &userAgent, &ipCountry, &routingPolicy, &routingReason, &insight.SolverCostUSD, &createdAt)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -447,6 +448,8 @@ func (r *SQLiteUsageInsightRepository) GetByUsageID(ctx context.Context, usageID
 	insight.RequestID = reqID.String
 	insight.UserAgent = userAgent.String
 	insight.IPCountry = ipCountry.String
+	insight.RoutingPolicy = routingPolicy.String
+	insight.RoutingReason = routingReason.String
 	insight.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 
 	return &insight, nil
@@ -456,7 +459,7 @@ func (r *SQLiteUsageInsightRepository) GetByUserID(ctx context.Context, userID s
 	query := `SELECT i.id, i.usage_id, i.target_url, i.schema_id, i.crawl_config_json, i.error_message, i.error_code,
 		i.tokens_input, i.tokens_output, i.llm_cost_usd, i.markup_rate, i.markup_usd, i.llm_provider, i.llm_model, i.generation_id, i.byok_provider,
 		i.pages_attempted, i.pages_successful, i.fetch_duration_ms, i.extract_duration_ms, i.total_duration_ms,
-		i.request_id, i.user_agent, i.ip_country, i.created_at
+		i.request_id, i.user_agent, i.ip_country, i.routing_policy, i.routing_reason, i.solver_cost_usd, i.created_at
 		FROM usage_insights i
 		JOIN usage_records u ON i.usage_id = u.id
 		WHERE u.user_id = ?
@@ -471,7 +474,7 @@ func (r *SQLiteUsageInsightRepository) GetByUserID(ctx context.Context, userID s
 	var insights []*models.UsageInsight
 	for rows.Next() {
 		var insight models.UsageInsight
-		var schemaID, crawlConfig, errorMsg, errorCode, provider, model, genID, byokProvider, reqID, userAgent, ipCountry sql.NullString
+		var schemaID, crawlConfig, errorMsg, errorCode, provider, model, genID, byokProvider, reqID, userAgent, ipCountry, routingPolicy, routingReason sql.NullString
 		var createdAt string
 
 		if err := rows.Scan(
@@ -479,7 +482,7 @@ func (r *SQLiteUsageInsightRepository) GetByUserID(ctx context.Context, userID s
 			&insight.TokensInput, &insight.TokensOutput, &insight.LLMCostUSD, &insight.MarkupRate, &insight.MarkupUSD,
 			&provider, &model, &genID, &byokProvider,
 			&insight.PagesAttempted, &insight.PagesSuccessful, &insight.FetchDurationMs, &insight.ExtractDurationMs, &insight.TotalDurationMs,
-			&reqID, &userAgent, &ipCountry, &createdAt); err != nil {
+			&reqID, &userAgent, &ipCountry, &routingPolicy, &routingReason, &insight.SolverCostUSD, &createdAt); err != nil {
 			return nil, err
 		}
 
@@ -494,6 +497,8 @@ func (r *SQLiteUsageInsightRepository) GetByUserID(ctx context.Context, userID s
 		insight.RequestID = reqID.String
 		insight.UserAgent = userAgent.String
 		insight.IPCountry = ipCountry.String
+		insight.RoutingPolicy = routingPolicy.String
+		insight.RoutingReason = routingReason.String
 		insight.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 
 		insights = append(insights, &insight)
@@ -886,6 +886,76 @@ func TestUsageRepository_CountByUserAndDateRange(t *testing.T) {
 	}
 }
 
+func TestUsageRepository_GetStatementItems(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	records := []*models.UsageRecord{
+		{ID: "usage-late", UserID: "user-1", Date: "2026-01-12", Type: "crawl", Status: "partial", TotalChargedUSD: 0.20, CreatedAt: base.AddDate(0, 0, 2)},
+		{ID: "usage-early", UserID: "user-1", Date: "2026-01-10", Type: "extract", Status: "success", TotalChargedUSD: 0.10, CreatedAt: base},
+		{ID: "usage-byok", UserID: "user-1", Date: "2026-01-11", Type: "extract", Status: "success", TotalChargedUSD: 0.05, IsBYOK: true, CreatedAt: base.AddDate(0, 0, 1)},
+		{ID: "usage-outside", UserID: "user-1", Date: "2026-02-01", Type: "extract", Status: "success", TotalChargedUSD: 1.00, CreatedAt: base.AddDate(0, 0, 22)},
+		{ID: "usage-other", UserID: "user-2", Date: "2026-01-10", Type: "extract", Status: "success", TotalChargedUSD: 1.00, CreatedAt: base},
+	}
+	for _, record := range records {
+		if err := repos.Usage.Create(ctx, record); err != nil {
+			t.Fatalf("failed to create usage record: %v", err)
+		}
+	}
+	if err := repos.UsageInsight.Create(ctx, &models.UsageInsight{
+		ID:           "insight-1",
+		UsageID:      "usage-early",
+		TargetURL:    "https://example.com",
+		TokensInput:  1000,
+		TokensOutput: 200,
+		LLMCostUSD:   0.04,
+		MarkupRate:   0.25,
+		MarkupUSD:    0.01,
+		LLMProvider:  "openrouter",
+		LLMModel:     "openai/gpt-4o-mini",
+		CreatedAt:    base,
+	}); err != nil {
+		t.Fatalf("failed to create insight: %v", err)
+	}
+
+	items, err := repos.Usage.GetStatementItems(ctx, "user-1", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("failed to get statement items: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("len(items) = %d, want 3", len(items))
+	}
+
+	// Oldest first
+	wantOrder := []string{"usage-early", "usage-byok", "usage-late"}
+	for i, want := range wantOrder {
+		if items[i].UsageID != want {
+			t.Errorf("items[%d].UsageID = %q, want %q", i, items[i].UsageID, want)
+		}
+	}
+
+	early := items[0]
+	if early.TokensInput != 1000 || early.TokensOutput != 200 {
+		t.Errorf("early tokens = %d/%d, want 1000/200", early.TokensInput, early.TokensOutput)
+	}
+	if early.LLMCostUSD != 0.04 || early.MarkupRate != 0.25 || early.MarkupUSD != 0.01 {
+		t.Errorf("early costs = %v/%v/%v, want 0.04/0.25/0.01", early.LLMCostUSD, early.MarkupRate, early.MarkupUSD)
+	}
+	if early.LLMModel != "openai/gpt-4o-mini" || early.TargetURL != "https://example.com" {
+		t.Errorf("early model/url = %q/%q", early.LLMModel, early.TargetURL)
+	}
+	if !items[1].IsBYOK {
+		t.Error("expected usage-byok to be BYOK")
+	}
+
+	// Records without an insight still appear, with zero cost details
+	late := items[2]
+	if late.TotalChargedUSD != 0.20 || late.LLMCostUSD != 0 || late.TokensInput != 0 || late.LLMModel != "" {
+		t.Errorf("late item = %+v, want charge only", late)
+	}
+}
+
 // ========================================
 // Usage Insight Repository Tests
 // ========================================
@@ -37,6 +37,7 @@ func scanEntry(rows *sql.Rows) (*models.FallbackChainEntry, error) {
 		&temperature,
 		&maxTokens,
 		&strictMode,
+		&entry.Weight,
 		&entry.IsEnabled,
 		&createdAt,
 		&updatedAt,
@@ -65,7 +66,7 @@ func scanEntry(rows *sql.Rows) (*models.FallbackChainEntry, error) {
 // GetAll returns all fallback chain entries ordered by tier and position.
 func (r *SQLiteFallbackChainRepository) GetAll(ctx context.Context) ([]*models.FallbackChainEntry, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at
+		SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at
 		FROM fallback_chain
 		ORDER BY COALESCE(tier, ''), position ASC
 	`)
@@ -94,14 +95,14 @@ func (r *SQLiteFallbackChainRepository) GetByTier(ctx context.Context, tier *str
 
 	if tier == nil {
 		rows, err = r.db.QueryContext(ctx, `
-			SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at
+			SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at
 			FROM fallback_chain
 			WHERE tier IS NULL
 			ORDER BY position ASC
 		`)
 	} else {
 		rows, err = r.db.QueryContext(ctx, `
-			SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at
+			SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at
 			FROM fallback_chain
 			WHERE tier = ?
 			ORDER BY position ASC
@@ -129,7 +130,7 @@ func (r *SQLiteFallbackChainRepository) GetByTier(ctx context.Context, tier *str
 func (r *SQLiteFallbackChainRepository) GetEnabledByTier(ctx context.Context, tier string) ([]*models.FallbackChainEntry, error) {
 	// First, try to get tier-specific chain
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at
+		SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at
 		FROM fallback_chain
 		WHERE tier = ? AND is_enabled = 1
 		ORDER BY position ASC
@@ -163,7 +164,7 @@ func (r *SQLiteFallbackChainRepository) GetEnabledByTier(ctx context.Context, ti
 // GetEnabled returns all enabled fallback chain entries from the default chain.
 func (r *SQLiteFallbackChainRepository) GetEnabled(ctx context.Context) ([]*models.FallbackChainEntry, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at
+		SELECT id, tier, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at
 		FROM fallback_chain
 		WHERE tier IS NULL AND is_enabled = 1
 		ORDER BY position ASC
@@ -224,9 +225,9 @@ func (r *SQLiteFallbackChainRepository) ReplaceAllByTier(ctx context.Context, ti
 			strictModeInt = &v
 		}
 		_, err := tx.ExecContext(ctx, `
-			INSERT INTO fallback_chain (id, tier, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, entry.ID, tier, entry.Position, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.IsEnabled, now, now)
+			INSERT INTO fallback_chain (id, tier, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, entry.ID, tier, entry.Position, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.Weight, entry.IsEnabled, now, now)
 		if err != nil {
 			return err
 		}
@@ -299,9 +300,9 @@ func (r *SQLiteFallbackChainRepository) Create(ctx context.Context, entry *model
 		strictModeInt = &v
 	}
 	_, err = r.db.ExecContext(ctx, `
-		INSERT INTO fallback_chain (id, tier, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`, entry.ID, entry.Tier, entry.Position, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.IsEnabled, now, now)
+		INSERT INTO fallback_chain (id, tier, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, entry.ID, entry.Tier, entry.Position, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.Weight, entry.IsEnabled, now, now)
 
 	return err
 }
@@ -320,9 +321,9 @@ func (r *SQLiteFallbackChainRepository) Update(ctx context.Context, entry *model
 	}
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE fallback_chain
-		SET provider = ?, model = ?, temperature = ?, max_tokens = ?, strict_mode = ?, is_enabled = ?, updated_at = ?
+		SET provider = ?, model = ?, temperature = ?, max_tokens = ?, strict_mode = ?, weight = ?, is_enabled = ?, updated_at = ?
 		WHERE id = ?
-	`, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.IsEnabled, now, entry.ID)
+	`, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.Weight, entry.IsEnabled, now, entry.ID)
 
 	return err
 }
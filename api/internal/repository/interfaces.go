@@ -65,6 +65,7 @@ type UsageRepository interface {
 	GetSummaryByDateRange(ctx context.Context, userID string, startDate, endDate time.Time) (*UsageSummary, error)
 	GetMonthlySpend(ctx context.Context, userID string, month time.Time) (float64, error)
 	CountByUserAndDateRange(ctx context.Context, userID string, startDate, endDate string) (int, error)
+	GetStatementItems(ctx context.Context, userID string, startDate, endDate time.Time) ([]*models.StatementItem, error)
 }
 
 // UsageSummary represents aggregated usage data.
@@ -130,6 +131,8 @@ type LicenseRepository interface {
 	GetByKey(ctx context.Context, key string) (*models.License, error)
 	Update(ctx context.Context, license *models.License) error
 	List(ctx context.Context, limit, offset int) ([]*models.License, error)
+	// GetActive returns the most recently installed license that hasn't been revoked, or nil.
+	GetActive(ctx context.Context) (*models.License, error)
 }
 
 // ServiceKeyRepository defines methods for service key data access.
@@ -222,6 +225,59 @@ type UserFallbackChainRepository interface {
 	ReplaceAll(ctx context.Context, userID string, entries []*models.UserFallbackChainEntry) error
 }
 
+// RoutingPolicyRepository defines methods for fallback chain routing policies.
+// Policies are keyed by chain scope (see models.TierRoutingScope and models.UserRoutingScope).
+type RoutingPolicyRepository interface {
+	// Get returns the policy for a scope, or "" if none is set (ordered)
+	Get(ctx context.Context, scope string) (models.RoutingPolicy, error)
+	// GetAll returns all configured policies keyed by scope
+	GetAll(ctx context.Context) (map[string]models.RoutingPolicy, error)
+	Set(ctx context.Context, scope string, policy models.RoutingPolicy) error
+	Delete(ctx context.Context, scope string) error
+}
+
+// SpendCapRepository defines methods for spend caps and the per-period spend counters
+// they are enforced against. Counters are keyed by scope (see models.SpendScope).
+type SpendCapRepository interface {
+	GetByID(ctx context.Context, id string) (*models.SpendCap, error)
+	GetByUserID(ctx context.Context, userID string) ([]*models.SpendCap, error)
+	// Upsert creates a cap or replaces the limits of the user's cap for the same API key and period
+	Upsert(ctx context.Context, spendCap *models.SpendCap) error
+	Delete(ctx context.Context, id string) error
+	AddUsage(ctx context.Context, userID, scope string, period models.SpendCapPeriod, periodStart time.Time, usage models.SpendUsage) error
+	GetUsage(ctx context.Context, scope string, period models.SpendCapPeriod, periodStart time.Time) (models.SpendUsage, error)
+	// MarkAlerted records a notified threshold, returning false if it was already recorded
+	MarkAlerted(ctx context.Context, capID string, periodStart time.Time, threshold int) (bool, error)
+}
+
+// ProxyRepository defines methods for outbound proxies and their health.
+// Proxies with an empty user ID form the admin-managed pool.
+type ProxyRepository interface {
+	Create(ctx context.Context, proxy *models.Proxy) error
+	Update(ctx context.Context, proxy *models.Proxy) error
+	GetByID(ctx context.Context, id string) (*models.Proxy, error)
+	// ListByOwner returns a user's proxies, or the managed pool when userID is empty
+	ListByOwner(ctx context.Context, userID string) ([]*models.Proxy, error)
+	ListEnabledByOwner(ctx context.Context, userID string) ([]*models.Proxy, error)
+	Delete(ctx context.Context, id string) error
+	// RecordOutcome counts a fetch outcome for the domain and across all domains
+	RecordOutcome(ctx context.Context, proxyID, domain string, outcome models.ProxyOutcome, at time.Time) error
+	// GetHealth returns every proxy's health for a domain and across all domains (Domain "")
+	GetHealth(ctx context.Context, domain string) ([]*models.ProxyHealth, error)
+	ListHealthByProxyID(ctx context.Context, proxyID string) ([]*models.ProxyHealth, error)
+}
+
+// AuthProfileRepository defines methods for users' stored logins.
+type AuthProfileRepository interface {
+	Create(ctx context.Context, profile *models.AuthProfile) error
+	Update(ctx context.Context, profile *models.AuthProfile) error
+	// UpdateCookies replaces the cookies after signing in again
+	UpdateCookies(ctx context.Context, id, cookiesEncrypted string, at time.Time) error
+	GetByID(ctx context.Context, id string) (*models.AuthProfile, error)
+	GetByUserID(ctx context.Context, userID string) ([]*models.AuthProfile, error)
+	Delete(ctx context.Context, id string) error
+}
+
 // WebhookRepository defines methods for webhook data access.
 // Webhooks allow users to receive notifications when job events occur.
 type WebhookRepository interface {
@@ -264,6 +320,10 @@ type Repositories struct {
 	SavedSites        SavedSitesRepository
 	UserServiceKey    UserServiceKeyRepository
 	UserFallbackChain UserFallbackChainRepository
+	RoutingPolicy     RoutingPolicyRepository
+	SpendCap          SpendCapRepository
+	Proxy             ProxyRepository
+	AuthProfile       AuthProfileRepository
 	Webhook           WebhookRepository
 	WebhookDelivery   WebhookDeliveryRepository
 	RateLimit         RateLimitRepository
@@ -289,6 +349,10 @@ func NewRepositories(db *sql.DB) *Repositories {
 		SavedSites:        NewSQLiteSavedSitesRepository(db),
 		UserServiceKey:    NewSQLiteUserServiceKeyRepository(db),
 		UserFallbackChain: NewSQLiteUserFallbackChainRepository(db),
+		RoutingPolicy:     NewSQLiteRoutingPolicyRepository(db),
+		SpendCap:          NewSQLiteSpendCapRepository(db),
+		Proxy:             NewSQLiteProxyRepository(db),
+		AuthProfile:       NewSQLiteAuthProfileRepository(db),
 		Webhook:           NewSQLiteWebhookRepository(db),
 		WebhookDelivery:   NewSQLiteWebhookDeliveryRepository(db),
 		RateLimit:         NewSQLiteRateLimitRepository(db),
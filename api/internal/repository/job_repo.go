@@ -28,8 +28,8 @@ func (r *SQLiteJobRepository) Create(ctx context.Context, job *models.Job) error
 			result_json, error_message, error_details, error_category,
 			llm_configs_json, tier, is_byok, llm_provider, llm_model, discovery_method, urls_queued, page_count,
 			token_usage_input, token_usage_output, cost_usd, llm_cost_usd, capture_debug, webhook_url, webhook_status,
-			webhook_attempts, started_at, completed_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			webhook_attempts, trace_parent, started_at, completed_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	isBYOK := 0
 	if job.IsBYOK {
@@ -67,6 +67,7 @@ func (r *SQLiteJobRepository) Create(ctx context.Context, job *models.Job) error
 		nullString(job.WebhookURL),
 		nullString(job.WebhookStatus),
 		job.WebhookAttempts,
+		nullString(job.TraceParent),
 		nullTime(job.StartedAt),
 		nullTime(job.CompletedAt),
 		job.CreatedAt.Format(time.RFC3339),
@@ -84,7 +85,7 @@ func (r *SQLiteJobRepository) GetByID(ctx context.Context, id string) (*models.J
 			result_json, error_message, error_details, error_category,
 			llm_configs_json, tier, is_byok, llm_provider, llm_model, discovery_method, urls_queued, page_count,
 			token_usage_input, token_usage_output, cost_usd, llm_cost_usd, capture_debug, webhook_url, webhook_status,
-			webhook_attempts, started_at, completed_at, created_at, updated_at
+			webhook_attempts, trace_parent, started_at, completed_at, created_at, updated_at
 		FROM jobs WHERE id = ?
 	`
 	return r.scanJob(r.db.QueryRowContext(ctx, query, id))
@@ -96,7 +97,7 @@ func (r *SQLiteJobRepository) GetByUserID(ctx context.Context, userID string, li
 			result_json, error_message, error_details, error_category,
 			llm_configs_json, tier, is_byok, llm_provider, llm_model, discovery_method, urls_queued, page_count,
 			token_usage_input, token_usage_output, cost_usd, llm_cost_usd, capture_debug, webhook_url, webhook_status,
-			webhook_attempts, started_at, completed_at, created_at, updated_at
+			webhook_attempts, trace_parent, started_at, completed_at, created_at, updated_at
 		FROM jobs WHERE user_id = ? ORDER BY created_at DESC LIMIT ? OFFSET ?
 	`
 	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
@@ -164,7 +165,7 @@ func (r *SQLiteJobRepository) GetPending(ctx context.Context, limit int) ([]*mod
 			result_json, error_message, error_details, error_category,
 			llm_configs_json, tier, is_byok, llm_provider, llm_model, discovery_method, urls_queued, page_count,
 			token_usage_input, token_usage_output, cost_usd, llm_cost_usd, capture_debug, webhook_url, webhook_status,
-			webhook_attempts, started_at, completed_at, created_at, updated_at
+			webhook_attempts, trace_parent, started_at, completed_at, created_at, updated_at
 		FROM jobs WHERE status = 'pending' AND type = 'crawl' ORDER BY created_at ASC LIMIT ?
 	`
 	rows, err := r.db.QueryContext(ctx, query, limit)
@@ -214,7 +215,7 @@ func (r *SQLiteJobRepository) ClaimJob(ctx context.Context, id string) (*models.
 			result_json, error_message, error_details, error_category,
 			llm_configs_json, tier, is_byok, llm_provider, llm_model, discovery_method, urls_queued, page_count,
 			token_usage_input, token_usage_output, cost_usd, llm_cost_usd, capture_debug, webhook_url, webhook_status,
-			webhook_attempts, started_at, completed_at, created_at, updated_at
+			webhook_attempts, trace_parent, started_at, completed_at, created_at, updated_at
 		FROM jobs WHERE id = ?
 	`
 	job, err := r.scanJob(tx.QueryRowContext(ctx, query, id))
@@ -318,7 +319,7 @@ func (r *SQLiteJobRepository) ClaimPendingWithLimits(ctx context.Context, tierLi
 			result_json, error_message, error_details, error_category,
 			llm_configs_json, tier, is_byok, llm_provider, llm_model, discovery_method, urls_queued, page_count,
 			token_usage_input, token_usage_output, cost_usd, llm_cost_usd, capture_debug, webhook_url, webhook_status,
-			webhook_attempts, started_at, completed_at, created_at, updated_at
+			webhook_attempts, trace_parent, started_at, completed_at, created_at, updated_at
 	`
 
 	job, err := r.scanJob(tx.QueryRowContext(ctx, query, now, now,
@@ -345,7 +346,7 @@ func (r *SQLiteJobRepository) scanJob(row *sql.Row) (*models.Job, error) {
 	var isBYOK, captureDebug int
 	var crawlOptionsJSON, resultJSON, errorMessage, errorDetails, errorCategory sql.NullString
 	var llmConfigsJSON, tier, llmProvider, llmModel, discoveryMethod sql.NullString
-	var webhookURL, webhookStatus sql.NullString
+	var webhookURL, webhookStatus, traceParent sql.NullString
 	var startedAt, completedAt sql.NullString
 
 	err := row.Scan(
@@ -356,7 +357,7 @@ func (r *SQLiteJobRepository) scanJob(row *sql.Row) (*models.Job, error) {
 		&job.URLsQueued, &job.PageCount,
 		&job.TokenUsageInput, &job.TokenUsageOutput, &job.CostUSD, &job.LLMCostUSD,
 		&captureDebug, &webhookURL, &webhookStatus, &job.WebhookAttempts,
-		&startedAt, &completedAt, &createdAt, &updatedAt,
+		&traceParent, &startedAt, &completedAt, &createdAt, &updatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -379,6 +380,7 @@ func (r *SQLiteJobRepository) scanJob(row *sql.Row) (*models.Job, error) {
 	job.DiscoveryMethod = discoveryMethod.String
 	job.WebhookURL = webhookURL.String
 	job.WebhookStatus = webhookStatus.String
+	job.TraceParent = traceParent.String
 	job.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	job.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 	if startedAt.Valid {
@@ -399,7 +401,7 @@ func (r *SQLiteJobRepository) scanJobFromRows(rows *sql.Rows) (*models.Job, erro
 	var isBYOK, captureDebug int
 	var crawlOptionsJSON, resultJSON, errorMessage, errorDetails, errorCategory sql.NullString
 	var llmConfigsJSON, tier, llmProvider, llmModel, discoveryMethod sql.NullString
-	var webhookURL, webhookStatus sql.NullString
+	var webhookURL, webhookStatus, traceParent sql.NullString
 	var startedAt, completedAt sql.NullString
 
 	err := rows.Scan(
@@ -410,7 +412,7 @@ func (r *SQLiteJobRepository) scanJobFromRows(rows *sql.Rows) (*models.Job, erro
 		&job.URLsQueued, &job.PageCount,
 		&job.TokenUsageInput, &job.TokenUsageOutput, &job.CostUSD, &job.LLMCostUSD,
 		&captureDebug, &webhookURL, &webhookStatus, &job.WebhookAttempts,
-		&startedAt, &completedAt, &createdAt, &updatedAt,
+		&traceParent, &startedAt, &completedAt, &createdAt, &updatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to scan job: %w", err)
@@ -430,6 +432,7 @@ func (r *SQLiteJobRepository) scanJobFromRows(rows *sql.Rows) (*models.Job, erro
 	job.DiscoveryMethod = discoveryMethod.String
 	job.WebhookURL = webhookURL.String
 	job.WebhookStatus = webhookStatus.String
+	job.TraceParent = traceParent.String
 	job.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	job.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 	if startedAt.Valid {
@@ -944,6 +947,46 @@ func (r *SQLiteUsageRepository) CountByUserAndDateRange(ctx context.Context, use
 	return count, err
 }
 
+// GetStatementItems returns the user's usage records in a date range with their insight
+// cost breakdown, oldest first. Records without an insight have zero token and cost details.
+func (r *SQLiteUsageRepository) GetStatementItems(ctx context.Context, userID string, startDate, endDate time.Time) ([]*models.StatementItem, error) {
+	query := `SELECT u.id, u.job_id, u.date, u.type, u.status, u.is_byok, u.total_charged_usd,
+		i.target_url, COALESCE(i.tokens_input, 0), COALESCE(i.tokens_output, 0),
+		COALESCE(i.llm_cost_usd, 0), COALESCE(i.markup_rate, 0), COALESCE(i.markup_usd, 0), COALESCE(i.solver_cost_usd, 0),
+		i.llm_provider, i.llm_model, u.created_at
+		FROM usage_records u
+		LEFT JOIN usage_insights i ON i.usage_id = u.id
+		WHERE u.user_id = ? AND u.date >= ? AND u.date < ?
+		ORDER BY u.created_at ASC, u.id ASC`
+	rows, err := r.db.QueryContext(ctx, query, userID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var items []*models.StatementItem
+	for rows.Next() {
+		var item models.StatementItem
+		var jobID, targetURL, provider, model sql.NullString
+		var isBYOK int
+		var createdAt string
+		if err := rows.Scan(&item.UsageID, &jobID, &item.Date, &item.Type, &item.Status, &isBYOK, &item.TotalChargedUSD,
+			&targetURL, &item.TokensInput, &item.TokensOutput,
+			&item.LLMCostUSD, &item.MarkupRate, &item.MarkupUSD, &item.SolverCostUSD,
+			&provider, &model, &createdAt); err != nil {
+			return nil, err
+		}
+		item.JobID = jobID.String
+		item.IsBYOK = isBYOK == 1
+		item.TargetURL = targetURL.String
+		item.LLMProvider = provider.String
+		item.LLMModel = model.String
+		item.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
 // SQLiteTelemetryRepository implements TelemetryRepository for SQLite.
 type SQLiteTelemetryRepository struct {
 	db *sql.DB
@@ -1043,6 +1086,32 @@ func (r *SQLiteLicenseRepository) GetByKey(ctx context.Context, key string) (*mo
 	return &license, nil
 }
 
+func (r *SQLiteLicenseRepository) GetActive(ctx context.Context) (*models.License, error) {
+	query := `SELECT id, license_key, organization_name, email, tier, max_users, features, issued_at, expires_at, revoked_at, created_at FROM licenses WHERE revoked_at IS NULL ORDER BY created_at DESC, id DESC LIMIT 1`
+	row := r.db.QueryRowContext(ctx, query)
+	var license models.License
+	var featuresJSON sql.NullString
+	var expiresAt, revokedAt sql.NullString
+	var issuedAt, createdAt string
+	err := row.Scan(&license.ID, &license.LicenseKey, &license.OrganizationName, &license.Email, &license.Tier, &license.MaxUsers, &featuresJSON, &issuedAt, &expiresAt, &revokedAt, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if featuresJSON.Valid {
+		_ = json.Unmarshal([]byte(featuresJSON.String), &license.Features)
+	}
+	license.IssuedAt, _ = time.Parse(time.RFC3339, issuedAt)
+	license.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	if expiresAt.Valid {
+		t, _ := time.Parse(time.RFC3339, expiresAt.String)
+		license.ExpiresAt = &t
+	}
+	return &license, nil
+}
+
 func (r *SQLiteLicenseRepository) Update(ctx context.Context, license *models.License) error {
 	query := `UPDATE licenses SET organization_name = ?, email = ?, tier = ?, max_users = ?, features = ?, expires_at = ?, revoked_at = ? WHERE id = ?`
 	featuresJSON, _ := json.Marshal(license.Features)
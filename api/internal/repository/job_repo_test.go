@@ -14,14 +14,15 @@ func TestJobRepository_Create(t *testing.T) {
 	ctx := context.Background()
 
 	job := &models.Job{
-		ID:         ulid.Make().String(),
-		UserID:     "user_123",
-		Type:       models.JobTypeExtract,
-		Status:     models.JobStatusPending,
-		URL:        "https://example.com",
-		SchemaJSON: `{"type": "object"}`,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		ID:          ulid.Make().String(),
+		UserID:      "user_123",
+		Type:        models.JobTypeExtract,
+		Status:      models.JobStatusPending,
+		URL:         "https://example.com",
+		SchemaJSON:  `{"type": "object"}`,
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}
 
 	err := repos.Job.Create(ctx, job)
@@ -46,6 +47,9 @@ func TestJobRepository_Create(t *testing.T) {
 	if got.Status != job.Status {
 		t.Errorf("Status = %s, want %s", got.Status, job.Status)
 	}
+	if got.TraceParent != job.TraceParent {
+		t.Errorf("TraceParent = %s, want %s", got.TraceParent, job.TraceParent)
+	}
 }
 
 func TestJobRepository_GetByID_NotFound(t *testing.T) {
@@ -642,3 +646,72 @@ func TestJobResultRepository_CountByJobID(t *testing.T) {
 		t.Errorf("count = %d, want 5", count)
 	}
 }
+
+// ========================================
+// LicenseRepository Tests
+// ========================================
+
+func TestLicenseRepository_GetActive(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+
+	active, err := repos.License.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("GetActive() error = %v", err)
+	}
+	if active != nil {
+		t.Fatalf("GetActive() = %+v, want nil with no licenses", active)
+	}
+
+	base := time.Now().UTC().Truncate(time.Second)
+	expires := base.Add(30 * 24 * time.Hour)
+	older := &models.License{
+		ID:               ulid.Make().String(),
+		LicenseKey:       "rfl1.older",
+		OrganizationName: "Acme",
+		Email:            "ops@acme.test",
+		Tier:             "selfhosted",
+		MaxUsers:         10,
+		Features:         []string{"webhooks"},
+		IssuedAt:         base,
+		ExpiresAt:        &expires,
+		CreatedAt:        base.Add(-time.Hour),
+	}
+	newer := &models.License{
+		ID:               ulid.Make().String(),
+		LicenseKey:       "rfl1.newer",
+		OrganizationName: "Acme",
+		Tier:             "selfhosted",
+		IssuedAt:         base,
+		CreatedAt:        base,
+	}
+	for _, lic := range []*models.License{older, newer} {
+		if err := repos.License.Create(ctx, lic); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	active, err = repos.License.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("GetActive() error = %v", err)
+	}
+	if active == nil || active.ID != newer.ID {
+		t.Fatalf("GetActive() = %+v, want newest license %s", active, newer.ID)
+	}
+
+	// Revoking the newest falls back to the older license
+	newer.RevokedAt = &base
+	if err := repos.License.Update(ctx, newer); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	active, err = repos.License.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("GetActive() error = %v", err)
+	}
+	if active == nil || active.ID != older.ID {
+		t.Fatalf("GetActive() = %+v, want %s", active, older.ID)
+	}
+	if active.MaxUsers != 10 || len(active.Features) != 1 || active.ExpiresAt == nil || !active.ExpiresAt.Equal(expires) {
+		t.Errorf("GetActive() = %+v, fields not round-tripped", active)
+	}
+}
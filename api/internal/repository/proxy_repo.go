@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// SQLiteProxyRepository implements ProxyRepository for SQLite/libsql.
+type SQLiteProxyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteProxyRepository creates a new SQLite proxy repository.
+func NewSQLiteProxyRepository(db *sql.DB) *SQLiteProxyRepository {
+	return &SQLiteProxyRepository{db: db}
+}
+
+const proxyColumns = `id, user_id, name, endpoint, url_encrypted, country, is_enabled, created_at, updated_at`
+
+// scanProxy scans a proxy from a row.
+func scanProxy(row interface{ Scan(...any) error }) (*models.Proxy, error) {
+	proxy := &models.Proxy{}
+	var createdAt, updatedAt string
+	if err := row.Scan(
+		&proxy.ID,
+		&proxy.UserID,
+		&proxy.Name,
+		&proxy.Endpoint,
+		&proxy.URLEncrypted,
+		&proxy.Country,
+		&proxy.IsEnabled,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	proxy.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	proxy.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return proxy, nil
+}
+
+// Create stores a new proxy, setting its ID and timestamps.
+func (r *SQLiteProxyRepository) Create(ctx context.Context, proxy *models.Proxy) error {
+	now := time.Now().UTC()
+	if proxy.ID == "" {
+		proxy.ID = ulid.Make().String()
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO proxies (`+proxyColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, proxy.ID, proxy.UserID, proxy.Name, proxy.Endpoint, proxy.URLEncrypted, proxy.Country, proxy.IsEnabled,
+		now.Format(time.RFC3339), now.Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	proxy.CreatedAt = now
+	proxy.UpdatedAt = now
+	return nil
+}
+
+// Update saves a proxy's name, URL, country and enabled state.
+func (r *SQLiteProxyRepository) Update(ctx context.Context, proxy *models.Proxy) error {
+	now := time.Now().UTC()
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE proxies
+		SET name = ?, endpoint = ?, url_encrypted = ?, country = ?, is_enabled = ?, updated_at = ?
+		WHERE id = ?
+	`, proxy.Name, proxy.Endpoint, proxy.URLEncrypted, proxy.Country, proxy.IsEnabled, now.Format(time.RFC3339), proxy.ID)
+	if err != nil {
+		return err
+	}
+	proxy.UpdatedAt = now
+	return nil
+}
+
+// GetByID returns a proxy by ID, or nil if it doesn't exist.
+func (r *SQLiteProxyRepository) GetByID(ctx context.Context, id string) (*models.Proxy, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+proxyColumns+` FROM proxies WHERE id = ?`, id)
+	proxy, err := scanProxy(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return proxy, err
+}
+
+// ListByOwner returns a user's proxies, or the managed pool when userID is empty.
+func (r *SQLiteProxyRepository) ListByOwner(ctx context.Context, userID string) ([]*models.Proxy, error) {
+	return r.list(ctx, `WHERE user_id = ?`, userID)
+}
+
+// ListEnabledByOwner returns a user's enabled proxies, or the enabled managed pool when userID is empty.
+func (r *SQLiteProxyRepository) ListEnabledByOwner(ctx context.Context, userID string) ([]*models.Proxy, error) {
+	return r.list(ctx, `WHERE user_id = ? AND is_enabled = 1`, userID)
+}
+
+// list returns the proxies matching a WHERE clause.
+func (r *SQLiteProxyRepository) list(ctx context.Context, where string, args ...any) ([]*models.Proxy, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+proxyColumns+` FROM proxies `+where+` ORDER BY name, id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var proxies []*models.Proxy
+	for rows.Next() {
+		proxy, err := scanProxy(rows)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, proxy)
+	}
+
+	return proxies, rows.Err()
+}
+
+// Delete removes a proxy and its health history.
+func (r *SQLiteProxyRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM proxy_health WHERE proxy_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM proxies WHERE id = ?`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RecordOutcome counts a fetch outcome for a proxy, both for the domain and across all domains.
+func (r *SQLiteProxyRepository) RecordOutcome(ctx context.Context, proxyID, domain string, outcome models.ProxyOutcome, at time.Time) error {
+	var successes, failures, bans int
+	var bannedAt *string
+	switch outcome {
+	case models.ProxyOutcomeSuccess:
+		successes = 1
+	case models.ProxyOutcomeBan:
+		bans = 1
+		ts := at.UTC().Format(time.RFC3339)
+		bannedAt = &ts
+	default:
+		failures = 1
+	}
+
+	domains := []string{""}
+	if domain != "" {
+		domains = append(domains, domain)
+	}
+	for _, d := range domains {
+		_, err := r.db.ExecContext(ctx, `
+			INSERT INTO proxy_health (proxy_id, domain, successes, failures, bans, last_banned_at, last_used_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(proxy_id, domain) DO UPDATE SET
+				successes = successes + excluded.successes,
+				failures = failures + excluded.failures,
+				bans = bans + excluded.bans,
+				last_banned_at = COALESCE(excluded.last_banned_at, last_banned_at),
+				last_used_at = excluded.last_used_at
+		`, proxyID, d, successes, failures, bans, bannedAt, at.UTC().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetHealth returns the health of every proxy for a domain and across all domains (Domain "").
+func (r *SQLiteProxyRepository) GetHealth(ctx context.Context, domain string) ([]*models.ProxyHealth, error) {
+	return r.listHealth(ctx, `WHERE domain IN (?, '')`, domain)
+}
+
+// ListHealthByProxyID returns a proxy's health for every domain it has fetched.
+func (r *SQLiteProxyRepository) ListHealthByProxyID(ctx context.Context, proxyID string) ([]*models.ProxyHealth, error) {
+	return r.listHealth(ctx, `WHERE proxy_id = ?`, proxyID)
+}
+
+// listHealth returns the health rows matching a WHERE clause.
+func (r *SQLiteProxyRepository) listHealth(ctx context.Context, where string, args ...any) ([]*models.ProxyHealth, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT proxy_id, domain, successes, failures, bans, last_banned_at, last_used_at
+		FROM proxy_health `+where+`
+		ORDER BY proxy_id, domain
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var health []*models.ProxyHealth
+	for rows.Next() {
+		h := &models.ProxyHealth{}
+		var bannedAt sql.NullString
+		var usedAt string
+		if err := rows.Scan(&h.ProxyID, &h.Domain, &h.Successes, &h.Failures, &h.Bans, &bannedAt, &usedAt); err != nil {
+			return nil, err
+		}
+		if bannedAt.Valid {
+			if t, err := time.Parse(time.RFC3339, bannedAt.String); err == nil {
+				h.LastBannedAt = &t
+			}
+		}
+		h.LastUsedAt, _ = time.Parse(time.RFC3339, usedAt)
+		health = append(health, h)
+	}
+
+	return health, rows.Err()
+}
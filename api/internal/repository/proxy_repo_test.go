@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// ========================================
+// ProxyRepository Tests
+// ========================================
+
+func TestProxyRepository_CreateListUpdateDelete(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+
+	pooled := &models.Proxy{Name: "de-1", Endpoint: "http://de.example.com:8080", URLEncrypted: "enc-1", Country: "DE", IsEnabled: true}
+	owned := &models.Proxy{UserID: "user-1", Name: "mine", Endpoint: "http://mine.example.com:3128", URLEncrypted: "enc-2", IsEnabled: true}
+	for _, p := range []*models.Proxy{pooled, owned} {
+		if err := repos.Proxy.Create(ctx, p); err != nil {
+			t.Fatalf("failed to create proxy: %v", err)
+		}
+		if p.ID == "" {
+			t.Fatal("expected ID to be set")
+		}
+	}
+
+	pool, err := repos.Proxy.ListByOwner(ctx, "")
+	if err != nil {
+		t.Fatalf("failed to list pool: %v", err)
+	}
+	if len(pool) != 1 || pool[0].ID != pooled.ID || pool[0].URLEncrypted != "enc-1" || pool[0].Country != "DE" {
+		t.Errorf("unexpected pool: %+v", pool)
+	}
+
+	owned.IsEnabled = false
+	owned.Name = "mine (off)"
+	if err := repos.Proxy.Update(ctx, owned); err != nil {
+		t.Fatalf("failed to update proxy: %v", err)
+	}
+	enabled, err := repos.Proxy.ListEnabledByOwner(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("failed to list enabled proxies: %v", err)
+	}
+	if len(enabled) != 0 {
+		t.Errorf("expected no enabled proxies, got %d", len(enabled))
+	}
+	got, err := repos.Proxy.GetByID(ctx, owned.ID)
+	if err != nil {
+		t.Fatalf("failed to get proxy: %v", err)
+	}
+	if got == nil || got.Name != "mine (off)" || got.IsEnabled {
+		t.Errorf("unexpected proxy: %+v", got)
+	}
+
+	if err := repos.Proxy.RecordOutcome(ctx, owned.ID, "example.com", models.ProxyOutcomeSuccess, time.Now()); err != nil {
+		t.Fatalf("failed to record outcome: %v", err)
+	}
+	if err := repos.Proxy.Delete(ctx, owned.ID); err != nil {
+		t.Fatalf("failed to delete proxy: %v", err)
+	}
+	if got, _ := repos.Proxy.GetByID(ctx, owned.ID); got != nil {
+		t.Error("expected proxy to be deleted")
+	}
+	if health, _ := repos.Proxy.ListHealthByProxyID(ctx, owned.ID); len(health) != 0 {
+		t.Errorf("expected health to be deleted, got %d rows", len(health))
+	}
+}
+
+func TestProxyRepository_RecordOutcome(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 16, 0, 0, 0, time.UTC)
+
+	for _, outcome := range []models.ProxyOutcome{models.ProxyOutcomeSuccess, models.ProxyOutcomeSuccess, models.ProxyOutcomeFailure} {
+		if err := repos.Proxy.RecordOutcome(ctx, "proxy-1", "shop.example.com", outcome, now); err != nil {
+			t.Fatalf("failed to record outcome: %v", err)
+		}
+	}
+	if err := repos.Proxy.RecordOutcome(ctx, "proxy-1", "news.example.com", models.ProxyOutcomeBan, now); err != nil {
+		t.Fatalf("failed to record ban: %v", err)
+	}
+
+	health, err := repos.Proxy.GetHealth(ctx, "shop.example.com")
+	if err != nil {
+		t.Fatalf("failed to get health: %v", err)
+	}
+	if len(health) != 2 {
+		t.Fatalf("expected overall and domain rows, got %d", len(health))
+	}
+	overall, shop := health[0], health[1]
+	if overall.Domain != "" || overall.Successes != 2 || overall.Failures != 1 || overall.Bans != 1 {
+		t.Errorf("unexpected overall health: %+v", overall)
+	}
+	if overall.LastBannedAt == nil || !overall.LastBannedAt.Equal(now) {
+		t.Errorf("LastBannedAt = %v, want %v", overall.LastBannedAt, now)
+	}
+	if shop.Domain != "shop.example.com" || shop.Successes != 2 || shop.Bans != 0 || shop.LastBannedAt != nil {
+		t.Errorf("unexpected domain health: %+v", shop)
+	}
+
+	// A later success keeps the last ban time
+	if err := repos.Proxy.RecordOutcome(ctx, "proxy-1", "news.example.com", models.ProxyOutcomeSuccess, now.Add(time.Minute)); err != nil {
+		t.Fatalf("failed to record outcome: %v", err)
+	}
+	all, err := repos.Proxy.ListHealthByProxyID(ctx, "proxy-1")
+	if err != nil {
+		t.Fatalf("failed to list health: %v", err)
+	}
+	if len(all) != 3 || all[1].Domain != "news.example.com" || all[1].LastBannedAt == nil {
+		t.Errorf("unexpected health rows: %+v", all)
+	}
+}
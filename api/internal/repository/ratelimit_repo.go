@@ -5,7 +5,9 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -18,6 +20,7 @@ type APIKeyRateLimit struct {
 }
 
 // RateLimitRepository handles API key rate limit persistence.
+// It tracks both provider key suspensions and sliding window request counts.
 type RateLimitRepository interface {
 	// IsSuspended checks if an API key is currently suspended.
 	IsSuspended(ctx context.Context, apiKey string) (bool, error)
@@ -26,7 +29,11 @@ type RateLimitRepository interface {
 	MarkRateLimited(ctx context.Context, apiKey string) (time.Duration, error)
 	// ClearSuspension removes the suspension for an API key.
 	ClearSuspension(ctx context.Context, apiKey string) error
-	// CleanupExpired removes expired rate limit entries.
+	// CountRequest counts a request for a key in the current bucket of each sliding
+	// window and returns every window's current and previous bucket counts, including
+	// the request. The request is kept only if admit accepts the counts.
+	CountRequest(ctx context.Context, key string, windows []time.Duration, now time.Time, admit func(current, previous []int) bool) (current, previous []int, err error)
+	// CleanupExpired removes expired rate limit entries and request counts.
 	CleanupExpired(ctx context.Context) (int64, error)
 	// GetStats returns rate limiting statistics.
 	GetStats(ctx context.Context) (RateLimitStats, error)
@@ -43,6 +50,8 @@ type SQLiteRateLimitRepository struct {
 	db          *sql.DB
 	baseBackoff time.Duration
 	maxBackoff  time.Duration
+
+	countMu sync.Mutex // Serializes CountRequest transactions, as SQLite has one writer at a time
 }
 
 // NewSQLiteRateLimitRepository creates a new rate limit repository.
@@ -159,18 +168,87 @@ func (r *SQLiteRateLimitRepository) ClearSuspension(ctx context.Context, apiKey
 	return nil
 }
 
-// CleanupExpired removes expired rate limit entries.
+// bucketStart returns the unix start of the window bucket containing t.
+func bucketStart(t time.Time, window time.Duration) int64 {
+	return t.Truncate(window).Unix()
+}
+
+// CountRequest counts a request for a key in the current bucket of each sliding
+// window and returns every window's current and previous bucket counts, including
+// the request. The counts are incremented and read in one transaction, so concurrent
+// requests each see the counts of those before them; the transaction is rolled back
+// unless admit accepts the counts, so rejected requests aren't counted.
+func (r *SQLiteRateLimitRepository) CountRequest(ctx context.Context, key string, windows []time.Duration, now time.Time, admit func(current, previous []int) bool) ([]int, []int, error) {
+	// Queue this instance's requests here rather than on the database lock
+	r.countMu.Lock()
+	defer r.countMu.Unlock()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	keyHash := hashKey(key)
+	current := make([]int, len(windows))
+	previous := make([]int, len(windows))
+	for i, window := range windows {
+		seconds := int64(window.Seconds())
+		start := bucketStart(now, window)
+
+		// Increment first, so the transaction holds the write lock before reading
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO request_rate_counters (key_hash, window_seconds, bucket_start, count)
+			VALUES (?, ?, ?, 1)
+			ON CONFLICT(key_hash, window_seconds, bucket_start) DO UPDATE SET
+				count = count + 1
+			RETURNING count
+		`, keyHash, seconds, start).Scan(&current[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to increment request count: %w", err)
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			SELECT count FROM request_rate_counters
+			WHERE key_hash = ? AND window_seconds = ? AND bucket_start = ?
+		`, keyHash, seconds, start-seconds).Scan(&previous[i])
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, fmt.Errorf("failed to get request count: %w", err)
+		}
+	}
+
+	if !admit(current, previous) {
+		return current, previous, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit request count: %w", err)
+	}
+	return current, previous, nil
+}
+
+// CleanupExpired removes expired rate limit entries, and request counts that are
+// too old to fall in any sliding window.
 func (r *SQLiteRateLimitRepository) CleanupExpired(ctx context.Context) (int64, error) {
-	now := time.Now().Format(time.RFC3339)
+	now := time.Now()
 
 	result, err := r.db.ExecContext(ctx, `
 		DELETE FROM api_key_rate_limits WHERE suspended_until < ?
-	`, now)
+	`, now.Format(time.RFC3339))
 	if err != nil {
 		return 0, fmt.Errorf("failed to cleanup expired: %w", err)
 	}
+	deleted, _ := result.RowsAffected()
+
+	// A bucket is still read as the previous bucket until two windows after it started
+	result, err = r.db.ExecContext(ctx, `
+		DELETE FROM request_rate_counters WHERE bucket_start + 2 * window_seconds < ?
+	`, now.Unix())
+	if err != nil {
+		return deleted, fmt.Errorf("failed to cleanup request counts: %w", err)
+	}
+	counts, _ := result.RowsAffected()
 
-	return result.RowsAffected()
+	return deleted + counts, nil
 }
 
 // GetStats returns rate limiting statistics.
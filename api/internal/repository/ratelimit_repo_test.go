@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/database/migrations"
+)
+
+// ========================================
+// RateLimitRepository Request Count Tests
+// ========================================
+
+// countRequest counts a request in window, failing the test on error.
+func countRequest(t *testing.T, repo RateLimitRepository, key string, window time.Duration, now time.Time) {
+	t.Helper()
+	admit := func(current, previous []int) bool { return true }
+	if _, _, err := repo.CountRequest(context.Background(), key, []time.Duration{window}, now, admit); err != nil {
+		t.Fatalf("failed to count request: %v", err)
+	}
+}
+
+// requestCounts returns the counts for key in window without counting a request.
+func requestCounts(t *testing.T, repo RateLimitRepository, key string, window time.Duration, now time.Time) (int, int) {
+	t.Helper()
+	reject := func(current, previous []int) bool { return false }
+	current, previous, err := repo.CountRequest(context.Background(), key, []time.Duration{window}, now, reject)
+	if err != nil {
+		t.Fatalf("failed to get request counts: %v", err)
+	}
+	return current[0] - 1, previous[0]
+}
+
+func TestRateLimitRepository_CountRequest(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 30, 20, 0, time.UTC)
+
+	// Two requests in the previous minute, three in the current one
+	for i := 0; i < 2; i++ {
+		countRequest(t, repos.RateLimit, "user:1", time.Minute, now.Add(-time.Minute))
+	}
+	for i := 0; i < 3; i++ {
+		countRequest(t, repos.RateLimit, "user:1", time.Minute, now)
+	}
+	// Counts are kept per key and window
+	countRequest(t, repos.RateLimit, "user:1", time.Hour, now)
+	countRequest(t, repos.RateLimit, "user:2", time.Minute, now)
+
+	// The returned counts include the request, for every window
+	var seen [][2]int
+	current, previous, err := repos.RateLimit.CountRequest(ctx, "user:1", []time.Duration{time.Minute, time.Hour}, now, func(current, previous []int) bool {
+		seen = append(seen, [2]int{current[0], previous[0]}, [2]int{current[1], previous[1]})
+		return true
+	})
+	if err != nil {
+		t.Fatalf("failed to count request: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != [2]int{4, 2} || seen[1] != [2]int{2, 0} {
+		t.Errorf("admit saw %v, want minute 4/2 and hour 2/0", seen)
+	}
+	if current[0] != 4 || previous[0] != 2 || current[1] != 2 {
+		t.Errorf("counts = %v/%v, want minute 4/2 and hour 2/0", current, previous)
+	}
+
+	// Rejected requests are rolled back
+	if current, _ := requestCounts(t, repos.RateLimit, "user:1", time.Minute, now); current != 4 {
+		t.Errorf("minute count = %d after a rejected request, want 4", current)
+	}
+	if current, _ := requestCounts(t, repos.RateLimit, "user:3", time.Minute, now); current != 0 {
+		t.Errorf("unknown key count = %d, want 0", current)
+	}
+}
+
+func TestRateLimitRepository_CountRequestConcurrent(t *testing.T) {
+	// Concurrent transactions need a database shared between connections
+	db, err := sql.Open("libsql", "file:"+filepath.Join(t.TempDir(), "ratelimit.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 30000"} {
+		var result string
+		_ = db.QueryRow(pragma).Scan(&result)
+	}
+	if err := migrations.Run(db, nil); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	repo := NewSQLiteRateLimitRepository(db)
+
+	const limit, requests = 5, 20
+	now := time.Now()
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			admitted := false
+			_, _, err := repo.CountRequest(context.Background(), "user:1", []time.Duration{time.Hour}, now, func(current, previous []int) bool {
+				admitted = current[0] <= limit
+				return admitted
+			})
+			if err != nil {
+				t.Errorf("failed to count request: %v", err)
+				return
+			}
+			if admitted {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != limit {
+		t.Errorf("admitted %d of %d concurrent requests, want exactly %d", got, requests, limit)
+	}
+	if current, _ := requestCounts(t, repo, "user:1", time.Hour, now); current != limit {
+		t.Errorf("counted %d requests, want %d", current, limit)
+	}
+}
+
+func TestRateLimitRepository_CleanupExpiredRequestCounts(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	countRequest(t, repos.RateLimit, "user:1", time.Minute, now.Add(-10*time.Minute))
+	countRequest(t, repos.RateLimit, "user:1", time.Minute, now)
+	// Still within the previous bucket of the day window
+	countRequest(t, repos.RateLimit, "user:1", 24*time.Hour, now.Add(-24*time.Hour))
+
+	deleted, err := repos.RateLimit.CleanupExpired(ctx)
+	if err != nil {
+		t.Fatalf("failed to clean up: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted = %d, want 1", deleted)
+	}
+
+	if current, _ := requestCounts(t, repos.RateLimit, "user:1", time.Minute, now); current != 1 {
+		t.Errorf("current count = %d, want 1", current)
+	}
+	if _, previous := requestCounts(t, repos.RateLimit, "user:1", 24*time.Hour, now); previous != 1 {
+		t.Errorf("previous day count = %d, want 1", previous)
+	}
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// SQLiteRoutingPolicyRepository implements RoutingPolicyRepository for SQLite/libsql.
+type SQLiteRoutingPolicyRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteRoutingPolicyRepository creates a new SQLite routing policy repository.
+func NewSQLiteRoutingPolicyRepository(db *sql.DB) *SQLiteRoutingPolicyRepository {
+	return &SQLiteRoutingPolicyRepository{db: db}
+}
+
+// Get returns the routing policy for a chain scope, or "" if none is set.
+func (r *SQLiteRoutingPolicyRepository) Get(ctx context.Context, scope string) (models.RoutingPolicy, error) {
+	var policy string
+	err := r.db.QueryRowContext(ctx, `SELECT policy FROM chain_routing_policies WHERE scope = ?`, scope).Scan(&policy)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return models.RoutingPolicy(policy), nil
+}
+
+// GetAll returns every configured routing policy keyed by scope.
+func (r *SQLiteRoutingPolicyRepository) GetAll(ctx context.Context) (map[string]models.RoutingPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT scope, policy FROM chain_routing_policies ORDER BY scope`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	policies := make(map[string]models.RoutingPolicy)
+	for rows.Next() {
+		var scope, policy string
+		if err := rows.Scan(&scope, &policy); err != nil {
+			return nil, err
+		}
+		policies[scope] = models.RoutingPolicy(policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// Set creates or replaces the routing policy for a chain scope.
+func (r *SQLiteRoutingPolicyRepository) Set(ctx context.Context, scope string, policy models.RoutingPolicy) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO chain_routing_policies (scope, policy, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(scope) DO UPDATE SET policy = excluded.policy, updated_at = excluded.updated_at
+	`, scope, string(policy), time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// Delete removes the routing policy for a chain scope, reverting it to ordered.
+func (r *SQLiteRoutingPolicyRepository) Delete(ctx context.Context, scope string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM chain_routing_policies WHERE scope = ?`, scope)
+	return err
+}
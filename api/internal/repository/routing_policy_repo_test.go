@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// ========================================
+// RoutingPolicyRepository Tests
+// ========================================
+
+func TestRoutingPolicyRepository_SetGetDelete(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+
+	policy, err := repos.RoutingPolicy.Get(ctx, "tier:default")
+	if err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if policy != "" {
+		t.Errorf("expected no policy, got %q", policy)
+	}
+
+	if err := repos.RoutingPolicy.Set(ctx, "tier:default", models.RoutingFastest); err != nil {
+		t.Fatalf("failed to set policy: %v", err)
+	}
+	// Setting again replaces the existing row
+	if err := repos.RoutingPolicy.Set(ctx, "tier:default", models.RoutingCheapestCapable); err != nil {
+		t.Fatalf("failed to update policy: %v", err)
+	}
+	if err := repos.RoutingPolicy.Set(ctx, "user:user-1", models.RoutingWeighted); err != nil {
+		t.Fatalf("failed to set user policy: %v", err)
+	}
+
+	policy, err = repos.RoutingPolicy.Get(ctx, "tier:default")
+	if err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	if policy != models.RoutingCheapestCapable {
+		t.Errorf("policy = %q, want cheapest_capable", policy)
+	}
+
+	all, err := repos.RoutingPolicy.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("failed to get all policies: %v", err)
+	}
+	if len(all) != 2 || all["user:user-1"] != models.RoutingWeighted {
+		t.Errorf("unexpected policies: %v", all)
+	}
+
+	if err := repos.RoutingPolicy.Delete(ctx, "tier:default"); err != nil {
+		t.Fatalf("failed to delete policy: %v", err)
+	}
+	policy, _ = repos.RoutingPolicy.Get(ctx, "tier:default")
+	if policy != "" {
+		t.Errorf("expected policy to be deleted, got %q", policy)
+	}
+}
+
+func TestFallbackChainRepository_Weight(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+
+	entries := []*models.FallbackChainEntry{
+		{Provider: "openai", Model: "gpt-4o-mini", Weight: 80, IsEnabled: true},
+		{Provider: "anthropic", Model: "claude-3-haiku-20240307", Weight: 20, IsEnabled: true},
+	}
+	if err := repos.FallbackChain.ReplaceAllByTier(ctx, nil, entries); err != nil {
+		t.Fatalf("failed to replace chain: %v", err)
+	}
+
+	result, err := repos.FallbackChain.GetByTier(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to get chain: %v", err)
+	}
+	if len(result) != 2 || result[0].Weight != 80 || result[1].Weight != 20 {
+		t.Errorf("weights not persisted: %+v", result)
+	}
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// SQLiteSpendCapRepository implements SpendCapRepository for SQLite/libsql.
+type SQLiteSpendCapRepository struct {
+	db *sql.DB
+}
+
+// NewSQLiteSpendCapRepository creates a new SQLite spend cap repository.
+func NewSQLiteSpendCapRepository(db *sql.DB) *SQLiteSpendCapRepository {
+	return &SQLiteSpendCapRepository{db: db}
+}
+
+const spendCapColumns = `id, user_id, api_key_id, period, max_cost_usd, max_tokens, max_pages, created_at, updated_at`
+
+// scanSpendCap scans a spend cap from a row.
+func scanSpendCap(row interface{ Scan(...any) error }) (*models.SpendCap, error) {
+	spendCap := &models.SpendCap{}
+	var period, createdAt, updatedAt string
+	var maxCost sql.NullFloat64
+	var maxTokens, maxPages sql.NullInt64
+	if err := row.Scan(
+		&spendCap.ID,
+		&spendCap.UserID,
+		&spendCap.APIKeyID,
+		&period,
+		&maxCost,
+		&maxTokens,
+		&maxPages,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	spendCap.Period = models.SpendCapPeriod(period)
+	if maxCost.Valid {
+		spendCap.MaxCostUSD = &maxCost.Float64
+	}
+	if maxTokens.Valid {
+		spendCap.MaxTokens = &maxTokens.Int64
+	}
+	if maxPages.Valid {
+		spendCap.MaxPages = &maxPages.Int64
+	}
+	spendCap.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	spendCap.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
+	return spendCap, nil
+}
+
+// GetByID returns a spend cap by ID, or nil if it doesn't exist.
+func (r *SQLiteSpendCapRepository) GetByID(ctx context.Context, id string) (*models.SpendCap, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+spendCapColumns+` FROM spend_caps WHERE id = ?`, id)
+	spendCap, err := scanSpendCap(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return spendCap, err
+}
+
+// GetByUserID returns all of a user's spend caps, including those on their API keys.
+func (r *SQLiteSpendCapRepository) GetByUserID(ctx context.Context, userID string) ([]*models.SpendCap, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+spendCapColumns+`
+		FROM spend_caps
+		WHERE user_id = ?
+		ORDER BY api_key_id, period
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var caps []*models.SpendCap
+	for rows.Next() {
+		spendCap, err := scanSpendCap(rows)
+		if err != nil {
+			return nil, err
+		}
+		caps = append(caps, spendCap)
+	}
+
+	return caps, rows.Err()
+}
+
+// Upsert creates a spend cap, or replaces the limits of the existing cap for the same
+// user, API key and period. The stored cap's ID and timestamps are set on spendCap.
+func (r *SQLiteSpendCapRepository) Upsert(ctx context.Context, spendCap *models.SpendCap) error {
+	now := time.Now().UTC()
+	if spendCap.ID == "" {
+		spendCap.ID = ulid.Make().String()
+	}
+
+	row := r.db.QueryRowContext(ctx, `
+		INSERT INTO spend_caps (id, user_id, api_key_id, period, max_cost_usd, max_tokens, max_pages, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, api_key_id, period) DO UPDATE SET
+			max_cost_usd = excluded.max_cost_usd,
+			max_tokens = excluded.max_tokens,
+			max_pages = excluded.max_pages,
+			updated_at = excluded.updated_at
+		RETURNING id, created_at
+	`, spendCap.ID, spendCap.UserID, spendCap.APIKeyID, string(spendCap.Period),
+		spendCap.MaxCostUSD, spendCap.MaxTokens, spendCap.MaxPages,
+		now.Format(time.RFC3339), now.Format(time.RFC3339))
+
+	var createdAt string
+	if err := row.Scan(&spendCap.ID, &createdAt); err != nil {
+		return err
+	}
+	spendCap.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	spendCap.UpdatedAt = now
+	return nil
+}
+
+// Delete removes a spend cap and its alert history.
+func (r *SQLiteSpendCapRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM spend_cap_alerts WHERE cap_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM spend_caps WHERE id = ?`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddUsage adds usage to a scope's counter for the period starting at periodStart.
+func (r *SQLiteSpendCapRepository) AddUsage(ctx context.Context, userID, scope string, period models.SpendCapPeriod, periodStart time.Time, usage models.SpendUsage) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO spend_counters (user_id, scope, period, period_start, cost_usd, tokens, pages, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(scope, period, period_start) DO UPDATE SET
+			cost_usd = cost_usd + excluded.cost_usd,
+			tokens = tokens + excluded.tokens,
+			pages = pages + excluded.pages,
+			updated_at = excluded.updated_at
+	`, userID, scope, string(period), periodStart.Format("2006-01-02"),
+		usage.CostUSD, usage.Tokens, usage.Pages, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+// GetUsage returns a scope's usage for the period starting at periodStart.
+// Returns zero usage if nothing has been recorded.
+func (r *SQLiteSpendCapRepository) GetUsage(ctx context.Context, scope string, period models.SpendCapPeriod, periodStart time.Time) (models.SpendUsage, error) {
+	var usage models.SpendUsage
+	err := r.db.QueryRowContext(ctx, `
+		SELECT cost_usd, tokens, pages
+		FROM spend_counters
+		WHERE scope = ? AND period = ? AND period_start = ?
+	`, scope, string(period), periodStart.Format("2006-01-02")).Scan(&usage.CostUSD, &usage.Tokens, &usage.Pages)
+	if err == sql.ErrNoRows {
+		return models.SpendUsage{}, nil
+	}
+	return usage, err
+}
+
+// MarkAlerted records that a cap's threshold was notified for a period.
+// Returns false if it had already been recorded.
+func (r *SQLiteSpendCapRepository) MarkAlerted(ctx context.Context, capID string, periodStart time.Time, threshold int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO spend_cap_alerts (cap_id, period_start, threshold, created_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(cap_id, period_start, threshold) DO NOTHING
+	`, capID, periodStart.Format("2006-01-02"), threshold, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
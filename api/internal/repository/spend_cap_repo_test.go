@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// ========================================
+// SpendCapRepository Tests
+// ========================================
+
+func TestSpendCapRepository_UpsertGetDelete(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+
+	maxCost := 25.0
+	spendCap := &models.SpendCap{
+		UserID:     "user-1",
+		Period:     models.SpendCapMonthly,
+		MaxCostUSD: &maxCost,
+	}
+	if err := repos.SpendCap.Upsert(ctx, spendCap); err != nil {
+		t.Fatalf("failed to create spend cap: %v", err)
+	}
+	if spendCap.ID == "" {
+		t.Fatal("expected ID to be set")
+	}
+
+	// Upserting the same user, key and period replaces the limits but keeps the ID
+	maxPages := int64(100)
+	replacement := &models.SpendCap{
+		UserID:   "user-1",
+		Period:   models.SpendCapMonthly,
+		MaxPages: &maxPages,
+	}
+	if err := repos.SpendCap.Upsert(ctx, replacement); err != nil {
+		t.Fatalf("failed to replace spend cap: %v", err)
+	}
+	if replacement.ID != spendCap.ID {
+		t.Errorf("ID = %s, want %s", replacement.ID, spendCap.ID)
+	}
+
+	got, err := repos.SpendCap.GetByID(ctx, spendCap.ID)
+	if err != nil {
+		t.Fatalf("failed to get spend cap: %v", err)
+	}
+	if got == nil || got.MaxCostUSD != nil || got.MaxPages == nil || *got.MaxPages != 100 {
+		t.Errorf("unexpected spend cap: %+v", got)
+	}
+
+	keyCap := &models.SpendCap{UserID: "user-1", APIKeyID: "key-1", Period: models.SpendCapDaily, MaxPages: &maxPages}
+	if err := repos.SpendCap.Upsert(ctx, keyCap); err != nil {
+		t.Fatalf("failed to create key spend cap: %v", err)
+	}
+	caps, err := repos.SpendCap.GetByUserID(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("failed to list spend caps: %v", err)
+	}
+	if len(caps) != 2 || caps[0].APIKeyID != "" || caps[1].APIKeyID != "key-1" {
+		t.Errorf("unexpected spend caps: %+v", caps)
+	}
+
+	if _, err := repos.SpendCap.MarkAlerted(ctx, spendCap.ID, time.Now(), 50); err != nil {
+		t.Fatalf("failed to mark alert: %v", err)
+	}
+	if err := repos.SpendCap.Delete(ctx, spendCap.ID); err != nil {
+		t.Fatalf("failed to delete spend cap: %v", err)
+	}
+	got, _ = repos.SpendCap.GetByID(ctx, spendCap.ID)
+	if got != nil {
+		t.Error("expected spend cap to be deleted")
+	}
+}
+
+func TestSpendCapRepository_Usage(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+	start := models.SpendCapMonthly.Start(time.Now())
+
+	usage, err := repos.SpendCap.GetUsage(ctx, "user:user-1", models.SpendCapMonthly, start)
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if usage != (models.SpendUsage{}) {
+		t.Errorf("expected zero usage, got %+v", usage)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repos.SpendCap.AddUsage(ctx, "user-1", "user:user-1", models.SpendCapMonthly, start, models.SpendUsage{CostUSD: 0.5, Tokens: 100, Pages: 1}); err != nil {
+			t.Fatalf("failed to add usage: %v", err)
+		}
+	}
+
+	usage, _ = repos.SpendCap.GetUsage(ctx, "user:user-1", models.SpendCapMonthly, start)
+	if usage.CostUSD != 1.0 || usage.Tokens != 200 || usage.Pages != 2 {
+		t.Errorf("usage = %+v, want cost 1.0, 200 tokens, 2 pages", usage)
+	}
+
+	// Counters are per period
+	daily, _ := repos.SpendCap.GetUsage(ctx, "user:user-1", models.SpendCapDaily, models.SpendCapDaily.Start(time.Now()))
+	if daily != (models.SpendUsage{}) {
+		t.Errorf("expected no daily usage, got %+v", daily)
+	}
+}
+
+func TestSpendCapRepository_MarkAlerted(t *testing.T) {
+	repos := setupTestRepos(t)
+	ctx := context.Background()
+	start := models.SpendCapDaily.Start(time.Now())
+
+	marked, err := repos.SpendCap.MarkAlerted(ctx, "cap-1", start, 80)
+	if err != nil {
+		t.Fatalf("failed to mark alert: %v", err)
+	}
+	if !marked {
+		t.Error("expected first alert to be marked")
+	}
+
+	marked, _ = repos.SpendCap.MarkAlerted(ctx, "cap-1", start, 80)
+	if marked {
+		t.Error("expected repeated alert not to be marked")
+	}
+
+	marked, _ = repos.SpendCap.MarkAlerted(ctx, "cap-1", start.AddDate(0, 0, 1), 80)
+	if !marked {
+		t.Error("expected alert in next period to be marked")
+	}
+}
@@ -23,7 +23,7 @@ func NewSQLiteUserFallbackChainRepository(db *sql.DB) *SQLiteUserFallbackChainRe
 // GetByUserID retrieves all fallback chain entries for a user.
 func (r *SQLiteUserFallbackChainRepository) GetByUserID(ctx context.Context, userID string) ([]*models.UserFallbackChainEntry, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at
+		SELECT id, user_id, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at
 		FROM user_fallback_chain
 		WHERE user_id = ?
 		ORDER BY position
@@ -39,7 +39,7 @@ func (r *SQLiteUserFallbackChainRepository) GetByUserID(ctx context.Context, use
 // GetEnabledByUserID retrieves enabled fallback chain entries for a user in position order.
 func (r *SQLiteUserFallbackChainRepository) GetEnabledByUserID(ctx context.Context, userID string) ([]*models.UserFallbackChainEntry, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at
+		SELECT id, user_id, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at
 		FROM user_fallback_chain
 		WHERE user_id = ? AND is_enabled = 1
 		ORDER BY position
@@ -85,9 +85,9 @@ func (r *SQLiteUserFallbackChainRepository) ReplaceAll(ctx context.Context, user
 		}
 
 		_, err := tx.ExecContext(ctx, `
-			INSERT INTO user_fallback_chain (id, user_id, position, provider, model, temperature, max_tokens, strict_mode, is_enabled, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		`, entry.ID, entry.UserID, entry.Position, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.IsEnabled, now, now)
+			INSERT INTO user_fallback_chain (id, user_id, position, provider, model, temperature, max_tokens, strict_mode, weight, is_enabled, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, entry.ID, entry.UserID, entry.Position, entry.Provider, entry.Model, entry.Temperature, entry.MaxTokens, strictModeInt, entry.Weight, entry.IsEnabled, now, now)
 		if err != nil {
 			return err
 		}
@@ -116,6 +116,7 @@ func (r *SQLiteUserFallbackChainRepository) scanEntries(rows *sql.Rows) ([]*mode
 			&temperature,
 			&maxTokens,
 			&strictMode,
+			&entry.Weight,
 			&entry.IsEnabled,
 			&createdAt,
 			&updatedAt,
@@ -133,8 +133,9 @@ func (s *AdminService) DeleteServiceKey(ctx context.Context, provider string) er
 
 // FallbackChainInput represents input for replacing the fallback chain.
 type FallbackChainInput struct {
-	Tier    *string                   `json:"tier,omitempty"` // nil for default chain
-	Entries []FallbackChainEntryInput `json:"entries"`
+	Tier          *string                   `json:"tier,omitempty"` // nil for default chain
+	Entries       []FallbackChainEntryInput `json:"entries"`
+	RoutingPolicy models.RoutingPolicy      `json:"routing_policy,omitempty"` // "" leaves the policy unchanged
 }
 
 // FallbackChainEntryInput represents a single entry in the fallback chain input.
@@ -143,6 +144,7 @@ type FallbackChainEntryInput struct {
 	Model       string   `json:"model"`
 	Temperature *float64 `json:"temperature,omitempty"`
 	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Weight      int      `json:"weight,omitempty"`
 	IsEnabled   bool     `json:"is_enabled"`
 }
 
@@ -166,6 +168,22 @@ func (s *AdminService) GetFallbackChainByTier(ctx context.Context, tier *string)
 	return s.repos.FallbackChain.GetByTier(ctx, normalizedTier)
 }
 
+// GetRoutingPolicies returns the configured routing policy of every system chain, keyed by
+// tier name ("default" for the default chain). Chains without an entry use the ordered policy.
+func (s *AdminService) GetRoutingPolicies(ctx context.Context) (map[string]models.RoutingPolicy, error) {
+	all, err := s.repos.RoutingPolicy.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[string]models.RoutingPolicy)
+	for scope, policy := range all {
+		if tier, ok := strings.CutPrefix(scope, "tier:"); ok {
+			policies[tier] = policy
+		}
+	}
+	return policies, nil
+}
+
 // GetAllTiers returns a list of all tiers with custom chains configured.
 func (s *AdminService) GetAllTiers(ctx context.Context) ([]string, error) {
 	return s.repos.FallbackChain.GetAllTiers(ctx)
@@ -178,6 +196,10 @@ func (s *AdminService) SetFallbackChain(ctx context.Context, input FallbackChain
 	// Normalize tier name for consistent storage
 	normalizedTier := normalizeTier(input.Tier)
 
+	if err := validateRoutingPolicy(input.RoutingPolicy); err != nil {
+		return nil, err
+	}
+
 	entries := make([]*models.FallbackChainEntry, 0, len(input.Entries))
 
 	for i, e := range input.Entries {
@@ -187,6 +209,9 @@ func (s *AdminService) SetFallbackChain(ctx context.Context, input FallbackChain
 		if e.Model == "" {
 			return nil, fmt.Errorf("model at position %d cannot be empty", i+1)
 		}
+		if err := validateRoutingWeight(i+1, e.Weight); err != nil {
+			return nil, err
+		}
 
 		entries = append(entries, &models.FallbackChainEntry{
 			Tier:        normalizedTier,
@@ -195,6 +220,7 @@ func (s *AdminService) SetFallbackChain(ctx context.Context, input FallbackChain
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 		})
 	}
@@ -202,12 +228,17 @@ func (s *AdminService) SetFallbackChain(ctx context.Context, input FallbackChain
 	if err := s.repos.FallbackChain.ReplaceAllByTier(ctx, normalizedTier, entries); err != nil {
 		return nil, fmt.Errorf("failed to update fallback chain: %w", err)
 	}
+	if input.RoutingPolicy != "" {
+		if err := setRoutingPolicy(ctx, s.repos.RoutingPolicy, models.TierRoutingScope(normalizedTier), input.RoutingPolicy); err != nil {
+			return nil, fmt.Errorf("failed to update routing policy: %w", err)
+		}
+	}
 
 	tierName := "default"
 	if normalizedTier != nil {
 		tierName = *normalizedTier
 	}
-	s.logger.Info("fallback chain updated", "tier", tierName, "entries", len(entries))
+	s.logger.Info("fallback chain updated", "tier", tierName, "entries", len(entries), "routing_policy", input.RoutingPolicy)
 
 	return s.repos.FallbackChain.GetByTier(ctx, normalizedTier)
 }
@@ -220,6 +251,9 @@ func (s *AdminService) DeleteFallbackChainByTier(ctx context.Context, tier strin
 	if err := s.repos.FallbackChain.DeleteByTier(ctx, normalizedTier); err != nil {
 		return fmt.Errorf("failed to delete fallback chain: %w", err)
 	}
+	if err := s.repos.RoutingPolicy.Delete(ctx, models.TierRoutingScope(&normalizedTier)); err != nil {
+		return fmt.Errorf("failed to delete routing policy: %w", err)
+	}
 
 	s.logger.Info("fallback chain deleted", "tier", normalizedTier)
 	return nil
@@ -292,6 +292,7 @@ func setupAdminService(t *testing.T) (*AdminService, *adminServiceKeyRepository,
 	repos := &repository.Repositories{
 		ServiceKey:    keyRepo,
 		FallbackChain: chainRepo,
+		RoutingPolicy: newMockRoutingPolicyRepository(),
 	}
 
 	// Create a test encryptor with a fixed key
@@ -316,6 +317,7 @@ func setupAdminServiceNoEncryption(t *testing.T) (*AdminService, *adminServiceKe
 	repos := &repository.Repositories{
 		ServiceKey:    keyRepo,
 		FallbackChain: chainRepo,
+		RoutingPolicy: newMockRoutingPolicyRepository(),
 	}
 
 	logger := slog.Default()
@@ -763,6 +765,82 @@ func TestAdminService_SetFallbackChain_OllamaValid(t *testing.T) {
 	}
 }
 
+func TestAdminService_SetFallbackChain_RoutingPolicy(t *testing.T) {
+	svc, _, _, _ := setupAdminService(t)
+	ctx := context.Background()
+
+	proTier := "pro"
+	entries, err := svc.SetFallbackChain(ctx, FallbackChainInput{
+		Tier: &proTier,
+		Entries: []FallbackChainEntryInput{
+			{Provider: "openai", Model: "gpt-4o-mini", Weight: 90, IsEnabled: true},
+			{Provider: "anthropic", Model: "claude-3-haiku-20240307", Weight: 10, IsEnabled: true},
+		},
+		RoutingPolicy: models.RoutingWeighted,
+	})
+	if err != nil {
+		t.Fatalf("failed to set fallback chain: %v", err)
+	}
+	if entries[0].Weight != 90 || entries[1].Weight != 10 {
+		t.Errorf("weights = %d, %d; want 90, 10", entries[0].Weight, entries[1].Weight)
+	}
+
+	policies, err := svc.GetRoutingPolicies(ctx)
+	if err != nil {
+		t.Fatalf("failed to get routing policies: %v", err)
+	}
+	if policies["pro"] != models.RoutingWeighted {
+		t.Errorf("policies = %v, want pro: weighted", policies)
+	}
+
+	// Omitting the policy leaves it unchanged
+	if _, err := svc.SetFallbackChain(ctx, FallbackChainInput{
+		Tier:    &proTier,
+		Entries: []FallbackChainEntryInput{{Provider: "openai", Model: "gpt-4o", IsEnabled: true}},
+	}); err != nil {
+		t.Fatalf("failed to set fallback chain: %v", err)
+	}
+	if policies, _ = svc.GetRoutingPolicies(ctx); policies["pro"] != models.RoutingWeighted {
+		t.Errorf("policy should be unchanged, got %v", policies)
+	}
+
+	// Deleting the tier's chain removes its policy
+	if err := svc.DeleteFallbackChainByTier(ctx, "pro"); err != nil {
+		t.Fatalf("failed to delete chain by tier: %v", err)
+	}
+	if policies, _ = svc.GetRoutingPolicies(ctx); len(policies) != 0 {
+		t.Errorf("expected no policies after delete, got %v", policies)
+	}
+}
+
+func TestAdminService_SetFallbackChain_InvalidRouting(t *testing.T) {
+	svc, _, _, _ := setupAdminService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		input FallbackChainInput
+	}{
+		{"unknown policy", FallbackChainInput{
+			Entries:       []FallbackChainEntryInput{{Provider: "openai", Model: "gpt-4o", IsEnabled: true}},
+			RoutingPolicy: "round_robin",
+		}},
+		{"weight above 100", FallbackChainInput{
+			Entries: []FallbackChainEntryInput{{Provider: "openai", Model: "gpt-4o", Weight: 101, IsEnabled: true}},
+		}},
+		{"negative weight", FallbackChainInput{
+			Entries: []FallbackChainEntryInput{{Provider: "openai", Model: "gpt-4o", Weight: -1, IsEnabled: true}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SetFallbackChain(ctx, tt.input); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
 // ========================================
 // DeleteFallbackChainByTier Tests
 // ========================================
@@ -51,16 +51,6 @@ func (s *AnalyzerService) getStrictMode(ctx context.Context, provider, model str
 	return strictMode
 }
 
-// supportsResponseFormat checks if a model supports the response_format parameter.
-// Used to determine if JSON mode can be enabled for better structured output.
-func (s *AnalyzerService) supportsResponseFormat(ctx context.Context, provider, model string) bool {
-	if s.resolver != nil {
-		return s.resolver.SupportsResponseFormat(ctx, provider, model)
-	}
-	// Assume support for OpenAI-compatible providers
-	return provider == "openai" || provider == "openrouter"
-}
-
 // NewAnalyzerService creates a new analyzer service (legacy constructor).
 func NewAnalyzerService(cfg *config.Config, repos *repository.Repositories, logger *slog.Logger) *AnalyzerService {
 	return NewAnalyzerServiceWithBilling(cfg, repos, nil, nil, logger)
@@ -124,6 +114,7 @@ type AnalyzeInput struct {
 	Depth     int    `json:"depth"`      // 0 = single page, 1 = crawl one level
 	FetchMode string `json:"fetch_mode"` // auto, static, dynamic
 	JobID     string `json:"-"`          // Job ID for tracking (not serialized)
+	APIKeyID  string `json:"-"`          // API key used, for per-key spend caps (not serialized)
 }
 
 // AnalyzeTokenUsage represents token consumption and cost info for an analysis.
@@ -213,6 +204,7 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 
 	// Pre-flight cost estimate (triggers pricing cache refresh if needed) and balance check
 	// Use first config in chain for estimate
+	var spendReservation *SpendReservation
 	if s.billing != nil {
 		estimatedCost := s.billing.EstimateCost(1, firstCfg.Model, firstCfg.Provider)
 		s.logger.Debug("pre-flight cost estimate",
@@ -229,6 +221,12 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 				return nil, err
 			}
 		}
+		reservation, err := s.billing.ReserveSpend(ctx, userID, input.APIKeyID, estimatedCost)
+		if err != nil {
+			return nil, err
+		}
+		spendReservation = reservation
+		defer s.billing.ReleaseSpend(context.WithoutCancel(ctx), reservation)
 	}
 
 	// Fetch main page content
@@ -237,7 +235,7 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 	mainContent, links, fetchMode, err := s.fetchContent(ctx, targetURL, input.FetchMode, userID, tier, contentDynamicAllowed, input.JobID)
 	if err != nil {
 		s.logger.Error("failed to fetch page content", "request_id", requestID, "user_id", userID, "url", targetURL, "error", err)
-		s.recordAnalyzeUsage(ctx, userID, tier, targetURL, firstCfg, llmChain.IsBYOK(), 0, 0,
+		s.recordAnalyzeUsage(ctx, userID, input.APIKeyID, spendReservation, tier, targetURL, firstCfg, llmChain.IsBYOK(), llmChain.Routing(), 0, 0,
 			int(time.Since(fetchStart).Milliseconds()), 0, int(time.Since(startTime).Milliseconds()),
 			"failed", err.Error(), requestID)
 		return nil, fmt.Errorf("failed to fetch page content: %w", err)
@@ -308,6 +306,12 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 	// Generate analysis prompt and call LLM
 	// First try with raw content (noop cleaner), retry with fallback cleaner on context length errors
 	// Iterate through fallback chain if a model fails
+	// Now the content is fetched, let cheapest_capable routing account for its size (~3 chars per token)
+	inputChars := len(mainContent)
+	for _, detail := range detailContents {
+		inputChars += len(detail)
+	}
+	llmChain.RouteForInput(inputChars / 3)
 	llmStart := time.Now()
 	var result *analyzeResult
 	var lastErr error
@@ -326,6 +330,7 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 			"of", total,
 		)
 
+		llmChain.BeginCall()
 		result, lastErr = s.analyzeWithLLM(ctx, targetURL, mainContent, detailContents, detailURLs, links, cfg)
 
 		// If context length error, retry with cleaned content
@@ -376,6 +381,7 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 
 		// Success!
 		if lastErr == nil {
+			llmChain.RecordSuccess(0) // Content is fetched before the loop, so time since Next is LLM time
 			s.logger.Info("analysis succeeded",
 				"request_id", requestID,
 				"user_id", userID,
@@ -387,6 +393,8 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 			break
 		}
 
+		llmChain.RecordError(llm.WrapError(lastErr, cfg.Provider, cfg.Model, llmChain.IsBYOK()))
+
 		// Log failure and try next model in the chain
 		s.logger.Warn("analysis failed, trying next model in fallback chain",
 			"request_id", requestID,
@@ -411,14 +419,14 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 			"chain_length", llmChain.Len(),
 			"error", lastErr,
 		)
-		s.recordAnalyzeUsage(ctx, userID, tier, targetURL, llmConfig, llmChain.IsBYOK(), 0, 0,
+		s.recordAnalyzeUsage(ctx, userID, input.APIKeyID, spendReservation, tier, targetURL, llmConfig, llmChain.IsBYOK(), llmChain.Routing(), 0, 0,
 			int(fetchDuration.Milliseconds()), int(llmDuration.Milliseconds()), int(time.Since(startTime).Milliseconds()),
 			"failed", lastErr.Error(), requestID)
 		return nil, fmt.Errorf("LLM analysis failed: %w", lastErr)
 	}
 
 	// Record successful usage
-	s.recordAnalyzeUsage(ctx, userID, tier, targetURL, llmConfig, llmChain.IsBYOK(),
+	s.recordAnalyzeUsage(ctx, userID, input.APIKeyID, spendReservation, tier, targetURL, llmConfig, llmChain.IsBYOK(), llmChain.Routing(),
 		result.InputTokens, result.OutputTokens,
 		int(fetchDuration.Milliseconds()), int(llmDuration.Milliseconds()), int(time.Since(startTime).Milliseconds()),
 		"success", "", requestID)
@@ -483,9 +491,12 @@ func (s *AnalyzerService) Analyze(ctx context.Context, userID string, input Anal
 // recordAnalyzeUsage records usage for an analyze operation.
 func (s *AnalyzerService) recordAnalyzeUsage(
 	ctx context.Context,
-	userID, tier, targetURL string,
+	userID, apiKeyID string,
+	spendReservation *SpendReservation,
+	tier, targetURL string,
 	llmConfig *LLMConfigInput,
 	isBYOK bool,
+	routing RoutingDecision,
 	inputTokens, outputTokens int,
 	fetchDurationMs, extractDurationMs, totalDurationMs int,
 	status, errorMessage string,
@@ -502,6 +513,7 @@ func (s *AnalyzerService) recordAnalyzeUsage(
 
 	usageRecord := &UsageRecord{
 		UserID:            userID,
+		APIKeyID:          apiKeyID,
 		JobID:             "", // Analyze operations don't have job records
 		JobType:           models.JobTypeAnalyze,
 		Status:            status,
@@ -515,12 +527,15 @@ func (s *AnalyzerService) recordAnalyzeUsage(
 		LLMProvider:       llmConfig.Provider,
 		LLMModel:          llmConfig.Model,
 		BYOKProvider:      byokProvider,
+		RoutingPolicy:     string(routing.Policy),
+		RoutingReason:     routing.Reason,
 		PagesAttempted:    1,
 		PagesSuccessful:   1,
 		FetchDurationMs:   fetchDurationMs,
 		ExtractDurationMs: extractDurationMs,
 		TotalDurationMs:   totalDurationMs,
 		RequestID:         requestID,
+		SpendReservation:  spendReservation,
 	}
 
 	if status == "failed" {
@@ -955,9 +970,9 @@ func (s *AnalyzerService) analyzeWithLLM(ctx context.Context, mainURL string, ma
 	// Call LLM API using shared client
 	llmClient := NewLLMClient(s.logger, s.resolver.GetRegistry())
 	opts := DefaultLLMCallOptions()
-	// Enable JSON mode if the model supports response_format parameter
-	// This hints the model to output JSON, improving structured output compliance
-	opts.JSONMode = s.supportsResponseFormat(ctx, llmConfig.Provider, llmConfig.Model)
+	// Constrain the response to the analysis shape; the client uses the provider's
+	// structured output mechanism when the model supports one
+	opts.JSONSchema = analysisResponseSchema
 	llmResult, err := llmClient.Call(ctx, llmConfig, prompt, opts)
 	if err != nil {
 		return nil, err
@@ -978,6 +993,75 @@ func (s *AnalyzerService) analyzeWithLLM(ctx context.Context, mainURL string, ma
 	}, nil
 }
 
+// analysisResponseSchema is the JSON Schema of the analysis response described in
+// buildAnalysisPrompt. Schema fields nest through $defs, as arrays and objects hold
+// further fields; items and properties are nullable for scalar fields.
+var analysisResponseSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"site_summary": map[string]any{"type": "string"},
+		"page_type":    map[string]any{"type": "string"},
+		"detected_elements": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":        map[string]any{"type": "string"},
+					"type":        map[string]any{"type": "string"},
+					"count":       map[string]any{"type": "integer"},
+					"description": map[string]any{"type": "string"},
+				},
+				"required": []any{"name", "type", "count"},
+			},
+		},
+		"suggested_schema": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":        map[string]any{"type": "string"},
+				"description": map[string]any{"type": "string"},
+				"fields": map[string]any{
+					"type":  "array",
+					"items": map[string]any{"$ref": "#/$defs/field"},
+				},
+			},
+			"required": []any{"name", "fields"},
+		},
+		"follow_patterns": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"pattern":     map[string]any{"type": "string"},
+					"description": map[string]any{"type": "string"},
+					"sample_urls": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				},
+				"required": []any{"pattern"},
+			},
+		},
+	},
+	"required": []any{"site_summary", "page_type", "detected_elements", "suggested_schema", "follow_patterns"},
+	"$defs": map[string]any{
+		"field": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"name":        map[string]any{"type": "string"},
+				"type":        map[string]any{"type": "string", "enum": []any{"string", "integer", "number", "boolean", "array", "object"}},
+				"description": map[string]any{"type": "string"},
+				"required":    map[string]any{"type": "boolean"},
+				"items": map[string]any{"anyOf": []any{
+					map[string]any{"$ref": "#/$defs/field"},
+					map[string]any{"type": "null"},
+				}},
+				"properties": map[string]any{
+					"type":  []any{"array", "null"},
+					"items": map[string]any{"$ref": "#/$defs/field"},
+				},
+			},
+			"required": []any{"type", "items", "properties"},
+		},
+	},
+}
+
 // truncateContent truncates HTML content to a maximum length while trying to preserve structure.
 func (s *AnalyzerService) truncateContent(content string, maxLen int) string {
 	if len(content) <= maxLen {
@@ -1165,8 +1249,9 @@ func (s *AnalyzerService) parseAnalysisResponse(response string) (*AnalyzeOutput
 			// Already a string (legacy format)
 			schemaString = str
 		} else {
-			// JSON object - serialize to pretty-printed JSON string
-			schemaBytes, err := json.MarshalIndent(parsed.SuggestedSchema, "", "  ")
+			// JSON object - serialize to pretty-printed JSON string, without the nulls
+			// structured output fills unused field keys with
+			schemaBytes, err := json.MarshalIndent(dropNullValues(parsed.SuggestedSchema), "", "  ")
 			if err == nil {
 				schemaString = string(schemaBytes)
 			}
@@ -1200,6 +1285,25 @@ func (s *AnalyzerService) parseAnalysisResponse(response string) (*AnalyzeOutput
 	return output, nil
 }
 
+// dropNullValues removes null object values from decoded JSON, recursively.
+func dropNullValues(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, item := range val {
+			if item == nil {
+				delete(val, key)
+				continue
+			}
+			val[key] = dropNullValues(item)
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = dropNullValues(item)
+		}
+	}
+	return v
+}
+
 // parsePageType converts a string to PageType.
 func (s *AnalyzerService) parsePageType(pt string) models.PageType {
 	switch strings.ToLower(pt) {
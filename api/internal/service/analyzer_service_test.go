@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/jmylchreest/refyne-api/internal/config"
@@ -778,6 +779,44 @@ I hope this helps!`
 	}
 }
 
+func TestAnalyzerService_ParseAnalysisResponse_StructuredOutputNulls(t *testing.T) {
+	cfg := &config.Config{EncryptionKey: []byte("12345678901234567890123456789012")}
+	svc := NewAnalyzerService(cfg, &repository.Repositories{}, slog.Default())
+
+	// Strict structured output fills unused field keys with null
+	response := `{
+		"site_summary": "Job board",
+		"page_type": "listing",
+		"detected_elements": [],
+		"suggested_schema": {
+			"name": "Jobs",
+			"description": null,
+			"fields": [{
+				"name": "jobs",
+				"type": "array",
+				"description": null,
+				"required": null,
+				"items": {"name": null, "type": "object", "description": null, "required": null, "items": null, "properties": [
+					{"name": "title", "type": "string", "description": null, "required": true, "items": null, "properties": null}
+				]},
+				"properties": null
+			}]
+		},
+		"follow_patterns": []
+	}`
+
+	output, err := svc.parseAnalysisResponse(response)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(output.SuggestedSchema, "null") {
+		t.Errorf("SuggestedSchema should not contain nulls:\n%s", output.SuggestedSchema)
+	}
+	if !strings.Contains(output.SuggestedSchema, `"title"`) {
+		t.Errorf("SuggestedSchema lost nested fields:\n%s", output.SuggestedSchema)
+	}
+}
+
 func TestAnalyzerService_ParseAnalysisResponse_InvalidJSON(t *testing.T) {
 	cfg := &config.Config{EncryptionKey: []byte("12345678901234567890123456789012")}
 	svc := NewAnalyzerService(cfg, &repository.Repositories{}, slog.Default())
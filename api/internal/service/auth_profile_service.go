@@ -0,0 +1,584 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/crypto"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+)
+
+var (
+	// ErrAuthProfileNotFound is returned when an auth profile doesn't exist or belongs to someone else.
+	ErrAuthProfileNotFound = errors.New("auth profile not found")
+
+	// ErrInvalidAuthProfile is returned when an auth profile's cookies, login URL or script are invalid.
+	ErrInvalidAuthProfile = errors.New("invalid auth profile")
+
+	// ErrAuthSessionExpired is returned when a page still redirects to the login page
+	// after the auth profile's cookies were refreshed.
+	ErrAuthSessionExpired = errors.New("auth profile session expired - upload fresh cookies or check the login script")
+)
+
+// AuthProfileService manages users' stored logins and signs fetches in with them.
+// Cookies and login scripts are stored encrypted. Login profiles are signed in again
+// in the browser, by replaying the script on the login page, when a fetch that uses
+// them is redirected to the login page.
+type AuthProfileService struct {
+	repos      *repository.Repositories
+	encryptor  *crypto.Encryptor
+	captchaSvc *CaptchaService
+	logger     *slog.Logger
+	nowFunc    func() time.Time
+}
+
+// NewAuthProfileService creates a new auth profile service.
+func NewAuthProfileService(repos *repository.Repositories, encryptor *crypto.Encryptor, logger *slog.Logger) *AuthProfileService {
+	return &AuthProfileService{
+		repos:     repos,
+		encryptor: encryptor,
+		logger:    logger,
+		nowFunc:   time.Now,
+	}
+}
+
+// SetCaptchaService sets the captcha service used to replay login scripts.
+func (s *AuthProfileService) SetCaptchaService(captchaSvc *CaptchaService) {
+	s.captchaSvc = captchaSvc
+}
+
+// AuthProfileInput represents input for creating or updating an auth profile.
+type AuthProfileInput struct {
+	Name     string
+	Type     models.AuthProfileType
+	LoginURL string                  // Login page; required for login profiles
+	Cookies  []captcha.Cookie        // Cookie jar; nil on update keeps the current cookies
+	Script   *models.AuthLoginScript // Login script (login profiles); nil on update keeps the current script
+}
+
+// List returns a user's auth profiles.
+func (s *AuthProfileService) List(ctx context.Context, userID string) ([]*models.AuthProfile, error) {
+	return s.repos.AuthProfile.GetByUserID(ctx, userID)
+}
+
+// Get returns one of a user's auth profiles.
+func (s *AuthProfileService) Get(ctx context.Context, userID, id string) (*models.AuthProfile, error) {
+	profile, err := s.repos.AuthProfile.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get auth profile: %w", err)
+	}
+	if profile == nil || profile.UserID != userID {
+		return nil, ErrAuthProfileNotFound
+	}
+	return profile, nil
+}
+
+// Create adds an auth profile for a user.
+func (s *AuthProfileService) Create(ctx context.Context, userID string, input AuthProfileInput) (*models.AuthProfile, error) {
+	profile := &models.AuthProfile{UserID: userID}
+	if err := s.apply(profile, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.AuthProfile.Create(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to save auth profile: %w", err)
+	}
+
+	s.logger.Info("auth profile created",
+		"auth_profile_id", profile.ID,
+		"user_id", userID,
+		"type", profile.Type,
+		"cookies", len(input.Cookies),
+	)
+	return profile, nil
+}
+
+// Update changes one of a user's auth profiles.
+func (s *AuthProfileService) Update(ctx context.Context, userID, id string, input AuthProfileInput) (*models.AuthProfile, error) {
+	profile, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(profile, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.AuthProfile.Update(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to save auth profile: %w", err)
+	}
+
+	s.logger.Info("auth profile updated", "auth_profile_id", profile.ID, "user_id", userID, "type", profile.Type)
+	return profile, nil
+}
+
+// Delete removes one of a user's auth profiles.
+func (s *AuthProfileService) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.repos.AuthProfile.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("auth profile deleted", "auth_profile_id", id, "user_id", userID)
+	return nil
+}
+
+// Login signs in with one of a user's login profiles now, replacing its cookies.
+func (s *AuthProfileService) Login(ctx context.Context, userID, tier, id string) (*models.AuthProfile, error) {
+	profile, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if profile.Type != models.AuthProfileLogin {
+		return nil, fmt.Errorf("%w: only login profiles can sign in", ErrInvalidAuthProfile)
+	}
+	if _, _, err := s.login(ctx, userID, tier, profile); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// apply validates the input and sets it on profile, encrypting cookies and the login script.
+func (s *AuthProfileService) apply(profile *models.AuthProfile, input AuthProfileInput) error {
+	if strings.TrimSpace(input.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAuthProfile)
+	}
+	switch input.Type {
+	case models.AuthProfileCookies, models.AuthProfileLogin:
+	default:
+		return fmt.Errorf("%w: type must be cookies or login", ErrInvalidAuthProfile)
+	}
+	profile.Name = strings.TrimSpace(input.Name)
+	profile.Type = input.Type
+
+	profile.LoginURL = ""
+	if input.LoginURL != "" {
+		u, err := url.Parse(strings.TrimSpace(input.LoginURL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: login_url must be an http or https URL", ErrInvalidAuthProfile)
+		}
+		profile.LoginURL = u.String()
+	}
+
+	if input.Cookies != nil {
+		if len(input.Cookies) > constants.MaxAuthProfileCookies {
+			return fmt.Errorf("%w: at most %d cookies are allowed", ErrInvalidAuthProfile, constants.MaxAuthProfileCookies)
+		}
+		for i, c := range input.Cookies {
+			if c.Name == "" {
+				return fmt.Errorf("%w: cookie %d has no name", ErrInvalidAuthProfile, i+1)
+			}
+			// Cookies without a domain are sent to the login page's host, so one is needed
+			if c.Domain == "" && profile.LoginURL == "" {
+				return fmt.Errorf("%w: cookie %d has no domain - set one or the profile's login_url", ErrInvalidAuthProfile, i+1)
+			}
+		}
+		encrypted, err := s.encrypt(input.Cookies)
+		if err != nil {
+			return err
+		}
+		profile.CookiesEncrypted = encrypted
+		at := s.nowFunc()
+		profile.CookiesUpdatedAt = &at
+	}
+
+	if input.Script != nil {
+		if err := validateLoginScript(input.Script); err != nil {
+			return err
+		}
+		encrypted, err := s.encrypt(input.Script)
+		if err != nil {
+			return err
+		}
+		profile.ScriptEncrypted = encrypted
+	}
+
+	switch profile.Type {
+	case models.AuthProfileCookies:
+		if profile.CookiesEncrypted == "" {
+			return fmt.Errorf("%w: cookies are required", ErrInvalidAuthProfile)
+		}
+		// A cookie profile has no script to replay
+		profile.ScriptEncrypted = ""
+	case models.AuthProfileLogin:
+		if profile.LoginURL == "" {
+			return fmt.Errorf("%w: login_url is required for login profiles", ErrInvalidAuthProfile)
+		}
+		if profile.ScriptEncrypted == "" {
+			return fmt.Errorf("%w: script is required for login profiles", ErrInvalidAuthProfile)
+		}
+	}
+	return nil
+}
+
+// validateLoginScript checks that a login script fills in at least one field, submits
+// the form and knows how to tell that the sign-in worked.
+func validateLoginScript(script *models.AuthLoginScript) error {
+	if len(script.Fields) == 0 {
+		return fmt.Errorf("%w: script needs at least one field", ErrInvalidAuthProfile)
+	}
+	if len(script.Fields) > constants.MaxAuthLoginFields {
+		return fmt.Errorf("%w: script can fill in at most %d fields", ErrInvalidAuthProfile, constants.MaxAuthLoginFields)
+	}
+	for i, f := range script.Fields {
+		if f.Selector == "" || f.Value == "" {
+			return fmt.Errorf("%w: field %d needs a selector and a value", ErrInvalidAuthProfile, i+1)
+		}
+	}
+	if script.SubmitSelector == "" {
+		return fmt.Errorf("%w: script needs a submit_selector", ErrInvalidAuthProfile)
+	}
+	if script.SuccessSelector == "" {
+		return fmt.Errorf("%w: script needs a success_selector", ErrInvalidAuthProfile)
+	}
+	return nil
+}
+
+// loginActions returns the browser actions that replay a login script: type each
+// field, click submit, then wait for the element that only appears once signed in.
+func loginActions(script *models.AuthLoginScript) []captcha.Action {
+	actions := make([]captcha.Action, 0, len(script.Fields)+2)
+	for _, f := range script.Fields {
+		actions = append(actions, captcha.Action{Type: "type", Selector: f.Selector, Text: f.Value})
+	}
+	return append(actions,
+		captcha.Action{Type: "click", Selector: script.SubmitSelector},
+		captcha.Action{Type: "wait", Selector: script.SuccessSelector, Timeout: int(constants.AuthLoginWaitTimeout.Milliseconds())},
+	)
+}
+
+// login replays a profile's login script in the browser and saves the cookies it ends up with.
+func (s *AuthProfileService) login(ctx context.Context, userID, tier string, profile *models.AuthProfile) ([]captcha.Cookie, time.Time, error) {
+	if s.captchaSvc == nil {
+		return nil, time.Time{}, ErrDynamicFetchNotConfigured
+	}
+	var script models.AuthLoginScript
+	if err := s.decrypt(profile.ScriptEncrypted, &script); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	startTime := s.nowFunc()
+	out, err := s.captchaSvc.FetchDynamicContent(ctx, userID, tier, CaptchaSolveInput{
+		URL:        profile.LoginURL,
+		MaxTimeout: int(constants.AuthLoginTimeout.Milliseconds()),
+		Actions:    loginActions(&script),
+	})
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("sign-in failed: %w", err)
+	}
+	if out.Status != "ok" || out.Solution == nil {
+		return nil, time.Time{}, fmt.Errorf("%w: sign-in failed: %s", ErrAuthSessionExpired, out.Message)
+	}
+
+	cookies := out.Solution.Cookies
+	encrypted, err := s.encrypt(cookies)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	at := s.nowFunc()
+	if err := s.repos.AuthProfile.UpdateCookies(ctx, profile.ID, encrypted, at); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to save auth profile cookies: %w", err)
+	}
+
+	s.logger.Info("auth profile signed in",
+		"auth_profile_id", profile.ID,
+		"user_id", userID,
+		"cookies", len(cookies),
+		"duration_ms", at.Sub(startTime).Milliseconds(),
+	)
+	return cookies, at, nil
+}
+
+// encrypt marshals v to JSON and encrypts it. Without an encryptor the JSON is stored as is.
+func (s *AuthProfileService) encrypt(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode auth profile: %w", err)
+	}
+	if s.encryptor == nil {
+		return string(data), nil
+	}
+	encrypted, err := s.encryptor.Encrypt(string(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt auth profile: %w", err)
+	}
+	return encrypted, nil
+}
+
+// decrypt decrypts an encrypted JSON value into v.
+func (s *AuthProfileService) decrypt(encrypted string, v any) error {
+	if encrypted == "" {
+		return nil
+	}
+	data := encrypted
+	if s.encryptor != nil {
+		var err error
+		if data, err = s.encryptor.Decrypt(encrypted); err != nil {
+			return fmt.Errorf("failed to decrypt auth profile: %w", err)
+		}
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to decode auth profile: %w", err)
+	}
+	return nil
+}
+
+// session loads one of a user's auth profiles for their fetches, or returns nil when
+// no profile was requested. Signing in again needs browser rendering, so it is only
+// allowed when dynamicAllowed is set.
+func (s *AuthProfileService) session(ctx context.Context, userID, tier, id string, dynamicAllowed bool) (*authSession, error) {
+	if id == "" {
+		return nil, nil
+	}
+	if s == nil {
+		return nil, ErrAuthProfileNotFound
+	}
+	profile, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &authSession{
+		svc:            s,
+		profileID:      profile.ID,
+		userID:         userID,
+		tier:           tier,
+		dynamicAllowed: dynamicAllowed,
+	}
+	if profile.LoginURL != "" {
+		a.loginURL, _ = url.Parse(profile.LoginURL)
+	}
+	if err := s.decrypt(profile.CookiesEncrypted, &a.cookies); err != nil {
+		return nil, err
+	}
+	if profile.CookiesUpdatedAt != nil {
+		a.version = *profile.CookiesUpdatedAt
+	}
+	return a, nil
+}
+
+// authSession signs one extraction or crawl's fetches in with an auth profile. It is
+// shared by every page of a crawl, so when several pages land on the login page at
+// once, only the first signs in again and the rest use its cookies.
+type authSession struct {
+	svc            *AuthProfileService
+	profileID      string
+	userID         string
+	tier           string
+	loginURL       *url.URL
+	dynamicAllowed bool
+
+	mu      sync.Mutex
+	cookies []captcha.Cookie
+	version time.Time // When the cookies were last replaced
+}
+
+// current returns the session's cookies and when they were last replaced.
+func (a *authSession) current() ([]captcha.Cookie, time.Time) {
+	if a == nil {
+		return nil, time.Time{}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cookies, a.version
+}
+
+// atLogin reports whether a fetch ended up on the profile's login page.
+// Query strings are ignored, as sites add the page to return to after signing in.
+func (a *authSession) atLogin(pageURL string) bool {
+	if a.loginURL == nil || pageURL == "" {
+		return false
+	}
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Host, a.loginURL.Host) &&
+		strings.TrimSuffix(u.Path, "/") == strings.TrimSuffix(a.loginURL.Path, "/")
+}
+
+// cookiesFor returns the cookies that apply to pageURL by domain, path and scheme,
+// so a crawl that leaves the profile's site doesn't send its session elsewhere.
+// Cookies without a domain belong to the login page's host.
+func (a *authSession) cookiesFor(pageURL string, cookies []captcha.Cookie) []captcha.Cookie {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	var result []captcha.Cookie
+	for _, c := range cookies {
+		if c.Domain == "" {
+			if a.loginURL == nil {
+				continue
+			}
+			c.Domain = a.loginURL.Hostname()
+		}
+		domain := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			continue
+		}
+		if c.Path != "" && c.Path != path &&
+			(!strings.HasPrefix(path, c.Path) || (!strings.HasSuffix(c.Path, "/") && path[len(c.Path)] != '/')) {
+			continue
+		}
+		if c.Secure && u.Scheme != "https" {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// fetch calls do with the session's cookies that apply to pageURL. When do returns a
+// page URL on the login page, the cookies are refreshed and do is called once more.
+// do is called once with no cookies when there is no session.
+func (a *authSession) fetch(ctx context.Context, pageURL string, do func(cookies []captcha.Cookie) (string, error)) error {
+	if a == nil {
+		_, err := do(nil)
+		return err
+	}
+
+	cookies, version := a.current()
+	landedURL, err := do(a.cookiesFor(pageURL, cookies))
+	if err != nil || !a.atLogin(landedURL) {
+		return err
+	}
+	a.svc.logger.Info("auth profile session expired, refreshing",
+		"auth_profile_id", a.profileID,
+		"user_id", a.userID,
+		"url", landedURL,
+	)
+	if err := a.refresh(ctx, version); err != nil {
+		return err
+	}
+
+	cookies, _ = a.current()
+	if landedURL, err = do(a.cookiesFor(pageURL, cookies)); err != nil {
+		return err
+	}
+	if a.atLogin(landedURL) {
+		return ErrAuthSessionExpired
+	}
+	return nil
+}
+
+// refresh replaces cookies that were last replaced at version. Cookies already replaced
+// by another fetch, or saved by another instance, are used as they are; otherwise login
+// profiles sign in again in the browser.
+func (a *authSession) refresh(ctx context.Context, version time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.version.After(version) {
+		return nil
+	}
+
+	profile, err := a.svc.repos.AuthProfile.GetByID(ctx, a.profileID)
+	if err != nil {
+		return fmt.Errorf("failed to get auth profile: %w", err)
+	}
+	if profile == nil {
+		return ErrAuthProfileNotFound
+	}
+	if profile.CookiesUpdatedAt != nil && profile.CookiesUpdatedAt.After(a.version) {
+		var cookies []captcha.Cookie
+		if err := a.svc.decrypt(profile.CookiesEncrypted, &cookies); err != nil {
+			return err
+		}
+		a.cookies, a.version = cookies, *profile.CookiesUpdatedAt
+		return nil
+	}
+
+	if profile.Type != models.AuthProfileLogin {
+		return ErrAuthSessionExpired
+	}
+	if !a.dynamicAllowed {
+		return ErrDynamicFetchNotAllowed
+	}
+	cookies, at, err := a.svc.login(ctx, a.userID, a.tier, profile)
+	if err != nil {
+		return err
+	}
+	a.cookies, a.version = cookies, at
+	return nil
+}
+
+// authFetcher sends an auth profile's cookies with every fetch, signing in again when
+// a page redirects to the login page.
+type authFetcher struct {
+	fetcher.Fetcher
+	auth *authSession
+}
+
+// Fetch retrieves the URL with the auth profile's cookies added to opts.
+func (f *authFetcher) Fetch(ctx context.Context, url string, opts fetcher.Options) (fetcher.Content, error) {
+	var content fetcher.Content
+	err := f.auth.fetch(ctx, url, func(cookies []captcha.Cookie) (string, error) {
+		signed := opts
+		signed.Cookies = append(append([]fetcher.Cookie(nil), opts.Cookies...), fetcherCookies(cookies)...)
+		var err error
+		content, err = f.Fetcher.Fetch(ctx, url, signed)
+		return content.URL, err
+	})
+	if err != nil {
+		return fetcher.Content{}, err
+	}
+	return content, nil
+}
+
+// fetcherCookies converts cookies to the fetcher's cookie type.
+func fetcherCookies(cookies []captcha.Cookie) []fetcher.Cookie {
+	result := make([]fetcher.Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		result = append(result, fetcher.Cookie{Name: c.Name, Value: c.Value, Domain: c.Domain})
+	}
+	return result
+}
+
+// httpCookies converts cookies to net/http cookies.
+func httpCookies(cookies []captcha.Cookie) []*http.Cookie {
+	result := make([]*http.Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		result = append(result, &http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+		})
+	}
+	return result
+}
+
+// cookieJar returns a jar holding the cookies that apply to pageURL, or nil when there are none.
+func cookieJar(pageURL string, cookies []captcha.Cookie) http.CookieJar {
+	if len(cookies) == 0 {
+		return nil
+	}
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	jar, _ := cookiejar.New(nil)
+	jar.SetCookies(u, httpCookies(cookies))
+	return jar
+}
@@ -0,0 +1,524 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+	"github.com/oklog/ulid/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/crypto"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+)
+
+// mockAuthProfileRepository implements repository.AuthProfileRepository for testing
+type mockAuthProfileRepository struct {
+	mu       sync.RWMutex
+	profiles map[string]*models.AuthProfile
+}
+
+func newMockAuthProfileRepository() *mockAuthProfileRepository {
+	return &mockAuthProfileRepository{profiles: make(map[string]*models.AuthProfile)}
+}
+
+func (m *mockAuthProfileRepository) Create(ctx context.Context, profile *models.AuthProfile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if profile.ID == "" {
+		profile.ID = ulid.Make().String()
+	}
+	stored := *profile
+	m.profiles[profile.ID] = &stored
+	return nil
+}
+
+func (m *mockAuthProfileRepository) Update(ctx context.Context, profile *models.AuthProfile) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *profile
+	m.profiles[profile.ID] = &stored
+	return nil
+}
+
+func (m *mockAuthProfileRepository) UpdateCookies(ctx context.Context, id, cookiesEncrypted string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if p, ok := m.profiles[id]; ok {
+		p.CookiesEncrypted = cookiesEncrypted
+		p.CookiesUpdatedAt = &at
+	}
+	return nil
+}
+
+func (m *mockAuthProfileRepository) GetByID(ctx context.Context, id string) (*models.AuthProfile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.profiles[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (m *mockAuthProfileRepository) GetByUserID(ctx context.Context, userID string) ([]*models.AuthProfile, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var result []*models.AuthProfile
+	for _, p := range m.profiles {
+		if p.UserID == userID {
+			copied := *p
+			result = append(result, &copied)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAuthProfileRepository) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.profiles, id)
+	return nil
+}
+
+func setupAuthProfileService(t *testing.T) (*AuthProfileService, *mockAuthProfileRepository) {
+	t.Helper()
+
+	repo := newMockAuthProfileRepository()
+	encryptor, err := crypto.NewEncryptor([]byte("12345678901234567890123456789012"))
+	if err != nil {
+		t.Fatalf("failed to create encryptor: %v", err)
+	}
+	svc := NewAuthProfileService(&repository.Repositories{AuthProfile: repo}, encryptor, slog.Default())
+	return svc, repo
+}
+
+func testLoginScript() *models.AuthLoginScript {
+	return &models.AuthLoginScript{
+		Fields: []models.AuthLoginField{
+			{Selector: "#username", Value: "buyer@example.com"},
+			{Selector: "#password", Value: "hunter2"},
+		},
+		SubmitSelector:  "button[type=submit]",
+		SuccessSelector: ".account-menu",
+	}
+}
+
+// newLoginSite starts a site whose pages redirect to /login unless the session cookie is valid.
+func newLoginSite(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			_, _ = w.Write([]byte("<form>sign in</form>"))
+			return
+		}
+		if c, err := r.Cookie("session"); err != nil || c.Value != "valid" {
+			http.Redirect(w, r, "/login?next="+r.URL.Path, http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("<h1>orders</h1>"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fetchAccount returns a fetch that gets the site's account page with the given cookies.
+func fetchAccount(server *httptest.Server, calls *int32) func(cookies []captcha.Cookie) (string, error) {
+	return func(cookies []captcha.Cookie) (string, error) {
+		atomic.AddInt32(calls, 1)
+		client := &http.Client{Jar: cookieJar(server.URL, cookies)}
+		resp, err := client.Get(server.URL + "/account")
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		return resp.Request.URL.String(), nil
+	}
+}
+
+func TestAuthProfileService_Create(t *testing.T) {
+	svc, repo := setupAuthProfileService(t)
+	ctx := context.Background()
+
+	profile, err := svc.Create(ctx, "user-1", AuthProfileInput{
+		Name:    "supplier portal",
+		Type:    models.AuthProfileCookies,
+		Cookies: []captcha.Cookie{{Name: "session", Value: "valid", Domain: "portal.example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	stored, _ := repo.GetByID(ctx, profile.ID)
+	if stored.CookiesEncrypted == "" || strings.Contains(stored.CookiesEncrypted, "valid") {
+		t.Errorf("CookiesEncrypted = %q, want encrypted cookies", stored.CookiesEncrypted)
+	}
+	if stored.CookiesUpdatedAt == nil {
+		t.Error("expected CookiesUpdatedAt to be set")
+	}
+
+	login, err := svc.Create(ctx, "user-1", AuthProfileInput{
+		Name:     "portal login",
+		Type:     models.AuthProfileLogin,
+		LoginURL: "https://portal.example.com/login",
+		Script:   testLoginScript(),
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if strings.Contains(login.ScriptEncrypted, "hunter2") {
+		t.Error("expected login script to be encrypted")
+	}
+}
+
+func TestAuthProfileService_Create_Invalid(t *testing.T) {
+	svc, _ := setupAuthProfileService(t)
+	cookies := []captcha.Cookie{{Name: "session", Value: "valid", Domain: "portal.example.com"}}
+
+	tests := []struct {
+		name  string
+		input AuthProfileInput
+	}{
+		{"missing name", AuthProfileInput{Type: models.AuthProfileCookies, Cookies: cookies}},
+		{"unknown type", AuthProfileInput{Name: "p", Type: "oauth", Cookies: cookies}},
+		{"cookies missing", AuthProfileInput{Name: "p", Type: models.AuthProfileCookies}},
+		{"unnamed cookie", AuthProfileInput{Name: "p", Type: models.AuthProfileCookies, Cookies: []captcha.Cookie{{Value: "x"}}}},
+		{"cookie without domain or login url", AuthProfileInput{Name: "p", Type: models.AuthProfileCookies, Cookies: []captcha.Cookie{{Name: "session", Value: "x"}}}},
+		{"bad login url", AuthProfileInput{Name: "p", Type: models.AuthProfileCookies, Cookies: cookies, LoginURL: "ftp://portal.example.com"}},
+		{"login without url", AuthProfileInput{Name: "p", Type: models.AuthProfileLogin, Script: testLoginScript()}},
+		{"login without script", AuthProfileInput{Name: "p", Type: models.AuthProfileLogin, LoginURL: "https://portal.example.com/login"}},
+		{"script without fields", AuthProfileInput{Name: "p", Type: models.AuthProfileLogin, LoginURL: "https://portal.example.com/login",
+			Script: &models.AuthLoginScript{SubmitSelector: "button", SuccessSelector: ".menu"}}},
+		{"script without success selector", AuthProfileInput{Name: "p", Type: models.AuthProfileLogin, LoginURL: "https://portal.example.com/login",
+			Script: &models.AuthLoginScript{Fields: []models.AuthLoginField{{Selector: "#u", Value: "a"}}, SubmitSelector: "button"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Create(context.Background(), "user-1", tt.input); !errors.Is(err, ErrInvalidAuthProfile) {
+				t.Errorf("Create() error = %v, want ErrInvalidAuthProfile", err)
+			}
+		})
+	}
+}
+
+func TestAuthProfileService_Update_KeepsSecrets(t *testing.T) {
+	svc, _ := setupAuthProfileService(t)
+	ctx := context.Background()
+
+	profile, err := svc.Create(ctx, "user-1", AuthProfileInput{
+		Name:    "portal",
+		Type:    models.AuthProfileCookies,
+		Cookies: []captcha.Cookie{{Name: "session", Value: "valid", Domain: "portal.example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	updated, err := svc.Update(ctx, "user-1", profile.ID, AuthProfileInput{Name: "renamed", Type: models.AuthProfileCookies})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Name != "renamed" || updated.CookiesEncrypted != profile.CookiesEncrypted {
+		t.Errorf("Update() = %+v, want name changed and cookies kept", updated)
+	}
+
+	if _, err := svc.Update(ctx, "user-2", profile.ID, AuthProfileInput{Name: "mine", Type: models.AuthProfileCookies}); !errors.Is(err, ErrAuthProfileNotFound) {
+		t.Errorf("Update() by another user error = %v, want ErrAuthProfileNotFound", err)
+	}
+	if err := svc.Delete(ctx, "user-2", profile.ID); !errors.Is(err, ErrAuthProfileNotFound) {
+		t.Errorf("Delete() by another user error = %v, want ErrAuthProfileNotFound", err)
+	}
+}
+
+func TestAuthProfileService_Login_RequiresLoginProfile(t *testing.T) {
+	svc, _ := setupAuthProfileService(t)
+	ctx := context.Background()
+
+	profile, err := svc.Create(ctx, "user-1", AuthProfileInput{
+		Name:    "portal",
+		Type:    models.AuthProfileCookies,
+		Cookies: []captcha.Cookie{{Name: "session", Value: "valid", Domain: "portal.example.com"}},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := svc.Login(ctx, "user-1", "pro", profile.ID); !errors.Is(err, ErrInvalidAuthProfile) {
+		t.Errorf("Login() error = %v, want ErrInvalidAuthProfile", err)
+	}
+}
+
+func TestLoginActions(t *testing.T) {
+	actions := loginActions(testLoginScript())
+
+	want := []captcha.Action{
+		{Type: "type", Selector: "#username", Text: "buyer@example.com"},
+		{Type: "type", Selector: "#password", Text: "hunter2"},
+		{Type: "click", Selector: "button[type=submit]"},
+		{Type: "wait", Selector: ".account-menu"},
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("loginActions() returned %d actions, want %d", len(actions), len(want))
+	}
+	for i, w := range want {
+		a := actions[i]
+		if a.Type != w.Type || a.Selector != w.Selector || a.Text != w.Text {
+			t.Errorf("action %d = %+v, want %+v", i, a, w)
+		}
+	}
+	if actions[3].Timeout <= 0 {
+		t.Error("expected the wait action to have a timeout")
+	}
+}
+
+func TestAuthSession_AtLogin(t *testing.T) {
+	svc, _ := setupAuthProfileService(t)
+	ctx := context.Background()
+
+	profile, err := svc.Create(ctx, "user-1", AuthProfileInput{
+		Name:     "portal",
+		Type:     models.AuthProfileCookies,
+		LoginURL: "https://portal.example.com/login",
+		Cookies:  []captcha.Cookie{{Name: "session", Value: "valid"}},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	session, err := svc.session(ctx, "user-1", "pro", profile.ID, false)
+	if err != nil {
+		t.Fatalf("session() error = %v", err)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://portal.example.com/login", true},
+		{"https://portal.example.com/login/?next=/orders", true},
+		{"https://PORTAL.example.com/login", true},
+		{"https://portal.example.com/orders", false},
+		{"https://other.example.com/login", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := session.atLogin(tt.url); got != tt.want {
+			t.Errorf("atLogin(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestAuthSession_Fetch(t *testing.T) {
+	server := newLoginSite(t)
+	ctx := context.Background()
+
+	newProfile := func(t *testing.T, svc *AuthProfileService, typ models.AuthProfileType, value string) *models.AuthProfile {
+		t.Helper()
+		input := AuthProfileInput{
+			Name:     "portal",
+			Type:     typ,
+			LoginURL: server.URL + "/login",
+			Cookies:  []captcha.Cookie{{Name: "session", Value: value}},
+		}
+		if typ == models.AuthProfileLogin {
+			input.Script = testLoginScript()
+		}
+		profile, err := svc.Create(ctx, "user-1", input)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		return profile
+	}
+
+	t.Run("valid cookies", func(t *testing.T) {
+		svc, _ := setupAuthProfileService(t)
+		profile := newProfile(t, svc, models.AuthProfileCookies, "valid")
+		session, err := svc.session(ctx, "user-1", "pro", profile.ID, false)
+		if err != nil {
+			t.Fatalf("session() error = %v", err)
+		}
+
+		var calls int32
+		if err := session.fetch(ctx, server.URL+"/account", fetchAccount(server, &calls)); err != nil {
+			t.Fatalf("fetch() error = %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("fetched %d times, want 1", calls)
+		}
+	})
+
+	t.Run("newer cookies saved by another instance", func(t *testing.T) {
+		svc, repo := setupAuthProfileService(t)
+		profile := newProfile(t, svc, models.AuthProfileCookies, "stale")
+		session, err := svc.session(ctx, "user-1", "pro", profile.ID, false)
+		if err != nil {
+			t.Fatalf("session() error = %v", err)
+		}
+
+		fresh, err := svc.encrypt([]captcha.Cookie{{Name: "session", Value: "valid"}})
+		if err != nil {
+			t.Fatalf("encrypt() error = %v", err)
+		}
+		if err := repo.UpdateCookies(ctx, profile.ID, fresh, profile.CookiesUpdatedAt.Add(time.Minute)); err != nil {
+			t.Fatalf("UpdateCookies() error = %v", err)
+		}
+
+		var calls int32
+		if err := session.fetch(ctx, server.URL+"/account", fetchAccount(server, &calls)); err != nil {
+			t.Fatalf("fetch() error = %v", err)
+		}
+		if calls != 2 {
+			t.Errorf("fetched %d times, want 2", calls)
+		}
+		if cookies, _ := session.current(); len(cookies) != 1 || cookies[0].Value != "valid" {
+			t.Errorf("session cookies = %+v, want the newer cookies", cookies)
+		}
+	})
+
+	t.Run("expired cookie profile", func(t *testing.T) {
+		svc, _ := setupAuthProfileService(t)
+		profile := newProfile(t, svc, models.AuthProfileCookies, "stale")
+		session, err := svc.session(ctx, "user-1", "pro", profile.ID, true)
+		if err != nil {
+			t.Fatalf("session() error = %v", err)
+		}
+
+		var calls int32
+		if err := session.fetch(ctx, server.URL+"/account", fetchAccount(server, &calls)); !errors.Is(err, ErrAuthSessionExpired) {
+			t.Errorf("fetch() error = %v, want ErrAuthSessionExpired", err)
+		}
+	})
+
+	t.Run("login profile without browser rendering", func(t *testing.T) {
+		svc, _ := setupAuthProfileService(t)
+		profile := newProfile(t, svc, models.AuthProfileLogin, "stale")
+		session, err := svc.session(ctx, "user-1", "pro", profile.ID, false)
+		if err != nil {
+			t.Fatalf("session() error = %v", err)
+		}
+
+		var calls int32
+		if err := session.fetch(ctx, server.URL+"/account", fetchAccount(server, &calls)); !errors.Is(err, ErrDynamicFetchNotAllowed) {
+			t.Errorf("fetch() error = %v, want ErrDynamicFetchNotAllowed", err)
+		}
+	})
+
+	t.Run("no session", func(t *testing.T) {
+		var session *authSession
+		var calls int32
+		if err := session.fetch(ctx, server.URL+"/account", fetchAccount(server, &calls)); err != nil {
+			t.Fatalf("fetch() error = %v", err)
+		}
+		if calls != 1 {
+			t.Errorf("fetched %d times, want 1", calls)
+		}
+	})
+}
+
+func TestAuthProfileService_Session(t *testing.T) {
+	svc, _ := setupAuthProfileService(t)
+	ctx := context.Background()
+
+	if session, err := svc.session(ctx, "user-1", "pro", "", false); session != nil || err != nil {
+		t.Errorf("session() with no ID = %v, %v, want nil, nil", session, err)
+	}
+	if _, err := svc.session(ctx, "user-1", "pro", "missing", false); !errors.Is(err, ErrAuthProfileNotFound) {
+		t.Errorf("session() error = %v, want ErrAuthProfileNotFound", err)
+	}
+
+	var unconfigured *AuthProfileService
+	if _, err := unconfigured.session(ctx, "user-1", "pro", "some-id", false); !errors.Is(err, ErrAuthProfileNotFound) {
+		t.Errorf("session() on nil service error = %v, want ErrAuthProfileNotFound", err)
+	}
+}
+
+func TestAuthSession_CookiesFor(t *testing.T) {
+	loginURL, _ := url.Parse("https://portal.example.com/login")
+	session := &authSession{loginURL: loginURL}
+	cookies := []captcha.Cookie{
+		{Name: "host", Value: "1"},
+		{Name: "parent", Value: "2", Domain: ".example.com"},
+		{Name: "orders", Value: "3", Domain: "portal.example.com", Path: "/orders"},
+		{Name: "secure", Value: "4", Domain: "portal.example.com", Secure: true},
+	}
+
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		{"https://portal.example.com/orders/1", []string{"host", "parent", "orders", "secure"}},
+		{"https://portal.example.com/ordersx", []string{"host", "parent", "secure"}},
+		{"http://portal.example.com/", []string{"host", "parent"}},
+		{"https://www.example.com/", []string{"parent"}},
+		{"https://example.com.evil.net/", nil},
+		{"https://cdn.other.net/report.pdf", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range session.cookiesFor(tt.url, cookies) {
+			got = append(got, c.Name)
+			if c.Domain == "" {
+				t.Errorf("cookiesFor(%q) cookie %s has no domain", tt.url, c.Name)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("cookiesFor(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestAuthFetcher_OtherHostGetsNoCookies(t *testing.T) {
+	var portalCookie, otherCookie atomic.Value
+	portal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		portalCookie.Store(r.Header.Get("Cookie"))
+		_, _ = w.Write([]byte("<p>not a pdf</p>"))
+	}))
+	t.Cleanup(portal.Close)
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherCookie.Store(r.Header.Get("Cookie"))
+		_, _ = w.Write([]byte("<p>not a pdf</p>"))
+	}))
+	t.Cleanup(other.Close)
+	// Serve the other site from a different host name than the portal
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+
+	svc, _ := setupAuthProfileService(t)
+	ctx := context.Background()
+	profile, err := svc.Create(ctx, "user-1", AuthProfileInput{
+		Name:     "portal",
+		Type:     models.AuthProfileCookies,
+		LoginURL: portal.URL + "/login",
+		Cookies:  []captcha.Cookie{{Name: "session", Value: "valid"}},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	session, err := svc.session(ctx, "user-1", "pro", profile.ID, false)
+	if err != nil {
+		t.Fatalf("session() error = %v", err)
+	}
+	f := &authFetcher{Fetcher: NewDocumentFetcher(nil, nil, slog.Default()), auth: session}
+
+	if _, err := f.Fetch(ctx, portal.URL+"/invoices/1.pdf", fetcher.Options{}); err != nil {
+		t.Fatalf("Fetch() portal error = %v", err)
+	}
+	if got, _ := portalCookie.Load().(string); got != "session=valid" {
+		t.Errorf("portal got Cookie %q, want the profile's session", got)
+	}
+
+	if _, err := f.Fetch(ctx, otherURL+"/datasheet.pdf", fetcher.Options{}); err != nil {
+		t.Fatalf("Fetch() other host error = %v", err)
+	}
+	if got, _ := otherCookie.Load().(string); got != "" {
+		t.Errorf("other host got Cookie %q, want no profile cookies", got)
+	}
+}
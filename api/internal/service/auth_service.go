@@ -3,9 +3,11 @@ package service
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"errors"
 	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/jmylchreest/refyne-api/internal/config"
@@ -17,6 +19,10 @@ var (
 	ErrTokenExpired = errors.New("token expired")
 )
 
+// OperatorUserID is the user ID of the self-hosted operator, who authenticates with the
+// API key whose hash is configured as REFYNE_API_KEY_HASH.
+const OperatorUserID = "operator"
+
 // AuthService handles authentication logic.
 // Note: User authentication (login, register, OAuth) is handled by Clerk.
 // This service only handles API key validation.
@@ -42,6 +48,7 @@ type TokenClaims struct {
 	Tier             string   `json:"tier"`
 	GlobalSuperadmin bool     `json:"global_superadmin,omitempty"`
 	Scopes           []string `json:"scopes,omitempty"`
+	KeyID            string   `json:"kid,omitempty"` // ID of the API key the claims were issued for
 }
 
 // ValidateAPIKey validates an API key and returns claims.
@@ -53,6 +60,16 @@ func (s *AuthService) ValidateAPIKey(ctx context.Context, apiKey string) (*Token
 	hash := sha256.Sum256([]byte(apiKey))
 	hashStr := hex.EncodeToString(hash[:])
 
+	// Self-hosted has no Clerk, so the operator administers the instance with its key
+	if s.isOperatorKey(hashStr) {
+		return &TokenClaims{
+			UserID:           OperatorUserID,
+			Tier:             "selfhosted",
+			GlobalSuperadmin: true,
+			Scopes:           []string{"*"},
+		}, nil
+	}
+
 	// Find the key
 	key, err := s.repos.APIKey.GetByKeyHash(ctx, hashStr)
 	if err != nil {
@@ -89,7 +106,17 @@ func (s *AuthService) ValidateAPIKey(ctx context.Context, apiKey string) (*Token
 		UserID:           key.UserID, // Clerk user ID
 		Email:            "",         // Not stored with API key
 		Tier:             tier,
-		GlobalSuperadmin: false, // API keys don't get superadmin access
+		GlobalSuperadmin: false, // User API keys don't get superadmin access
 		Scopes:           key.Scopes,
+		KeyID:            key.ID,
 	}, nil
 }
+
+// isOperatorKey reports whether an API key hash is the self-hosted operator key's.
+// The operator key is only honoured in self-hosted mode.
+func (s *AuthService) isOperatorKey(hashStr string) bool {
+	if !s.cfg.IsSelfHosted() || s.cfg.APIKeyHash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashStr), []byte(strings.ToLower(s.cfg.APIKeyHash))) == 1
+}
@@ -331,6 +331,27 @@ func TestValidateAPIKey_SelfHostedMode(t *testing.T) {
 	}
 }
 
+func TestValidateAPIKey_OperatorKey(t *testing.T) {
+	operatorKey := "rf_operator_key_12345"
+	repos := &repository.Repositories{APIKey: newMockAPIKeyRepository()}
+
+	// Self-hosted operators administer the instance with the configured key
+	cfg := &config.Config{DeploymentMode: "selfhosted", APIKeyHash: hashAPIKey(operatorKey)}
+	claims, err := NewAuthService(cfg, repos, slog.Default()).ValidateAPIKey(context.Background(), operatorKey)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !claims.GlobalSuperadmin || claims.UserID != OperatorUserID || claims.Tier != "selfhosted" {
+		t.Errorf("claims = %+v, want self-hosted operator with superadmin", claims)
+	}
+
+	// Hosted deployments administer through Clerk, so the key isn't honoured
+	cfg = &config.Config{DeploymentMode: "hosted", APIKeyHash: hashAPIKey(operatorKey)}
+	if _, err := NewAuthService(cfg, repos, slog.Default()).ValidateAPIKey(context.Background(), operatorKey); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected ErrInvalidToken in hosted mode, got %v", err)
+	}
+}
+
 func TestValidateAPIKey_UpdatesLastUsed(t *testing.T) {
 	mockRepo := newMockAPIKeyRepository()
 	cfg := &config.Config{
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"strings"
 	"time"
 
@@ -23,6 +24,9 @@ var (
 
 	// ErrDuplicatePayment indicates a duplicate Stripe payment ID.
 	ErrDuplicatePayment = errors.New("duplicate payment - already processed")
+
+	// ErrInvalidStatementPeriod indicates a statement period that isn't a YYYY-MM month or "current".
+	ErrInvalidStatementPeriod = errors.New("invalid statement period")
 )
 
 // BalanceService handles user balance and credit operations.
@@ -308,6 +312,119 @@ func (s *BalanceService) GetSubscriptionPeriod(ctx context.Context, userID strin
 	return balance.PeriodStart, balance.PeriodEnd, nil
 }
 
+// StatementPeriodCurrent requests a statement for the user's current subscription period.
+const StatementPeriodCurrent = "current"
+
+// Statement itemizes a user's usage charges for a billing period.
+type Statement struct {
+	Period      string    // YYYY-MM month, or StatementPeriodCurrent
+	PeriodStart time.Time // Inclusive
+	PeriodEnd   time.Time // Exclusive
+	Lines       []StatementLine
+	Totals      StatementTotals
+}
+
+// StatementLine is one usage record on a statement, with its charge broken down.
+type StatementLine struct {
+	models.StatementItem
+	PerTransactionUSD float64 // Flat per-transaction fee included in the charge
+	ChargedUSD        float64 // Credits deducted; only CAPTCHA solves for BYOK usage, whose LLM cost the provider bills directly
+}
+
+// StatementTotals sums the lines of a statement.
+type StatementTotals struct {
+	Jobs              int
+	BYOKJobs          int
+	TokensInput       int
+	TokensOutput      int
+	LLMCostUSD        float64 // LLM cost of charged usage
+	MarkupUSD         float64
+	PerTransactionUSD float64
+	SolverCostUSD     float64 // CAPTCHA solves, charged for BYOK usage too
+	ChargedUSD        float64
+	BYOKLLMCostUSD    float64 // LLM cost of BYOK usage, billed by the provider
+}
+
+// GetStatement itemizes the user's usage for a billing period.
+// The period is a calendar month (YYYY-MM, UTC) or StatementPeriodCurrent for the user's
+// subscription period, which falls back to the current calendar month if no period is set.
+func (s *BalanceService) GetStatement(ctx context.Context, userID, period string) (*Statement, error) {
+	start, end, err := s.statementPeriod(ctx, userID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repos.Usage.GetStatementItems(ctx, userID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statement items: %w", err)
+	}
+
+	statement := &Statement{
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Lines:       make([]StatementLine, 0, len(items)),
+	}
+	totals := &statement.Totals
+	for _, item := range items {
+		line := StatementLine{StatementItem: *item}
+		totals.Jobs++
+		totals.TokensInput += item.TokensInput
+		totals.TokensOutput += item.TokensOutput
+		totals.SolverCostUSD += item.SolverCostUSD
+
+		if item.IsBYOK {
+			line.ChargedUSD = item.SolverCostUSD
+			totals.BYOKJobs++
+			totals.BYOKLLMCostUSD += item.LLMCostUSD
+		} else {
+			line.ChargedUSD = item.TotalChargedUSD
+			line.PerTransactionUSD = perTransactionFee(item)
+			totals.LLMCostUSD += item.LLMCostUSD
+			totals.MarkupUSD += item.MarkupUSD
+			totals.PerTransactionUSD += line.PerTransactionUSD
+		}
+		totals.ChargedUSD += line.ChargedUSD
+
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	return statement, nil
+}
+
+// statementPeriod resolves a statement period to its start (inclusive) and end (exclusive).
+func (s *BalanceService) statementPeriod(ctx context.Context, userID, period string) (time.Time, time.Time, error) {
+	if period == StatementPeriodCurrent {
+		periodStart, periodEnd, err := s.GetSubscriptionPeriod(ctx, userID)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if periodStart != nil && periodEnd != nil {
+			return *periodStart, *periodEnd, nil
+		}
+		now := time.Now().UTC()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return monthStart, monthStart.AddDate(0, 1, 0), nil
+	}
+
+	month, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %q must be YYYY-MM or %s", ErrInvalidStatementPeriod, period, StatementPeriodCurrent)
+	}
+	return month, month.AddDate(0, 1, 0), nil
+}
+
+// perTransactionFee derives the flat fee included in a usage charge from what was recorded,
+// rather than the tier's current fee, which may have changed since.
+func perTransactionFee(item *models.StatementItem) float64 {
+	// Round away floating point noise from the subtraction
+	fee := math.Round((item.TotalChargedUSD-item.LLMCostUSD-item.MarkupUSD-item.SolverCostUSD)*1e8) / 1e8
+	if fee < 0 {
+		return 0
+	}
+	return fee
+}
+
 // isDuplicateKeyError checks if an error is a duplicate key constraint violation.
 func isDuplicateKeyError(err error) bool {
 	if err == nil {
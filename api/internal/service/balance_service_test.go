@@ -182,10 +182,13 @@ func (m *mockCreditTransactionRepository) GetAvailableBalance(ctx context.Contex
 
 // mockUsageRepository implements repository.UsageRepository for testing
 type mockUsageRepository struct {
-	mu            sync.RWMutex
-	monthlySpend  map[string]float64
-	summaries     map[string]map[string]*repository.UsageSummary // userID -> period -> summary
-	dateSummary   *repository.UsageSummary
+	mu             sync.RWMutex
+	monthlySpend   map[string]float64
+	summaries      map[string]map[string]*repository.UsageSummary // userID -> period -> summary
+	dateSummary    *repository.UsageSummary
+	statementItems []*models.StatementItem
+	statementStart time.Time
+	statementEnd   time.Time
 }
 
 func newMockUsageRepository() *mockUsageRepository {
@@ -236,6 +239,14 @@ func (m *mockUsageRepository) CountByUserAndDateRange(ctx context.Context, userI
 	return 0, nil
 }
 
+func (m *mockUsageRepository) GetStatementItems(ctx context.Context, userID string, startDate, endDate time.Time) ([]*models.StatementItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statementStart = startDate
+	m.statementEnd = endDate
+	return m.statementItems, nil
+}
+
 func (m *mockUsageRepository) SetSummary(userID, period string, summary *repository.UsageSummary) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -636,6 +647,112 @@ func TestGetSubscriptionPeriod(t *testing.T) {
 	}
 }
 
+func TestGetStatement(t *testing.T) {
+	svc, _, _, usageRepo := newTestBalanceService()
+	ctx := context.Background()
+
+	usageRepo.statementItems = []*models.StatementItem{
+		{UsageID: "u1", JobID: "job-1", Type: models.JobTypeExtract, TokensInput: 1000, TokensOutput: 200,
+			LLMCostUSD: 0.04, MarkupRate: 0.25, MarkupUSD: 0.01, TotalChargedUSD: 0.055},
+		{UsageID: "u2", JobID: "job-2", Type: models.JobTypeExtract, TokensInput: 500, TokensOutput: 100,
+			LLMCostUSD: 0.03, TotalChargedUSD: 0.03, IsBYOK: true},
+		{UsageID: "u3", JobID: "job-3", Type: models.JobTypeAnalyze, TotalChargedUSD: 0.005},
+	}
+
+	statement, err := svc.GetStatement(ctx, "user_123", "2026-03")
+	if err != nil {
+		t.Fatalf("GetStatement() error = %v", err)
+	}
+
+	wantStart := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	if !statement.PeriodStart.Equal(wantStart) || !statement.PeriodEnd.Equal(wantEnd) {
+		t.Errorf("period = %v - %v, want %v - %v", statement.PeriodStart, statement.PeriodEnd, wantStart, wantEnd)
+	}
+	if !usageRepo.statementStart.Equal(wantStart) || !usageRepo.statementEnd.Equal(wantEnd) {
+		t.Errorf("queried %v - %v, want %v - %v", usageRepo.statementStart, usageRepo.statementEnd, wantStart, wantEnd)
+	}
+	if len(statement.Lines) != 3 {
+		t.Fatalf("len(Lines) = %d, want 3", len(statement.Lines))
+	}
+
+	// Charged line: fee is what remains after LLM cost and markup
+	if fee := statement.Lines[0].PerTransactionUSD; fee != 0.005 {
+		t.Errorf("Lines[0].PerTransactionUSD = %v, want 0.005", fee)
+	}
+	if charged := statement.Lines[0].ChargedUSD; charged != 0.055 {
+		t.Errorf("Lines[0].ChargedUSD = %v, want 0.055", charged)
+	}
+
+	// BYOK line isn't charged
+	if statement.Lines[1].ChargedUSD != 0 || statement.Lines[1].PerTransactionUSD != 0 {
+		t.Errorf("BYOK line charged %v (fee %v), want 0", statement.Lines[1].ChargedUSD, statement.Lines[1].PerTransactionUSD)
+	}
+
+	totals := statement.Totals
+	if totals.Jobs != 3 || totals.BYOKJobs != 1 {
+		t.Errorf("jobs = %d (byok %d), want 3 (byok 1)", totals.Jobs, totals.BYOKJobs)
+	}
+	if totals.TokensInput != 1500 || totals.TokensOutput != 300 {
+		t.Errorf("tokens = %d/%d, want 1500/300", totals.TokensInput, totals.TokensOutput)
+	}
+	if totals.LLMCostUSD != 0.04 || totals.BYOKLLMCostUSD != 0.03 {
+		t.Errorf("llm cost = %v (byok %v), want 0.04 (byok 0.03)", totals.LLMCostUSD, totals.BYOKLLMCostUSD)
+	}
+	if totals.PerTransactionUSD != 0.01 {
+		t.Errorf("per transaction total = %v, want 0.01", totals.PerTransactionUSD)
+	}
+	if diff := totals.ChargedUSD - 0.06; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("charged total = %v, want 0.06", totals.ChargedUSD)
+	}
+}
+
+func TestGetStatementCurrentPeriod(t *testing.T) {
+	svc, balanceRepo, _, usageRepo := newTestBalanceService()
+	ctx := context.Background()
+	userID := "user_123"
+
+	// Without a subscription period, the current calendar month is used
+	statement, err := svc.GetStatement(ctx, userID, StatementPeriodCurrent)
+	if err != nil {
+		t.Fatalf("GetStatement() error = %v", err)
+	}
+	now := time.Now().UTC()
+	wantStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if !statement.PeriodStart.Equal(wantStart) || !statement.PeriodEnd.Equal(wantStart.AddDate(0, 1, 0)) {
+		t.Errorf("period = %v - %v, want calendar month from %v", statement.PeriodStart, statement.PeriodEnd, wantStart)
+	}
+
+	periodStart := time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	balanceRepo.balances[userID] = &models.UserBalance{
+		UserID:      userID,
+		PeriodStart: &periodStart,
+		PeriodEnd:   &periodEnd,
+	}
+
+	statement, err = svc.GetStatement(ctx, userID, StatementPeriodCurrent)
+	if err != nil {
+		t.Fatalf("GetStatement() error = %v", err)
+	}
+	if !statement.PeriodStart.Equal(periodStart) || !statement.PeriodEnd.Equal(periodEnd) {
+		t.Errorf("period = %v - %v, want %v - %v", statement.PeriodStart, statement.PeriodEnd, periodStart, periodEnd)
+	}
+	if !usageRepo.statementStart.Equal(periodStart) {
+		t.Errorf("queried from %v, want %v", usageRepo.statementStart, periodStart)
+	}
+}
+
+func TestGetStatementInvalidPeriod(t *testing.T) {
+	svc, _, _, _ := newTestBalanceService()
+
+	for _, period := range []string{"", "2026", "2026-13", "last-month", "2026-03-01"} {
+		if _, err := svc.GetStatement(context.Background(), "user_123", period); !errors.Is(err, ErrInvalidStatementPeriod) {
+			t.Errorf("GetStatement(%q) error = %v, want ErrInvalidStatementPeriod", period, err)
+		}
+	}
+}
+
 func TestBalanceServiceConcurrent(t *testing.T) {
 	svc, balanceRepo, creditRepo, _ := newTestBalanceService()
 	ctx := context.Background()
@@ -25,6 +25,7 @@ type BillingService struct {
 	repos      *repository.Repositories
 	billingCfg *config.BillingConfig
 	pricingSvc *PricingService
+	spendCaps  *SpendCapService
 	logger     *slog.Logger
 }
 
@@ -38,6 +39,11 @@ func NewBillingService(repos *repository.Repositories, billingCfg *config.Billin
 	}
 }
 
+// SetSpendCaps enables spend cap enforcement and spend tracking.
+func (s *BillingService) SetSpendCaps(spendCaps *SpendCapService) {
+	s.spendCaps = spendCaps
+}
+
 // ========================================
 // Balance Operations
 // ========================================
@@ -73,6 +79,36 @@ func (s *BillingService) CheckSufficientBalance(ctx context.Context, userID stri
 	return nil
 }
 
+// ReserveSpend verifies an operation estimated to cost estimatedCostUSD (and extract one
+// page) won't exceed the user's spend caps or those on the API key used, and holds that
+// spend against the caps until the operation's usage is recorded. Returns a nil
+// reservation when spend caps aren't enabled.
+// Unlike the balance check this also applies to BYOK usage.
+func (s *BillingService) ReserveSpend(ctx context.Context, userID, apiKeyID string, estimatedCostUSD float64) (*SpendReservation, error) {
+	if s.spendCaps == nil {
+		return nil, nil
+	}
+	return s.spendCaps.Reserve(ctx, userID, apiKeyID, models.SpendUsage{CostUSD: estimatedCostUSD, Pages: 1})
+}
+
+// SettleSpend adds usage to the spend cap counters, replacing the reservation it was
+// made under (nil when nothing was reserved).
+func (s *BillingService) SettleSpend(ctx context.Context, userID, apiKeyID string, reservation *SpendReservation, usage models.SpendUsage) {
+	if s.spendCaps == nil {
+		return
+	}
+	s.spendCaps.Settle(ctx, userID, apiKeyID, reservation, usage)
+}
+
+// ReleaseSpend returns a reservation's spend to the caps if the operation ended without
+// settling it. Safe to defer after ReserveSpend.
+func (s *BillingService) ReleaseSpend(ctx context.Context, reservation *SpendReservation) {
+	if s.spendCaps == nil {
+		return
+	}
+	s.spendCaps.Release(ctx, reservation)
+}
+
 // DeductUsage deducts credits for API usage.
 func (s *BillingService) DeductUsage(ctx context.Context, userID string, amountUSD float64, jobID *string) error {
 	if amountUSD <= 0 {
@@ -291,6 +327,7 @@ func (s *BillingService) GetOrCreateSchemaSnapshot(ctx context.Context, userID,
 // UsageRecord holds all data needed to record usage.
 type UsageRecord struct {
 	UserID          string
+	APIKeyID        string // API key used, for per-key spend caps
 	JobID           string
 	JobType         models.JobType
 	Status          string // success, failed, partial
@@ -308,10 +345,13 @@ type UsageRecord struct {
 	LLMCostUSD        float64
 	MarkupRate        float64
 	MarkupUSD         float64
+	SolverCostUSD     float64 // Paid CAPTCHA solves, included in TotalChargedUSD
 	LLMProvider       string
 	LLMModel          string
 	GenerationID      string
 	BYOKProvider      string
+	RoutingPolicy     string
+	RoutingReason     string
 	PagesAttempted    int
 	PagesSuccessful   int
 	FetchDurationMs   int
@@ -320,6 +360,9 @@ type UsageRecord struct {
 	RequestID         string
 	UserAgent         string
 	IPCountry         string
+
+	SpendReservation *SpendReservation // Spend cap reservation the usage settles
+	SpendRecorded    bool              // Spend already counted towards spend caps (crawls record it per page)
 }
 
 // RecordUsage records usage to both lean billing table and rich insights table.
@@ -358,10 +401,13 @@ func (s *BillingService) RecordUsage(ctx context.Context, record *UsageRecord) e
 		LLMCostUSD:        record.LLMCostUSD,
 		MarkupRate:        record.MarkupRate,
 		MarkupUSD:         record.MarkupUSD,
+		SolverCostUSD:     record.SolverCostUSD,
 		LLMProvider:       record.LLMProvider,
 		LLMModel:          record.LLMModel,
 		GenerationID:      record.GenerationID,
 		BYOKProvider:      record.BYOKProvider,
+		RoutingPolicy:     record.RoutingPolicy,
+		RoutingReason:     record.RoutingReason,
 		PagesAttempted:    record.PagesAttempted,
 		PagesSuccessful:   record.PagesSuccessful,
 		FetchDurationMs:   record.FetchDurationMs,
@@ -378,17 +424,38 @@ func (s *BillingService) RecordUsage(ctx context.Context, record *UsageRecord) e
 		// Don't fail - lean record is more important
 	}
 
+	if !record.SpendRecorded {
+		s.SettleSpend(ctx, record.UserID, record.APIKeyID, record.SpendReservation, spendForUsage(record))
+	}
+
 	return nil
 }
 
+// spendForUsage returns the spend a usage record counts towards spend caps.
+// BYOK usage is only charged for CAPTCHA solves, so its LLM cost counts as well.
+func spendForUsage(record *UsageRecord) models.SpendUsage {
+	spend := models.SpendUsage{
+		CostUSD: record.TotalChargedUSD,
+		Tokens:  int64(record.TokensInput + record.TokensOutput),
+		Pages:   int64(record.PagesSuccessful),
+	}
+	if record.IsBYOK {
+		spend.CostUSD = record.LLMCostUSD + record.SolverCostUSD
+	}
+	if spend.Pages == 0 && (record.Status == "success" || record.Status == "completed") {
+		spend.Pages = 1
+	}
+	return spend
+}
+
 // ========================================
 // BYOK Detection
 // ========================================
 
 // IsBYOK determines if the request is using user's own API key.
 func (s *BillingService) IsBYOK(provider, apiKey, serviceOpenRouterKey, serviceAnthropicKey, serviceOpenAIKey string) bool {
-	// Ollama is local, so effectively BYOK (no API cost)
-	if provider == llm.ProviderOllama {
+	// Self-hosted providers are local, so effectively BYOK (no API cost)
+	if !llm.RequiresAPIKey(provider) {
 		return true
 	}
 
@@ -417,6 +484,7 @@ func (s *BillingService) IsBYOK(provider, apiKey, serviceOpenRouterKey, serviceA
 // ChargeForUsageInput contains all info needed to charge and record usage.
 type ChargeForUsageInput struct {
 	UserID       string
+	APIKeyID     string // API key used, for per-key spend caps
 	Tier         string
 	JobID        string
 	JobType      models.JobType
@@ -427,6 +495,12 @@ type ChargeForUsageInput struct {
 	Provider     string
 	APIKey       string // User's API key for BYOK (used to query actual cost)
 
+	// SolverCostUSD is what paid CAPTCHA solvers charged for the job. It is passed
+	// through at cost and charged even for BYOK usage, since the solvers bill us.
+	SolverCostUSD float64
+
+	SpendReservation *SpendReservation // Spend cap reservation the usage settles
+
 	// For recording insights
 	TargetURL         string
 	SchemaID          string
@@ -434,6 +508,8 @@ type ChargeForUsageInput struct {
 	ErrorMessage      string
 	ErrorCode         string
 	GenerationID      string
+	RoutingPolicy     string
+	RoutingReason     string
 	PagesAttempted    int
 	PagesSuccessful   int
 	FetchDurationMs   int
@@ -446,10 +522,11 @@ type ChargeForUsageInput struct {
 
 // ChargeForUsageResult contains the billing results.
 type ChargeForUsageResult struct {
-	LLMCostUSD   float64
-	MarkupRate   float64
-	MarkupUSD    float64
-	TotalCostUSD float64
+	LLMCostUSD    float64
+	MarkupRate    float64
+	MarkupUSD     float64
+	SolverCostUSD float64
+	TotalCostUSD  float64 // User cost of the LLM usage plus solver cost
 }
 
 // ChargeForUsage handles the complete billing flow: calculate cost, deduct credits, record usage.
@@ -471,16 +548,22 @@ func (s *BillingService) ChargeForUsage(ctx context.Context, input *ChargeForUsa
 	result.TotalCostUSD = costs.UserCostUSD
 	result.MarkupRate = costs.MarkupRate
 	result.MarkupUSD = costs.MarkupUSD
+	result.SolverCostUSD = input.SolverCostUSD
+	result.TotalCostUSD += input.SolverCostUSD
 
-	// Deduct credits for non-BYOK
-	if !input.IsBYOK && result.TotalCostUSD > 0 {
+	// Deduct credits for non-BYOK usage; BYOK usage is only charged for CAPTCHA solves
+	charge := result.TotalCostUSD
+	if input.IsBYOK {
+		charge = result.SolverCostUSD
+	}
+	if charge > 0 {
 
 		// Deduct credits
 		jobID := &input.JobID
 		if input.JobID == "" {
 			jobID = nil
 		}
-		if err := s.DeductUsage(ctx, input.UserID, result.TotalCostUSD, jobID); err != nil {
+		if err := s.DeductUsage(ctx, input.UserID, charge, jobID); err != nil {
 			s.logger.Warn("failed to deduct credits", "user_id", input.UserID, "error", err)
 			// Continue - don't fail the operation for billing errors
 		}
@@ -497,6 +580,7 @@ func (s *BillingService) ChargeForUsage(ctx context.Context, input *ChargeForUsa
 	// Record usage
 	usageRecord := &UsageRecord{
 		UserID:            input.UserID,
+		APIKeyID:          input.APIKeyID,
 		JobID:             input.JobID,
 		JobType:           input.JobType,
 		Status:            status,
@@ -512,9 +596,12 @@ func (s *BillingService) ChargeForUsage(ctx context.Context, input *ChargeForUsa
 		LLMCostUSD:        result.LLMCostUSD,
 		MarkupRate:        result.MarkupRate,
 		MarkupUSD:         result.MarkupUSD,
+		SolverCostUSD:     result.SolverCostUSD,
 		LLMProvider:       input.Provider,
 		LLMModel:          input.Model,
 		GenerationID:      input.GenerationID,
+		RoutingPolicy:     input.RoutingPolicy,
+		RoutingReason:     input.RoutingReason,
 		PagesAttempted:    input.PagesAttempted,
 		PagesSuccessful:   input.PagesSuccessful,
 		FetchDurationMs:   input.FetchDurationMs,
@@ -523,6 +610,7 @@ func (s *BillingService) ChargeForUsage(ctx context.Context, input *ChargeForUsa
 		RequestID:         input.RequestID,
 		UserAgent:         input.UserAgent,
 		IPCountry:         input.IPCountry,
+		SpendReservation:  input.SpendReservation,
 	}
 
 	if input.IsBYOK {
@@ -71,6 +71,10 @@ func (m *mockBillingUsageRepository) CountByUserAndDateRange(ctx context.Context
 	return len(m.records), nil
 }
 
+func (m *mockBillingUsageRepository) GetStatementItems(ctx context.Context, userID string, startDate, endDate time.Time) ([]*models.StatementItem, error) {
+	return nil, nil
+}
+
 // mockUsageInsightRepository implements repository.UsageInsightRepository for testing
 type mockUsageInsightRepository struct {
 	mu        sync.RWMutex
@@ -10,18 +10,30 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/observability"
 )
 
 // ErrCaptchaServiceStarting is returned when the captcha service is still warming up.
 var ErrCaptchaServiceStarting = errors.New("captcha service is starting up, please try again in a few seconds")
 
+// ErrSolverBudgetExceeded is returned when a page needs a paid CAPTCHA solve but the
+// daily solver spend cap has been reached.
+var ErrSolverBudgetExceeded = errors.New("daily CAPTCHA solver budget exceeded, please try again tomorrow")
+
 // isServiceStartingUp checks if the error message indicates the service is still starting.
 func isServiceStartingUp(msg string) bool {
 	return strings.Contains(strings.ToLower(msg), "starting up") ||
 		strings.Contains(strings.ToLower(msg), "service starting")
 }
 
+// isSolverBudgetExceeded checks if the error message indicates no solver fit the spend budget.
+func isSolverBudgetExceeded(msg string) bool {
+	return strings.Contains(strings.ToLower(msg), "solver spend budget exceeded")
+}
+
 // CaptchaService provides internal captcha/browser solving for dynamic content.
 // This is an internal service used by the extraction pipeline, not exposed to users.
 // Users with the "content_dynamic" feature get JavaScript/real browser support.
@@ -32,6 +44,12 @@ type CaptchaService struct {
 
 	// External captcha service API key (for 2captcha, etc. when native solving fails)
 	externalAPIKey string
+
+	// Daily solver spend caps; nil leaves paid solves unlimited
+	spendCaps *SpendCapService
+
+	// Challenge clearances from browser renders, reused by static fetches
+	clearances *ClearanceStore
 }
 
 // SessionInfo tracks session ownership and instance routing.
@@ -63,9 +81,24 @@ func NewCaptchaService(cfg CaptchaServiceConfig) *CaptchaService {
 		client:         client,
 		logger:         cfg.Logger,
 		externalAPIKey: cfg.ExternalAPIKey,
+		clearances:     NewClearanceStore(),
 	}
 }
 
+// SetSpendCaps sets the spend cap service that limits and records paid CAPTCHA solves.
+func (s *CaptchaService) SetSpendCaps(spendCaps *SpendCapService) {
+	s.spendCaps = spendCaps
+}
+
+// Clearances returns the challenge clearances obtained by browser renders, or nil
+// when the captcha service isn't configured.
+func (s *CaptchaService) Clearances() *ClearanceStore {
+	if s == nil {
+		return nil
+	}
+	return s.clearances
+}
+
 // CaptchaSolveInput is the input for solving a captcha/fetching dynamic content.
 type CaptchaSolveInput struct {
 	URL        string
@@ -73,7 +106,12 @@ type CaptchaSolveInput struct {
 	MaxTimeout int
 	Cookies    []captcha.Cookie
 	Proxy      *captcha.ProxyConfig
-	JobID      string // Optional job ID for tracking/logging
+	Actions    []captcha.Action        // Browser actions run before the page is captured
+	Network    *captcha.NetworkCapture // XHR/fetch responses to record during the page load
+	Resources  *captcha.ResourcePolicy // Page resources to block while rendering
+	JobID      string                  // Optional job ID for tracking/logging
+
+	Screenshots *captcha.ScreenshotCapture // Full-page screenshots to take after the page loads
 }
 
 // CaptchaSolveOutput is the output from solving a captcha.
@@ -86,12 +124,22 @@ type CaptchaSolveOutput struct {
 	Solved        bool
 	// ExternalServiceUsed indicates if an external service (2captcha, etc.) was used
 	ExternalServiceUsed bool
+	// BlockedRequests is the number of requests blocked by the resource policy
+	BlockedRequests int
+	// SolverCostUSD is what a paid CAPTCHA solver charged for the page
+	SolverCostUSD float64
 }
 
 // FetchDynamicContent fetches content using a real browser, solving any challenges encountered.
 // This is called internally by the extraction service for users with "content_dynamic" feature.
 // Returns ErrSessionNotFound if specified session doesn't exist, ErrSessionNotOwned if user doesn't own it.
-func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier string, input CaptchaSolveInput) (*CaptchaSolveOutput, error) {
+func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier string, input CaptchaSolveInput) (_ *CaptchaSolveOutput, err error) {
+	ctx, span := observability.StartSpan(ctx, "captcha.fetch",
+		attribute.String("url.full", input.URL),
+		attribute.String("job.id", input.JobID),
+	)
+	defer func() { observability.EndSpan(span, err) }()
+
 	// Get instance ID for session affinity if session is specified
 	instanceID := ""
 	if input.Session != "" {
@@ -122,12 +170,17 @@ func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier s
 
 	// Build request
 	req := captcha.SolveRequest{
-		Cmd:        "request.get",
-		URL:        input.URL,
-		Session:    input.Session,
-		MaxTimeout: input.MaxTimeout,
-		Cookies:    input.Cookies,
-		Proxy:      input.Proxy,
+		Cmd:            "request.get",
+		URL:            input.URL,
+		Session:        input.Session,
+		MaxTimeout:     input.MaxTimeout,
+		Cookies:        input.Cookies,
+		Proxy:          input.Proxy,
+		Actions:        input.Actions,
+		CaptureNetwork: input.Network,
+		ResourcePolicy: input.Resources,
+
+		CaptureScreenshots: input.Screenshots,
 	}
 
 	// If we have an external API key, include it for fallback to external services
@@ -135,6 +188,24 @@ func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier s
 		req.ExternalAPIKey = s.externalAPIKey
 	}
 
+	var resp *captcha.SolveResponse
+
+	// Limit paid solves to budget reserved from today's solver caps, and settle the
+	// reservation with what the solve cost however the request ends
+	if s.spendCaps != nil {
+		reservation := s.spendCaps.ReserveSolverBudget(ctx, userID)
+		if reservation != nil {
+			req.MaxSolverCostUSD = &reservation.BudgetUSD
+		}
+		defer func() {
+			var costUSD float64
+			if resp != nil && resp.Usage != nil {
+				costUSD = resp.Usage.SolverCostUSD
+			}
+			s.spendCaps.SettleSolverSpend(context.WithoutCancel(ctx), userID, reservation, costUSD)
+		}()
+	}
+
 	s.logger.Info("fetching dynamic content",
 		"user_id", userID,
 		"job_id", input.JobID,
@@ -144,8 +215,6 @@ func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier s
 
 	// Send request to captcha service with retry for startup conditions
 	const maxRetries = 3
-	var resp *captcha.SolveResponse
-	var err error
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		resp, err = s.client.Solve(ctx, userCtx, req, instanceID)
@@ -194,6 +263,9 @@ func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier s
 			"status", resp.Status,
 			"message", resp.Message,
 		)
+		if isSolverBudgetExceeded(resp.Message) {
+			return nil, ErrSolverBudgetExceeded
+		}
 		return nil, fmt.Errorf("dynamic content fetch failed: %s", resp.Message)
 	}
 
@@ -222,7 +294,7 @@ func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier s
 		"external_service_used", resp.SolverUsed != "" && resp.SolverUsed != "native",
 	)
 
-	return &CaptchaSolveOutput{
+	output := &CaptchaSolveOutput{
 		Status:              resp.Status,
 		Message:             resp.Message,
 		Solution:            resp.Solution,
@@ -230,7 +302,15 @@ func (s *CaptchaService) FetchDynamicContent(ctx context.Context, userID, tier s
 		ChallengeType:       resp.ChallengeType,
 		Solved:              resp.Solved,
 		ExternalServiceUsed: resp.SolverUsed != "" && resp.SolverUsed != "native",
-	}, nil
+	}
+	if resp.Usage != nil {
+		output.BlockedRequests = resp.Usage.BlockedRequests
+		output.SolverCostUSD = resp.Usage.SolverCostUSD
+	}
+	if resp.Solution != nil && resp.Solution.Clearance != nil {
+		s.clearances.Put(input.URL, proxyExit(input.Proxy), resp.Solution.Clearance)
+	}
+	return output, nil
 }
 
 // CreateSession creates a new browser session for maintaining state across requests.
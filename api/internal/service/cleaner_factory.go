@@ -6,6 +6,8 @@ import (
 
 	"github.com/jmylchreest/refyne/pkg/cleaner"
 	refynecleaner "github.com/jmylchreest/refyne/pkg/cleaner/refyne"
+
+	"github.com/jmylchreest/refyne-api/internal/cleaners"
 )
 
 // CleanerType represents a named content cleaner type.
@@ -18,6 +20,18 @@ const (
 
 	// CleanerRefyne is our custom configurable cleaner with heuristic-based cleaning.
 	CleanerRefyne CleanerType = "refyne"
+
+	// CleanerSelect keeps only the subtrees matching a CSS selector.
+	CleanerSelect CleanerType = "select"
+
+	// CleanerReadability keeps the main content container using readability heuristics.
+	CleanerReadability CleanerType = "readability"
+
+	// CleanerScriptData extracts hydration JSON (e.g., __NEXT_DATA__) from script tags.
+	CleanerScriptData CleanerType = "script_data"
+
+	// CleanerTable converts HTML tables to markdown or CSV.
+	CleanerTable CleanerType = "table"
 )
 
 // DefaultExtractionCleaner is the default cleaner for extraction operations.
@@ -79,6 +93,12 @@ type CleanerOptions struct {
 	// ResolveURLs: resolve relative URLs to absolute using BaseURL (refyne)
 	// Default: false (API postprocessor handles URL resolution)
 	ResolveURLs bool `json:"resolve_urls,omitempty"`
+
+	// Selector: CSS selector of the subtrees to keep (select, required)
+	Selector string `json:"selector,omitempty"`
+
+	// TableFormat: "markdown" (default) or "csv" (table)
+	TableFormat string `json:"table_format,omitempty"`
 }
 
 // CleanerConfig defines a single cleaner in a chain.
@@ -100,23 +120,100 @@ func NewCleanerFactory() *CleanerFactory {
 
 // Create creates a single cleaner instance from config.
 func (f *CleanerFactory) Create(config CleanerConfig) (cleaner.Cleaner, error) {
-	cleanerType := CleanerType(strings.ToLower(config.Name))
+	reg, ok := lookupCleaner(config.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown cleaner type: %s (valid types: %s)", config.Name, strings.Join(registeredCleanerNames(), ", "))
+	}
+	return reg.New(config.Options)
+}
 
-	switch cleanerType {
-	case CleanerNoop:
-		return cleaner.NewNoop(), nil
+func init() {
+	RegisterCleaner(CleanerNoop, CleanerRegistration{
+		Info: CleanerInfo{
+			Description: "Pass-through cleaner that returns content unchanged. Use for maximum detail when token usage is not a concern.",
+		},
+		New: func(*CleanerOptions) (cleaner.Cleaner, error) {
+			return cleaner.NewNoop(), nil
+		},
+	})
 
-	case CleanerRefyne:
-		return f.createRefyne(config.Options), nil
+	RegisterCleaner(CleanerRefyne, CleanerRegistration{
+		Info: CleanerInfo{
+			Description: "Custom configurable cleaner with heuristic-based HTML cleaning. Removes scripts, styles, hidden elements while preserving content structure. Supports LLM-optimized markdown output with frontmatter.",
+			Options: []CleanerOptionInfo{
+				{Name: "output", Type: "string", Default: "html", Description: "Output format: 'html', 'text', or 'markdown'"},
+				{Name: "preset", Type: "string", Default: "default", Description: "Preset: 'default', 'minimal', or 'aggressive'"},
+				{Name: "include_frontmatter", Type: "boolean", Default: false, Description: "Prepend YAML frontmatter with metadata (markdown output only)"},
+				{Name: "extract_images", Type: "boolean", Default: true, Description: "Extract images to frontmatter with {{IMG_001}} placeholders in body"},
+				{Name: "extract_headings", Type: "boolean", Default: true, Description: "Include heading structure in frontmatter"},
+				{Name: "base_url", Type: "string", Default: "", Description: "Base URL for resolving relative URLs (only used when resolve_urls is true)"},
+				{Name: "resolve_urls", Type: "boolean", Default: false, Description: "Resolve relative URLs to absolute using base_url"},
+				{Name: "remove_selectors", Type: "array", Default: nil, Description: "CSS selectors for elements to remove (e.g., '.sidebar', 'nav')"},
+				{Name: "keep_selectors", Type: "array", Default: nil, Description: "CSS selectors for elements to always keep (overrides removals)"},
+			},
+		},
+		New: func(opts *CleanerOptions) (cleaner.Cleaner, error) {
+			return createRefyne(opts), nil
+		},
+	})
 
-	default:
-		return nil, fmt.Errorf("unknown cleaner type: %s (valid types: noop, refyne)", config.Name)
-	}
+	RegisterCleaner(CleanerSelect, CleanerRegistration{
+		Info: CleanerInfo{
+			Description: "Keeps only the elements matching a CSS selector and discards the rest of the page. Returns the page unchanged if nothing matches. Chain before refyne to narrow extraction to a known container.",
+			Options: []CleanerOptionInfo{
+				{Name: "selector", Type: "string", Default: "", Description: "CSS selector of the elements to keep (required, e.g., '#results' or 'main .product')"},
+			},
+		},
+		New: func(opts *CleanerOptions) (cleaner.Cleaner, error) {
+			selector := ""
+			if opts != nil {
+				selector = opts.Selector
+			}
+			return cleaners.NewSelect(selector)
+		},
+	})
+
+	RegisterCleaner(CleanerReadability, CleanerRegistration{
+		Info: CleanerInfo{
+			Description: "Readability-style main content extraction. Scores text blocks and keeps the best content container, dropping navigation, sidebars, footers and other boilerplate.",
+			Options: []CleanerOptionInfo{
+				{Name: "output", Type: "string", Default: "html", Description: "Output format: 'html' (for chaining with refyne) or 'text'"},
+			},
+		},
+		New: func(opts *CleanerOptions) (cleaner.Cleaner, error) {
+			return cleaners.NewReadability(opts != nil && opts.Output == "text"), nil
+		},
+	})
+
+	RegisterCleaner(CleanerScriptData, CleanerRegistration{
+		Info: CleanerInfo{
+			Description: "Extracts framework hydration data (__NEXT_DATA__, window.__INITIAL_STATE__, JSON script tags) as text. Useful for single-page apps that render content client-side. Returns the page unchanged if no data is found.",
+		},
+		New: func(*CleanerOptions) (cleaner.Cleaner, error) {
+			return cleaners.NewScriptData(), nil
+		},
+	})
+
+	RegisterCleaner(CleanerTable, CleanerRegistration{
+		Info: CleanerInfo{
+			Description: "Converts HTML tables to markdown or CSV, one section per table. Useful for specification sheets, price lists and comparison tables. Returns the page unchanged if it has no tables.",
+			Options: []CleanerOptionInfo{
+				{Name: "table_format", Type: "string", Default: "markdown", Description: "Table format: 'markdown' or 'csv'"},
+			},
+		},
+		New: func(opts *CleanerOptions) (cleaner.Cleaner, error) {
+			format := ""
+			if opts != nil {
+				format = opts.TableFormat
+			}
+			return cleaners.NewTable(format)
+		},
+	})
 }
 
 // createRefyne creates a Refyne cleaner with optional config.
 // The Refyne cleaner is our custom heuristic-based cleaner that's highly configurable.
-func (f *CleanerFactory) createRefyne(opts *CleanerOptions) cleaner.Cleaner {
+func createRefyne(opts *CleanerOptions) cleaner.Cleaner {
 	// Start with default config
 	cfg := refynecleaner.DefaultConfig()
 
@@ -233,28 +330,14 @@ type CleanerInfo struct {
 
 // GetAvailableCleaners returns information about all available cleaners.
 func GetAvailableCleaners() []CleanerInfo {
-	return []CleanerInfo{
-		{
-			Name:        "noop",
-			Description: "Pass-through cleaner that returns content unchanged. Use for maximum detail when token usage is not a concern.",
-			Options:     nil,
-		},
-		{
-			Name:        "refyne",
-			Description: "Custom configurable cleaner with heuristic-based HTML cleaning. Removes scripts, styles, hidden elements while preserving content structure. Supports LLM-optimized markdown output with frontmatter.",
-			Options: []CleanerOptionInfo{
-				{Name: "output", Type: "string", Default: "html", Description: "Output format: 'html', 'text', or 'markdown'"},
-				{Name: "preset", Type: "string", Default: "default", Description: "Preset: 'default', 'minimal', or 'aggressive'"},
-				{Name: "include_frontmatter", Type: "boolean", Default: false, Description: "Prepend YAML frontmatter with metadata (markdown output only)"},
-				{Name: "extract_images", Type: "boolean", Default: true, Description: "Extract images to frontmatter with {{IMG_001}} placeholders in body"},
-				{Name: "extract_headings", Type: "boolean", Default: true, Description: "Include heading structure in frontmatter"},
-				{Name: "base_url", Type: "string", Default: "", Description: "Base URL for resolving relative URLs (only used when resolve_urls is true)"},
-				{Name: "resolve_urls", Type: "boolean", Default: false, Description: "Resolve relative URLs to absolute using base_url"},
-				{Name: "remove_selectors", Type: "array", Default: nil, Description: "CSS selectors for elements to remove (e.g., '.sidebar', 'nav')"},
-				{Name: "keep_selectors", Type: "array", Default: nil, Description: "CSS selectors for elements to always keep (overrides removals)"},
-			},
-		},
+	cleanerRegistry.mu.RLock()
+	defer cleanerRegistry.mu.RUnlock()
+
+	infos := make([]CleanerInfo, 0, len(cleanerRegistry.order))
+	for _, name := range cleanerRegistry.order {
+		infos = append(infos, cleanerRegistry.byKey[name].Info)
 	}
+	return infos
 }
 
 // GetDefaultExtractionChain returns the default cleaner chain for extraction.
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/jmylchreest/refyne/pkg/cleaner"
 )
 
 func TestEnrichCleanerChainWithCrawlSelectors(t *testing.T) {
@@ -139,6 +142,15 @@ func TestCleanerFactoryCreate(t *testing.T) {
 		{"noop cleaner", CleanerConfig{Name: "noop"}, false},
 		{"refyne cleaner", CleanerConfig{Name: "refyne"}, false},
 		{"unknown cleaner", CleanerConfig{Name: "unknown"}, true},
+		{"readability cleaner", CleanerConfig{Name: "readability"}, false},
+		{"script_data cleaner", CleanerConfig{Name: "script_data"}, false},
+		{"table cleaner", CleanerConfig{Name: "table"}, false},
+		{"case insensitive name", CleanerConfig{Name: "Readability"}, false},
+		{"select without selector", CleanerConfig{Name: "select"}, true},
+		{"select with invalid selector", CleanerConfig{Name: "select", Options: &CleanerOptions{Selector: "div[["}}, true},
+		{"select with selector", CleanerConfig{Name: "select", Options: &CleanerOptions{Selector: "#results"}}, false},
+		{"table with csv format", CleanerConfig{Name: "table", Options: &CleanerOptions{TableFormat: "csv"}}, false},
+		{"table with invalid format", CleanerConfig{Name: "table", Options: &CleanerOptions{TableFormat: "xml"}}, true},
 		{
 			"refyne with preset",
 			CleanerConfig{
@@ -220,6 +232,11 @@ func TestCleanerFactoryCreateChain(t *testing.T) {
 			[]CleanerConfig{{Name: "refyne"}, {Name: "noop"}},
 			false,
 		},
+		{
+			"select then refyne",
+			[]CleanerConfig{{Name: "select", Options: &CleanerOptions{Selector: "main"}}, {Name: "refyne"}},
+			false,
+		},
 		{
 			"chain with invalid",
 			[]CleanerConfig{{Name: "refyne"}, {Name: "invalid"}},
@@ -253,12 +270,12 @@ func TestCleanerFactoryCreateChain(t *testing.T) {
 func TestGetAvailableCleaners(t *testing.T) {
 	cleaners := GetAvailableCleaners()
 
-	if len(cleaners) != 2 {
-		t.Errorf("expected 2 cleaners, got %d", len(cleaners))
+	if len(cleaners) != 6 {
+		t.Errorf("expected 6 cleaners, got %d", len(cleaners))
 	}
 
 	// Check that all expected cleaners are present
-	expectedNames := []string{"noop", "refyne"}
+	expectedNames := []string{"noop", "refyne", "select", "readability", "script_data", "table"}
 	cleanerMap := make(map[string]CleanerInfo)
 	for _, c := range cleaners {
 		cleanerMap[c.Name] = c
@@ -319,3 +336,43 @@ func TestGetChainName(t *testing.T) {
 		})
 	}
 }
+
+func TestCleanerFactoryCreate_UnknownListsRegisteredTypes(t *testing.T) {
+	_, err := NewCleanerFactory().Create(CleanerConfig{Name: "bogus"})
+	if err == nil {
+		t.Fatal("expected error for unknown cleaner")
+	}
+	for _, name := range []string{"noop", "refyne", "select", "readability", "script_data", "table"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q should list valid type %q", err, name)
+		}
+	}
+}
+
+func TestRegisterCleaner(t *testing.T) {
+	RegisterCleaner("Test_Upper", CleanerRegistration{
+		Info: CleanerInfo{Description: "test cleaner"},
+		New: func(*CleanerOptions) (cleaner.Cleaner, error) {
+			return cleaner.NewNoop(), nil
+		},
+	})
+	t.Cleanup(func() {
+		cleanerRegistry.mu.Lock()
+		defer cleanerRegistry.mu.Unlock()
+		delete(cleanerRegistry.byKey, "test_upper")
+		cleanerRegistry.order = cleanerRegistry.order[:len(cleanerRegistry.order)-1]
+	})
+
+	c, err := NewCleanerFactory().Create(CleanerConfig{Name: "test_upper"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c == nil {
+		t.Fatal("expected non-nil cleaner")
+	}
+
+	infos := GetAvailableCleaners()
+	if last := infos[len(infos)-1]; last.Name != "test_upper" {
+		t.Errorf("expected registered cleaner listed last with normalized name, got %q", last.Name)
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/jmylchreest/refyne/pkg/cleaner"
+)
+
+// CleanerConstructor builds a cleaner from optional configuration.
+type CleanerConstructor func(opts *CleanerOptions) (cleaner.Cleaner, error)
+
+// CleanerRegistration contains everything needed to expose a cleaner type.
+type CleanerRegistration struct {
+	Info CleanerInfo        // Metadata returned by the cleaners listing endpoint
+	New  CleanerConstructor // Builds an instance from the chain entry's options
+}
+
+// cleanerRegistry holds registered cleaner types in registration order so the
+// listing endpoint and error messages are stable.
+var cleanerRegistry = struct {
+	mu    sync.RWMutex
+	byKey map[CleanerType]CleanerRegistration
+	order []CleanerType
+}{byKey: make(map[CleanerType]CleanerRegistration)}
+
+// RegisterCleaner adds a cleaner type to the registry. Registering a name twice
+// replaces the earlier registration but keeps its position in the listing.
+// Built-in cleaners register themselves from init().
+func RegisterCleaner(name CleanerType, reg CleanerRegistration) {
+	if reg.New == nil {
+		panic(fmt.Sprintf("cleaner %q registered without a constructor", name))
+	}
+	name = CleanerType(strings.ToLower(string(name)))
+
+	cleanerRegistry.mu.Lock()
+	defer cleanerRegistry.mu.Unlock()
+
+	// Ensure info name matches registration key
+	reg.Info.Name = string(name)
+	if _, exists := cleanerRegistry.byKey[name]; !exists {
+		cleanerRegistry.order = append(cleanerRegistry.order, name)
+	}
+	cleanerRegistry.byKey[name] = reg
+}
+
+// lookupCleaner returns the registration for a cleaner name (case-insensitive).
+func lookupCleaner(name string) (CleanerRegistration, bool) {
+	cleanerRegistry.mu.RLock()
+	defer cleanerRegistry.mu.RUnlock()
+	reg, ok := cleanerRegistry.byKey[CleanerType(strings.ToLower(name))]
+	return reg, ok
+}
+
+// registeredCleanerNames returns the registered cleaner names in registration order.
+func registeredCleanerNames() []string {
+	cleanerRegistry.mu.RLock()
+	defer cleanerRegistry.mu.RUnlock()
+	names := make([]string, len(cleanerRegistry.order))
+	for i, name := range cleanerRegistry.order {
+		names[i] = string(name)
+	}
+	return names
+}
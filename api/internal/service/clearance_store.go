@@ -0,0 +1,123 @@
+// Package service contains the business logic layer.
+package service
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+)
+
+// clearanceKey identifies a clearance by the host it was obtained for and the
+// proxy exit it was obtained through.
+type clearanceKey struct {
+	host string
+	exit string
+}
+
+// ClearanceStore holds the Cloudflare/DDoS-Guard clearances browser renders obtained,
+// so static fetches to the same host through the same exit can reuse them instead of
+// falling back to the browser. It is safe for concurrent use.
+type ClearanceStore struct {
+	nowFunc func() time.Time
+
+	mu      sync.Mutex
+	entries map[clearanceKey]*captcha.Clearance
+}
+
+// NewClearanceStore creates an empty clearance store.
+func NewClearanceStore() *ClearanceStore {
+	return &ClearanceStore{
+		nowFunc: time.Now,
+		entries: make(map[clearanceKey]*captcha.Clearance),
+	}
+}
+
+// Get returns the unexpired clearance for the host of pageURL through exit.
+func (s *ClearanceStore) Get(pageURL, exit string) (*captcha.Clearance, bool) {
+	if s == nil {
+		return nil, false
+	}
+	key := clearanceKey{clearanceHost(pageURL), exit}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if s.nowFunc().UnixMilli() >= c.ExpiresAt {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return c, true
+}
+
+// Put stores a clearance obtained for the host of pageURL through exit.
+func (s *ClearanceStore) Put(pageURL, exit string, c *captcha.Clearance) {
+	if s == nil || c == nil || len(c.Cookies) == 0 {
+		return
+	}
+	host := clearanceHost(pageURL)
+	now := s.nowFunc().UnixMilli()
+	if host == "" || now >= c.ExpiresAt {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Clearances are only written after a browser render, so sweeping here is cheap
+	for k, e := range s.entries {
+		if now >= e.ExpiresAt {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[clearanceKey{host, exit}] = c
+}
+
+// Invalidate drops the clearance for the host of pageURL through exit, for when a
+// page was challenged again despite it.
+func (s *ClearanceStore) Invalidate(pageURL, exit string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.entries, clearanceKey{clearanceHost(pageURL), exit})
+	s.mu.Unlock()
+}
+
+// clearanceHost returns the lowercased host of a page URL.
+func clearanceHost(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// proxyExit identifies the address a site sees requests through a proxy come from,
+// matching the captcha service's keying. Rotating proxies commonly pick the exit IP
+// from the username, so it is included. Direct requests have no exit ("").
+//
+// Direct clearances are obtained from the captcha service's address, so static fetches
+// only get through with them when the API shares its egress; when they don't, the page
+// is challenged, the clearance is dropped and the fetch falls back to the browser.
+func proxyExit(cfg *captcha.ProxyConfig) string {
+	if cfg == nil {
+		return ""
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return cfg.URL
+	}
+	username := cfg.Username
+	if username == "" && u.User != nil {
+		username = u.User.Username()
+	}
+	if username != "" {
+		return username + "@" + u.Host
+	}
+	return u.Host
+}
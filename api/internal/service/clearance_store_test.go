@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+)
+
+const clearanceUA = "Mozilla/5.0 (cleared)"
+
+func newTestClearance(now time.Time, ttl time.Duration) *captcha.Clearance {
+	return &captcha.Clearance{
+		Cookies:    []captcha.Cookie{{Name: "cf_clearance", Value: "cleared", Path: "/"}},
+		UserAgent:  clearanceUA,
+		ObtainedAt: now.UnixMilli(),
+		ExpiresAt:  now.Add(ttl).UnixMilli(),
+	}
+}
+
+func TestClearanceStore_PutGet(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s := NewClearanceStore()
+	s.nowFunc = func() time.Time { return now }
+
+	s.Put("https://Example.com/products", "", newTestClearance(now, 30*time.Minute))
+
+	if _, ok := s.Get("https://example.com/other-page", ""); !ok {
+		t.Error("Get() for another page on the host found nothing")
+	}
+	if _, ok := s.Get("https://example.com/", "user@proxy.example.net:8080"); ok {
+		t.Error("Get() through another exit found the direct clearance")
+	}
+	if _, ok := s.Get("https://shop.example.com/", ""); ok {
+		t.Error("Get() for another host found a clearance")
+	}
+
+	now = now.Add(30 * time.Minute)
+	if _, ok := s.Get("https://example.com/", ""); ok {
+		t.Error("Get() after expiry found a clearance")
+	}
+}
+
+func TestClearanceStore_PutIgnoresUnusable(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s := NewClearanceStore()
+	s.nowFunc = func() time.Time { return now }
+
+	s.Put("https://example.com/", "", nil)
+	s.Put("https://example.com/", "", &captcha.Clearance{UserAgent: clearanceUA, ExpiresAt: now.Add(time.Hour).UnixMilli()})
+	s.Put("https://expired.com/", "", newTestClearance(now, -time.Minute))
+	s.Put("not a url", "", newTestClearance(now, time.Hour))
+
+	if len(s.entries) != 0 {
+		t.Errorf("entries = %d, want 0", len(s.entries))
+	}
+
+	var nilStore *ClearanceStore
+	nilStore.Put("https://example.com/", "", newTestClearance(now, time.Hour))
+	if _, ok := nilStore.Get("https://example.com/", ""); ok {
+		t.Error("nil store Get() found a clearance")
+	}
+	nilStore.Invalidate("https://example.com/", "")
+}
+
+func TestProxyExit(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *captcha.ProxyConfig
+		want string
+	}{
+		{"direct", nil, ""},
+		{"without credentials", &captcha.ProxyConfig{URL: "http://proxy.example.net:8080"}, "proxy.example.net:8080"},
+		{"username field", &captcha.ProxyConfig{URL: "http://proxy.example.net:8080", Username: "user-country-de", Password: "pw"}, "user-country-de@proxy.example.net:8080"},
+		{"username in url", &captcha.ProxyConfig{URL: "socks5://user:pw@10.0.0.1:1080"}, "user@10.0.0.1:1080"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := proxyExit(tt.cfg); got != tt.want {
+				t.Errorf("proxyExit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newChallengeServer serves a Cloudflare challenge unless the request carries the
+// clearance cookie with the user agent it was issued to.
+func newChallengeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("cf_clearance")
+		if err != nil || cookie.Value != "cleared" || r.UserAgent() != clearanceUA {
+			w.Header().Set("cf-mitigated", "challenge")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, "<html><head><title>Just a moment...</title></head></html>")
+			return
+		}
+		_, _ = io.WriteString(w, "<html><head><title>Products</title></head><body><main>"+
+			strings.Repeat("<article><h2>Product</h2><p>A product description with a price of $10.</p></article>", 20)+
+			"</main></body></html>")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProtectionAwareFetcher_ReusesClearance(t *testing.T) {
+	srv := newChallengeServer(t)
+	store := NewClearanceStore()
+	f := NewProtectionAwareFetcher(ProtectionAwareFetcherConfig{
+		Clearances: store,
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	ctx := context.Background()
+
+	// Without a clearance the page is challenged, so the caller falls back to the browser
+	if _, err := f.Fetch(ctx, srv.URL+"/a", fetcher.Options{}); err == nil {
+		t.Fatal("Fetch() without clearance expected error")
+	}
+
+	// A browser render cleared the challenge; later static fetches get through with it
+	store.Put(srv.URL+"/a", "", newTestClearance(time.Now(), time.Hour))
+	content, err := f.Fetch(ctx, srv.URL+"/b", fetcher.Options{})
+	if err != nil {
+		t.Fatalf("Fetch() with clearance error = %v", err)
+	}
+	if content.StatusCode != http.StatusOK {
+		t.Errorf("StatusCode = %d, want 200", content.StatusCode)
+	}
+
+	// Clearances are bound to their user agent, so a request for another one doesn't use it
+	if _, err := f.Fetch(ctx, srv.URL+"/c", fetcher.Options{UserAgent: "custom-agent"}); err == nil {
+		t.Error("Fetch() with another user agent expected error")
+	}
+	if _, ok := store.Get(srv.URL, ""); !ok {
+		t.Error("clearance dropped by a fetch that didn't use it")
+	}
+}
+
+func TestProtectionAwareFetcher_DropsRejectedClearance(t *testing.T) {
+	srv := newChallengeServer(t)
+	store := NewClearanceStore()
+	f := NewProtectionAwareFetcher(ProtectionAwareFetcherConfig{
+		Clearances: store,
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+
+	stale := newTestClearance(time.Now(), time.Hour)
+	stale.Cookies[0].Value = "revoked"
+	store.Put(srv.URL, "", stale)
+
+	var botErr *ErrBotProtectionDetected
+	if _, err := f.Fetch(context.Background(), srv.URL, fetcher.Options{}); !errors.As(err, &botErr) {
+		t.Fatalf("Fetch() error = %v, want bot protection detected", err)
+	}
+	if _, ok := store.Get(srv.URL, ""); ok {
+		t.Error("rejected clearance still stored")
+	}
+}
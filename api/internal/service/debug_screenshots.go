@@ -0,0 +1,52 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+)
+
+// DebugScreenshot is a screenshot sent to a vision model during extraction. Debug
+// captures reference the image by its object storage key rather than embedding it.
+type DebugScreenshot struct {
+	Key      string `json:"key"`       // Object storage key of the image
+	MimeType string `json:"mime_type"` // "image/jpeg" or "image/png"
+	Offset   int    `json:"offset"`    // Page y-offset of the segment's top edge, in CSS pixels
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+
+	data string // Base64-encoded image, uploaded by StoreDebugCapture
+}
+
+// NewDebugScreenshots prepares screenshots for a debug capture (nil if there are none).
+func NewDebugScreenshots(shots []captcha.Screenshot) []DebugScreenshot {
+	if len(shots) == 0 {
+		return nil
+	}
+	out := make([]DebugScreenshot, 0, len(shots))
+	for _, shot := range shots {
+		out = append(out, DebugScreenshot{
+			MimeType: shot.MimeType,
+			Offset:   shot.Offset,
+			Width:    shot.Width,
+			Height:   shot.Height,
+			data:     shot.Data,
+		})
+	}
+	return out
+}
+
+// debugScreenshotKey returns the object storage key of a debug capture's screenshot.
+// Keys share the job's debug/{jobID}/ prefix so they are deleted with the capture.
+func debugScreenshotKey(jobID string, capture, segment int, mimeType string) string {
+	ext := "jpg"
+	if mimeType == "image/png" {
+		ext = "png"
+	}
+	return fmt.Sprintf("%s%d-%d.%s", debugScreenshotPrefix(jobID), capture, segment, ext)
+}
+
+// debugScreenshotPrefix returns the object storage prefix of a job's debug screenshots.
+func debugScreenshotPrefix(jobID string) string {
+	return fmt.Sprintf("debug/%s/screenshots/", jobID)
+}
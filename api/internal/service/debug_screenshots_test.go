@@ -0,0 +1,49 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+)
+
+func TestNewDebugScreenshots(t *testing.T) {
+	if got := NewDebugScreenshots(nil); got != nil {
+		t.Errorf("NewDebugScreenshots(nil) = %v, want nil", got)
+	}
+
+	shots := NewDebugScreenshots([]captcha.Screenshot{
+		{Data: "aW1n", MimeType: "image/png", Offset: 2000, Width: 1280, Height: 800},
+	})
+	if len(shots) != 1 || shots[0].Offset != 2000 || shots[0].data != "aW1n" {
+		t.Fatalf("NewDebugScreenshots() = %+v", shots)
+	}
+
+	// The image is uploaded separately and never embedded in the capture JSON
+	data, err := json.Marshal(shots[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "aW1n") {
+		t.Errorf("capture JSON embeds the image: %s", data)
+	}
+}
+
+func TestDebugScreenshotKey(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		want     string
+	}{
+		{"image/jpeg", "debug/job-1/screenshots/2-0.jpg"},
+		{"image/png", "debug/job-1/screenshots/2-0.png"},
+	}
+	for _, tt := range tests {
+		if got := debugScreenshotKey("job-1", 2, 0, tt.mimeType); got != tt.want {
+			t.Errorf("debugScreenshotKey(%s) = %q, want %q", tt.mimeType, got, tt.want)
+		}
+		if !strings.HasPrefix(tt.want, debugScreenshotPrefix("job-1")) {
+			t.Errorf("key %q is outside the job's screenshot prefix", tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/documents"
+)
+
+// ErrDocumentTooLarge is returned when a document exceeds constants.MaxDocumentSize.
+var ErrDocumentTooLarge = errors.New("document exceeds maximum size")
+
+// DocumentFetcher wraps a page fetcher and converts PDF, DOCX, XLSX and CSV
+// responses into page-sectioned HTML, so documents flow through the same cleaner
+// chain and extraction as web pages. URLs with a document file extension are
+// downloaded directly (browser rendering cannot return document bytes), through
+// the request's proxies when it has any; other responses are sniffed after the
+// wrapped fetcher returns them.
+type DocumentFetcher struct {
+	fetcher.Fetcher
+	proxies *proxyRouter
+	logger  *slog.Logger
+}
+
+// NewDocumentFetcher creates a document-aware fetcher around inner. Documents are
+// downloaded through proxies when a router is given.
+func NewDocumentFetcher(inner fetcher.Fetcher, proxies *proxyRouter, logger *slog.Logger) *DocumentFetcher {
+	return &DocumentFetcher{
+		Fetcher: inner,
+		proxies: proxies,
+		logger:  logger,
+	}
+}
+
+// Fetch retrieves the URL and converts document responses to HTML.
+func (f *DocumentFetcher) Fetch(ctx context.Context, url string, opts fetcher.Options) (fetcher.Content, error) {
+	if documents.FormatFromURL(url) != "" {
+		return fetchDocument(ctx, url, opts, f.proxies, f.logger)
+	}
+
+	content, err := f.Fetcher.Fetch(ctx, url, opts)
+	if err != nil {
+		return content, err
+	}
+
+	pageURL := content.URL
+	if pageURL == "" {
+		pageURL = url
+	}
+	format := documents.Detect(content.ContentType, pageURL, []byte(content.HTML))
+	if format == "" {
+		return content, nil
+	}
+	return convertDocument(content, format, []byte(content.HTML), f.logger)
+}
+
+// fetchDocument downloads a document URL directly over HTTP and converts it. With a
+// proxy router the download goes through its proxies, rotating away from blocked
+// responses like page fetches do. Responses that turn out not to be documents are
+// returned unconverted.
+func fetchDocument(ctx context.Context, url string, opts fetcher.Options, proxies *proxyRouter, logger *slog.Logger) (fetcher.Content, error) {
+	var content fetcher.Content
+	var body []byte
+	download := func(ctx context.Context, proxy *SelectedProxy) (proxyResponse, error) {
+		var resp proxyResponse
+		var err error
+		content, body, resp, err = downloadDocument(ctx, url, opts, proxy)
+		return resp, err
+	}
+	if proxies != nil {
+		if _, err := proxies.fetch(ctx, url, download); err != nil {
+			return fetcher.Content{}, err
+		}
+	} else if _, err := download(ctx, nil); err != nil {
+		return fetcher.Content{}, err
+	}
+
+	if content.StatusCode != http.StatusOK {
+		return fetcher.Content{}, fmt.Errorf("failed to fetch document: HTTP %d", content.StatusCode)
+	}
+	if len(body) > constants.MaxDocumentSize {
+		return fetcher.Content{}, ErrDocumentTooLarge
+	}
+
+	format := documents.Detect(content.ContentType, content.URL, body)
+	if format == "" {
+		// The extension lied (e.g., an HTML download page); treat it as a web page
+		return content, nil
+	}
+	return convertDocument(content, format, body, logger)
+}
+
+// downloadDocument GETs url with opts' user agent, headers and cookies, through proxy
+// when one is given. It reads at most one byte past constants.MaxDocumentSize, so the
+// caller can tell the document was too large.
+func downloadDocument(ctx context.Context, url string, opts fetcher.Options, proxy *SelectedProxy) (fetcher.Content, []byte, proxyResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fetcher.Content{}, nil, proxyResponse{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", getDefaultUserAgent(opts))
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{Timeout: constants.DocumentFetchTimeout}
+	if proxy != nil {
+		// The transport is only used for this request; close its pooled connections
+		// so each proxied fetch doesn't leave an idle connection and goroutines behind
+		transport := &http.Transport{Proxy: http.ProxyURL(proxy.URL)}
+		defer transport.CloseIdleConnections()
+		client.Transport = transport
+	}
+	if len(opts.Cookies) > 0 {
+		// The jar drops cookies for other domains and keeps them off cross-host redirects
+		jar, _ := cookiejar.New(nil)
+		cookies := make([]*http.Cookie, 0, len(opts.Cookies))
+		for _, c := range opts.Cookies {
+			cookies = append(cookies, &http.Cookie{Name: c.Name, Value: c.Value, Domain: c.Domain})
+		}
+		jar.SetCookies(req.URL, cookies)
+		client.Jar = jar
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fetcher.Content{}, nil, proxyResponse{}, fmt.Errorf("failed to fetch document: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, constants.MaxDocumentSize+1))
+	if err != nil {
+		return fetcher.Content{}, nil, proxyResponse{}, fmt.Errorf("failed to read document: %w", err)
+	}
+
+	content := fetcher.Content{
+		URL:         resp.Request.URL.String(),
+		HTML:        string(body),
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		FetchedAt:   time.Now(),
+	}
+	return content, body, proxyResponse{StatusCode: resp.StatusCode, Headers: resp.Header, Body: body}, nil
+}
+
+// convertDocument replaces the raw document bytes in content with rendered HTML.
+func convertDocument(content fetcher.Content, format documents.Format, body []byte, logger *slog.Logger) (fetcher.Content, error) {
+	doc, err := documents.Parse(format, body)
+	if err != nil {
+		return fetcher.Content{}, err
+	}
+
+	logger.Debug("converted document for extraction",
+		"url", content.URL,
+		"format", format,
+		"pages", len(doc.Pages),
+		"size_bytes", len(body),
+	)
+
+	content.HTML = doc.HTML()
+	content.Text = doc.Text()
+	content.Title = doc.Title
+	content.ContentType = format.MIMEType()
+	content.Links = nil
+	return content, nil
+}
+
+// Type returns the fetcher type identifier.
+func (f *DocumentFetcher) Type() string {
+	return "document+" + f.Fetcher.Type()
+}
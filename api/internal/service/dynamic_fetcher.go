@@ -17,7 +17,17 @@ type DynamicFetcher struct {
 	userID     string
 	tier       string
 	jobID      string
+	actions    []captcha.Action
+	network    *captcha.NetworkCapture
+	onNetwork  func([]captcha.NetworkResponse)
+	resources  *captcha.ResourcePolicy
+	onBlocked  func(int)
+	onSolver   func(float64)
+	proxies    *proxyRouter
 	logger     *slog.Logger
+
+	screenshots   *captcha.ScreenshotCapture
+	onScreenshots func([]captcha.Screenshot)
 }
 
 // DynamicFetcherConfig holds configuration for creating a DynamicFetcher.
@@ -26,7 +36,17 @@ type DynamicFetcherConfig struct {
 	UserID     string
 	Tier       string
 	JobID      string
+	Actions    []captcha.Action                // Browser actions run on each page before capture
+	Network    *captcha.NetworkCapture         // XHR/fetch responses to record on each page
+	OnNetwork  func([]captcha.NetworkResponse) // Receives each page's recorded responses
+	Resources  *captcha.ResourcePolicy         // Page resources to block while rendering
+	OnBlocked  func(int)                       // Receives each page's blocked request count
+	OnSolver   func(float64)                   // Receives each page's paid CAPTCHA solver cost
+	Proxies    *proxyRouter                    // Render through proxies, rotating away from blocked pages
 	Logger     *slog.Logger
+
+	Screenshots   *captcha.ScreenshotCapture // Full-page screenshots to take of each page
+	OnScreenshots func([]captcha.Screenshot) // Receives each page's screenshot segments
 }
 
 // NewDynamicFetcher creates a new DynamicFetcher that uses browser rendering.
@@ -36,7 +56,17 @@ func NewDynamicFetcher(cfg DynamicFetcherConfig) *DynamicFetcher {
 		userID:     cfg.UserID,
 		tier:       cfg.Tier,
 		jobID:      cfg.JobID,
+		actions:    cfg.Actions,
+		network:    cfg.Network,
+		onNetwork:  cfg.OnNetwork,
+		resources:  cfg.Resources,
+		onBlocked:  cfg.OnBlocked,
+		onSolver:   cfg.OnSolver,
+		proxies:    cfg.Proxies,
 		logger:     cfg.Logger,
+
+		screenshots:   cfg.Screenshots,
+		onScreenshots: cfg.OnScreenshots,
 	}
 }
 
@@ -65,15 +95,21 @@ func (f *DynamicFetcher) Fetch(ctx context.Context, url string, opts fetcher.Opt
 		"user_id", f.userID,
 		"job_id", f.jobID,
 		"timeout_ms", timeoutMs,
+		"actions", len(f.actions),
 	)
 
 	// Call captcha service
-	result, err := f.captchaSvc.FetchDynamicContent(ctx, f.userID, f.tier, CaptchaSolveInput{
+	result, err := fetchDynamicContent(ctx, f.captchaSvc, f.userID, f.tier, CaptchaSolveInput{
 		URL:        url,
 		MaxTimeout: timeoutMs,
 		Cookies:    cookies,
+		Actions:    f.actions,
+		Network:    f.network,
+		Resources:  f.resources,
 		JobID:      f.jobID,
-	})
+
+		Screenshots: f.screenshots,
+	}, f.proxies)
 	if err != nil {
 		f.logger.Error("browser rendering failed",
 			"url", url,
@@ -101,9 +137,26 @@ func (f *DynamicFetcher) Fetch(ctx context.Context, url string, opts fetcher.Opt
 		"job_id", f.jobID,
 		"challenge_type", result.ChallengeType,
 		"solved", result.Solved,
+		"network_responses", len(result.Solution.Network),
+		"screenshots", len(result.Solution.Screenshots),
+		"blocked_requests", result.BlockedRequests,
+		"solver_cost_usd", result.SolverCostUSD,
 		"duration_ms", time.Since(startTime).Milliseconds(),
 	)
 
+	if f.onNetwork != nil {
+		f.onNetwork(result.Solution.Network)
+	}
+	if f.onScreenshots != nil {
+		f.onScreenshots(result.Solution.Screenshots)
+	}
+	if f.onBlocked != nil {
+		f.onBlocked(result.BlockedRequests)
+	}
+	if f.onSolver != nil && result.SolverCostUSD > 0 {
+		f.onSolver(result.SolverCostUSD)
+	}
+
 	// Extract links from the response if available
 	var links []string
 	// Note: The captcha service doesn't currently return links, but we can parse them from HTML if needed
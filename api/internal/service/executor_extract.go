@@ -28,7 +28,7 @@ func NewExtractExecutor(extractionSvc *ExtractionService, input ExtractInput, ec
 		input:         input,
 		ectx:          ectx,
 		isByok:        ectx != nil && ectx.IsBYOK,
-		url:           input.URL,
+		url:           input.TargetURL(),
 		schema:        input.Schema,
 	}
 }
@@ -72,6 +72,9 @@ func (e *ExtractExecutor) Execute(ctx context.Context) (*JobExecutionResult, err
 			RawContent:     result.RawContent,
 			RawLLMResponse: result.RawLLMResponse,
 			Schema:         schemaStr,
+			Hints:          result.Hints,
+			HAR:            BuildHAR(result.URL, result.FetchedAt, result.Network),
+			Screenshots:    result.Screenshots,
 			Provider:       result.Metadata.Provider,
 			Model:          result.Metadata.Model,
 			DurationMs:     time.Since(startTime).Milliseconds(),
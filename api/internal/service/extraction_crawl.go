@@ -5,60 +5,73 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 	"github.com/jmylchreest/refyne-api/internal/llm"
+	"github.com/jmylchreest/refyne-api/internal/models"
 )
 
 // CrawlInput represents crawl input.
 type CrawlInput struct {
-	JobID        string            `json:"job_id,omitempty"`        // Job ID for logging/tracking
-	URL          string            `json:"url"`
-	SeedURLs     []string          `json:"seed_urls,omitempty"`     // Additional seed URLs (from sitemap discovery)
-	Schema       json.RawMessage   `json:"schema"`
-	Options      CrawlOptions      `json:"options"`
-	LLMConfigs   []*LLMConfigInput `json:"llm_configs,omitempty"`   // Pre-resolved LLM config chain
-	Tier         string            `json:"tier,omitempty"`          // User's subscription tier at job creation time
-	IsBYOK       bool              `json:"is_byok,omitempty"`       // Whether using user's own API keys
-	CleanerChain []CleanerConfig   `json:"cleaner_chain,omitempty"` // Content cleaner chain
+	JobID         string               `json:"job_id,omitempty"` // Job ID for logging/tracking
+	URL           string               `json:"url"`
+	SeedURLs      []string             `json:"seed_urls,omitempty"` // Additional seed URLs (from sitemap discovery)
+	Schema        json.RawMessage      `json:"schema"`
+	Options       CrawlOptions         `json:"options"`
+	LLMConfigs    []*LLMConfigInput    `json:"llm_configs,omitempty"`   // Pre-resolved LLM config chain
+	Tier          string               `json:"tier,omitempty"`          // User's subscription tier at job creation time
+	IsBYOK        bool                 `json:"is_byok,omitempty"`       // Whether using user's own API keys
+	CleanerChain  []CleanerConfig      `json:"cleaner_chain,omitempty"` // Content cleaner chain
+	Preprocessors []PreprocessorConfig `json:"preprocessors,omitempty"` // Preprocessor chain for prompt hints
 }
 
 // Note: CrawlOptions is defined in job_service.go to avoid duplication
 
 // PageResult represents an individual page result from a crawl.
 type PageResult struct {
-	URL               string  `json:"url"`
-	ParentURL         *string `json:"parent_url,omitempty"` // URL that linked to this page (nil for seed)
-	Depth             int     `json:"depth"`                // Distance from seed URL (0 for seed)
-	Data              any     `json:"data,omitempty"`
-	Error             string  `json:"error,omitempty"`           // User-visible error (sanitized)
-	ErrorDetails      string  `json:"error_details,omitempty"`   // Full error (admin/BYOK only)
-	ErrorCategory     string  `json:"error_category,omitempty"`  // Error classification
-	LLMProvider       string  `json:"llm_provider,omitempty"`    // Provider used
-	LLMModel          string  `json:"llm_model,omitempty"`       // Model used
-	GenerationID      string  `json:"generation_id,omitempty"`   // Provider generation ID for cost tracking
-	IsBYOK            bool    `json:"is_byok"`                   // True if user's own key
-	RetryCount        int     `json:"retry_count"`               // Number of retries
-	TokenUsageInput   int     `json:"token_usage_input"`
-	TokenUsageOutput  int     `json:"token_usage_output"`
-	FetchDurationMs   int     `json:"fetch_duration_ms,omitempty"`
-	ExtractDurationMs int     `json:"extract_duration_ms,omitempty"`
-	RawContent        string  `json:"-"` // Raw page content (not serialized, for debug capture only)
-	RawLLMResponse    string  `json:"-"` // Raw LLM output (not serialized, for debug capture only)
+	URL               string            `json:"url"`
+	ParentURL         *string           `json:"parent_url,omitempty"` // URL that linked to this page (nil for seed)
+	Depth             int               `json:"depth"`                // Distance from seed URL (0 for seed)
+	Data              any               `json:"data,omitempty"`
+	Error             string            `json:"error,omitempty"`          // User-visible error (sanitized)
+	ErrorDetails      string            `json:"error_details,omitempty"`  // Full error (admin/BYOK only)
+	ErrorCategory     string            `json:"error_category,omitempty"` // Error classification
+	LLMProvider       string            `json:"llm_provider,omitempty"`   // Provider used
+	LLMModel          string            `json:"llm_model,omitempty"`      // Model used
+	GenerationID      string            `json:"generation_id,omitempty"`  // Provider generation ID for cost tracking
+	IsBYOK            bool              `json:"is_byok"`                  // True if user's own key
+	RetryCount        int               `json:"retry_count"`              // Number of retries
+	TokenUsageInput   int               `json:"token_usage_input"`
+	TokenUsageOutput  int               `json:"token_usage_output"`
+	FetchDurationMs   int               `json:"fetch_duration_ms,omitempty"`
+	ExtractDurationMs int               `json:"extract_duration_ms,omitempty"`
+	RawContent        string            `json:"-"` // Raw page content (not serialized, for debug capture only)
+	RawLLMResponse    string            `json:"-"` // Raw LLM output (not serialized, for debug capture only)
+	Hints             map[string]string `json:"-"` // Preprocessing hints applied (not serialized, for debug capture only)
+
+	Network     []captcha.NetworkResponse `json:"-"` // XHR/fetch responses captured while rendering (for debug capture only)
+	Screenshots []captcha.Screenshot      `json:"-"` // Screenshots sent to the model (for debug capture only)
+
+	StructuredData *StructuredDataMeta `json:"structured_data,omitempty"` // Fields filled from embedded structured data
+	Document       *DocumentMeta       `json:"document,omitempty"`        // Document input details (PDF, DOCX, XLSX, CSV)
+	Visual         *VisualMeta         `json:"visual,omitempty"`          // Screenshot input details (visual/hybrid modes)
 }
 
 // CrawlResult represents the result of a crawl operation.
 type CrawlResult struct {
-	Results           []any        `json:"results"`      // Aggregated data (backward compat)
-	PageResults       []PageResult `json:"page_results"` // Individual page results for SSE streaming
-	PageCount         int          `json:"page_count"`
-	TotalTokensInput  int          `json:"total_tokens_input"`
-	TotalTokensOutput int          `json:"total_tokens_output"`
-	TotalCostUSD      float64      `json:"total_cost_usd"`     // Actual USD cost charged to user
-	TotalLLMCostUSD   float64      `json:"total_llm_cost_usd"` // Actual LLM provider cost
-	LLMProvider       string       `json:"llm_provider"`       // LLM provider used
-	LLMModel          string       `json:"llm_model"`          // LLM model used
-	StoppedEarly      bool         `json:"stopped_early"`      // True if crawl terminated before completion
-	StopReason        string       `json:"stop_reason"`        // Reason for early stop (e.g., "insufficient_balance")
+	Results            []any        `json:"results"`      // Aggregated data (backward compat)
+	PageResults        []PageResult `json:"page_results"` // Individual page results for SSE streaming
+	PageCount          int          `json:"page_count"`
+	TotalTokensInput   int          `json:"total_tokens_input"`
+	TotalTokensOutput  int          `json:"total_tokens_output"`
+	TotalCostUSD       float64      `json:"total_cost_usd"`        // Actual USD cost charged to user
+	TotalLLMCostUSD    float64      `json:"total_llm_cost_usd"`    // Actual LLM provider cost
+	TotalSolverCostUSD float64      `json:"total_solver_cost_usd"` // Paid CAPTCHA solves, included in TotalCostUSD
+	LLMProvider        string       `json:"llm_provider"`          // LLM provider used
+	LLMModel           string       `json:"llm_model"`             // LLM model used
+	StoppedEarly       bool         `json:"stopped_early"`         // True if crawl terminated before completion
+	StopReason         string       `json:"stop_reason"`           // Reason for early stop (e.g., "insufficient_balance")
 }
 
 // CrawlResultCallback is called for each page result during a crawl.
@@ -102,12 +115,25 @@ func (s *ExtractionService) Crawl(ctx context.Context, userID string, input Craw
 	inputFormat, sch, err := DetectInputFormat(input.Schema)
 	if inputFormat == InputFormatPrompt {
 		// Use prompt-based crawling
-		return s.crawlWithPrompt(ctx, userID, input, nil)
+		return s.crawlWithPrompt(ctx, userID, input, nil, nil)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("invalid schema: %w", err)
 	}
 
+	// Screenshot input modes need a vision-capable model
+	llmConfigs, err := s.visionLLMConfigs(ctx, input.LLMConfigs, input.Options.InputMode)
+	if err != nil {
+		return nil, err
+	}
+	llmCfg = llmConfigs[0]
+
+	// Stored login shared by every page of the crawl
+	auth, err := s.authProfileSvc.session(ctx, userID, input.Tier, input.Options.AuthProfileID, input.Options.ContentDynamicAllowed)
+	if err != nil {
+		return nil, err
+	}
+
 	// Enrich cleaner chain with crawl selectors as keep selectors
 	// This ensures elements matching FollowSelector/NextSelector are preserved during cleaning
 	enrichedCleanerChain := EnrichCleanerChainWithCrawlSelectors(
@@ -150,6 +176,7 @@ func (s *ExtractionService) Crawl(ctx context.Context, userID string, input Craw
 	} else if input.Options.FollowSelector != "" || input.Options.FollowPattern != "" || input.Options.NextSelector != "" {
 		// Need to discover URLs - use URLDiscoverer
 		discoverer := NewURLDiscoverer(s.logger)
+		cookies, _ := auth.current()
 		discovered, err := discoverer.Discover(ctx, seedURLs, URLDiscoveryOptions{
 			FollowSelector: input.Options.FollowSelector,
 			FollowPattern:  input.Options.FollowPattern,
@@ -158,6 +185,7 @@ func (s *ExtractionService) Crawl(ctx context.Context, userID string, input Craw
 			MaxURLs:        input.Options.MaxURLs,
 			SameDomainOnly: input.Options.SameDomainOnly,
 			NextSelector:   input.Options.NextSelector,
+			Cookies:        httpCookies(cookies),
 		})
 		if err != nil {
 			return nil, fmt.Errorf("URL discovery failed: %w", err)
@@ -183,6 +211,13 @@ func (s *ExtractionService) Crawl(ctx context.Context, userID string, input Craw
 	extractor := NewSchemaPageExtractor(s, sch, SchemaExtractorOptions{
 		LLMConfig:             llmCfg,
 		CleanerChain:          enrichedCleanerChain,
+		Preprocessors:         input.Preprocessors,
+		StructuredData:        input.Options.StructuredData,
+		Actions:               input.Options.Actions,
+		Network:               input.Options.CaptureNetwork,
+		InputMode:             input.Options.InputMode,
+		FetchOptions:          input.Options.FetchOptions,
+		Auth:                  auth,
 		ContentDynamicAllowed: input.Options.ContentDynamicAllowed,
 		UserID:                userID,
 		Tier:                  input.Tier,
@@ -194,6 +229,7 @@ func (s *ExtractionService) Crawl(ctx context.Context, userID string, input Craw
 		pageResults       []PageResult
 		totalTokensInput  int
 		totalTokensOutput int
+		totalSolverCost   float64
 		pageCount         int
 		lastError         error
 		cancelled         bool
@@ -265,10 +301,17 @@ func (s *ExtractionService) Crawl(ctx context.Context, userID string, input Craw
 			pageResult.GenerationID = extractResult.GenerationID
 			pageResult.RetryCount = extractResult.RetryCount
 			pageResult.RawContent = extractResult.RawContent
+			pageResult.StructuredData = extractResult.StructuredData
+			pageResult.Document = extractResult.Document
+			pageResult.Hints = extractResult.Hints
+			pageResult.Network = extractResult.Network
+			pageResult.Visual = extractResult.Visual
+			pageResult.Screenshots = extractResult.Screenshots
 
 			data = append(data, extractResult.Data)
 			totalTokensInput += extractResult.TokensInput
 			totalTokensOutput += extractResult.TokensOutput
+			totalSolverCost += extractResult.SolverCostUSD
 			pageCount++
 
 			// Calculate costs for logging
@@ -330,20 +373,22 @@ func (s *ExtractionService) Crawl(ctx context.Context, userID string, input Craw
 		"total_output_tokens", totalTokensOutput,
 		"llm_cost_usd", totalCosts.LLMCostUSD,
 		"user_cost_usd", totalCosts.UserCostUSD,
+		"solver_cost_usd", totalSolverCost,
 	)
 
 	return &CrawlResult{
-		Results:           data,
-		PageResults:       pageResults,
-		PageCount:         pageCount,
-		TotalTokensInput:  totalTokensInput,
-		TotalTokensOutput: totalTokensOutput,
-		TotalCostUSD:      totalCosts.UserCostUSD,
-		TotalLLMCostUSD:   totalCosts.LLMCostUSD,
-		LLMProvider:       llmCfg.Provider,
-		LLMModel:          llmCfg.Model,
-		StoppedEarly:      false, // Simple Crawl doesn't have mid-crawl balance check
-		StopReason:        "",
+		Results:            data,
+		PageResults:        pageResults,
+		PageCount:          pageCount,
+		TotalTokensInput:   totalTokensInput,
+		TotalTokensOutput:  totalTokensOutput,
+		TotalCostUSD:       totalCosts.UserCostUSD + totalSolverCost,
+		TotalLLMCostUSD:    totalCosts.LLMCostUSD,
+		TotalSolverCostUSD: totalSolverCost,
+		LLMProvider:        llmCfg.Provider,
+		LLMModel:           llmCfg.Model,
+		StoppedEarly:       false, // Simple Crawl doesn't have mid-crawl balance check
+		StopReason:         "",
 	}, nil
 }
 
@@ -360,6 +405,19 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 	llmConfigs := input.LLMConfigs // Full fallback chain for per-page retries
 	isBYOK := input.IsBYOK
 
+	// Spend caps apply to all users, including BYOK. Spend is reserved for each page
+	// before it is extracted and settled once it has been (see settleCrawlPageSpend).
+	var spendReservation *SpendReservation
+	if s.billing != nil {
+		estimatedCost := s.billing.EstimateCost(1, llmConfigs[0].Model, llmConfigs[0].Provider)
+		reservation, err := s.billing.ReserveSpend(ctx, userID, input.Options.APIKeyID, estimatedCost)
+		if err != nil {
+			return nil, err
+		}
+		spendReservation = reservation
+		defer func() { s.billing.ReleaseSpend(context.WithoutCancel(ctx), spendReservation) }()
+	}
+
 	// Get available balance for non-BYOK users (for mid-crawl balance enforcement)
 	// Skip balance check if user has skip_credit_check feature enabled
 	var availableBalance float64
@@ -387,12 +445,23 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 	inputFormat, sch, err := DetectInputFormat(input.Schema)
 	if inputFormat == InputFormatPrompt {
 		// Use prompt-based crawling
-		return s.crawlWithPrompt(ctx, userID, input, &callbacks)
+		return s.crawlWithPrompt(ctx, userID, input, &callbacks, spendReservation)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("invalid schema: %w", err)
 	}
 
+	// Screenshot input modes need a vision-capable model
+	if llmConfigs, err = s.visionLLMConfigs(ctx, llmConfigs, input.Options.InputMode); err != nil {
+		return nil, err
+	}
+
+	// Stored login shared by every page of the crawl
+	auth, err := s.authProfileSvc.session(ctx, userID, input.Tier, input.Options.AuthProfileID, input.Options.ContentDynamicAllowed)
+	if err != nil {
+		return nil, err
+	}
+
 	// Enrich cleaner chain with crawl selectors as keep selectors
 	// This ensures elements matching FollowSelector/NextSelector are preserved during cleaning
 	enrichedCleanerChain := EnrichCleanerChainWithCrawlSelectors(
@@ -436,6 +505,7 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 	} else if input.Options.FollowSelector != "" || input.Options.FollowPattern != "" || input.Options.NextSelector != "" {
 		// Need to discover URLs - use URLDiscoverer
 		discoverer := NewURLDiscoverer(s.logger)
+		cookies, _ := auth.current()
 		discovered, err := discoverer.Discover(ctx, seedURLs, URLDiscoveryOptions{
 			FollowSelector: input.Options.FollowSelector,
 			FollowPattern:  input.Options.FollowPattern,
@@ -444,6 +514,7 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 			MaxURLs:        input.Options.MaxURLs,
 			SameDomainOnly: input.Options.SameDomainOnly,
 			NextSelector:   input.Options.NextSelector,
+			Cookies:        httpCookies(cookies),
 		})
 		if err != nil {
 			return nil, fmt.Errorf("URL discovery failed: %w", err)
@@ -475,6 +546,7 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 		pageResults       []PageResult
 		totalTokensInput  int
 		totalTokensOutput int
+		totalSolverCost   float64
 		pageCount         int
 		cumulativeCostUSD float64
 		stoppedEarly      bool
@@ -501,12 +573,15 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 			parentURL = &discoveredURL.ParentURL
 		}
 
-		// Try each LLM config in the fallback chain for this page
+		// Try each LLM config in the fallback chain for this page. The chain is rebuilt
+		// per page so models whose circuit opened on earlier pages are tried last.
 		var pageResult PageResult
 		var pageSuccess bool
 
-		for cfgIdx, llmCfg := range llmConfigs {
+		llmChain := NewLLMConfigChain(llmConfigs, isBYOK).WithHealth(s.resolver.HealthTracker())
+		for llmCfg := llmChain.Next(); llmCfg != nil; llmCfg = llmChain.Next() {
 			lastUsedConfig = llmCfg
+			attempt, chainLen := llmChain.Position()
 
 			pageResult = PageResult{
 				URL:         discoveredURL.URL,
@@ -521,6 +596,13 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 			extractor := NewSchemaPageExtractor(s, sch, SchemaExtractorOptions{
 				LLMConfig:             llmCfg,
 				CleanerChain:          enrichedCleanerChain,
+				Preprocessors:         input.Preprocessors,
+				StructuredData:        input.Options.StructuredData,
+				Actions:               input.Options.Actions,
+				Network:               input.Options.CaptureNetwork,
+				InputMode:             input.Options.InputMode,
+				FetchOptions:          input.Options.FetchOptions,
+				Auth:                  auth,
 				ContentDynamicAllowed: input.Options.ContentDynamicAllowed,
 				UserID:                userID,
 				Tier:                  input.Tier,
@@ -528,6 +610,7 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 			})
 
 			// Extract using SchemaPageExtractor (handles dynamic retry internally)
+			llmChain.BeginCall()
 			extractResult, err := extractor.Extract(ctx, discoveredURL.URL)
 
 			if err != nil || (extractResult != nil && extractResult.Error != nil) {
@@ -539,6 +622,7 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 				lastError = errToUse
 
 				errInfo := llm.WrapError(errToUse, llmCfg.Provider, llmCfg.Model, isBYOK)
+				llmChain.RecordError(errInfo)
 				pageResult.Error = errInfo.UserMessage
 				pageResult.ErrorCategory = errInfo.Category
 				if isBYOK {
@@ -551,12 +635,13 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 				}
 
 				// Check if we should try the next model in the chain
-				if errInfo.ShouldFallback && cfgIdx < len(llmConfigs)-1 {
+				if errInfo.ShouldFallback && attempt < chainLen {
 					s.logger.Info("crawl page error, trying fallback model",
 						"job_id", input.JobID,
 						"url", discoveredURL.URL,
 						"failed_model", llmCfg.Model,
-						"next_model", llmConfigs[cfgIdx+1].Model,
+						"attempt", attempt,
+						"of", chainLen,
 						"error_category", errInfo.Category,
 					)
 					continue // Try next model in chain
@@ -569,13 +654,14 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 					"url", discoveredURL.URL,
 					"error", errToUse,
 					"model", llmCfg.Model,
-					"fallback_attempted", cfgIdx > 0,
+					"fallback_attempted", attempt > 1,
 					"used_dynamic", extractResult != nil && extractResult.UsedDynamicMode,
 				)
 				break // Stop trying for this page
 			}
 
 			// Success!
+			llmChain.RecordSuccess(time.Duration(extractResult.ExtractDurationMs) * time.Millisecond)
 			pageSuccess = true
 			pageResult.URL = extractResult.URL
 			pageResult.Data = extractResult.Data
@@ -586,10 +672,17 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 			pageResult.GenerationID = extractResult.GenerationID
 			pageResult.RetryCount = extractResult.RetryCount
 			pageResult.RawContent = extractResult.RawContent
+			pageResult.StructuredData = extractResult.StructuredData
+			pageResult.Document = extractResult.Document
+			pageResult.Hints = extractResult.Hints
+			pageResult.Network = extractResult.Network
+			pageResult.Visual = extractResult.Visual
+			pageResult.Screenshots = extractResult.Screenshots
 
 			data = append(data, extractResult.Data)
 			totalTokensInput += extractResult.TokensInput
 			totalTokensOutput += extractResult.TokensOutput
+			totalSolverCost += extractResult.SolverCostUSD
 			pageCount++
 
 			// Calculate costs
@@ -604,7 +697,7 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 					GenerationID: extractResult.GenerationID,
 					APIKey:       llmCfg.APIKey,
 				})
-				cumulativeCostUSD += pageCosts.UserCostUSD
+				cumulativeCostUSD += pageCosts.UserCostUSD + extractResult.SolverCostUSD
 
 				// Check balance for next page
 				if checkBalance && pageCosts.UserCostUSD > 0 {
@@ -623,6 +716,13 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 						stopReason = "insufficient_balance"
 					}
 				}
+
+				var capReached bool
+				spendReservation, capReached = s.settleCrawlPageSpend(ctx, userID, input, spendReservation, pageCosts, extractResult.SolverCostUSD, extractResult.TokensInput, extractResult.TokensOutput)
+				if capReached && !stoppedEarly {
+					stoppedEarly = true
+					stopReason = "spend_cap_exceeded"
+				}
 			}
 
 			s.logger.Info("extracted page",
@@ -631,7 +731,7 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 				"input_tokens", extractResult.TokensInput,
 				"output_tokens", extractResult.TokensOutput,
 				"model", llmCfg.Model,
-				"fallback_used", cfgIdx > 0,
+				"fallback_used", llmCfg != llmConfigs[0],
 				"used_dynamic", extractResult.UsedDynamicMode,
 				"retry_count", extractResult.RetryCount,
 			)
@@ -684,38 +784,52 @@ func (s *ExtractionService) CrawlWithCallback(ctx context.Context, userID string
 		"total_output_tokens", totalTokensOutput,
 		"llm_cost_usd", totalCosts.LLMCostUSD,
 		"user_cost_usd", totalCosts.UserCostUSD,
+		"solver_cost_usd", totalSolverCost,
 		"stopped_early", stoppedEarly,
 		"stop_reason", stopReason,
 	)
 
-	return &CrawlResult{
-		Results:           data,
-		PageResults:       pageResults,
-		PageCount:         pageCount,
-		TotalTokensInput:  totalTokensInput,
-		TotalTokensOutput: totalTokensOutput,
-		TotalCostUSD:      totalCosts.UserCostUSD,
-		TotalLLMCostUSD:   totalCosts.LLMCostUSD,
-		LLMProvider:       primaryConfig.Provider,
-		LLMModel:          primaryConfig.Model,
-		StoppedEarly:      stoppedEarly,
-		StopReason:        stopReason,
-	}, nil
+	result := &CrawlResult{
+		Results:            data,
+		PageResults:        pageResults,
+		PageCount:          pageCount,
+		TotalTokensInput:   totalTokensInput,
+		TotalTokensOutput:  totalTokensOutput,
+		TotalCostUSD:       totalCosts.UserCostUSD + totalSolverCost,
+		TotalLLMCostUSD:    totalCosts.LLMCostUSD,
+		TotalSolverCostUSD: totalSolverCost,
+		LLMProvider:        primaryConfig.Provider,
+		LLMModel:           primaryConfig.Model,
+		StoppedEarly:       stoppedEarly,
+		StopReason:         stopReason,
+	}
+	s.recordCrawlUsage(ctx, userID, input, result, pageSuccessCount(pageResults))
+	return result, nil
 }
 
 // crawlWithPrompt performs a multi-page extraction using a freeform prompt instead of a schema.
 // This iterates through URLs (from seeds, sitemap, or single URL) and extracts each page using the prompt.
 // Uses PromptPageExtractor which handles dynamic retry for bot protection and insufficient content.
-func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string, input CrawlInput, callbacks *CrawlCallbacks) (*CrawlResult, error) {
+// spendReservation is the spend reserved for the first page.
+func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string, input CrawlInput, callbacks *CrawlCallbacks, spendReservation *SpendReservation) (*CrawlResult, error) {
 	if len(input.LLMConfigs) == 0 {
 		return nil, fmt.Errorf("no LLM configs provided")
 	}
+	if s.billing != nil {
+		defer func() { s.billing.ReleaseSpend(context.WithoutCancel(ctx), spendReservation) }()
+	}
 	llmCfg := input.LLMConfigs[0]
 	isBYOK := input.IsBYOK
 
 	// Extract prompt text from Schema field
 	promptText := strings.TrimSpace(string(input.Schema))
 
+	// Stored login shared by every page of the crawl
+	auth, err := s.authProfileSvc.session(ctx, userID, input.Tier, input.Options.AuthProfileID, input.Options.ContentDynamicAllowed)
+	if err != nil {
+		return nil, err
+	}
+
 	s.logger.Info("prompt-based crawl starting",
 		"job_id", input.JobID,
 		"user_id", userID,
@@ -741,6 +855,7 @@ func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string,
 	var pageResults []PageResult
 	var allData []any
 	var totalTokensInput, totalTokensOutput int
+	var totalSolverCost float64
 	var cumulativeCostUSD float64
 	var stoppedEarly bool
 	var stopReason string
@@ -769,7 +884,12 @@ func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string,
 		PromptText:            promptText,
 		LLMConfig:             llmCfg,
 		CleanerChain:          input.CleanerChain,
+		Preprocessors:         input.Preprocessors,
 		IsBYOK:                isBYOK,
+		Actions:               input.Options.Actions,
+		Network:               input.Options.CaptureNetwork,
+		FetchOptions:          input.Options.FetchOptions,
+		Auth:                  auth,
 		ContentDynamicAllowed: input.Options.ContentDynamicAllowed,
 		UserID:                userID,
 		Tier:                  input.Tier,
@@ -834,9 +954,12 @@ func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string,
 			pageResult.ExtractDurationMs = extractResult.ExtractDurationMs
 			pageResult.RetryCount = extractResult.RetryCount
 			pageResult.RawContent = extractResult.RawContent
+			pageResult.Hints = extractResult.Hints
+			pageResult.Network = extractResult.Network
 
 			totalTokensInput += extractResult.TokensInput
 			totalTokensOutput += extractResult.TokensOutput
+			totalSolverCost += extractResult.SolverCostUSD
 			allData = append(allData, extractResult.Data)
 
 			// Calculate costs
@@ -849,7 +972,7 @@ func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string,
 					Tier:         input.Tier,
 					IsBYOK:       isBYOK,
 				})
-				cumulativeCostUSD += pageCosts.UserCostUSD
+				cumulativeCostUSD += pageCosts.UserCostUSD + extractResult.SolverCostUSD
 
 				// Check balance for next page
 				if checkBalance && pageCosts.UserCostUSD > 0 {
@@ -864,6 +987,13 @@ func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string,
 						stopReason = "insufficient_balance"
 					}
 				}
+
+				var capReached bool
+				spendReservation, capReached = s.settleCrawlPageSpend(ctx, userID, input, spendReservation, pageCosts, extractResult.SolverCostUSD, extractResult.TokensInput, extractResult.TokensOutput)
+				if capReached && !stoppedEarly {
+					stoppedEarly = true
+					stopReason = "spend_cap_exceeded"
+				}
 			}
 
 			s.logger.Info("extracted page",
@@ -910,21 +1040,113 @@ func (s *ExtractionService) crawlWithPrompt(ctx context.Context, userID string,
 		"total_output_tokens", totalTokensOutput,
 		"llm_cost_usd", totalCosts.LLMCostUSD,
 		"user_cost_usd", totalCosts.UserCostUSD,
+		"solver_cost_usd", totalSolverCost,
 		"stopped_early", stoppedEarly,
 		"stop_reason", stopReason,
 	)
 
-	return &CrawlResult{
-		Results:           allData,
-		PageResults:       pageResults,
-		PageCount:         len(pageResults),
-		TotalTokensInput:  totalTokensInput,
-		TotalTokensOutput: totalTokensOutput,
-		TotalCostUSD:      totalCosts.UserCostUSD,
-		TotalLLMCostUSD:   totalCosts.LLMCostUSD,
-		LLMProvider:       llmCfg.Provider,
-		LLMModel:          llmCfg.Model,
-		StoppedEarly:      stoppedEarly,
-		StopReason:        stopReason,
-	}, nil
+	result := &CrawlResult{
+		Results:            allData,
+		PageResults:        pageResults,
+		PageCount:          len(pageResults),
+		TotalTokensInput:   totalTokensInput,
+		TotalTokensOutput:  totalTokensOutput,
+		TotalCostUSD:       totalCosts.UserCostUSD + totalSolverCost,
+		TotalLLMCostUSD:    totalCosts.LLMCostUSD,
+		TotalSolverCostUSD: totalSolverCost,
+		LLMProvider:        llmCfg.Provider,
+		LLMModel:           llmCfg.Model,
+		StoppedEarly:       stoppedEarly,
+		StopReason:         stopReason,
+	}
+	s.recordCrawlUsage(ctx, userID, input, result, pageSuccessCount(pageResults))
+	return result, nil
+}
+
+// settleCrawlPageSpend settles a crawled page's spend against the reservation made for
+// it, then reserves spend for another page of similar cost. It returns the next page's
+// reservation and whether the caps leave no room for it.
+// The crawl's usage record is only written once it completes, so spend is settled here
+// per page.
+func (s *ExtractionService) settleCrawlPageSpend(ctx context.Context, userID string, input CrawlInput, reservation *SpendReservation, costs CostResult, solverCostUSD float64, tokensInput, tokensOutput int) (*SpendReservation, bool) {
+	spend := models.SpendUsage{
+		CostUSD: costs.UserCostUSD + solverCostUSD,
+		Tokens:  int64(tokensInput + tokensOutput),
+		Pages:   1,
+	}
+	if input.IsBYOK {
+		spend.CostUSD = costs.LLMCostUSD + solverCostUSD
+	}
+	s.billing.SettleSpend(context.WithoutCancel(ctx), userID, input.Options.APIKeyID, reservation, spend)
+
+	next, err := s.billing.ReserveSpend(ctx, userID, input.Options.APIKeyID, spend.CostUSD)
+	if err != nil {
+		s.logger.Warn("spend cap reached, stopping crawl",
+			"job_id", input.JobID,
+			"user_id", userID,
+			"api_key_id", input.Options.APIKeyID,
+			"error", err,
+		)
+		return nil, true
+	}
+	return next, false
+}
+
+// recordCrawlUsage writes a completed crawl's usage record, including how the job's
+// fallback chain was routed. Spend was already recorded per page.
+func (s *ExtractionService) recordCrawlUsage(ctx context.Context, userID string, input CrawlInput, result *CrawlResult, pagesSuccessful int) {
+	if s.billing == nil {
+		return
+	}
+
+	status := "success"
+	if result.StoppedEarly {
+		status = "partial"
+	}
+	byokProvider := ""
+	if input.IsBYOK {
+		byokProvider = result.LLMProvider
+	}
+
+	record := &UsageRecord{
+		UserID:          userID,
+		APIKeyID:        input.Options.APIKeyID,
+		JobID:           input.JobID,
+		JobType:         models.JobTypeCrawl,
+		Status:          status,
+		TotalChargedUSD: result.TotalCostUSD,
+		IsBYOK:          input.IsBYOK,
+		TargetURL:       input.URL,
+		TokensInput:     result.TotalTokensInput,
+		TokensOutput:    result.TotalTokensOutput,
+		LLMCostUSD:      result.TotalLLMCostUSD,
+		SolverCostUSD:   result.TotalSolverCostUSD,
+		LLMProvider:     result.LLMProvider,
+		LLMModel:        result.LLMModel,
+		BYOKProvider:    byokProvider,
+		RoutingPolicy:   string(input.Options.RoutingPolicy),
+		RoutingReason:   input.Options.RoutingReason,
+		PagesAttempted:  result.PageCount,
+		PagesSuccessful: pagesSuccessful,
+		SpendRecorded:   true,
+	}
+	if input.IsBYOK {
+		record.TotalChargedUSD = result.TotalSolverCostUSD // BYOK users only pay for CAPTCHA solves
+	}
+
+	// Use detached context - we want to record usage even if the job was cancelled
+	if err := s.billing.RecordUsage(context.WithoutCancel(ctx), record); err != nil {
+		s.logger.Warn("failed to record crawl usage", "job_id", input.JobID, "error", err)
+	}
+}
+
+// pageSuccessCount returns the number of crawled pages that extracted without error.
+func pageSuccessCount(pages []PageResult) int {
+	count := 0
+	for _, page := range pages {
+		if page.Error == "" {
+			count++
+		}
+	}
+	return count
 }
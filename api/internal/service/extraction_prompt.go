@@ -14,7 +14,9 @@ import (
 // extractWithPrompt performs extraction using natural language instructions instead of a schema.
 // This allows users to describe what they want extracted in plain text.
 // Uses PromptPageExtractor which handles dynamic retry for bot protection and insufficient content.
-func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string, input ExtractInput, ectx *ExtractContext, startTime time.Time) (*ExtractOutput, error) {
+// When content is non-nil it is extracted directly instead of fetching input.URL;
+// otherwise the page is fetched signed in with auth, when set.
+func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string, input ExtractInput, content *SubmittedContent, auth *authSession, ectx *ExtractContext, startTime time.Time) (*ExtractOutput, error) {
 	// Extract prompt text from Schema field (which contains the freeform text)
 	promptText := strings.TrimSpace(string(input.Schema))
 
@@ -26,7 +28,11 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 
 	// Get LLM config chain (same as schema-based extraction)
 	llmChain := s.resolveLLMConfigChain(ctx, userID, input.LLMConfig, ectx.Tier, ectx.BYOKAllowed, ectx.ModelsCustomAllowed, ectx.LLMProvider, ectx.LLMModel, ectx.LLMConfigs)
+	if content != nil {
+		llmChain.RouteForInput((len(content.Body) + len(promptText)) / 3) // ~3 chars per token
+	}
 	ectx.IsBYOK = llmChain.IsBYOK()
+	ectx.Routing = llmChain.Routing()
 
 	if llmChain.IsEmpty() {
 		return nil, llm.NewNoModelsConfiguredError("no models in fallback chain or missing API keys")
@@ -56,6 +62,17 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 		}
 	}
 
+	// Spend caps apply to all users, including BYOK
+	if s.billing != nil && firstCfg != nil {
+		estimatedCost := s.billing.EstimateCost(1, firstCfg.Model, firstCfg.Provider)
+		reservation, err := s.billing.ReserveSpend(ctx, userID, ectx.APIKeyID, estimatedCost)
+		if err != nil {
+			return nil, err
+		}
+		ectx.SpendReservation = reservation
+		defer s.billing.ReleaseSpend(context.WithoutCancel(ctx), reservation)
+	}
+
 	// Use JobID if available, otherwise fall back to SchemaID for tracking
 	jobIDForTracking := ectx.JobID
 	if jobIDForTracking == "" {
@@ -113,7 +130,13 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 			PromptText:            promptText,
 			LLMConfig:             llmCfg,
 			CleanerChain:          input.CleanerChain,
+			Preprocessors:         input.Preprocessors,
 			IsBYOK:                llmChain.IsBYOK(),
+			Content:               content,
+			Actions:               input.Actions,
+			Network:               input.CaptureNetwork,
+			FetchOptions:          input.FetchOptions,
+			Auth:                  auth,
 			ContentDynamicAllowed: ectx.ContentDynamicAllowed,
 			UserID:                userID,
 			Tier:                  ectx.Tier,
@@ -121,10 +144,12 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 		})
 
 		// Perform extraction (dynamic retry happens inside Extract)
+		llmChain.BeginCall()
 		pageResult, err := extractor.Extract(ctx, input.URL)
 
 		if err == nil && pageResult != nil && pageResult.Error == nil {
 			// Success - calculate costs and return
+			llmChain.RecordSuccess(time.Duration(pageResult.ExtractDurationMs) * time.Millisecond)
 
 			// Calculate costs and record usage
 			var costs CostResult
@@ -137,18 +162,26 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 					Tier:         ectx.Tier,
 					IsBYOK:       llmChain.IsBYOK(),
 				})
+				// Paid CAPTCHA solves are passed through at cost
+				costs.UserCostUSD += pageResult.SolverCostUSD
 				_ = s.billing.RecordUsage(ctx, &UsageRecord{
 					UserID:          userID,
+					APIKeyID:        ectx.APIKeyID,
 					JobType:         models.JobTypeExtract,
 					Status:          "completed",
 					TokensInput:     pageResult.TokensInput,
 					TokensOutput:    pageResult.TokensOutput,
 					TotalChargedUSD: costs.UserCostUSD,
 					LLMCostUSD:      costs.LLMCostUSD,
+					SolverCostUSD:   pageResult.SolverCostUSD,
 					IsBYOK:          llmChain.IsBYOK(),
 					TargetURL:       input.URL,
 					LLMProvider:     llmCfg.Provider,
 					LLMModel:        llmCfg.Model,
+					RoutingPolicy:   string(ectx.Routing.Policy),
+					RoutingReason:   ectx.Routing.Reason,
+
+					SpendReservation: ectx.SpendReservation,
 				})
 			}
 
@@ -163,6 +196,9 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 					CostUSD:      costs.UserCostUSD,
 					LLMCostUSD:   costs.LLMCostUSD,
 					IsBYOK:       llmChain.IsBYOK(),
+
+					BlockedRequests: pageResult.BlockedRequests,
+					SolverCostUSD:   pageResult.SolverCostUSD,
 				},
 				Metadata: ExtractMeta{
 					FetchDurationMs:   pageResult.FetchDurationMs,
@@ -172,6 +208,8 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 					BudgetSkips:       budgetSkips,
 				},
 				RawContent: pageResult.RawContent,
+				Hints:      pageResult.Hints,
+				Network:    pageResult.Network,
 			}, nil
 		}
 
@@ -194,6 +232,7 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 		}
 
 		lastLLMErr = llm.WrapError(lastErr, llmCfg.Provider, llmCfg.Model, llmChain.IsBYOK())
+		llmChain.RecordError(lastLLMErr)
 
 		s.logger.Warn("prompt extraction failed",
 			"provider", llmCfg.Provider,
@@ -228,11 +267,12 @@ func (s *ExtractionService) extractWithPrompt(ctx context.Context, userID string
 }
 
 // buildPromptExtractionPrompt constructs the LLM prompt for freeform extraction.
+// hintsSection carries preprocessor guidance (e.g., detected item counts) and may be empty.
 // Uses strings.Builder for efficient memory allocation with large page content.
-func (s *ExtractionService) buildPromptExtractionPrompt(pageContent, userPrompt string) string {
+func (s *ExtractionService) buildPromptExtractionPrompt(pageContent, userPrompt, hintsSection string) string {
 	const templateOverhead = 500 // Approximate size of template text
 	var b strings.Builder
-	b.Grow(len(pageContent) + len(userPrompt) + templateOverhead)
+	b.Grow(len(pageContent) + len(userPrompt) + len(hintsSection) + templateOverhead)
 
 	b.WriteString(`You are a data extraction assistant. Extract structured data from the provided web page content based on the user's instructions.
 
@@ -244,6 +284,10 @@ USER'S EXTRACTION INSTRUCTIONS:
 WEB PAGE CONTENT:
 `)
 	b.WriteString(pageContent)
+	if hintsSection != "" {
+		b.WriteString("\n")
+		b.WriteString(hintsSection)
+	}
 	b.WriteString(`
 
 IMPORTANT INSTRUCTIONS:
@@ -11,9 +11,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jmylchreest/refyne/pkg/fetcher"
 	"github.com/jmylchreest/refyne/pkg/refyne"
 	"github.com/jmylchreest/refyne/pkg/schema"
 
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 	"github.com/jmylchreest/refyne-api/internal/config"
 	"github.com/jmylchreest/refyne-api/internal/constants"
 	"github.com/jmylchreest/refyne-api/internal/crypto"
@@ -61,8 +63,11 @@ type ExtractionService struct {
 	resolver           *LLMConfigResolver
 	logger             *slog.Logger
 	encryptor          *crypto.Encryptor
-	captchaSvc         *CaptchaService        // For dynamic content fetching with browser rendering
-	protectionDetector *protection.Detector   // Detects bot protection signals in responses
+	captchaSvc         *CaptchaService      // For dynamic content fetching with browser rendering
+	protectionDetector *protection.Detector // Detects bot protection signals in responses
+	partialResults     *PartialResultBroker // Receives items parsed from streamed LLM responses
+	proxySvc           *ProxyService        // Selects proxies for fetches that request one
+	authProfileSvc     *AuthProfileService  // Signs fetches in with users' stored logins
 }
 
 // NewExtractionService creates a new extraction service (legacy constructor).
@@ -89,6 +94,22 @@ func (s *ExtractionService) SetCaptchaService(captchaSvc *CaptchaService) {
 	s.captchaSvc = captchaSvc
 }
 
+// SetPartialResults sets the broker that receives array items while the LLM is still
+// writing its response. Responses are only streamed when the job has stream subscribers.
+func (s *ExtractionService) SetPartialResults(broker *PartialResultBroker) {
+	s.partialResults = broker
+}
+
+// SetProxyService sets the proxy service used for fetches that request a proxy.
+func (s *ExtractionService) SetProxyService(proxySvc *ProxyService) {
+	s.proxySvc = proxySvc
+}
+
+// SetAuthProfileService sets the auth profile service used for fetches that reference a stored login.
+func (s *ExtractionService) SetAuthProfileService(authProfileSvc *AuthProfileService) {
+	s.authProfileSvc = authProfileSvc
+}
+
 // getStrictMode determines if a model supports strict JSON schema mode.
 // Delegates to the resolver which uses cached capabilities when available.
 func (s *ExtractionService) getStrictMode(ctx context.Context, provider, model string, chainStrictMode *bool) bool {
@@ -123,13 +144,33 @@ func (s *ExtractionService) getContextLength(ctx context.Context, provider, mode
 	return 0 // Unknown - validation will be skipped
 }
 
+// supportsVision reports whether a config's model accepts image inputs.
+func (s *ExtractionService) supportsVision(ctx context.Context, cfg *LLMConfigInput) bool {
+	if s.resolver == nil {
+		return false
+	}
+	return s.resolver.GetRegistry().SupportsVision(ctx, cfg.Provider, cfg.Model)
+}
+
 // ExtractInput represents extraction input.
+// Either URL is fetched, or Content is extracted directly without fetching.
 type ExtractInput struct {
-	URL          string           `json:"url"`
-	Schema       json.RawMessage  `json:"schema"`                  // Can be structured schema (YAML/JSON) or freeform prompt - auto-detected
-	FetchMode    string           `json:"fetch_mode,omitempty"`
-	LLMConfig    *LLMConfigInput  `json:"llm_config,omitempty"`
-	CleanerChain []CleanerConfig  `json:"cleaner_chain,omitempty"` // Content cleaner chain: [{name: "refyne", options: {...}}]
+	URL            string                `json:"url"`
+	Content        string                `json:"content,omitempty"`      // Content to extract from instead of fetching URL
+	ContentType    string                `json:"content_type,omitempty"` // Content format: html (default), markdown or text
+	BaseURL        string                `json:"base_url,omitempty"`     // URL the content came from, for resolving relative links
+	Schema         json.RawMessage       `json:"schema"`                 // Can be structured schema (YAML/JSON) or freeform prompt - auto-detected
+	FetchMode      string                `json:"fetch_mode,omitempty"`
+	LLMConfig      *LLMConfigInput       `json:"llm_config,omitempty"`
+	CleanerChain   []CleanerConfig       `json:"cleaner_chain,omitempty"`   // Content cleaner chain: [{name: "refyne", options: {...}}]
+	Preprocessors  []PreprocessorConfig  `json:"preprocessors,omitempty"`   // Preprocessor chain for prompt hints: [{name: "hint_repeats"}]
+	StructuredData *StructuredDataConfig `json:"structured_data,omitempty"` // Structured data fast path (JSON-LD, microdata, OpenGraph)
+	Actions        []captcha.Action      `json:"actions,omitempty"`         // Browser actions run before capture (implies browser rendering)
+	CaptureNetwork *NetworkCaptureConfig `json:"capture_network,omitempty"` // Record XHR/fetch responses (implies browser rendering)
+	FetchOptions   *FetchOptions         `json:"fetch_options,omitempty"`   // Resource blocking and proxy selection
+	AuthProfileID  string                `json:"auth_profile_id,omitempty"` // Stored login to fetch the page with
+
+	InputMode InputMode `json:"input_mode,omitempty"` // text (default), visual or hybrid - screenshot modes imply browser rendering
 }
 
 // LLMConfigInput represents user-provided LLM configuration.
@@ -141,6 +182,7 @@ type LLMConfigInput struct {
 	MaxTokens     int    `json:"max_tokens,omitempty"`      // Max output tokens for LLM responses
 	ContextLength int    `json:"context_length,omitempty"`  // Total context window size (input + output)
 	StrictMode    bool   `json:"strict_mode,omitempty"`     // Whether to use strict JSON schema mode
+	Weight        int    `json:"-"`                         // Traffic share under the weighted routing policy
 
 	// For Helicone self-hosted proxy mode
 	TargetProvider string `json:"target_provider,omitempty"` // Underlying provider (e.g., "openai", "anthropic")
@@ -159,32 +201,42 @@ const (
 
 // ExtractOutput represents extraction output.
 type ExtractOutput struct {
-	Data           any         `json:"data"`
-	URL            string      `json:"url"`
-	FetchedAt      time.Time   `json:"fetched_at"`
-	Usage          UsageInfo   `json:"usage"`
-	Metadata       ExtractMeta `json:"metadata"`
-	InputFormat    InputFormat `json:"input_format"`    // "schema" or "prompt" - indicates how the input was interpreted
-	RawContent     string      `json:"-"`               // Raw page content (not serialized, for debug capture only)
-	RawLLMResponse string      `json:"-"`               // Raw LLM output (not serialized, for debug capture only)
+	Data           any                       `json:"data"`
+	URL            string                    `json:"url"`
+	FetchedAt      time.Time                 `json:"fetched_at"`
+	Usage          UsageInfo                 `json:"usage"`
+	Metadata       ExtractMeta               `json:"metadata"`
+	InputFormat    InputFormat               `json:"input_format"` // "schema" or "prompt" - indicates how the input was interpreted
+	RawContent     string                    `json:"-"`            // Raw page content (not serialized, for debug capture only)
+	RawLLMResponse string                    `json:"-"`            // Raw LLM output (not serialized, for debug capture only)
+	Hints          map[string]string         `json:"-"`            // Preprocessing hints applied (not serialized, for debug capture only)
+	Network        []captcha.NetworkResponse `json:"-"`            // XHR/fetch responses captured while rendering (for debug capture only)
+
+	Screenshots []captcha.Screenshot `json:"-"` // Screenshots sent to the model (for debug capture only)
 }
 
 // UsageInfo represents token usage and cost information.
 type UsageInfo struct {
 	InputTokens  int     `json:"input_tokens"`
 	OutputTokens int     `json:"output_tokens"`
-	CostUSD      float64 `json:"cost_usd"`      // Total charged to user (LLM cost + markup)
-	LLMCostUSD   float64 `json:"llm_cost_usd"`  // Actual LLM cost from OpenRouter
-	IsBYOK       bool    `json:"is_byok"`       // True if user's own API key was used (no charge)
+	CostUSD      float64 `json:"cost_usd"`     // Total charged to user (LLM cost + markup)
+	LLMCostUSD   float64 `json:"llm_cost_usd"` // Actual LLM cost from OpenRouter
+	IsBYOK       bool    `json:"is_byok"`      // True if user's own API key was used (no charge)
+
+	BlockedRequests int     `json:"blocked_requests,omitempty"` // Requests blocked by the resource policy while rendering
+	SolverCostUSD   float64 `json:"solver_cost_usd,omitempty"`  // Paid CAPTCHA solves, included in CostUSD
 }
 
 // ExtractMeta represents extraction metadata.
 type ExtractMeta struct {
-	FetchDurationMs   int          `json:"fetch_duration_ms"`
-	ExtractDurationMs int          `json:"extract_duration_ms"`
-	Model             string       `json:"model"`
-	Provider          string       `json:"provider"`
-	BudgetSkips       []BudgetSkip `json:"budget_skips,omitempty"` // Models skipped due to budget constraints
+	FetchDurationMs   int                 `json:"fetch_duration_ms"`
+	ExtractDurationMs int                 `json:"extract_duration_ms"`
+	Model             string              `json:"model"`
+	Provider          string              `json:"provider"`
+	BudgetSkips       []BudgetSkip        `json:"budget_skips,omitempty"`    // Models skipped due to budget constraints
+	StructuredData    *StructuredDataMeta `json:"structured_data,omitempty"` // Fields filled from embedded structured data
+	Document          *DocumentMeta       `json:"document,omitempty"`        // Document input details (PDF, DOCX, XLSX, CSV)
+	Visual            *VisualMeta         `json:"visual,omitempty"`          // Screenshot input details (visual/hybrid modes)
 }
 
 // BudgetSkip represents a model that was skipped due to budget constraints.
@@ -199,6 +251,7 @@ type BudgetSkip struct {
 // ExtractContext holds context for billing tracking and feature access.
 type ExtractContext struct {
 	UserID                 string
+	APIKeyID               string // API key used for the request (for per-key spend caps)
 	Tier                   string // From JWT claims
 	SchemaID               string
 	JobID                  string // Job ID for tracking in captcha/browser service
@@ -211,6 +264,8 @@ type ExtractContext struct {
 	LLMProvider            string // For S3 API keys: forced LLM provider (deprecated, use LLMConfigs)
 	LLMModel               string // For S3 API keys: forced LLM model (deprecated, use LLMConfigs)
 	LLMConfigs             []config.APIKeyLLMConfig // For S3 API keys: fallback chain of LLM configs
+	Routing                RoutingDecision          // How the fallback chain was ordered (set during extraction)
+	SpendReservation       *SpendReservation        // Spend held against spend caps until usage is recorded (set during extraction)
 }
 
 // Extract performs a single-page extraction.
@@ -238,6 +293,24 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 		"llm_model", ectx.LLMModel,
 	)
 
+	// Submitted content is extracted in place of fetching; its base URL stands in for the page URL
+	content := input.SubmittedContent()
+	if content != nil {
+		if err := content.Validate(); err != nil {
+			return nil, err
+		}
+		input.URL = content.BaseURL
+	}
+
+	// Stored login to fetch the page with (submitted content isn't fetched)
+	var auth *authSession
+	if content == nil {
+		var err error
+		if auth, err = s.authProfileSvc.session(ctx, userID, ectx.Tier, input.AuthProfileID, ectx.ContentDynamicAllowed); err != nil {
+			return nil, err
+		}
+	}
+
 	// Auto-detect input format using shared helper
 	// If parsing fails, treat the input as a freeform prompt
 	inputFormat, sch, schemaErr := DetectInputFormat(input.Schema)
@@ -247,7 +320,7 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 			"user_id", userID,
 			"parse_error", schemaErr.Error(),
 		)
-		return s.extractWithPrompt(ctx, userID, input, ectx, startTime)
+		return s.extractWithPrompt(ctx, userID, input, content, auth, ectx, startTime)
 	}
 	if schemaErr != nil {
 		// Schema parsing failed for some other reason
@@ -272,7 +345,11 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 	// ModelsCustomAllowed controls whether user's custom model chain is used
 	// LLMConfigs from S3 API keys bypass the entire fallback chain (supports multiple models)
 	llmChain := s.resolveLLMConfigChain(ctx, userID, input.LLMConfig, ectx.Tier, ectx.BYOKAllowed, ectx.ModelsCustomAllowed, ectx.LLMProvider, ectx.LLMModel, ectx.LLMConfigs)
+	if content != nil {
+		llmChain.RouteForInput(len(content.Body) / 3) // ~3 chars per token
+	}
 	ectx.IsBYOK = llmChain.IsBYOK()
+	ectx.Routing = llmChain.Routing()
 
 	// Log the full chain for debugging fallback issues
 	chainModels := make([]string, 0, llmChain.Len())
@@ -283,6 +360,8 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 		"chain_length", llmChain.Len(),
 		"is_byok", llmChain.IsBYOK(),
 		"models", chainModels,
+		"routing_policy", ectx.Routing.Policy,
+		"routing_reason", ectx.Routing.Reason,
 	)
 
 	// For models_premium users, get available balance for per-model budget checking
@@ -323,16 +402,41 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 		}
 	}
 
+	// Spend caps apply to all users, including BYOK
+	if s.billing != nil && firstCfg != nil {
+		estimatedCost := s.billing.EstimateCost(1, firstCfg.Model, firstCfg.Provider)
+		reservation, err := s.billing.ReserveSpend(ctx, userID, ectx.APIKeyID, estimatedCost)
+		if err != nil {
+			return nil, err
+		}
+		ectx.SpendReservation = reservation
+		defer s.billing.ReleaseSpend(context.WithoutCancel(ctx), reservation)
+	}
+
 	// Try each config in the chain until one succeeds (no retries on same model)
 	var lastErr error
 	var lastLLMErr *llm.LLMError
 	var lastCfg *LLMConfigInput
 	modelsSkippedDueToBudget := 0
+	modelsSkippedWithoutVision := 0
 
 	for llmCfg := llmChain.Next(); llmCfg != nil; llmCfg = llmChain.Next() {
 		lastCfg = llmCfg
 		pos, total := llmChain.Position()
 
+		// Screenshot input modes need a model that accepts images
+		if input.InputMode.UsesScreenshots() && !s.supportsVision(ctx, llmCfg) {
+			modelsSkippedWithoutVision++
+			s.logger.Debug("skipping model without vision support",
+				"provider", llmCfg.Provider,
+				"model", llmCfg.Model,
+				"input_mode", input.InputMode,
+				"attempt", pos,
+				"of", total,
+			)
+			continue
+		}
+
 		// Budget-based fallback: check if this model is affordable
 		if useBudgetFallback {
 			estimatedCost := s.billing.EstimateCost(1, llmCfg.Model, llmCfg.Provider)
@@ -394,6 +498,14 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 		extractor := NewSchemaPageExtractor(s, sch, SchemaExtractorOptions{
 			LLMConfig:             llmCfg,
 			CleanerChain:          input.CleanerChain,
+			Preprocessors:         input.Preprocessors,
+			StructuredData:        input.StructuredData,
+			Actions:               input.Actions,
+			Network:               input.CaptureNetwork,
+			InputMode:             input.InputMode,
+			FetchOptions:          input.FetchOptions,
+			Auth:                  auth,
+			Content:               content,
 			ContentDynamicAllowed: ectx.ContentDynamicAllowed,
 			UserID:                userID,
 			Tier:                  ectx.Tier,
@@ -401,13 +513,26 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 		})
 
 		// Perform extraction (dynamic retry happens inside Extract)
+		llmChain.BeginCall()
 		pageResult, err := extractor.Extract(ctx, input.URL)
 
 		// Check for success
 		if err == nil && pageResult != nil && pageResult.Error == nil {
+			llmChain.RecordSuccess(time.Duration(pageResult.ExtractDurationMs) * time.Millisecond)
+
 			// Convert PageExtractionResult to refyne.Result for existing billing handler
 			refyneResult := s.pageResultToRefyneResult(pageResult)
-			return s.handleSuccessfulExtraction(ctx, userID, input, ectx, llmCfg, refyneResult, llmChain.IsBYOK(), startTime, budgetSkips)
+			output, err := s.handleSuccessfulExtraction(ctx, userID, input, ectx, llmCfg, refyneResult, llmChain.IsBYOK(), pageResult.SolverCostUSD, startTime, budgetSkips)
+			if output != nil {
+				output.Metadata.StructuredData = pageResult.StructuredData
+				output.Metadata.Document = pageResult.Document
+				output.Metadata.Visual = pageResult.Visual
+				output.Hints = pageResult.Hints
+				output.Network = pageResult.Network
+				output.Screenshots = pageResult.Screenshots
+				output.Usage.BlockedRequests = pageResult.BlockedRequests
+			}
+			return output, err
 		}
 
 		// Extraction failed - classify the error
@@ -438,6 +563,7 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 		}
 
 		lastLLMErr = llm.WrapError(lastErr, llmCfg.Provider, llmCfg.Model, llmChain.IsBYOK())
+		llmChain.RecordError(lastLLMErr)
 
 		s.logger.Warn("extraction failed",
 			"provider", llmCfg.Provider,
@@ -504,6 +630,10 @@ func (s *ExtractionService) ExtractWithContext(ctx context.Context, userID strin
 		return nil, llm.NewInsufficientCreditsError("all models exceed available budget", 0, int(availableBudget*100))
 	}
 
+	if modelsSkippedWithoutVision > 0 && modelsSkippedWithoutVision+modelsSkippedDueToBudget == llmChain.Len() {
+		return nil, llm.NewNoModelsConfiguredError(fmt.Sprintf("no vision-capable models in fallback chain for input_mode %q", input.InputMode))
+	}
+
 	return nil, llm.NewNoModelsConfiguredError("no models in fallback chain or missing API keys")
 }
 
@@ -553,6 +683,7 @@ func (s *ExtractionService) recordFailedExtractionWithDetails(
 
 	usageRecord := &UsageRecord{
 		UserID:          userID,
+		APIKeyID:        ectx.APIKeyID,
 		JobType:         models.JobTypeExtract,
 		Status:          "failed",
 		TotalChargedUSD: 0, // No charge for failed attempts
@@ -564,9 +695,13 @@ func (s *ExtractionService) recordFailedExtractionWithDetails(
 		LLMProvider:     llmCfg.Provider,
 		LLMModel:        llmCfg.Model,
 		BYOKProvider:    byokProvider,
+		RoutingPolicy:   string(ectx.Routing.Policy),
+		RoutingReason:   ectx.Routing.Reason,
 		PagesAttempted:  totalRetries,
 		PagesSuccessful: 0,
 		TotalDurationMs: int(time.Since(startTime).Milliseconds()),
+
+		SpendReservation: ectx.SpendReservation,
 	}
 
 	// Use detached context - we want to record usage even if request timed out
@@ -598,6 +733,7 @@ func (s *ExtractionService) recordBudgetExhaustedFailure(
 
 	usageRecord := &UsageRecord{
 		UserID:          userID,
+		APIKeyID:        ectx.APIKeyID,
 		JobType:         models.JobTypeExtract,
 		Status:          "failed",
 		TotalChargedUSD: 0, // No charge - nothing was attempted
@@ -606,9 +742,13 @@ func (s *ExtractionService) recordBudgetExhaustedFailure(
 		SchemaID:        ectx.SchemaID,
 		ErrorMessage:    errorMessage,
 		ErrorCode:       "budget_exhausted",
+		RoutingPolicy:   string(ectx.Routing.Policy),
+		RoutingReason:   ectx.Routing.Reason,
 		PagesAttempted:  0,
 		PagesSuccessful: 0,
 		TotalDurationMs: int(time.Since(startTime).Milliseconds()),
+
+		SpendReservation: ectx.SpendReservation,
 	}
 
 	// Use detached context - we want to record usage even if request timed out
@@ -626,6 +766,7 @@ func (s *ExtractionService) handleSuccessfulExtraction(
 	llmCfg *LLMConfigInput,
 	result *refyne.Result,
 	isBYOK bool,
+	solverCostUSD float64,
 	startTime time.Time,
 	budgetSkips []BudgetSkip,
 ) (*ExtractOutput, error) {
@@ -634,6 +775,7 @@ func (s *ExtractionService) handleSuccessfulExtraction(
 	if s.billing != nil {
 		billingResult, _ = s.billing.ChargeForUsage(ctx, &ChargeForUsageInput{
 			UserID:            userID,
+			APIKeyID:          ectx.APIKeyID,
 			Tier:              ectx.Tier,
 			JobType:           models.JobTypeExtract,
 			IsBYOK:            isBYOK,
@@ -642,7 +784,10 @@ func (s *ExtractionService) handleSuccessfulExtraction(
 			Model:             llmCfg.Model,
 			Provider:          llmCfg.Provider,
 			APIKey:            llmCfg.APIKey,
+			SolverCostUSD:     solverCostUSD,
 			GenerationID:      result.GenerationID,
+			RoutingPolicy:     string(ectx.Routing.Policy),
+			RoutingReason:     ectx.Routing.Reason,
 			TargetURL:         input.URL,
 			SchemaID:          ectx.SchemaID,
 			PagesAttempted:    1,
@@ -650,6 +795,7 @@ func (s *ExtractionService) handleSuccessfulExtraction(
 			FetchDurationMs:   int(result.FetchDuration.Milliseconds()),
 			ExtractDurationMs: int(result.ExtractDuration.Milliseconds()),
 			TotalDurationMs:   int(time.Since(startTime).Milliseconds()),
+			SpendReservation:  ectx.SpendReservation,
 		})
 	}
 
@@ -662,6 +808,7 @@ func (s *ExtractionService) handleSuccessfulExtraction(
 	if billingResult != nil {
 		usageInfo.CostUSD = billingResult.TotalCostUSD
 		usageInfo.LLMCostUSD = billingResult.LLMCostUSD
+		usageInfo.SolverCostUSD = billingResult.SolverCostUSD
 	}
 
 	s.logger.Info("extraction completed",
@@ -745,7 +892,7 @@ func (s *ExtractionService) resolveLLMConfigChain(ctx context.Context, userID st
 			configs = append(configs, cfg)
 		}
 
-		return NewLLMConfigChain(configs, false) // Not BYOK - using system keys
+		return NewLLMConfigChain(configs, false).WithHealth(s.resolver.HealthTracker()) // Not BYOK - using system keys
 	}
 
 	// Backward compatibility: single provider/model (deprecated)
@@ -771,7 +918,7 @@ func (s *ExtractionService) resolveLLMConfigChain(ctx context.Context, userID st
 			cfg.APIKey = serviceKeys.Get(injectedProvider)
 		}
 
-		return NewLLMConfigChain([]*LLMConfigInput{cfg}, false) // Not BYOK - using system keys
+		return NewLLMConfigChain([]*LLMConfigInput{cfg}, false).WithHealth(s.resolver.HealthTracker()) // Not BYOK - using system keys
 	}
 
 	// Delegate to resolver for all standard cases
@@ -789,17 +936,34 @@ func (s *ExtractionService) resolveLLMConfigChain(ctx context.Context, userID st
 
 // FetchModeConfig holds fetch mode configuration for creating refyne instances.
 type FetchModeConfig struct {
-	Mode                  string // "auto", "static", or "dynamic"
-	ContentDynamicAllowed bool   // Whether user has content_dynamic feature
-	UserID                string // For creating dynamic fetcher context
-	Tier                  string // For creating dynamic fetcher context
-	JobID                 string // For tracking in dynamic fetcher
+	Mode                  string                  // "auto", "static", "dynamic", or "content"
+	ContentDynamicAllowed bool                    // Whether user has content_dynamic feature
+	UserID                string                  // For creating dynamic fetcher context
+	Tier                  string                  // For creating dynamic fetcher context
+	JobID                 string                  // For tracking in dynamic fetcher
+	Actions               []captcha.Action        // Browser actions run before capture (dynamic mode)
+	Network               *NetworkCaptureConfig   // XHR/fetch responses to record (dynamic mode)
+	Resources             *captcha.ResourcePolicy // Page resources to block (dynamic mode)
+	OnBlocked             func(int)               // Receives each rendered page's blocked request count
+	OnSolver              func(float64)           // Receives each rendered page's paid CAPTCHA solver cost
+	Proxy                 *ProxyRequest           // Proxies to fetch through, nil to fetch directly
+	Auth                  *authSession            // Stored login to fetch with, nil to fetch anonymously
+
+	// Screenshots are full-page screenshots to take of each rendered page (dynamic mode)
+	Screenshots *captcha.ScreenshotCapture
+
+	// Content is submitted content to extract from instead of fetching (Mode "content")
+	Content *SubmittedContent
 }
 
 // createRefyneInstanceWithFetchMode creates a new refyne instance with configurable fetch mode.
 // When fetchMode is "dynamic", uses browser rendering via the captcha service.
+// Documents (PDF, DOCX, XLSX, CSV) are converted to HTML in every fetch mode.
+// Optional decorators wrap the LLM extractor; when present, fetched pages are
+// captured so decorators can inspect the raw HTML, and LLM calls are recorded in
+// telemetry. Without decorators refyne builds its own extractor.
 // Returns the refyne instance and the cleaner chain name for logging.
-func (s *ExtractionService) createRefyneInstanceWithFetchMode(llmCfg *LLMConfigInput, cleanerChain []CleanerConfig, fetchCfg FetchModeConfig) (*refyne.Refyne, string, error) {
+func (s *ExtractionService) createRefyneInstanceWithFetchMode(llmCfg *LLMConfigInput, cleanerChain []CleanerConfig, fetchCfg FetchModeConfig, decorators ...extractorDecorator) (*refyne.Refyne, string, error) {
 	// Create cleaner chain from factory, using default if not specified
 	factory := NewCleanerFactory()
 	contentCleaner, err := factory.CreateChainWithDefault(cleanerChain, DefaultExtractionCleanerChain)
@@ -821,7 +985,14 @@ func (s *ExtractionService) createRefyneInstanceWithFetchMode(llmCfg *LLMConfigI
 		refyne.WithLogger(s.logger), // Inject our logger into refyne
 	}
 
+	// Extractor decorators need the raw page, so capture it from the fetcher
+	page := &pageCapture{}
+
+	// Route fetches through proxies when the request asks for them
+	proxies := s.proxySvc.router(fetchCfg.Proxy)
+
 	// Handle fetch modes
+	var pageFetcher fetcher.Fetcher
 	switch fetchCfg.Mode {
 	case "dynamic":
 		// Explicit dynamic mode - use browser rendering via captcha service
@@ -837,9 +1008,19 @@ func (s *ExtractionService) createRefyneInstanceWithFetchMode(llmCfg *LLMConfigI
 			UserID:     fetchCfg.UserID,
 			Tier:       fetchCfg.Tier,
 			JobID:      fetchCfg.JobID,
+			Actions:    fetchCfg.Actions,
+			Network:    fetchCfg.Network.captchaConfig(),
+			OnNetwork:  page.setNetwork,
+			Resources:  fetchCfg.Resources,
+			OnBlocked:  fetchCfg.OnBlocked,
+			OnSolver:   fetchCfg.OnSolver,
+			Proxies:    proxies,
 			Logger:     s.logger,
+
+			Screenshots:   fetchCfg.Screenshots,
+			OnScreenshots: page.setScreenshots,
 		})
-		opts = append(opts, refyne.WithFetcher(dynamicFetcher))
+		pageFetcher = dynamicFetcher
 
 		s.logger.Info("using browser rendering for extraction",
 			"user_id", fetchCfg.UserID,
@@ -850,9 +1031,11 @@ func (s *ExtractionService) createRefyneInstanceWithFetchMode(llmCfg *LLMConfigI
 		// Auto mode - use protection-aware fetcher that detects bot protection
 		// The caller should catch ErrBotProtectionDetected and retry with dynamic if allowed
 		protectionFetcher := NewProtectionAwareFetcher(ProtectionAwareFetcherConfig{
-			Logger: s.logger,
+			Proxies:    proxies,
+			Clearances: s.captchaSvc.Clearances(),
+			Logger:     s.logger,
 		})
-		opts = append(opts, refyne.WithFetcher(protectionFetcher))
+		pageFetcher = protectionFetcher
 
 		s.logger.Debug("using protection-aware fetcher for extraction",
 			"user_id", fetchCfg.UserID,
@@ -860,11 +1043,24 @@ func (s *ExtractionService) createRefyneInstanceWithFetchMode(llmCfg *LLMConfigI
 		)
 
 	case "static":
-		// Explicit static mode - use default Colly fetcher (no custom fetcher needed)
+		// Explicit static mode - use default Colly fetcher (no custom fetcher needed),
+		// unless proxies must be rotated away from blocked responses or cookies sent
+		if proxies != nil || fetchCfg.Auth != nil {
+			pageFetcher = NewProtectionAwareFetcher(ProtectionAwareFetcherConfig{
+				Proxies:    proxies,
+				Clearances: s.captchaSvc.Clearances(),
+				Logger:     s.logger,
+			})
+		}
 		s.logger.Debug("using static fetcher for extraction",
 			"user_id", fetchCfg.UserID,
 			"job_id", fetchCfg.JobID,
+			"proxy", fetchCfg.Proxy != nil,
 		)
+
+	case "content":
+		// Submitted content - nothing is fetched
+		pageFetcher = &contentFetcher{content: fetchCfg.Content}
 	}
 
 	if llmCfg.APIKey != "" {
@@ -885,6 +1081,35 @@ func (s *ExtractionService) createRefyneInstanceWithFetchMode(llmCfg *LLMConfigI
 	}
 	opts = append(opts, refyne.WithMaxTokens(maxTokens))
 
+	if pageFetcher == nil {
+		// Same static fetcher refyne would create by default
+		pageFetcher = fetcher.NewStatic(fetcher.StaticConfig{Timeout: llm.LLMTimeout})
+	}
+
+	// Convert PDF, DOCX, XLSX and CSV responses to HTML for the cleaner chain
+	if fetchCfg.Mode != "content" {
+		pageFetcher = NewDocumentFetcher(pageFetcher, proxies, s.logger)
+	}
+	// Sign fetches in with the auth profile, including document downloads
+	if fetchCfg.Auth != nil {
+		pageFetcher = &authFetcher{Fetcher: pageFetcher, auth: fetchCfg.Auth}
+	}
+	pageFetcher = newInstrumentedFetcher(pageFetcher, fetchCfg.Mode)
+
+	if len(decorators) > 0 {
+		// Record LLM calls innermost, so each retry by other decorators is its own call
+		decorators = append([]extractorDecorator{newLLMTelemetryExtractor(llmCfg).decorate}, decorators...)
+
+		pageFetcher = &capturingFetcher{Fetcher: pageFetcher, capture: page}
+
+		ext, err := buildDecoratedExtractor(opts, page, decorators)
+		if err != nil {
+			return nil, "", err
+		}
+		opts = append(opts, refyne.WithExtractor(ext))
+	}
+	opts = append(opts, refyne.WithFetcher(pageFetcher))
+
 	r, err := refyne.New(opts...)
 	if err != nil {
 		return nil, "", err
@@ -2,10 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"testing"
 
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 	"github.com/jmylchreest/refyne-api/internal/config"
+	"github.com/jmylchreest/refyne-api/internal/models"
 	"github.com/jmylchreest/refyne-api/internal/repository"
 )
 
@@ -535,3 +540,75 @@ func TestPageResult_WithError(t *testing.T) {
 		t.Error("expected IsBYOK to be true")
 	}
 }
+
+// ========================================
+// Browser Actions Tests
+// ========================================
+
+func TestPageExtractors_ActionsRequireBrowserRendering(t *testing.T) {
+	svc := &ExtractionService{logger: slog.Default()}
+	llmCfg := &LLMConfigInput{Provider: "openai", Model: "gpt-4o-mini"}
+	actions := []captcha.Action{{Type: "click", Selector: "button.load-more", Repeat: 5}}
+
+	extractors := map[string]PageExtractor{
+		"schema": NewSchemaPageExtractor(svc, schema.Schema{}, SchemaExtractorOptions{
+			LLMConfig: llmCfg,
+			Actions:   actions,
+		}),
+		"prompt": NewPromptPageExtractor(svc, PromptExtractorOptions{
+			PromptText: "Extract the products",
+			LLMConfig:  llmCfg,
+			Actions:    actions,
+		}),
+	}
+
+	// Actions start extraction in dynamic mode, which the user isn't allowed to use
+	for name, extractor := range extractors {
+		t.Run(name, func(t *testing.T) {
+			result, err := extractor.Extract(context.Background(), "https://example.com/products")
+			if !errors.Is(err, ErrDynamicFetchNotAllowed) {
+				t.Fatalf("Extract() error = %v, want ErrDynamicFetchNotAllowed", err)
+			}
+			if !errors.Is(result.Error, ErrDynamicFetchNotAllowed) {
+				t.Errorf("result.Error = %v, want ErrDynamicFetchNotAllowed", result.Error)
+			}
+		})
+	}
+}
+
+// ========================================
+// Crawl Usage Tests
+// ========================================
+
+func TestRecordCrawlUsage_Routing(t *testing.T) {
+	billing, _, _, usageRepo, insightRepo, _ := newTestBillingService()
+	svc := &ExtractionService{billing: billing, logger: slog.Default()}
+
+	input := CrawlInput{
+		JobID: "job_123",
+		URL:   "https://example.com",
+		Options: CrawlOptions{
+			RoutingPolicy: models.RoutingCheapestCapable,
+			RoutingReason: "cheapest of 2 capable models",
+		},
+	}
+	result := &CrawlResult{PageCount: 3, TotalCostUSD: 0.05, LLMProvider: "openai", LLMModel: "gpt-4o-mini"}
+	svc.recordCrawlUsage(context.Background(), "user_123", input, result, 2)
+
+	if len(usageRepo.records) != 1 {
+		t.Fatalf("expected 1 usage record, got %d", len(usageRepo.records))
+	}
+	if usage := usageRepo.records[0]; usage.Type != models.JobTypeCrawl || usage.JobID != "job_123" || usage.TotalChargedUSD != 0.05 {
+		t.Errorf("usage record = %+v", usage)
+	}
+	if len(insightRepo.insights) != 1 {
+		t.Fatalf("expected 1 usage insight, got %d", len(insightRepo.insights))
+	}
+	insight := insightRepo.insights[0]
+	if insight.RoutingPolicy != string(models.RoutingCheapestCapable) || insight.RoutingReason != input.Options.RoutingReason {
+		t.Errorf("routing = %q (%q), want %q (%q)", insight.RoutingPolicy, insight.RoutingReason, models.RoutingCheapestCapable, input.Options.RoutingReason)
+	}
+	if insight.PagesAttempted != 3 || insight.PagesSuccessful != 2 {
+		t.Errorf("pages = %d/%d, want 2/3", insight.PagesSuccessful, insight.PagesAttempted)
+	}
+}
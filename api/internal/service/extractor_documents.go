@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/documents"
+)
+
+// DocumentMeta reports how a document input (PDF, DOCX, XLSX or CSV) was extracted.
+type DocumentMeta struct {
+	Format    string `json:"format"`              // Document format: pdf, docx, xlsx or csv
+	Chunks    int    `json:"chunks"`              // Number of chunks sent to the LLM
+	Truncated bool   `json:"truncated,omitempty"` // True if the document had more chunks than were extracted
+}
+
+// documentChunkExtractor is an extractor decorator that splits long documents into
+// page-aligned chunks, extracts each chunk separately and merges the results, so
+// catalogues larger than the LLM context are extracted in full. Web pages and short
+// documents pass straight through. It should be the innermost decorator so hints
+// and structured data apply to the document as a whole.
+type documentChunkExtractor struct {
+	decoratedExtractor
+	page   *pageCapture
+	logger *slog.Logger
+
+	// meta records the document handled by the last Extract call (nil for web pages).
+	meta *DocumentMeta
+}
+
+// newDocumentChunkExtractor creates a document chunking decorator.
+// Use decorate as the extractorDecorator when building a refyne instance.
+func newDocumentChunkExtractor(logger *slog.Logger) *documentChunkExtractor {
+	return &documentChunkExtractor{logger: logger}
+}
+
+// decorate implements extractorDecorator.
+func (e *documentChunkExtractor) decorate(inner extractor.Extractor, page *pageCapture) extractor.Extractor {
+	e.inner = inner
+	e.page = page
+	e.meta = nil
+	return e
+}
+
+// Name returns the extractor name.
+func (e *documentChunkExtractor) Name() string {
+	return "documents+" + e.inner.Name()
+}
+
+// Extract extracts documents chunk by chunk and merges the results.
+func (e *documentChunkExtractor) Extract(ctx context.Context, content string, s schema.Schema) (*extractor.Result, error) {
+	e.meta = nil
+
+	format := e.page.document()
+	if format == "" {
+		return e.inner.Extract(ctx, content, s)
+	}
+
+	chunks := documents.Chunk(content, constants.DocumentChunkSize)
+	meta := &DocumentMeta{Format: string(format), Chunks: len(chunks)}
+	e.meta = meta
+	if len(chunks) <= 1 {
+		return e.inner.Extract(ctx, content, s)
+	}
+
+	pageURL, _ := e.page.get()
+	if len(chunks) > constants.MaxDocumentChunks {
+		e.logger.Warn("document exceeds chunk limit, extracting leading chunks only",
+			"url", pageURL,
+			"chunks", len(chunks),
+			"max_chunks", constants.MaxDocumentChunks,
+		)
+		chunks = chunks[:constants.MaxDocumentChunks]
+		meta.Chunks = len(chunks)
+		meta.Truncated = true
+	}
+
+	e.logger.Info("extracting document in chunks",
+		"url", pageURL,
+		"format", format,
+		"chunks", len(chunks),
+		"content_size", len(content),
+	)
+
+	var merged *extractor.Result
+	var raws []string
+	for i, chunk := range chunks {
+		guidance := fmt.Sprintf("## Document Part\nThis content is part %d of %d of a longer document. "+
+			"Extract only what appears in this part; the parts are merged afterwards.", i+1, len(chunks))
+
+		result, err := e.inner.Extract(ctx, chunk, withPromptGuidance(s, guidance))
+		if err != nil {
+			return result, err
+		}
+		if result == nil {
+			return nil, fmt.Errorf("document chunk %d of %d returned no result", i+1, len(chunks))
+		}
+		raws = append(raws, result.Raw)
+
+		if merged == nil {
+			merged = result
+		} else {
+			mergeChunkResult(merged, result)
+		}
+		if result.IsTruncated() {
+			// Truncated output is handled by model fallback for the whole document
+			merged.FinishReason = result.FinishReason
+			return merged, nil
+		}
+	}
+
+	merged.Raw = strings.Join(raws, "\n")
+	merged.RawContent = content
+	// Each chunk is a separate generation, so cost is estimated from the summed tokens
+	merged.GenerationID = ""
+	return merged, nil
+}
+
+// mergeChunkResult folds a chunk's result into the running merged result.
+func mergeChunkResult(merged, result *extractor.Result) {
+	merged.Data = mergeChunkData(merged.Data, result.Data)
+	merged.Errors = append(merged.Errors, result.Errors...)
+	merged.Usage.InputTokens += result.Usage.InputTokens
+	merged.Usage.OutputTokens += result.Usage.OutputTokens
+	merged.RetryCount += result.RetryCount
+	merged.Duration += result.Duration
+	merged.FinishReason = result.FinishReason
+	if merged.CostIncluded && result.CostIncluded {
+		merged.Cost += result.Cost
+	} else {
+		merged.CostIncluded = false
+	}
+}
+
+// mergeChunkData merges data extracted from consecutive document chunks: lists are
+// concatenated, objects are merged field by field, and for other values the first
+// non-empty value wins (document headers usually come first).
+func mergeChunkData(a, b any) any {
+	if isEmptyChunkValue(a) {
+		return b
+	}
+	if isEmptyChunkValue(b) {
+		return a
+	}
+
+	switch av := a.(type) {
+	case []any:
+		if bv, ok := b.([]any); ok {
+			return append(av, bv...)
+		}
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			for key, value := range bv {
+				av[key] = mergeChunkData(av[key], value)
+			}
+			return av
+		}
+	}
+	return a
+}
+
+// isEmptyChunkValue reports whether an extracted value carries no data.
+func isEmptyChunkValue(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(val) == ""
+	case []any:
+		return len(val) == 0
+	case map[string]any:
+		return len(val) == 0
+	}
+	return false
+}
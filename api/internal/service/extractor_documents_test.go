@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+)
+
+// chunkRecorder returns one result per call, recording the content and schema of each call.
+type chunkRecorder struct {
+	results  []*extractor.Result
+	contents []string
+	schemas  []schema.Schema
+}
+
+func (c *chunkRecorder) Extract(_ context.Context, content string, sch schema.Schema) (*extractor.Result, error) {
+	c.contents = append(c.contents, content)
+	c.schemas = append(c.schemas, sch)
+	return c.results[len(c.contents)-1], nil
+}
+
+func (c *chunkRecorder) Name() string    { return "recorder" }
+func (c *chunkRecorder) Available() bool { return true }
+
+func newDocumentTestExtractor(contentType string, inner extractor.Extractor) *documentChunkExtractor {
+	page := &pageCapture{}
+	page.set("https://example.com/catalogue.pdf", "<html></html>", contentType)
+	e := newDocumentChunkExtractor(slog.Default())
+	e.decorate(inner, page)
+	return e
+}
+
+// longDocument builds cleaned document content with the given number of pages,
+// each large enough that two pages do not fit in one chunk.
+func longDocument(pages int) string {
+	var b strings.Builder
+	b.WriteString("# Catalogue\n\n")
+	for i := 1; i <= pages; i++ {
+		fmt.Fprintf(&b, "## Page %d\n\n%s\n\n", i, strings.Repeat("w", constants.DocumentChunkSize*2/3))
+	}
+	return b.String()
+}
+
+func TestDocumentChunkExtractor_WebPagePassesThrough(t *testing.T) {
+	inner := &chunkRecorder{results: []*extractor.Result{{Data: map[string]any{}}}}
+	e := newDocumentTestExtractor("text/html; charset=utf-8", inner)
+
+	if _, err := e.Extract(context.Background(), longDocument(3), productListSchema); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(inner.contents) != 1 {
+		t.Errorf("web pages should not be chunked, got %d calls", len(inner.contents))
+	}
+	if e.meta != nil {
+		t.Errorf("meta should be nil for web pages, got %+v", e.meta)
+	}
+}
+
+func TestDocumentChunkExtractor_ShortDocumentSingleCall(t *testing.T) {
+	inner := &chunkRecorder{results: []*extractor.Result{{Data: map[string]any{}}}}
+	e := newDocumentTestExtractor("application/pdf", inner)
+
+	if _, err := e.Extract(context.Background(), "# Catalogue\n\n## Page 1\n\nWidget", productListSchema); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(inner.contents) != 1 {
+		t.Errorf("expected 1 call, got %d", len(inner.contents))
+	}
+	if e.meta == nil || e.meta.Format != "pdf" || e.meta.Chunks != 1 {
+		t.Errorf("unexpected meta %+v", e.meta)
+	}
+}
+
+func TestDocumentChunkExtractor_MergesChunks(t *testing.T) {
+	inner := &chunkRecorder{results: []*extractor.Result{
+		{
+			Data:         map[string]any{"title": "Catalogue", "products": []any{"a", "b"}},
+			Raw:          "r1",
+			Usage:        extractor.Usage{InputTokens: 100, OutputTokens: 10},
+			GenerationID: "gen-1",
+			Cost:         0.01,
+			CostIncluded: true,
+			FinishReason: "stop",
+		},
+		{
+			Data:         map[string]any{"title": "", "products": []any{"c"}},
+			Raw:          "r2",
+			Usage:        extractor.Usage{InputTokens: 90, OutputTokens: 8},
+			GenerationID: "gen-2",
+			Cost:         0.02,
+			CostIncluded: true,
+			FinishReason: "stop",
+		},
+		{
+			Data:         map[string]any{"title": "Appendix", "products": []any{"d"}},
+			Raw:          "r3",
+			Usage:        extractor.Usage{InputTokens: 80, OutputTokens: 6},
+			GenerationID: "gen-3",
+			Cost:         0.03,
+			CostIncluded: true,
+			FinishReason: "stop",
+		},
+	}}
+	e := newDocumentTestExtractor("application/pdf", inner)
+	content := longDocument(3)
+
+	result, err := e.Extract(context.Background(), content, productListSchema)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if len(inner.contents) != 3 {
+		t.Fatalf("expected 3 chunk calls, got %d", len(inner.contents))
+	}
+	for i, chunk := range inner.contents {
+		if !strings.Contains(chunk, fmt.Sprintf("## Page %d", i+1)) {
+			t.Errorf("chunk %d should contain page %d", i+1, i+1)
+		}
+		if !strings.Contains(inner.schemas[i].Description, fmt.Sprintf("part %d of 3", i+1)) {
+			t.Errorf("chunk %d schema should describe its part, got %q", i+1, inner.schemas[i].Description)
+		}
+	}
+
+	want := map[string]any{"title": "Catalogue", "products": []any{"a", "b", "c", "d"}}
+	if !reflect.DeepEqual(result.Data, want) {
+		t.Errorf("Data = %#v, want %#v", result.Data, want)
+	}
+	if result.Usage.InputTokens != 270 || result.Usage.OutputTokens != 24 {
+		t.Errorf("usage should be summed, got %+v", result.Usage)
+	}
+	if !result.CostIncluded || result.Cost < 0.0599 || result.Cost > 0.0601 {
+		t.Errorf("cost should be summed, got %v (included=%v)", result.Cost, result.CostIncluded)
+	}
+	if result.GenerationID != "" {
+		t.Errorf("generation ID should be cleared for merged results, got %q", result.GenerationID)
+	}
+	if result.Raw != "r1\nr2\nr3" || result.RawContent != content {
+		t.Errorf("unexpected raw fields: Raw=%q", result.Raw)
+	}
+	if e.meta == nil || e.meta.Chunks != 3 || e.meta.Truncated {
+		t.Errorf("unexpected meta %+v", e.meta)
+	}
+}
+
+func TestDocumentChunkExtractor_StopsOnTruncatedChunk(t *testing.T) {
+	inner := &chunkRecorder{results: []*extractor.Result{
+		{Data: map[string]any{"products": []any{"a"}}, FinishReason: "stop"},
+		{Data: map[string]any{"products": []any{"b"}}, FinishReason: "length"},
+		{Data: map[string]any{"products": []any{"c"}}, FinishReason: "stop"},
+	}}
+	e := newDocumentTestExtractor("application/pdf", inner)
+
+	result, err := e.Extract(context.Background(), longDocument(3), productListSchema)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(inner.contents) != 2 {
+		t.Errorf("expected extraction to stop after the truncated chunk, got %d calls", len(inner.contents))
+	}
+	if !result.IsTruncated() {
+		t.Error("merged result should be reported as truncated")
+	}
+}
+
+func TestDocumentChunkExtractor_CapsChunks(t *testing.T) {
+	pages := constants.MaxDocumentChunks + 2
+	results := make([]*extractor.Result, pages)
+	for i := range results {
+		results[i] = &extractor.Result{Data: map[string]any{}}
+	}
+	inner := &chunkRecorder{results: results}
+	e := newDocumentTestExtractor("text/csv", inner)
+
+	if _, err := e.Extract(context.Background(), longDocument(pages), productListSchema); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(inner.contents) != constants.MaxDocumentChunks {
+		t.Errorf("expected %d calls, got %d", constants.MaxDocumentChunks, len(inner.contents))
+	}
+	if e.meta == nil || !e.meta.Truncated || e.meta.Format != "csv" {
+		t.Errorf("unexpected meta %+v", e.meta)
+	}
+}
+
+func TestMergeChunkData(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+		want any
+	}{
+		{"top-level arrays concatenate", []any{1, 2}, []any{3}, []any{1, 2, 3}},
+		{"empty first takes second", nil, map[string]any{"x": "y"}, map[string]any{"x": "y"}},
+		{"empty second keeps first", []any{1}, []any{}, []any{1}},
+		{"first scalar wins", "first", "second", "first"},
+		{"blank scalar is replaced", "  ", "second", "second"},
+		{
+			"nested objects merge",
+			map[string]any{"supplier": map[string]any{"name": "Acme"}, "items": []any{"a"}},
+			map[string]any{"supplier": map[string]any{"phone": "123"}, "items": []any{"b"}, "currency": "EUR"},
+			map[string]any{"supplier": map[string]any{"name": "Acme", "phone": "123"}, "items": []any{"a", "b"}, "currency": "EUR"},
+		},
+		{"mismatched types keep first", map[string]any{"a": 1}, []any{2}, map[string]any{"a": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeChunkData(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeChunkData() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// staticContentFetcher returns fixed content, recording whether it was called.
+type staticContentFetcher struct {
+	content fetcher.Content
+	called  bool
+}
+
+func (f *staticContentFetcher) Fetch(_ context.Context, url string, _ fetcher.Options) (fetcher.Content, error) {
+	f.called = true
+	content := f.content
+	content.URL = url
+	return content, nil
+}
+
+func (f *staticContentFetcher) Close() error { return nil }
+func (f *staticContentFetcher) Type() string { return "static-content" }
+
+func TestDocumentFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/prices.csv":
+			if r.Header.Get("User-Agent") != "refyne-test" {
+				t.Errorf("User-Agent = %q, want refyne-test", r.Header.Get("User-Agent"))
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("sku,price\nW-1,9.99\n"))
+		case "/download.pdf":
+			// A download page that is not actually a document
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte("<html><body>Sign in to download</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	opts := fetcher.Options{UserAgent: "refyne-test"}
+
+	t.Run("document URLs are downloaded directly", func(t *testing.T) {
+		inner := &staticContentFetcher{}
+		f := NewDocumentFetcher(inner, nil, slog.Default())
+
+		content, err := f.Fetch(ctx, server.URL+"/prices.csv", opts)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if inner.called {
+			t.Error("inner fetcher should not be used for document URLs")
+		}
+		if content.ContentType != "text/csv" {
+			t.Errorf("ContentType = %q, want text/csv", content.ContentType)
+		}
+		if !strings.Contains(content.HTML, "<td>W-1</td><td>9.99</td>") || !strings.Contains(content.HTML, "<h2>Page 1</h2>") {
+			t.Errorf("document should be rendered as HTML, got %q", content.HTML)
+		}
+	})
+
+	t.Run("misleading extension is treated as a web page", func(t *testing.T) {
+		f := NewDocumentFetcher(&staticContentFetcher{}, nil, slog.Default())
+
+		content, err := f.Fetch(ctx, server.URL+"/download.pdf", opts)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if !strings.Contains(content.HTML, "Sign in to download") || content.ContentType != "text/html" {
+			t.Errorf("unexpected content %+v", content)
+		}
+	})
+
+	t.Run("missing document returns an error", func(t *testing.T) {
+		f := NewDocumentFetcher(&staticContentFetcher{}, nil, slog.Default())
+
+		if _, err := f.Fetch(ctx, server.URL+"/missing.xlsx", opts); err == nil {
+			t.Error("expected error for 404 document")
+		}
+	})
+
+	t.Run("documents served without an extension are detected", func(t *testing.T) {
+		inner := &staticContentFetcher{content: fetcher.Content{
+			HTML:        "name;qty\nWidget;4\n",
+			ContentType: "text/csv; charset=utf-8",
+			StatusCode:  http.StatusOK,
+		}}
+		f := NewDocumentFetcher(inner, nil, slog.Default())
+
+		content, err := f.Fetch(ctx, "https://example.com/export?format=csv", opts)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if !inner.called || !strings.Contains(content.HTML, "<td>Widget</td><td>4</td>") {
+			t.Errorf("expected converted CSV, got %q", content.HTML)
+		}
+	})
+
+	t.Run("web pages pass through", func(t *testing.T) {
+		page := "<html><body><h1>Products</h1></body></html>"
+		inner := &staticContentFetcher{content: fetcher.Content{HTML: page, ContentType: "text/html"}}
+		f := NewDocumentFetcher(inner, nil, slog.Default())
+
+		content, err := f.Fetch(ctx, "https://example.com/products", opts)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if content.HTML != page {
+			t.Errorf("HTML = %q, want unchanged page", content.HTML)
+		}
+	})
+}
+
+func TestDocumentFetcher_Proxies(t *testing.T) {
+	// Forward proxies see the absolute document URL; one is blocked by the site
+	var blockedHits atomic.Int32
+	blocked := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blockedHits.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<title>Attention Required! | Cloudflare</title>"))
+	}))
+	defer blocked.Close()
+	working := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "files.example" {
+			t.Errorf("proxy got request for %q, want files.example", r.URL.Host)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		_, _ = w.Write([]byte("sku,price\nW-1,9.99\n"))
+	}))
+	defer working.Close()
+
+	svc, repo := setupProxyService(t)
+	ctx := context.Background()
+	bad, _ := svc.Create(ctx, "", ProxyInput{Name: "blocked", URL: blocked.URL, IsEnabled: true})
+	if _, err := svc.Create(ctx, "", ProxyInput{Name: "working", URL: working.URL, IsEnabled: true}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	f := NewDocumentFetcher(&staticContentFetcher{}, svc.router(&ProxyRequest{Source: ProxySourcePool}), slog.Default())
+	content, err := f.Fetch(ctx, "http://files.example/prices.csv", fetcher.Options{})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !strings.Contains(content.HTML, "<td>W-1</td><td>9.99</td>") {
+		t.Errorf("expected the CSV from the working proxy, got %q", content.HTML)
+	}
+
+	// Blocks are scored against the proxy like page fetches
+	health, _ := repo.ListHealthByProxyID(ctx, bad.ID)
+	var bans int64
+	for _, h := range health {
+		if h.Domain == "files.example" {
+			bans = h.Bans
+		}
+	}
+	if bans != int64(blockedHits.Load()) {
+		t.Errorf("blocked proxy bans = %d, want one per blocked download (%d)", bans, blockedHits.Load())
+	}
+}
+
+func TestDownloadDocument_ClosesProxyConnections(t *testing.T) {
+	closed := make(chan struct{}, 1)
+	proxy := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("sku,price\n"))
+	}))
+	proxy.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			closed <- struct{}{}
+		}
+	}
+	proxy.Start()
+	defer proxy.Close()
+
+	proxyURL, _ := url.Parse(proxy.URL)
+	if _, _, _, err := downloadDocument(context.Background(), "http://files.example/prices.csv", fetcher.Options{}, &SelectedProxy{URL: proxyURL}); err != nil {
+		t.Fatalf("downloadDocument() error = %v", err)
+	}
+
+	// The per-request transport must not keep its connection to the proxy open
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Error("connection to the proxy was left open after the download")
+	}
+}
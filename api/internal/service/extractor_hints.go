@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/preprocessor"
+)
+
+// hintsExtractor is an extractor decorator that runs the preprocessor chain over
+// the fetched page and adds the resulting hints (e.g., "this page has 48 products")
+// to the extraction prompt. After extraction it compares the number of items
+// returned against the number detected and retries once if far too few came back
+// (see itemCountRetry).
+type hintsExtractor struct {
+	decoratedExtractor
+	page   *pageCapture
+	chain  *preprocessor.Chain
+	logger *slog.Logger
+
+	// applied records the hints used by the last Extract call (nil if none), for debug capture.
+	applied map[string]string
+}
+
+// newHintsExtractor creates a hints decorator for the given preprocessor chain.
+// Use decorate as the extractorDecorator when building a refyne instance.
+func newHintsExtractor(chain *preprocessor.Chain, logger *slog.Logger) *hintsExtractor {
+	return &hintsExtractor{chain: chain, logger: logger}
+}
+
+// decorate implements extractorDecorator.
+func (e *hintsExtractor) decorate(inner extractor.Extractor, page *pageCapture) extractor.Extractor {
+	e.inner = inner
+	e.page = page
+	e.applied = nil
+	return e
+}
+
+// Name returns the extractor name.
+func (e *hintsExtractor) Name() string {
+	return "hints+" + e.inner.Name()
+}
+
+// Extract adds preprocessor hints to the schema description, extracts, and retries
+// once with stronger guidance when the item count falls well short of the hints.
+func (e *hintsExtractor) Extract(ctx context.Context, content string, s schema.Schema) (*extractor.Result, error) {
+	e.applied = nil
+
+	// Detection works best on the raw HTML; fall back to the cleaned content
+	pageURL, html := e.page.get()
+	source := html
+	if source == "" {
+		source = content
+	}
+
+	hints, _ := e.chain.ProcessWithURL(source, pageURL)
+	section := hints.ToExtractionPromptSection()
+	if section == "" {
+		return e.inner.Extract(ctx, content, s)
+	}
+	e.applied = hints.ToMap()
+
+	result, err := e.inner.Extract(ctx, content, withPromptGuidance(s, section))
+	if err != nil || result == nil || result.IsTruncated() {
+		// Truncated output is handled by model fallback, not an item-count retry
+		return result, err
+	}
+
+	var retry *extractor.Result
+	better := itemCountRetry(hints, &s, result.Data, e.applied, e.logger, pageURL, func(guidance string) (any, error) {
+		r, err := e.inner.Extract(ctx, content, withPromptGuidance(s, section+guidance))
+		if r != nil {
+			retry = r
+		}
+		if err != nil || r == nil || r.IsTruncated() {
+			return nil, err
+		}
+		return r.Data, nil
+	})
+	if retry == nil {
+		return result, nil
+	}
+
+	// Both calls are billed, so report their combined usage on whichever result is
+	// kept. Cost is only reported when both calls included it; otherwise billing
+	// prices the combined tokens.
+	kept := result
+	if better != nil {
+		kept = retry
+	}
+	cost, costIncluded := result.Cost+retry.Cost, result.CostIncluded && retry.CostIncluded
+	kept.Usage = extractor.Usage{
+		InputTokens:  result.Usage.InputTokens + retry.Usage.InputTokens,
+		OutputTokens: result.Usage.OutputTokens + retry.Usage.OutputTokens,
+	}
+	kept.Duration = result.Duration + retry.Duration
+	kept.Cost, kept.CostIncluded = 0, costIncluded
+	if costIncluded {
+		kept.Cost = cost
+	}
+	// Two generations, so the cost can't be looked up by one generation ID
+	kept.GenerationID = ""
+	return kept, nil
+}
+
+// itemCountRetry checks an extraction against the number of items the hints detected
+// on the page and retries once when far too few came back. With a schema, items are
+// counted in the array field matching a detected type; without one (prompt
+// extractions), the largest extracted list is compared with the largest detected
+// count. retry runs the extraction again with guidance added to the prompt and
+// returns its data (nil if unusable). Returns the retry's data when it found more
+// items, or nil to keep the original. The counts are recorded in applied.
+func itemCountRetry(hints *preprocessor.Hints, s *schema.Schema, data any, applied map[string]string, logger *slog.Logger, pageURL string, retry func(guidance string) (any, error)) any {
+	field, expected := "", hints.MaxDetectedCount()
+	count := largestArrayLen
+	if s != nil {
+		field, expected = expectedItemCount(hints, *s)
+		count = func(data any) int { return countExtractedItems(data, field) }
+	}
+	if expected < constants.HintItemCountMinExpected {
+		return nil
+	}
+
+	got := count(data)
+	applied["item_count_expected"] = strconv.Itoa(expected)
+	applied["item_count_extracted"] = strconv.Itoa(got)
+	if float64(got) >= float64(expected)*constants.HintItemCountRetryRatio {
+		return nil
+	}
+
+	logger.Info("extraction returned too few items, retrying with item-count guidance",
+		"url", pageURL,
+		"field", field,
+		"expected", expected,
+		"extracted", got,
+	)
+
+	items := "items"
+	if field != "" {
+		items += " for `" + field + "`"
+	}
+	retryData, err := retry("\n## Item Count Check\n" +
+		"A previous extraction of this page returned only " + strconv.Itoa(got) + " of approximately " +
+		strconv.Itoa(expected) + " " + items + ". Extract EVERY item on the page.\n")
+	if err != nil {
+		// Keep the first result - it was valid, just incomplete
+		logger.Warn("item-count retry failed, keeping original result", "url", pageURL, "error", err)
+		return nil
+	}
+	applied["item_count_retried"] = "true"
+
+	if retryGot := count(retryData); retryData != nil && retryGot > got {
+		applied["item_count_extracted"] = strconv.Itoa(retryGot)
+		return retryData
+	}
+	return nil
+}
+
+// withPromptGuidance returns a copy of the schema with guidance appended to its
+// description, which refyne includes in the extraction prompt.
+func withPromptGuidance(s schema.Schema, guidance string) schema.Schema {
+	s.Description = strings.TrimSpace(s.Description + "\n" + guidance)
+	return s
+}
+
+// expectedItemCount matches detected content types against the schema's top-level
+// array fields. Returns the matched field name and the detected item count, or
+// ("", 0) when no array field corresponds to a detected type.
+func expectedItemCount(hints *preprocessor.Hints, s schema.Schema) (string, int) {
+	if hints == nil || len(hints.DetectedTypes) == 0 {
+		return "", 0
+	}
+
+	var arrayFields []string
+	for _, f := range s.Fields {
+		if f.Type == schema.TypeArray {
+			arrayFields = append(arrayFields, f.Name)
+		}
+	}
+	if len(arrayFields) == 0 {
+		return "", 0
+	}
+
+	bestField, bestCount := "", 0
+	for _, dt := range hints.DetectedTypes {
+		for _, name := range arrayFields {
+			if itemNamesMatch(name, dt.Name) && dt.Count > bestCount {
+				bestField, bestCount = name, dt.Count
+			}
+		}
+	}
+	if bestField != "" {
+		return bestField, bestCount
+	}
+
+	// A single list in both the schema and the page is unambiguous even if names differ
+	if len(arrayFields) == 1 && len(hints.DetectedTypes) == 1 {
+		return arrayFields[0], hints.DetectedTypes[0].Count
+	}
+	return "", 0
+}
+
+// itemNamesMatch reports whether a schema field name refers to a detected content
+// type (e.g., "product_list" and "products", or "members" and "team_members").
+func itemNamesMatch(fieldName, typeName string) bool {
+	field := strings.TrimSuffix(strings.ReplaceAll(strings.ToLower(fieldName), "_", ""), "s")
+	detected := strings.TrimSuffix(strings.ReplaceAll(strings.ToLower(typeName), "_", ""), "s")
+	if field == "" || detected == "" {
+		return false
+	}
+	return strings.Contains(field, detected) || strings.Contains(detected, field)
+}
+
+// countExtractedItems returns the number of items in the named array field of the
+// extracted data. A top-level array is counted directly.
+func countExtractedItems(data any, field string) int {
+	switch v := data.(type) {
+	case []any:
+		return len(v)
+	case map[string]any:
+		if items, ok := v[field].([]any); ok {
+			return len(items)
+		}
+	}
+	return 0
+}
+
+// largestArrayLen returns the length of the largest list in the extracted data,
+// checking a top-level array and the top-level fields of an object.
+func largestArrayLen(data any) int {
+	switch v := data.(type) {
+	case []any:
+		return len(v)
+	case map[string]any:
+		largest := 0
+		for _, value := range v {
+			if items, ok := value.([]any); ok && len(items) > largest {
+				largest = len(items)
+			}
+		}
+		return largest
+	}
+	return 0
+}
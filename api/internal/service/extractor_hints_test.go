@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/preprocessor"
+)
+
+// sequenceExtractor returns results (and errors) in order, recording each schema it
+// was called with.
+type sequenceExtractor struct {
+	results []*extractor.Result
+	errs    []error
+	schemas []schema.Schema
+}
+
+func (s *sequenceExtractor) Extract(_ context.Context, _ string, sch schema.Schema) (*extractor.Result, error) {
+	s.schemas = append(s.schemas, sch)
+	i := len(s.schemas) - 1
+	var err error
+	if i < len(s.errs) {
+		err = s.errs[i]
+	}
+	return s.results[i], err
+}
+
+func (s *sequenceExtractor) Name() string    { return "sequence" }
+func (s *sequenceExtractor) Available() bool { return true }
+
+// productListingPage builds a listing page with n product cards.
+func productListingPage(n int) string {
+	var b strings.Builder
+	b.WriteString("<html><body><ul>")
+	for range n {
+		b.WriteString(`<li class="product-card"><h2>Widget</h2><span class="price">$10</span></li>`)
+	}
+	b.WriteString("</ul></body></html>")
+	return b.String()
+}
+
+// productItems builds an extracted products array with n items.
+func productItems(n int) map[string]any {
+	items := make([]any, n)
+	for i := range items {
+		items[i] = map[string]any{"name": "Widget"}
+	}
+	return map[string]any{"products": items}
+}
+
+var productListSchema = schema.Schema{Fields: []schema.Field{
+	{Name: "products", Type: schema.TypeArray, Items: &schema.Field{Type: schema.TypeObject}},
+}}
+
+func newHintsTestExtractor(t *testing.T, html string, inner extractor.Extractor) *hintsExtractor {
+	t.Helper()
+	chain, err := NewPreprocessorFactory().CreateChain(nil)
+	if err != nil {
+		t.Fatalf("CreateChain() error = %v", err)
+	}
+	page := &pageCapture{}
+	page.set("https://example.com/products", html, "text/html")
+	e := newHintsExtractor(chain, slog.Default())
+	e.decorate(inner, page)
+	return e
+}
+
+func TestHintsExtractor_AddsGuidanceToSchema(t *testing.T) {
+	inner := &sequenceExtractor{results: []*extractor.Result{{Data: productItems(12)}}}
+	e := newHintsTestExtractor(t, productListingPage(12), inner)
+
+	if _, err := e.Extract(context.Background(), "content", productListSchema); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(inner.schemas) != 1 {
+		t.Fatalf("LLM extractor called %d times, want 1", len(inner.schemas))
+	}
+	if !strings.Contains(inner.schemas[0].Description, "Extract ALL 12 products") {
+		t.Errorf("schema description should contain item-count guidance, got %q", inner.schemas[0].Description)
+	}
+	if e.applied["detected_products"] != "12" {
+		t.Errorf("applied hints = %v, want detected_products=12", e.applied)
+	}
+}
+
+func TestHintsExtractor_RetriesWhenTooFewItems(t *testing.T) {
+	inner := &sequenceExtractor{results: []*extractor.Result{
+		{Data: productItems(3), Usage: extractor.Usage{InputTokens: 1000, OutputTokens: 50}},
+		{Data: productItems(20), Usage: extractor.Usage{InputTokens: 1100, OutputTokens: 400}},
+	}}
+	e := newHintsTestExtractor(t, productListingPage(20), inner)
+
+	result, err := e.Extract(context.Background(), "content", productListSchema)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(inner.schemas) != 2 {
+		t.Fatalf("LLM extractor called %d times, want 2", len(inner.schemas))
+	}
+	if !strings.Contains(inner.schemas[1].Description, "returned only 3 of approximately 20") {
+		t.Errorf("retry guidance missing, got %q", inner.schemas[1].Description)
+	}
+	if got := countExtractedItems(result.Data, "products"); got != 20 {
+		t.Errorf("kept result has %d items, want 20", got)
+	}
+	if result.Usage.InputTokens != 2100 || result.Usage.OutputTokens != 450 {
+		t.Errorf("Usage = %+v, want combined usage of both calls", result.Usage)
+	}
+	if e.applied["item_count_retried"] != "true" {
+		t.Errorf("applied hints = %v, want item_count_retried", e.applied)
+	}
+}
+
+func TestHintsExtractor_KeepsOriginalWhenRetryIsNotBetter(t *testing.T) {
+	inner := &sequenceExtractor{results: []*extractor.Result{
+		{Data: productItems(3), Usage: extractor.Usage{InputTokens: 1000, OutputTokens: 50}},
+		{Data: productItems(2), Usage: extractor.Usage{InputTokens: 1000, OutputTokens: 40}},
+	}}
+	e := newHintsTestExtractor(t, productListingPage(20), inner)
+
+	result, err := e.Extract(context.Background(), "content", productListSchema)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if got := countExtractedItems(result.Data, "products"); got != 3 {
+		t.Errorf("kept result has %d items, want original 3", got)
+	}
+	if result.Usage.InputTokens != 2000 || result.Usage.OutputTokens != 90 {
+		t.Errorf("Usage = %+v, want combined usage of both calls", result.Usage)
+	}
+}
+
+func TestHintsExtractor_FailedRetryIsStillBilled(t *testing.T) {
+	inner := &sequenceExtractor{
+		results: []*extractor.Result{
+			{Data: productItems(3), Usage: extractor.Usage{InputTokens: 1000, OutputTokens: 50}, Cost: 0.01, CostIncluded: true},
+			{Usage: extractor.Usage{InputTokens: 1000, OutputTokens: 20}},
+		},
+		errs: []error{nil, errors.New("schema validation failed")},
+	}
+	e := newHintsTestExtractor(t, productListingPage(20), inner)
+
+	result, err := e.Extract(context.Background(), "content", productListSchema)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if got := countExtractedItems(result.Data, "products"); got != 3 {
+		t.Errorf("kept result has %d items, want original 3", got)
+	}
+	if result.Usage.InputTokens != 2000 || result.Usage.OutputTokens != 70 {
+		t.Errorf("Usage = %+v, want the failed retry's usage included", result.Usage)
+	}
+	// Only the first call reported its cost, so billing must price the tokens
+	if result.CostIncluded || result.Cost != 0 {
+		t.Errorf("Cost = %v (included %v), want it left to billing", result.Cost, result.CostIncluded)
+	}
+}
+
+func TestHintsExtractor_SumsIncludedCosts(t *testing.T) {
+	inner := &sequenceExtractor{results: []*extractor.Result{
+		{Data: productItems(3), Cost: 0.01, CostIncluded: true, GenerationID: "gen-1"},
+		{Data: productItems(20), Cost: 0.02, CostIncluded: true, GenerationID: "gen-2"},
+	}}
+	e := newHintsTestExtractor(t, productListingPage(20), inner)
+
+	result, err := e.Extract(context.Background(), "content", productListSchema)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if !result.CostIncluded || math.Abs(result.Cost-0.03) > 1e-9 || result.GenerationID != "" {
+		t.Errorf("Cost = %v (included %v, generation %q), want 0.03 for both calls", result.Cost, result.CostIncluded, result.GenerationID)
+	}
+}
+
+func TestHintsExtractor_NoRetryWhenTruncated(t *testing.T) {
+	inner := &sequenceExtractor{results: []*extractor.Result{
+		{Data: productItems(3), FinishReason: "length"},
+	}}
+	e := newHintsTestExtractor(t, productListingPage(20), inner)
+
+	result, err := e.Extract(context.Background(), "content", productListSchema)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(inner.schemas) != 1 {
+		t.Errorf("LLM extractor called %d times, want 1 (truncation is handled by model fallback)", len(inner.schemas))
+	}
+	if !result.IsTruncated() {
+		t.Error("truncated result should be returned unchanged")
+	}
+}
+
+func TestHintsExtractor_NoHintsPassesThrough(t *testing.T) {
+	inner := &sequenceExtractor{results: []*extractor.Result{{Data: map[string]any{"title": "Hello"}}}}
+	e := newHintsTestExtractor(t, "<html><body><h1>Hello</h1></body></html>", inner)
+
+	sch := schema.Schema{Description: "A page", Fields: []schema.Field{{Name: "title", Type: schema.TypeString}}}
+	if _, err := e.Extract(context.Background(), "# Hello", sch); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if inner.schemas[0].Description != "A page" {
+		t.Errorf("Description = %q, want unchanged", inner.schemas[0].Description)
+	}
+	if e.applied != nil {
+		t.Errorf("applied = %v, want nil", e.applied)
+	}
+}
+
+func TestItemCountRetry_WithoutSchema(t *testing.T) {
+	hints := &preprocessor.Hints{DetectedTypes: []preprocessor.DetectedContentType{
+		{Name: "products", Count: 24},
+		{Name: "reviews", Count: 6},
+	}}
+
+	// Without a schema the largest list is compared with the largest detected count
+	var guidance string
+	applied := map[string]string{}
+	got := itemCountRetry(hints, nil, productItems(4), applied, slog.Default(), "https://example.com", func(g string) (any, error) {
+		guidance = g
+		return productItems(24), nil
+	})
+	if countExtractedItems(got, "products") != 24 {
+		t.Errorf("itemCountRetry() = %v, want the retry's 24 items", got)
+	}
+	if !strings.Contains(guidance, "returned only 4 of approximately 24 items.") {
+		t.Errorf("retry guidance = %q", guidance)
+	}
+	if applied["item_count_expected"] != "24" || applied["item_count_extracted"] != "24" || applied["item_count_retried"] != "true" {
+		t.Errorf("applied hints = %v", applied)
+	}
+
+	// Enough items: no retry
+	retried := false
+	got = itemCountRetry(hints, nil, productItems(20), map[string]string{}, slog.Default(), "https://example.com", func(string) (any, error) {
+		retried = true
+		return nil, nil
+	})
+	if got != nil || retried {
+		t.Errorf("itemCountRetry() = %v, retried = %v, want no retry", got, retried)
+	}
+}
+
+func TestExpectedItemCount(t *testing.T) {
+	hints := func(types ...preprocessor.DetectedContentType) *preprocessor.Hints {
+		h := preprocessor.NewHints()
+		h.DetectedTypes = types
+		return h
+	}
+	arrayField := func(name string) schema.Field {
+		return schema.Field{Name: name, Type: schema.TypeArray}
+	}
+
+	tests := []struct {
+		name         string
+		hints        *preprocessor.Hints
+		fields       []schema.Field
+		wantField    string
+		wantExpected int
+	}{
+		{
+			name:         "matching name",
+			hints:        hints(preprocessor.DetectedContentType{Name: "products", Count: 48}),
+			fields:       []schema.Field{{Name: "title", Type: schema.TypeString}, arrayField("products")},
+			wantField:    "products",
+			wantExpected: 48,
+		},
+		{
+			name:         "singular and compound names",
+			hints:        hints(preprocessor.DetectedContentType{Name: "team_members", Count: 9}),
+			fields:       []schema.Field{arrayField("members")},
+			wantField:    "members",
+			wantExpected: 9,
+		},
+		{
+			name: "picks the matching type on mixed pages",
+			hints: hints(
+				preprocessor.DetectedContentType{Name: "products", Count: 24},
+				preprocessor.DetectedContentType{Name: "reviews", Count: 6},
+			),
+			fields:       []schema.Field{arrayField("reviews")},
+			wantField:    "reviews",
+			wantExpected: 6,
+		},
+		{
+			name:         "single list with different names",
+			hints:        hints(preprocessor.DetectedContentType{Name: "items", Count: 15}),
+			fields:       []schema.Field{arrayField("listings")},
+			wantField:    "listings",
+			wantExpected: 15,
+		},
+		{
+			name: "ambiguous lists",
+			hints: hints(
+				preprocessor.DetectedContentType{Name: "products", Count: 24},
+				preprocessor.DetectedContentType{Name: "articles", Count: 6},
+			),
+			fields:       []schema.Field{arrayField("listings")},
+			wantExpected: 0,
+		},
+		{
+			name:         "no array fields",
+			hints:        hints(preprocessor.DetectedContentType{Name: "products", Count: 48}),
+			fields:       []schema.Field{{Name: "title", Type: schema.TypeString}},
+			wantExpected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, expected := expectedItemCount(tt.hints, schema.Schema{Fields: tt.fields})
+			if field != tt.wantField || expected != tt.wantExpected {
+				t.Errorf("expectedItemCount() = (%q, %d), want (%q, %d)", field, expected, tt.wantField, tt.wantExpected)
+			}
+		})
+	}
+}
+
+func TestPreprocessorFactory_CreateChain(t *testing.T) {
+	factory := NewPreprocessorFactory()
+
+	chain, err := factory.CreateChain(nil)
+	if err != nil {
+		t.Fatalf("CreateChain(nil) error = %v", err)
+	}
+	if chain.Name() != "chain(hint_repeats->hint_feedback)" {
+		t.Errorf("default chain = %s, want hint_repeats->hint_feedback", chain.Name())
+	}
+
+	chain, err = factory.CreateChain([]PreprocessorConfig{{Name: "noop"}})
+	if err != nil {
+		t.Fatalf("CreateChain(noop) error = %v", err)
+	}
+	hints, _ := chain.Process(productListingPage(20))
+	if hints.ToExtractionPromptSection() != "" {
+		t.Error("noop chain should not produce hints")
+	}
+
+	chain, err = factory.CreateChain([]PreprocessorConfig{{Name: "hint_repeats", Options: &PreprocessorOptions{MinRepeats: 50}}})
+	if err != nil {
+		t.Fatalf("CreateChain(hint_repeats) error = %v", err)
+	}
+	hints, _ = chain.Process(productListingPage(20))
+	if len(hints.DetectedTypes) != 0 {
+		t.Errorf("min_repeats=50 should suppress 20 products, got %v", hints.DetectedTypes)
+	}
+
+	if _, err := factory.CreateChain([]PreprocessorConfig{{Name: "unknown"}}); err == nil {
+		t.Error("expected error for unknown preprocessor")
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/constants"
+)
+
+// NetworkCaptureConfig records the XHR/fetch responses a page makes while it is
+// rendered. Many single-page apps load their data from JSON APIs, which is cheaper
+// and more accurate to read than the rendered HTML. Capturing implies browser rendering.
+type NetworkCaptureConfig struct {
+	// URLPatterns are regular expressions; responses whose URL matches one are recorded.
+	URLPatterns []string `json:"url_patterns,omitempty"`
+
+	// ContentTypes are MIME type substrings (e.g., "json"); matching responses are recorded.
+	// With no URL patterns or content types, JSON responses are recorded.
+	ContentTypes []string `json:"content_types,omitempty"`
+
+	// MaxResponses caps the number of responses recorded per page (default 50).
+	MaxResponses int `json:"max_responses,omitempty"`
+
+	// IncludeInPrompt adds the captured responses to the extraction prompt as a
+	// separate section (default true). When false they are only kept for debug capture.
+	IncludeInPrompt *bool `json:"include_in_prompt,omitempty"`
+}
+
+// captchaConfig returns the capture settings sent to the captcha service.
+func (c *NetworkCaptureConfig) captchaConfig() *captcha.NetworkCapture {
+	if c == nil {
+		return nil
+	}
+	return &captcha.NetworkCapture{
+		URLPatterns:  c.URLPatterns,
+		ContentTypes: c.ContentTypes,
+		MaxResponses: c.MaxResponses,
+	}
+}
+
+// includeInPrompt reports whether captured responses are added to the prompt.
+func (c *NetworkCaptureConfig) includeInPrompt() bool {
+	return c != nil && (c.IncludeInPrompt == nil || *c.IncludeInPrompt)
+}
+
+// networkPromptSection formats captured responses as a prompt section, fitting as
+// many as possible into constants.NetworkPromptMaxSize. A response too large for the
+// remaining space is truncated to fit. Returns "" if none fit.
+func networkPromptSection(responses []captcha.NetworkResponse) string {
+	const header = "\n\n## Captured API Responses\n" +
+		"The page loaded these responses from its own APIs while rendering. " +
+		"Prefer them over the page text when they contain the requested data.\n"
+	const truncated = "\n[Response truncated...]"
+
+	var b strings.Builder
+	included := 0
+	for _, r := range responses {
+		body := strings.TrimSpace(r.Body)
+		if r.Status >= 400 || body == "" {
+			continue
+		}
+		framing := len(fmt.Sprintf("\n### %s %s\n```\n\n```\n", r.Method, r.URL))
+		remaining := constants.NetworkPromptMaxSize - len(header) - b.Len() - framing
+		if len(body) > remaining {
+			if remaining <= len(truncated) {
+				continue
+			}
+			body = truncateUTF8(body, remaining-len(truncated)) + truncated
+		}
+		fmt.Fprintf(&b, "\n### %s %s\n```\n%s\n```\n", r.Method, r.URL, body)
+		included++
+	}
+	if included == 0 {
+		return ""
+	}
+	return header + b.String()
+}
+
+// truncateUTF8 returns the longest prefix of s that is at most n bytes and does not
+// split a multi-byte character.
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// networkCaptureExtractor is an extractor decorator that adds the XHR/fetch responses
+// captured while rendering the page to the content sent to the LLM.
+type networkCaptureExtractor struct {
+	decoratedExtractor
+	page   *pageCapture
+	prompt bool
+}
+
+// newNetworkCaptureExtractor creates a network capture decorator for one extraction.
+func newNetworkCaptureExtractor(cfg *NetworkCaptureConfig) *networkCaptureExtractor {
+	return &networkCaptureExtractor{prompt: cfg.includeInPrompt()}
+}
+
+// decorate implements extractorDecorator.
+func (e *networkCaptureExtractor) decorate(inner extractor.Extractor, page *pageCapture) extractor.Extractor {
+	e.inner = inner
+	e.page = page
+	return e
+}
+
+// Name returns the extractor name.
+func (e *networkCaptureExtractor) Name() string {
+	return "network+" + e.inner.Name()
+}
+
+// Extract appends the captured responses to the content, trimming the page content
+// so both fit in the LLM's content limit.
+func (e *networkCaptureExtractor) Extract(ctx context.Context, content string, s schema.Schema) (*extractor.Result, error) {
+	if !e.prompt {
+		return e.inner.Extract(ctx, content, s)
+	}
+	section := networkPromptSection(e.page.networkResponses())
+	if section == "" {
+		return e.inner.Extract(ctx, content, s)
+	}
+	if len(content) > constants.NetworkPromptMaxPageSize {
+		content = truncateUTF8(content, constants.NetworkPromptMaxPageSize) + "\n\n[Content truncated...]"
+	}
+	return e.inner.Extract(ctx, content+section, s)
+}
+
+// responses returns the responses captured for the most recently fetched page.
+func (e *networkCaptureExtractor) responses() []captcha.NetworkResponse {
+	if e.page == nil {
+		return nil
+	}
+	return e.page.networkResponses()
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/constants"
+)
+
+func TestNetworkCaptureConfig_IncludeInPrompt(t *testing.T) {
+	no := false
+	yes := true
+
+	tests := []struct {
+		name string
+		cfg  *NetworkCaptureConfig
+		want bool
+	}{
+		{"nil config", nil, false},
+		{"default", &NetworkCaptureConfig{}, true},
+		{"explicit true", &NetworkCaptureConfig{IncludeInPrompt: &yes}, true},
+		{"explicit false", &NetworkCaptureConfig{IncludeInPrompt: &no}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.includeInPrompt(); got != tt.want {
+				t.Errorf("includeInPrompt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNetworkCaptureConfig_CaptchaConfig(t *testing.T) {
+	var nilCfg *NetworkCaptureConfig
+	if got := nilCfg.captchaConfig(); got != nil {
+		t.Errorf("captchaConfig() on nil = %+v, want nil", got)
+	}
+
+	got := (&NetworkCaptureConfig{URLPatterns: []string{"/api/"}, ContentTypes: []string{"json"}, MaxResponses: 10}).captchaConfig()
+	if len(got.URLPatterns) != 1 || len(got.ContentTypes) != 1 || got.MaxResponses != 10 {
+		t.Errorf("captchaConfig() = %+v", got)
+	}
+}
+
+func TestNetworkPromptSection(t *testing.T) {
+	t.Run("no usable responses", func(t *testing.T) {
+		section := networkPromptSection([]captcha.NetworkResponse{
+			{Method: "GET", URL: "https://example.com/api/missing", Status: 404, Body: `{"error":"not found"}`},
+			{Method: "GET", URL: "https://example.com/api/empty", Status: 200, Body: "  "},
+		})
+		if section != "" {
+			t.Errorf("section = %q, want empty", section)
+		}
+	})
+
+	t.Run("includes successful responses", func(t *testing.T) {
+		section := networkPromptSection([]captcha.NetworkResponse{
+			{Method: "GET", URL: "https://example.com/api/products", Status: 200, Body: `{"items":[1,2]}`},
+			{Method: "POST", URL: "https://example.com/api/error", Status: 500, Body: `{"error":"boom"}`},
+		})
+		if !strings.Contains(section, "## Captured API Responses") {
+			t.Errorf("section missing header: %q", section)
+		}
+		if !strings.Contains(section, "### GET https://example.com/api/products") || !strings.Contains(section, `{"items":[1,2]}`) {
+			t.Errorf("section missing product response: %q", section)
+		}
+		if strings.Contains(section, "/api/error") {
+			t.Errorf("section includes failed response: %q", section)
+		}
+	})
+
+	t.Run("truncates responses to the size limit", func(t *testing.T) {
+		large := strings.Repeat("é", constants.NetworkPromptMaxSize)
+		section := networkPromptSection([]captcha.NetworkResponse{
+			{Method: "GET", URL: "https://example.com/api/small", Status: 200, Body: `{"ok":true}`},
+			{Method: "GET", URL: "https://example.com/api/large", Status: 200, Body: large},
+			{Method: "GET", URL: "https://example.com/api/after", Status: 200, Body: `{"ok":false}`},
+		})
+		if len(section) > constants.NetworkPromptMaxSize {
+			t.Errorf("len(section) = %d, want <= %d", len(section), constants.NetworkPromptMaxSize)
+		}
+		if !utf8.ValidString(section) {
+			t.Error("section splits a multi-byte character")
+		}
+		if !strings.Contains(section, "/api/small") || !strings.Contains(section, "/api/large") {
+			t.Errorf("expected the small and truncated large responses, got %q", section[:min(len(section), 200)])
+		}
+		if !strings.Contains(section, "[Response truncated...]") || strings.Contains(section, "/api/after") {
+			t.Error("large response should be truncated to fill the remaining space")
+		}
+	})
+}
+
+func TestTruncateUTF8(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 3, "hel"},
+		{"héllo", 2, "h"},
+		{"héllo", 3, "hé"},
+		{"日本", 4, "日"},
+		{"日本", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := truncateUTF8(tt.s, tt.n); got != tt.want {
+			t.Errorf("truncateUTF8(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkCaptureExtractor(t *testing.T) {
+	responses := []captcha.NetworkResponse{
+		{Method: "GET", URL: "https://example.com/api/products", Status: 200, Body: `{"items":[1,2]}`},
+	}
+	sch := schema.Schema{Fields: []schema.Field{{Name: "name", Type: "string"}}}
+
+	t.Run("appends captured responses and trims page content", func(t *testing.T) {
+		page := &pageCapture{}
+		page.setNetwork(responses)
+		inner := &stubExtractor{}
+		ext := newNetworkCaptureExtractor(&NetworkCaptureConfig{}).decorate(inner, page)
+
+		content := strings.Repeat("p", constants.NetworkPromptMaxPageSize+100)
+		result, err := ext.Extract(context.Background(), content, sch)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if !strings.Contains(result.RawContent, "### GET https://example.com/api/products") {
+			t.Error("content sent to LLM is missing the captured response")
+		}
+		if strings.Count(result.RawContent, "p") < constants.NetworkPromptMaxPageSize || strings.Contains(result.RawContent, content) {
+			t.Error("page content was not trimmed to NetworkPromptMaxPageSize")
+		}
+		if ext.Name() != "network+stub" {
+			t.Errorf("Name() = %q", ext.Name())
+		}
+	})
+
+	t.Run("trims multi-byte page content on a character boundary", func(t *testing.T) {
+		page := &pageCapture{}
+		page.setNetwork(responses)
+		ext := newNetworkCaptureExtractor(&NetworkCaptureConfig{}).decorate(&stubExtractor{}, page)
+
+		// "x" offsets the three-byte characters so the byte limit falls inside one
+		content := "x" + strings.Repeat("日", constants.NetworkPromptMaxPageSize/3+10)
+		result, err := ext.Extract(context.Background(), content, sch)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if !utf8.ValidString(result.RawContent) {
+			t.Error("trimmed content splits a multi-byte character")
+		}
+	})
+
+	t.Run("include_in_prompt false leaves content unchanged", func(t *testing.T) {
+		no := false
+		page := &pageCapture{}
+		page.setNetwork(responses)
+		inner := &stubExtractor{}
+		netExt := newNetworkCaptureExtractor(&NetworkCaptureConfig{IncludeInPrompt: &no})
+		ext := netExt.decorate(inner, page)
+
+		result, err := ext.Extract(context.Background(), "page", sch)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if result.RawContent != "page" {
+			t.Errorf("content = %q, want unchanged", result.RawContent)
+		}
+		if len(netExt.responses()) != 1 {
+			t.Errorf("responses() = %d, want 1 kept for debug capture", len(netExt.responses()))
+		}
+	})
+}
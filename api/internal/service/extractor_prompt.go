@@ -9,20 +9,31 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/jmylchreest/refyne/pkg/fetcher"
 	"github.com/jmylchreest/refyne/pkg/refyne"
 
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/documents"
 	"github.com/jmylchreest/refyne-api/internal/llm"
+	"github.com/jmylchreest/refyne-api/internal/protection"
 )
 
 // PromptPageExtractor extracts data from a single page using a freeform prompt.
 // It handles fetch + LLM call with dynamic retry support for bot protection
 // detection and insufficient content errors.
 type PromptPageExtractor struct {
-	svc          *ExtractionService
-	promptText   string
-	llmCfg       *LLMConfigInput
-	cleanerChain []CleanerConfig
-	isBYOK       bool
+	svc           *ExtractionService
+	promptText    string
+	llmCfg        *LLMConfigInput
+	cleanerChain  []CleanerConfig
+	preprocessors []PreprocessorConfig
+	isBYOK        bool
+	content       *SubmittedContent
+	actions       []captcha.Action
+	network       *NetworkCaptureConfig
+	fetchOptions  *FetchOptions
+	auth          *authSession
 
 	// Context for dynamic retry
 	contentDynamicAllowed bool
@@ -34,12 +45,19 @@ type PromptPageExtractor struct {
 // NewPromptPageExtractor creates a new prompt-based page extractor.
 func NewPromptPageExtractor(svc *ExtractionService, opts PromptExtractorOptions) *PromptPageExtractor {
 	return &PromptPageExtractor{
-		svc:                   svc,
-		promptText:            opts.PromptText,
-		llmCfg:                opts.LLMConfig,
-		cleanerChain:          opts.CleanerChain,
-		isBYOK:                opts.IsBYOK,
-		contentDynamicAllowed: opts.ContentDynamicAllowed,
+		svc:           svc,
+		promptText:    opts.PromptText,
+		llmCfg:        opts.LLMConfig,
+		cleanerChain:  opts.CleanerChain,
+		preprocessors: opts.Preprocessors,
+		isBYOK:        opts.IsBYOK,
+		content:       opts.Content,
+		actions:       opts.Actions,
+		network:       opts.Network,
+		fetchOptions:  opts.FetchOptions,
+		auth:          opts.Auth,
+		// Submitted content is never re-fetched with browser rendering
+		contentDynamicAllowed: opts.ContentDynamicAllowed && opts.Content == nil,
 		userID:                opts.UserID,
 		tier:                  opts.Tier,
 		jobID:                 opts.JobID,
@@ -55,12 +73,33 @@ func (e *PromptPageExtractor) Extract(ctx context.Context, pageURL string) (*Pag
 
 	effectiveFetchMode := "auto"
 	dynamicRetryAttempted := false
+	if e.content != nil {
+		effectiveFetchMode = "content"
+	} else if len(e.actions) > 0 || e.network != nil {
+		// Browser actions and network capture need a rendered page
+		effectiveFetchMode = "dynamic"
+	}
+
+	// Preprocessor chain generates hints (e.g., item counts) for the extraction prompt
+	hintsChain, err := NewPreprocessorFactory().CreateChain(e.preprocessors)
+	if err != nil {
+		result.Error = fmt.Errorf("invalid preprocessors: %w", err)
+		result.ErrorCategory = "config_error"
+		return result, result.Error
+	}
 
 extractAttempt:
 	// 1. Fetch and clean content (with fetch mode)
 	fetchStart := time.Now()
-	pageContent, fetchedURL, err := e.fetchAndCleanContentWithMode(ctx, pageURL, effectiveFetchMode)
-	result.FetchDurationMs = int(time.Since(fetchStart).Milliseconds())
+	var network []captcha.NetworkResponse
+	pageContent, rawHTML, fetchedURL, err := e.fetchAndCleanContentWithMode(ctx, pageURL, effectiveFetchMode, func(resp *CaptchaSolveOutput) {
+		network = resp.Solution.Network
+		result.BlockedRequests += resp.BlockedRequests
+		result.SolverCostUSD += resp.SolverCostUSD
+	})
+	if e.content == nil {
+		result.FetchDurationMs = int(time.Since(fetchStart).Milliseconds())
+	}
 	result.URL = fetchedURL
 
 	if err != nil {
@@ -98,12 +137,20 @@ extractAttempt:
 
 	result.RawContent = pageContent
 	result.UsedDynamicMode = effectiveFetchMode == "dynamic"
+	result.Network = network
 
 	// 2. Truncate content if too long
 	maxContentLen := 100000 // ~25k tokens roughly
+	networkSection := ""
+	if e.network.includeInPrompt() {
+		if networkSection = networkPromptSection(network); networkSection != "" {
+			maxContentLen = constants.NetworkPromptMaxPageSize
+		}
+	}
 	if len(pageContent) > maxContentLen {
 		pageContent = pageContent[:maxContentLen] + "\n\n[Content truncated...]"
 	}
+	pageContent += networkSection
 
 	// 3. Check for insufficient content after cleaning
 	minContentSize := 200
@@ -119,13 +166,21 @@ extractAttempt:
 			goto extractAttempt
 		}
 		err := fmt.Errorf("page has insufficient content (%d bytes) - likely requires JavaScript rendering", len(pageContent))
+		if e.content != nil {
+			err = fmt.Errorf("submitted content is too short (%d bytes after cleaning)", len(pageContent))
+		}
 		result.Error = err
 		result.ErrorCategory = "fetch_error"
 		return result, err
 	}
 
-	// 4. Build prompt and call LLM
-	extractPrompt := e.svc.buildPromptExtractionPrompt(pageContent, e.promptText)
+	// 4. Build prompt with preprocessor hints and call LLM
+	hints, _ := hintsChain.ProcessWithURL(rawHTML, fetchedURL)
+	hintsSection := hints.ToExtractionPromptSection()
+	if hintsSection != "" {
+		result.Hints = hints.ToMap()
+	}
+	extractPrompt := e.svc.buildPromptExtractionPrompt(pageContent, e.promptText, hintsSection)
 	llmClient := NewLLMClient(e.svc.logger, e.svc.resolver.GetRegistry())
 
 	// Estimate input tokens (~3.5 chars per token for English text, conservative)
@@ -171,12 +226,15 @@ extractAttempt:
 		}
 	}
 
-	llmResult, err := llmClient.Call(ctx, e.llmCfg, extractPrompt, LLMCallOptions{
+	callOpts := LLMCallOptions{
 		Temperature: 0.1,
 		MaxTokens:   maxTokens,
 		Timeout:     180 * time.Second,
 		JSONMode:    true,
-	})
+	}
+	firstCallOpts := callOpts
+	firstCallOpts.OnDelta = partialResultsWriter(e.svc.partialResults, e.jobID, pageURL)
+	llmResult, err := llmClient.Call(ctx, e.llmCfg, extractPrompt, firstCallOpts)
 
 	result.ExtractDurationMs = int(time.Since(startTime).Milliseconds()) - result.FetchDurationMs
 	result.Provider = e.llmCfg.Provider
@@ -215,6 +273,34 @@ extractAttempt:
 			"raw_response": llmResult.Content,
 			"parse_error":  "Response was not valid JSON",
 		}
+		result.Data = extractedData
+		return result, nil
+	}
+
+	// 7. Item-count check - retry once if far fewer items came back than were detected
+	if hintsSection != "" {
+		var retryContent string
+		retryData := itemCountRetry(hints, nil, extractedData, result.Hints, e.svc.logger, pageURL, func(guidance string) (any, error) {
+			retryResult, err := llmClient.Call(ctx, e.llmCfg, e.svc.buildPromptExtractionPrompt(pageContent, e.promptText, hintsSection+guidance), callOpts)
+			if err != nil {
+				return nil, err
+			}
+			// Both calls are billed
+			result.TokensInput += retryResult.InputTokens
+			result.TokensOutput += retryResult.OutputTokens
+
+			var data any
+			if retryResult.IsTruncated() || json.Unmarshal([]byte(retryResult.Content), &data) != nil {
+				return nil, nil
+			}
+			retryContent = retryResult.Content
+			return data, nil
+		})
+		if retryData != nil {
+			extractedData = retryData
+			result.RawLLMResponse = retryContent
+		}
+		result.ExtractDurationMs = int(time.Since(startTime).Milliseconds()) - result.FetchDurationMs
 	}
 
 	result.Data = extractedData
@@ -223,27 +309,103 @@ extractAttempt:
 }
 
 // fetchAndCleanContentWithMode fetches a URL and cleans the content, supporting different fetch modes.
+// Returns the cleaned content, the raw HTML (for preprocessing) and the final URL.
 // When mode is "dynamic", uses browser rendering via the captcha service.
 // When mode is "auto", uses protection-aware fetcher that detects bot protection.
-func (e *PromptPageExtractor) fetchAndCleanContentWithMode(ctx context.Context, targetURL, fetchMode string) (string, string, error) {
+// When mode is "content", cleans the submitted content without fetching.
+// onRender, if set, receives the captcha service response when the page is rendered in the browser.
+func (e *PromptPageExtractor) fetchAndCleanContentWithMode(ctx context.Context, targetURL, fetchMode string, onRender func(*CaptchaSolveOutput)) (string, string, string, error) {
+	cleanerChain := e.cleanerChain
+	if fetchMode == "content" {
+		cleanerChain = e.content.cleanerChain(cleanerChain)
+	}
+
 	// Create cleaner chain
 	factory := NewCleanerFactory()
-	contentCleaner, err := factory.CreateChainWithDefault(e.cleanerChain, DefaultExtractionCleanerChain)
+	contentCleaner, err := factory.CreateChainWithDefault(cleanerChain, DefaultExtractionCleanerChain)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid cleaner chain: %w", err)
+	}
+
+	// Documents are downloaded directly in every mode (browser rendering cannot return them)
+	if fetchMode != "content" && documents.FormatFromURL(targetURL) != "" {
+		fetchMode = "document"
+	}
+
+	// Send the auth profile's cookies, signing in again if the page redirects to the login page
+	var body []byte
+	var finalURL, contentType string
+	err = e.auth.fetch(ctx, targetURL, func(cookies []captcha.Cookie) (string, error) {
+		landedURL := ""
+		var err error
+		body, finalURL, contentType, err = e.fetchBody(ctx, targetURL, fetchMode, cookies, func(resp *CaptchaSolveOutput) {
+			landedURL = resp.Solution.URL
+			if onRender != nil {
+				onRender(resp)
+			}
+		})
+		if landedURL == "" {
+			landedURL = finalURL
+		}
+		return landedURL, err
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+
+	// Convert PDF, DOCX, XLSX and CSV responses to HTML for the cleaner chain
+	if fetchMode != "document" && fetchMode != "content" {
+		if format := documents.Detect(contentType, finalURL, body); format != "" {
+			content, err := convertDocument(fetcher.Content{URL: finalURL}, format, body, e.svc.logger)
+			if err != nil {
+				return "", "", "", err
+			}
+			body = []byte(content.HTML)
+		}
+	}
+
+	// Clean the content
+	cleanedContent, err := contentCleaner.Clean(string(body))
 	if err != nil {
-		return "", "", fmt.Errorf("invalid cleaner chain: %w", err)
+		// If cleaning fails, use raw content
+		e.svc.logger.Warn("content cleaning failed, using raw HTML", "error", err)
+		cleanedContent = string(body)
 	}
 
+	return cleanedContent, string(body), finalURL, nil
+}
+
+// fetchBody fetches a URL in the given fetch mode ("document" downloads it directly)
+// with cookies, returning the body, the final URL and the content type.
+// onRender, if set, receives the captcha service response when the page is rendered in the browser.
+func (e *PromptPageExtractor) fetchBody(ctx context.Context, targetURL, fetchMode string, cookies []captcha.Cookie, onRender func(*CaptchaSolveOutput)) ([]byte, string, string, error) {
 	var body []byte
-	var finalURL string
+	var finalURL, contentType string
+	var err error
 
 	switch fetchMode {
+	case "content":
+		body = []byte(e.content.Body)
+		finalURL = e.content.BaseURL
+		contentType = e.content.mimeType()
+
+	case "document":
+		proxies := e.svc.proxySvc.router(e.fetchOptions.proxyRequest(e.userID))
+		content, err := fetchDocument(ctx, targetURL, fetcher.Options{Cookies: fetcherCookies(cookies)}, proxies, e.svc.logger)
+		if err != nil {
+			return nil, "", "", err
+		}
+		body = []byte(content.HTML)
+		finalURL = content.URL
+		contentType = content.ContentType
+
 	case "dynamic":
 		// Use browser rendering via captcha service
 		if !e.contentDynamicAllowed {
-			return "", "", ErrDynamicFetchNotAllowed
+			return nil, "", "", ErrDynamicFetchNotAllowed
 		}
 		if e.svc.captchaSvc == nil {
-			return "", "", ErrDynamicFetchNotConfigured
+			return nil, "", "", ErrDynamicFetchNotConfigured
 		}
 
 		e.svc.logger.Info("using browser rendering for prompt extraction",
@@ -252,72 +414,113 @@ func (e *PromptPageExtractor) fetchAndCleanContentWithMode(ctx context.Context,
 			"job_id", e.jobID,
 		)
 
-		result, err := e.svc.captchaSvc.FetchDynamicContent(ctx, e.userID, e.tier, CaptchaSolveInput{
+		result, err := fetchDynamicContent(ctx, e.svc.captchaSvc, e.userID, e.tier, CaptchaSolveInput{
 			URL:        targetURL,
 			MaxTimeout: 60000,
+			Cookies:    cookies,
+			Actions:    e.actions,
+			Network:    e.network.captchaConfig(),
+			Resources:  e.fetchOptions.resourcePolicy(),
 			JobID:      e.jobID,
-		})
+		}, e.svc.proxySvc.router(e.fetchOptions.proxyRequest(e.userID)))
 		if err != nil {
-			return "", "", fmt.Errorf("dynamic fetch failed: %w", err)
+			return nil, "", "", fmt.Errorf("dynamic fetch failed: %w", err)
 		}
 		if result.Status != "ok" || result.Solution == nil {
-			return "", "", fmt.Errorf("browser rendering returned non-ok status: %s", result.Message)
+			return nil, "", "", fmt.Errorf("browser rendering returned non-ok status: %s", result.Message)
 		}
 		body = []byte(result.Solution.Response)
 		finalURL = targetURL // Browser service doesn't track redirects
+		if onRender != nil {
+			onRender(result)
+		}
 
 	case "auto", "":
 		// Use protection-aware fetching
 		var resp *http.Response
-		body, resp, err = e.fetchWithProtectionDetection(ctx, targetURL)
+		body, resp, err = e.fetchWithProtectionDetection(ctx, targetURL, cookies)
 		if err != nil {
-			return "", "", err
+			return nil, "", "", err
 		}
 		finalURL = resp.Request.URL.String()
+		contentType = resp.Header.Get("Content-Type")
 
 	default:
 		// Static mode - simple HTTP fetch
-		client := &http.Client{Timeout: 60 * time.Second}
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
-		if err != nil {
-			return "", "", fmt.Errorf("failed to create request: %w", err)
-		}
-		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
-		req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-
-		resp, err := client.Do(req)
+		var resp *http.Response
+		body, resp, _, err = e.fetchPage(ctx, targetURL, cookies)
 		if err != nil {
-			return "", "", fmt.Errorf("failed to fetch page: %w", err)
+			return nil, "", "", err
 		}
-		defer func() { _ = resp.Body.Close() }()
 
 		if resp.StatusCode != http.StatusOK {
-			return "", "", fmt.Errorf("page returned status %d", resp.StatusCode)
-		}
-
-		body, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return "", "", fmt.Errorf("failed to read response: %w", err)
+			return nil, "", "", fmt.Errorf("page returned status %d", resp.StatusCode)
 		}
 		finalURL = resp.Request.URL.String()
+		contentType = resp.Header.Get("Content-Type")
 	}
 
-	// Clean the content
-	cleanedContent, err := contentCleaner.Clean(string(body))
+	return body, finalURL, contentType, nil
+}
+
+// fetchWithProtectionDetection performs HTTP fetch with bot protection detection.
+// Returns the body, response (for URL tracking), and any error.
+func (e *PromptPageExtractor) fetchWithProtectionDetection(ctx context.Context, targetURL string, cookies []captcha.Cookie) ([]byte, *http.Response, error) {
+	body, resp, result, err := e.fetchPage(ctx, targetURL, cookies)
 	if err != nil {
-		// If cleaning fails, use raw content
-		e.svc.logger.Warn("content cleaning failed, using raw HTML", "error", err)
-		cleanedContent = string(body)
+		return nil, nil, err
+	}
+
+	// Check for bot protection signals using the protection detector
+	if result.Detected && result.SuggestDynamic {
+		return nil, nil, NewErrBotProtectionDetected(string(result.Signal), result.UserMessage())
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("page returned status %d", resp.StatusCode)
 	}
 
-	return cleanedContent, finalURL, nil
+	return body, resp, nil
 }
 
-// fetchWithProtectionDetection performs HTTP fetch with bot protection detection.
-// Returns the body, response (for URL tracking), and any error.
-func (e *PromptPageExtractor) fetchWithProtectionDetection(ctx context.Context, targetURL string) ([]byte, *http.Response, error) {
-	client := &http.Client{Timeout: 60 * time.Second}
+// fetchPage fetches a page over HTTP and checks it for bot protection signals.
+// When the request asks for a proxy, the page is fetched through proxies,
+// rotating to another proxy while responses are blocked.
+func (e *PromptPageExtractor) fetchPage(ctx context.Context, targetURL string, cookies []captcha.Cookie) ([]byte, *http.Response, protection.DetectionResult, error) {
+	proxies := e.svc.proxySvc.router(e.fetchOptions.proxyRequest(e.userID))
+	if proxies == nil {
+		body, resp, err := e.get(ctx, targetURL, cookies, nil)
+		if err != nil || e.svc.protectionDetector == nil {
+			return body, resp, protection.DetectionResult{}, err
+		}
+		return body, resp, e.svc.protectionDetector.DetectFromResponse(resp.StatusCode, resp.Header, body), nil
+	}
+
+	var body []byte
+	var resp *http.Response
+	result, err := proxies.fetch(ctx, targetURL, func(ctx context.Context, proxy *SelectedProxy) (proxyResponse, error) {
+		var err error
+		if body, resp, err = e.get(ctx, targetURL, cookies, proxy); err != nil {
+			return proxyResponse{}, err
+		}
+		return proxyResponse{StatusCode: resp.StatusCode, Headers: resp.Header, Body: body}, nil
+	})
+	if err != nil {
+		return nil, nil, protection.DetectionResult{}, err
+	}
+	return body, resp, result, nil
+}
+
+// get performs a browser-like GET request with cookies, through proxy when one is given.
+func (e *PromptPageExtractor) get(ctx context.Context, targetURL string, cookies []captcha.Cookie, proxy *SelectedProxy) ([]byte, *http.Response, error) {
+	client := &http.Client{Timeout: 60 * time.Second, Jar: cookieJar(targetURL, cookies)}
+	if proxy != nil {
+		// The transport is only used for this request; close its pooled connections
+		// so each proxied fetch doesn't leave an idle connection and goroutines behind
+		transport := &http.Transport{Proxy: http.ProxyURL(proxy.URL)}
+		defer transport.CloseIdleConnections()
+		client.Transport = transport
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create request: %w", err)
@@ -337,18 +540,5 @@ func (e *PromptPageExtractor) fetchWithProtectionDetection(ctx context.Context,
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read response: %w", err)
 	}
-
-	// Check for bot protection signals using the protection detector
-	if e.svc.protectionDetector != nil {
-		result := e.svc.protectionDetector.DetectFromResponse(resp.StatusCode, resp.Header, body)
-		if result.Detected && result.SuggestDynamic {
-			return nil, nil, NewErrBotProtectionDetected(string(result.Signal), result.UserMessage())
-		}
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, nil, fmt.Errorf("page returned status %d", resp.StatusCode)
-	}
-
 	return body, resp, nil
 }
@@ -3,19 +3,30 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/jmylchreest/refyne/pkg/refyne"
 	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 )
 
 // SchemaPageExtractor extracts data from a single page using a structured JSON schema.
 // It wraps the refyne library's Extract() method with dynamic retry support for bot
 // protection detection and insufficient content errors.
 type SchemaPageExtractor struct {
-	svc          *ExtractionService
-	schema       schema.Schema
-	llmCfg       *LLMConfigInput
-	cleanerChain []CleanerConfig
+	svc            *ExtractionService
+	schema         schema.Schema
+	llmCfg         *LLMConfigInput
+	cleanerChain   []CleanerConfig
+	preprocessors  []PreprocessorConfig
+	structuredData *StructuredDataConfig
+	content        *SubmittedContent
+	actions        []captcha.Action
+	network        *NetworkCaptureConfig
+	inputMode      InputMode
+	fetchOptions   *FetchOptions
+	auth           *authSession
 
 	// Context for dynamic retry
 	contentDynamicAllowed bool
@@ -27,11 +38,20 @@ type SchemaPageExtractor struct {
 // NewSchemaPageExtractor creates a new schema-based page extractor.
 func NewSchemaPageExtractor(svc *ExtractionService, sch schema.Schema, opts SchemaExtractorOptions) *SchemaPageExtractor {
 	return &SchemaPageExtractor{
-		svc:                   svc,
-		schema:                sch,
-		llmCfg:                opts.LLMConfig,
-		cleanerChain:          opts.CleanerChain,
-		contentDynamicAllowed: opts.ContentDynamicAllowed,
+		svc:            svc,
+		schema:         sch,
+		llmCfg:         opts.LLMConfig,
+		cleanerChain:   opts.CleanerChain,
+		preprocessors:  opts.Preprocessors,
+		structuredData: opts.StructuredData,
+		content:        opts.Content,
+		actions:        opts.Actions,
+		network:        opts.Network,
+		inputMode:      opts.InputMode,
+		fetchOptions:   opts.FetchOptions,
+		auth:           opts.Auth,
+		// Submitted content is never re-fetched with browser rendering
+		contentDynamicAllowed: opts.ContentDynamicAllowed && opts.Content == nil,
 		userID:                opts.UserID,
 		tier:                  opts.Tier,
 		jobID:                 opts.JobID,
@@ -47,16 +67,76 @@ func (e *SchemaPageExtractor) Extract(ctx context.Context, pageURL string) (*Pag
 	// Start with auto mode to enable protection detection
 	effectiveFetchMode := "auto"
 	dynamicRetryAttempted := false
+	cleanerChain := e.cleanerChain
+	if e.content != nil {
+		effectiveFetchMode = "content"
+		cleanerChain = e.content.cleanerChain(cleanerChain)
+	} else if len(e.actions) > 0 || e.network != nil || e.inputMode.UsesScreenshots() {
+		// Browser actions, network capture and screenshots need a rendered page
+		effectiveFetchMode = "dynamic"
+	}
+
+	// Preprocessor chain generates hints (e.g., item counts) for the extraction prompt
+	hintsChain, err := NewPreprocessorFactory().CreateChain(e.preprocessors)
+	if err != nil {
+		result.Error = fmt.Errorf("invalid preprocessors: %w", err)
+		result.ErrorCategory = "config_error"
+		return result, result.Error
+	}
 
 extractAttempt:
+	// Split long documents (PDF, DOCX, XLSX, CSV) into page-aligned chunks
+	documentExt := newDocumentChunkExtractor(e.svc.logger)
+
+	// Add preprocessor hints to the prompt and check item counts after extraction
+	hintsExt := newHintsExtractor(hintsChain, e.svc.logger)
+	decorators := []extractorDecorator{documentExt.decorate, hintsExt.decorate}
+
+	// Add captured API responses to the content (innermost, so every LLM call sees them)
+	var networkExt *networkCaptureExtractor
+	if e.network != nil {
+		networkExt = newNetworkCaptureExtractor(e.network)
+		decorators = append([]extractorDecorator{networkExt.decorate}, decorators...)
+	}
+
+	// Publish items to the job's stream subscribers as the LLM generates them (innermost,
+	// as it replaces the LLM call)
+	if broker := e.svc.partialResults; broker != nil && e.jobID != "" && broker.HasSubscribers(e.jobID) {
+		streamingExt := newStreamingExtractor(e.llmCfg, NewLLMClient(e.svc.logger, e.svc.resolver.GetRegistry()), broker, e.jobID, e.svc.logger)
+		decorators = append([]extractorDecorator{streamingExt.decorate}, decorators...)
+	}
+
+	// Read full-page screenshots with a vision model (replacing or filling in the text extraction)
+	var visualExt *visualExtractor
+	if e.inputMode.UsesScreenshots() && e.content == nil {
+		visualExt = newVisualExtractor(e.inputMode, e.llmCfg, NewLLMClient(e.svc.logger, e.svc.resolver.GetRegistry()), e.svc.logger)
+		decorators = append(decorators, visualExt.decorate)
+	}
+
+	// Map embedded structured data (JSON-LD, microdata, OpenGraph) before the LLM runs
+	var structuredExt *structuredDataExtractor
+	if e.structuredData.Enabled() {
+		structuredExt = newStructuredDataExtractor(e.structuredData, e.svc.logger)
+		decorators = append(decorators, structuredExt.decorate)
+	}
+
 	// Create refyne instance with current fetch mode
-	r, _, err := e.svc.createRefyneInstanceWithFetchMode(e.llmCfg, e.cleanerChain, FetchModeConfig{
+	r, _, err := e.svc.createRefyneInstanceWithFetchMode(e.llmCfg, cleanerChain, FetchModeConfig{
 		Mode:                  effectiveFetchMode,
 		ContentDynamicAllowed: e.contentDynamicAllowed,
 		UserID:                e.userID,
 		Tier:                  e.tier,
 		JobID:                 e.jobID,
-	})
+		Content:               e.content,
+		Actions:               e.actions,
+		Network:               e.network,
+		Screenshots:           e.inputMode.screenshotCapture(),
+		Resources:             e.fetchOptions.resourcePolicy(),
+		Proxy:                 e.fetchOptions.proxyRequest(e.userID),
+		Auth:                  e.auth,
+		OnBlocked:             func(n int) { result.BlockedRequests += n },
+		OnSolver:              func(cost float64) { result.SolverCostUSD += cost },
+	}, decorators...)
 	if err != nil {
 		// Check for permission/configuration errors that shouldn't be retried
 		if errors.Is(err, ErrDynamicFetchNotAllowed) || errors.Is(err, ErrDynamicFetchNotConfigured) {
@@ -82,12 +162,26 @@ extractAttempt:
 		result.RawLLMResponse = refyneResult.Raw // Capture raw LLM output for debug
 		result.TokensInput = refyneResult.TokenUsage.InputTokens
 		result.TokensOutput = refyneResult.TokenUsage.OutputTokens
-		result.FetchDurationMs = int(refyneResult.FetchDuration.Milliseconds())
+		if e.content == nil {
+			result.FetchDurationMs = int(refyneResult.FetchDuration.Milliseconds())
+		}
 		result.ExtractDurationMs = int(refyneResult.ExtractDuration.Milliseconds())
 		result.Provider = refyneResult.Provider
 		result.Model = refyneResult.Model
 		result.GenerationID = refyneResult.GenerationID
 		result.UsedDynamicMode = effectiveFetchMode == "dynamic"
+		if structuredExt != nil {
+			result.StructuredData = structuredExt.meta
+		}
+		result.Document = documentExt.meta
+		result.Hints = hintsExt.applied
+		if networkExt != nil {
+			result.Network = networkExt.responses()
+		}
+		if visualExt != nil && visualExt.meta != nil {
+			result.Visual = visualExt.meta
+			result.Screenshots = visualExt.page.pageScreenshots()
+		}
 		return result, nil
 	}
 
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/llm"
+)
+
+// streamingExtractor is an extractor decorator that streams a page's first LLM call
+// and publishes array items to the job's stream subscribers as they are generated, as
+// freeform prompt extractions do. Without subscribers it passes straight through.
+// It should be the innermost decorator, as it replaces the LLM call itself.
+type streamingExtractor struct {
+	decoratedExtractor
+	page   *pageCapture
+	llmCfg *LLMConfigInput
+	client *LLMClient
+	broker *PartialResultBroker
+	jobID  string
+	logger *slog.Logger
+
+	// streamed is set once the page's first call has been streamed; later calls (e.g.
+	// item-count retries) use the wrapped extractor so items are not published twice.
+	streamed bool
+}
+
+// newStreamingExtractor creates a streaming decorator publishing to the job's subscribers.
+// Use decorate as the extractorDecorator when building a refyne instance.
+func newStreamingExtractor(llmCfg *LLMConfigInput, client *LLMClient, broker *PartialResultBroker, jobID string, logger *slog.Logger) *streamingExtractor {
+	return &streamingExtractor{llmCfg: llmCfg, client: client, broker: broker, jobID: jobID, logger: logger}
+}
+
+// decorate implements extractorDecorator.
+func (e *streamingExtractor) decorate(inner extractor.Extractor, page *pageCapture) extractor.Extractor {
+	e.inner = inner
+	e.page = page
+	e.streamed = false
+	return e
+}
+
+// Name returns the extractor name.
+func (e *streamingExtractor) Name() string {
+	return "streaming+" + e.inner.Name()
+}
+
+// Extract streams the first call for the page when someone is listening. A streamed
+// response that doesn't parse or validate falls back to the wrapped extractor, which
+// retries with the validation errors in the prompt.
+func (e *streamingExtractor) Extract(ctx context.Context, content string, s schema.Schema) (*extractor.Result, error) {
+	pageURL, _ := e.page.get()
+	onDelta := partialResultsWriter(e.broker, e.jobID, pageURL)
+	if e.streamed || onDelta == nil {
+		return e.inner.Extract(ctx, content, s)
+	}
+	e.streamed = true
+
+	streamed, err := e.extractStreaming(ctx, content, s, onDelta)
+	if err == nil || IsOutputTruncated(err) || streamed == nil {
+		// Truncation is handled by model fallback, and call errors would fail again
+		return streamed, err
+	}
+
+	e.logger.Debug("streamed extraction failed, retrying without streaming",
+		"url", pageURL,
+		"model", e.llmCfg.Model,
+		"error", err,
+	)
+	result, err := e.inner.Extract(ctx, content, s)
+	if result != nil {
+		// Both calls are billed
+		result.Usage.InputTokens += streamed.Usage.InputTokens
+		result.Usage.OutputTokens += streamed.Usage.OutputTokens
+		result.Duration += streamed.Duration
+		result.GenerationID = ""
+		result.Cost, result.CostIncluded = 0, false
+	}
+	return result, err
+}
+
+// extractStreaming sends refyne's extraction prompt with the schema as structured
+// output, streaming the response to onDelta.
+func (e *streamingExtractor) extractStreaming(ctx context.Context, content string, s schema.Schema, onDelta func(string)) (*extractor.Result, error) {
+	start := time.Now()
+
+	jsonSchema, err := s.ToJSONSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert schema: %w", err)
+	}
+
+	defaults := extractor.DefaultLLMConfig()
+	opts := DefaultLLMCallOptions()
+	opts.Temperature = defaults.Temperature
+	if e.llmCfg.MaxTokens > 0 {
+		opts.MaxTokens = e.llmCfg.MaxTokens
+	}
+	opts.Timeout = llm.LLMTimeout
+	opts.JSONSchema = jsonSchema
+	opts.OnDelta = onDelta
+
+	prompt := extractor.SystemPrompt + "\n\n" + extractor.BuildPrompt(content, s, nil, defaults.MaxContentSize)
+	callResult, err := e.client.Call(ctx, e.llmCfg, prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &extractor.Result{
+		Raw:          callResult.Content,
+		RawContent:   content,
+		Usage:        extractor.Usage{InputTokens: callResult.InputTokens, OutputTokens: callResult.OutputTokens},
+		Model:        e.llmCfg.Model,
+		Provider:     e.llmCfg.Provider,
+		FinishReason: callResult.FinishReason,
+		Duration:     time.Since(start),
+	}
+	if truncErr := callResult.TruncationError(); truncErr != nil {
+		return result, truncErr
+	}
+
+	data, err := s.Unmarshal([]byte(extractor.StripMarkdownCodeBlock(callResult.Content)))
+	if err != nil {
+		return result, fmt.Errorf("streamed response was not valid JSON: %w", err)
+	}
+	if errs := s.Validate(data); len(errs) > 0 {
+		result.Errors = errs
+		return result, fmt.Errorf("streamed response failed schema validation (%d errors)", len(errs))
+	}
+	result.Data = data
+	return result, nil
+}
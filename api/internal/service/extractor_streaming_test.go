@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"testing"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/llm"
+)
+
+func TestStreamingExtractor(t *testing.T) {
+	sch := schema.Schema{Fields: []schema.Field{{
+		Name:  "products",
+		Type:  "array",
+		Items: &schema.Field{Type: "object", Properties: []schema.Field{{Name: "name", Type: "string"}}},
+	}}}
+
+	// newStreaming returns a streaming extractor whose model streams content in two fragments.
+	newStreaming := func(t *testing.T, broker *PartialResultBroker, content string) (*streamingExtractor, *map[string]any) {
+		var request map[string]any
+		srv := newStreamServer(t, "text/event-stream", []string{
+			`data: {"choices":[{"delta":{"content":` + quoteJSON(content[:len(content)/2]) + `}}]}`,
+			``,
+			`data: {"choices":[{"delta":{"content":` + quoteJSON(content[len(content)/2:]) + `},"finish_reason":"stop"}]}`,
+			``,
+			`data: {"choices":[],"usage":{"prompt_tokens":100,"completion_tokens":20}}`,
+			``,
+			`data: [DONE]`,
+		}, &request)
+		registry := newStructuredOutputRegistry("openai", srv.URL, llm.APIFormatOpenAI, llm.StructuredOutputJSONSchema, llm.ModelCapabilities{SupportsStructuredOutputs: true})
+		cfg := &LLMConfigInput{Provider: "openai", Model: "gpt-4o", APIKey: "key"}
+		return newStreamingExtractor(cfg, NewLLMClient(slog.Default(), registry), broker, "job-1", slog.Default()), &request
+	}
+	newPage := func() *pageCapture {
+		page := &pageCapture{}
+		page.set("https://example.com/products", "<html></html>", "text/html")
+		return page
+	}
+
+	t.Run("publishes items and extracts the streamed response", func(t *testing.T) {
+		broker := NewPartialResultBroker()
+		results, unsubscribe := broker.Subscribe("job-1")
+		defer unsubscribe()
+
+		se, request := newStreaming(t, broker, `{"products":[{"name":"A"},{"name":"B"}]}`)
+		inner := &stubExtractor{}
+		ext := se.decorate(inner, newPage())
+
+		result, err := ext.Extract(context.Background(), "page text", sch)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if inner.calls != 0 {
+			t.Errorf("inner extractor called %d times, want 0", inner.calls)
+		}
+		want := map[string]any{"products": []any{map[string]any{"name": "A"}, map[string]any{"name": "B"}}}
+		if !reflect.DeepEqual(result.Data, want) {
+			t.Errorf("Data = %v, want %v", result.Data, want)
+		}
+		if result.Usage.InputTokens != 100 || result.Usage.OutputTokens != 20 || result.RawContent != "page text" {
+			t.Errorf("result = %+v", result)
+		}
+		if format, _ := (*request)["response_format"].(map[string]any); format["type"] != "json_schema" {
+			t.Errorf("response_format = %v, want json_schema", (*request)["response_format"])
+		}
+
+		var published []string
+		for len(results) > 0 {
+			r := <-results
+			if r.URL != "https://example.com/products" || r.Field != "products" {
+				t.Errorf("partial result = %+v", r)
+			}
+			published = append(published, string(r.Item))
+		}
+		if want := []string{`{"name":"A"}`, `{"name":"B"}`}; !reflect.DeepEqual(published, want) {
+			t.Errorf("published = %v, want %v", published, want)
+		}
+
+		// Later calls for the page (e.g. item-count retries) are not streamed again
+		if _, err := ext.Extract(context.Background(), "page text", sch); err != nil {
+			t.Fatalf("second Extract() error = %v", err)
+		}
+		if inner.calls != 1 {
+			t.Errorf("inner extractor called %d times after second call, want 1", inner.calls)
+		}
+	})
+
+	t.Run("invalid response falls back to the wrapped extractor", func(t *testing.T) {
+		broker := NewPartialResultBroker()
+		_, unsubscribe := broker.Subscribe("job-1")
+		defer unsubscribe()
+
+		se, _ := newStreaming(t, broker, `{"products": [{"name":"A"}`)
+		inner := &stubExtractor{result: &extractor.Result{
+			Data:         map[string]any{"products": []any{}},
+			Usage:        extractor.Usage{InputTokens: 50, OutputTokens: 5},
+			Cost:         0.01,
+			CostIncluded: true,
+		}}
+
+		result, err := se.decorate(inner, newPage()).Extract(context.Background(), "page text", sch)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if inner.calls != 1 {
+			t.Errorf("inner extractor called %d times, want 1", inner.calls)
+		}
+		if result.Usage.InputTokens != 150 || result.Usage.OutputTokens != 25 {
+			t.Errorf("usage = %+v, want both calls billed", result.Usage)
+		}
+		if result.CostIncluded {
+			t.Error("CostIncluded should be false when the streamed call's cost is unknown")
+		}
+	})
+
+	t.Run("passes through without subscribers", func(t *testing.T) {
+		se, _ := newStreaming(t, NewPartialResultBroker(), `{"products":[]}`)
+		inner := &stubExtractor{}
+
+		if _, err := se.decorate(inner, newPage()).Extract(context.Background(), "page text", sch); err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if inner.calls != 1 {
+			t.Errorf("inner extractor called %d times, want 1", inner.calls)
+		}
+	})
+}
+
+// quoteJSON returns s as a JSON string literal.
+func quoteJSON(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/structured"
+)
+
+// StructuredDataProvider is reported as the provider when an extraction was
+// answered entirely from embedded structured data without an LLM call.
+const StructuredDataProvider = "structured_data"
+
+// StructuredDataConfig controls the structured-data fast path for schema extraction.
+// Pages that embed JSON-LD, microdata or OpenGraph data are mapped onto the schema
+// before the LLM runs.
+type StructuredDataConfig struct {
+	// Mode is "auto" (default) to use structured data when available, or "off"
+	// to always send the full schema to the LLM.
+	Mode string `json:"mode,omitempty"`
+
+	// MinCoverage is the fraction of schema fields (0-1) that must be filled from
+	// structured data to skip the LLM call entirely. Defaults to 1.0 (all fields).
+	MinCoverage float64 `json:"min_coverage,omitempty"`
+}
+
+// Enabled returns true unless the fast path is explicitly turned off.
+func (c *StructuredDataConfig) Enabled() bool {
+	return c == nil || c.Mode != "off"
+}
+
+// skipCoverage returns the coverage required to skip the LLM call.
+func (c *StructuredDataConfig) skipCoverage() float64 {
+	if c == nil || c.MinCoverage <= 0 || c.MinCoverage > 1 {
+		return constants.StructuredDataSkipCoverage
+	}
+	return c.MinCoverage
+}
+
+// StructuredDataMeta reports how embedded structured data contributed to an extraction.
+type StructuredDataMeta struct {
+	Sources    []string `json:"sources"`     // Formats that contributed values (json-ld, microdata, opengraph)
+	Fields     []string `json:"fields"`      // Schema fields filled from structured data
+	Coverage   float64  `json:"coverage"`    // Fraction of schema fields filled (0-1)
+	LLMSkipped bool     `json:"llm_skipped"` // True if no LLM call was needed
+}
+
+// structuredDataExtractor is an extractor decorator that maps embedded structured
+// data onto the schema before calling the LLM. With full coverage the LLM is skipped;
+// with partial coverage the LLM is asked only for the missing fields and the results
+// are merged.
+type structuredDataExtractor struct {
+	decoratedExtractor
+	page   *pageCapture
+	cfg    *StructuredDataConfig
+	logger *slog.Logger
+
+	// meta records the outcome of the last Extract call (nil if structured data was not used).
+	meta *StructuredDataMeta
+}
+
+// newStructuredDataExtractor creates a structured data decorator.
+// Use decorate as the extractorDecorator when building a refyne instance.
+func newStructuredDataExtractor(cfg *StructuredDataConfig, logger *slog.Logger) *structuredDataExtractor {
+	return &structuredDataExtractor{cfg: cfg, logger: logger}
+}
+
+// decorate implements extractorDecorator.
+func (e *structuredDataExtractor) decorate(inner extractor.Extractor, page *pageCapture) extractor.Extractor {
+	e.inner = inner
+	e.page = page
+	e.meta = nil
+	return e
+}
+
+// Name returns the extractor name.
+func (e *structuredDataExtractor) Name() string {
+	return "structured_data+" + e.inner.Name()
+}
+
+// Extract maps structured data onto the schema, falling back to the LLM for any
+// fields it cannot fill.
+func (e *structuredDataExtractor) Extract(ctx context.Context, content string, s schema.Schema) (*extractor.Result, error) {
+	e.meta = nil
+
+	pageURL, html := e.page.get()
+	if html == "" {
+		return e.inner.Extract(ctx, content, s)
+	}
+
+	start := time.Now()
+	doc := structured.Parse(html)
+	if doc.IsEmpty() {
+		return e.inner.Extract(ctx, content, s)
+	}
+
+	mapped := structured.Map(doc, s)
+	if len(mapped.Matched) == 0 {
+		return e.inner.Extract(ctx, content, s)
+	}
+
+	meta := &StructuredDataMeta{
+		Fields:   mapped.Matched,
+		Coverage: mapped.Coverage,
+	}
+	for _, source := range mapped.Sources {
+		meta.Sources = append(meta.Sources, string(source))
+	}
+
+	// Full coverage - answer without the LLM
+	if mapped.Coverage >= e.cfg.skipCoverage() && mapped.RequiredSatisfied {
+		meta.LLMSkipped = true
+		e.meta = meta
+
+		raw, _ := json.Marshal(mapped.Data)
+		e.logger.Info("structured data covers schema, skipping LLM",
+			"url", pageURL,
+			"sources", meta.Sources,
+			"coverage", mapped.Coverage,
+		)
+		return &extractor.Result{
+			Data:         mapped.Data,
+			Raw:          string(raw),
+			RawContent:   content,
+			Model:        strings.Join(meta.Sources, "+"),
+			Provider:     StructuredDataProvider,
+			FinishReason: "stop",
+			Duration:     time.Since(start),
+		}, nil
+	}
+
+	// Not enough coverage to be worth splitting the work
+	if mapped.Coverage < constants.StructuredDataPartialCoverage {
+		return e.inner.Extract(ctx, content, s)
+	}
+
+	// Partial coverage - ask the LLM only for the missing fields
+	e.logger.Info("structured data partially covers schema, reducing prompt",
+		"url", pageURL,
+		"sources", meta.Sources,
+		"coverage", mapped.Coverage,
+		"missing_fields", mapped.Missing,
+	)
+
+	result, err := e.inner.Extract(ctx, content, structured.Subschema(s, mapped.Missing))
+	if result == nil || err != nil {
+		return result, err
+	}
+
+	e.meta = meta
+	result.Data = mergeStructuredData(mapped.Data, result.Data)
+	return result, nil
+}
+
+// mergeStructuredData merges the LLM's result for the missing fields into the
+// values taken from structured data. Structured data wins for fields it filled.
+func mergeStructuredData(structuredData map[string]any, llmData any) any {
+	merged := make(map[string]any, len(structuredData))
+
+	if llmMap, ok := llmData.(map[string]any); ok {
+		for key, value := range llmMap {
+			merged[key] = value
+		}
+	} else if llmData != nil {
+		// Unexpected shape from the LLM - keep structured data only
+		return structuredData
+	}
+
+	for key, value := range structuredData {
+		merged[key] = value
+	}
+	return merged
+}
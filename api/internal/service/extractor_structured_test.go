@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+)
+
+// stubExtractor is a test extractor that records the schema it was called with.
+type stubExtractor struct {
+	calls      int
+	lastSchema schema.Schema
+	result     *extractor.Result
+	err        error
+}
+
+func (s *stubExtractor) Extract(_ context.Context, content string, sch schema.Schema) (*extractor.Result, error) {
+	s.calls++
+	s.lastSchema = sch
+	if s.result == nil {
+		return &extractor.Result{Data: map[string]any{}, RawContent: content}, s.err
+	}
+	return s.result, s.err
+}
+
+func (s *stubExtractor) Name() string    { return "stub" }
+func (s *stubExtractor) Available() bool { return true }
+
+const structuredProductPage = `<html><head>
+<script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "Product", "name": "Widget",
+ "offers": {"@type": "Offer", "price": "19.99", "priceCurrency": "USD"}}
+</script>
+</head><body><h1>Widget</h1></body></html>`
+
+func newStructuredTestExtractor(cfg *StructuredDataConfig, html string, inner extractor.Extractor) *structuredDataExtractor {
+	page := &pageCapture{}
+	page.set("https://example.com/widget", html, "text/html")
+	e := newStructuredDataExtractor(cfg, slog.Default())
+	e.decorate(inner, page)
+	return e
+}
+
+func TestStructuredDataExtractor_FullCoverageSkipsLLM(t *testing.T) {
+	inner := &stubExtractor{}
+	e := newStructuredTestExtractor(nil, structuredProductPage, inner)
+
+	sch := schema.Schema{Fields: []schema.Field{
+		{Name: "name", Type: "string"},
+		{Name: "price", Type: "number"},
+		{Name: "currency", Type: "string"},
+	}}
+
+	result, err := e.Extract(context.Background(), "# Widget", sch)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if inner.calls != 0 {
+		t.Errorf("LLM extractor called %d times, want 0", inner.calls)
+	}
+	if result.Provider != StructuredDataProvider {
+		t.Errorf("Provider = %q, want %q", result.Provider, StructuredDataProvider)
+	}
+	if result.Usage.InputTokens != 0 || result.Usage.OutputTokens != 0 {
+		t.Errorf("expected zero token usage, got %+v", result.Usage)
+	}
+	data := result.Data.(map[string]any)
+	if data["price"] != 19.99 || data["currency"] != "USD" {
+		t.Errorf("Data = %v", data)
+	}
+	if e.meta == nil || !e.meta.LLMSkipped {
+		t.Errorf("meta = %+v, want LLMSkipped", e.meta)
+	}
+}
+
+func TestStructuredDataExtractor_PartialCoverageReducesSchema(t *testing.T) {
+	inner := &stubExtractor{
+		result: &extractor.Result{
+			Data:     map[string]any{"color": "blue", "name": "LLM name"},
+			Provider: "openai",
+			Usage:    extractor.Usage{InputTokens: 100, OutputTokens: 10},
+		},
+	}
+	e := newStructuredTestExtractor(nil, structuredProductPage, inner)
+
+	sch := schema.Schema{Fields: []schema.Field{
+		{Name: "name", Type: "string"},
+		{Name: "price", Type: "number"},
+		{Name: "color", Type: "string"},
+	}}
+
+	result, err := e.Extract(context.Background(), "# Widget", sch)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if inner.calls != 1 {
+		t.Fatalf("LLM extractor called %d times, want 1", inner.calls)
+	}
+	if len(inner.lastSchema.Fields) != 1 || inner.lastSchema.Fields[0].Name != "color" {
+		t.Errorf("LLM schema fields = %v, want only [color]", inner.lastSchema.Fields)
+	}
+
+	data := result.Data.(map[string]any)
+	if data["name"] != "Widget" {
+		t.Errorf("name = %v, structured data should win for fields it filled", data["name"])
+	}
+	if data["color"] != "blue" || data["price"] != 19.99 {
+		t.Errorf("Data = %v, want merged result", data)
+	}
+	if e.meta == nil || e.meta.LLMSkipped {
+		t.Errorf("meta = %+v, want partial (LLM not skipped)", e.meta)
+	}
+}
+
+func TestStructuredDataExtractor_LowCoverageUsesFullSchema(t *testing.T) {
+	inner := &stubExtractor{}
+	e := newStructuredTestExtractor(nil, structuredProductPage, inner)
+
+	sch := schema.Schema{Fields: []schema.Field{
+		{Name: "name", Type: "string"},
+		{Name: "color", Type: "string"},
+		{Name: "weight", Type: "string"},
+		{Name: "material", Type: "string"},
+	}}
+
+	if _, err := e.Extract(context.Background(), "# Widget", sch); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if inner.calls != 1 || len(inner.lastSchema.Fields) != 4 {
+		t.Errorf("expected one LLM call with the full schema, got %d calls with %d fields", inner.calls, len(inner.lastSchema.Fields))
+	}
+	if e.meta != nil {
+		t.Errorf("meta = %+v, want nil when structured data was not used", e.meta)
+	}
+}
+
+func TestStructuredDataExtractor_NoStructuredData(t *testing.T) {
+	inner := &stubExtractor{}
+	e := newStructuredTestExtractor(nil, "<html><body>plain</body></html>", inner)
+
+	sch := schema.Schema{Fields: []schema.Field{{Name: "name", Type: "string"}}}
+
+	if _, err := e.Extract(context.Background(), "plain", sch); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("LLM extractor called %d times, want 1", inner.calls)
+	}
+}
+
+func TestStructuredDataConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         *StructuredDataConfig
+		wantEnabled bool
+		wantSkip    float64
+	}{
+		{"nil uses defaults", nil, true, 1.0},
+		{"auto mode", &StructuredDataConfig{Mode: "auto"}, true, 1.0},
+		{"off mode", &StructuredDataConfig{Mode: "off"}, false, 1.0},
+		{"custom coverage", &StructuredDataConfig{MinCoverage: 0.8}, true, 0.8},
+		{"out of range coverage", &StructuredDataConfig{MinCoverage: 2}, true, 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Enabled(); got != tt.wantEnabled {
+				t.Errorf("Enabled() = %v, want %v", got, tt.wantEnabled)
+			}
+			if got := tt.cfg.skipCoverage(); got != tt.wantSkip {
+				t.Errorf("skipCoverage() = %v, want %v", got, tt.wantSkip)
+			}
+		})
+	}
+}
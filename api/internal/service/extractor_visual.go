@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/llm"
+)
+
+// InputMode selects what schema extraction reads from a page.
+type InputMode string
+
+const (
+	// InputModeText extracts from the cleaned page text (default).
+	InputModeText InputMode = "text"
+	// InputModeVisual extracts from full-page screenshots with a vision-capable model,
+	// for pages that put their data in images or canvas elements.
+	InputModeVisual InputMode = "visual"
+	// InputModeHybrid extracts from the page text and fills the fields it missed
+	// from full-page screenshots.
+	InputModeHybrid InputMode = "hybrid"
+)
+
+// UsesScreenshots reports whether the mode reads screenshots, which implies browser
+// rendering and a vision-capable model.
+func (m InputMode) UsesScreenshots() bool {
+	return m == InputModeVisual || m == InputModeHybrid
+}
+
+// screenshotCapture returns the screenshots the captcha service takes for the mode
+// (nil for text extraction).
+func (m InputMode) screenshotCapture() *captcha.ScreenshotCapture {
+	if !m.UsesScreenshots() {
+		return nil
+	}
+	return &captcha.ScreenshotCapture{
+		SegmentHeight: constants.VisualSegmentHeight,
+		MaxSegments:   constants.VisualMaxSegments,
+		Format:        "jpeg",
+		Quality:       constants.VisualJPEGQuality,
+	}
+}
+
+// VisualMeta reports how screenshots contributed to an extraction.
+type VisualMeta struct {
+	Mode        InputMode `json:"mode"`             // visual or hybrid
+	Screenshots int       `json:"screenshots"`      // Screenshot segments sent to the model
+	Fields      []string  `json:"fields,omitempty"` // Hybrid: fields text extraction missed that were filled from screenshots
+}
+
+// visionLLMConfigs narrows an LLM config chain to vision-capable models when the input
+// mode reads screenshots, failing when none are left.
+func (s *ExtractionService) visionLLMConfigs(ctx context.Context, configs []*LLMConfigInput, mode InputMode) ([]*LLMConfigInput, error) {
+	if !mode.UsesScreenshots() {
+		return configs, nil
+	}
+	var kept []*LLMConfigInput
+	for _, cfg := range configs {
+		if s.supportsVision(ctx, cfg) {
+			kept = append(kept, cfg)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, llm.NewNoModelsConfiguredError(fmt.Sprintf("no vision-capable models in fallback chain for input_mode %q", mode))
+	}
+	return kept, nil
+}
+
+// visualExtractor is an extractor decorator that reads the page's full-page
+// screenshots with a vision-capable model. In visual mode the screenshots replace
+// the page text; in hybrid mode the text is extracted first and empty fields are
+// filled from the screenshots.
+type visualExtractor struct {
+	decoratedExtractor
+	page   *pageCapture
+	mode   InputMode
+	llmCfg *LLMConfigInput
+	client *LLMClient
+	logger *slog.Logger
+
+	// meta records how the last Extract call used screenshots (nil if it didn't).
+	meta *VisualMeta
+}
+
+// newVisualExtractor creates a visual extraction decorator calling llmCfg's model.
+// Use decorate as the extractorDecorator when building a refyne instance.
+func newVisualExtractor(mode InputMode, llmCfg *LLMConfigInput, client *LLMClient, logger *slog.Logger) *visualExtractor {
+	return &visualExtractor{mode: mode, llmCfg: llmCfg, client: client, logger: logger}
+}
+
+// decorate implements extractorDecorator.
+func (e *visualExtractor) decorate(inner extractor.Extractor, page *pageCapture) extractor.Extractor {
+	e.inner = inner
+	e.page = page
+	e.meta = nil
+	return e
+}
+
+// Name returns the extractor name.
+func (e *visualExtractor) Name() string {
+	return "visual+" + e.inner.Name()
+}
+
+// Extract reads the page's screenshots, falling back to the page text when the
+// browser couldn't take any.
+func (e *visualExtractor) Extract(ctx context.Context, content string, s schema.Schema) (*extractor.Result, error) {
+	e.meta = nil
+
+	shots := e.page.pageScreenshots()
+	if len(shots) == 0 {
+		pageURL, _ := e.page.get()
+		e.logger.Warn("no screenshots for visual extraction, using page text",
+			"url", pageURL,
+			"mode", e.mode,
+		)
+		return e.inner.Extract(ctx, content, s)
+	}
+
+	if e.mode == InputModeVisual {
+		result, err := e.extractScreenshots(ctx, s, shots)
+		if err == nil {
+			e.meta = &VisualMeta{Mode: e.mode, Screenshots: len(shots)}
+		}
+		return result, err
+	}
+
+	// Hybrid: the text result is authoritative, screenshots only fill its gaps
+	result, err := e.inner.Extract(ctx, content, s)
+	if result == nil || err != nil {
+		return result, err
+	}
+	visual, err := e.extractScreenshots(ctx, s, shots)
+	if visual != nil {
+		// Both calls are billed
+		result.Usage.InputTokens += visual.Usage.InputTokens
+		result.Usage.OutputTokens += visual.Usage.OutputTokens
+		result.Duration += visual.Duration
+		result.GenerationID = ""
+		result.CostIncluded = false
+	}
+	if err != nil {
+		pageURL, _ := e.page.get()
+		e.logger.Warn("visual extraction failed, keeping text result",
+			"url", pageURL,
+			"model", e.llmCfg.Model,
+			"error", err,
+		)
+		return result, nil
+	}
+
+	var filled []string
+	result.Data = mergeVisualData(result.Data, visual.Data, "", &filled)
+	result.Raw += "\n" + visual.Raw
+	e.meta = &VisualMeta{Mode: e.mode, Screenshots: len(shots), Fields: filled}
+	return result, nil
+}
+
+// extractScreenshots sends the screenshots and schema to the vision model.
+func (e *visualExtractor) extractScreenshots(ctx context.Context, s schema.Schema, shots []captcha.Screenshot) (*extractor.Result, error) {
+	start := time.Now()
+	pageURL, _ := e.page.get()
+
+	jsonSchema, err := s.ToJSONSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert schema: %w", err)
+	}
+	images := make([]llm.Image, 0, len(shots))
+	for _, shot := range shots {
+		images = append(images, llm.Image{MimeType: shot.MimeType, Data: shot.Data})
+	}
+
+	maxTokens := e.llmCfg.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = constants.VisualMaxTokens
+	}
+	callResult, err := e.client.Call(ctx, e.llmCfg, buildVisualPrompt(pageURL, s, len(shots)), LLMCallOptions{
+		Temperature: 0.1,
+		MaxTokens:   maxTokens,
+		Timeout:     constants.VisualTimeout,
+		JSONSchema:  jsonSchema,
+		Images:      images,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &extractor.Result{
+		Raw:          callResult.Content,
+		Usage:        extractor.Usage{InputTokens: callResult.InputTokens, OutputTokens: callResult.OutputTokens},
+		Model:        e.llmCfg.Model,
+		Provider:     e.llmCfg.Provider,
+		FinishReason: callResult.FinishReason,
+		Duration:     time.Since(start),
+	}
+	if truncErr := callResult.TruncationError(); truncErr != nil {
+		return result, truncErr
+	}
+
+	if err := json.Unmarshal([]byte(trimCodeFence(callResult.Content)), &result.Data); err != nil {
+		return result, fmt.Errorf("visual extraction response was not valid JSON: %w", err)
+	}
+	return result, nil
+}
+
+// buildVisualPrompt builds the instructions sent with a page's screenshots.
+func buildVisualPrompt(pageURL string, s schema.Schema, segments int) string {
+	var b strings.Builder
+	b.WriteString("Extract structured data from screenshots of a web page.\n\n")
+	if segments > 1 {
+		fmt.Fprintf(&b, "The %d images are consecutive segments of one full-page screenshot, from top to bottom. "+
+			"Content at the bottom edge of one segment continues at the top of the next.\n", segments)
+	}
+	b.WriteString("Read all visible text, including text inside images, charts and canvas elements. " +
+		"Use null for fields that are not visible on the page. Never invent values.\n\n")
+	if pageURL != "" {
+		b.WriteString("## Page\n" + pageURL + "\n\n")
+	}
+	b.WriteString("## Schema\n")
+	b.WriteString(s.ToPromptDescription())
+	b.WriteString("\n\nRespond with a single JSON object matching the schema and nothing else.")
+	return b.String()
+}
+
+// trimCodeFence removes a markdown code fence some models wrap JSON responses in.
+func trimCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "```json")
+	s = strings.TrimPrefix(s, "```")
+	s = strings.TrimSuffix(s, "```")
+	return strings.TrimSpace(s)
+}
+
+// mergeVisualData fills the empty values of the text result from the visual result.
+// Objects are merged field by field and lists of the same length item by item, as
+// both describe the same page; otherwise the text value wins. The paths of the
+// filled values are appended to filled.
+func mergeVisualData(text, visual any, path string, filled *[]string) any {
+	if isEmptyChunkValue(text) {
+		if !isEmptyChunkValue(visual) && path != "" {
+			*filled = append(*filled, path)
+		}
+		if isEmptyChunkValue(visual) {
+			return text
+		}
+		return visual
+	}
+
+	switch tv := text.(type) {
+	case map[string]any:
+		vv, ok := visual.(map[string]any)
+		if !ok {
+			return text
+		}
+		keys := make([]string, 0, len(vv))
+		for key := range vv {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys) // Stable field order in metadata
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			tv[key] = mergeVisualData(tv[key], vv[key], fieldPath, filled)
+		}
+		return tv
+	case []any:
+		vv, ok := visual.([]any)
+		if !ok || len(vv) != len(tv) {
+			return text
+		}
+		for i := range tv {
+			tv[i] = mergeVisualData(tv[i], vv[i], path+"["+strconv.Itoa(i)+"]", filled)
+		}
+		return tv
+	}
+	return text
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/schema"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/llm"
+)
+
+func TestInputMode_ScreenshotCapture(t *testing.T) {
+	if got := InputModeText.screenshotCapture(); got != nil {
+		t.Errorf("text screenshotCapture() = %+v, want nil", got)
+	}
+	if got := InputMode("").screenshotCapture(); got != nil {
+		t.Errorf("default screenshotCapture() = %+v, want nil", got)
+	}
+	for _, mode := range []InputMode{InputModeVisual, InputModeHybrid} {
+		if got := mode.screenshotCapture(); got == nil || got.Format != "jpeg" {
+			t.Errorf("%s screenshotCapture() = %+v, want jpeg capture", mode, got)
+		}
+	}
+}
+
+func TestMergeVisualData(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       any
+		visual     any
+		want       any
+		wantFilled []string
+	}{
+		{
+			"fills empty fields",
+			map[string]any{"name": "Widget", "price": nil, "tags": []any{}},
+			map[string]any{"name": "Gadget", "price": 9.99, "tags": []any{"new"}},
+			map[string]any{"name": "Widget", "price": 9.99, "tags": []any{"new"}},
+			[]string{"price", "tags"},
+		},
+		{
+			"merges nested objects and equal-length lists",
+			map[string]any{"items": []any{map[string]any{"name": "A", "price": ""}}},
+			map[string]any{"items": []any{map[string]any{"name": "B", "price": 1.5}}},
+			map[string]any{"items": []any{map[string]any{"name": "A", "price": 1.5}}},
+			[]string{"items[0].price"},
+		},
+		{
+			"keeps text list of different length",
+			map[string]any{"items": []any{"a"}},
+			map[string]any{"items": []any{"b", "c"}},
+			map[string]any{"items": []any{"a"}},
+			nil,
+		},
+		{
+			"nothing to fill",
+			map[string]any{"name": "Widget"},
+			map[string]any{"name": nil},
+			map[string]any{"name": "Widget"},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filled []string
+			got := mergeVisualData(tt.text, tt.visual, "", &filled)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeVisualData() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(filled, tt.wantFilled) {
+				t.Errorf("filled = %v, want %v", filled, tt.wantFilled)
+			}
+		})
+	}
+}
+
+func TestVisualExtractor(t *testing.T) {
+	sch := schema.Schema{Fields: []schema.Field{{Name: "name", Type: "string"}, {Name: "price", Type: "number"}}}
+	shots := []captcha.Screenshot{
+		{Data: "c2VnMQ==", MimeType: "image/jpeg", Offset: 0, Width: 1280, Height: 2000},
+		{Data: "c2VnMg==", MimeType: "image/jpeg", Offset: 2000, Width: 1280, Height: 900},
+	}
+
+	// newVisual returns a visual extractor whose model replies with the data in a code fence.
+	newVisual := func(t *testing.T, mode InputMode, request *map[string]any) *visualExtractor {
+		srv := newStructuredOutputServer(t, `{
+			"choices": [{"message": {"content": "`+"```json\\n"+`{\"name\":\"Gadget\",\"price\":9.99}`+"\\n```"+`"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 1500, "completion_tokens": 20}
+		}`, request)
+		registry := newStructuredOutputRegistry("openai", srv.URL, llm.APIFormatOpenAI, llm.StructuredOutputJSONObject, llm.ModelCapabilities{SupportsResponseFormat: true, SupportsVision: true})
+		cfg := &LLMConfigInput{Provider: "openai", Model: "gpt-4o", APIKey: "key"}
+		return newVisualExtractor(mode, cfg, NewLLMClient(slog.Default(), registry), slog.Default())
+	}
+
+	t.Run("visual reads screenshots instead of text", func(t *testing.T) {
+		var request map[string]any
+		ve := newVisual(t, InputModeVisual, &request)
+		page := &pageCapture{}
+		page.set("https://example.com/product", "<html></html>", "text/html")
+		page.setScreenshots(shots)
+		inner := &stubExtractor{}
+
+		result, err := ve.decorate(inner, page).Extract(context.Background(), "page text", sch)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if inner.calls != 0 {
+			t.Errorf("inner extractor called %d times, want 0", inner.calls)
+		}
+		if want := map[string]any{"name": "Gadget", "price": 9.99}; !reflect.DeepEqual(result.Data, want) {
+			t.Errorf("Data = %v, want %v", result.Data, want)
+		}
+		if result.Usage.InputTokens != 1500 || result.Model != "gpt-4o" {
+			t.Errorf("result = %+v", result)
+		}
+		if ve.meta == nil || ve.meta.Mode != InputModeVisual || ve.meta.Screenshots != 2 {
+			t.Errorf("meta = %+v", ve.meta)
+		}
+
+		messages := request["messages"].([]any)
+		content := messages[0].(map[string]any)["content"].([]any)
+		if len(content) != 3 {
+			t.Fatalf("message content has %d parts, want 2 images and the prompt", len(content))
+		}
+		if url := content[0].(map[string]any)["image_url"].(map[string]any)["url"]; url != "data:image/jpeg;base64,c2VnMQ==" {
+			t.Errorf("first image url = %v", url)
+		}
+		if prompt := content[2].(map[string]any)["text"].(string); !strings.Contains(prompt, "https://example.com/product") || !strings.Contains(prompt, "consecutive segments") {
+			t.Errorf("prompt = %q", prompt)
+		}
+	})
+
+	t.Run("hybrid fills fields text extraction missed", func(t *testing.T) {
+		var request map[string]any
+		ve := newVisual(t, InputModeHybrid, &request)
+		page := &pageCapture{}
+		page.setScreenshots(shots)
+		inner := &stubExtractor{result: &extractor.Result{
+			Data:         map[string]any{"name": "Widget", "price": nil},
+			Usage:        extractor.Usage{InputTokens: 400, OutputTokens: 10},
+			GenerationID: "gen-1",
+		}}
+
+		result, err := ve.decorate(inner, page).Extract(context.Background(), "page text", sch)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if want := map[string]any{"name": "Widget", "price": 9.99}; !reflect.DeepEqual(result.Data, want) {
+			t.Errorf("Data = %v, want %v", result.Data, want)
+		}
+		if result.Usage.InputTokens != 1900 || result.Usage.OutputTokens != 30 {
+			t.Errorf("Usage = %+v, want both calls summed", result.Usage)
+		}
+		if result.GenerationID != "" {
+			t.Errorf("GenerationID = %q, want empty for a combined result", result.GenerationID)
+		}
+		if ve.meta == nil || !reflect.DeepEqual(ve.meta.Fields, []string{"price"}) {
+			t.Errorf("meta = %+v, want price filled", ve.meta)
+		}
+	})
+
+	t.Run("falls back to text without screenshots", func(t *testing.T) {
+		var request map[string]any
+		ve := newVisual(t, InputModeVisual, &request)
+		inner := &stubExtractor{}
+
+		if _, err := ve.decorate(inner, &pageCapture{}).Extract(context.Background(), "page text", sch); err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if inner.calls != 1 {
+			t.Errorf("inner extractor called %d times, want 1", inner.calls)
+		}
+		if request != nil {
+			t.Error("vision model was called without screenshots")
+		}
+		if ve.meta != nil {
+			t.Errorf("meta = %+v, want nil", ve.meta)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+)
+
+// Resource policy profiles for browser rendering.
+const (
+	ResourcePolicyFull     = "full"      // Load every resource
+	ResourcePolicyNoMedia  = "no_media"  // Block images, media, fonts and ad/analytics domains
+	ResourcePolicyTextOnly = "text_only" // Also block stylesheets and other non-content resources
+)
+
+// Proxy sources for fetching pages.
+const (
+	ProxySourcePool = "pool" // The managed proxy pool
+	ProxySourceOwn  = "own"  // The user's own proxies
+)
+
+// FetchOptions tunes how pages are fetched. Resource policies only apply when a page
+// is rendered in the browser (dynamic fetch mode, or auto mode falling back to it),
+// where blocking images, fonts and trackers makes rendering several times faster.
+type FetchOptions struct {
+	// ResourcePolicy is a built-in profile: "text_only", "no_media" or "full" (default).
+	ResourcePolicy string `json:"resource_policy,omitempty"`
+
+	// BlockResourceTypes are additional resource types to block (image, font, script, ...).
+	BlockResourceTypes []string `json:"block_resource_types,omitempty"`
+
+	// BlockDomains are hosts to block, including their subdomains.
+	BlockDomains []string `json:"block_domains,omitempty"`
+
+	// BlockURLPatterns are URL globs to block (* matches anything, ? one character).
+	BlockURLPatterns []string `json:"block_url_patterns,omitempty"`
+
+	// Proxy routes fetches through a proxy: "pool" (managed pool) or "own" (the user's proxies).
+	Proxy string `json:"proxy,omitempty"`
+
+	// ProxyCountry restricts proxies to an ISO 3166-1 alpha-2 exit country.
+	ProxyCountry string `json:"proxy_country,omitempty"`
+
+	// ProxySession keeps fetches sharing the key on the same proxy while it stays healthy.
+	ProxySession string `json:"proxy_session,omitempty"`
+}
+
+// proxyRequest returns the proxy selection for a user's fetches, or nil when no proxy was requested.
+func (o *FetchOptions) proxyRequest(userID string) *ProxyRequest {
+	if o == nil || o.Proxy == "" {
+		return nil
+	}
+	return &ProxyRequest{
+		UserID:  userID,
+		Source:  o.Proxy,
+		Country: o.ProxyCountry,
+		Session: o.ProxySession,
+	}
+}
+
+// resourcePolicy returns the resource policy sent to the captcha service,
+// or nil when nothing is blocked.
+func (o *FetchOptions) resourcePolicy() *captcha.ResourcePolicy {
+	if o == nil {
+		return nil
+	}
+	if (o.ResourcePolicy == "" || o.ResourcePolicy == ResourcePolicyFull) &&
+		len(o.BlockResourceTypes) == 0 && len(o.BlockDomains) == 0 && len(o.BlockURLPatterns) == 0 {
+		return nil
+	}
+	return &captcha.ResourcePolicy{
+		Profile:            o.ResourcePolicy,
+		BlockResourceTypes: o.BlockResourceTypes,
+		BlockDomains:       o.BlockDomains,
+		BlockURLPatterns:   o.BlockURLPatterns,
+	}
+}
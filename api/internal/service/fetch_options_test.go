@@ -0,0 +1,54 @@
+package service
+
+import "testing"
+
+func TestFetchOptions_ResourcePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    *FetchOptions
+		wantNil bool
+		profile string
+	}{
+		{"nil options", nil, true, ""},
+		{"empty options", &FetchOptions{}, true, ""},
+		{"full profile blocks nothing", &FetchOptions{ResourcePolicy: ResourcePolicyFull}, true, ""},
+		{"text_only profile", &FetchOptions{ResourcePolicy: ResourcePolicyTextOnly}, false, ResourcePolicyTextOnly},
+		{"full profile with custom block list", &FetchOptions{ResourcePolicy: ResourcePolicyFull, BlockDomains: []string{"ads.example.com"}}, false, ResourcePolicyFull},
+		{"custom block list only", &FetchOptions{BlockURLPatterns: []string{"*/pixel*"}}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opts.resourcePolicy()
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("resourcePolicy() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("resourcePolicy() = nil")
+			}
+			if got.Profile != tt.profile {
+				t.Errorf("Profile = %q, want %q", got.Profile, tt.profile)
+			}
+			if len(got.BlockDomains) != len(tt.opts.BlockDomains) || len(got.BlockURLPatterns) != len(tt.opts.BlockURLPatterns) {
+				t.Errorf("block lists not copied: %+v", got)
+			}
+		})
+	}
+}
+
+func TestFetchOptions_ProxyRequest(t *testing.T) {
+	if got := (*FetchOptions)(nil).proxyRequest("user-1"); got != nil {
+		t.Errorf("proxyRequest() on nil options = %+v, want nil", got)
+	}
+	if got := (&FetchOptions{ResourcePolicy: ResourcePolicyTextOnly}).proxyRequest("user-1"); got != nil {
+		t.Errorf("proxyRequest() without proxy = %+v, want nil", got)
+	}
+
+	got := (&FetchOptions{Proxy: ProxySourcePool, ProxyCountry: "DE", ProxySession: "s1"}).proxyRequest("user-1")
+	if got == nil || got.UserID != "user-1" || got.Source != ProxySourcePool || got.Country != "DE" || got.Session != "s1" {
+		t.Errorf("proxyRequest() = %+v", got)
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/extractor/generic"
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+	"github.com/jmylchreest/refyne/pkg/refyne"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/documents"
+)
+
+// pageCapture holds the raw HTML of the page most recently fetched by a refyne
+// instance. Cleaners discard the original markup before the extractor runs, so
+// extractor decorators that need it (e.g., structured data) read it from here.
+type pageCapture struct {
+	mu          sync.Mutex
+	url         string
+	html        string
+	contentType string
+	network     []captcha.NetworkResponse
+	screenshots []captcha.Screenshot
+}
+
+// set records a fetched page.
+func (p *pageCapture) set(url, html, contentType string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.url = url
+	p.html = html
+	p.contentType = contentType
+}
+
+// get returns the most recently fetched page URL and HTML.
+func (p *pageCapture) get() (string, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.url, p.html
+}
+
+// setNetwork records the XHR/fetch responses captured while rendering the page.
+// The dynamic fetcher calls it on every fetch, so responses never carry over between pages.
+func (p *pageCapture) setNetwork(responses []captcha.NetworkResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.network = responses
+}
+
+// networkResponses returns the XHR/fetch responses captured for the most recently fetched page.
+func (p *pageCapture) networkResponses() []captcha.NetworkResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.network
+}
+
+// setScreenshots records the full-page screenshot segments taken of the rendered page.
+// The dynamic fetcher calls it on every fetch, so screenshots never carry over between pages.
+func (p *pageCapture) setScreenshots(shots []captcha.Screenshot) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.screenshots = shots
+}
+
+// pageScreenshots returns the screenshot segments taken of the most recently fetched page.
+func (p *pageCapture) pageScreenshots() []captcha.Screenshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.screenshots
+}
+
+// document returns the format of the most recently fetched page when it was a
+// converted document (PDF, DOCX, XLSX or CSV), or "" for web pages.
+func (p *pageCapture) document() documents.Format {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return documents.FormatFromContentType(p.contentType)
+}
+
+// capturingFetcher wraps a fetcher and records every fetched page into a pageCapture.
+type capturingFetcher struct {
+	fetcher.Fetcher
+	capture *pageCapture
+}
+
+// Fetch retrieves the page via the wrapped fetcher and records its HTML.
+func (f *capturingFetcher) Fetch(ctx context.Context, url string, opts fetcher.Options) (fetcher.Content, error) {
+	content, err := f.Fetcher.Fetch(ctx, url, opts)
+	if err == nil {
+		pageURL := content.URL
+		if pageURL == "" {
+			pageURL = url
+		}
+		f.capture.set(pageURL, content.HTML, content.ContentType)
+	}
+	return content, err
+}
+
+// extractorDecorator wraps the LLM extractor used by a refyne instance.
+// Decorators receive the page capture so they can inspect the raw HTML.
+type extractorDecorator func(inner extractor.Extractor, page *pageCapture) extractor.Extractor
+
+// decoratedExtractor is embedded by extractor decorators to hold the extractor they
+// wrap. Decorators only change how pages are extracted, so it delegates Available.
+type decoratedExtractor struct {
+	inner extractor.Extractor
+}
+
+// Available reports whether the wrapped LLM extractor is available.
+func (d *decoratedExtractor) Available() bool {
+	return d.inner.Available()
+}
+
+// buildDecoratedExtractor creates the LLM extractor refyne would build from opts
+// and applies the decorators in order (the last decorator is the outermost).
+// refyne.New has no hook for wrapping its own extractor, so this mirrors its
+// construction; TestBuildDecoratedExtractor_MatchesRefyne fails if they drift apart.
+func buildDecoratedExtractor(opts []refyne.Option, page *pageCapture, decorators []extractorDecorator) (extractor.Extractor, error) {
+	cfg := refyne.DefaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	provider := cfg.Provider
+	if provider == "" {
+		provider = "anthropic" // Same default as refyne.New
+	}
+
+	llmExtractor, err := generic.New(provider, &extractor.LLMConfig{
+		Model:          cfg.Model,
+		APIKey:         cfg.APIKey,
+		BaseURL:        cfg.BaseURL,
+		Temperature:    cfg.Temperature,
+		MaxTokens:      cfg.MaxTokens,
+		MaxRetries:     cfg.MaxRetries,
+		MaxContentSize: cfg.MaxContentSize,
+		StrictMode:     cfg.StrictMode,
+		TargetProvider: cfg.TargetProvider,
+		TargetAPIKey:   cfg.TargetAPIKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create extractor: %w", err)
+	}
+
+	var ext extractor.Extractor = llmExtractor
+	for _, decorate := range decorators {
+		ext = decorate(ext, page)
+	}
+	return ext, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/refyne"
+)
+
+// unexported returns a readable copy of an unexported struct field.
+func unexported(v reflect.Value, name string) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	f := v.FieldByName(name)
+	if !f.IsValid() {
+		return f
+	}
+	if !f.CanAddr() {
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		f = copied.FieldByName(name)
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+}
+
+// comparableFields returns the plain-data fields of a provider (strings, numbers,
+// bools and config structs), skipping clients and caches that differ per instance.
+func comparableFields(v reflect.Value) map[string]any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	fields := make(map[string]any)
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		switch v.Field(i).Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64, reflect.Struct:
+			f := unexported(v, name)
+			if f.Kind() == reflect.Struct && !f.Type().Comparable() {
+				continue
+			}
+			fields[name] = f.Interface()
+		}
+	}
+	return fields
+}
+
+// The decorated extractor is built outside refyne.New, which has no hook for
+// wrapping its extractor. Pin it to what refyne.New builds from the same options so
+// a refyne upgrade that changes extractor construction fails here.
+func TestBuildDecoratedExtractor_MatchesRefyne(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []refyne.Option
+	}{
+		{"default provider", []refyne.Option{refyne.WithProvider(""), refyne.WithAPIKey("sk-ant-test")}},
+		{"openrouter", []refyne.Option{
+			refyne.WithProvider("openrouter"),
+			refyne.WithAPIKey("sk-or-test"),
+			refyne.WithModel("openai/gpt-4o-mini"),
+			refyne.WithTemperature(0.3),
+			refyne.WithMaxTokens(4096),
+			refyne.WithMaxRetries(2),
+			refyne.WithMaxContentSize(50000),
+			refyne.WithStrictMode(true),
+		}},
+		{"ollama", []refyne.Option{
+			refyne.WithProvider("ollama"),
+			refyne.WithBaseURL("http://localhost:11434"),
+			refyne.WithModel("llama3.2"),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := refyne.New(tt.opts...)
+			if err != nil {
+				t.Fatalf("refyne.New() error = %v", err)
+			}
+			want := unexported(reflect.ValueOf(r), "extractor").Interface().(extractor.Extractor)
+
+			got, err := buildDecoratedExtractor(tt.opts, &pageCapture{}, nil)
+			if err != nil {
+				t.Fatalf("buildDecoratedExtractor() error = %v", err)
+			}
+
+			if reflect.TypeOf(got) != reflect.TypeOf(want) {
+				t.Fatalf("extractor type = %T, refyne builds %T", got, want)
+			}
+			if got.Name() != want.Name() || got.Available() != want.Available() {
+				t.Errorf("extractor = %s (available %v), refyne builds %s (available %v)",
+					got.Name(), got.Available(), want.Name(), want.Available())
+			}
+
+			gotBase := unexported(reflect.ValueOf(got), "BaseLLMExtractor")
+			wantBase := unexported(reflect.ValueOf(want), "BaseLLMExtractor")
+			if g, w := unexported(gotBase, "config").Interface(), unexported(wantBase, "config").Interface(); !reflect.DeepEqual(g, w) {
+				t.Errorf("LLM config = %+v, refyne builds %+v", g, w)
+			}
+
+			gotProvider, wantProvider := unexported(gotBase, "provider"), unexported(wantBase, "provider")
+			if gotProvider.Elem().Type() != wantProvider.Elem().Type() {
+				t.Fatalf("provider type = %s, refyne builds %s", gotProvider.Elem().Type(), wantProvider.Elem().Type())
+			}
+			if g, w := comparableFields(gotProvider), comparableFields(wantProvider); !reflect.DeepEqual(g, w) {
+				t.Errorf("provider = %+v, refyne builds %+v", g, w)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/version"
+)
+
+// HAR is an HTTP Archive (HAR 1.2) of the XHR/fetch responses captured while
+// rendering pages. It opens in browser devtools and most HTTP debugging tools.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of a HAR file.
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Pages   []HARPage  `json:"pages,omitempty"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator identifies the application that created the HAR.
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HARPage is a rendered page the entries belong to.
+type HARPage struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     HARPageTimings `json:"pageTimings"`
+}
+
+// HARPageTimings holds page load timings (-1 when unknown).
+type HARPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// HAREntry is a single request/response pair.
+type HAREntry struct {
+	PageRef         string      `json:"pageref,omitempty"`
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+}
+
+// HARRequest is the request half of an entry.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARPostData is a request body.
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARResponse is the response half of an entry.
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARContent is a response body.
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// HARTimings breaks down an entry's time. Only the total is known, so it is reported as wait.
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// HARNameValue is a header, cookie or query parameter.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NewHAR creates an empty HAR created by refyne-api.
+func NewHAR() *HAR {
+	return &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "refyne-api", Version: version.Get().Short()},
+		Entries: []HAREntry{},
+	}}
+}
+
+// AddPage adds a rendered page and the responses captured on it.
+func (h *HAR) AddPage(pageURL string, fetchedAt time.Time, responses []captcha.NetworkResponse) {
+	pageID := fmt.Sprintf("page_%d", len(h.Log.Pages)+1)
+	h.Log.Pages = append(h.Log.Pages, HARPage{
+		StartedDateTime: fetchedAt.UTC().Format(time.RFC3339Nano),
+		ID:              pageID,
+		Title:           pageURL,
+		PageTimings:     HARPageTimings{OnContentLoad: -1, OnLoad: -1},
+	})
+	for _, r := range responses {
+		h.Log.Entries = append(h.Log.Entries, harEntry(pageID, r))
+	}
+}
+
+// Merge adds the pages and entries of other, renumbering its pages.
+func (h *HAR) Merge(other *HAR) {
+	if other == nil {
+		return
+	}
+	ids := make(map[string]string, len(other.Log.Pages))
+	for _, p := range other.Log.Pages {
+		ids[p.ID] = fmt.Sprintf("page_%d", len(h.Log.Pages)+1)
+		p.ID = ids[p.ID]
+		h.Log.Pages = append(h.Log.Pages, p)
+	}
+	for _, e := range other.Log.Entries {
+		e.PageRef = ids[e.PageRef]
+		h.Log.Entries = append(h.Log.Entries, e)
+	}
+}
+
+// BuildHAR returns a HAR for one rendered page, or nil if nothing was captured.
+func BuildHAR(pageURL string, fetchedAt time.Time, responses []captcha.NetworkResponse) *HAR {
+	if len(responses) == 0 {
+		return nil
+	}
+	h := NewHAR()
+	h.AddPage(pageURL, fetchedAt, responses)
+	return h
+}
+
+// harEntry converts a captured response to a HAR entry.
+func harEntry(pageID string, r captcha.NetworkResponse) HAREntry {
+	entry := HAREntry{
+		PageRef:         pageID,
+		StartedDateTime: time.UnixMilli(r.StartedAt).UTC().Format(time.RFC3339Nano),
+		Time:            float64(r.DurationMS),
+		Request: HARRequest{
+			Method:      r.Method,
+			URL:         r.URL,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []HARNameValue{},
+			Headers:     harHeaders(r.RequestHeaders),
+			QueryString: harQueryString(r.URL),
+			HeadersSize: -1,
+			BodySize:    len(r.RequestBody),
+		},
+		Response: HARResponse{
+			Status:      r.Status,
+			StatusText:  r.StatusText,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []HARNameValue{},
+			Headers:     harHeaders(r.ResponseHeaders),
+			Content: HARContent{
+				Size:     len(r.Body),
+				MimeType: r.MimeType,
+				Text:     r.Body,
+			},
+			HeadersSize: -1,
+			BodySize:    len(r.Body),
+		},
+		Timings: HARTimings{Send: 0, Wait: float64(r.DurationMS), Receive: 0},
+	}
+	if r.RequestBody != "" {
+		entry.Request.PostData = &HARPostData{MimeType: r.RequestHeaders["Content-Type"], Text: r.RequestBody}
+	}
+	if r.BodyTruncated {
+		entry.Response.Content.Comment = "body truncated"
+		entry.Response.BodySize = -1
+	}
+	return entry
+}
+
+// harHeaders converts a header map to sorted HAR name/value pairs.
+func harHeaders(headers map[string]string) []HARNameValue {
+	out := make([]HARNameValue, 0, len(headers))
+	for name, value := range headers {
+		out = append(out, HARNameValue{Name: name, Value: value})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// harQueryString returns the query parameters of rawURL.
+func harQueryString(rawURL string) []HARNameValue {
+	out := []HARNameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return out
+	}
+	for name, values := range u.Query() {
+		for _, value := range values {
+			out = append(out, HARNameValue{Name: name, Value: value})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+)
+
+func TestBuildHAR(t *testing.T) {
+	if har := BuildHAR("https://example.com", time.Now(), nil); har != nil {
+		t.Errorf("BuildHAR() with no responses = %+v, want nil", har)
+	}
+
+	har := BuildHAR("https://example.com/products", time.Unix(1700000000, 0), []captcha.NetworkResponse{
+		{
+			URL:             "https://example.com/api/products?page=2&sort=price",
+			Method:          "POST",
+			Status:          200,
+			StatusText:      "OK",
+			MimeType:        "application/json",
+			RequestHeaders:  map[string]string{"Content-Type": "application/json", "Accept": "*/*"},
+			RequestBody:     `{"q":"shoes"}`,
+			ResponseHeaders: map[string]string{"Content-Type": "application/json"},
+			Body:            `{"items":[]}`,
+			BodyTruncated:   true,
+			StartedAt:       1700000000500,
+			DurationMS:      120,
+		},
+	})
+	if har == nil {
+		t.Fatal("BuildHAR() = nil")
+	}
+	if har.Log.Version != "1.2" || len(har.Log.Pages) != 1 || len(har.Log.Entries) != 1 {
+		t.Fatalf("log = %+v", har.Log)
+	}
+
+	entry := har.Log.Entries[0]
+	if entry.PageRef != har.Log.Pages[0].ID {
+		t.Errorf("pageref = %q, want %q", entry.PageRef, har.Log.Pages[0].ID)
+	}
+	if entry.Request.Method != "POST" || entry.Request.PostData == nil || entry.Request.PostData.MimeType != "application/json" {
+		t.Errorf("request = %+v", entry.Request)
+	}
+	if len(entry.Request.QueryString) != 2 || entry.Request.QueryString[0].Name != "page" {
+		t.Errorf("queryString = %+v", entry.Request.QueryString)
+	}
+	if len(entry.Request.Headers) != 2 || entry.Request.Headers[0].Name != "Accept" {
+		t.Errorf("headers not sorted: %+v", entry.Request.Headers)
+	}
+	if entry.Response.Content.Text != `{"items":[]}` || entry.Response.Content.Comment == "" || entry.Response.BodySize != -1 {
+		t.Errorf("response = %+v", entry.Response)
+	}
+	if entry.Time != 120 || entry.StartedDateTime != "2023-11-14T22:13:20.5Z" {
+		t.Errorf("time = %v, startedDateTime = %q", entry.Time, entry.StartedDateTime)
+	}
+
+	if _, err := json.Marshal(har); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
+
+func TestJobDebugCapture_HAR(t *testing.T) {
+	response := []captcha.NetworkResponse{{URL: "https://example.com/api/a", Method: "GET", Status: 200}}
+
+	capture := &JobDebugCapture{Captures: []LLMRequestCapture{{URL: "https://example.com/1"}}}
+	if har := capture.HAR(); har != nil {
+		t.Errorf("HAR() without network captures = %+v, want nil", har)
+	}
+
+	capture.Captures = []LLMRequestCapture{
+		{URL: "https://example.com/1", HAR: BuildHAR("https://example.com/1", time.Now(), response)},
+		{URL: "https://example.com/2"},
+		{URL: "https://example.com/3", HAR: BuildHAR("https://example.com/3", time.Now(), append(response, response...))},
+	}
+
+	har := capture.HAR()
+	if har == nil {
+		t.Fatal("HAR() = nil")
+	}
+	if len(har.Log.Pages) != 2 || len(har.Log.Entries) != 3 {
+		t.Fatalf("pages = %d, entries = %d, want 2 and 3", len(har.Log.Pages), len(har.Log.Entries))
+	}
+	if har.Log.Pages[0].ID == har.Log.Pages[1].ID {
+		t.Errorf("page IDs not renumbered: %q", har.Log.Pages[0].ID)
+	}
+	if har.Log.Entries[0].PageRef != har.Log.Pages[0].ID || har.Log.Entries[2].PageRef != har.Log.Pages[1].ID {
+		t.Errorf("entries reference wrong pages: %+v", har.Log.Entries)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 	"github.com/jmylchreest/refyne-api/internal/models"
 )
 
@@ -87,6 +88,9 @@ type DebugCaptureData struct {
 	Prompt       string            // Full prompt for analyze jobs (legacy)
 	RawContent   string            // Page content sent to LLM
 	Hints        map[string]string // Preprocessing hints applied
+	HAR          *HAR              // XHR/fetch responses captured while rendering
+
+	Screenshots []captcha.Screenshot // Screenshots sent to a vision model (visual/hybrid input modes)
 
 	// Response data
 	RawLLMResponse string // Raw LLM output
@@ -9,8 +9,10 @@ import (
 
 	"github.com/oklog/ulid/v2"
 
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 	"github.com/jmylchreest/refyne-api/internal/config"
 	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/observability"
 	"github.com/jmylchreest/refyne-api/internal/repository"
 )
 
@@ -21,6 +23,8 @@ type JobService struct {
 	storageSvc *StorageService
 	webhookSvc *WebhookService
 	logger     *slog.Logger
+
+	partialResults *PartialResultBroker // Streams provisional items to /jobs/{id}/stream
 }
 
 // NewJobService creates a new job service.
@@ -30,9 +34,16 @@ func NewJobService(cfg *config.Config, repos *repository.Repositories, storageSv
 		repos:      repos,
 		storageSvc: storageSvc,
 		logger:     logger,
+
+		partialResults: NewPartialResultBroker(),
 	}
 }
 
+// PartialResults returns the broker for provisional results of running jobs.
+func (s *JobService) PartialResults() *PartialResultBroker {
+	return s.partialResults
+}
+
 // SetWebhookService sets the webhook service for job completion notifications.
 // This allows for late binding to avoid circular dependencies.
 func (s *JobService) SetWebhookService(webhookSvc *WebhookService) {
@@ -110,6 +121,7 @@ func (s *JobService) createJobRecord(ctx context.Context, executor JobExecutor,
 		IsBYOK:       executor.IsBYOK(),
 		CaptureDebug: opts.CaptureDebug,
 		PageCount:    1, // Default for single-page jobs
+		TraceParent:  observability.TraceParent(ctx),
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -250,6 +262,8 @@ func (s *JobService) handleJobSuccess(ctx context.Context, job *models.Job, exec
 							ParseError:   result.DebugCapture.ParseError,
 						},
 					},
+					HAR:         result.DebugCapture.HAR,
+					Screenshots: NewDebugScreenshots(result.DebugCapture.Screenshots),
 				},
 			},
 		}
@@ -343,34 +357,46 @@ func (opts *RunJobOptions) GetJobID() string {
 
 // CrawlOptions represents crawl job options.
 type CrawlOptions struct {
-	FollowSelector        string          `json:"follow_selector,omitempty"`
-	FollowPattern         string          `json:"follow_pattern,omitempty"`
-	MaxDepth              int             `json:"max_depth,omitempty"`
-	NextSelector          string          `json:"next_selector,omitempty"`
-	MaxPages              int             `json:"max_pages,omitempty"`
-	MaxURLs               int             `json:"max_urls,omitempty"`
-	Delay                 string          `json:"delay,omitempty"`
-	Concurrency           int             `json:"concurrency,omitempty"`
-	SameDomainOnly        bool            `json:"same_domain_only,omitempty"`
-	ExtractFromSeeds      bool            `json:"extract_from_seeds,omitempty"`
-	UseSitemap            bool            `json:"use_sitemap,omitempty"`
-	FetchMode             string          `json:"fetch_mode,omitempty"`              // auto, static, or dynamic
-	ContentDynamicAllowed bool            `json:"content_dynamic_allowed,omitempty"` // Whether user has content_dynamic feature (set at job creation)
-	SkipCreditCheck       bool            `json:"skip_credit_check,omitempty"`       // Whether user has skip_credit_check feature (disables mid-crawl balance check)
-	CleanerChain          []CleanerConfig `json:"cleaner_chain,omitempty"`
+	FollowSelector        string                `json:"follow_selector,omitempty"`
+	FollowPattern         string                `json:"follow_pattern,omitempty"`
+	MaxDepth              int                   `json:"max_depth,omitempty"`
+	NextSelector          string                `json:"next_selector,omitempty"`
+	MaxPages              int                   `json:"max_pages,omitempty"`
+	MaxURLs               int                   `json:"max_urls,omitempty"`
+	Delay                 string                `json:"delay,omitempty"`
+	Concurrency           int                   `json:"concurrency,omitempty"`
+	SameDomainOnly        bool                  `json:"same_domain_only,omitempty"`
+	ExtractFromSeeds      bool                  `json:"extract_from_seeds,omitempty"`
+	UseSitemap            bool                  `json:"use_sitemap,omitempty"`
+	FetchMode             string                `json:"fetch_mode,omitempty"`              // auto, static, or dynamic
+	ContentDynamicAllowed bool                  `json:"content_dynamic_allowed,omitempty"` // Whether user has content_dynamic feature (set at job creation)
+	SkipCreditCheck       bool                  `json:"skip_credit_check,omitempty"`       // Whether user has skip_credit_check feature (disables mid-crawl balance check)
+	APIKeyID              string                `json:"api_key_id,omitempty"`              // API key that created the job (for per-key spend caps)
+	RoutingPolicy         models.RoutingPolicy  `json:"routing_policy,omitempty"`          // Policy that ordered the LLM config chain (set at job creation)
+	RoutingReason         string                `json:"routing_reason,omitempty"`          // Why the policy ordered the chain as it did
+	CleanerChain          []CleanerConfig       `json:"cleaner_chain,omitempty"`
+	Preprocessors         []PreprocessorConfig  `json:"preprocessors,omitempty"`
+	StructuredData        *StructuredDataConfig `json:"structured_data,omitempty"` // Structured data fast path (JSON-LD, microdata, OpenGraph)
+	Actions               []captcha.Action      `json:"actions,omitempty"`         // Browser actions run on each page before capture
+	CaptureNetwork        *NetworkCaptureConfig `json:"capture_network,omitempty"` // Record XHR/fetch responses on each page
+	FetchOptions          *FetchOptions         `json:"fetch_options,omitempty"`   // Resource blocking and proxy selection
+	AuthProfileID         string                `json:"auth_profile_id,omitempty"` // Stored login to fetch pages with
+
+	InputMode InputMode `json:"input_mode,omitempty"` // text (default), visual or hybrid - screenshot modes imply browser rendering
 }
 
 // CreateCrawlJobInput represents input for creating a crawl job.
 type CreateCrawlJobInput struct {
-	URL          string            `json:"url"`
-	Schema       json.RawMessage   `json:"schema"`
-	Options      CrawlOptions      `json:"options,omitempty"`
-	CleanerChain []CleanerConfig   `json:"cleaner_chain,omitempty"` // Content cleaner chain
-	WebhookURL   string            `json:"webhook_url,omitempty"`
-	LLMConfigs   []*LLMConfigInput `json:"llm_configs"`        // Pre-resolved LLM config chain
-	Tier         string            `json:"tier"`               // User's subscription tier at job creation time
-	IsBYOK       bool              `json:"is_byok"`            // Whether using user's own API keys
-	CaptureDebug *bool             `json:"capture_debug,omitempty"` // Whether to capture LLM requests for debugging
+	URL           string               `json:"url"`
+	Schema        json.RawMessage      `json:"schema"`
+	Options       CrawlOptions         `json:"options,omitempty"`
+	CleanerChain  []CleanerConfig      `json:"cleaner_chain,omitempty"` // Content cleaner chain
+	Preprocessors []PreprocessorConfig `json:"preprocessors,omitempty"` // Preprocessor chain for prompt hints
+	WebhookURL    string               `json:"webhook_url,omitempty"`
+	LLMConfigs    []*LLMConfigInput    `json:"llm_configs"`             // Pre-resolved LLM config chain
+	Tier          string               `json:"tier"`                    // User's subscription tier at job creation time
+	IsBYOK        bool                 `json:"is_byok"`                 // Whether using user's own API keys
+	CaptureDebug  *bool                `json:"capture_debug,omitempty"` // Whether to capture LLM requests for debugging
 }
 
 // CreateCrawlJobOutput represents output from creating a crawl job.
@@ -382,13 +408,16 @@ type CreateCrawlJobOutput struct {
 
 // CreateCrawlJob creates a new crawl job.
 func (s *JobService) CreateCrawlJob(ctx context.Context, userID string, input CreateCrawlJobInput) (*CreateCrawlJobOutput, error) {
-	// Include cleaner chain in options for storage
+	// Include cleaner chain and preprocessors in options for storage
 	options := input.Options
 	if len(input.CleanerChain) > 0 {
 		options.CleanerChain = input.CleanerChain
 	}
+	if len(input.Preprocessors) > 0 {
+		options.Preprocessors = input.Preprocessors
+	}
 
-	// Serialize options (includes cleaner chain)
+	// Serialize options (includes cleaner chain and preprocessors)
 	optionsJSON, err := json.Marshal(options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize options: %w", err)
@@ -420,6 +449,7 @@ func (s *JobService) CreateCrawlJob(ctx context.Context, userID string, input Cr
 		IsBYOK:           input.IsBYOK,
 		CaptureDebug:     captureDebug,
 		WebhookURL:       input.WebhookURL,
+		TraceParent:      observability.TraceParent(ctx), // Lets the worker continue the request's trace
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
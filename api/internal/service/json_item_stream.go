@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// jsonItemStream incrementally scans a JSON document as it is written (e.g., streamed
+// from an LLM) and reports each element of a top-level array as soon as the element is
+// complete. Arrays are top-level when they are the document root or a property of the
+// root object, which covers both `[{...}, ...]` and `{"products": [{...}, ...]}`.
+// Text before the document (such as a markdown code fence) is ignored.
+type jsonItemStream struct {
+	onItem func(field string, index int, item json.RawMessage)
+
+	buf      []byte
+	pos      int
+	stack    []byte // open containers: '{' or '['
+	inString bool
+	escape   bool
+	strStart int
+
+	lastKey      string // last string seen directly inside the root object
+	collecting   bool   // inside a top-level array
+	collectDepth int    // stack depth of the top-level array
+	field        string // root property holding the array ("" for a root array)
+	index        int    // index of the next item in the array
+	itemStart    int    // buffer offset of the current item, -1 if none
+}
+
+// newJSONItemStream creates a stream that calls onItem for each completed top-level array item.
+func newJSONItemStream(onItem func(field string, index int, item json.RawMessage)) *jsonItemStream {
+	return &jsonItemStream{onItem: onItem, itemStart: -1}
+}
+
+// Write appends a fragment of the document and reports any items it completes.
+func (s *jsonItemStream) Write(fragment string) {
+	s.buf = append(s.buf, fragment...)
+	for ; s.pos < len(s.buf); s.pos++ {
+		s.scan(s.pos, s.buf[s.pos])
+	}
+}
+
+func (s *jsonItemStream) scan(i int, c byte) {
+	if s.inString {
+		switch {
+		case s.escape:
+			s.escape = false
+		case c == '\\':
+			s.escape = true
+		case c == '"':
+			s.inString = false
+			if len(s.stack) == 1 && s.stack[0] == '{' {
+				var key string
+				if json.Unmarshal(s.buf[s.strStart:i+1], &key) == nil {
+					s.lastKey = key
+				}
+			}
+		}
+		return
+	}
+
+	atItemLevel := s.collecting && len(s.stack) == s.collectDepth
+
+	switch c {
+	case '"':
+		s.inString = true
+		s.strStart = i
+		if atItemLevel && s.itemStart < 0 {
+			s.itemStart = i
+		}
+	case '{', '[':
+		if atItemLevel && s.itemStart < 0 {
+			s.itemStart = i
+		}
+		s.stack = append(s.stack, c)
+		if c == '[' && !s.collecting && (len(s.stack) == 1 || (len(s.stack) == 2 && s.stack[0] == '{')) {
+			s.collecting = true
+			s.collectDepth = len(s.stack)
+			s.index = 0
+			s.field = ""
+			if len(s.stack) == 2 {
+				s.field = s.lastKey
+			}
+		}
+	case '}', ']':
+		if len(s.stack) == 0 {
+			return
+		}
+		s.stack = s.stack[:len(s.stack)-1]
+		switch {
+		case s.collecting && len(s.stack) == s.collectDepth-1:
+			// The top-level array closed; emit a trailing primitive item
+			s.emit(s.itemStart, i)
+			s.collecting = false
+		case s.collecting && len(s.stack) == s.collectDepth && s.itemStart >= 0:
+			// An object or array item closed
+			s.emit(s.itemStart, i+1)
+		}
+	case ',':
+		if atItemLevel {
+			s.emit(s.itemStart, i)
+		}
+	case ' ', '\t', '\n', '\r':
+	default:
+		if atItemLevel && s.itemStart < 0 {
+			s.itemStart = i
+		}
+	}
+}
+
+// emit reports buf[start:end] as the next item if it holds a complete JSON value.
+func (s *jsonItemStream) emit(start, end int) {
+	s.itemStart = -1
+	if start < 0 {
+		return
+	}
+	item := bytes.TrimSpace(s.buf[start:end])
+	if len(item) == 0 || !json.Valid(item) {
+		return
+	}
+	s.onItem(s.field, s.index, json.RawMessage(bytes.Clone(item)))
+	s.index++
+}
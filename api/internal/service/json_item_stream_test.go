@@ -0,0 +1,77 @@
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSONItemStream(t *testing.T) {
+	type item struct {
+		Field string
+		Index int
+		JSON  string
+	}
+
+	tests := []struct {
+		name      string
+		fragments []string
+		want      []item
+	}{
+		{
+			name:      "root array",
+			fragments: []string{`[{"a":1},{"a":2}]`},
+			want:      []item{{"", 0, `{"a":1}`}, {"", 1, `{"a":2}`}},
+		},
+		{
+			name:      "array property of root object",
+			fragments: []string{`{"title":"x","products":[{"name":"A"},{"name":"B"}],"count":2}`},
+			want:      []item{{"products", 0, `{"name":"A"}`}, {"products", 1, `{"name":"B"}`}},
+		},
+		{
+			name:      "fragmented writes",
+			fragments: []string{`{"it`, `ems": [ {"n`, `ame": "A"`, ` }, {"name"`, `: "B"}`, ` ]}`},
+			want:      []item{{"items", 0, `{"name": "A" }`}, {"items", 1, `{"name": "B"}`}},
+		},
+		{
+			name:      "brackets inside strings",
+			fragments: []string{`[{"t":"a ] b, {c}"},{"t":"\"]\""}]`},
+			want:      []item{{"", 0, `{"t":"a ] b, {c}"}`}, {"", 1, `{"t":"\"]\""}`}},
+		},
+		{
+			name:      "code fence prefix",
+			fragments: []string{"```json\n", `{"items":[{"a":1}]}`, "\n```"},
+			want:      []item{{"items", 0, `{"a":1}`}},
+		},
+		{
+			name:      "primitive items",
+			fragments: []string{`{"tags":["a", "b",`, ` 3]}`},
+			want:      []item{{"tags", 0, `"a"`}, {"tags", 1, `"b"`}, {"tags", 2, `3`}},
+		},
+		{
+			name:      "nested arrays are not top-level",
+			fragments: []string{`{"meta":{"list":[1,2]},"items":[{"sub":[1,2]}]}`},
+			want:      []item{{"items", 0, `{"sub":[1,2]}`}},
+		},
+		{
+			name:      "incomplete item is not emitted",
+			fragments: []string{`{"items":[{"a":1},{"a":`},
+			want:      []item{{"items", 0, `{"a":1}`}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []item
+			s := newJSONItemStream(func(field string, index int, raw json.RawMessage) {
+				got = append(got, item{field, index, string(raw)})
+			})
+			for _, f := range tt.fragments {
+				s.Write(f)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/config"
+	"github.com/jmylchreest/refyne-api/internal/license"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+)
+
+var (
+	// ErrInvalidLicense is returned when a license token fails verification.
+	ErrInvalidLicense = errors.New("invalid license")
+
+	// ErrLicenseExpired is returned when installing a license whose grace period has ended.
+	ErrLicenseExpired = errors.New("license has expired")
+)
+
+// LicenseStatus is the result of the most recent license check.
+type LicenseStatus struct {
+	Status      license.Status
+	License     *models.License // Installed license, with entitlements taken from its verified token
+	GraceEndsAt *time.Time      // When an expired license stops granting features
+	Error       string          // Why the license is invalid
+	CheckedAt   time.Time
+}
+
+// Active returns true if the license currently grants its entitlements.
+func (s LicenseStatus) Active() bool {
+	return s.License != nil && s.Status.Active()
+}
+
+// LicenseService verifies and installs self-hosted licenses.
+// Licenses are verified offline against an Ed25519 public key, persisted so a
+// license replaced via the admin API survives restarts, and re-checked
+// periodically so expiry takes effect without a restart.
+type LicenseService struct {
+	repos       *repository.Repositories
+	publicKey   ed25519.PublicKey
+	configKey   string
+	gracePeriod time.Duration
+	logger      *slog.Logger
+	nowFunc     func() time.Time
+
+	mu        sync.RWMutex
+	status    LicenseStatus
+	configErr error // Why REFYNE_LICENSE_KEY couldn't be installed
+}
+
+// NewLicenseService creates a new license service.
+// The public key comes from cfg.LicensePublicKey, falling back to the build-time key.
+func NewLicenseService(cfg *config.Config, repos *repository.Repositories, logger *slog.Logger) (*LicenseService, error) {
+	keyStr := cfg.LicensePublicKey
+	if keyStr == "" {
+		keyStr = license.PublicKey
+	}
+
+	var publicKey ed25519.PublicKey
+	if keyStr != "" {
+		var err error
+		publicKey, err = license.ParsePublicKey(keyStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &LicenseService{
+		repos:       repos,
+		publicKey:   publicKey,
+		configKey:   cfg.LicenseKey,
+		gracePeriod: cfg.LicenseGracePeriod,
+		logger:      logger,
+		nowFunc:     time.Now,
+		status:      LicenseStatus{Status: license.StatusMissing},
+	}, nil
+}
+
+// Load installs the configured license key, if it hasn't been installed before, and
+// checks the active license. A configured key that was since replaced via the admin
+// API is not reinstated.
+func (s *LicenseService) Load(ctx context.Context) (LicenseStatus, error) {
+	if s.configKey != "" {
+		existing, err := s.repos.License.GetByKey(ctx, s.configKey)
+		if err != nil {
+			return s.Status(), fmt.Errorf("failed to get license: %w", err)
+		}
+
+		switch {
+		case existing == nil:
+			if err := s.install(ctx, s.configKey); err != nil {
+				if !errors.Is(err, ErrInvalidLicense) && !errors.Is(err, ErrLicenseExpired) {
+					return s.Status(), err
+				}
+				s.mu.Lock()
+				s.configErr = err
+				s.mu.Unlock()
+				s.logger.Error("configured license could not be installed", "error", err)
+			}
+		case existing.RevokedAt != nil:
+			s.logger.Info("configured license was replaced, using the installed license",
+				"license_id", existing.ID,
+			)
+		}
+	}
+
+	return s.Check(ctx)
+}
+
+// Start re-checks the license every interval until ctx is cancelled.
+func (s *LicenseService) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Check(ctx); err != nil {
+					s.logger.Warn("license check failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// Check re-verifies the active license and updates the current status.
+// If the license can't be read, the previous status is kept.
+func (s *LicenseService) Check(ctx context.Context) (LicenseStatus, error) {
+	active, err := s.repos.License.GetActive(ctx)
+	if err != nil {
+		return s.Status(), fmt.Errorf("failed to get license: %w", err)
+	}
+
+	s.mu.Lock()
+	status := s.evaluate(active)
+	previous := s.status.Status
+	s.status = status
+	s.mu.Unlock()
+
+	s.logStatus(status, previous)
+	return status, nil
+}
+
+// Replace verifies and installs a license, revoking the previously active one.
+func (s *LicenseService) Replace(ctx context.Context, token string) (LicenseStatus, error) {
+	if err := s.install(ctx, token); err != nil {
+		return s.Status(), err
+	}
+
+	s.mu.Lock()
+	s.configErr = nil
+	s.mu.Unlock()
+
+	return s.Check(ctx)
+}
+
+// Status returns the result of the most recent license check.
+func (s *LicenseService) Status() LicenseStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Entitlements returns the tier and features granted by the license.
+// ok is false when no license is active.
+func (s *LicenseService) Entitlements() (tier string, features []string, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !s.status.Active() {
+		return "", nil, false
+	}
+	return s.status.License.Tier, s.status.License.Features, true
+}
+
+// install verifies a license token and makes it the active license.
+func (s *LicenseService) install(ctx context.Context, token string) error {
+	claims, err := license.Verify(token, s.publicKey)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidLicense, err)
+	}
+
+	now := s.nowFunc().UTC()
+	if claims.StatusAt(now, s.gracePeriod) == license.StatusExpired {
+		return ErrLicenseExpired
+	}
+
+	previous, err := s.repos.License.GetActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get license: %w", err)
+	}
+
+	lic, err := s.repos.License.GetByKey(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to get license: %w", err)
+	}
+	if lic == nil {
+		lic = &models.License{
+			ID:         ulid.Make().String(),
+			LicenseKey: token,
+			CreatedAt:  now,
+		}
+		applyLicenseClaims(lic, claims)
+		if err := s.repos.License.Create(ctx, lic); err != nil {
+			return fmt.Errorf("failed to save license: %w", err)
+		}
+	} else if lic.RevokedAt != nil {
+		lic.RevokedAt = nil
+		if err := s.repos.License.Update(ctx, lic); err != nil {
+			return fmt.Errorf("failed to reinstate license: %w", err)
+		}
+	}
+
+	if previous != nil && previous.ID != lic.ID {
+		previous.RevokedAt = &now
+		if err := s.repos.License.Update(ctx, previous); err != nil {
+			return fmt.Errorf("failed to revoke previous license: %w", err)
+		}
+	}
+
+	s.logger.Info("license installed",
+		"license_id", lic.ID,
+		"organization", claims.OrganizationName,
+		"tier", claims.Tier,
+		"expires_at", claims.Expiry(),
+	)
+	return nil
+}
+
+// evaluate verifies the active license and computes its status.
+// Entitlements are taken from the signed token rather than the stored columns.
+// Must be called with s.mu held.
+func (s *LicenseService) evaluate(active *models.License) LicenseStatus {
+	now := s.nowFunc().UTC()
+	status := LicenseStatus{CheckedAt: now}
+
+	if active == nil {
+		status.Status = license.StatusMissing
+		if s.configErr != nil {
+			status.Status = license.StatusInvalid
+			status.Error = s.configErr.Error()
+		}
+		return status
+	}
+
+	lic := *active
+	status.License = &lic
+
+	claims, err := license.Verify(lic.LicenseKey, s.publicKey)
+	if err != nil {
+		status.Status = license.StatusInvalid
+		status.Error = err.Error()
+		return status
+	}
+	applyLicenseClaims(&lic, claims)
+
+	status.Status = claims.StatusAt(now, s.gracePeriod)
+	if expiry := claims.Expiry(); expiry != nil {
+		graceEndsAt := expiry.Add(s.gracePeriod)
+		status.GraceEndsAt = &graceEndsAt
+	}
+	return status
+}
+
+// logStatus logs license status changes, and warns on every check during the grace period.
+func (s *LicenseService) logStatus(status LicenseStatus, previous license.Status) {
+	switch status.Status {
+	case license.StatusGrace:
+		s.logger.Warn("license has expired and is in its grace period",
+			"expires_at", status.License.ExpiresAt,
+			"grace_ends_at", status.GraceEndsAt,
+		)
+	case previous:
+		return
+	case license.StatusValid:
+		s.logger.Info("license verified",
+			"license_id", status.License.ID,
+			"organization", status.License.OrganizationName,
+			"tier", status.License.Tier,
+			"features", status.License.Features,
+			"expires_at", status.License.ExpiresAt,
+		)
+	case license.StatusExpired:
+		s.logger.Error("license has expired, licensed features are disabled",
+			"expires_at", status.License.ExpiresAt,
+		)
+	case license.StatusInvalid:
+		s.logger.Error("license is invalid, licensed features are disabled", "error", status.Error)
+	case license.StatusMissing:
+		s.logger.Info("no license installed, licensed features are disabled")
+	}
+}
+
+// applyLicenseClaims copies a license token's signed claims onto its record.
+func applyLicenseClaims(lic *models.License, claims *license.Claims) {
+	lic.OrganizationName = claims.OrganizationName
+	lic.Email = claims.Email
+	lic.Tier = claims.Tier
+	lic.MaxUsers = claims.MaxUsers
+	lic.Features = claims.Features
+	lic.IssuedAt = time.Unix(claims.IssuedAt, 0).UTC()
+	lic.ExpiresAt = claims.Expiry()
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/config"
+	"github.com/jmylchreest/refyne-api/internal/license"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+)
+
+// mockLicenseRepository implements repository.LicenseRepository for testing.
+type mockLicenseRepository struct {
+	mu       sync.Mutex
+	licenses []*models.License
+}
+
+func (m *mockLicenseRepository) Create(_ context.Context, lic *models.License) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *lic
+	m.licenses = append(m.licenses, &stored)
+	return nil
+}
+
+func (m *mockLicenseRepository) GetByKey(_ context.Context, key string) (*models.License, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, lic := range m.licenses {
+		if lic.LicenseKey == key {
+			found := *lic
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockLicenseRepository) Update(_ context.Context, lic *models.License) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.licenses {
+		if existing.ID == lic.ID {
+			updated := *lic
+			m.licenses[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (m *mockLicenseRepository) List(_ context.Context, limit, offset int) ([]*models.License, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.licenses, nil
+}
+
+// GetActive returns the most recently created unrevoked license (creation order in the mock).
+func (m *mockLicenseRepository) GetActive(_ context.Context) (*models.License, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.licenses) - 1; i >= 0; i-- {
+		if m.licenses[i].RevokedAt == nil {
+			found := *m.licenses[i]
+			return &found, nil
+		}
+	}
+	return nil, nil
+}
+
+type licenseTestKeys struct {
+	publicKey  string
+	privateKey string
+}
+
+func newLicenseTestKeys(t *testing.T) licenseTestKeys {
+	t.Helper()
+	pub, priv, err := license.GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return licenseTestKeys{publicKey: pub, privateKey: priv}
+}
+
+func (k licenseTestKeys) issue(t *testing.T, claims license.Claims) string {
+	t.Helper()
+	key, err := license.ParsePrivateKey(k.privateKey)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	token, err := license.Issue(claims, key)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	return token
+}
+
+func newTestLicenseService(t *testing.T, keys licenseTestKeys, configKey string, now time.Time) (*LicenseService, *mockLicenseRepository) {
+	t.Helper()
+	repo := &mockLicenseRepository{}
+	cfg := &config.Config{
+		LicenseKey:         configKey,
+		LicensePublicKey:   keys.publicKey,
+		LicenseGracePeriod: 7 * 24 * time.Hour,
+	}
+	svc, err := NewLicenseService(cfg, &repository.Repositories{License: repo}, slog.Default())
+	if err != nil {
+		t.Fatalf("NewLicenseService() error = %v", err)
+	}
+	svc.nowFunc = func() time.Time { return now }
+	return svc, repo
+}
+
+func TestLicenseService_LoadConfiguredLicense(t *testing.T) {
+	keys := newLicenseTestKeys(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	token := keys.issue(t, license.Claims{
+		ID:               "lic_1",
+		OrganizationName: "Acme",
+		Tier:             "selfhosted",
+		MaxUsers:         10,
+		Features:         []string{"webhooks", "content_dynamic"},
+		IssuedAt:         now.Add(-24 * time.Hour).Unix(),
+		ExpiresAt:        now.Add(30 * 24 * time.Hour).Unix(),
+	})
+
+	svc, repo := newTestLicenseService(t, keys, token, now)
+
+	if _, _, ok := svc.Entitlements(); ok {
+		t.Error("Entitlements() ok before Load, want false")
+	}
+
+	status, err := svc.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if status.Status != license.StatusValid {
+		t.Fatalf("Status = %q, want %q (error: %s)", status.Status, license.StatusValid, status.Error)
+	}
+	if len(repo.licenses) != 1 || repo.licenses[0].OrganizationName != "Acme" || repo.licenses[0].MaxUsers != 10 {
+		t.Errorf("stored licenses = %+v", repo.licenses)
+	}
+
+	tier, features, ok := svc.Entitlements()
+	if !ok || tier != "selfhosted" || len(features) != 2 {
+		t.Errorf("Entitlements() = %q, %v, %v", tier, features, ok)
+	}
+
+	// Loading again (restart) doesn't store the license twice
+	if _, err := svc.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(repo.licenses) != 1 {
+		t.Errorf("stored %d licenses after reload, want 1", len(repo.licenses))
+	}
+}
+
+func TestLicenseService_GracePeriod(t *testing.T) {
+	keys := newLicenseTestKeys(t)
+	expires := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	token := keys.issue(t, license.Claims{
+		ID:        "lic_1",
+		Tier:      "selfhosted",
+		Features:  []string{"webhooks"},
+		IssuedAt:  expires.AddDate(-1, 0, 0).Unix(),
+		ExpiresAt: expires.Unix(),
+	})
+
+	now := expires.Add(-time.Hour)
+	svc, _ := newTestLicenseService(t, keys, token, now)
+	svc.nowFunc = func() time.Time { return now }
+
+	if status, _ := svc.Load(context.Background()); status.Status != license.StatusValid {
+		t.Fatalf("Status = %q before expiry, want %q", status.Status, license.StatusValid)
+	}
+
+	now = expires.Add(3 * 24 * time.Hour)
+	status, err := svc.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if status.Status != license.StatusGrace {
+		t.Errorf("Status = %q within grace, want %q", status.Status, license.StatusGrace)
+	}
+	if status.GraceEndsAt == nil || !status.GraceEndsAt.Equal(expires.Add(7*24*time.Hour)) {
+		t.Errorf("GraceEndsAt = %v", status.GraceEndsAt)
+	}
+	if _, _, ok := svc.Entitlements(); !ok {
+		t.Error("Entitlements() not ok during grace period")
+	}
+
+	now = expires.Add(8 * 24 * time.Hour)
+	if status, _ := svc.Check(context.Background()); status.Status != license.StatusExpired {
+		t.Errorf("Status = %q after grace, want %q", status.Status, license.StatusExpired)
+	}
+	if _, _, ok := svc.Entitlements(); ok {
+		t.Error("Entitlements() ok after grace period ended")
+	}
+}
+
+func TestLicenseService_Replace(t *testing.T) {
+	keys := newLicenseTestKeys(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	original := keys.issue(t, license.Claims{ID: "lic_1", Tier: "selfhosted", IssuedAt: now.Unix()})
+	replacement := keys.issue(t, license.Claims{
+		ID:       "lic_2",
+		Tier:     "selfhosted",
+		Features: []string{"webhooks"},
+		IssuedAt: now.Unix(),
+	})
+
+	svc, repo := newTestLicenseService(t, keys, original, now)
+	if _, err := svc.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	status, err := svc.Replace(context.Background(), replacement)
+	if err != nil {
+		t.Fatalf("Replace() error = %v", err)
+	}
+	if status.Status != license.StatusValid || status.License.LicenseKey != replacement {
+		t.Errorf("Replace() status = %+v", status)
+	}
+	if repo.licenses[0].RevokedAt == nil {
+		t.Error("original license was not revoked")
+	}
+
+	// On restart the configured key was replaced, so it isn't reinstated
+	restarted, _ := newTestLicenseService(t, keys, original, now)
+	restarted.repos.License = repo
+	status, err = restarted.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if status.License == nil || status.License.LicenseKey != replacement {
+		t.Errorf("after restart active license = %+v, want replacement", status.License)
+	}
+}
+
+func TestLicenseService_RejectsInvalidLicenses(t *testing.T) {
+	keys := newLicenseTestKeys(t)
+	otherKeys := newLicenseTestKeys(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	forged := otherKeys.issue(t, license.Claims{ID: "lic_forged", Tier: "selfhosted", IssuedAt: now.Unix()})
+	expired := keys.issue(t, license.Claims{
+		ID:        "lic_old",
+		Tier:      "selfhosted",
+		IssuedAt:  now.AddDate(-1, 0, 0).Unix(),
+		ExpiresAt: now.AddDate(0, -1, 0).Unix(),
+	})
+
+	svc, repo := newTestLicenseService(t, keys, forged, now)
+
+	// An invalid configured key is reported rather than failing startup
+	status, err := svc.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if status.Status != license.StatusInvalid || status.Error == "" {
+		t.Errorf("Status = %+v, want invalid with error", status)
+	}
+
+	if _, err := svc.Replace(context.Background(), forged); !errors.Is(err, ErrInvalidLicense) {
+		t.Errorf("Replace(forged) error = %v, want %v", err, ErrInvalidLicense)
+	}
+	if _, err := svc.Replace(context.Background(), expired); !errors.Is(err, ErrLicenseExpired) {
+		t.Errorf("Replace(expired) error = %v, want %v", err, ErrLicenseExpired)
+	}
+	if len(repo.licenses) != 0 {
+		t.Errorf("stored %d licenses, want 0", len(repo.licenses))
+	}
+}
+
+func TestLicenseService_StoredLicenseReverified(t *testing.T) {
+	keys := newLicenseTestKeys(t)
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	token := keys.issue(t, license.Claims{ID: "lic_1", Tier: "selfhosted", Features: []string{"webhooks"}, IssuedAt: now.Unix()})
+
+	svc, repo := newTestLicenseService(t, keys, token, now)
+	if _, err := svc.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// Editing the stored columns doesn't change entitlements, which come from the signed token
+	repo.licenses[0].Features = []string{"webhooks", "everything"}
+	if _, err := svc.Check(context.Background()); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if _, features, _ := svc.Entitlements(); len(features) != 1 {
+		t.Errorf("Entitlements() features = %v, want signed features only", features)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/jmylchreest/refyne-api/internal/llm"
@@ -39,7 +40,18 @@ type LLMCallOptions struct {
 	Temperature float64 // Default: 0.2
 	MaxTokens   int     // Default: 16384
 	Timeout     time.Duration // Default: 120s
-	JSONMode    bool    // Request JSON response format (OpenAI/OpenRouter only)
+	JSONMode    bool          // Request a JSON response using the provider's structured output strategy
+
+	// JSONSchema constrains the response to a schema (implies JSONMode). Callers convert
+	// the job's schema once (schema.ToJSONSchema) and pass the same map to every call.
+	JSONSchema map[string]any
+
+	// OnDelta, when set, streams the response and is called with each content fragment
+	// as it arrives. Timeout then applies to gaps between fragments rather than the whole call.
+	OnDelta func(delta string)
+
+	// Images are sent with the prompt for vision-capable models (see ModelCapabilities.SupportsVision).
+	Images []llm.Image
 }
 
 // DefaultLLMCallOptions returns sensible defaults for LLM calls.
@@ -93,8 +105,23 @@ func NewLLMClient(logger *slog.Logger, registry *llm.Registry) *LLMClient {
 
 // Call makes a direct call to an LLM API and returns the response with token usage.
 func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt string, opts LLMCallOptions) (*LLMCallResult, error) {
+	ctx, span := startLLMSpan(ctx, config.Provider, config.Model)
+	start := time.Now()
+
+	result, err := c.call(ctx, config, prompt, opts)
+
+	var inputTokens, outputTokens int
+	if result != nil {
+		inputTokens, outputTokens = result.InputTokens, result.OutputTokens
+	}
+	endLLMSpan(span, config.Provider, config.Model, start, inputTokens, outputTokens, err)
+	return result, err
+}
+
+// call performs the LLM API request for Call.
+func (c *LLMClient) call(ctx context.Context, config *LLMConfigInput, prompt string, opts LLMCallOptions) (*LLMCallResult, error) {
 	// Validate config
-	if config.APIKey == "" && config.Provider != "ollama" {
+	if config.APIKey == "" && llm.RequiresAPIKey(config.Provider) {
 		return nil, fmt.Errorf("no API key available for provider %s", config.Provider)
 	}
 
@@ -110,32 +137,33 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 	}
 
 	// Build request body
-	reqBody := map[string]any{
-		"model": config.Model,
-		"messages": []map[string]string{
-			{"role": "user", "content": prompt},
-		},
-		"temperature": opts.Temperature,
-		"max_tokens":  opts.MaxTokens,
-	}
-
-	// Add JSON mode if requested
-	// The caller is responsible for checking model capabilities via SupportsResponseFormat()
-	// This parameter is supported by OpenAI-compatible APIs
-	// Anthropic uses tool_use for structured outputs, Ollama varies by model
-	if opts.JSONMode {
-		// Check API format from registry - only OpenAI-compatible APIs support response_format
-		apiFormat := llm.APIFormatOpenAI // default assumption
-		if c.registry != nil {
-			if apiConfig := c.registry.GetProviderAPIConfig(config.Provider); apiConfig != nil {
-				apiFormat = apiConfig.APIFormat
-			}
-		}
-		if apiFormat == llm.APIFormatOpenAI {
-			reqBody["response_format"] = map[string]string{"type": "json_object"}
+	apiFormat := c.apiFormat(config.Provider)
+	var reqBody map[string]any
+	if apiFormat == llm.APIFormatGemini {
+		// Gemini selects streaming by endpoint rather than a request field
+		reqBody = llm.GeminiRequestBody("", prompt, opts.Temperature, opts.MaxTokens)
+		llm.GeminiAddImages(reqBody, opts.Images)
+	} else {
+		reqBody = map[string]any{
+			"model":       config.Model,
+			"messages":    []map[string]any{llm.UserMessage(apiFormat, prompt, opts.Images)},
+			"temperature": opts.Temperature,
+			"max_tokens":  opts.MaxTokens,
+			"stream":      opts.OnDelta != nil,
 		}
-		// Anthropic and Ollama don't use response_format - they have different mechanisms
-		// If JSONMode is requested for these, we rely on the prompt instructions
+	}
+	if opts.OnDelta != nil && apiFormat == llm.APIFormatOpenAI {
+		reqBody["stream_options"] = map[string]any{"include_usage": true}
+	}
+
+	// Constrain the response to JSON using the provider's native mechanism
+	// (json_schema, forced tool_use, Ollama format or Gemini response schema), as the
+	// model's capabilities allow.
+	// Models without native support rely on the prompt instructions.
+	strategy := llm.StructuredOutputNone
+	if opts.JSONMode || opts.JSONSchema != nil {
+		strategy = c.structuredOutputStrategy(ctx, config)
+		llm.ApplyStructuredOutput(reqBody, strategy, opts.JSONSchema)
 	}
 
 	jsonBody, err := json.Marshal(reqBody)
@@ -145,6 +173,9 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 
 	// Determine API endpoint
 	apiURL := c.getAPIURL(config)
+	if opts.OnDelta != nil && apiFormat == llm.APIFormatGemini {
+		apiURL = llm.GeminiStreamURL(apiURL)
+	}
 
 	if c.logger != nil {
 		c.logger.Debug("making LLM API request",
@@ -152,11 +183,27 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 			"model", config.Model,
 			"api_url", apiURL,
 			"prompt_length", len(prompt),
+			"images", len(opts.Images),
 			"temperature", opts.Temperature,
 			"max_tokens", opts.MaxTokens,
+			"structured_output", strategy,
+			"stream", opts.OnDelta != nil,
 		)
 	}
 
+	// Streamed calls have no overall timeout; an idle timer cancels them when the
+	// provider stops sending data.
+	client := &http.Client{Timeout: opts.Timeout}
+	var idle *idleTimer
+	if opts.OnDelta != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		idle = newIdleTimer(opts.Timeout, cancel)
+		defer idle.stop()
+		client = &http.Client{}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -165,9 +212,11 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 	req.Header.Set("Content-Type", "application/json")
 	c.setAuthHeaders(req, config)
 
-	client := &http.Client{Timeout: opts.Timeout}
 	resp, err := client.Do(req)
 	if err != nil {
+		if idle != nil {
+			err = idle.err(err)
+		}
 		if c.logger != nil {
 			c.logger.Error("LLM API request failed", "provider", config.Provider, "error", err)
 		}
@@ -175,6 +224,36 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	var result *LLMCallResult
+	if opts.OnDelta != nil {
+		result, err = c.readStream(config.Provider, resp, opts.OnDelta, idle)
+	} else {
+		result, err = c.readResponse(config.Provider, resp)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Add context for truncation error reporting
+	result.Model = config.Model
+	result.MaxTokens = opts.MaxTokens
+
+	// Log if response was truncated
+	if result.IsTruncated() && c.logger != nil {
+		c.logger.Warn("LLM output truncated",
+			"provider", config.Provider,
+			"model", config.Model,
+			"output_tokens", result.OutputTokens,
+			"max_tokens", opts.MaxTokens,
+			"finish_reason", result.FinishReason,
+		)
+	}
+
+	return result, nil
+}
+
+// readResponse reads and parses a non-streamed response.
+func (c *LLMClient) readResponse(provider string, resp *http.Response) (*LLMCallResult, error) {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
@@ -182,7 +261,7 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 
 	if c.logger != nil {
 		c.logger.Debug("LLM API response received",
-			"provider", config.Provider,
+			"provider", provider,
 			"status_code", resp.StatusCode,
 			"response_length", len(body),
 		)
@@ -191,7 +270,7 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 	if resp.StatusCode != http.StatusOK {
 		if c.logger != nil {
 			c.logger.Error("LLM API error",
-				"provider", config.Provider,
+				"provider", provider,
 				"status_code", resp.StatusCode,
 				"response", string(body),
 			)
@@ -200,27 +279,22 @@ func (c *LLMClient) Call(ctx context.Context, config *LLMConfigInput, prompt str
 	}
 
 	// Parse response based on provider
-	result, err := c.ParseResponse(config.Provider, body)
-	if err != nil {
-		return nil, err
-	}
-
-	// Add context for truncation error reporting
-	result.Model = config.Model
-	result.MaxTokens = opts.MaxTokens
+	return c.ParseResponse(provider, body)
+}
 
-	// Log if response was truncated
-	if result.IsTruncated() && c.logger != nil {
-		c.logger.Warn("LLM output truncated",
-			"provider", config.Provider,
-			"model", config.Model,
-			"output_tokens", result.OutputTokens,
-			"max_tokens", opts.MaxTokens,
-			"finish_reason", result.FinishReason,
-		)
+// structuredOutputStrategy returns the structured output strategy for the configured model.
+// Providers missing from the registry are assumed to be OpenAI-compatible.
+func (c *LLMClient) structuredOutputStrategy(ctx context.Context, config *LLMConfigInput) llm.StructuredOutputStrategy {
+	if c.registry != nil && c.registry.GetProviderAPIConfig(config.Provider) != nil {
+		return c.registry.StructuredOutputStrategy(ctx, config.Provider, config.Model)
 	}
 
-	return result, nil
+	switch config.Provider {
+	case llm.ProviderAnthropic, llm.ProviderOllama:
+		return llm.StructuredOutputNone
+	default:
+		return llm.StructuredOutputJSONObject
+	}
 }
 
 // getAPIURL returns the API endpoint for a provider using registry configuration.
@@ -235,6 +309,10 @@ func (c *LLMClient) getAPIURL(config *LLMConfigInput) string {
 			if apiConfig.AllowBaseURLOverride && config.BaseURL != "" {
 				baseURL = config.BaseURL
 			}
+			if config.Provider == llm.ProviderOpenAICompatible {
+				// Users often paste the server's URL including /v1
+				baseURL = llm.TrimOpenAIBaseURL(baseURL)
+			}
 
 			// Special handling for Helicone self-hosted mode
 			if config.Provider == llm.ProviderHelicone && c.isHeliconeSelfHosted(config) {
@@ -246,7 +324,7 @@ func (c *LLMClient) getAPIURL(config *LLMConfigInput) string {
 				return baseURL + "/v1/gateway/" + targetProvider + "/v1/chat/completions"
 			}
 
-			return baseURL + apiConfig.ChatEndpoint
+			return baseURL + strings.ReplaceAll(apiConfig.ChatEndpoint, "{model}", config.Model)
 		}
 	}
 
@@ -303,7 +381,10 @@ func (c *LLMClient) setAuthHeaders(req *http.Request, config *LLMConfigInput) {
 			// Set auth based on type
 			switch apiConfig.AuthType {
 			case llm.AuthTypeBearer:
-				req.Header.Set("Authorization", "Bearer "+config.APIKey)
+				// Keyless providers (self-hosted servers) may still accept a key
+				if config.APIKey != "" {
+					req.Header.Set("Authorization", "Bearer "+config.APIKey)
+				}
 			case llm.AuthTypeAPIKey:
 				headerName := apiConfig.AuthHeader
 				if headerName == "" {
@@ -340,41 +421,50 @@ func (c *LLMClient) setAuthHeaders(req *http.Request, config *LLMConfigInput) {
 // Uses registry configuration to determine the API format when available.
 // Exported for testing.
 func (c *LLMClient) ParseResponse(provider string, body []byte) (*LLMCallResult, error) {
-	// Determine API format from registry
-	apiFormat := llm.APIFormatOpenAI // default
-	if c.registry != nil {
-		apiConfig := c.registry.GetProviderAPIConfig(provider)
-		if apiConfig != nil {
-			apiFormat = apiConfig.APIFormat
-		}
-	} else {
-		// Fallback to hardcoded format detection
-		switch provider {
-		case llm.ProviderAnthropic:
-			apiFormat = llm.APIFormatAnthropic
-		case llm.ProviderOllama:
-			apiFormat = llm.APIFormatOllama
-		default:
-			apiFormat = llm.APIFormatOpenAI
-		}
-	}
-
 	// Parse based on API format
-	switch apiFormat {
+	switch c.apiFormat(provider) {
 	case llm.APIFormatAnthropic:
 		return c.parseAnthropicFormat(body)
 	case llm.APIFormatOllama:
 		return c.parseOllamaFormat(body)
+	case llm.APIFormatGemini:
+		return c.parseGeminiFormat(body)
 	default:
 		return c.parseOpenAIFormat(body)
 	}
 }
 
+// apiFormat returns the request/response format for a provider.
+// Uses registry configuration when available.
+func (c *LLMClient) apiFormat(provider string) llm.APIFormat {
+	if c.registry != nil {
+		if apiConfig := c.registry.GetProviderAPIConfig(provider); apiConfig != nil {
+			return apiConfig.APIFormat
+		}
+		return llm.APIFormatOpenAI
+	}
+
+	// Fallback to hardcoded format detection
+	switch provider {
+	case llm.ProviderAnthropic:
+		return llm.APIFormatAnthropic
+	case llm.ProviderOllama:
+		return llm.APIFormatOllama
+	case llm.ProviderGemini:
+		return llm.APIFormatGemini
+	default:
+		return llm.APIFormatOpenAI
+	}
+}
+
 // parseAnthropicFormat parses Anthropic API response format.
+// A tool_use block (forced structured output) takes precedence over text; its input is the data.
 func (c *LLMClient) parseAnthropicFormat(body []byte) (*LLMCallResult, error) {
 	var resp struct {
 		Content []struct {
-			Text string `json:"text"`
+			Type  string          `json:"type"`
+			Text  string          `json:"text"`
+			Input json.RawMessage `json:"input"`
 		} `json:"content"`
 		StopReason string `json:"stop_reason"` // "end_turn", "max_tokens", "stop_sequence"
 		Usage      struct {
@@ -394,18 +484,46 @@ func (c *LLMClient) parseAnthropicFormat(body []byte) (*LLMCallResult, error) {
 		InputTokens:  resp.Usage.InputTokens,
 		OutputTokens: resp.Usage.OutputTokens,
 	}
+	for _, block := range resp.Content {
+		if block.Type == "tool_use" && len(block.Input) > 0 {
+			result.Content = string(block.Input)
+			break
+		}
+	}
+
+	result.FinishReason = normalizeAnthropicStopReason(resp.StopReason)
 
-	// Normalize Anthropic's stop_reason to OpenAI-style finish_reason
-	switch resp.StopReason {
+	return result, nil
+}
+
+// normalizeAnthropicStopReason maps Anthropic's stop_reason to an OpenAI-style finish_reason.
+func normalizeAnthropicStopReason(stopReason string) string {
+	switch stopReason {
 	case "max_tokens":
-		result.FinishReason = "length"
-	case "end_turn", "stop_sequence":
-		result.FinishReason = "stop"
+		return "length"
+	case "end_turn", "stop_sequence", "tool_use":
+		return "stop"
 	default:
-		result.FinishReason = resp.StopReason
+		return stopReason
 	}
+}
 
-	return result, nil
+// parseGeminiFormat parses Gemini generateContent response format.
+func (c *LLMClient) parseGeminiFormat(body []byte) (*LLMCallResult, error) {
+	resp, err := llm.ParseGeminiResponse(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Candidates) == 0 {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+
+	return &LLMCallResult{
+		Content:      resp.Text(),
+		InputTokens:  resp.UsageMetadata.PromptTokenCount,
+		OutputTokens: resp.UsageMetadata.CandidatesTokenCount,
+		FinishReason: resp.FinishReason(),
+	}, nil
 }
 
 // parseOllamaFormat parses Ollama API response format.
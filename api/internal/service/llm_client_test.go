@@ -0,0 +1,516 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/config"
+	"github.com/jmylchreest/refyne-api/internal/llm"
+)
+
+var testJSONSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"title": map[string]any{"type": "string"},
+	},
+	"required": []any{"title"},
+}
+
+// newStructuredOutputServer returns a server that records the request body and replies with response.
+func newStructuredOutputServer(t *testing.T, response string, request *map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newStructuredOutputRegistry registers a single provider pointing at baseURL.
+func newStructuredOutputRegistry(provider, baseURL string, format llm.APIFormat, strategy llm.StructuredOutputStrategy, caps llm.ModelCapabilities) *llm.Registry {
+	r := llm.NewRegistry(&config.Config{}, slog.Default())
+	r.Register(provider, llm.ProviderRegistration{
+		GetCapabilities: func(context.Context, string) llm.ModelCapabilities { return caps },
+		APIConfig: llm.ProviderAPIConfig{
+			BaseURL:          baseURL,
+			ChatEndpoint:     "/chat",
+			AuthType:         llm.AuthTypeNone,
+			APIFormat:        format,
+			StructuredOutput: strategy,
+		},
+	})
+	return r
+}
+
+func TestLLMClient_Call_AnthropicToolUse(t *testing.T) {
+	var request map[string]any
+	srv := newStructuredOutputServer(t, `{
+		"content": [{"type": "tool_use", "name": "extract_data", "input": {"title": "Widget"}}],
+		"stop_reason": "tool_use",
+		"usage": {"input_tokens": 10, "output_tokens": 5}
+	}`, &request)
+	registry := newStructuredOutputRegistry("anthropic", srv.URL, llm.APIFormatAnthropic, llm.StructuredOutputToolUse, llm.ModelCapabilities{SupportsTools: true})
+
+	client := NewLLMClient(slog.Default(), registry)
+	result, err := client.Call(context.Background(), &LLMConfigInput{Provider: "anthropic", Model: "claude", APIKey: "key"}, "extract", LLMCallOptions{JSONSchema: testJSONSchema})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	tools, _ := request["tools"].([]any)
+	if len(tools) != 1 {
+		t.Fatalf("tools = %v, want one tool", request["tools"])
+	}
+	tool := tools[0].(map[string]any)
+	if _, ok := tool["input_schema"].(map[string]any)["properties"].(map[string]any)["title"]; !ok {
+		t.Errorf("input_schema = %v, want the JSON schema", tool["input_schema"])
+	}
+	if choice := request["tool_choice"].(map[string]any); choice["type"] != "tool" || choice["name"] != tool["name"] {
+		t.Errorf("tool_choice = %v, want forced %v", choice, tool["name"])
+	}
+	if _, ok := request["response_format"]; ok {
+		t.Error("response_format should not be sent to Anthropic")
+	}
+
+	if result.Content != `{"title": "Widget"}` {
+		t.Errorf("Content = %q, want tool input", result.Content)
+	}
+	if result.FinishReason != "stop" {
+		t.Errorf("FinishReason = %q, want stop", result.FinishReason)
+	}
+}
+
+func TestLLMClient_Call_OpenAIJSONSchema(t *testing.T) {
+	var request map[string]any
+	srv := newStructuredOutputServer(t, `{
+		"choices": [{"message": {"content": "{\"title\":\"Widget\"}"}, "finish_reason": "stop"}],
+		"usage": {"prompt_tokens": 10, "completion_tokens": 5}
+	}`, &request)
+	registry := newStructuredOutputRegistry("openai", srv.URL, llm.APIFormatOpenAI, llm.StructuredOutputJSONSchema, llm.ModelCapabilities{SupportsStructuredOutputs: true, SupportsResponseFormat: true})
+
+	client := NewLLMClient(slog.Default(), registry)
+	if _, err := client.Call(context.Background(), &LLMConfigInput{Provider: "openai", Model: "gpt-4o", APIKey: "key"}, "extract", LLMCallOptions{JSONSchema: testJSONSchema}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	format := request["response_format"].(map[string]any)
+	if format["type"] != "json_schema" {
+		t.Fatalf("response_format.type = %v, want json_schema", format["type"])
+	}
+	jsonSchema := format["json_schema"].(map[string]any)
+	if jsonSchema["strict"] != true {
+		t.Error("json_schema.strict should be true")
+	}
+	if jsonSchema["schema"].(map[string]any)["additionalProperties"] != false {
+		t.Error("strict schema should disallow additional properties")
+	}
+}
+
+func TestLLMClient_Call_OllamaFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       LLMCallOptions
+		wantFormat any
+	}{
+		{"schema", LLMCallOptions{JSONSchema: testJSONSchema}, "object"},
+		{"json mode", LLMCallOptions{JSONMode: true}, "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request map[string]any
+			srv := newStructuredOutputServer(t, `{"message": {"content": "{}"}, "done_reason": "stop"}`, &request)
+			registry := newStructuredOutputRegistry("ollama", srv.URL, llm.APIFormatOllama, llm.StructuredOutputOllamaFormat, llm.ModelCapabilities{SupportsStructuredOutputs: true})
+
+			client := NewLLMClient(slog.Default(), registry)
+			if _, err := client.Call(context.Background(), &LLMConfigInput{Provider: "ollama", Model: "llama3.2"}, "extract", tt.opts); err != nil {
+				t.Fatalf("Call() error = %v", err)
+			}
+
+			switch format := request["format"].(type) {
+			case string:
+				if format != tt.wantFormat {
+					t.Errorf("format = %q, want %v", format, tt.wantFormat)
+				}
+			case map[string]any:
+				if format["type"] != tt.wantFormat {
+					t.Errorf("format = %v, want JSON schema", format)
+				}
+			default:
+				t.Errorf("format = %v, want %v", request["format"], tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestLLMClient_Call_UnsupportedModelUsesPrompt(t *testing.T) {
+	var request map[string]any
+	srv := newStructuredOutputServer(t, `{"content": [{"type": "text", "text": "{}"}], "stop_reason": "end_turn"}`, &request)
+	registry := newStructuredOutputRegistry("anthropic", srv.URL, llm.APIFormatAnthropic, llm.StructuredOutputToolUse, llm.ModelCapabilities{})
+
+	client := NewLLMClient(slog.Default(), registry)
+	if _, err := client.Call(context.Background(), &LLMConfigInput{Provider: "anthropic", Model: "claude-instant", APIKey: "key"}, "extract", LLMCallOptions{JSONSchema: testJSONSchema}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	for _, key := range []string{"tools", "tool_choice", "response_format", "format"} {
+		if _, ok := request[key]; ok {
+			t.Errorf("%s should not be sent for a model without native structured output", key)
+		}
+	}
+}
+
+// newStreamServer returns a server that replies to a streaming request with the given lines.
+func newStreamServer(t *testing.T, contentType string, lines []string, request *map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.Header().Set("Content-Type", contentType)
+		for _, line := range lines {
+			_, _ = w.Write([]byte(line + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLLMClient_Call_StreamOpenAI(t *testing.T) {
+	tests := []struct {
+		name          string
+		finishReason  string
+		wantTruncated bool
+	}{
+		{"complete", "stop", false},
+		{"truncated", "length", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request map[string]any
+			srv := newStreamServer(t, "text/event-stream", []string{
+				`data: {"choices":[{"delta":{"role":"assistant","content":""}}]}`,
+				``,
+				`data: {"choices":[{"delta":{"content":"[{\"a\":1},"}}]}`,
+				``,
+				`data: {"choices":[{"delta":{"content":"{\"a\":2}]"},"finish_reason":"` + tt.finishReason + `"}]}`,
+				``,
+				`data: {"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":7}}`,
+				``,
+				`data: [DONE]`,
+			}, &request)
+			registry := newStructuredOutputRegistry("openai", srv.URL, llm.APIFormatOpenAI, llm.StructuredOutputJSONObject, llm.ModelCapabilities{SupportsResponseFormat: true})
+
+			var deltas []string
+			client := NewLLMClient(slog.Default(), registry)
+			result, err := client.Call(context.Background(), &LLMConfigInput{Provider: "openai", Model: "gpt-4o", APIKey: "key"}, "extract", LLMCallOptions{
+				JSONMode: true,
+				OnDelta:  func(d string) { deltas = append(deltas, d) },
+			})
+			if err != nil {
+				t.Fatalf("Call() error = %v", err)
+			}
+
+			if request["stream"] != true {
+				t.Errorf("stream = %v, want true", request["stream"])
+			}
+			if opts, _ := request["stream_options"].(map[string]any); opts["include_usage"] != true {
+				t.Errorf("stream_options = %v, want include_usage", request["stream_options"])
+			}
+			if want := []string{`[{"a":1},`, `{"a":2}]`}; !reflect.DeepEqual(deltas, want) {
+				t.Errorf("deltas = %q, want %q", deltas, want)
+			}
+			if result.Content != `[{"a":1},{"a":2}]` {
+				t.Errorf("Content = %q", result.Content)
+			}
+			if result.InputTokens != 12 || result.OutputTokens != 7 {
+				t.Errorf("tokens = %d/%d, want 12/7", result.InputTokens, result.OutputTokens)
+			}
+			if result.IsTruncated() != tt.wantTruncated {
+				t.Errorf("IsTruncated() = %v, want %v", result.IsTruncated(), tt.wantTruncated)
+			}
+			if tt.wantTruncated && !IsOutputTruncated(result.TruncationError()) {
+				t.Error("TruncationError() should be ErrOutputTruncated")
+			}
+		})
+	}
+}
+
+func TestLLMClient_Call_StreamAnthropicToolUse(t *testing.T) {
+	var request map[string]any
+	srv := newStreamServer(t, "text/event-stream", []string{
+		`event: message_start`,
+		`data: {"type":"message_start","message":{"usage":{"input_tokens":20,"output_tokens":1}}}`,
+		``,
+		`event: content_block_start`,
+		`data: {"type":"content_block_start","index":0,"content_block":{"type":"tool_use","name":"extract_data","input":{}}}`,
+		``,
+		`event: content_block_delta`,
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"title\":"}}`,
+		``,
+		`event: content_block_delta`,
+		`data: {"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":" \"Widget\"}"}}`,
+		``,
+		`event: message_delta`,
+		`data: {"type":"message_delta","delta":{"stop_reason":"max_tokens"},"usage":{"output_tokens":9}}`,
+		``,
+		`event: message_stop`,
+		`data: {"type":"message_stop"}`,
+	}, &request)
+	registry := newStructuredOutputRegistry("anthropic", srv.URL, llm.APIFormatAnthropic, llm.StructuredOutputToolUse, llm.ModelCapabilities{SupportsTools: true})
+
+	var streamed string
+	client := NewLLMClient(slog.Default(), registry)
+	result, err := client.Call(context.Background(), &LLMConfigInput{Provider: "anthropic", Model: "claude", APIKey: "key"}, "extract", LLMCallOptions{
+		JSONSchema: testJSONSchema,
+		OnDelta:    func(d string) { streamed += d },
+	})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	if _, ok := request["stream_options"]; ok {
+		t.Error("stream_options should not be sent to Anthropic")
+	}
+	if result.Content != `{"title": "Widget"}` || streamed != result.Content {
+		t.Errorf("Content = %q, streamed = %q, want tool input", result.Content, streamed)
+	}
+	if result.FinishReason != "length" {
+		t.Errorf("FinishReason = %q, want length", result.FinishReason)
+	}
+	if result.InputTokens != 20 || result.OutputTokens != 9 {
+		t.Errorf("tokens = %d/%d, want 20/9", result.InputTokens, result.OutputTokens)
+	}
+}
+
+func TestLLMClient_Call_StreamOllama(t *testing.T) {
+	var request map[string]any
+	srv := newStreamServer(t, "application/x-ndjson", []string{
+		`{"message":{"role":"assistant","content":"{\"a\":"},"done":false}`,
+		`{"message":{"role":"assistant","content":"1}"},"done":false}`,
+		`{"message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":5,"eval_count":3}`,
+	}, &request)
+	registry := newStructuredOutputRegistry("ollama", srv.URL, llm.APIFormatOllama, llm.StructuredOutputOllamaFormat, llm.ModelCapabilities{SupportsStructuredOutputs: true})
+
+	var streamed string
+	client := NewLLMClient(slog.Default(), registry)
+	result, err := client.Call(context.Background(), &LLMConfigInput{Provider: "ollama", Model: "llama3.2"}, "extract", LLMCallOptions{
+		JSONMode: true,
+		OnDelta:  func(d string) { streamed += d },
+	})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	if request["stream"] != true {
+		t.Errorf("stream = %v, want true", request["stream"])
+	}
+	if result.Content != `{"a":1}` || streamed != result.Content {
+		t.Errorf("Content = %q, streamed = %q", result.Content, streamed)
+	}
+	if result.FinishReason != "stop" || result.InputTokens != 5 || result.OutputTokens != 3 {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestLLMClient_Call_StreamIdleTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"choices":[{"delta":{"content":"["}}]}` + "\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	registry := newStructuredOutputRegistry("openai", srv.URL, llm.APIFormatOpenAI, llm.StructuredOutputNone, llm.ModelCapabilities{})
+
+	client := NewLLMClient(slog.Default(), registry)
+	_, err := client.Call(context.Background(), &LLMConfigInput{Provider: "openai", Model: "gpt-4o", APIKey: "key"}, "extract", LLMCallOptions{
+		Timeout: 50 * time.Millisecond,
+		OnDelta: func(string) {},
+	})
+	if err == nil {
+		t.Fatal("Call() should fail when the stream stalls")
+	}
+	if llmErr := llm.ClassifyError(err, "openai", "gpt-4o", 0, false); llmErr.Category != "timeout" {
+		t.Errorf("ClassifyError() category = %q, want timeout (error: %v)", llmErr.Category, err)
+	}
+}
+
+func TestLLMClient_Call_NonStreamingDisablesStream(t *testing.T) {
+	var request map[string]any
+	srv := newStructuredOutputServer(t, `{"message": {"content": "{}"}, "done_reason": "stop"}`, &request)
+	registry := newStructuredOutputRegistry("ollama", srv.URL, llm.APIFormatOllama, llm.StructuredOutputOllamaFormat, llm.ModelCapabilities{})
+
+	client := NewLLMClient(slog.Default(), registry)
+	if _, err := client.Call(context.Background(), &LLMConfigInput{Provider: "ollama", Model: "llama3.2"}, "extract", LLMCallOptions{}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	// Ollama streams by default, so non-streamed calls must opt out explicitly
+	if request["stream"] != false {
+		t.Errorf("stream = %v, want false", request["stream"])
+	}
+}
+
+// newRecordingServer returns a server that records the request and replies with the given lines.
+func newRecordingServer(t *testing.T, contentType string, lines []string, request **http.Request, body *map[string]any) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*request = r.Clone(context.Background())
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		w.Header().Set("Content-Type", contentType)
+		for _, line := range lines {
+			_, _ = w.Write([]byte(line + "\n"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newGeminiRegistry registers Gemini pointing at baseURL with its native API format.
+func newGeminiRegistry(baseURL string) *llm.Registry {
+	r := llm.NewRegistry(&config.Config{}, slog.Default())
+	r.Register(llm.ProviderGemini, llm.ProviderRegistration{
+		GetCapabilities: func(context.Context, string) llm.ModelCapabilities {
+			return llm.ModelCapabilities{SupportsStructuredOutputs: true, SupportsStreaming: true}
+		},
+		APIConfig: llm.ProviderAPIConfig{
+			BaseURL:          baseURL,
+			ChatEndpoint:     llm.GeminiChatEndpoint,
+			AuthType:         llm.AuthTypeAPIKey,
+			AuthHeader:       "x-goog-api-key",
+			APIFormat:        llm.APIFormatGemini,
+			StructuredOutput: llm.StructuredOutputResponseSchema,
+		},
+	})
+	return r
+}
+
+func TestLLMClient_Call_Gemini(t *testing.T) {
+	var req *http.Request
+	var body map[string]any
+	srv := newRecordingServer(t, "application/json", []string{`{
+		"candidates": [{"content": {"parts": [{"text": "{\"title\": \"Widget\"}"}], "role": "model"}, "finishReason": "MAX_TOKENS"}],
+		"usageMetadata": {"promptTokenCount": 30, "candidatesTokenCount": 8}
+	}`}, &req, &body)
+
+	client := NewLLMClient(slog.Default(), newGeminiRegistry(srv.URL))
+	result, err := client.Call(context.Background(), &LLMConfigInput{Provider: llm.ProviderGemini, Model: "gemini-2.5-flash", APIKey: "key"}, "extract", LLMCallOptions{JSONSchema: testJSONSchema, MaxTokens: 512})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	if req.URL.Path != "/v1beta/models/gemini-2.5-flash:generateContent" {
+		t.Errorf("path = %q", req.URL.Path)
+	}
+	if got := req.Header.Get("x-goog-api-key"); got != "key" {
+		t.Errorf("x-goog-api-key = %q, want key", got)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("Authorization should not be sent to Gemini")
+	}
+	if _, ok := body["stream"]; ok {
+		t.Error("stream should not be sent in a Gemini request body")
+	}
+	genConfig, _ := body["generationConfig"].(map[string]any)
+	if genConfig["responseMimeType"] != "application/json" || genConfig["responseJsonSchema"] == nil {
+		t.Errorf("generationConfig = %v, want JSON schema output", genConfig)
+	}
+	if genConfig["maxOutputTokens"] != float64(512) {
+		t.Errorf("maxOutputTokens = %v, want 512", genConfig["maxOutputTokens"])
+	}
+
+	if result.Content != `{"title": "Widget"}` {
+		t.Errorf("Content = %q", result.Content)
+	}
+	if result.InputTokens != 30 || result.OutputTokens != 8 {
+		t.Errorf("tokens = %d/%d, want 30/8", result.InputTokens, result.OutputTokens)
+	}
+	if !result.IsTruncated() {
+		t.Error("MAX_TOKENS should be reported as truncated")
+	}
+}
+
+func TestLLMClient_Call_StreamGemini(t *testing.T) {
+	var req *http.Request
+	var body map[string]any
+	srv := newRecordingServer(t, "text/event-stream", []string{
+		`data: {"candidates":[{"content":{"parts":[{"text":"[{\"a\":1},"}],"role":"model"}}],"usageMetadata":{"promptTokenCount":15}}`,
+		``,
+		`data: {"candidates":[{"content":{"parts":[{"text":"{\"a\":2}]"}],"role":"model"},"finishReason":"STOP"}],"usageMetadata":{"promptTokenCount":15,"candidatesTokenCount":9}}`,
+		``,
+	}, &req, &body)
+
+	var deltas []string
+	client := NewLLMClient(slog.Default(), newGeminiRegistry(srv.URL))
+	result, err := client.Call(context.Background(), &LLMConfigInput{Provider: llm.ProviderGemini, Model: "gemini-2.5-flash", APIKey: "key"}, "extract", LLMCallOptions{
+		JSONMode: true,
+		OnDelta:  func(d string) { deltas = append(deltas, d) },
+	})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	if req.URL.Path != "/v1beta/models/gemini-2.5-flash:streamGenerateContent" || req.URL.Query().Get("alt") != "sse" {
+		t.Errorf("URL = %q, want streamGenerateContent with alt=sse", req.URL.String())
+	}
+	if want := []string{`[{"a":1},`, `{"a":2}]`}; !reflect.DeepEqual(deltas, want) {
+		t.Errorf("deltas = %q, want %q", deltas, want)
+	}
+	if result.Content != `[{"a":1},{"a":2}]` || result.FinishReason != "stop" {
+		t.Errorf("result = %+v", result)
+	}
+	if result.InputTokens != 15 || result.OutputTokens != 9 {
+		t.Errorf("tokens = %d/%d, want 15/9", result.InputTokens, result.OutputTokens)
+	}
+}
+
+func TestLLMClient_Call_OpenAICompatibleWithoutKey(t *testing.T) {
+	var req *http.Request
+	var body map[string]any
+	srv := newRecordingServer(t, "application/json", []string{`{
+		"choices": [{"message": {"content": "{\"title\": \"Widget\"}"}, "finish_reason": "stop"}],
+		"usage": {"prompt_tokens": 10, "completion_tokens": 5}
+	}`}, &req, &body)
+
+	client := NewLLMClient(slog.Default(), llm.InitRegistry(&config.Config{}, slog.Default()))
+	// Self-hosted base URLs are commonly entered with the /v1 suffix
+	result, err := client.Call(context.Background(), &LLMConfigInput{
+		Provider: llm.ProviderOpenAICompatible,
+		Model:    "qwen2.5-7b-instruct",
+		BaseURL:  srv.URL + "/v1/",
+	}, "extract", LLMCallOptions{JSONSchema: testJSONSchema})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	if req.URL.Path != "/v1/chat/completions" {
+		t.Errorf("path = %q, want /v1/chat/completions", req.URL.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("Authorization = %q, want none without a key", got)
+	}
+	if format, _ := body["response_format"].(map[string]any); format["type"] != "json_schema" {
+		t.Errorf("response_format = %v, want json_schema", body["response_format"])
+	}
+	if result.Content != `{"title": "Widget"}` {
+		t.Errorf("Content = %q", result.Content)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/jmylchreest/refyne-api/internal/config"
 	"github.com/jmylchreest/refyne-api/internal/constants"
@@ -80,6 +81,7 @@ type LLMConfigResolver struct {
 	registry  *llm.Registry
 	pricing   *PricingService // For dynamic max_completion_tokens
 	encryptor *crypto.Encryptor
+	health    *llm.HealthTracker // Shared provider/model circuit breaker state
 	logger    *slog.Logger
 }
 
@@ -98,6 +100,19 @@ func (r *LLMConfigResolver) SetRegistry(registry *llm.Registry) {
 	r.registry = registry
 }
 
+// SetHealthTracker sets the provider health tracker used to order config chains.
+func (r *LLMConfigResolver) SetHealthTracker(health *llm.HealthTracker) {
+	r.health = health
+}
+
+// HealthTracker returns the provider health tracker (nil if not configured).
+func (r *LLMConfigResolver) HealthTracker() *llm.HealthTracker {
+	if r == nil {
+		return nil
+	}
+	return r.health
+}
+
 // GetRegistry returns the LLM provider registry.
 func (r *LLMConfigResolver) GetRegistry() *llm.Registry {
 	return r.registry
@@ -175,9 +190,10 @@ func (r *LLMConfigResolver) GetStrictMode(ctx context.Context, provider, model s
 		return *chainStrictMode
 	}
 
-	// Use registry for capability detection if available
+	// Use registry for capability detection if available; strict mode only applies
+	// to providers whose structured output strategy is an OpenAI-style json_schema
 	if r.registry != nil {
-		return r.registry.SupportsStructuredOutputs(ctx, provider, model)
+		return r.registry.StructuredOutputStrategy(ctx, provider, model) == llm.StructuredOutputJSONSchema
 	}
 
 	// Fall back to static defaults
@@ -384,6 +400,13 @@ func (e *ErrContextCapacityExceeded) Error() string {
 // - models_custom only: system keys + user chain
 // - Neither: system keys + system chain
 func (r *LLMConfigResolver) ResolveConfigs(ctx context.Context, userID string, override *LLMConfigInput, tier string, byokAllowed, modelsCustomAllowed bool) ([]*LLMConfigInput, bool) {
+	configs, isBYOK, _ := r.resolveConfigs(ctx, userID, override, tier, byokAllowed, modelsCustomAllowed)
+	return configs, isBYOK
+}
+
+// resolveConfigs implements ResolveConfigs, additionally returning the routing policy
+// scope of the chain the configs came from ("" for an override, which isn't routed).
+func (r *LLMConfigResolver) resolveConfigs(ctx context.Context, userID string, override *LLMConfigInput, tier string, byokAllowed, modelsCustomAllowed bool) ([]*LLMConfigInput, bool, string) {
 	r.logger.Debug("resolving LLM configs with feature matrix",
 		"user_id", userID,
 		"tier", tier,
@@ -397,7 +420,8 @@ func (r *LLMConfigResolver) ResolveConfigs(ctx context.Context, userID string, o
 			r.logger.Debug("override provided but BYOK not allowed, using system chain",
 				"user_id", userID,
 			)
-			return r.GetDefaultConfigsForTier(ctx, tier), false
+			configs, scope := r.defaultConfigsForTier(ctx, tier)
+			return configs, false, scope
 		}
 
 		// Populate defaults for any missing fields
@@ -434,7 +458,7 @@ func (r *LLMConfigResolver) ResolveConfigs(ctx context.Context, userID string, o
 			"max_tokens", resolvedOverride.MaxTokens,
 			"context_length", resolvedOverride.ContextLength,
 		)
-		return []*LLMConfigInput{resolvedOverride}, true
+		return []*LLMConfigInput{resolvedOverride}, true, ""
 	}
 
 	// Case 2: BYOK + models_custom - user chain with user keys
@@ -446,7 +470,7 @@ func (r *LLMConfigResolver) ResolveConfigs(ctx context.Context, userID string, o
 				"provider", configs[0].Provider,
 				"model", configs[0].Model,
 			)
-			return configs, true
+			return configs, true, models.UserRoutingScope(userID)
 		}
 	}
 
@@ -459,25 +483,25 @@ func (r *LLMConfigResolver) ResolveConfigs(ctx context.Context, userID string, o
 				"provider", configs[0].Provider,
 				"model", configs[0].Model,
 			)
-			return configs, false
+			return configs, false, models.UserRoutingScope(userID)
 		}
 	}
 
 	// Case 4: BYOK only - check for user keys to use with system chain
 	if byokAllowed && !modelsCustomAllowed {
-		configs := r.buildBYOKOnlyConfigs(ctx, userID, tier)
+		configs, scope := r.buildBYOKOnlyConfigs(ctx, userID, tier)
 		if len(configs) > 0 {
 			r.logger.Info("using system chain with user keys (BYOK only)",
 				"user_id", userID,
 				"provider", configs[0].Provider,
 				"model", configs[0].Model,
 			)
-			return configs, true
+			return configs, true, scope
 		}
 	}
 
 	// Case 5: Default - system chain with system keys
-	configs := r.GetDefaultConfigsForTier(ctx, tier)
+	configs, scope := r.defaultConfigsForTier(ctx, tier)
 	if len(configs) > 0 {
 		r.logger.Debug("using system default chain",
 			"user_id", userID,
@@ -486,15 +510,25 @@ func (r *LLMConfigResolver) ResolveConfigs(ctx context.Context, userID string, o
 			"model", configs[0].Model,
 		)
 	}
-	return configs, false
+	return configs, false, scope
 }
 
 // LLMConfigChain provides iteration over fallback LLM configurations.
 // It allows services to try each config in sequence until one succeeds.
+// Configs are ordered by the chain's routing policy; with a health tracker
+// attached, configs whose circuit is open are tried last.
 type LLMConfigChain struct {
-	configs []*LLMConfigInput
-	isBYOK  bool
-	index   int // 0 = not started, 1 = first config, etc.
+	configs   []*LLMConfigInput
+	isBYOK    bool
+	index     int // 0 = not started, 1 = first config, etc.
+	health    *llm.HealthTracker
+	tried     []bool          // Configs already returned by Next (health-ordered iteration)
+	current   *LLMConfigInput // Config most recently returned by Next
+	startedAt time.Time       // When the current config was returned by Next
+
+	router   *chainRouter
+	unrouted []*LLMConfigInput // Configs in chain position order, before routing
+	routing  RoutingDecision
 }
 
 // NewLLMConfigChain creates a new config chain from a slice of configs.
@@ -517,30 +551,128 @@ func NewLLMConfigChain(configs []*LLMConfigInput, isBYOK bool) *LLMConfigChain {
 //	    return nil, ErrNoModelsConfigured
 //	}
 //	for cfg := chain.Next(); cfg != nil; cfg = chain.Next() {
+//	    chain.BeginCall()
 //	    result, err := doSomething(cfg)
 //	    if err == nil {
 //	        return result, nil
 //	    }
 //	    // Log error and continue to next config
 //	}
+//
+// The chain is ordered by the routing policy configured for the user or tier chain it
+// came from (see RoutingPolicy). Overrides are a single config and aren't routed.
 func (r *LLMConfigResolver) ResolveConfigChain(ctx context.Context, userID string, override *LLMConfigInput, tier string, byokAllowed, modelsCustomAllowed bool) *LLMConfigChain {
-	configs, isBYOK := r.ResolveConfigs(ctx, userID, override, tier, byokAllowed, modelsCustomAllowed)
-	return &LLMConfigChain{
+	configs, isBYOK, scope := r.resolveConfigs(ctx, userID, override, tier, byokAllowed, modelsCustomAllowed)
+	chain := &LLMConfigChain{
 		configs: configs,
 		isBYOK:  isBYOK,
 		index:   0,
+		health:  r.health,
+	}
+	if scope != "" {
+		chain.withRouting(newChainRouter(r.pricing, r.health), r.RoutingPolicy(ctx, scope))
+		r.logger.Debug("fallback chain routed",
+			"user_id", userID,
+			"scope", scope,
+			"policy", chain.routing.Policy,
+			"reason", chain.routing.Reason,
+		)
+	}
+	return chain
+}
+
+// RoutingPolicy returns the routing policy for a chain scope, defaulting to ordered
+// when none is configured or the stored value is unknown.
+func (r *LLMConfigResolver) RoutingPolicy(ctx context.Context, scope string) models.RoutingPolicy {
+	if r.repos == nil || r.repos.RoutingPolicy == nil {
+		return models.RoutingOrdered
+	}
+	policy, err := r.repos.RoutingPolicy.Get(ctx, scope)
+	if err != nil {
+		r.logger.Warn("failed to get routing policy, using ordered", "scope", scope, "error", err)
+		return models.RoutingOrdered
+	}
+	if policy == "" {
+		return models.RoutingOrdered
+	}
+	if !policy.IsValid() {
+		r.logger.Warn("unknown routing policy, using ordered", "scope", scope, "policy", policy)
+		return models.RoutingOrdered
+	}
+	return policy
+}
+
+// withRouting orders the chain by a routing policy, assuming the default input size.
+func (c *LLMConfigChain) withRouting(router *chainRouter, policy models.RoutingPolicy) *LLMConfigChain {
+	c.router = router
+	c.unrouted = c.configs
+	c.configs, c.routing = router.route(policy, c.unrouted, 0)
+	return c
+}
+
+// RouteForInput re-orders a cheapest_capable chain once the input size is known, so
+// models whose context window can't fit the input are tried last. It has no effect
+// on other policies or once iteration has started.
+func (c *LLMConfigChain) RouteForInput(estimatedInputTokens int) {
+	if c.router == nil || c.index > 0 || c.routing.Policy != models.RoutingCheapestCapable {
+		return
 	}
+	c.configs, c.routing = c.router.route(c.routing.Policy, c.unrouted, estimatedInputTokens)
+}
+
+// Routing returns the routing policy that ordered the chain and why.
+// Zero for chains that weren't routed (overrides and injected configs).
+func (c *LLMConfigChain) Routing() RoutingDecision {
+	return c.routing
+}
+
+// WithHealth attaches a provider health tracker so Next() skips past models whose
+// circuit is open, returning them only after every healthy config has been tried.
+func (c *LLMConfigChain) WithHealth(health *llm.HealthTracker) *LLMConfigChain {
+	c.health = health
+	return c
 }
 
 // Next returns the next LLM config in the chain, or nil if exhausted.
-// Call this in a loop to iterate through all fallback configs.
+// Call this in a loop to iterate through all fallback configs, and BeginCall
+// once a config is actually called.
+// Health is checked as the chain is walked, so a model that fails during
+// iteration is deferred for the rest of this and every other chain.
 func (c *LLMConfigChain) Next() *LLMConfigInput {
 	if c.index >= len(c.configs) {
 		return nil
 	}
-	cfg := c.configs[c.index]
+	c.startedAt = time.Now()
+	if c.health == nil {
+		c.current = c.configs[c.index]
+		c.index++
+		return c.current
+	}
+
+	if c.tried == nil {
+		c.tried = make([]bool, len(c.configs))
+	}
+	pick := -1
+	for i, cfg := range c.configs {
+		if !c.tried[i] && c.health.Allow(cfg.Provider, cfg.Model) {
+			pick = i
+			break
+		}
+	}
+	if pick < 0 {
+		// Only unhealthy configs remain - try them in chain order as a last resort
+		for i := range c.configs {
+			if !c.tried[i] {
+				pick = i
+				break
+			}
+		}
+	}
+
+	c.tried[pick] = true
+	c.current = c.configs[pick]
 	c.index++
-	return cfg
+	return c.current
 }
 
 // Current returns the current config without advancing the iterator.
@@ -549,7 +681,40 @@ func (c *LLMConfigChain) Current() *LLMConfigInput {
 	if c.index == 0 || c.index > len(c.configs) {
 		return nil
 	}
-	return c.configs[c.index-1]
+	return c.current
+}
+
+// BeginCall reports that the current config is about to be called. Next only checks
+// health, so configs skipped without a call (e.g. over budget) don't take the probe of
+// a half-open circuit. BYOK calls aren't recorded, like their outcomes.
+func (c *LLMConfigChain) BeginCall() {
+	if c.health == nil || c.isBYOK || c.current == nil {
+		return
+	}
+	c.health.BeginCall(c.current.Provider, c.current.Model)
+}
+
+// RecordSuccess reports that the current config succeeded to the health tracker, along
+// with the LLM call latency used by fastest routing (<= 0 uses the time since Next).
+// BYOK outcomes aren't recorded: they reflect the user's own key or server, not shared capacity.
+func (c *LLMConfigChain) RecordSuccess(latency time.Duration) {
+	if c.health == nil || c.isBYOK || c.current == nil {
+		return
+	}
+	if latency <= 0 {
+		latency = time.Since(c.startedAt)
+	}
+	c.health.RecordSuccess(c.current.Provider, c.current.Model)
+	c.health.RecordLatency(c.current.Provider, c.current.Model, latency)
+}
+
+// RecordError reports a classified failure of the current config to the health tracker.
+// BYOK outcomes aren't recorded: they reflect the user's own key or server, not shared capacity.
+func (c *LLMConfigChain) RecordError(llmErr *llm.LLMError) {
+	if c.health == nil || c.isBYOK || c.current == nil {
+		return
+	}
+	c.health.RecordError(c.current.Provider, c.current.Model, llmErr)
 }
 
 // First returns the first config in the chain without advancing the iterator.
@@ -623,12 +788,13 @@ func (r *LLMConfigResolver) BuildUserFallbackChain(ctx context.Context, userID s
 
 	for _, entry := range chain {
 		key, ok := keyMap[entry.Provider]
-		if !ok && entry.Provider != llm.ProviderOllama {
-			continue // No key for this provider (Ollama doesn't need one)
+		requiresKey := llm.RequiresAPIKey(entry.Provider)
+		if !ok && requiresKey {
+			continue // No key for this provider (self-hosted providers don't need one)
 		}
 
 		var apiKey, baseURL string
-		if entry.Provider != llm.ProviderOllama {
+		if requiresKey {
 			if key == nil || key.APIKeyEncrypted == "" {
 				continue // No API key configured
 			}
@@ -648,6 +814,14 @@ func (r *LLMConfigResolver) BuildUserFallbackChain(ctx context.Context, userID s
 				apiKey = key.APIKeyEncrypted
 			}
 			baseURL = key.BaseURL
+		} else if key != nil {
+			// Self-hosted servers need their base URL; a key is optional
+			baseURL = key.BaseURL
+			if key.APIKeyEncrypted != "" && r.encryptor != nil {
+				if decrypted, err := r.encryptor.Decrypt(key.APIKeyEncrypted); err == nil {
+					apiKey = decrypted
+				}
+			}
 		}
 
 		configs = append(configs, &LLMConfigInput{
@@ -658,6 +832,7 @@ func (r *LLMConfigResolver) BuildUserFallbackChain(ctx context.Context, userID s
 			MaxTokens:     r.GetMaxTokens(ctx, entry.Provider, entry.Model, entry.MaxTokens),
 			ContextLength: r.GetContextLength(ctx, entry.Provider, entry.Model),
 			StrictMode:    r.GetStrictMode(ctx, entry.Provider, entry.Model, entry.StrictMode),
+			Weight:        entry.Weight,
 		})
 	}
 
@@ -691,13 +866,14 @@ func (r *LLMConfigResolver) BuildUserChainWithSystemKeys(ctx context.Context, us
 			MaxTokens:     r.GetMaxTokens(ctx, entry.Provider, entry.Model, entry.MaxTokens),
 			ContextLength: r.GetContextLength(ctx, entry.Provider, entry.Model),
 			StrictMode:    r.GetStrictMode(ctx, entry.Provider, entry.Model, entry.StrictMode),
+			Weight:        entry.Weight,
 		}
 
 		// Use SYSTEM keys for the provider (provider-agnostic)
 		config.APIKey = serviceKeys.Get(entry.Provider)
 
-		// Skip if no system key available for non-Ollama providers
-		if config.APIKey == "" && entry.Provider != llm.ProviderOllama {
+		// Skip if no system key available for providers that need one
+		if config.APIKey == "" && llm.RequiresAPIKey(entry.Provider) {
 			continue
 		}
 
@@ -709,15 +885,16 @@ func (r *LLMConfigResolver) BuildUserChainWithSystemKeys(ctx context.Context, us
 
 // buildBYOKOnlyConfigs handles the BYOK-only case where user has their own keys
 // but should use the system chain (not custom models).
-func (r *LLMConfigResolver) buildBYOKOnlyConfigs(ctx context.Context, userID string, tier string) []*LLMConfigInput {
+// Also returns the routing policy scope of the system chain used.
+func (r *LLMConfigResolver) buildBYOKOnlyConfigs(ctx context.Context, userID string, tier string) ([]*LLMConfigInput, string) {
 	if r.repos.UserServiceKey == nil {
-		return nil
+		return nil, ""
 	}
 
 	// Get user's enabled service keys
 	userKeys, err := r.repos.UserServiceKey.GetEnabledByUserID(ctx, userID)
 	if err != nil || len(userKeys) == 0 {
-		return nil
+		return nil, ""
 	}
 
 	// Build map of user's providers
@@ -762,18 +939,26 @@ func (r *LLMConfigResolver) buildBYOKOnlyConfigs(ctx context.Context, userID str
 					MaxTokens:     r.GetMaxTokens(ctx, entry.Provider, entry.Model, entry.MaxTokens),
 					ContextLength: r.GetContextLength(ctx, entry.Provider, entry.Model),
 					StrictMode:    r.GetStrictMode(ctx, entry.Provider, entry.Model, entry.StrictMode),
+					Weight:        entry.Weight,
 				})
 			}
 		}
 	}
 
-	return configs
+	return configs, chainRoutingScope(chain)
 }
 
 // GetDefaultConfigsForTier returns the default LLM configs for a tier.
 // Returns nil if no chain is configured for the tier.
 // Fallback order: tier-specific -> default (NULL) -> free tier
 func (r *LLMConfigResolver) GetDefaultConfigsForTier(ctx context.Context, tier string) []*LLMConfigInput {
+	configs, _ := r.defaultConfigsForTier(ctx, tier)
+	return configs
+}
+
+// defaultConfigsForTier implements GetDefaultConfigsForTier, additionally returning
+// the routing policy scope of the chain that was used.
+func (r *LLMConfigResolver) defaultConfigsForTier(ctx context.Context, tier string) ([]*LLMConfigInput, string) {
 	// Normalize tier name (e.g., "tier_v1_free" -> "free")
 	normalizedTier := constants.NormalizeTierName(tier)
 
@@ -824,12 +1009,13 @@ func (r *LLMConfigResolver) GetDefaultConfigsForTier(ctx context.Context, tier s
 					MaxTokens:     r.GetMaxTokens(ctx, entry.Provider, entry.Model, entry.MaxTokens),
 					ContextLength: r.GetContextLength(ctx, entry.Provider, entry.Model),
 					StrictMode:    r.GetStrictMode(ctx, entry.Provider, entry.Model, entry.StrictMode),
+					Weight:        entry.Weight,
 				}
 
 				// Use provider-agnostic key lookup
 				config.APIKey = serviceKeys.Get(entry.Provider)
 
-				if config.APIKey != "" || entry.Provider == llm.ProviderOllama {
+				if config.APIKey != "" || !llm.RequiresAPIKey(entry.Provider) {
 					configs = append(configs, config)
 				} else {
 					skippedNoKey++
@@ -841,7 +1027,7 @@ func (r *LLMConfigResolver) GetDefaultConfigsForTier(ctx context.Context, tier s
 			}
 
 			if len(configs) > 0 {
-				return configs
+				return configs, chainRoutingScope(chain)
 			}
 
 			// Chain exists but no usable configs (service keys not configured)
@@ -851,7 +1037,7 @@ func (r *LLMConfigResolver) GetDefaultConfigsForTier(ctx context.Context, tier s
 					"chain_entries", len(chain),
 					"skipped_no_key", skippedNoKey,
 				)
-				return nil
+				return nil, ""
 			}
 		}
 	}
@@ -860,7 +1046,15 @@ func (r *LLMConfigResolver) GetDefaultConfigsForTier(ctx context.Context, tier s
 	r.logger.Warn("no fallback chain configured for tier",
 		"tier", normalizedTier,
 	)
-	return nil
+	return nil, ""
+}
+
+// chainRoutingScope returns the routing policy scope of a system chain (all entries share a tier).
+func chainRoutingScope(chain []*models.FallbackChainEntry) string {
+	if len(chain) == 0 {
+		return ""
+	}
+	return models.TierRoutingScope(chain[0].Tier)
 }
 
 // GetDefaultConfig returns the first valid default config for a tier.
@@ -2,13 +2,17 @@ package service
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/jmylchreest/refyne-api/internal/config"
 	"github.com/jmylchreest/refyne-api/internal/crypto"
+	"github.com/jmylchreest/refyne-api/internal/llm"
 	"github.com/jmylchreest/refyne-api/internal/models"
 	"github.com/jmylchreest/refyne-api/internal/repository"
 )
@@ -302,6 +306,48 @@ func (m *mockUserFallbackChainRepository) ReplaceAll(ctx context.Context, userID
 	return nil
 }
 
+// mockRoutingPolicyRepository implements repository.RoutingPolicyRepository for testing
+type mockRoutingPolicyRepository struct {
+	mu       sync.RWMutex
+	policies map[string]models.RoutingPolicy
+}
+
+func newMockRoutingPolicyRepository() *mockRoutingPolicyRepository {
+	return &mockRoutingPolicyRepository{
+		policies: make(map[string]models.RoutingPolicy),
+	}
+}
+
+func (m *mockRoutingPolicyRepository) Get(ctx context.Context, scope string) (models.RoutingPolicy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.policies[scope], nil
+}
+
+func (m *mockRoutingPolicyRepository) GetAll(ctx context.Context) (map[string]models.RoutingPolicy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[string]models.RoutingPolicy, len(m.policies))
+	for scope, policy := range m.policies {
+		result[scope] = policy
+	}
+	return result, nil
+}
+
+func (m *mockRoutingPolicyRepository) Set(ctx context.Context, scope string, policy models.RoutingPolicy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.policies[scope] = policy
+	return nil
+}
+
+func (m *mockRoutingPolicyRepository) Delete(ctx context.Context, scope string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.policies, scope)
+	return nil
+}
+
 // newTestLLMConfigResolver creates a resolver with mocks for testing
 func newTestLLMConfigResolver() (
 	*LLMConfigResolver,
@@ -837,6 +883,91 @@ func TestResolveConfigChain_Iterator(t *testing.T) {
 	}
 }
 
+func TestLLMConfigChain_HealthDefersOpenCircuits(t *testing.T) {
+	health := llm.NewHealthTracker(llm.HealthConfig{FailureThreshold: 1, Cooldown: time.Hour})
+	configs := []*LLMConfigInput{
+		{Provider: "openrouter", Model: "a"},
+		{Provider: "openrouter", Model: "b"},
+		{Provider: "openrouter", Model: "c"},
+	}
+	unavailable := llm.WrapError(errors.New("status: 503"), "openrouter", "a", false)
+
+	// First chain: model "a" fails and opens its circuit
+	chain := NewLLMConfigChain(configs, false).WithHealth(health)
+	if cfg := chain.Next(); cfg.Model != "a" {
+		t.Fatalf("expected a first, got %s", cfg.Model)
+	}
+	chain.RecordError(unavailable)
+	if cfg := chain.Next(); cfg.Model != "b" {
+		t.Fatalf("expected b second, got %s", cfg.Model)
+	}
+	chain.RecordSuccess(time.Second)
+
+	// Second chain: "a" is open, so it is tried last
+	chain = NewLLMConfigChain(configs, false).WithHealth(health)
+	var order []string
+	for cfg := chain.Next(); cfg != nil; cfg = chain.Next() {
+		order = append(order, cfg.Model)
+		if chain.Current() != cfg {
+			t.Errorf("Current() should return the config returned by Next()")
+		}
+	}
+	if strings.Join(order, ",") != "b,c,a" {
+		t.Errorf("expected order b,c,a, got %v", order)
+	}
+	if pos, total := chain.Position(); pos != 3 || total != 3 {
+		t.Errorf("expected position 3/3, got %d/%d", pos, total)
+	}
+}
+
+func TestLLMConfigChain_ProbeTakenOnlyWhenCalled(t *testing.T) {
+	health := llm.NewHealthTracker(llm.HealthConfig{FailureThreshold: 1, Cooldown: time.Nanosecond})
+	configs := []*LLMConfigInput{{Provider: "openrouter", Model: "a"}}
+	health.RecordError("openrouter", "a", llm.WrapError(errors.New("status: 503"), "openrouter", "a", false))
+	time.Sleep(time.Millisecond) // Cooldown elapses, the circuit may be probed
+
+	// A chain that walks past the config without calling it (e.g. over budget)
+	chain := NewLLMConfigChain(configs, false).WithHealth(health)
+	chain.Next()
+	if state := health.Snapshot()[0].State; state != llm.CircuitOpen {
+		t.Fatalf("state = %q after Next, want open", state)
+	}
+
+	chain = NewLLMConfigChain(configs, false).WithHealth(health)
+	chain.Next()
+	chain.BeginCall()
+	if state := health.Snapshot()[0].State; state != llm.CircuitHalfOpen {
+		t.Errorf("state = %q after BeginCall, want half_open", state)
+	}
+}
+
+func TestLLMConfigChain_WithoutHealthKeepsOrder(t *testing.T) {
+	configs := []*LLMConfigInput{
+		{Provider: "openrouter", Model: "a"},
+		{Provider: "openrouter", Model: "b"},
+	}
+	chain := NewLLMConfigChain(configs, false)
+	chain.Next()
+	chain.RecordError(llm.WrapError(errors.New("status: 503"), "openrouter", "a", false))
+
+	if cfg := chain.Next(); cfg.Model != "b" {
+		t.Errorf("expected b, got %s", cfg.Model)
+	}
+}
+
+func TestLLMConfigChain_BYOKNotRecorded(t *testing.T) {
+	health := llm.NewHealthTracker(llm.HealthConfig{FailureThreshold: 1, Cooldown: time.Hour})
+	configs := []*LLMConfigInput{{Provider: "openai", Model: "gpt-4o", APIKey: "sk-user"}}
+
+	chain := NewLLMConfigChain(configs, true).WithHealth(health)
+	chain.Next()
+	chain.RecordError(llm.WrapError(errors.New("status: 429"), "openai", "gpt-4o", true))
+
+	if len(health.Snapshot()) != 0 {
+		t.Error("BYOK failures should not be recorded in shared provider health")
+	}
+}
+
 // Helper functions
 func boolPtr(b bool) *bool {
 	return &b
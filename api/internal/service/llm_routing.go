@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/llm"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+)
+
+// RoutingDecision records which routing policy ordered a fallback chain and why.
+// It is stored with usage insights so routing behaviour can be audited.
+type RoutingDecision struct {
+	Policy models.RoutingPolicy
+	Reason string
+}
+
+// MaxRoutingWeight is the largest weight a chain entry may have. Weights are relative
+// shares, conventionally percentages summing to 100.
+const MaxRoutingWeight = 100
+
+// validateRoutingWeight checks a chain entry's weighted routing share.
+func validateRoutingWeight(position, weight int) error {
+	if weight < 0 || weight > MaxRoutingWeight {
+		return fmt.Errorf("weight at position %d must be between 0 and %d", position, MaxRoutingWeight)
+	}
+	return nil
+}
+
+// validateRoutingPolicy checks a routing policy from API input ("" is allowed and means unchanged).
+func validateRoutingPolicy(policy models.RoutingPolicy) error {
+	if policy != "" && !policy.IsValid() {
+		return fmt.Errorf("invalid routing policy %q (valid policies: ordered, cheapest_capable, fastest, weighted)", policy)
+	}
+	return nil
+}
+
+// setRoutingPolicy stores a chain scope's routing policy. Ordered is the default, so it
+// is stored by removing the scope's row.
+func setRoutingPolicy(ctx context.Context, repo repository.RoutingPolicyRepository, scope string, policy models.RoutingPolicy) error {
+	if policy == models.RoutingOrdered {
+		return repo.Delete(ctx, scope)
+	}
+	return repo.Set(ctx, scope, policy)
+}
+
+// defaultRoutingInputTokens is the input size assumed by cheapest_capable routing before
+// the content is known. Matches the per-page estimate used for pre-flight cost checks.
+const defaultRoutingInputTokens = 2000
+
+// chainRouter orders fallback chain configs according to a routing policy.
+// Configs a policy can't rank keep their chain order after the ranked ones,
+// so every config remains available as a fallback.
+type chainRouter struct {
+	pricing *PricingService    // For cheapest_capable cost estimates
+	health  *llm.HealthTracker // For fastest p50 latencies
+	intn    func(n int) int    // Random source for weighted selection
+}
+
+// newChainRouter creates a chain router. Either dependency may be nil; the
+// policies that need it then fall back to chain order.
+func newChainRouter(pricing *PricingService, health *llm.HealthTracker) *chainRouter {
+	return &chainRouter{
+		pricing: pricing,
+		health:  health,
+		intn:    rand.IntN,
+	}
+}
+
+// route returns the configs in the order the policy wants them tried, and why.
+// estimatedInputTokens <= 0 means the input size isn't known yet.
+func (rt *chainRouter) route(policy models.RoutingPolicy, configs []*LLMConfigInput, estimatedInputTokens int) ([]*LLMConfigInput, RoutingDecision) {
+	if len(configs) <= 1 && policy != models.RoutingOrdered {
+		return configs, RoutingDecision{Policy: policy, Reason: "single model in chain"}
+	}
+
+	switch policy {
+	case models.RoutingCheapestCapable:
+		return rt.cheapestCapable(configs, estimatedInputTokens)
+	case models.RoutingFastest:
+		return rt.fastest(configs)
+	case models.RoutingWeighted:
+		return rt.weighted(configs)
+	}
+	return configs, RoutingDecision{Policy: models.RoutingOrdered, Reason: "chain order"}
+}
+
+// cheapestCapable orders models whose context window fits the input by estimated cost,
+// followed by models too small for the input in chain order.
+func (rt *chainRouter) cheapestCapable(configs []*LLMConfigInput, estimatedInputTokens int) ([]*LLMConfigInput, RoutingDecision) {
+	decision := RoutingDecision{Policy: models.RoutingCheapestCapable}
+	if rt.pricing == nil {
+		decision.Reason = "pricing unavailable, using chain order"
+		return configs, decision
+	}
+
+	inputTokens := estimatedInputTokens
+	if inputTokens <= 0 {
+		inputTokens = defaultRoutingInputTokens
+	}
+	outputTokens := inputTokens / 4
+
+	type candidate struct {
+		cfg  *LLMConfigInput
+		cost float64
+	}
+	var capable []candidate
+	var tooSmall []*LLMConfigInput
+	for _, cfg := range configs {
+		// Unknown context length (0) is treated as capable, matching ValidateContextCapacity
+		if cfg.ContextLength > 0 && inputTokens > int(float64(cfg.ContextLength)*ContextCapacityThreshold) {
+			tooSmall = append(tooSmall, cfg)
+			continue
+		}
+		capable = append(capable, candidate{
+			cfg:  cfg,
+			cost: rt.pricing.EstimateCost(cfg.Provider, cfg.Model, inputTokens, outputTokens),
+		})
+	}
+	if len(capable) == 0 {
+		decision.Reason = fmt.Sprintf("no model fits ~%d input tokens, using chain order", inputTokens)
+		return configs, decision
+	}
+
+	sort.SliceStable(capable, func(i, j int) bool { return capable[i].cost < capable[j].cost })
+
+	ordered := make([]*LLMConfigInput, 0, len(configs))
+	for _, c := range capable {
+		ordered = append(ordered, c.cfg)
+	}
+	ordered = append(ordered, tooSmall...)
+
+	decision.Reason = fmt.Sprintf("%s/%s cheapest of %d capable models for ~%d input tokens ($%.6f)",
+		capable[0].cfg.Provider, capable[0].cfg.Model, len(capable), inputTokens, capable[0].cost)
+	return ordered, decision
+}
+
+// fastest orders models by observed p50 latency, followed by models without
+// latency data in chain order.
+func (rt *chainRouter) fastest(configs []*LLMConfigInput) ([]*LLMConfigInput, RoutingDecision) {
+	decision := RoutingDecision{Policy: models.RoutingFastest}
+
+	type candidate struct {
+		cfg *LLMConfigInput
+		p50 time.Duration
+	}
+	var measured []candidate
+	var unmeasured []*LLMConfigInput
+	for _, cfg := range configs {
+		if p50, ok := rt.health.P50Latency(cfg.Provider, cfg.Model); ok {
+			measured = append(measured, candidate{cfg: cfg, p50: p50})
+		} else {
+			unmeasured = append(unmeasured, cfg)
+		}
+	}
+	if len(measured) == 0 {
+		decision.Reason = "no latency data yet, using chain order"
+		return configs, decision
+	}
+
+	sort.SliceStable(measured, func(i, j int) bool { return measured[i].p50 < measured[j].p50 })
+
+	ordered := make([]*LLMConfigInput, 0, len(configs))
+	for _, c := range measured {
+		ordered = append(ordered, c.cfg)
+	}
+	ordered = append(ordered, unmeasured...)
+
+	decision.Reason = fmt.Sprintf("%s/%s fastest of %d measured models (p50 %dms)",
+		measured[0].cfg.Provider, measured[0].cfg.Model, len(measured), measured[0].p50.Milliseconds())
+	return ordered, decision
+}
+
+// weighted picks the first model at random in proportion to its weight; the rest
+// follow in chain order. Models with weight 0 are never picked first.
+func (rt *chainRouter) weighted(configs []*LLMConfigInput) ([]*LLMConfigInput, RoutingDecision) {
+	decision := RoutingDecision{Policy: models.RoutingWeighted}
+
+	total := 0
+	for _, cfg := range configs {
+		if cfg.Weight > 0 {
+			total += cfg.Weight
+		}
+	}
+	if total == 0 {
+		decision.Reason = "no weights set, using chain order"
+		return configs, decision
+	}
+
+	pick := 0
+	for i, n := 0, rt.intn(total); i < len(configs); i++ {
+		if configs[i].Weight <= 0 {
+			continue
+		}
+		if n < configs[i].Weight {
+			pick = i
+			break
+		}
+		n -= configs[i].Weight
+	}
+
+	ordered := make([]*LLMConfigInput, 0, len(configs))
+	ordered = append(ordered, configs[pick])
+	ordered = append(ordered, configs[:pick]...)
+	ordered = append(ordered, configs[pick+1:]...)
+
+	decision.Reason = fmt.Sprintf("%s/%s selected with weight %d of %d",
+		configs[pick].Provider, configs[pick].Model, configs[pick].Weight, total)
+	return ordered, decision
+}
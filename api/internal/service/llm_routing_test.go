@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/llm"
+	"github.com/jmylchreest/refyne-api/internal/models"
+)
+
+// routedModels returns the model names of configs, comma separated, in order.
+func routedModels(configs []*LLMConfigInput) string {
+	names := make([]string, 0, len(configs))
+	for _, cfg := range configs {
+		names = append(names, cfg.Model)
+	}
+	return strings.Join(names, ",")
+}
+
+// newRoutingPricingService returns a pricing service with cached OpenRouter prices.
+func newRoutingPricingService(prices map[string]float64) *PricingService {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := NewPricingService(PricingServiceConfig{RefreshInterval: 24 * time.Hour}, logger)
+	svc.openRouterMu.Lock()
+	for model, price := range prices {
+		svc.openRouterPrices[model] = &ModelPricing{ID: model, PromptPrice: price, CompletionPrice: price}
+	}
+	svc.lastRefresh = time.Now()
+	svc.openRouterMu.Unlock()
+	return svc
+}
+
+func TestChainRouter_Ordered(t *testing.T) {
+	configs := []*LLMConfigInput{
+		{Provider: "openrouter", Model: "a"},
+		{Provider: "openrouter", Model: "b"},
+	}
+
+	routed, decision := newChainRouter(nil, nil).route(models.RoutingOrdered, configs, 0)
+	if got := routedModels(routed); got != "a,b" {
+		t.Errorf("order = %s, want a,b", got)
+	}
+	if decision.Policy != models.RoutingOrdered {
+		t.Errorf("policy = %q, want ordered", decision.Policy)
+	}
+}
+
+func TestChainRouter_CheapestCapable(t *testing.T) {
+	pricing := newRoutingPricingService(map[string]float64{
+		"vendor/expensive": 0.00001,
+		"vendor/cheap":     0.000001,
+		"vendor/tiny":      0.0000001,
+	})
+	configs := []*LLMConfigInput{
+		{Provider: "openrouter", Model: "vendor/expensive", ContextLength: 200000},
+		{Provider: "openrouter", Model: "vendor/tiny", ContextLength: 8000},
+		{Provider: "openrouter", Model: "vendor/cheap", ContextLength: 128000},
+	}
+	router := newChainRouter(pricing, nil)
+
+	t.Run("small input picks the cheapest model", func(t *testing.T) {
+		routed, decision := router.route(models.RoutingCheapestCapable, configs, 1000)
+		if got := routedModels(routed); got != "vendor/tiny,vendor/cheap,vendor/expensive" {
+			t.Errorf("order = %s", got)
+		}
+		if !strings.HasPrefix(decision.Reason, "openrouter/vendor/tiny cheapest of 3 capable models") {
+			t.Errorf("reason = %q", decision.Reason)
+		}
+	})
+
+	t.Run("models too small for the input go last", func(t *testing.T) {
+		routed, decision := router.route(models.RoutingCheapestCapable, configs, 50000)
+		if got := routedModels(routed); got != "vendor/cheap,vendor/expensive,vendor/tiny" {
+			t.Errorf("order = %s", got)
+		}
+		if !strings.Contains(decision.Reason, "of 2 capable models") {
+			t.Errorf("reason = %q", decision.Reason)
+		}
+	})
+
+	t.Run("no pricing keeps chain order", func(t *testing.T) {
+		routed, _ := newChainRouter(nil, nil).route(models.RoutingCheapestCapable, configs, 1000)
+		if got := routedModels(routed); got != "vendor/expensive,vendor/tiny,vendor/cheap" {
+			t.Errorf("order = %s", got)
+		}
+	})
+}
+
+func TestChainRouter_Fastest(t *testing.T) {
+	health := llm.NewHealthTracker(llm.HealthConfig{})
+	health.RecordLatency("openai", "slow", 3*time.Second)
+	health.RecordLatency("openai", "fast", 500*time.Millisecond)
+	configs := []*LLMConfigInput{
+		{Provider: "openai", Model: "unmeasured"},
+		{Provider: "openai", Model: "slow"},
+		{Provider: "openai", Model: "fast"},
+	}
+
+	routed, decision := newChainRouter(nil, health).route(models.RoutingFastest, configs, 0)
+	if got := routedModels(routed); got != "fast,slow,unmeasured" {
+		t.Errorf("order = %s, want fast,slow,unmeasured", got)
+	}
+	if decision.Reason != "openai/fast fastest of 2 measured models (p50 500ms)" {
+		t.Errorf("reason = %q", decision.Reason)
+	}
+
+	routed, decision = newChainRouter(nil, nil).route(models.RoutingFastest, configs, 0)
+	if got := routedModels(routed); got != "unmeasured,slow,fast" {
+		t.Errorf("order without latency data = %s", got)
+	}
+	if decision.Reason != "no latency data yet, using chain order" {
+		t.Errorf("reason = %q", decision.Reason)
+	}
+}
+
+func TestChainRouter_Weighted(t *testing.T) {
+	configs := []*LLMConfigInput{
+		{Provider: "openai", Model: "a", Weight: 70},
+		{Provider: "openai", Model: "fallback"},
+		{Provider: "openai", Model: "b", Weight: 30},
+	}
+
+	tests := []struct {
+		roll int
+		want string
+	}{
+		{0, "a,fallback,b"},
+		{69, "a,fallback,b"},
+		{70, "b,a,fallback"},
+		{99, "b,a,fallback"},
+	}
+	for _, tt := range tests {
+		router := newChainRouter(nil, nil)
+		router.intn = func(n int) int {
+			if n != 100 {
+				t.Fatalf("intn(%d), want total weight 100", n)
+			}
+			return tt.roll
+		}
+		routed, _ := router.route(models.RoutingWeighted, configs, 0)
+		if got := routedModels(routed); got != tt.want {
+			t.Errorf("roll %d: order = %s, want %s", tt.roll, got, tt.want)
+		}
+	}
+
+	unweighted := []*LLMConfigInput{{Model: "x"}, {Model: "y"}}
+	routed, decision := newChainRouter(nil, nil).route(models.RoutingWeighted, unweighted, 0)
+	if got := routedModels(routed); got != "x,y" || decision.Reason != "no weights set, using chain order" {
+		t.Errorf("unweighted chain: order = %s, reason = %q", got, decision.Reason)
+	}
+}
+
+func TestLLMConfigChain_RouteForInput(t *testing.T) {
+	pricing := newRoutingPricingService(map[string]float64{
+		"vendor/big":   0.00001,
+		"vendor/small": 0.000001,
+	})
+	configs := []*LLMConfigInput{
+		{Provider: "openrouter", Model: "vendor/big", ContextLength: 200000},
+		{Provider: "openrouter", Model: "vendor/small", ContextLength: 8000},
+	}
+
+	chain := NewLLMConfigChain(configs, false).withRouting(newChainRouter(pricing, nil), models.RoutingCheapestCapable)
+	if got := routedModels(chain.All()); got != "vendor/small,vendor/big" {
+		t.Fatalf("default estimate order = %s, want vendor/small first", got)
+	}
+
+	// A large page doesn't fit the small model, so routing is redone with the real size
+	chain.RouteForInput(20000)
+	if got := chain.Next(); got == nil || got.Model != "vendor/big" {
+		t.Fatalf("large input should route to vendor/big first, got %+v", got)
+	}
+	if routing := chain.Routing(); routing.Policy != models.RoutingCheapestCapable || !strings.Contains(routing.Reason, "~20000 input tokens") {
+		t.Errorf("routing = %+v", routing)
+	}
+
+	// Routing is fixed once the chain is being iterated
+	chain.RouteForInput(100)
+	if got := chain.Next(); got == nil || got.Model != "vendor/small" {
+		t.Errorf("second attempt = %+v, want vendor/small", got)
+	}
+}
+
+func TestLLMConfigResolver_RoutingPolicyDefaults(t *testing.T) {
+	resolver := &LLMConfigResolver{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	if got := resolver.RoutingPolicy(context.Background(), "tier:default"); got != models.RoutingOrdered {
+		t.Errorf("RoutingPolicy() without repos = %q, want ordered", got)
+	}
+}
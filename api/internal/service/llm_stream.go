@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/llm"
+)
+
+// maxStreamLineSize is the largest single line accepted from a streamed LLM response.
+const maxStreamLineSize = 1024 * 1024
+
+// idleTimer cancels a streamed request when no data arrives within the timeout.
+// Unlike an overall request timeout, it allows long generations that keep producing output.
+type idleTimer struct {
+	timer   *time.Timer
+	timeout time.Duration
+	fired   atomic.Bool
+}
+
+// newIdleTimer starts a timer that calls cancel after timeout without a reset.
+func newIdleTimer(timeout time.Duration, cancel context.CancelFunc) *idleTimer {
+	t := &idleTimer{timeout: timeout}
+	t.timer = time.AfterFunc(timeout, func() {
+		t.fired.Store(true)
+		cancel()
+	})
+	return t
+}
+
+// reset restarts the idle timeout after data has been received.
+func (t *idleTimer) reset() {
+	t.timer.Reset(t.timeout)
+}
+
+// stop stops the timer.
+func (t *idleTimer) stop() {
+	t.timer.Stop()
+}
+
+// err returns a timeout error if the timer fired, otherwise err unchanged.
+func (t *idleTimer) err(err error) error {
+	if t.fired.Load() {
+		return fmt.Errorf("LLM stream timeout: no data received for %s", t.timeout)
+	}
+	return err
+}
+
+// readStream reads a streamed chat completion, calling onDelta with each content
+// fragment, and assembles the same result a non-streamed call returns.
+func (c *LLMClient) readStream(provider string, resp *http.Response, onDelta func(string), idle *idleTimer) (*LLMCallResult, error) {
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxStreamLineSize))
+		if c.logger != nil {
+			c.logger.Error("LLM API error",
+				"provider", provider,
+				"status_code", resp.StatusCode,
+				"response", string(body),
+			)
+		}
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineSize)
+	lines := func(yield func(string) bool) {
+		for scanner.Scan() {
+			idle.reset()
+			if !yield(scanner.Text()) {
+				return
+			}
+		}
+	}
+
+	var result *LLMCallResult
+	var err error
+	switch c.apiFormat(provider) {
+	case llm.APIFormatAnthropic:
+		result, err = parseAnthropicStream(lines, onDelta)
+	case llm.APIFormatOllama:
+		result, err = parseOllamaStream(lines, onDelta)
+	case llm.APIFormatGemini:
+		result, err = parseGeminiStream(lines, onDelta)
+	default:
+		result, err = parseOpenAIStream(lines, onDelta)
+	}
+	if scanErr := scanner.Err(); scanErr != nil {
+		return nil, idle.err(fmt.Errorf("failed to read stream: %w", scanErr))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.logger != nil {
+		c.logger.Debug("LLM API stream completed",
+			"provider", provider,
+			"response_length", len(result.Content),
+			"finish_reason", result.FinishReason,
+		)
+	}
+	return result, nil
+}
+
+// sseData returns the payload of an SSE data line.
+func sseData(line string) (string, bool) {
+	data, ok := strings.CutPrefix(line, "data:")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(data), true
+}
+
+// streamError is the error object some providers send mid-stream.
+type streamError struct {
+	Message string `json:"message"`
+}
+
+// parseOpenAIStream parses an OpenAI-compatible SSE stream (data: chunks, data: [DONE]).
+// Token usage arrives in a final chunk when stream_options.include_usage is set.
+func parseOpenAIStream(lines func(func(string) bool), onDelta func(string)) (*LLMCallResult, error) {
+	var content strings.Builder
+	result := &LLMCallResult{}
+	var streamErr error
+
+	lines(func(line string) bool {
+		data, ok := sseData(line)
+		if !ok || data == "" {
+			return true
+		}
+		if data == "[DONE]" {
+			return false
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+				FinishReason string `json:"finish_reason"`
+			} `json:"choices"`
+			Usage *struct {
+				PromptTokens     int `json:"prompt_tokens"`
+				CompletionTokens int `json:"completion_tokens"`
+			} `json:"usage"`
+			Error *streamError `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			streamErr = fmt.Errorf("failed to parse OpenAI stream chunk: %w", err)
+			return false
+		}
+		if chunk.Error != nil {
+			streamErr = fmt.Errorf("API error in stream: %s", chunk.Error.Message)
+			return false
+		}
+		if chunk.Usage != nil {
+			result.InputTokens = chunk.Usage.PromptTokens
+			result.OutputTokens = chunk.Usage.CompletionTokens
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				content.WriteString(choice.Delta.Content)
+				onDelta(choice.Delta.Content)
+			}
+			if choice.FinishReason != "" {
+				result.FinishReason = choice.FinishReason
+			}
+		}
+		return true
+	})
+	if streamErr != nil {
+		return nil, streamErr
+	}
+
+	result.Content = content.String()
+	if result.Content == "" && result.FinishReason == "" {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+	return result, nil
+}
+
+// parseAnthropicStream parses an Anthropic messages SSE stream. As with non-streamed
+// responses, a tool_use block (forced structured output) takes precedence over text.
+func parseAnthropicStream(lines func(func(string) bool), onDelta func(string)) (*LLMCallResult, error) {
+	var text, toolInput strings.Builder
+	usingTool := false
+	result := &LLMCallResult{}
+	var streamErr error
+
+	lines(func(line string) bool {
+		data, ok := sseData(line)
+		if !ok || data == "" {
+			return true
+		}
+
+		var event struct {
+			Type    string `json:"type"`
+			Message struct {
+				Usage struct {
+					InputTokens  int `json:"input_tokens"`
+					OutputTokens int `json:"output_tokens"`
+				} `json:"usage"`
+			} `json:"message"`
+			ContentBlock struct {
+				Type string `json:"type"`
+			} `json:"content_block"`
+			Delta struct {
+				Type        string `json:"type"`
+				Text        string `json:"text"`
+				PartialJSON string `json:"partial_json"`
+				StopReason  string `json:"stop_reason"`
+			} `json:"delta"`
+			Usage struct {
+				OutputTokens int `json:"output_tokens"`
+			} `json:"usage"`
+			Error *streamError `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			streamErr = fmt.Errorf("failed to parse Anthropic stream event: %w", err)
+			return false
+		}
+
+		switch event.Type {
+		case "message_start":
+			result.InputTokens = event.Message.Usage.InputTokens
+			result.OutputTokens = event.Message.Usage.OutputTokens
+		case "content_block_start":
+			if event.ContentBlock.Type == "tool_use" {
+				usingTool = true
+			}
+		case "content_block_delta":
+			switch event.Delta.Type {
+			case "text_delta":
+				text.WriteString(event.Delta.Text)
+				if !usingTool {
+					onDelta(event.Delta.Text)
+				}
+			case "input_json_delta":
+				toolInput.WriteString(event.Delta.PartialJSON)
+				onDelta(event.Delta.PartialJSON)
+			}
+		case "message_delta":
+			result.FinishReason = normalizeAnthropicStopReason(event.Delta.StopReason)
+			if event.Usage.OutputTokens > 0 {
+				result.OutputTokens = event.Usage.OutputTokens
+			}
+		case "message_stop":
+			return false
+		case "error":
+			msg := "unknown error"
+			if event.Error != nil {
+				msg = event.Error.Message
+			}
+			streamErr = fmt.Errorf("API error in stream: %s", msg)
+			return false
+		}
+		return true
+	})
+	if streamErr != nil {
+		return nil, streamErr
+	}
+
+	result.Content = text.String()
+	if usingTool {
+		result.Content = toolInput.String()
+	}
+	if result.Content == "" && result.FinishReason == "" {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+	return result, nil
+}
+
+// parseOllamaStream parses Ollama's newline-delimited JSON stream.
+func parseOllamaStream(lines func(func(string) bool), onDelta func(string)) (*LLMCallResult, error) {
+	var content strings.Builder
+	result := &LLMCallResult{}
+	var streamErr error
+
+	lines(func(line string) bool {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return true
+		}
+
+		var chunk struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			Done            bool   `json:"done"`
+			DoneReason      string `json:"done_reason"`
+			PromptEvalCount int    `json:"prompt_eval_count"`
+			EvalCount       int    `json:"eval_count"`
+			Error           string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &chunk); err != nil {
+			streamErr = fmt.Errorf("failed to parse Ollama stream chunk: %w", err)
+			return false
+		}
+		if chunk.Error != "" {
+			streamErr = fmt.Errorf("API error in stream: %s", chunk.Error)
+			return false
+		}
+		if chunk.Message.Content != "" {
+			content.WriteString(chunk.Message.Content)
+			onDelta(chunk.Message.Content)
+		}
+		if chunk.Done {
+			result.FinishReason = chunk.DoneReason
+			result.InputTokens = chunk.PromptEvalCount
+			result.OutputTokens = chunk.EvalCount
+			return false
+		}
+		return true
+	})
+	if streamErr != nil {
+		return nil, streamErr
+	}
+
+	result.Content = content.String()
+	return result, nil
+}
+
+// parseGeminiStream parses a Gemini streamGenerateContent SSE stream (alt=sse), where each
+// event is a partial generateContent response and usage is cumulative.
+func parseGeminiStream(lines func(func(string) bool), onDelta func(string)) (*LLMCallResult, error) {
+	var content strings.Builder
+	result := &LLMCallResult{}
+	var streamErr error
+
+	lines(func(line string) bool {
+		data, ok := sseData(line)
+		if !ok || data == "" {
+			return true
+		}
+
+		chunk, err := llm.ParseGeminiResponse([]byte(data))
+		if err != nil {
+			streamErr = err
+			return false
+		}
+		if text := chunk.Text(); text != "" {
+			content.WriteString(text)
+			onDelta(text)
+		}
+		if reason := chunk.FinishReason(); reason != "" {
+			result.FinishReason = reason
+		}
+		if chunk.UsageMetadata.PromptTokenCount > 0 {
+			result.InputTokens = chunk.UsageMetadata.PromptTokenCount
+		}
+		if chunk.UsageMetadata.CandidatesTokenCount > 0 {
+			result.OutputTokens = chunk.UsageMetadata.CandidatesTokenCount
+		}
+		return true
+	})
+	if streamErr != nil {
+		return nil, streamErr
+	}
+
+	result.Content = content.String()
+	if result.Content == "" && result.FinishReason == "" {
+		return nil, fmt.Errorf("empty response from LLM")
+	}
+	return result, nil
+}
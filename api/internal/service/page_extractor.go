@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
 )
 
 // PageExtractor defines the interface for extracting data from a single page.
@@ -48,6 +50,30 @@ type PageExtractionResult struct {
 	// Retry info
 	UsedDynamicMode bool // True if browser rendering was used
 	RetryCount      int  // Number of retries attempted
+
+	// StructuredData describes fields filled from embedded structured data (nil if unused).
+	StructuredData *StructuredDataMeta
+
+	// Document describes how a document input was chunked (nil for web pages).
+	Document *DocumentMeta
+
+	// Hints contains the preprocessing hints applied to the prompt (for debug capture).
+	Hints map[string]string
+
+	// Network contains the XHR/fetch responses captured while rendering (for debug capture).
+	Network []captcha.NetworkResponse
+
+	// Visual describes how screenshots were used (nil in text mode).
+	Visual *VisualMeta
+
+	// Screenshots contains the screenshots sent to the model (for debug capture).
+	Screenshots []captcha.Screenshot
+
+	// BlockedRequests is the number of requests the resource policy blocked while rendering.
+	BlockedRequests int
+
+	// SolverCostUSD is what paid CAPTCHA solvers charged while rendering.
+	SolverCostUSD float64
 }
 
 // SchemaExtractorOptions configures a SchemaPageExtractor.
@@ -58,6 +84,32 @@ type SchemaExtractorOptions struct {
 	// CleanerChain is the content cleaner chain configuration.
 	CleanerChain []CleanerConfig
 
+	// Preprocessors is the preprocessor chain that generates prompt hints (nil uses defaults).
+	Preprocessors []PreprocessorConfig
+
+	// StructuredData configures the structured data fast path (nil uses defaults).
+	StructuredData *StructuredDataConfig
+
+	// Content is submitted content to extract from instead of fetching the URL (nil to fetch).
+	Content *SubmittedContent
+
+	// Actions are browser actions run before capture. Setting them fetches pages
+	// with browser rendering from the first attempt.
+	Actions []captcha.Action
+
+	// Network records XHR/fetch responses while rendering (implies browser rendering).
+	Network *NetworkCaptureConfig
+
+	// InputMode extracts from screenshots (visual) or fills text extraction gaps from
+	// them (hybrid). Screenshot modes imply browser rendering and need a vision-capable model.
+	InputMode InputMode
+
+	// FetchOptions selects page resources to block when rendering, and proxies to fetch through.
+	FetchOptions *FetchOptions
+
+	// Auth signs fetches in with a stored login (nil to fetch anonymously).
+	Auth *authSession
+
 	// ContentDynamicAllowed indicates if browser rendering is allowed.
 	ContentDynamicAllowed bool
 
@@ -82,9 +134,28 @@ type PromptExtractorOptions struct {
 	// CleanerChain is the content cleaner chain configuration.
 	CleanerChain []CleanerConfig
 
+	// Preprocessors is the preprocessor chain that generates prompt hints (nil uses defaults).
+	Preprocessors []PreprocessorConfig
+
 	// IsBYOK indicates if using user's own API keys.
 	IsBYOK bool
 
+	// Content is submitted content to extract from instead of fetching the URL (nil to fetch).
+	Content *SubmittedContent
+
+	// Actions are browser actions run before capture. Setting them fetches pages
+	// with browser rendering from the first attempt.
+	Actions []captcha.Action
+
+	// Network records XHR/fetch responses while rendering (implies browser rendering).
+	Network *NetworkCaptureConfig
+
+	// FetchOptions selects page resources to block when rendering, and proxies to fetch through.
+	FetchOptions *FetchOptions
+
+	// Auth signs fetches in with a stored login (nil to fetch anonymously).
+	Auth *authSession
+
 	// ContentDynamicAllowed indicates if browser rendering is allowed.
 	ContentDynamicAllowed bool
 
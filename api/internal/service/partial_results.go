@@ -0,0 +1,90 @@
+package service
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// partialResultBuffer is the number of partial results buffered per subscriber.
+// Results are dropped for subscribers that fall this far behind.
+const partialResultBuffer = 256
+
+// PartialResult is one array item parsed from an LLM response while it is still being
+// generated. Partial results are provisional: the page's final result (which may differ,
+// e.g. after a retry or fallback to another model) is stored when extraction completes.
+type PartialResult struct {
+	JobID string          `json:"job_id"`
+	URL   string          `json:"url"`
+	Field string          `json:"field,omitempty"` // Top-level property holding the array ("" for a root array)
+	Index int             `json:"index"`           // Position of the item in the array
+	Item  json.RawMessage `json:"item"`
+}
+
+// PartialResultBroker fans out partial results to stream subscribers, keyed by job ID.
+// Partial results only reach subscribers connected to the instance running the job.
+type PartialResultBroker struct {
+	mu   sync.RWMutex
+	subs map[string]map[chan PartialResult]struct{}
+}
+
+// NewPartialResultBroker creates an empty broker.
+func NewPartialResultBroker() *PartialResultBroker {
+	return &PartialResultBroker{subs: make(map[string]map[chan PartialResult]struct{})}
+}
+
+// Subscribe registers for partial results of a job. The returned function unsubscribes
+// and must be called when the subscriber is done.
+func (b *PartialResultBroker) Subscribe(jobID string) (<-chan PartialResult, func()) {
+	ch := make(chan PartialResult, partialResultBuffer)
+
+	b.mu.Lock()
+	if b.subs[jobID] == nil {
+		b.subs[jobID] = make(map[chan PartialResult]struct{})
+	}
+	b.subs[jobID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs[jobID], ch)
+			if len(b.subs[jobID]) == 0 {
+				delete(b.subs, jobID)
+			}
+			b.mu.Unlock()
+		})
+	}
+}
+
+// HasSubscribers reports whether anyone is listening for a job's partial results.
+func (b *PartialResultBroker) HasSubscribers(jobID string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs[jobID]) > 0
+}
+
+// Publish sends a partial result to the job's subscribers without blocking.
+func (b *PartialResultBroker) Publish(result PartialResult) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subs[result.JobID] {
+		select {
+		case ch <- result:
+		default:
+			// Subscriber is not keeping up; drop rather than stall extraction
+		}
+	}
+}
+
+// partialResultsWriter returns a delta callback that publishes array items as they are
+// parsed from a streamed LLM response, or nil when nobody is streaming the job's results.
+func partialResultsWriter(broker *PartialResultBroker, jobID, pageURL string) func(string) {
+	if broker == nil || jobID == "" || !broker.HasSubscribers(jobID) {
+		return nil
+	}
+	items := newJSONItemStream(func(field string, index int, item json.RawMessage) {
+		broker.Publish(PartialResult{JobID: jobID, URL: pageURL, Field: field, Index: index, Item: item})
+	})
+	return items.Write
+}
@@ -0,0 +1,50 @@
+package service
+
+import "testing"
+
+func TestPartialResultBroker(t *testing.T) {
+	b := NewPartialResultBroker()
+	if b.HasSubscribers("job-1") {
+		t.Fatal("new broker should have no subscribers")
+	}
+
+	ch, unsubscribe := b.Subscribe("job-1")
+	if !b.HasSubscribers("job-1") {
+		t.Fatal("HasSubscribers() = false after Subscribe")
+	}
+
+	b.Publish(PartialResult{JobID: "job-2", Index: 9})
+	b.Publish(PartialResult{JobID: "job-1", Index: 1})
+	select {
+	case got := <-ch:
+		if got.Index != 1 {
+			t.Errorf("received %+v, want job-1 result", got)
+		}
+	default:
+		t.Fatal("expected a published result")
+	}
+	select {
+	case got := <-ch:
+		t.Errorf("received result for another job: %+v", got)
+	default:
+	}
+
+	unsubscribe()
+	unsubscribe() // Safe to call twice
+	if b.HasSubscribers("job-1") {
+		t.Error("HasSubscribers() = true after unsubscribe")
+	}
+}
+
+func TestPartialResultBroker_DropsWhenSubscriberIsFull(t *testing.T) {
+	b := NewPartialResultBroker()
+	ch, unsubscribe := b.Subscribe("job")
+	defer unsubscribe()
+
+	for i := 0; i < partialResultBuffer+10; i++ {
+		b.Publish(PartialResult{JobID: "job", Index: i})
+	}
+	if len(ch) != partialResultBuffer {
+		t.Errorf("buffered %d results, want %d", len(ch), partialResultBuffer)
+	}
+}
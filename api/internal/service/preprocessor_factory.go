@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jmylchreest/refyne-api/internal/preprocessor"
+)
+
+// PreprocessorType represents a named LLM preprocessor type.
+type PreprocessorType string
+
+// Available preprocessor types
+const (
+	// PreprocessorNoop generates no hints. Use alone to disable preprocessing.
+	PreprocessorNoop PreprocessorType = "noop"
+
+	// PreprocessorHintRepeats detects repeated elements (product cards, articles, jobs)
+	// and tells the LLM how many items to expect.
+	PreprocessorHintRepeats PreprocessorType = "hint_repeats"
+
+	// PreprocessorHintFeedback detects human feedback (reviews, comments, testimonials).
+	PreprocessorHintFeedback PreprocessorType = "hint_feedback"
+)
+
+// DefaultExtractionPreprocessors is the default preprocessor chain for extraction.
+// Matches the chain the analyzer uses for schema generation.
+var DefaultExtractionPreprocessors = []PreprocessorConfig{
+	{Name: string(PreprocessorHintRepeats)},
+	{Name: string(PreprocessorHintFeedback)},
+}
+
+// PreprocessorOptions contains configuration options for preprocessors.
+type PreprocessorOptions struct {
+	// MinRepeats: minimum number of repeated elements to report (hint_repeats, hint_feedback)
+	MinRepeats int `json:"min_repeats,omitempty"`
+
+	// MinScore: minimum aggregate signal score to report feedback (hint_feedback)
+	MinScore float64 `json:"min_score,omitempty"`
+}
+
+// PreprocessorConfig defines a single preprocessor in a chain.
+type PreprocessorConfig struct {
+	// Name is the preprocessor type name (required)
+	Name string `json:"name"`
+
+	// Options contains preprocessor-specific configuration (optional)
+	Options *PreprocessorOptions `json:"options,omitempty"`
+}
+
+// PreprocessorFactory creates preprocessor instances by name.
+type PreprocessorFactory struct{}
+
+// NewPreprocessorFactory creates a new preprocessor factory.
+func NewPreprocessorFactory() *PreprocessorFactory {
+	return &PreprocessorFactory{}
+}
+
+// Create creates a single preprocessor instance from config.
+func (f *PreprocessorFactory) Create(config PreprocessorConfig) (preprocessor.LLMPreProcessor, error) {
+	preprocessorType := PreprocessorType(strings.ToLower(config.Name))
+
+	switch preprocessorType {
+	case PreprocessorNoop:
+		return preprocessor.NewNoop(), nil
+
+	case PreprocessorHintRepeats:
+		var opts []preprocessor.HintRepeatsOption
+		if config.Options != nil && config.Options.MinRepeats > 0 {
+			opts = append(opts, preprocessor.WithMinRepeats(config.Options.MinRepeats))
+		}
+		return preprocessor.NewHintRepeats(opts...), nil
+
+	case PreprocessorHintFeedback:
+		var opts []preprocessor.HintFeedbackOption
+		if config.Options != nil {
+			if config.Options.MinRepeats > 0 {
+				opts = append(opts, preprocessor.WithFeedbackMinRepeats(config.Options.MinRepeats))
+			}
+			if config.Options.MinScore > 0 {
+				opts = append(opts, preprocessor.WithFeedbackMinScore(config.Options.MinScore))
+			}
+		}
+		return preprocessor.NewHintFeedback(opts...), nil
+
+	default:
+		return nil, fmt.Errorf("unknown preprocessor type: %s (valid types: noop, hint_repeats, hint_feedback)", config.Name)
+	}
+}
+
+// CreateChain creates a preprocessor chain from a list of configs.
+// If the list is empty, returns the default extraction preprocessor chain.
+func (f *PreprocessorFactory) CreateChain(configs []PreprocessorConfig) (*preprocessor.Chain, error) {
+	if len(configs) == 0 {
+		configs = DefaultExtractionPreprocessors
+	}
+
+	preprocessors := make([]preprocessor.LLMPreProcessor, 0, len(configs))
+	for _, cfg := range configs {
+		p, err := f.Create(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create preprocessor '%s': %w", cfg.Name, err)
+		}
+		preprocessors = append(preprocessors, p)
+	}
+
+	return preprocessor.NewChain(preprocessors...), nil
+}
+
+// PreprocessorInfo describes an available preprocessor.
+type PreprocessorInfo struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Options     []CleanerOptionInfo `json:"options,omitempty"`
+}
+
+// GetAvailablePreprocessors returns information about all available preprocessors.
+func GetAvailablePreprocessors() []PreprocessorInfo {
+	return []PreprocessorInfo{
+		{
+			Name:        string(PreprocessorNoop),
+			Description: "Generates no hints. Use as the only entry to disable preprocessing.",
+		},
+		{
+			Name:        string(PreprocessorHintRepeats),
+			Description: "Detects repeated elements (products, articles, jobs, events, etc.) and tells the LLM how many items to extract. Extractions that return far fewer items than detected are retried once.",
+			Options: []CleanerOptionInfo{
+				{Name: "min_repeats", Type: "integer", Default: 3, Description: "Minimum number of repeated elements to report a listing"},
+			},
+		},
+		{
+			Name:        string(PreprocessorHintFeedback),
+			Description: "Detects human feedback such as reviews, comments and testimonials.",
+			Options: []CleanerOptionInfo{
+				{Name: "min_repeats", Type: "integer", Default: 3, Description: "Minimum number of feedback elements to report"},
+				{Name: "min_score", Type: "number", Default: 0.3, Description: "Minimum aggregate signal score to report feedback"},
+			},
+		},
+	}
+}
@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
@@ -21,20 +22,26 @@ var ErrProtectionDetected = NewErrBotProtectionDetected("", "bot protection dete
 // for bot protection signals. When protection is detected, it returns a specific
 // error that can trigger fallback to browser rendering.
 type ProtectionAwareFetcher struct {
-	detector *protection.Detector
-	logger   *slog.Logger
+	detector   *protection.Detector
+	proxies    *proxyRouter
+	clearances *ClearanceStore
+	logger     *slog.Logger
 }
 
 // ProtectionAwareFetcherConfig holds configuration for creating a ProtectionAwareFetcher.
 type ProtectionAwareFetcherConfig struct {
-	Logger *slog.Logger
+	Proxies    *proxyRouter    // Fetch through proxies, rotating away from blocked responses
+	Clearances *ClearanceStore // Challenge clearances from browser renders to fetch with
+	Logger     *slog.Logger
 }
 
 // NewProtectionAwareFetcher creates a new fetcher that detects bot protection.
 func NewProtectionAwareFetcher(cfg ProtectionAwareFetcherConfig) *ProtectionAwareFetcher {
 	return &ProtectionAwareFetcher{
-		detector: protection.NewDetector(),
-		logger:   cfg.Logger,
+		detector:   protection.NewDetector(),
+		proxies:    cfg.Proxies,
+		clearances: cfg.Clearances,
+		logger:     cfg.Logger,
 	}
 }
 
@@ -42,11 +49,71 @@ func NewProtectionAwareFetcher(cfg ProtectionAwareFetcherConfig) *ProtectionAwar
 // Returns ErrProtectionDetected if the response appears to be a challenge page.
 func (f *ProtectionAwareFetcher) Fetch(ctx context.Context, url string, opts fetcher.Options) (fetcher.Content, error) {
 	var result fetcher.Content
-	var statusCode int
-	var rawBody []byte
+	var detection protection.DetectionResult
+	if f.proxies != nil {
+		var err error
+		detection, err = f.proxies.fetch(ctx, url, func(ctx context.Context, proxy *SelectedProxy) (proxyResponse, error) {
+			var resp proxyResponse
+			result, resp, err = f.visit(url, opts, proxy)
+			return resp, err
+		})
+		if err != nil {
+			return fetcher.Content{}, err
+		}
+		if !detection.Detected && result.StatusCode >= http.StatusBadRequest {
+			return fetcher.Content{}, errors.New(http.StatusText(result.StatusCode))
+		}
+	} else {
+		var resp proxyResponse
+		var err error
+		if result, resp, err = f.visit(url, opts, nil); err != nil {
+			return fetcher.Content{}, err
+		}
+		detection = f.detector.DetectFromResponse(resp.StatusCode, nil, resp.Body)
+		if !detection.Detected && result.StatusCode >= http.StatusBadRequest {
+			return fetcher.Content{}, errors.New(http.StatusText(result.StatusCode))
+		}
+	}
+
+	// Check for bot protection signals
+	if detection.Detected && detection.IsRetryable() {
+		f.logger.Info("bot protection detected",
+			"url", url,
+			"signal", detection.Signal,
+			"confidence", detection.Confidence,
+		)
+		return result, NewErrBotProtectionDetected(
+			string(detection.Signal),
+			detection.UserMessage(),
+		)
+	}
+
+	return result, nil
+}
+
+// visit fetches a page with Colly, through proxy when one is given. When a browser
+// render cleared the site's challenge through the same exit, its cookies and user
+// agent are sent along; a clearance that gets challenged again is dropped.
+func (f *ProtectionAwareFetcher) visit(url string, opts fetcher.Options, proxy *SelectedProxy) (fetcher.Content, proxyResponse, error) {
+	var result fetcher.Content
+	var resp proxyResponse
+
+	exit := ""
+	if proxy != nil {
+		exit = proxyExit(proxy.captchaConfig())
+	}
+	userAgent := getDefaultUserAgent(opts)
+	clearance, ok := f.clearances.Get(url, exit)
+	if ok && opts.UserAgent != "" && opts.UserAgent != clearance.UserAgent {
+		// Clearances are bound to the user agent that obtained them
+		clearance = nil
+	}
+	if clearance != nil {
+		userAgent = clearance.UserAgent
+	}
 
 	c := colly.NewCollector(
-		colly.UserAgent(getDefaultUserAgent(opts)),
+		colly.UserAgent(userAgent),
 		colly.AllowURLRevisit(),
 	)
 
@@ -57,6 +124,16 @@ func (f *ProtectionAwareFetcher) Fetch(ctx context.Context, url string, opts fet
 	}
 	c.SetRequestTimeout(timeout)
 
+	if proxy != nil {
+		c.SetProxyFunc(http.ProxyURL(proxy.URL))
+		// Keep 403/429 challenge pages so a proxy blocked by the site is told apart from a broken one
+		c.ParseHTTPErrorResponse = true
+	}
+	if clearance != nil {
+		// Keep challenge pages so a clearance the site no longer accepts is noticed
+		c.ParseHTTPErrorResponse = true
+	}
+
 	// Apply cookies if provided
 	if len(opts.Cookies) > 0 {
 		var httpCookies []*http.Cookie
@@ -71,11 +148,25 @@ func (f *ProtectionAwareFetcher) Fetch(ctx context.Context, url string, opts fet
 			f.logger.Warn("failed to set cookies", "error", err)
 		}
 	}
+	if clearance != nil {
+		httpCookies := make([]*http.Cookie, 0, len(clearance.Cookies))
+		for _, cookie := range clearance.Cookies {
+			httpCookies = append(httpCookies, &http.Cookie{
+				Name:   cookie.Name,
+				Value:  cookie.Value,
+				Domain: cookie.Domain,
+				Path:   cookie.Path,
+			})
+		}
+		if err := c.SetCookies(url, httpCookies); err != nil {
+			f.logger.Warn("failed to set clearance cookies", "error", err)
+		}
+		f.logger.Debug("fetching with browser clearance", "url", url, "obtained_at", time.UnixMilli(clearance.ObtainedAt))
+	}
 
 	// Capture response
 	c.OnResponse(func(r *colly.Response) {
-		statusCode = r.StatusCode
-		rawBody = r.Body
+		resp = proxyResponse{StatusCode: r.StatusCode, Headers: *r.Headers, Body: r.Body}
 		result = fetcher.Content{
 			URL:         r.Request.URL.String(),
 			HTML:        string(r.Body),
@@ -99,26 +190,16 @@ func (f *ProtectionAwareFetcher) Fetch(ctx context.Context, url string, opts fet
 
 	// Perform the fetch
 	if err := c.Visit(url); err != nil {
-		return fetcher.Content{}, err
+		return fetcher.Content{}, proxyResponse{}, err
 	}
 
-	result.Links = links
-
-	// Check for bot protection signals
-	detection := f.detector.DetectFromResponse(statusCode, nil, rawBody)
-	if detection.Detected && detection.IsRetryable() {
-		f.logger.Info("bot protection detected",
-			"url", url,
-			"signal", detection.Signal,
-			"confidence", detection.Confidence,
-		)
-		return result, NewErrBotProtectionDetected(
-			string(detection.Signal),
-			detection.UserMessage(),
-		)
+	if clearance != nil && f.detector.DetectFromResponse(resp.StatusCode, resp.Headers, resp.Body).Detected {
+		f.clearances.Invalidate(url, exit)
+		f.logger.Info("browser clearance rejected", "url", url)
 	}
 
-	return result, nil
+	result.Links = links
+	return result, resp, nil
 }
 
 // Close releases any resources. For ProtectionAwareFetcher, this is a no-op.
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/captcha"
+	"github.com/jmylchreest/refyne-api/internal/constants"
+	"github.com/jmylchreest/refyne-api/internal/crypto"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/protection"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+)
+
+var (
+	// ErrProxyNotFound is returned when a proxy doesn't exist or belongs to someone else.
+	ErrProxyNotFound = errors.New("proxy not found")
+
+	// ErrInvalidProxy is returned when a proxy URL or country is invalid.
+	ErrInvalidProxy = errors.New("invalid proxy")
+
+	// ErrNoProxyAvailable is returned when no enabled, unbanned proxy matches a request.
+	ErrNoProxyAvailable = errors.New("no proxy available")
+)
+
+// ProxyService manages the admin-managed proxy pool and users' own proxies, and picks
+// a proxy for each fetch. Outcomes are scored per proxy and domain: proxies blocked on
+// a domain are rested for constants.ProxyBanCooldown, and the rest are weighted by
+// their success rate.
+type ProxyService struct {
+	repos     *repository.Repositories
+	encryptor *crypto.Encryptor
+	detector  *protection.Detector
+	logger    *slog.Logger
+	nowFunc   func() time.Time
+	randFunc  func() float64
+}
+
+// NewProxyService creates a new proxy service.
+func NewProxyService(repos *repository.Repositories, encryptor *crypto.Encryptor, logger *slog.Logger) *ProxyService {
+	return &ProxyService{
+		repos:     repos,
+		encryptor: encryptor,
+		detector:  protection.NewDetector(),
+		logger:    logger,
+		nowFunc:   time.Now,
+		randFunc:  rand.Float64,
+	}
+}
+
+// ProxyInput represents input for creating or updating a proxy.
+type ProxyInput struct {
+	Name      string
+	URL       string // Proxy URL including any credentials; empty on update keeps the current URL
+	Country   string // ISO 3166-1 alpha-2 exit country, optional
+	IsEnabled bool
+}
+
+// List returns a user's proxies, or the managed pool when ownerID is empty.
+func (s *ProxyService) List(ctx context.Context, ownerID string) ([]*models.Proxy, error) {
+	return s.repos.Proxy.ListByOwner(ctx, ownerID)
+}
+
+// Create adds a proxy for a user, or to the managed pool when ownerID is empty.
+// The URL is stored encrypted, as it usually carries credentials.
+func (s *ProxyService) Create(ctx context.Context, ownerID string, input ProxyInput) (*models.Proxy, error) {
+	proxy := &models.Proxy{UserID: ownerID, Name: input.Name, IsEnabled: input.IsEnabled}
+	if err := s.apply(proxy, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Proxy.Create(ctx, proxy); err != nil {
+		return nil, fmt.Errorf("failed to save proxy: %w", err)
+	}
+
+	s.logger.Info("proxy created",
+		"proxy_id", proxy.ID,
+		"user_id", ownerID,
+		"endpoint", proxy.Endpoint,
+		"country", proxy.Country,
+	)
+	return proxy, nil
+}
+
+// Update changes one of a user's proxies, or a pool proxy when ownerID is empty.
+func (s *ProxyService) Update(ctx context.Context, ownerID, id string, input ProxyInput) (*models.Proxy, error) {
+	proxy, err := s.get(ctx, ownerID, id)
+	if err != nil {
+		return nil, err
+	}
+	proxy.Name = input.Name
+	proxy.IsEnabled = input.IsEnabled
+	if err := s.apply(proxy, input); err != nil {
+		return nil, err
+	}
+	if err := s.repos.Proxy.Update(ctx, proxy); err != nil {
+		return nil, fmt.Errorf("failed to save proxy: %w", err)
+	}
+
+	s.logger.Info("proxy updated", "proxy_id", proxy.ID, "user_id", ownerID, "enabled", proxy.IsEnabled)
+	return proxy, nil
+}
+
+// Delete removes one of a user's proxies, or a pool proxy when ownerID is empty.
+func (s *ProxyService) Delete(ctx context.Context, ownerID, id string) error {
+	if _, err := s.get(ctx, ownerID, id); err != nil {
+		return err
+	}
+	if err := s.repos.Proxy.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Info("proxy deleted", "proxy_id", id, "user_id", ownerID)
+	return nil
+}
+
+// Health returns a proxy's health per domain, with the all-domains row first.
+func (s *ProxyService) Health(ctx context.Context, ownerID, id string) ([]*models.ProxyHealth, error) {
+	if _, err := s.get(ctx, ownerID, id); err != nil {
+		return nil, err
+	}
+	return s.repos.Proxy.ListHealthByProxyID(ctx, id)
+}
+
+// get returns a proxy if it belongs to ownerID (or the pool when ownerID is empty).
+func (s *ProxyService) get(ctx context.Context, ownerID, id string) (*models.Proxy, error) {
+	proxy, err := s.repos.Proxy.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proxy: %w", err)
+	}
+	if proxy == nil || proxy.UserID != ownerID {
+		return nil, ErrProxyNotFound
+	}
+	return proxy, nil
+}
+
+// apply validates the input's URL and country and sets them on proxy.
+func (s *ProxyService) apply(proxy *models.Proxy, input ProxyInput) error {
+	country := strings.ToUpper(strings.TrimSpace(input.Country))
+	if country != "" && (len(country) != 2 || strings.Trim(country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "") {
+		return fmt.Errorf("%w: country must be a two-letter ISO code", ErrInvalidProxy)
+	}
+	proxy.Country = country
+
+	if input.URL == "" {
+		if proxy.URLEncrypted == "" {
+			return fmt.Errorf("%w: url is required", ErrInvalidProxy)
+		}
+		return nil
+	}
+	u, err := parseProxyURL(input.URL)
+	if err != nil {
+		return err
+	}
+	proxy.Endpoint = u.Scheme + "://" + u.Host
+	if s.encryptor != nil {
+		encrypted, err := s.encryptor.Encrypt(u.String())
+		if err != nil {
+			return fmt.Errorf("failed to encrypt proxy url: %w", err)
+		}
+		proxy.URLEncrypted = encrypted
+	} else {
+		proxy.URLEncrypted = u.String()
+	}
+	return nil
+}
+
+// parseProxyURL validates a proxy URL. Browser rendering can't authenticate with
+// SOCKS proxies, so those must not carry credentials.
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProxy, err)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("%w: scheme must be http, https or socks5", ErrInvalidProxy)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("%w: url has no host", ErrInvalidProxy)
+	}
+	if u.Scheme == "socks5" && u.User != nil {
+		return nil, fmt.Errorf("%w: socks5 proxies with credentials aren't supported by browser rendering", ErrInvalidProxy)
+	}
+	return u, nil
+}
+
+// ProxyRequest selects proxies for a fetch.
+type ProxyRequest struct {
+	UserID  string
+	Source  string // ProxySourcePool or ProxySourceOwn
+	Country string // Only use proxies exiting in this country
+	Session string // Keep using the same proxy for this key while it stays healthy
+}
+
+// SelectedProxy is a proxy picked for a fetch, with its decrypted URL.
+type SelectedProxy struct {
+	ID      string
+	Country string
+	URL     *url.URL
+}
+
+// captchaConfig returns the proxy for browser rendering, with credentials split out.
+func (p *SelectedProxy) captchaConfig() *captcha.ProxyConfig {
+	cfg := &captcha.ProxyConfig{URL: p.URL.Scheme + "://" + p.URL.Host}
+	if p.URL.User != nil {
+		cfg.Username = p.URL.User.Username()
+		cfg.Password, _ = p.URL.User.Password()
+	}
+	return cfg
+}
+
+// Select picks a proxy for fetching from domain, skipping proxies in exclude.
+// With a session key the same proxy is returned for as long as it stays eligible;
+// otherwise proxies are picked at random, weighted by their health score.
+func (s *ProxyService) Select(ctx context.Context, req ProxyRequest, domain string, exclude map[string]bool) (*SelectedProxy, error) {
+	ownerID := ""
+	if req.Source == ProxySourceOwn {
+		ownerID = req.UserID
+	}
+	proxies, err := s.repos.Proxy.ListEnabledByOwner(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list proxies: %w", err)
+	}
+	health, err := s.repos.Proxy.GetHealth(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proxy health: %w", err)
+	}
+	overall := make(map[string]*models.ProxyHealth)
+	onDomain := make(map[string]*models.ProxyHealth)
+	for _, h := range health {
+		if h.Domain == "" {
+			overall[h.ProxyID] = h
+		} else {
+			onDomain[h.ProxyID] = h
+		}
+	}
+
+	now := s.nowFunc()
+	var candidates []*models.Proxy
+	var weights []float64
+	for _, p := range proxies {
+		if exclude[p.ID] || (req.Country != "" && !strings.EqualFold(p.Country, req.Country)) {
+			continue
+		}
+		if onDomain[p.ID].BannedAt(now, constants.ProxyBanCooldown) {
+			continue
+		}
+		candidates = append(candidates, p)
+		weights = append(weights, onDomain[p.ID].Score(constants.ProxyBanWeight)*overall[p.ID].Score(constants.ProxyBanWeight))
+	}
+	if len(candidates) == 0 {
+		if req.Country != "" {
+			return nil, fmt.Errorf("%w for country %s", ErrNoProxyAvailable, strings.ToUpper(req.Country))
+		}
+		return nil, ErrNoProxyAvailable
+	}
+
+	var chosen *models.Proxy
+	if req.Session != "" {
+		chosen = stickyProxy(candidates, req.UserID+"|"+req.Session)
+	} else {
+		chosen = weightedProxy(candidates, weights, s.randFunc())
+	}
+
+	rawURL := chosen.URLEncrypted
+	if s.encryptor != nil {
+		if rawURL, err = s.encryptor.Decrypt(chosen.URLEncrypted); err != nil {
+			return nil, fmt.Errorf("failed to decrypt proxy url: %w", err)
+		}
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy url: %w", err)
+	}
+	return &SelectedProxy{ID: chosen.ID, Country: chosen.Country, URL: u}, nil
+}
+
+// stickyProxy picks the candidate with the highest hash for the session key (rendezvous
+// hashing), so a session keeps its proxy until that proxy becomes ineligible.
+func stickyProxy(candidates []*models.Proxy, key string) *models.Proxy {
+	var best *models.Proxy
+	var bestHash uint64
+	for _, p := range candidates {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key + "|" + p.ID))
+		if sum := h.Sum64(); best == nil || sum > bestHash {
+			best, bestHash = p, sum
+		}
+	}
+	return best
+}
+
+// weightedProxy picks a candidate with probability proportional to its weight,
+// using r in [0, 1).
+func weightedProxy(candidates []*models.Proxy, weights []float64, r float64) *models.Proxy {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	target := r * total
+	for i, w := range weights {
+		if target < w {
+			return candidates[i]
+		}
+		target -= w
+	}
+	return candidates[len(candidates)-1]
+}
+
+// Report records the outcome of fetching from domain through a proxy.
+// Failures to record are logged, as they shouldn't fail the fetch.
+func (s *ProxyService) Report(ctx context.Context, proxyID, domain string, outcome models.ProxyOutcome) {
+	if err := s.repos.Proxy.RecordOutcome(ctx, proxyID, domain, outcome, s.nowFunc()); err != nil {
+		s.logger.Warn("failed to record proxy outcome", "proxy_id", proxyID, "domain", domain, "error", err)
+	}
+}
+
+// proxyResponse is what a fetch through a proxy returned, for block detection.
+type proxyResponse struct {
+	StatusCode int
+	Headers    http.Header
+	Body       []byte
+}
+
+// proxyRouter fetches pages through proxies selected for a request, moving to another
+// proxy when a page is blocked or the proxy fails. Static and browser fetches share it,
+// so both select, rotate and score proxies the same way.
+type proxyRouter struct {
+	svc    *ProxyService
+	req    ProxyRequest
+	logger *slog.Logger
+}
+
+// router returns a proxy router for req, or nil when no proxy was requested.
+func (s *ProxyService) router(req *ProxyRequest) *proxyRouter {
+	if s == nil || req == nil {
+		return nil
+	}
+	return &proxyRouter{svc: s, req: *req, logger: s.logger}
+}
+
+// fetch calls do with a proxy for targetURL, up to constants.ProxyMaxAttempts times,
+// until a response isn't blocked. It returns the block detection of the last response
+// (not detected when it got through) or the last error.
+func (r *proxyRouter) fetch(ctx context.Context, targetURL string, do func(ctx context.Context, proxy *SelectedProxy) (proxyResponse, error)) (protection.DetectionResult, error) {
+	domain := ""
+	if u, err := url.Parse(targetURL); err == nil {
+		domain = strings.ToLower(u.Hostname())
+	}
+
+	tried := make(map[string]bool)
+	var detection protection.DetectionResult
+	var lastErr error
+	for attempt := 1; attempt <= constants.ProxyMaxAttempts; attempt++ {
+		proxy, err := r.svc.Select(ctx, r.req, domain, tried)
+		if err != nil {
+			if errors.Is(err, ErrNoProxyAvailable) && attempt > 1 {
+				// Every eligible proxy has been tried
+				break
+			}
+			return protection.DetectionResult{}, err
+		}
+		tried[proxy.ID] = true
+
+		resp, err := do(ctx, proxy)
+		if err != nil {
+			if ctx.Err() != nil {
+				return protection.DetectionResult{}, err
+			}
+			r.svc.Report(ctx, proxy.ID, domain, models.ProxyOutcomeFailure)
+			r.logger.Info("proxy fetch failed, rotating",
+				"url", targetURL,
+				"proxy_id", proxy.ID,
+				"attempt", attempt,
+				"error", err,
+			)
+			detection, lastErr = protection.DetectionResult{}, err
+			continue
+		}
+
+		detection, lastErr = r.svc.detector.DetectFromResponse(resp.StatusCode, resp.Headers, resp.Body), nil
+		if !detection.IsBlock() {
+			r.svc.Report(ctx, proxy.ID, domain, models.ProxyOutcomeSuccess)
+			return detection, nil
+		}
+		r.svc.Report(ctx, proxy.ID, domain, models.ProxyOutcomeBan)
+		r.logger.Info("proxy blocked, rotating",
+			"url", targetURL,
+			"proxy_id", proxy.ID,
+			"attempt", attempt,
+			"signal", detection.Signal,
+		)
+	}
+	return detection, lastErr
+}
+
+// fetchDynamicContent renders a page with the captcha service, through proxies when
+// a router is given. When every proxy was blocked, the last rendered page is returned.
+func fetchDynamicContent(ctx context.Context, captchaSvc *CaptchaService, userID, tier string, input CaptchaSolveInput, proxies *proxyRouter) (*CaptchaSolveOutput, error) {
+	if proxies == nil {
+		return captchaSvc.FetchDynamicContent(ctx, userID, tier, input)
+	}
+
+	var result *CaptchaSolveOutput
+	_, err := proxies.fetch(ctx, input.URL, func(ctx context.Context, proxy *SelectedProxy) (proxyResponse, error) {
+		result = nil
+		input.Proxy = proxy.captchaConfig()
+		out, err := captchaSvc.FetchDynamicContent(ctx, userID, tier, input)
+		if err != nil {
+			return proxyResponse{}, err
+		}
+		result = out
+		if out.Status != "ok" || out.Solution == nil {
+			return proxyResponse{}, fmt.Errorf("browser rendering returned %s: %s", out.Status, out.Message)
+		}
+		headers := make(http.Header, len(out.Solution.Headers))
+		for k, v := range out.Solution.Headers {
+			headers.Set(k, v)
+		}
+		return proxyResponse{StatusCode: out.Solution.Status, Headers: headers, Body: []byte(out.Solution.Response)}, nil
+	})
+	if result != nil {
+		return result, nil
+	}
+	return nil, err
+}
@@ -26,16 +26,21 @@ type Services struct {
 	Balance           *BalanceService
 	Schema            *SchemaService
 	Billing           *BillingService
+	SpendCap          *SpendCapService
 	Admin             *AdminService
 	Analyzer          *AnalyzerService
 	Storage           *StorageService
 	UserLLM           *UserLLMService
+	Proxy             *ProxyService
+	AuthProfile       *AuthProfileService
 	Sitemap           *SitemapService
 	Pricing           *PricingService
 	TierSync          *TierSyncService
 	LLMConfigResolver *LLMConfigResolver
-	Captcha           *CaptchaService // For dynamic content fetching with browser rendering
+	ProviderHealth    *llm.HealthTracker      // Shared LLM provider/model circuit breaker state
+	Captcha           *CaptchaService         // For dynamic content fetching with browser rendering
 	SubscriptionCache *auth.SubscriptionCache // For API key tier/feature hydration from Clerk
+	License           *LicenseService         // Self-hosted license verification (nil in hosted mode)
 }
 
 // NewServices creates all service instances.
@@ -79,6 +84,13 @@ func NewServices(cfg *config.Config, repos *repository.Repositories, logger *slo
 	// Create shared LLM config resolver (used by extraction and analyzer services)
 	llmResolver := NewLLMConfigResolver(cfg, repos, encryptor, logger)
 
+	// Track provider/model health so failing models move to the back of fallback chains
+	providerHealth := llm.NewHealthTracker(llm.HealthConfig{
+		FailureThreshold: cfg.LLMCircuitFailureThreshold,
+		Cooldown:         cfg.LLMCircuitCooldown,
+	})
+	llmResolver.SetHealthTracker(providerHealth)
+
 	// Set up dynamic key resolver for pricing service
 	// This allows pricing service to get fresh keys from DB on each refresh attempt
 	pricingSvc.SetKeyResolver(func(ctx context.Context) string {
@@ -102,13 +114,27 @@ func NewServices(cfg *config.Config, repos *repository.Repositories, logger *slo
 	// Create extraction and analyzer services with resolver dependency
 	extractionSvc := NewExtractionServiceWithBilling(cfg, repos, billingSvc, llmResolver, encryptor, logger)
 	analyzerSvc := NewAnalyzerServiceWithBilling(cfg, repos, billingSvc, llmResolver, logger)
+	extractionSvc.SetPartialResults(jobSvc.PartialResults())
 
 	// Create webhook service with tracking and encryption support
 	webhookSvc := NewWebhookService(logger, repos.Webhook, repos.WebhookDelivery, encryptor)
 
+	// Spend caps alert via webhooks, so they're wired into billing once webhooks exist
+	spendCapSvc := NewSpendCapService(repos, webhookSvc, logger)
+	billingSvc.SetSpendCaps(spendCapSvc)
+	spendCapSvc.SetSolverCaps(cfg.SolverDailyBudgetUSD, cfg.SolverUserDailyBudgetUSD)
+
 	adminSvc := NewAdminServiceWithClerk(repos, encryptor, cfg.ClerkSecretKey, logger)
 	userLLMSvc := NewUserLLMService(repos, encryptor, logger)
 
+	// Proxy pool and users' own proxies, for fetches that request a proxy
+	proxySvc := NewProxyService(repos, encryptor, logger)
+	extractionSvc.SetProxyService(proxySvc)
+
+	// Users' stored logins, for fetching pages that require one
+	authProfileSvc := NewAuthProfileService(repos, encryptor, logger)
+	extractionSvc.SetAuthProfileService(authProfileSvc)
+
 	// Create sitemap service for URL discovery
 	sitemapSvc := NewSitemapService(logger)
 
@@ -121,9 +147,12 @@ func NewServices(cfg *config.Config, repos *repository.Repositories, logger *slo
 			Secret:     cfg.CaptchaSecret,
 			Logger:     logger,
 		})
+		// Paid CAPTCHA solves are limited by the daily solver caps
+		captchaSvc.SetSpendCaps(spendCapSvc)
 		// Wire captcha service to extraction and analyzer services for dynamic fetch mode
 		extractionSvc.SetCaptchaService(captchaSvc)
 		analyzerSvc.SetCaptchaService(captchaSvc)
+		authProfileSvc.SetCaptchaService(captchaSvc)
 		logger.Info("captcha service enabled for dynamic content fetching",
 			"service_url", cfg.CaptchaServiceURL,
 		)
@@ -157,6 +186,15 @@ func NewServices(cfg *config.Config, repos *repository.Repositories, logger *slo
 		logger.Info("subscription cache enabled for API key auth", "ttl", auth.DefaultSubscriptionCacheTTL)
 	}
 
+	// Self-hosted deployments take tier and features from an offline-verified license
+	var licenseSvc *LicenseService
+	if cfg.IsSelfHosted() {
+		licenseSvc, err = NewLicenseService(cfg, repos, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create license service: %w", err)
+		}
+	}
+
 	return &Services{
 		Auth:              authSvc,
 		Extraction:        extractionSvc,
@@ -167,16 +205,21 @@ func NewServices(cfg *config.Config, repos *repository.Repositories, logger *slo
 		Balance:           balanceSvc,
 		Schema:            schemaSvc,
 		Billing:           billingSvc,
+		SpendCap:          spendCapSvc,
 		Admin:             adminSvc,
 		Analyzer:          analyzerSvc,
 		Storage:           storageSvc,
 		UserLLM:           userLLMSvc,
+		Proxy:             proxySvc,
+		AuthProfile:       authProfileSvc,
 		Sitemap:           sitemapSvc,
 		Pricing:           pricingSvc,
 		TierSync:          tierSyncSvc,
 		LLMConfigResolver: llmResolver,
+		ProviderHealth:    providerHealth,
 		Captcha:           captchaSvc,
 		SubscriptionCache: subscriptionCache,
+		License:           licenseSvc,
 	}, nil
 }
 
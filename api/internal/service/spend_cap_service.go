@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/llm"
+	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/repository"
+)
+
+var (
+	// ErrSpendCapNotFound is returned when a spend cap or its API key doesn't belong to the user.
+	ErrSpendCapNotFound = errors.New("spend cap not found")
+
+	// ErrInvalidSpendCap is returned when a spend cap has no valid limits or period.
+	ErrInvalidSpendCap = errors.New("invalid spend cap")
+)
+
+// SpendCapAlertThresholds are the percentages of a cap at which notifications fire.
+var SpendCapAlertThresholds = []int{50, 80, 100}
+
+// SpendCapService manages per-user and per-API-key spend caps.
+// Spend is counted per UTC day and month for the user and for the API key used,
+// independently of credit balance, so caps also apply to BYOK usage.
+type SpendCapService struct {
+	repos   *repository.Repositories
+	webhook *WebhookService
+	logger  *slog.Logger
+	nowFunc func() time.Time
+
+	// Daily caps on paid CAPTCHA solver spend (0 = unlimited)
+	solverDailyUSD     float64
+	solverUserDailyUSD float64
+
+	solverMu sync.Mutex // Serializes solver budget reservations
+	spendMu  sync.Mutex // Serializes spend cap reservations
+}
+
+// NewSpendCapService creates a new spend cap service.
+// webhookSvc may be nil, in which case threshold alerts are only logged.
+func NewSpendCapService(repos *repository.Repositories, webhookSvc *WebhookService, logger *slog.Logger) *SpendCapService {
+	return &SpendCapService{
+		repos:   repos,
+		webhook: webhookSvc,
+		logger:  logger,
+		nowFunc: time.Now,
+	}
+}
+
+// SpendCapInput represents input for creating or updating a spend cap.
+type SpendCapInput struct {
+	APIKeyID   string // Empty to cap all of the user's usage
+	Period     models.SpendCapPeriod
+	MaxCostUSD *float64
+	MaxTokens  *int64
+	MaxPages   *int64
+}
+
+// SpendCapStatus is a spend cap with its usage for the current period.
+type SpendCapStatus struct {
+	Cap         *models.SpendCap
+	Usage       models.SpendUsage
+	PeriodStart time.Time
+	Percent     float64 // Largest share of any limit used, as a percentage
+}
+
+// List returns the user's spend caps with their current period usage.
+func (s *SpendCapService) List(ctx context.Context, userID string) ([]SpendCapStatus, error) {
+	caps, err := s.repos.SpendCap.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spend caps: %w", err)
+	}
+
+	now := s.nowFunc()
+	statuses := make([]SpendCapStatus, 0, len(caps))
+	for _, spendCap := range caps {
+		periodStart := spendCap.Period.Start(now)
+		usage, err := s.repos.SpendCap.GetUsage(ctx, spendCap.Scope(), spendCap.Period, periodStart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get spend usage: %w", err)
+		}
+		statuses = append(statuses, SpendCapStatus{
+			Cap:         spendCap,
+			Usage:       usage,
+			PeriodStart: periodStart,
+			Percent:     spendCap.Fraction(usage) * 100,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Set creates or replaces the user's cap for an API key (or all usage) and period.
+func (s *SpendCapService) Set(ctx context.Context, userID string, input SpendCapInput) (*models.SpendCap, error) {
+	if !input.Period.IsValid() {
+		return nil, fmt.Errorf("%w: period must be daily or monthly", ErrInvalidSpendCap)
+	}
+	if input.MaxCostUSD == nil && input.MaxTokens == nil && input.MaxPages == nil {
+		return nil, fmt.Errorf("%w: at least one of max_cost_usd, max_tokens or max_pages is required", ErrInvalidSpendCap)
+	}
+	if (input.MaxCostUSD != nil && *input.MaxCostUSD <= 0) ||
+		(input.MaxTokens != nil && *input.MaxTokens <= 0) ||
+		(input.MaxPages != nil && *input.MaxPages <= 0) {
+		return nil, fmt.Errorf("%w: limits must be greater than zero", ErrInvalidSpendCap)
+	}
+
+	if input.APIKeyID != "" {
+		key, err := s.repos.APIKey.GetByID(ctx, input.APIKeyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get API key: %w", err)
+		}
+		if key == nil || key.UserID != userID {
+			return nil, ErrSpendCapNotFound
+		}
+	}
+
+	spendCap := &models.SpendCap{
+		UserID:     userID,
+		APIKeyID:   input.APIKeyID,
+		Period:     input.Period,
+		MaxCostUSD: input.MaxCostUSD,
+		MaxTokens:  input.MaxTokens,
+		MaxPages:   input.MaxPages,
+	}
+	if err := s.repos.SpendCap.Upsert(ctx, spendCap); err != nil {
+		return nil, fmt.Errorf("failed to save spend cap: %w", err)
+	}
+
+	s.logger.Info("spend cap set",
+		"user_id", userID,
+		"cap_id", spendCap.ID,
+		"api_key_id", spendCap.APIKeyID,
+		"period", spendCap.Period,
+	)
+	return spendCap, nil
+}
+
+// Delete removes one of the user's spend caps.
+func (s *SpendCapService) Delete(ctx context.Context, userID, capID string) error {
+	spendCap, err := s.repos.SpendCap.GetByID(ctx, capID)
+	if err != nil {
+		return fmt.Errorf("failed to get spend cap: %w", err)
+	}
+	if spendCap == nil || spendCap.UserID != userID {
+		return ErrSpendCapNotFound
+	}
+
+	return s.repos.SpendCap.Delete(ctx, capID)
+}
+
+// Check returns an error if an operation expected to add pending usage would exceed
+// any of the user's caps, or the caps on the API key used. Caps are hard limits, so
+// they fail closed: if the caps or counters can't be read the operation is rejected.
+func (s *SpendCapService) Check(ctx context.Context, userID, apiKeyID string, pending models.SpendUsage) error {
+	caps, err := s.applicableCaps(ctx, userID, apiKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to get spend caps: %w", err)
+	}
+
+	now := s.nowFunc()
+	for _, spendCap := range caps {
+		used, err := s.repos.SpendCap.GetUsage(ctx, spendCap.Scope(), spendCap.Period, spendCap.Period.Start(now))
+		if err != nil {
+			return fmt.Errorf("failed to get spend usage: %w", err)
+		}
+		if limit := spendCap.Blocks(used, pending); limit != "" {
+			s.logger.Info("spend cap exceeded",
+				"user_id", userID,
+				"cap_id", spendCap.ID,
+				"api_key_id", spendCap.APIKeyID,
+				"period", spendCap.Period,
+				"limit", limit,
+			)
+			return llm.NewSpendCapExceededError(spendCapScopeLabel(spendCap), string(spendCap.Period), limit)
+		}
+	}
+
+	return nil
+}
+
+// SpendReservation is usage held against a user's spend caps for one operation until
+// it is settled with what the operation actually used.
+type SpendReservation struct {
+	Usage models.SpendUsage // Usage held against the caps
+
+	userID   string
+	apiKeyID string
+	at       time.Time // When the usage was reserved, so it is released from the same periods
+	settled  bool
+}
+
+// Reserve checks an operation expected to add pending usage against the caps, as Check
+// does, and holds the usage against them until Settle replaces it with the actual usage,
+// so concurrent operations can't spend the same remaining headroom.
+func (s *SpendCapService) Reserve(ctx context.Context, userID, apiKeyID string, pending models.SpendUsage) (*SpendReservation, error) {
+	s.spendMu.Lock()
+	defer s.spendMu.Unlock()
+
+	if err := s.Check(ctx, userID, apiKeyID, pending); err != nil {
+		return nil, err
+	}
+	reservation := &SpendReservation{Usage: pending, userID: userID, apiKeyID: apiKeyID, at: s.nowFunc()}
+	s.addUsage(ctx, userID, apiKeyID, reservation.at, pending)
+	return reservation, nil
+}
+
+// Settle records what an operation used, releasing its reservation (nil when nothing
+// was reserved). A reservation is only released once; settling it again just records
+// the usage.
+func (s *SpendCapService) Settle(ctx context.Context, userID, apiKeyID string, reservation *SpendReservation, usage models.SpendUsage) {
+	if reservation != nil && !reservation.settled {
+		reservation.settled = true
+		s.addUsage(ctx, reservation.userID, reservation.apiKeyID, reservation.at, models.SpendUsage{}.Sub(reservation.Usage))
+	}
+	s.Record(ctx, userID, apiKeyID, usage)
+}
+
+// Release returns a reservation's usage to the caps if it hasn't been settled, for
+// operations that end without recording usage.
+func (s *SpendCapService) Release(ctx context.Context, reservation *SpendReservation) {
+	if reservation == nil {
+		return
+	}
+	s.Settle(ctx, reservation.userID, reservation.apiKeyID, reservation, models.SpendUsage{})
+}
+
+// Record adds usage to the user's counters (and the API key's, if set) for every period,
+// then notifies any cap thresholds the usage crossed.
+func (s *SpendCapService) Record(ctx context.Context, userID, apiKeyID string, usage models.SpendUsage) {
+	if usage == (models.SpendUsage{}) {
+		return
+	}
+
+	now := s.nowFunc()
+	s.addUsage(ctx, userID, apiKeyID, now, usage)
+
+	caps, err := s.applicableCaps(ctx, userID, apiKeyID)
+	if err != nil {
+		s.logger.Warn("failed to get spend caps for alerts", "user_id", userID, "error", err)
+		return
+	}
+	for _, spendCap := range caps {
+		s.checkThresholds(ctx, spendCap, now)
+	}
+}
+
+// addUsage adds to the user's counters (and the API key's, if set) for every period
+// containing at. A negative usage releases a reservation.
+func (s *SpendCapService) addUsage(ctx context.Context, userID, apiKeyID string, at time.Time, usage models.SpendUsage) {
+	scopes := []string{models.SpendScope(userID, "")}
+	if apiKeyID != "" {
+		scopes = append(scopes, models.SpendScope(userID, apiKeyID))
+	}
+	for _, scope := range scopes {
+		for _, period := range models.SpendCapPeriods {
+			if err := s.repos.SpendCap.AddUsage(ctx, userID, scope, period, period.Start(at), usage); err != nil {
+				s.logger.Warn("failed to record spend", "user_id", userID, "scope", scope, "period", period, "error", err)
+			}
+		}
+	}
+}
+
+// SetSolverCaps sets the daily caps on paid CAPTCHA solver spend across all users
+// and per user. A cap of 0 is not enforced.
+func (s *SpendCapService) SetSolverCaps(dailyUSD, userDailyUSD float64) {
+	s.solverDailyUSD = dailyUSD
+	s.solverUserDailyUSD = userDailyUSD
+}
+
+// solverReserveUSD is the most a single page's CAPTCHA solve reserves from the
+// solver budgets: enough for the solver chain to try each paid solver in turn.
+const solverReserveUSD = 0.02
+
+// SolverReservation is solver budget held for one page's CAPTCHA solve until it is
+// settled with what the solve actually cost.
+type SolverReservation struct {
+	BudgetUSD float64 // Most the solve may spend across all its attempts
+
+	userID string
+	day    time.Time
+}
+
+// ReserveSolverBudget reserves part of what the user may still spend on paid CAPTCHA
+// solves today, or returns nil when neither solver cap is set. The reservation counts
+// against the caps until SettleSolverSpend replaces it with the actual cost, so
+// concurrent solves can't spend the same remaining budget.
+func (s *SpendCapService) ReserveSolverBudget(ctx context.Context, userID string) *SolverReservation {
+	s.solverMu.Lock()
+	defer s.solverMu.Unlock()
+
+	day := models.SpendCapDaily.Start(s.nowFunc())
+	budget := s.solverBudget(ctx, userID, day)
+	if budget == nil {
+		return nil
+	}
+	reservation := &SolverReservation{BudgetUSD: min(*budget, solverReserveUSD), userID: userID, day: day}
+	s.addSolverSpend(ctx, userID, day, reservation.BudgetUSD)
+	return reservation
+}
+
+// SettleSolverSpend records what a solve cost against the user's and the global
+// solver counters, releasing its reservation (nil when nothing was reserved).
+func (s *SpendCapService) SettleSolverSpend(ctx context.Context, userID string, reservation *SolverReservation, costUSD float64) {
+	if reservation == nil {
+		s.addSolverSpend(ctx, userID, models.SpendCapDaily.Start(s.nowFunc()), costUSD)
+		return
+	}
+	s.addSolverSpend(ctx, reservation.userID, reservation.day, costUSD-reservation.BudgetUSD)
+}
+
+// solverBudget returns how much the user may still spend on paid CAPTCHA solves on
+// day under the global and per-user solver caps, or nil when neither cap is set.
+// Failures to read the counters are logged and the cap is not applied.
+func (s *SpendCapService) solverBudget(ctx context.Context, userID string, day time.Time) *float64 {
+	var budget *float64
+	for _, c := range []struct {
+		scope string
+		limit float64
+	}{
+		{models.SolverSpendScope(""), s.solverDailyUSD},
+		{models.SolverSpendScope(userID), s.solverUserDailyUSD},
+	} {
+		if c.limit <= 0 {
+			continue
+		}
+		used, err := s.repos.SpendCap.GetUsage(ctx, c.scope, models.SpendCapDaily, day)
+		if err != nil {
+			s.logger.Warn("failed to get solver spend, skipping cap", "scope", c.scope, "error", err)
+			continue
+		}
+		remaining := max(c.limit-used.CostUSD, 0)
+		if budget == nil || remaining < *budget {
+			budget = &remaining
+		}
+	}
+	return budget
+}
+
+// addSolverSpend adds to the user's and the global solver counters for day. A
+// negative amount releases an unspent reservation.
+func (s *SpendCapService) addSolverSpend(ctx context.Context, userID string, day time.Time, costUSD float64) {
+	if costUSD == 0 {
+		return
+	}
+
+	usage := models.SpendUsage{CostUSD: costUSD}
+	// The global counter isn't owned by any user, so it survives user deletion
+	for _, owner := range []string{"", userID} {
+		scope := models.SolverSpendScope(owner)
+		if err := s.repos.SpendCap.AddUsage(ctx, owner, scope, models.SpendCapDaily, day, usage); err != nil {
+			s.logger.Warn("failed to record solver spend", "user_id", userID, "scope", scope, "error", err)
+		}
+	}
+}
+
+// applicableCaps returns the user-wide caps plus the caps on apiKeyID.
+func (s *SpendCapService) applicableCaps(ctx context.Context, userID, apiKeyID string) ([]*models.SpendCap, error) {
+	caps, err := s.repos.SpendCap.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var applicable []*models.SpendCap
+	for _, spendCap := range caps {
+		if spendCap.APIKeyID == "" || spendCap.APIKeyID == apiKeyID {
+			applicable = append(applicable, spendCap)
+		}
+	}
+	return applicable, nil
+}
+
+// checkThresholds marks every alert threshold the cap's usage has reached this period
+// and sends a single notification for the highest newly reached one.
+func (s *SpendCapService) checkThresholds(ctx context.Context, spendCap *models.SpendCap, now time.Time) {
+	periodStart := spendCap.Period.Start(now)
+	usage, err := s.repos.SpendCap.GetUsage(ctx, spendCap.Scope(), spendCap.Period, periodStart)
+	if err != nil {
+		s.logger.Warn("failed to get spend usage for alerts", "cap_id", spendCap.ID, "error", err)
+		return
+	}
+
+	percent := spendCap.Fraction(usage) * 100
+	notify := 0
+	for _, threshold := range SpendCapAlertThresholds {
+		if percent < float64(threshold) {
+			break
+		}
+		marked, err := s.repos.SpendCap.MarkAlerted(ctx, spendCap.ID, periodStart, threshold)
+		if err != nil {
+			s.logger.Warn("failed to record spend cap alert", "cap_id", spendCap.ID, "threshold", threshold, "error", err)
+			continue
+		}
+		if marked {
+			notify = threshold
+		}
+	}
+	if notify == 0 {
+		return
+	}
+
+	s.notify(ctx, spendCap, notify, percent, usage, periodStart)
+}
+
+// SpendCapAlert is the webhook payload for spend_cap.threshold events.
+// Subject and Message are human-readable, suitable for relaying as an email.
+type SpendCapAlert struct {
+	Subject     string            `json:"subject"`
+	Message     string            `json:"message"`
+	CapID       string            `json:"cap_id"`
+	APIKeyID    string            `json:"api_key_id,omitempty"`
+	Period      string            `json:"period"`
+	PeriodStart string            `json:"period_start"`
+	Threshold   int               `json:"threshold"`
+	Percent     float64           `json:"percent"`
+	Usage       models.SpendUsage `json:"usage"`
+	MaxCostUSD  *float64          `json:"max_cost_usd,omitempty"`
+	MaxTokens   *int64            `json:"max_tokens,omitempty"`
+	MaxPages    *int64            `json:"max_pages,omitempty"`
+}
+
+// notify logs and sends a threshold alert to the user's webhooks.
+func (s *SpendCapService) notify(ctx context.Context, spendCap *models.SpendCap, threshold int, percent float64, usage models.SpendUsage, periodStart time.Time) {
+	scope := spendCapScopeLabel(spendCap)
+	alert := SpendCapAlert{
+		Subject:     fmt.Sprintf("You've used %d%% of your %s %s spend cap", threshold, spendCap.Period, scope),
+		CapID:       spendCap.ID,
+		APIKeyID:    spendCap.APIKeyID,
+		Period:      string(spendCap.Period),
+		PeriodStart: periodStart.Format("2006-01-02"),
+		Threshold:   threshold,
+		Percent:     percent,
+		Usage:       usage,
+		MaxCostUSD:  spendCap.MaxCostUSD,
+		MaxTokens:   spendCap.MaxTokens,
+		MaxPages:    spendCap.MaxPages,
+	}
+	if threshold >= 100 {
+		alert.Message = fmt.Sprintf("Your %s %s spend cap has been reached. New extractions and crawls will be rejected until the period resets or the cap is raised.", spendCap.Period, scope)
+	} else {
+		alert.Message = fmt.Sprintf("Usage has reached %.0f%% of your %s %s spend cap. Requests will be rejected once it reaches 100%%.", percent, spendCap.Period, scope)
+	}
+
+	s.logger.Warn("spend cap threshold reached",
+		"user_id", spendCap.UserID,
+		"cap_id", spendCap.ID,
+		"api_key_id", spendCap.APIKeyID,
+		"period", spendCap.Period,
+		"threshold", threshold,
+		"percent", percent,
+	)
+
+	if s.webhook != nil {
+		s.webhook.SendForJob(context.WithoutCancel(ctx), spendCap.UserID, string(models.WebhookEventSpendCapThreshold), "", alert, nil)
+	}
+}
+
+// spendCapScopeLabel describes what a cap applies to, for user-facing messages.
+func spendCapScopeLabel(spendCap *models.SpendCap) string {
+	if spendCap.APIKeyID != "" {
+		return "API key"
+	}
+	return "account"
+}
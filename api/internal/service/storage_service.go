@@ -4,6 +4,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -108,6 +109,12 @@ type LLMRequestCapture struct {
 
 	// === Response Section ===
 	Response LLMResponseSection `json:"response"`
+
+	// === Network Section ===
+	HAR *HAR `json:"har,omitempty"` // XHR/fetch responses captured while rendering the page
+
+	// === Screenshots Section ===
+	Screenshots []DebugScreenshot `json:"screenshots,omitempty"` // Screenshots sent to a vision model (images stored under their keys)
 }
 
 // LLMRequestSection contains the request metadata and payload.
@@ -192,6 +199,22 @@ type JobDebugCapture struct {
 	Captures []LLMRequestCapture `json:"captures"`
 }
 
+// HAR merges the network captures of all requests into a single HAR.
+// Returns nil if no request captured any network responses.
+func (c *JobDebugCapture) HAR() *HAR {
+	var merged *HAR
+	for _, capture := range c.Captures {
+		if capture.HAR == nil {
+			continue
+		}
+		if merged == nil {
+			merged = NewHAR()
+		}
+		merged.Merge(capture.HAR)
+	}
+	return merged
+}
+
 // JobResults represents all results for a job.
 type JobResults struct {
 	JobID       string          `json:"job_id"`
@@ -416,6 +439,9 @@ func (s *StorageService) StoreDebugCapture(ctx context.Context, capture *JobDebu
 
 	key := fmt.Sprintf("debug/%s.json", capture.JobID)
 
+	// Screenshots are stored as images next to the capture, which references them by key
+	s.storeDebugScreenshots(ctx, capture)
+
 	data, err := json.Marshal(capture)
 	if err != nil {
 		return fmt.Errorf("failed to marshal debug capture: %w", err)
@@ -439,6 +465,43 @@ func (s *StorageService) StoreDebugCapture(ctx context.Context, capture *JobDebu
 	return nil
 }
 
+// storeDebugScreenshots uploads the captures' pending screenshot images and sets their
+// keys. Screenshots that fail to upload are dropped from the capture.
+func (s *StorageService) storeDebugScreenshots(ctx context.Context, capture *JobDebugCapture) {
+	for i := range capture.Captures {
+		shots := capture.Captures[i].Screenshots
+		stored := shots[:0]
+		for j, shot := range shots {
+			if shot.data == "" {
+				stored = append(stored, shot)
+				continue
+			}
+			img, err := base64.StdEncoding.DecodeString(shot.data)
+			if err == nil {
+				shot.Key = debugScreenshotKey(capture.JobID, i, j, shot.MimeType)
+				_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+					Bucket:      aws.String(s.bucket),
+					Key:         aws.String(shot.Key),
+					Body:        bytes.NewReader(img),
+					ContentType: aws.String(shot.MimeType),
+				})
+			}
+			if err != nil {
+				s.logger.Warn("failed to store debug screenshot",
+					"job_id", capture.JobID,
+					"capture", i,
+					"segment", j,
+					"error", err,
+				)
+				continue
+			}
+			shot.data = ""
+			stored = append(stored, shot)
+		}
+		capture.Captures[i].Screenshots = stored
+	}
+}
+
 // GetDebugCapture retrieves debug captures for a job from object storage.
 func (s *StorageService) GetDebugCapture(ctx context.Context, jobID string) (*JobDebugCapture, error) {
 	if !s.enabled {
@@ -534,6 +597,26 @@ func (s *StorageService) DeleteDebugCapture(ctx context.Context, jobID string) e
 		return fmt.Errorf("failed to delete debug capture: %w", err)
 	}
 
+	// Delete the screenshots stored with the capture
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(debugScreenshotPrefix(jobID)),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list debug screenshots: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket: aws.String(s.bucket),
+				Key:    obj.Key,
+			}); err != nil {
+				return fmt.Errorf("failed to delete debug screenshot: %w", err)
+			}
+		}
+	}
+
 	s.logger.Info("deleted debug capture", "job_id", jobID, "key", key)
 	return nil
 }
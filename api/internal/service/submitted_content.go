@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+)
+
+// Content types accepted for directly submitted content.
+const (
+	SubmittedContentHTML     = "html"
+	SubmittedContentMarkdown = "markdown"
+	SubmittedContentText     = "text"
+)
+
+// SubmittedContent is page content supplied with an extraction request in place of
+// a URL to fetch (e.g., HTML from the caller's own crawler or from behind a login).
+type SubmittedContent struct {
+	Body    string // The HTML, markdown or text to extract from
+	Type    string // html (default), markdown or text
+	BaseURL string // Optional URL the content came from, used to resolve relative links
+}
+
+// Validate checks the content type, size and base URL.
+func (c *SubmittedContent) Validate() error {
+	switch c.Type {
+	case "", SubmittedContentHTML, SubmittedContentMarkdown, SubmittedContentText:
+	default:
+		return fmt.Errorf("invalid content_type %q (valid types: html, markdown, text)", c.Type)
+	}
+	if strings.TrimSpace(c.Body) == "" {
+		return errors.New("content is empty")
+	}
+	if len(c.Body) > constants.MaxSubmittedContentSize {
+		return fmt.Errorf("content exceeds maximum size of %d bytes", constants.MaxSubmittedContentSize)
+	}
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid base_url %q: must be an absolute http or https URL", c.BaseURL)
+		}
+	}
+	return nil
+}
+
+// contentType returns the content type, defaulting to HTML.
+func (c *SubmittedContent) contentType() string {
+	if c.Type == "" {
+		return SubmittedContentHTML
+	}
+	return c.Type
+}
+
+// mimeType returns the MIME type reported for the content.
+func (c *SubmittedContent) mimeType() string {
+	switch c.contentType() {
+	case SubmittedContentMarkdown:
+		return "text/markdown"
+	case SubmittedContentText:
+		return "text/plain"
+	}
+	return "text/html"
+}
+
+// cleanerChain returns the cleaner chain to apply to the content. HTML uses the
+// requested chain (or the default); markdown and text are already clean, so unless
+// a chain is requested explicitly they skip the HTML cleaners.
+func (c *SubmittedContent) cleanerChain(requested []CleanerConfig) []CleanerConfig {
+	if len(requested) > 0 || c.contentType() == SubmittedContentHTML {
+		return requested
+	}
+	return []CleanerConfig{{Name: string(CleanerNoop)}}
+}
+
+// SubmittedContent returns the content submitted with the request, or nil when the
+// request is for a URL to fetch.
+func (in ExtractInput) SubmittedContent() *SubmittedContent {
+	if in.Content == "" {
+		return nil
+	}
+	return &SubmittedContent{
+		Body:    in.Content,
+		Type:    in.ContentType,
+		BaseURL: in.BaseURL,
+	}
+}
+
+// TargetURL returns the URL recorded for the extraction: the page URL, or the base
+// URL of submitted content (which may be empty).
+func (in ExtractInput) TargetURL() string {
+	if content := in.SubmittedContent(); content != nil {
+		return content.BaseURL
+	}
+	return in.URL
+}
+
+// contentFetcher is a fetcher that returns submitted content instead of fetching.
+type contentFetcher struct {
+	content *SubmittedContent
+}
+
+// Fetch returns the submitted content; the URL is ignored.
+func (f *contentFetcher) Fetch(_ context.Context, _ string, _ fetcher.Options) (fetcher.Content, error) {
+	content := fetcher.Content{
+		URL:         f.content.BaseURL,
+		HTML:        f.content.Body,
+		StatusCode:  200,
+		ContentType: f.content.mimeType(),
+		FetchedAt:   time.Now(),
+	}
+	if f.content.contentType() != SubmittedContentHTML {
+		// Used if a cleaner fails on non-HTML content
+		content.Text = f.content.Body
+	}
+	return content, nil
+}
+
+// Close is a no-op.
+func (f *contentFetcher) Close() error {
+	return nil
+}
+
+// Type returns the fetcher type identifier.
+func (f *contentFetcher) Type() string {
+	return "content"
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+
+	"github.com/jmylchreest/refyne-api/internal/constants"
+)
+
+func TestSubmittedContent_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content SubmittedContent
+		wantErr string
+	}{
+		{"html default type", SubmittedContent{Body: "<p>Hello</p>"}, ""},
+		{"markdown", SubmittedContent{Body: "# Hello", Type: SubmittedContentMarkdown}, ""},
+		{"text with base url", SubmittedContent{Body: "Hello", Type: SubmittedContentText, BaseURL: "https://example.com/page"}, ""},
+		{"invalid type", SubmittedContent{Body: "Hello", Type: "pdf"}, "invalid content_type"},
+		{"empty", SubmittedContent{Body: "  \n "}, "content is empty"},
+		{"too large", SubmittedContent{Body: strings.Repeat("a", constants.MaxSubmittedContentSize+1)}, "exceeds maximum size"},
+		{"relative base url", SubmittedContent{Body: "Hello", BaseURL: "/products"}, "invalid base_url"},
+		{"non-http base url", SubmittedContent{Body: "Hello", BaseURL: "file:///etc/passwd"}, "invalid base_url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.content.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubmittedContent_CleanerChain(t *testing.T) {
+	requested := []CleanerConfig{{Name: string(CleanerReadability)}}
+	noop := []CleanerConfig{{Name: string(CleanerNoop)}}
+
+	tests := []struct {
+		name      string
+		typ       string
+		requested []CleanerConfig
+		want      []CleanerConfig
+	}{
+		{"html uses default chain", SubmittedContentHTML, nil, nil},
+		{"html uses requested chain", SubmittedContentHTML, requested, requested},
+		{"markdown skips html cleaners", SubmittedContentMarkdown, nil, noop},
+		{"text skips html cleaners", SubmittedContentText, nil, noop},
+		{"text uses requested chain", SubmittedContentText, requested, requested},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &SubmittedContent{Body: "x", Type: tt.typ}
+			got := c.cleanerChain(tt.requested)
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0].Name != tt.want[0].Name) {
+				t.Errorf("cleanerChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractInput_SubmittedContent(t *testing.T) {
+	urlInput := ExtractInput{URL: "https://example.com"}
+	if urlInput.SubmittedContent() != nil {
+		t.Error("SubmittedContent() should be nil for URL input")
+	}
+	if got := urlInput.TargetURL(); got != "https://example.com" {
+		t.Errorf("TargetURL() = %q, want page URL", got)
+	}
+
+	contentInput := ExtractInput{Content: "# Hi", ContentType: SubmittedContentMarkdown, BaseURL: "https://example.com/a"}
+	content := contentInput.SubmittedContent()
+	if content == nil || content.Body != "# Hi" || content.Type != SubmittedContentMarkdown {
+		t.Fatalf("SubmittedContent() = %+v", content)
+	}
+	if got := contentInput.TargetURL(); got != "https://example.com/a" {
+		t.Errorf("TargetURL() = %q, want base URL", got)
+	}
+}
+
+func TestContentFetcher_Fetch(t *testing.T) {
+	f := &contentFetcher{content: &SubmittedContent{
+		Body:    "Plain text body",
+		Type:    SubmittedContentText,
+		BaseURL: "https://example.com/page",
+	}}
+
+	got, err := f.Fetch(context.Background(), "https://ignored.example.com", fetcher.Options{})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if got.URL != "https://example.com/page" {
+		t.Errorf("URL = %q, want base URL", got.URL)
+	}
+	if got.HTML != "Plain text body" || got.Text != "Plain text body" {
+		t.Errorf("HTML = %q, Text = %q, want the submitted body", got.HTML, got.Text)
+	}
+	if got.ContentType != "text/plain" {
+		t.Errorf("ContentType = %q, want text/plain", got.ContentType)
+	}
+	if f.Type() != "content" {
+		t.Errorf("Type() = %q, want content", f.Type())
+	}
+}
+
+func TestPromptPageExtractor_SubmittedContentSkipsFetch(t *testing.T) {
+	markdown := "# Products\n\n- Widget: $10\n- Gadget: $20"
+	e := &PromptPageExtractor{content: &SubmittedContent{
+		Body:    markdown,
+		Type:    SubmittedContentMarkdown,
+		BaseURL: "https://example.com/products",
+	}}
+
+	cleaned, raw, finalURL, err := e.fetchAndCleanContentWithMode(context.Background(), "https://example.com/products", "content", nil)
+	if err != nil {
+		t.Fatalf("fetchAndCleanContentWithMode() error = %v", err)
+	}
+	if strings.TrimSpace(cleaned) != markdown || raw != markdown {
+		t.Errorf("cleaned = %q, raw = %q, want markdown unchanged", cleaned, raw)
+	}
+	if finalURL != "https://example.com/products" {
+		t.Errorf("finalURL = %q, want base URL", finalURL)
+	}
+}
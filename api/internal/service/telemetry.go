@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmylchreest/refyne/pkg/extractor"
+	"github.com/jmylchreest/refyne/pkg/fetcher"
+	"github.com/jmylchreest/refyne/pkg/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jmylchreest/refyne-api/internal/llm"
+	"github.com/jmylchreest/refyne-api/internal/observability"
+)
+
+// instrumentedFetcher wraps a page fetcher with a span and latency metrics per fetch.
+type instrumentedFetcher struct {
+	fetcher.Fetcher
+	mode string
+}
+
+// newInstrumentedFetcher wraps inner, labelling its fetches with the requested fetch mode.
+func newInstrumentedFetcher(inner fetcher.Fetcher, mode string) *instrumentedFetcher {
+	if mode == "" {
+		mode = "auto"
+	}
+	return &instrumentedFetcher{Fetcher: inner, mode: mode}
+}
+
+// Fetch retrieves the page via the wrapped fetcher.
+func (f *instrumentedFetcher) Fetch(ctx context.Context, url string, opts fetcher.Options) (fetcher.Content, error) {
+	ctx, span := observability.StartSpan(ctx, "fetch",
+		attribute.String("fetch.mode", f.mode),
+		attribute.String("url.full", url),
+	)
+	start := time.Now()
+
+	content, err := f.Fetcher.Fetch(ctx, url, opts)
+
+	observability.ObserveFetch(f.mode, time.Since(start), err)
+	if err == nil {
+		span.SetAttributes(
+			attribute.Int("http.response.status_code", content.StatusCode),
+			attribute.String("fetch.content_type", content.ContentType),
+		)
+	}
+	observability.EndSpan(span, err)
+	return content, err
+}
+
+// llmTelemetryExtractor is an extractor decorator that records a span and metrics
+// for each LLM extraction call. It should wrap the LLM extractor directly so
+// retries by outer decorators are recorded as separate calls.
+type llmTelemetryExtractor struct {
+	decoratedExtractor
+	provider string
+	model    string
+}
+
+// newLLMTelemetryExtractor creates a telemetry decorator for the given LLM config.
+func newLLMTelemetryExtractor(llmCfg *LLMConfigInput) *llmTelemetryExtractor {
+	return &llmTelemetryExtractor{provider: llmCfg.Provider, model: llmCfg.Model}
+}
+
+// decorate implements extractorDecorator.
+func (e *llmTelemetryExtractor) decorate(inner extractor.Extractor, page *pageCapture) extractor.Extractor {
+	e.inner = inner
+	return e
+}
+
+// Name returns the underlying extractor name.
+func (e *llmTelemetryExtractor) Name() string {
+	return e.inner.Name()
+}
+
+// Extract runs the wrapped extractor inside an LLM span.
+func (e *llmTelemetryExtractor) Extract(ctx context.Context, content string, s schema.Schema) (*extractor.Result, error) {
+	ctx, span := startLLMSpan(ctx, e.provider, e.model)
+	start := time.Now()
+
+	result, err := e.inner.Extract(ctx, content, s)
+
+	model := e.model
+	var inputTokens, outputTokens int
+	if result != nil {
+		if result.Model != "" {
+			model = result.Model
+		}
+		inputTokens = result.Usage.InputTokens
+		outputTokens = result.Usage.OutputTokens
+	}
+	endLLMSpan(span, e.provider, model, start, inputTokens, outputTokens, err)
+	return result, err
+}
+
+// startLLMSpan starts a span for an LLM call.
+func startLLMSpan(ctx context.Context, provider, model string) (context.Context, trace.Span) {
+	return observability.StartSpan(ctx, "llm.call",
+		attribute.String("gen_ai.system", provider),
+		attribute.String("gen_ai.request.model", model),
+	)
+}
+
+// endLLMSpan records an LLM call's latency, token usage and error category, and ends its span.
+func endLLMSpan(span trace.Span, provider, model string, start time.Time, inputTokens, outputTokens int, err error) {
+	var category string
+	if err != nil {
+		category = llm.WrapError(err, provider, model, false).Category
+		span.SetAttributes(attribute.String("error.type", category))
+	}
+	span.SetAttributes(
+		attribute.String("gen_ai.response.model", model),
+		attribute.Int("gen_ai.usage.input_tokens", inputTokens),
+		attribute.Int("gen_ai.usage.output_tokens", outputTokens),
+	)
+	observability.ObserveLLMCall(provider, model, time.Since(start), inputTokens, outputTokens, err, category)
+	observability.EndSpan(span, err)
+}
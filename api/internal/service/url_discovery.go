@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"log/slog"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
@@ -10,6 +11,8 @@ import (
 	"time"
 
 	"github.com/gocolly/colly/v2"
+
+	"github.com/jmylchreest/refyne-api/internal/documents"
 )
 
 // URLDiscoveryOptions configures URL discovery behavior.
@@ -37,6 +40,9 @@ type URLDiscoveryOptions struct {
 
 	// NextSelector is a CSS selector for pagination links.
 	NextSelector string
+
+	// Cookies are sent with every request, e.g. an auth profile's session cookies.
+	Cookies []*http.Cookie
 }
 
 // DiscoveredURL represents a URL found during discovery.
@@ -142,6 +148,15 @@ func (d *URLDiscoverer) Discover(ctx context.Context, seedURLs []string, opts UR
 		})
 	}
 
+	// Send cookies to the seed hosts (the jar keeps each cookie to its domain)
+	if len(opts.Cookies) > 0 {
+		for _, seedURL := range seedURLs {
+			if err := c.SetCookies(seedURL, opts.Cookies); err != nil {
+				d.logger.Warn("failed to set discovery cookies", "url", seedURL, "error", err)
+			}
+		}
+	}
+
 	// Set allowed domains if same domain only
 	if allowedDomain != "" {
 		c.AllowedDomains = []string{allowedDomain}
@@ -204,8 +219,13 @@ func (d *URLDiscoverer) Discover(ctx context.Context, seedURLs []string, opts UR
 			return
 		}
 
+		// Documents (PDF, DOCX, XLSX, CSV) are leaf pages that are extracted but
+		// never crawled. They are often hosted on a CDN, so an explicit follow
+		// pattern match admits them from any domain.
+		isDocument := documents.FormatFromURL(absoluteURL) != ""
+
 		// Check domain restriction
-		if allowedDomain != "" {
+		if allowedDomain != "" && (followRegex == nil || !isDocument) {
 			parsedURL, err := url.Parse(absoluteURL)
 			if err != nil || parsedURL.Host != allowedDomain {
 				mu.Lock()
@@ -252,7 +272,7 @@ func (d *URLDiscoverer) Discover(ctx context.Context, seedURLs []string, opts UR
 
 		// Continue crawling if we haven't hit page limit
 		// Use collector.Visit for proper async queueing
-		if len(discovered) < maxURLs && depth < maxDepth {
+		if len(discovered) < maxURLs && depth < maxDepth && !isDocument {
 			go func(url string) {
 				_ = c.Visit(url)
 			}(absoluteURL)
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"log/slog"
 	"time"
+
+	"github.com/jmylchreest/refyne-api/internal/models"
 )
 
 // UserCleanupService handles deletion of all user data.
@@ -39,8 +41,11 @@ func NewUserCleanupService(db *sql.DB, storageSvc *StorageService, logger *slog.
 //   - api_keys, user_service_keys: authentication credentials
 //   - webhooks, webhook_deliveries: notification configs
 //   - user_fallback_chain: LLM preferences
+//   - spend_caps, spend_counters, spend_cap_alerts: spend limits and tracking
 //   - schema_snapshots, schema_catalog: user schemas
 //   - saved_sites: saved site configurations
+//   - proxies, proxy_health: the user's own proxies and their health
+//   - auth_profiles: stored cookies and login scripts
 //   - user_balances: current balance (transactions retained)
 //
 // This operation is irreversible.
@@ -114,8 +119,28 @@ func (s *UserCleanupService) DeleteAllUserData(ctx context.Context, userID strin
 		s.logger.Error("failed to delete user fallback chain", "user_id", userID, "error", err)
 		return err
 	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM chain_routing_policies WHERE scope = ?`, models.UserRoutingScope(userID)); err != nil {
+		s.logger.Error("failed to delete user routing policy", "user_id", userID, "error", err)
+		return err
+	}
+
+	// 7. Delete spend caps, their alert history and spend counters
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM spend_cap_alerts WHERE cap_id IN (SELECT id FROM spend_caps WHERE user_id = ?)
+	`, userID); err != nil {
+		s.logger.Error("failed to delete spend cap alerts", "user_id", userID, "error", err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM spend_caps WHERE user_id = ?`, userID); err != nil {
+		s.logger.Error("failed to delete spend caps", "user_id", userID, "error", err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM spend_counters WHERE user_id = ?`, userID); err != nil {
+		s.logger.Error("failed to delete spend counters", "user_id", userID, "error", err)
+		return err
+	}
 
-	// 7. Delete webhooks (notification configs)
+	// 8. Delete webhooks (notification configs)
 	if _, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE user_id = ?`, userID); err != nil {
 		s.logger.Error("failed to delete webhooks", "user_id", userID, "error", err)
 		return err
@@ -124,31 +149,50 @@ func (s *UserCleanupService) DeleteAllUserData(ctx context.Context, userID strin
 	// NOTE: usage_insights, usage_records, credit_transactions, telemetry_events
 	// are RETAINED for audit - provides billing history for abuse/dispute investigation
 
-	// 8. Delete user balance (current state - transactions retained for history)
+	// 9. Delete user balance (current state - transactions retained for history)
 	if _, err := tx.ExecContext(ctx, `DELETE FROM user_balances WHERE user_id = ?`, userID); err != nil {
 		s.logger.Error("failed to delete user balance", "user_id", userID, "error", err)
 		return err
 	}
 
-	// 9. Delete schema snapshots (user's saved schemas)
+	// 10. Delete schema snapshots (user's saved schemas)
 	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_snapshots WHERE user_id = ?`, userID); err != nil {
 		s.logger.Error("failed to delete schema snapshots", "user_id", userID, "error", err)
 		return err
 	}
 
-	// 10. Delete user-owned schema catalog entries
+	// 11. Delete user-owned schema catalog entries
 	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_catalog WHERE owner_user_id = ?`, userID); err != nil {
 		s.logger.Error("failed to delete schema catalog entries", "user_id", userID, "error", err)
 		return err
 	}
 
-	// 11. Delete saved sites
+	// 12. Delete saved sites
 	if _, err := tx.ExecContext(ctx, `DELETE FROM saved_sites WHERE user_id = ?`, userID); err != nil {
 		s.logger.Error("failed to delete saved sites", "user_id", userID, "error", err)
 		return err
 	}
 
-	// 12. Record the user deletion for audit tracking
+	// 13. Delete the user's own proxies (encrypted credentials) and their health.
+	// Pool proxies have an empty user_id and are never matched.
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM proxy_health WHERE proxy_id IN (SELECT id FROM proxies WHERE user_id = ? AND user_id != '')
+	`, userID); err != nil {
+		s.logger.Error("failed to delete proxy health", "user_id", userID, "error", err)
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM proxies WHERE user_id = ? AND user_id != ''`, userID); err != nil {
+		s.logger.Error("failed to delete proxies", "user_id", userID, "error", err)
+		return err
+	}
+
+	// 14. Delete auth profiles (encrypted cookies and login credentials)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM auth_profiles WHERE user_id = ?`, userID); err != nil {
+		s.logger.Error("failed to delete auth profiles", "user_id", userID, "error", err)
+		return err
+	}
+
+	// 15. Record the user deletion for audit tracking
 	deletedAt := time.Now().UTC().Format(time.RFC3339)
 	if _, err := tx.ExecContext(ctx, `
 		INSERT INTO deleted_users (user_id, deleted_at, reason) VALUES (?, ?, ?)
@@ -66,11 +66,15 @@ func TestNewUserCleanupService_WithStorage(t *testing.T) {
 //    - api_keys
 //    - user_service_keys
 //    - user_fallback_chain
+//    - chain_routing_policies (user scope)
+//    - spend_cap_alerts, spend_caps, spend_counters
 //    - webhooks
 //    - user_balances
 //    - schema_snapshots
 //    - schema_catalog (owner_user_id)
 //    - saved_sites
+//    - proxy_health, proxies
+//    - auth_profiles
 // 4. Records deletion in deleted_users table
 //
 // Retained for audit/compliance:
@@ -125,6 +125,7 @@ type UserFallbackChainEntryInput struct {
 	Model       string   `json:"model"`
 	Temperature *float64 `json:"temperature,omitempty"`
 	MaxTokens   *int     `json:"max_tokens,omitempty"`
+	Weight      int      `json:"weight,omitempty"`
 	IsEnabled   bool     `json:"is_enabled"`
 }
 
@@ -138,6 +139,9 @@ func (s *UserLLMService) SetFallbackChain(ctx context.Context, userID string, en
 		if e.Model == "" {
 			return nil, fmt.Errorf("model at position %d cannot be empty", i+1)
 		}
+		if err := validateRoutingWeight(i+1, e.Weight); err != nil {
+			return nil, err
+		}
 
 		modelEntries = append(modelEntries, &models.UserFallbackChainEntry{
 			UserID:      userID,
@@ -146,6 +150,7 @@ func (s *UserLLMService) SetFallbackChain(ctx context.Context, userID string, en
 			Model:       e.Model,
 			Temperature: e.Temperature,
 			MaxTokens:   e.MaxTokens,
+			Weight:      e.Weight,
 			IsEnabled:   e.IsEnabled,
 		})
 	}
@@ -162,6 +167,34 @@ func (s *UserLLMService) SetFallbackChain(ctx context.Context, userID string, en
 	return s.repos.UserFallbackChain.GetByUserID(ctx, userID)
 }
 
+// GetRoutingPolicy returns the routing policy of the user's fallback chain (ordered if unset).
+func (s *UserLLMService) GetRoutingPolicy(ctx context.Context, userID string) (models.RoutingPolicy, error) {
+	policy, err := s.repos.RoutingPolicy.Get(ctx, models.UserRoutingScope(userID))
+	if err != nil {
+		return "", err
+	}
+	if policy == "" {
+		return models.RoutingOrdered, nil
+	}
+	return policy, nil
+}
+
+// SetRoutingPolicy sets the routing policy of the user's fallback chain.
+func (s *UserLLMService) SetRoutingPolicy(ctx context.Context, userID string, policy models.RoutingPolicy) error {
+	if policy == "" {
+		return fmt.Errorf("routing policy cannot be empty")
+	}
+	if err := validateRoutingPolicy(policy); err != nil {
+		return err
+	}
+	if err := setRoutingPolicy(ctx, s.repos.RoutingPolicy, models.UserRoutingScope(userID), policy); err != nil {
+		return fmt.Errorf("failed to update routing policy: %w", err)
+	}
+
+	s.logger.Info("user routing policy updated", "user_id", userID, "routing_policy", policy)
+	return nil
+}
+
 // GetDecryptedKey returns the decrypted API key for a provider.
 // This is used internally by the extraction service.
 func (s *UserLLMService) GetDecryptedKey(ctx context.Context, userID, provider string) (string, error) {
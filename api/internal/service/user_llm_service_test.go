@@ -185,6 +185,7 @@ func setupUserLLMService(t *testing.T) (*UserLLMService, *userLLMServiceKeyRepos
 	repos := &repository.Repositories{
 		UserServiceKey:    keyRepo,
 		UserFallbackChain: chainRepo,
+		RoutingPolicy:     newMockRoutingPolicyRepository(),
 	}
 
 	// Create a test encryptor with a fixed key
@@ -209,6 +210,7 @@ func setupUserLLMServiceNoEncryption(t *testing.T) (*UserLLMService, *userLLMSer
 	repos := &repository.Repositories{
 		UserServiceKey:    keyRepo,
 		UserFallbackChain: chainRepo,
+		RoutingPolicy:     newMockRoutingPolicyRepository(),
 	}
 
 	logger := slog.Default()
@@ -690,6 +692,33 @@ func TestUserLLMService_SetFallbackChain_EmptyModel(t *testing.T) {
 	}
 }
 
+func TestUserLLMService_RoutingPolicy(t *testing.T) {
+	svc, _, _, _ := setupUserLLMService(t)
+	ctx := context.Background()
+
+	policy, err := svc.GetRoutingPolicy(ctx, "user-routing")
+	if err != nil {
+		t.Fatalf("failed to get routing policy: %v", err)
+	}
+	if policy != models.RoutingOrdered {
+		t.Errorf("default policy = %q, want ordered", policy)
+	}
+
+	if err := svc.SetRoutingPolicy(ctx, "user-routing", models.RoutingFastest); err != nil {
+		t.Fatalf("failed to set routing policy: %v", err)
+	}
+	if policy, _ = svc.GetRoutingPolicy(ctx, "user-routing"); policy != models.RoutingFastest {
+		t.Errorf("policy = %q, want fastest", policy)
+	}
+	if policy, _ = svc.GetRoutingPolicy(ctx, "other-user"); policy != models.RoutingOrdered {
+		t.Errorf("other user's policy = %q, want ordered", policy)
+	}
+
+	if err := svc.SetRoutingPolicy(ctx, "user-routing", "round_robin"); err == nil {
+		t.Error("expected error for invalid policy")
+	}
+}
+
 // ========================================
 // GetDecryptedKey Tests
 // ========================================
@@ -15,6 +15,7 @@ import (
 
 	"github.com/jmylchreest/refyne-api/internal/crypto"
 	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/observability"
 	"github.com/jmylchreest/refyne-api/internal/repository"
 )
 
@@ -169,6 +170,7 @@ func (s *WebhookService) deliverWithRetries(ctx context.Context, config *Webhook
 				"attempt", attempt,
 				"response_time_ms", responseTime,
 			)
+			observability.ObserveWebhookDelivery(string(delivery.Status))
 			return result
 		}
 
@@ -200,6 +202,7 @@ func (s *WebhookService) deliverWithRetries(ctx context.Context, config *Webhook
 				"error", delivery.ErrorMessage,
 			)
 		}
+		observability.ObserveWebhookDelivery(string(delivery.Status))
 	}
 
 	return result
@@ -397,6 +400,7 @@ func (s *WebhookService) ProcessPendingRetries(ctx context.Context, limit int) (
 				delivery.NextRetryAt = &nextRetry
 			}
 		}
+		observability.ObserveWebhookDelivery(string(delivery.Status))
 
 		if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
 			s.logger.Error("webhook: failed to update retry delivery", "delivery_id", delivery.ID, "error", err)
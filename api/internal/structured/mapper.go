@@ -0,0 +1,648 @@
+package structured
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jmylchreest/refyne/pkg/schema"
+)
+
+// MapResult is the outcome of mapping structured data onto a schema.
+type MapResult struct {
+	// Data holds the values for every field that could be filled.
+	Data map[string]any
+
+	// Matched lists the top-level schema fields filled from structured data.
+	Matched []string
+
+	// Missing lists the top-level schema fields that could not be filled.
+	Missing []string
+
+	// Coverage is the fraction of top-level schema fields filled (0-1).
+	Coverage float64
+
+	// RequiredSatisfied is true if every required top-level field was filled.
+	RequiredSatisfied bool
+
+	// Sources lists the structured data formats that contributed values.
+	Sources []Source
+}
+
+// fieldSynonyms maps normalized schema field names to candidate property paths
+// in schema.org vocabulary. Paths use dots to traverse nested entities
+// (e.g., "offers.price"). The field's own name is always tried first.
+var fieldSynonyms = map[string][]string{
+	"title":          {"name", "headline", "title"},
+	"name":           {"name", "headline", "title"},
+	"productname":    {"name"},
+	"headline":       {"headline", "name"},
+	"jobtitle":       {"title", "name"},
+	"description":    {"description", "abstract"},
+	"summary":        {"description", "abstract"},
+	"body":           {"articleBody", "reviewBody", "text"},
+	"content":        {"articleBody", "reviewBody", "text"},
+	"text":           {"text", "reviewBody", "articleBody"},
+	"price":          {"offers.price", "offers.lowPrice", "offers.priceSpecification.price", "price"},
+	"lowprice":       {"offers.lowPrice"},
+	"highprice":      {"offers.highPrice"},
+	"currency":       {"offers.priceCurrency", "priceCurrency", "offers.priceSpecification.priceCurrency"},
+	"pricecurrency":  {"offers.priceCurrency", "priceCurrency"},
+	"availability":   {"offers.availability", "availability"},
+	"instock":        {"offers.availability", "availability"},
+	"condition":      {"offers.itemCondition", "itemCondition"},
+	"image":          {"image", "thumbnailUrl"},
+	"imageurl":       {"image", "thumbnailUrl"},
+	"images":         {"image"},
+	"thumbnail":      {"thumbnailUrl", "image"},
+	"url":            {"url"},
+	"link":           {"url"},
+	"brand":          {"brand", "manufacturer"},
+	"manufacturer":   {"manufacturer", "brand"},
+	"sku":            {"sku", "mpn", "productID"},
+	"gtin":           {"gtin", "gtin13", "gtin12", "gtin14", "gtin8"},
+	"rating":         {"aggregateRating.ratingValue", "reviewRating.ratingValue", "ratingValue"},
+	"averagerating":  {"aggregateRating.ratingValue"},
+	"ratingcount":    {"aggregateRating.ratingCount", "aggregateRating.reviewCount"},
+	"reviewcount":    {"aggregateRating.reviewCount", "aggregateRating.ratingCount"},
+	"author":         {"author", "creator"},
+	"publisher":      {"publisher"},
+	"date":           {"datePublished", "dateCreated", "uploadDate", "startDate"},
+	"published":      {"datePublished"},
+	"publishedat":    {"datePublished"},
+	"publishdate":    {"datePublished"},
+	"updatedat":      {"dateModified"},
+	"modified":       {"dateModified"},
+	"category":       {"category", "recipeCategory", "articleSection"},
+	"tags":           {"keywords"},
+	"company":        {"hiringOrganization", "worksFor", "organizer"},
+	"employer":       {"hiringOrganization"},
+	"organization":   {"hiringOrganization", "organizer", "publisher"},
+	"location":       {"jobLocation.address", "location", "address"},
+	"address":        {"address", "location.address", "jobLocation.address"},
+	"salary":         {"baseSalary.value.value", "baseSalary.value.minValue", "baseSalary"},
+	"employmenttype": {"employmentType"},
+	"venue":          {"location.name", "location"},
+	"organizer":      {"organizer"},
+	"ingredients":    {"recipeIngredient", "ingredients"},
+	"instructions":   {"recipeInstructions"},
+	"steps":          {"recipeInstructions", "step"},
+	"yield":          {"recipeYield"},
+	"servings":       {"recipeYield"},
+	"calories":       {"nutrition.calories"},
+	"cuisine":        {"recipeCuisine"},
+	"duration":       {"duration", "totalTime"},
+	"sitename":       {"siteName", "publisher.name"},
+	"language":       {"inLanguage"},
+	"phone":          {"telephone"},
+	"email":          {"email"},
+}
+
+// Map fills the given schema from structured data.
+// Top-level scalar fields are taken from the primary entity (the top-level item
+// that matches the most fields), with gaps filled from the other top-level items
+// in source priority order. Array fields are filled from a matching array
+// property or from a collection of entities of the same type.
+func Map(doc *Document, s schema.Schema) *MapResult {
+	result := &MapResult{Data: make(map[string]any)}
+	if len(s.Fields) == 0 {
+		return result
+	}
+	if doc.IsEmpty() {
+		for _, f := range s.Fields {
+			result.Missing = append(result.Missing, f.Name)
+		}
+		return result
+	}
+
+	candidates := rankTopLevelItems(doc, s.Fields)
+	usedSources := make(map[Source]bool)
+
+	for _, f := range s.Fields {
+		value, source, ok := mapTopLevelField(doc, candidates, f)
+		if !ok {
+			result.Missing = append(result.Missing, f.Name)
+			continue
+		}
+		result.Data[f.Name] = value
+		result.Matched = append(result.Matched, f.Name)
+		usedSources[source] = true
+	}
+
+	result.Coverage = float64(len(result.Matched)) / float64(len(s.Fields))
+	result.RequiredSatisfied = true
+	for _, f := range s.Fields {
+		if f.Required {
+			if _, ok := result.Data[f.Name]; !ok {
+				result.RequiredSatisfied = false
+				break
+			}
+		}
+	}
+
+	for source := range usedSources {
+		result.Sources = append(result.Sources, source)
+	}
+	sort.Slice(result.Sources, func(i, j int) bool {
+		return sourcePriority[result.Sources[i]] < sourcePriority[result.Sources[j]]
+	})
+
+	return result
+}
+
+// rankTopLevelItems orders top-level items by how many schema fields they can
+// fill, then by source priority. Nested entities are excluded.
+func rankTopLevelItems(doc *Document, fields []schema.Field) []Item {
+	type scored struct {
+		item  Item
+		score int
+	}
+
+	var ranked []scored
+	for _, item := range doc.Items {
+		if item.Depth > 0 {
+			continue
+		}
+		score := 0
+		for _, f := range fields {
+			if f.Type == "array" {
+				continue
+			}
+			if _, ok := lookupField(item.Properties, f); ok {
+				score++
+			}
+		}
+		ranked = append(ranked, scored{item: item, score: score})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return sourcePriority[ranked[i].item.Source] < sourcePriority[ranked[j].item.Source]
+	})
+
+	items := make([]Item, 0, len(ranked))
+	for _, r := range ranked {
+		items = append(items, r.item)
+	}
+	return items
+}
+
+// mapTopLevelField resolves a single top-level schema field.
+func mapTopLevelField(doc *Document, candidates []Item, f schema.Field) (any, Source, bool) {
+	// Direct property lookup on the ranked top-level items
+	for _, item := range candidates {
+		if value, ok := lookupField(item.Properties, f); ok {
+			return value, item.Source, true
+		}
+	}
+
+	// Array fields may correspond to a collection of entities (ItemList members,
+	// product listings, reviews) rather than a single property
+	if f.Type == "array" && f.Items != nil && f.Items.Type == "object" {
+		if values, source, ok := collectEntities(doc, f); ok {
+			return values, source, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// collectEntities builds an array value from entities whose type or properties
+// match the array's item schema. The entity type contributing the most matching
+// items wins, so a listing page maps its products rather than its breadcrumbs.
+func collectEntities(doc *Document, f schema.Field) ([]any, Source, bool) {
+	type group struct {
+		source Source
+		values []any
+	}
+
+	groups := make(map[string]*group)
+	var order []string
+	wantType := singular(normalize(f.Name))
+
+	for _, item := range doc.Items {
+		typeKey := string(item.Source) + ":" + strings.Join(item.Types, ",")
+		value, matched := mapObject(item.Properties, f.Items.Properties)
+		if !matched {
+			continue
+		}
+
+		// Require at least half of the item fields to match unless the entity type
+		// names the array (e.g., "products" -> Product)
+		typeMatches := false
+		for _, t := range item.Types {
+			if normalize(t) == wantType {
+				typeMatches = true
+				break
+			}
+		}
+		if !typeMatches && len(value)*2 < len(f.Items.Properties) {
+			continue
+		}
+
+		g, ok := groups[typeKey]
+		if !ok {
+			g = &group{source: item.Source}
+			groups[typeKey] = g
+			order = append(order, typeKey)
+		}
+		g.values = append(g.values, value)
+	}
+
+	var best *group
+	for _, key := range order {
+		g := groups[key]
+		if best == nil || len(g.values) > len(best.values) ||
+			(len(g.values) == len(best.values) && sourcePriority[g.source] < sourcePriority[best.source]) {
+			best = g
+		}
+	}
+	if best == nil || len(best.values) == 0 {
+		return nil, "", false
+	}
+	return best.values, best.source, true
+}
+
+// mapObject fills an object schema from an entity's properties.
+// Returns false if no field could be filled.
+func mapObject(props map[string]any, fields []schema.Field) (map[string]any, bool) {
+	out := make(map[string]any)
+	for _, sub := range fields {
+		if value, ok := lookupField(props, sub); ok {
+			out[sub.Name] = value
+		}
+	}
+	return out, len(out) > 0
+}
+
+// lookupField finds and coerces the value for a schema field in a property map.
+func lookupField(props map[string]any, f schema.Field) (any, bool) {
+	if len(props) == 0 {
+		return nil, false
+	}
+	for _, path := range candidatePaths(f.Name) {
+		raw, ok := resolvePath(props, path)
+		if !ok {
+			continue
+		}
+		if value, ok := coerce(raw, f); ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// candidatePaths returns the property paths to try for a field name: the name
+// itself, its synonyms, and the synonyms of its last word
+// (e.g., "product_price" -> "price").
+func candidatePaths(fieldName string) []string {
+	key := normalize(fieldName)
+	paths := []string{fieldName}
+	paths = append(paths, fieldSynonyms[key]...)
+
+	words := strings.FieldsFunc(fieldName, func(r rune) bool { return r == '_' || r == '-' || r == ' ' || r == '.' })
+	if len(words) > 1 {
+		last := normalize(words[len(words)-1])
+		paths = append(paths, last)
+		paths = append(paths, fieldSynonyms[last]...)
+	}
+	return paths
+}
+
+// resolvePath walks a dotted property path through nested entities.
+// Arrays encountered before the final segment are traversed via their first element.
+func resolvePath(props map[string]any, path string) (any, bool) {
+	var current any = props
+	for _, segment := range strings.Split(path, ".") {
+		if arr, ok := current.([]any); ok {
+			if len(arr) == 0 {
+				return nil, false
+			}
+			current = arr[0]
+		}
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		value, found := getProperty(m, segment)
+		if !found {
+			return nil, false
+		}
+		current = value
+	}
+	if isEmpty(current) {
+		return nil, false
+	}
+	return current, true
+}
+
+// getProperty looks up a property by normalized name
+// ("datePublished" matches "date_published").
+func getProperty(m map[string]any, name string) (any, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	want := normalize(name)
+	for key, v := range m {
+		if normalize(key) == want {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// ========================================
+// Type coercion
+// ========================================
+
+var numberPattern = regexp.MustCompile(`-?\d[\d,. ]*`)
+
+// coerce converts a structured data value to the schema field's type.
+func coerce(value any, f schema.Field) (any, bool) {
+	switch f.Type {
+	case "number", "integer":
+		n, ok := toNumber(value)
+		if !ok {
+			return nil, false
+		}
+		if f.Type == "integer" {
+			return int64(math.Round(n)), true
+		}
+		return n, true
+
+	case "boolean":
+		return toBool(value)
+
+	case "object":
+		props, ok := firstElement(value).(map[string]any)
+		if !ok || len(f.Properties) == 0 {
+			return nil, false
+		}
+		obj, matched := mapObject(props, f.Properties)
+		if !matched {
+			return nil, false
+		}
+		return obj, true
+
+	case "array":
+		elems, ok := value.([]any)
+		if !ok {
+			elems = []any{value}
+		}
+		if f.Items == nil {
+			return elems, len(elems) > 0
+		}
+		out := make([]any, 0, len(elems))
+		for _, elem := range elems {
+			if v, ok := coerce(elem, *f.Items); ok {
+				out = append(out, v)
+			}
+		}
+		return out, len(out) > 0
+
+	default: // string and untyped fields
+		s, ok := toString(value, isURLField(f.Name))
+		if !ok {
+			return nil, false
+		}
+		return s, true
+	}
+}
+
+// toString converts a value to a string. Entities are reduced to their name
+// (or value/url/text); arrays are joined unless the field holds a single URL.
+func toString(value any, singleValue bool) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		return s, s != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case map[string]any:
+		for _, key := range []string{"name", "@value", "url", "contentUrl", "text", "value"} {
+			if inner, ok := v[key]; ok {
+				if s, ok := toString(inner, singleValue); ok {
+					return s, true
+				}
+			}
+		}
+		return joinScalarValues(v)
+	case []any:
+		if singleValue {
+			for _, elem := range v {
+				if s, ok := toString(elem, true); ok {
+					return s, true
+				}
+			}
+			return "", false
+		}
+		var parts []string
+		for _, elem := range v {
+			if s, ok := toString(elem, false); ok {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", "), len(parts) > 0
+	}
+	return "", false
+}
+
+// addressFields orders PostalAddress properties for readable output.
+var addressFields = []string{"streetAddress", "addressLocality", "addressRegion", "postalCode", "addressCountry"}
+
+// joinScalarValues flattens an entity without a name into a single string.
+// Addresses are joined in postal order; other entities in key order.
+func joinScalarValues(m map[string]any) (string, bool) {
+	var keys []string
+	for _, key := range addressFields {
+		if _, ok := m[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		for key := range m {
+			if !strings.HasPrefix(key, "@") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+	}
+
+	var parts []string
+	for _, key := range keys {
+		switch inner := m[key].(type) {
+		case string, float64:
+			if s, ok := toString(inner, true); ok {
+				parts = append(parts, s)
+			}
+		case map[string]any:
+			// e.g. addressCountry: {"@type": "Country", "name": "GB"}
+			if name, ok := inner["name"].(string); ok && name != "" {
+				parts = append(parts, name)
+			}
+		}
+	}
+	return strings.Join(parts, ", "), len(parts) > 0
+}
+
+// toNumber parses numbers from JSON numbers or formatted strings such as
+// "£1,299.99", "12,50 €" or "4.5 out of 5".
+func toNumber(value any) (float64, bool) {
+	switch v := firstElement(value).(type) {
+	case float64:
+		return v, true
+	case map[string]any:
+		for _, key := range []string{"@value", "value", "price", "ratingValue"} {
+			if inner, ok := v[key]; ok {
+				return toNumber(inner)
+			}
+		}
+		return 0, false
+	case string:
+		match := strings.TrimSpace(numberPattern.FindString(v))
+		if match == "" {
+			return 0, false
+		}
+		match = strings.ReplaceAll(match, " ", "")
+		match = normalizeDecimal(match)
+		n, err := strconv.ParseFloat(match, 64)
+		if err != nil {
+			return 0, false
+		}
+		return n, true
+	}
+	return 0, false
+}
+
+// normalizeDecimal converts locale-formatted numbers to Go float syntax.
+// A trailing separator followed by one or two digits is treated as the
+// decimal point; all other separators are thousands separators.
+func normalizeDecimal(s string) string {
+	s = strings.TrimRight(s, ".,")
+	lastSep := strings.LastIndexAny(s, ".,")
+	if lastSep < 0 {
+		return s
+	}
+
+	decimals := len(s) - lastSep - 1
+	isDecimal := decimals > 0 && decimals <= 2
+	// A single dot with three decimals ("1.234") is ambiguous; JSON-LD and
+	// microdata content attributes use dots as decimal points, so keep it
+	if !isDecimal && s[lastSep] == '.' && strings.Count(s, ".") == 1 && !strings.Contains(s, ",") {
+		return s
+	}
+
+	intPart := strings.NewReplacer(",", "", ".", "").Replace(s[:lastSep])
+	if isDecimal {
+		return intPart + "." + s[lastSep+1:]
+	}
+	return intPart + s[lastSep+1:]
+}
+
+// toBool converts booleans, common truthy strings and schema.org
+// availability values ("https://schema.org/InStock") to bool.
+func toBool(value any) (any, bool) {
+	switch v := firstElement(value).(type) {
+	case bool:
+		return v, true
+	case float64:
+		return v != 0, true
+	case string:
+		s := strings.ToLower(trimVocabulary(v))
+		switch s {
+		case "true", "yes", "1", "instock", "onlineonly", "instoreonly", "limitedavailability", "presale", "preorder":
+			return true, true
+		case "false", "no", "0", "outofstock", "soldout", "discontinued":
+			return false, true
+		}
+	}
+	return nil, false
+}
+
+// firstElement returns the first element of an array value, or the value itself.
+func firstElement(value any) any {
+	if arr, ok := value.([]any); ok {
+		if len(arr) == 0 {
+			return nil
+		}
+		return arr[0]
+	}
+	return value
+}
+
+// isURLField returns true for fields that should hold a single URL.
+func isURLField(name string) bool {
+	n := normalize(name)
+	return strings.Contains(n, "url") || strings.Contains(n, "link") ||
+		n == "image" || n == "thumbnail" || strings.HasSuffix(n, "image")
+}
+
+// isEmpty returns true for nil, blank strings and empty collections.
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// normalize lowercases a name and strips non-alphanumeric characters so that
+// camelCase, snake_case and kebab-case spellings compare equal.
+func normalize(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// singular returns a naive singular form of a normalized plural name
+// ("products" -> "product", "categories" -> "category").
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "ses") && len(name) > 3:
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name
+}
+
+// Subschema returns a copy of the schema restricted to the named top-level fields.
+func Subschema(s schema.Schema, fieldNames []string) schema.Schema {
+	keep := make(map[string]bool, len(fieldNames))
+	for _, name := range fieldNames {
+		keep[name] = true
+	}
+
+	sub := s
+	sub.Fields = nil
+	for _, f := range s.Fields {
+		if keep[f.Name] {
+			sub.Fields = append(sub.Fields, f)
+		}
+	}
+	return sub
+}
+
+// String returns a short human-readable summary for logging.
+func (r *MapResult) String() string {
+	return fmt.Sprintf("coverage=%.2f matched=%d missing=%d", r.Coverage, len(r.Matched), len(r.Missing))
+}
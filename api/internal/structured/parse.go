@@ -0,0 +1,421 @@
+// Package structured extracts embedded structured data (JSON-LD, microdata and
+// OpenGraph) from HTML pages and maps it onto extraction schemas.
+//
+// Many commerce, recipe, job and article pages already publish schema.org data
+// for search engines. When that data covers the fields a user asked for, the
+// extraction can be answered without an LLM call, or the LLM prompt can be
+// reduced to just the fields the structured data does not provide.
+package structured
+
+import (
+	"encoding/json"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Source identifies the markup format a structured data item was found in.
+type Source string
+
+const (
+	SourceJSONLD    Source = "json-ld"
+	SourceMicrodata Source = "microdata"
+	SourceOpenGraph Source = "opengraph"
+)
+
+// sourcePriority orders sources by how reliable their data tends to be.
+// JSON-LD is usually generated from the same data as the page itself,
+// microdata is tied to rendered markup, OpenGraph is a sharing summary.
+var sourcePriority = map[Source]int{
+	SourceJSONLD:    0,
+	SourceMicrodata: 1,
+	SourceOpenGraph: 2,
+}
+
+// Item is a single structured data entity found on a page.
+type Item struct {
+	// Source is the markup format the item came from.
+	Source Source
+
+	// Types are the schema.org types (e.g., "Product") or the og:type value.
+	// Type names are stored without their vocabulary prefix.
+	Types []string
+
+	// Properties holds the entity's properties. Values are strings, float64,
+	// bool, map[string]any (nested entities) or []any (repeated properties).
+	Properties map[string]any
+
+	// Depth is the nesting level of the entity (0 for top-level entities).
+	Depth int
+}
+
+// HasType returns true if the item has the given type (case-insensitive).
+func (i Item) HasType(t string) bool {
+	for _, typ := range i.Types {
+		if strings.EqualFold(typ, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Document holds all structured data items found on a page.
+type Document struct {
+	Items []Item
+}
+
+// IsEmpty returns true if no structured data was found.
+func (d *Document) IsEmpty() bool {
+	return d == nil || len(d.Items) == 0
+}
+
+// Sources returns the distinct sources present in the document, most reliable first.
+func (d *Document) Sources() []Source {
+	if d.IsEmpty() {
+		return nil
+	}
+	seen := make(map[Source]bool)
+	var sources []Source
+	for _, item := range d.Items {
+		if !seen[item.Source] {
+			seen[item.Source] = true
+			sources = append(sources, item.Source)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sourcePriority[sources[i]] < sourcePriority[sources[j]]
+	})
+	return sources
+}
+
+// Parse extracts all structured data items from an HTML document.
+// Malformed blocks are skipped; an unparseable document yields an empty result.
+func Parse(htmlContent string) *Document {
+	doc := &Document{}
+	if strings.TrimSpace(htmlContent) == "" {
+		return doc
+	}
+
+	root, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return doc
+	}
+
+	doc.Items = append(doc.Items, parseJSONLD(root)...)
+	doc.Items = append(doc.Items, parseMicrodata(root)...)
+	if og := parseOpenGraph(root); og != nil {
+		doc.Items = append(doc.Items, *og)
+	}
+
+	return doc
+}
+
+// ========================================
+// JSON-LD
+// ========================================
+
+// parseJSONLD extracts entities from <script type="application/ld+json"> blocks.
+// Top-level entities, @graph members and nested typed entities (offers, reviews,
+// list elements) are all returned as items so that array fields can be mapped
+// from nested collections.
+func parseJSONLD(root *goquery.Document) []Item {
+	var items []Item
+
+	root.Find(`script[type="application/ld+json"]`).Each(func(_ int, sel *goquery.Selection) {
+		raw := strings.TrimSpace(sel.Text())
+		if raw == "" {
+			return
+		}
+
+		var data any
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return
+		}
+
+		items = append(items, collectJSONLD(data, 0)...)
+	})
+
+	return items
+}
+
+// collectJSONLD walks a decoded JSON-LD value and collects typed entities.
+func collectJSONLD(value any, depth int) []Item {
+	var items []Item
+
+	switch v := value.(type) {
+	case []any:
+		for _, elem := range v {
+			items = append(items, collectJSONLD(elem, depth)...)
+		}
+
+	case map[string]any:
+		// @graph containers hold a flat list of top-level entities
+		if graph, ok := v["@graph"]; ok {
+			items = append(items, collectJSONLD(graph, depth)...)
+		}
+
+		types := jsonLDTypes(v["@type"])
+		if len(types) > 0 {
+			items = append(items, Item{
+				Source:     SourceJSONLD,
+				Types:      types,
+				Properties: unescapeStrings(v).(map[string]any),
+				Depth:      depth,
+			})
+		}
+
+		// Nested typed entities become their own items one level deeper.
+		// Keys are visited in sorted order so results are deterministic;
+		// array order (e.g., ItemList members) is preserved.
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if !strings.HasPrefix(key, "@") {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := v[key]
+			switch child.(type) {
+			case map[string]any, []any:
+				items = append(items, collectJSONLD(child, depth+1)...)
+			}
+		}
+	}
+
+	return items
+}
+
+// jsonLDTypes normalizes an @type value (string or array) to a list of type names.
+func jsonLDTypes(value any) []string {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = append(types, trimVocabulary(v))
+	case []any:
+		for _, elem := range v {
+			if s, ok := elem.(string); ok {
+				types = append(types, trimVocabulary(s))
+			}
+		}
+	}
+	return types
+}
+
+// trimVocabulary strips schema.org URL prefixes from type names
+// (e.g., "https://schema.org/Product" -> "Product").
+func trimVocabulary(s string) string {
+	s = strings.TrimSpace(s)
+	if idx := strings.LastIndexAny(s, "/:"); idx >= 0 && idx < len(s)-1 {
+		return s[idx+1:]
+	}
+	return s
+}
+
+// unescapeStrings decodes HTML entities in all string values of a JSON value.
+// Many sites HTML-escape their JSON-LD ("Fish &amp; Chips").
+func unescapeStrings(value any) any {
+	switch v := value.(type) {
+	case string:
+		return html.UnescapeString(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			out[i] = unescapeStrings(elem)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, elem := range v {
+			out[key] = unescapeStrings(elem)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// ========================================
+// Microdata
+// ========================================
+
+// parseMicrodata extracts itemscope/itemprop entities.
+func parseMicrodata(root *goquery.Document) []Item {
+	var items []Item
+
+	root.Find("[itemscope]").Each(func(_ int, sel *goquery.Selection) {
+		types := microdataTypes(sel)
+		props := microdataProperties(sel)
+		if len(types) == 0 && len(props) == 0 {
+			return
+		}
+
+		items = append(items, Item{
+			Source:     SourceMicrodata,
+			Types:      types,
+			Properties: props,
+			Depth:      sel.ParentsFiltered("[itemscope]").Length(),
+		})
+	})
+
+	return items
+}
+
+// microdataTypes returns the item types declared by an itemscope element.
+func microdataTypes(sel *goquery.Selection) []string {
+	var types []string
+	for _, t := range strings.Fields(sel.AttrOr("itemtype", "")) {
+		types = append(types, trimVocabulary(t))
+	}
+	return types
+}
+
+// microdataProperties collects the properties belonging to an itemscope element.
+// Properties of nested scopes are attached to the nested entity, not the parent.
+func microdataProperties(scope *goquery.Selection) map[string]any {
+	props := make(map[string]any)
+	scopeNode := scope.Get(0)
+
+	scope.Find("[itemprop]").Each(func(_ int, prop *goquery.Selection) {
+		// Only include properties whose closest enclosing scope is this element
+		owner := prop.ParentsFiltered("[itemscope]").First()
+		if owner.Length() == 0 || owner.Get(0) != scopeNode {
+			return
+		}
+
+		var value any
+		if _, nested := prop.Attr("itemscope"); nested {
+			nestedProps := microdataProperties(prop)
+			if types := microdataTypes(prop); len(types) > 0 {
+				nestedProps["@type"] = types[0]
+			}
+			value = nestedProps
+		} else {
+			value = microdataValue(prop)
+		}
+
+		for _, name := range strings.Fields(prop.AttrOr("itemprop", "")) {
+			addProperty(props, name, value)
+		}
+	})
+
+	return props
+}
+
+// microdataValue returns the value of a non-scope itemprop element,
+// following the HTML microdata value rules.
+func microdataValue(sel *goquery.Selection) string {
+	if content, ok := sel.Attr("content"); ok {
+		return strings.TrimSpace(content)
+	}
+
+	switch goquery.NodeName(sel) {
+	case "a", "link", "area":
+		return strings.TrimSpace(sel.AttrOr("href", ""))
+	case "img", "audio", "video", "source", "embed", "iframe", "track":
+		return strings.TrimSpace(sel.AttrOr("src", ""))
+	case "object":
+		return strings.TrimSpace(sel.AttrOr("data", ""))
+	case "data", "meter":
+		return strings.TrimSpace(sel.AttrOr("value", ""))
+	case "time":
+		if dt, ok := sel.Attr("datetime"); ok {
+			return strings.TrimSpace(dt)
+		}
+	}
+
+	return strings.Join(strings.Fields(sel.Text()), " ")
+}
+
+// addProperty sets a property value, converting repeated properties to arrays.
+func addProperty(props map[string]any, name string, value any) {
+	existing, ok := props[name]
+	if !ok {
+		props[name] = value
+		return
+	}
+	if arr, isArr := existing.([]any); isArr {
+		props[name] = append(arr, value)
+		return
+	}
+	props[name] = []any{existing, value}
+}
+
+// ========================================
+// OpenGraph
+// ========================================
+
+// openGraphProperties maps OpenGraph meta properties to schema.org property
+// names so that the same field matching rules apply to all sources.
+var openGraphProperties = map[string]string{
+	"og:title":                 "name",
+	"og:description":           "description",
+	"og:image":                 "image",
+	"og:url":                   "url",
+	"og:site_name":             "siteName",
+	"og:locale":                "inLanguage",
+	"og:price:amount":          "price",
+	"og:price:currency":        "priceCurrency",
+	"og:availability":          "availability",
+	"product:price:amount":     "price",
+	"product:price:currency":   "priceCurrency",
+	"product:availability":     "availability",
+	"product:brand":            "brand",
+	"product:condition":        "itemCondition",
+	"product:retailer_item_id": "sku",
+	"article:published_time":   "datePublished",
+	"article:modified_time":    "dateModified",
+	"article:author":           "author",
+	"article:section":          "articleSection",
+	"article:tag":              "keywords",
+}
+
+// parseOpenGraph extracts OpenGraph (and product/article extension) meta tags
+// as a single top-level item.
+func parseOpenGraph(root *goquery.Document) *Item {
+	props := make(map[string]any)
+	var ogType string
+
+	root.Find("meta[property], meta[name]").Each(func(_ int, sel *goquery.Selection) {
+		key := strings.ToLower(strings.TrimSpace(sel.AttrOr("property", sel.AttrOr("name", ""))))
+		content := strings.TrimSpace(sel.AttrOr("content", ""))
+		if key == "" || content == "" {
+			return
+		}
+
+		if key == "og:type" {
+			ogType = content
+			return
+		}
+
+		name, ok := openGraphProperties[key]
+		if !ok {
+			return
+		}
+		value := html.UnescapeString(content)
+
+		// Keep the first value for scalar properties; tags and authors may repeat
+		if name == "keywords" || name == "author" {
+			addProperty(props, name, value)
+			return
+		}
+		if _, exists := props[name]; !exists {
+			props[name] = value
+		}
+	})
+
+	if len(props) == 0 {
+		return nil
+	}
+
+	item := &Item{
+		Source:     SourceOpenGraph,
+		Properties: props,
+	}
+	if ogType != "" {
+		item.Types = []string{trimVocabulary(ogType)}
+	}
+	return item
+}
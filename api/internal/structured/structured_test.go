@@ -0,0 +1,409 @@
+package structured
+
+import (
+	"testing"
+
+	"github.com/jmylchreest/refyne/pkg/schema"
+)
+
+// ========================================
+// Parse Tests
+// ========================================
+
+const productJSONLD = `<html><head>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@type": "Product",
+  "name": "Fish &amp; Chips Fryer",
+  "description": "A deep fryer.",
+  "image": ["https://example.com/fryer.jpg", "https://example.com/fryer-2.jpg"],
+  "sku": "FRY-100",
+  "brand": {"@type": "Brand", "name": "Acme"},
+  "offers": {"@type": "Offer", "price": "1,299.99", "priceCurrency": "GBP", "availability": "https://schema.org/InStock"},
+  "aggregateRating": {"@type": "AggregateRating", "ratingValue": "4.5", "reviewCount": "87"},
+  "review": [
+    {"@type": "Review", "author": {"@type": "Person", "name": "Sam"}, "reviewBody": "Great.", "reviewRating": {"@type": "Rating", "ratingValue": 5}},
+    {"@type": "Review", "author": {"@type": "Person", "name": "Alex"}, "reviewBody": "Fine.", "reviewRating": {"@type": "Rating", "ratingValue": 3}}
+  ]
+}
+</script>
+<meta property="og:title" content="Fryer | Example Shop">
+<meta property="og:site_name" content="Example Shop">
+</head><body></body></html>`
+
+func TestParse_JSONLD(t *testing.T) {
+	doc := Parse(productJSONLD)
+
+	if doc.IsEmpty() {
+		t.Fatal("expected structured data items")
+	}
+
+	var product *Item
+	for i := range doc.Items {
+		if doc.Items[i].HasType("Product") {
+			product = &doc.Items[i]
+			break
+		}
+	}
+	if product == nil {
+		t.Fatal("expected Product item")
+	}
+	if product.Source != SourceJSONLD {
+		t.Errorf("Source = %q, want %q", product.Source, SourceJSONLD)
+	}
+	if product.Depth != 0 {
+		t.Errorf("Depth = %d, want 0", product.Depth)
+	}
+	if got := product.Properties["name"]; got != "Fish & Chips Fryer" {
+		t.Errorf("name = %v, want HTML entities decoded", got)
+	}
+
+	reviews := 0
+	for _, item := range doc.Items {
+		if item.HasType("Review") {
+			reviews++
+			if item.Depth != 1 {
+				t.Errorf("Review depth = %d, want 1", item.Depth)
+			}
+		}
+	}
+	if reviews != 2 {
+		t.Errorf("found %d Review items, want 2", reviews)
+	}
+
+	sources := doc.Sources()
+	if len(sources) != 2 || sources[0] != SourceJSONLD || sources[1] != SourceOpenGraph {
+		t.Errorf("Sources() = %v, want [json-ld opengraph]", sources)
+	}
+}
+
+func TestParse_JSONLDGraph(t *testing.T) {
+	html := `<script type="application/ld+json">
+	{"@context": "https://schema.org", "@graph": [
+		{"@type": "WebSite", "name": "Example"},
+		{"@type": "https://schema.org/Article", "headline": "Hello"}
+	]}
+	</script>`
+
+	doc := Parse(html)
+
+	found := map[string]bool{}
+	for _, item := range doc.Items {
+		for _, typ := range item.Types {
+			found[typ] = true
+		}
+	}
+	if !found["WebSite"] || !found["Article"] {
+		t.Errorf("expected WebSite and Article from @graph, got %v", found)
+	}
+}
+
+func TestParse_MalformedJSONLD(t *testing.T) {
+	html := `<script type="application/ld+json">{not json</script>
+	<script type="application/ld+json">{"@type": "Thing", "name": "ok"}</script>`
+
+	doc := Parse(html)
+	if len(doc.Items) != 1 {
+		t.Fatalf("expected 1 item (malformed block skipped), got %d", len(doc.Items))
+	}
+}
+
+func TestParse_Microdata(t *testing.T) {
+	html := `<div itemscope itemtype="https://schema.org/Product">
+		<h1 itemprop="name">Widget</h1>
+		<img itemprop="image" src="/widget.png">
+		<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+			<meta itemprop="priceCurrency" content="USD">
+			<span itemprop="price" content="19.99">$19.99</span>
+		</div>
+	</div>`
+
+	doc := Parse(html)
+
+	var product, offer *Item
+	for i := range doc.Items {
+		switch {
+		case doc.Items[i].HasType("Product"):
+			product = &doc.Items[i]
+		case doc.Items[i].HasType("Offer"):
+			offer = &doc.Items[i]
+		}
+	}
+	if product == nil || offer == nil {
+		t.Fatalf("expected Product and Offer items, got %+v", doc.Items)
+	}
+
+	if product.Properties["name"] != "Widget" {
+		t.Errorf("name = %v, want Widget", product.Properties["name"])
+	}
+	if product.Properties["image"] != "/widget.png" {
+		t.Errorf("image = %v, want /widget.png", product.Properties["image"])
+	}
+	if _, ok := product.Properties["price"]; ok {
+		t.Error("nested offer properties should not leak into the product")
+	}
+	offers, ok := product.Properties["offers"].(map[string]any)
+	if !ok || offers["price"] != "19.99" {
+		t.Errorf("offers = %v, want nested map with price 19.99", product.Properties["offers"])
+	}
+	if offer.Depth != 1 {
+		t.Errorf("offer depth = %d, want 1", offer.Depth)
+	}
+}
+
+func TestParse_Empty(t *testing.T) {
+	if doc := Parse(""); !doc.IsEmpty() {
+		t.Error("expected empty document for empty input")
+	}
+	if doc := Parse("<html><body><p>No data here</p></body></html>"); !doc.IsEmpty() {
+		t.Errorf("expected empty document, got %+v", doc.Items)
+	}
+}
+
+// ========================================
+// Map Tests
+// ========================================
+
+func TestMap_ProductFullCoverage(t *testing.T) {
+	doc := Parse(productJSONLD)
+	s := schema.Schema{
+		Name: "Product",
+		Fields: []schema.Field{
+			{Name: "title", Type: "string", Required: true},
+			{Name: "price", Type: "number", Required: true},
+			{Name: "currency", Type: "string"},
+			{Name: "image_url", Type: "string"},
+			{Name: "brand", Type: "string"},
+			{Name: "rating", Type: "number"},
+			{Name: "review_count", Type: "integer"},
+			{Name: "in_stock", Type: "boolean"},
+		},
+	}
+
+	result := Map(doc, s)
+
+	if result.Coverage != 1 {
+		t.Errorf("Coverage = %v, want 1 (missing %v)", result.Coverage, result.Missing)
+	}
+	if !result.RequiredSatisfied {
+		t.Error("RequiredSatisfied = false, want true")
+	}
+
+	want := map[string]any{
+		"title":        "Fish & Chips Fryer",
+		"price":        1299.99,
+		"currency":     "GBP",
+		"image_url":    "https://example.com/fryer.jpg",
+		"brand":        "Acme",
+		"rating":       4.5,
+		"review_count": int64(87),
+		"in_stock":     true,
+	}
+	for key, wantVal := range want {
+		if got := result.Data[key]; got != wantVal {
+			t.Errorf("Data[%q] = %v (%T), want %v (%T)", key, got, got, wantVal, wantVal)
+		}
+	}
+
+	if len(result.Sources) != 1 || result.Sources[0] != SourceJSONLD {
+		t.Errorf("Sources = %v, want [json-ld]", result.Sources)
+	}
+}
+
+func TestMap_PartialCoverage(t *testing.T) {
+	doc := Parse(productJSONLD)
+	s := schema.Schema{
+		Fields: []schema.Field{
+			{Name: "name", Type: "string"},
+			{Name: "sku", Type: "string"},
+			{Name: "warranty_years", Type: "integer", Required: true},
+			{Name: "dimensions", Type: "string"},
+		},
+	}
+
+	result := Map(doc, s)
+
+	if result.Coverage != 0.5 {
+		t.Errorf("Coverage = %v, want 0.5", result.Coverage)
+	}
+	if result.RequiredSatisfied {
+		t.Error("RequiredSatisfied = true, want false")
+	}
+	if len(result.Missing) != 2 || result.Missing[0] != "warranty_years" || result.Missing[1] != "dimensions" {
+		t.Errorf("Missing = %v, want [warranty_years dimensions]", result.Missing)
+	}
+}
+
+func TestMap_NestedCollection(t *testing.T) {
+	doc := Parse(productJSONLD)
+	s := schema.Schema{
+		Fields: []schema.Field{
+			{Name: "reviews", Type: "array", Items: &schema.Field{
+				Type: "object",
+				Properties: []schema.Field{
+					{Name: "author", Type: "string"},
+					{Name: "body", Type: "string"},
+					{Name: "rating", Type: "number"},
+				},
+			}},
+		},
+	}
+
+	result := Map(doc, s)
+
+	reviews, ok := result.Data["reviews"].([]any)
+	if !ok || len(reviews) != 2 {
+		t.Fatalf("reviews = %v, want 2 items", result.Data["reviews"])
+	}
+	first := reviews[0].(map[string]any)
+	if first["author"] != "Sam" || first["body"] != "Great." || first["rating"] != 5.0 {
+		t.Errorf("first review = %v", first)
+	}
+}
+
+func TestMap_ItemList(t *testing.T) {
+	html := `<script type="application/ld+json">
+	{"@context": "https://schema.org", "@type": "ItemList", "itemListElement": [
+		{"@type": "ListItem", "position": 1, "item": {"@type": "Product", "name": "A", "url": "/a", "offers": {"price": "10"}}},
+		{"@type": "ListItem", "position": 2, "item": {"@type": "Product", "name": "B", "url": "/b", "offers": {"price": "20"}}},
+		{"@type": "ListItem", "position": 3, "item": {"@type": "Product", "name": "C", "url": "/c", "offers": {"price": "30"}}}
+	]}
+	</script>
+	<script type="application/ld+json">{"@type": "BreadcrumbList", "itemListElement": [
+		{"@type": "ListItem", "position": 1, "name": "Home"}
+	]}</script>`
+
+	s := schema.Schema{
+		Fields: []schema.Field{
+			{Name: "products", Type: "array", Items: &schema.Field{
+				Type: "object",
+				Properties: []schema.Field{
+					{Name: "name", Type: "string"},
+					{Name: "url", Type: "string"},
+					{Name: "price", Type: "number"},
+				},
+			}},
+		},
+	}
+
+	result := Map(Parse(html), s)
+
+	products, ok := result.Data["products"].([]any)
+	if !ok || len(products) != 3 {
+		t.Fatalf("products = %v, want 3 items", result.Data["products"])
+	}
+	for i, name := range []string{"A", "B", "C"} {
+		p := products[i].(map[string]any)
+		if p["name"] != name {
+			t.Errorf("products[%d].name = %v, want %s (order must be preserved)", i, p["name"], name)
+		}
+	}
+	if products[1].(map[string]any)["price"] != 20.0 {
+		t.Errorf("products[1].price = %v, want 20", products[1].(map[string]any)["price"])
+	}
+}
+
+func TestMap_OpenGraphFallback(t *testing.T) {
+	html := `<head>
+		<meta property="og:type" content="article">
+		<meta property="og:title" content="Hello World">
+		<meta property="og:description" content="An article">
+		<meta property="article:published_time" content="2026-01-02T10:00:00Z">
+	</head>`
+
+	s := schema.Schema{
+		Fields: []schema.Field{
+			{Name: "title", Type: "string"},
+			{Name: "summary", Type: "string"},
+			{Name: "published_at", Type: "string"},
+		},
+	}
+
+	result := Map(Parse(html), s)
+
+	if result.Coverage != 1 {
+		t.Errorf("Coverage = %v, want 1 (missing %v)", result.Coverage, result.Missing)
+	}
+	if result.Data["published_at"] != "2026-01-02T10:00:00Z" {
+		t.Errorf("published_at = %v", result.Data["published_at"])
+	}
+}
+
+func TestMap_EmptyDocument(t *testing.T) {
+	s := schema.Schema{Fields: []schema.Field{{Name: "title", Type: "string"}}}
+
+	result := Map(&Document{}, s)
+
+	if result.Coverage != 0 {
+		t.Errorf("Coverage = %v, want 0", result.Coverage)
+	}
+	if len(result.Missing) != 1 {
+		t.Errorf("Missing = %v, want [title]", result.Missing)
+	}
+}
+
+func TestToNumber(t *testing.T) {
+	tests := []struct {
+		input any
+		want  float64
+		ok    bool
+	}{
+		{12.5, 12.5, true},
+		{"£12.99", 12.99, true},
+		{"$1,299.99", 1299.99, true},
+		{"12,50 €", 12.5, true},
+		{"1.234,56", 1234.56, true},
+		{"1 299,00 kr", 1299, true},
+		{"4.5 out of 5", 4.5, true},
+		{"1,234", 1234, true},
+		{"-3", -3, true},
+		{[]any{"7"}, 7, true},
+		{map[string]any{"@value": "9.5"}, 9.5, true},
+		{"free", 0, false},
+		{nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := toNumber(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("toNumber(%v) = (%v, %v), want (%v, %v)", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestToString_Address(t *testing.T) {
+	addr := map[string]any{
+		"@type":           "PostalAddress",
+		"addressCountry":  map[string]any{"@type": "Country", "name": "GB"},
+		"addressLocality": "London",
+		"streetAddress":   "1 High St",
+	}
+
+	got, ok := toString(addr, false)
+	if !ok || got != "1 High St, London, GB" {
+		t.Errorf("toString(address) = %q, want %q", got, "1 High St, London, GB")
+	}
+}
+
+func TestSubschema(t *testing.T) {
+	s := schema.Schema{
+		Name:        "Product",
+		Description: "A product",
+		Fields: []schema.Field{
+			{Name: "title"}, {Name: "price"}, {Name: "color"},
+		},
+	}
+
+	sub := Subschema(s, []string{"color", "title"})
+
+	if sub.Name != "Product" || sub.Description != "A product" {
+		t.Error("Subschema should keep name and description")
+	}
+	if len(sub.Fields) != 2 || sub.Fields[0].Name != "title" || sub.Fields[1].Name != "color" {
+		t.Errorf("Fields = %v, want [title color] in schema order", sub.Fields)
+	}
+	if len(s.Fields) != 3 {
+		t.Error("Subschema must not modify the original schema")
+	}
+}
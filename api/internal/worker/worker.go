@@ -10,9 +10,11 @@ import (
 	"time"
 
 	"github.com/oklog/ulid/v2"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/jmylchreest/refyne-api/internal/constants"
 	"github.com/jmylchreest/refyne-api/internal/models"
+	"github.com/jmylchreest/refyne-api/internal/observability"
 	"github.com/jmylchreest/refyne-api/internal/repository"
 	"github.com/jmylchreest/refyne-api/internal/service"
 )
@@ -102,6 +104,11 @@ func (w *Worker) Start(ctx context.Context) {
 	}
 }
 
+// Concurrency returns the number of jobs the worker processes at once.
+func (w *Worker) Concurrency() int {
+	return w.concurrency
+}
+
 // ActiveJobs returns the number of jobs currently being processed.
 func (w *Worker) ActiveJobs() int64 {
 	w.activeJobsMu.Lock()
@@ -208,6 +215,26 @@ func (w *Worker) processNextJob(ctx context.Context, workerID int) bool {
 
 	w.logger.Info("processing job", "worker_id", workerID, "job_id", job.ID, "type", job.Type)
 
+	// Continue the trace of the request that created the job
+	ctx = observability.ContextWithTraceParent(ctx, job.TraceParent)
+	ctx, span := observability.StartSpan(ctx, "worker.process"+jobSpanSuffix(job.Type),
+		attribute.String("job.id", job.ID),
+		attribute.String("job.type", string(job.Type)),
+		attribute.String("job.tier", job.Tier),
+		attribute.Int("worker.id", workerID),
+	)
+	defer func() {
+		span.SetAttributes(
+			attribute.String("job.status", string(job.Status)),
+			attribute.Int("job.page_count", job.PageCount),
+		)
+		var err error
+		if job.Status == models.JobStatusFailed {
+			err = fmt.Errorf("job failed: %s", job.ErrorMessage)
+		}
+		observability.EndSpan(span, err)
+	}()
+
 	// Process based on job type
 	switch job.Type {
 	case models.JobTypeExtract:
@@ -221,6 +248,18 @@ func (w *Worker) processNextJob(ctx context.Context, workerID int) bool {
 	return true
 }
 
+// jobSpanSuffix names a job's worker span after the method that processes it.
+func jobSpanSuffix(jobType models.JobType) string {
+	switch jobType {
+	case models.JobTypeExtract:
+		return "ExtractJob"
+	case models.JobTypeCrawl:
+		return "CrawlJob"
+	default:
+		return "Job"
+	}
+}
+
 func (w *Worker) processExtractJob(ctx context.Context, job *models.Job) {
 	result, err := w.extractionSvc.Extract(ctx, job.UserID, service.ExtractInput{
 		URL:       job.URL,
@@ -268,6 +307,7 @@ func (w *Worker) processExtractJob(ctx context.Context, job *models.Job) {
 				Payload: service.LLMRequestPayload{
 					Schema:      job.SchemaJSON,
 					PageContent: result.RawContent,
+					Hints:       result.Hints,
 				},
 			},
 			Response: service.LLMResponseSection{
@@ -461,6 +501,7 @@ func (w *Worker) processCrawlJob(ctx context.Context, job *models.Job) {
 					Payload: service.LLMRequestPayload{
 						Schema:      job.SchemaJSON,
 						PageContent: pageResult.RawContent,
+						Hints:       pageResult.Hints,
 					},
 				},
 				Response: service.LLMResponseSection{
@@ -475,6 +516,8 @@ func (w *Worker) processCrawlJob(ctx context.Context, job *models.Job) {
 						RawOutput: pageResult.RawLLMResponse,
 					},
 				},
+				HAR:         service.BuildHAR(pageResult.URL, now, pageResult.Network),
+				Screenshots: service.NewDebugScreenshots(pageResult.Screenshots),
 			}
 			capturesMu.Lock()
 			debugCaptures = append(debugCaptures, capture)
@@ -508,14 +551,15 @@ func (w *Worker) processCrawlJob(ctx context.Context, job *models.Job) {
 	}
 
 	result, err := w.extractionSvc.CrawlWithCallback(ctx, job.UserID, service.CrawlInput{
-		JobID:        job.ID,
-		URL:          job.URL,
-		SeedURLs:     sitemapURLs, // URLs from sitemap discovery (empty if not using sitemap)
-		Schema:       json.RawMessage(job.SchemaJSON),
-		LLMConfigs:   llmConfigs,  // Pre-resolved config chain from job creation
-		Tier:         job.Tier,    // User's tier at job creation time
-		IsBYOK:       job.IsBYOK,  // Whether using user's own API keys
-		CleanerChain: options.CleanerChain, // Content cleaner chain from job creation
+		JobID:         job.ID,
+		URL:           job.URL,
+		SeedURLs:      sitemapURLs, // URLs from sitemap discovery (empty if not using sitemap)
+		Schema:        json.RawMessage(job.SchemaJSON),
+		LLMConfigs:    llmConfigs,            // Pre-resolved config chain from job creation
+		Tier:          job.Tier,              // User's tier at job creation time
+		IsBYOK:        job.IsBYOK,            // Whether using user's own API keys
+		CleanerChain:  options.CleanerChain,  // Content cleaner chain from job creation
+		Preprocessors: options.Preprocessors, // Preprocessor chain from job creation
 		Options: service.CrawlOptions{
 			FollowSelector:        options.FollowSelector,
 			FollowPattern:         options.FollowPattern,
@@ -529,8 +573,17 @@ func (w *Worker) processCrawlJob(ctx context.Context, job *models.Job) {
 			ExtractFromSeeds:      options.ExtractFromSeeds,
 			UseSitemap:            options.UseSitemap,
 			FetchMode:             options.FetchMode,
+			StructuredData:        options.StructuredData,
+			Actions:               options.Actions,
+			CaptureNetwork:        options.CaptureNetwork,
+			FetchOptions:          options.FetchOptions,
+			AuthProfileID:         options.AuthProfileID,
+			InputMode:             options.InputMode,
 			ContentDynamicAllowed: options.ContentDynamicAllowed,
 			SkipCreditCheck:       options.SkipCreditCheck,
+			APIKeyID:              options.APIKeyID,
+			RoutingPolicy:         options.RoutingPolicy,
+			RoutingReason:         options.RoutingReason,
 		},
 	}, service.CrawlCallbacks{
 		OnResult:     resultCallback,
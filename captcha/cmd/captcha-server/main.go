@@ -23,6 +23,7 @@ import (
 	"github.com/jmylchreest/refyne-api/captcha/internal/browser"
 	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
 	"github.com/jmylchreest/refyne-api/captcha/internal/config"
+	"github.com/jmylchreest/refyne-api/captcha/internal/fingerprint"
 	"github.com/jmylchreest/refyne-api/captcha/internal/http/mw"
 	"github.com/jmylchreest/refyne-api/captcha/internal/logging"
 	"github.com/jmylchreest/refyne-api/captcha/internal/models"
@@ -63,6 +64,14 @@ func main() {
 		logger.Info("stealth mode enabled")
 	}
 
+	if _, ok := fingerprint.Get(cfg.FingerprintProfile); !ok {
+		logger.Warn("unknown fingerprint profile, using default",
+			"profile", cfg.FingerprintProfile,
+			"default", fingerprint.Default,
+		)
+		cfg.FingerprintProfile = fingerprint.Default
+	}
+
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -70,6 +79,7 @@ func main() {
 	// Initialize browser pool (does not block - warmup is async)
 	pool := browser.NewPool(cfg, logger)
 	defer pool.Close()
+	go pool.StartAutoscaler(ctx)
 
 	// Initialize session manager
 	sessions := session.NewManager(cfg, logger)
@@ -87,7 +97,8 @@ func main() {
 	// Initialize challenge detector
 	detector := challenge.NewDetector(logger)
 
-	// Initialize solver chain - wait solver first (handles auto-resolving challenges)
+	// Initialize solver chain - the chain tries the cheapest expected cost first,
+	// so the free wait solver always goes ahead of paid solvers
 	solvers := make([]solver.Solver, 0)
 
 	// Wait solver handles Cloudflare JS challenges that auto-resolve
@@ -101,8 +112,13 @@ func main() {
 	}
 
 	if cfg.CapSolverAPIKey != "" {
-		logger.Info("capsolver enabled (not yet implemented)")
-		// TODO: Add CapSolver implementation
+		logger.Info("capsolver solver enabled")
+		solvers = append(solvers, solver.NewCapSolver(cfg.CapSolverAPIKey))
+	}
+
+	if cfg.AntiCaptchaAPIKey != "" {
+		logger.Info("anti-captcha solver enabled")
+		solvers = append(solvers, solver.NewAntiCaptcha(cfg.AntiCaptchaAPIKey))
 	}
 
 	solverChain := solver.NewChain(solvers...)
@@ -0,0 +1,326 @@
+// Package actions runs scripted browser interactions (click, scroll, type, ...) before page capture.
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+// Limits on scripted actions, so a request can't hold a browser indefinitely.
+const (
+	MaxActions        = 25
+	MaxRepeat         = 50
+	MaxDelay          = 10 * time.Second
+	MaxTimeout        = 30 * time.Second
+	DefaultTimeout    = 10 * time.Second
+	DefaultRepeatWait = time.Second // Wait between repetitions when no delay is given
+)
+
+// Snippets are the JavaScript snippets evaluate actions may run, by name.
+// Arbitrary JavaScript is not accepted.
+var Snippets = map[string]string{
+	// Remove fixed overlays (modals, newsletter popups) and restore page scrolling
+	"remove_overlays": `() => {
+		for (const el of document.querySelectorAll('body *')) {
+			const style = getComputedStyle(el);
+			if ((style.position === 'fixed' || style.position === 'sticky') && parseInt(style.zIndex || '0', 10) >= 100) {
+				el.remove();
+			}
+		}
+		document.documentElement.style.overflow = 'auto';
+		document.body.style.overflow = 'auto';
+	}`,
+	// Open all <details> elements so collapsed content is captured
+	"expand_details": `() => {
+		for (const el of document.querySelectorAll('details')) {
+			el.open = true;
+		}
+	}`,
+	// Load lazy images by copying data-src attributes into src
+	"load_lazy_images": `() => {
+		for (const img of document.querySelectorAll('img[data-src], img[data-lazy-src]')) {
+			img.src = img.dataset.src || img.dataset.lazySrc;
+		}
+		for (const img of document.querySelectorAll('img[loading="lazy"]')) {
+			img.loading = 'eager';
+		}
+	}`,
+	// Expand elements hidden behind "aria-expanded=false" toggles (accordions, tabs)
+	"expand_accordions": `() => {
+		for (const el of document.querySelectorAll('[aria-expanded="false"]')) {
+			el.click();
+		}
+	}`,
+}
+
+// SnippetNames returns the names of the safe-listed snippets, sorted.
+func SnippetNames() []string {
+	names := make([]string, 0, len(Snippets))
+	for name := range Snippets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that actions are well-formed and within limits.
+func Validate(actions []models.Action) error {
+	if len(actions) > MaxActions {
+		return fmt.Errorf("too many actions: %d (max %d)", len(actions), MaxActions)
+	}
+	for i, a := range actions {
+		if err := validateAction(a); err != nil {
+			return fmt.Errorf("action %d (%s): %w", i+1, a.Type, err)
+		}
+	}
+	return nil
+}
+
+func validateAction(a models.Action) error {
+	switch a.Type {
+	case models.ActionClick, models.ActionHover:
+		if a.Selector == "" {
+			return fmt.Errorf("selector is required")
+		}
+	case models.ActionType:
+		if a.Selector == "" {
+			return fmt.Errorf("selector is required")
+		}
+		if a.Text == "" {
+			return fmt.Errorf("text is required")
+		}
+	case models.ActionSelect:
+		if a.Selector == "" {
+			return fmt.Errorf("selector is required")
+		}
+		if a.Text == "" {
+			return fmt.Errorf("text (the option to choose) is required")
+		}
+	case models.ActionWait:
+		if a.Selector == "" && a.Delay <= 0 {
+			return fmt.Errorf("selector or delay is required")
+		}
+	case models.ActionScrollToBottom:
+	case models.ActionEvaluate:
+		if _, ok := Snippets[a.Script]; !ok {
+			return fmt.Errorf("unknown script %q (allowed: %v)", a.Script, SnippetNames())
+		}
+	default:
+		return fmt.Errorf("unknown action type")
+	}
+
+	if a.Repeat < 0 || a.Repeat > MaxRepeat {
+		return fmt.Errorf("repeat must be between 0 and %d", MaxRepeat)
+	}
+	if a.Delay < 0 || time.Duration(a.Delay)*time.Millisecond > MaxDelay {
+		return fmt.Errorf("delay must be between 0 and %d ms", MaxDelay.Milliseconds())
+	}
+	if a.Timeout < 0 || time.Duration(a.Timeout)*time.Millisecond > MaxTimeout {
+		return fmt.Errorf("timeout must be between 0 and %d ms", MaxTimeout.Milliseconds())
+	}
+	return nil
+}
+
+// Runner executes browser actions on a page.
+type Runner struct {
+	logger *slog.Logger
+}
+
+// NewRunner creates a new action runner.
+func NewRunner(logger *slog.Logger) *Runner {
+	return &Runner{logger: logger}
+}
+
+// Run executes actions in order. Actions marked optional are skipped when they fail;
+// any other failure stops the run and is returned.
+func (r *Runner) Run(ctx context.Context, page *rod.Page, actions []models.Action) error {
+	for i, a := range actions {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := r.run(ctx, page, a); err != nil {
+			if a.Optional {
+				r.logger.Debug("optional action failed", "index", i+1, "type", a.Type, "selector", a.Selector, "error", err)
+				continue
+			}
+			return fmt.Errorf("action %d (%s) failed: %w", i+1, a.Type, err)
+		}
+		r.logger.Debug("action completed", "index", i+1, "type", a.Type, "selector", a.Selector)
+	}
+	return nil
+}
+
+func (r *Runner) run(ctx context.Context, page *rod.Page, a models.Action) error {
+	page = page.Context(ctx)
+
+	switch a.Type {
+	case models.ActionClick:
+		return r.click(ctx, page, a)
+	case models.ActionScrollToBottom:
+		return r.scrollToBottom(ctx, page, a)
+	case models.ActionType:
+		el, err := findElement(page, a)
+		if err != nil {
+			return err
+		}
+		if err := el.Input(a.Text); err != nil {
+			return err
+		}
+	case models.ActionSelect:
+		el, err := findElement(page, a)
+		if err != nil {
+			return err
+		}
+		if err := el.Select([]string{a.Text}, true, rod.SelectorTypeText); err != nil {
+			return err
+		}
+	case models.ActionHover:
+		el, err := findElement(page, a)
+		if err != nil {
+			return err
+		}
+		if err := el.Hover(); err != nil {
+			return err
+		}
+	case models.ActionWait:
+		if a.Selector != "" {
+			if _, err := findElement(page, a); err != nil {
+				return err
+			}
+		}
+	case models.ActionEvaluate:
+		script, ok := Snippets[a.Script]
+		if !ok {
+			return fmt.Errorf("unknown script %q", a.Script)
+		}
+		if _, err := page.Eval(script); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown action type")
+	}
+
+	return sleep(ctx, time.Duration(a.Delay)*time.Millisecond)
+}
+
+// click clicks the element, and with repeat keeps clicking while it is present
+// (e.g., a "Load more" button that disappears when everything is loaded). Each click
+// is followed by one wait, so the delay between repeated clicks is also the delay
+// after the last one.
+func (r *Runner) click(ctx context.Context, page *rod.Page, a models.Action) error {
+	el, err := findElement(page, a)
+	if err != nil {
+		return err
+	}
+
+	clicks := max(a.Repeat, 1)
+	wait := time.Duration(a.Delay) * time.Millisecond
+	if clicks > 1 {
+		wait = repeatWait(a)
+	}
+	return repeat(ctx, clicks, wait, func(i int) (bool, error) {
+		if i > 0 {
+			has, next, err := page.Has(a.Selector)
+			if err != nil {
+				return false, err
+			}
+			if !has {
+				r.logger.Debug("click target gone", "selector", a.Selector, "clicks", i)
+				return false, nil
+			}
+			el = next
+		}
+		if err := el.ScrollIntoView(); err != nil {
+			return false, err
+		}
+		if err := el.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			if i > 0 {
+				return false, nil // Element was removed or hidden mid-click
+			}
+			return false, err
+		}
+		return true, nil
+	})
+}
+
+// scrollToBottom scrolls to the end of the page, repeating while new content loads.
+func (r *Runner) scrollToBottom(ctx context.Context, page *rod.Page, a models.Action) error {
+	scrolls := max(a.Repeat, 1)
+	lastHeight := -1
+	for i := 0; i < scrolls; i++ {
+		res, err := page.Eval(`() => {
+			window.scrollTo(0, document.body.scrollHeight);
+			return document.body.scrollHeight;
+		}`)
+		if err != nil {
+			return err
+		}
+		if err := sleep(ctx, repeatWait(a)); err != nil {
+			return err
+		}
+
+		height := res.Value.Int()
+		if height == lastHeight {
+			r.logger.Debug("page stopped growing", "scrolls", i+1, "height", height)
+			return nil
+		}
+		lastHeight = height
+	}
+	return nil
+}
+
+// findElement waits for the action's selector, up to its timeout.
+func findElement(page *rod.Page, a models.Action) (*rod.Element, error) {
+	timeout := DefaultTimeout
+	if a.Timeout > 0 {
+		timeout = time.Duration(a.Timeout) * time.Millisecond
+	}
+	el, err := page.Timeout(timeout).Element(a.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("element %q not found: %w", a.Selector, err)
+	}
+	return el.CancelTimeout(), nil
+}
+
+// repeatWait is how long to wait between repetitions of an action.
+func repeatWait(a models.Action) time.Duration {
+	if a.Delay > 0 {
+		return time.Duration(a.Delay) * time.Millisecond
+	}
+	return DefaultRepeatWait
+}
+
+// repeat runs step up to times times, waiting after each run. It stops early when
+// step returns false, without waiting.
+func repeat(ctx context.Context, times int, wait time.Duration, step func(i int) (bool, error)) error {
+	for i := 0; i < times; i++ {
+		ok, err := step(i)
+		if err != nil || !ok {
+			return err
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
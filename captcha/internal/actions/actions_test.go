@@ -0,0 +1,121 @@
+package actions
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []models.Action
+		wantErr string
+	}{
+		{name: "no actions"},
+		{
+			name: "valid sequence",
+			actions: []models.Action{
+				{Type: models.ActionClick, Selector: "#close-modal", Optional: true},
+				{Type: models.ActionType, Selector: "input[name=postcode]", Text: "SW1A 1AA"},
+				{Type: models.ActionSelect, Selector: "select#size", Text: "Large"},
+				{Type: models.ActionHover, Selector: ".menu"},
+				{Type: models.ActionClick, Selector: "button.load-more", Repeat: 10, Delay: 500},
+				{Type: models.ActionScrollToBottom, Repeat: 5},
+				{Type: models.ActionWait, Selector: ".price", Timeout: 5000},
+				{Type: models.ActionWait, Delay: 1000},
+				{Type: models.ActionEvaluate, Script: "expand_details"},
+			},
+		},
+		{name: "unknown type", actions: []models.Action{{Type: "navigate"}}, wantErr: "unknown action type"},
+		{name: "click without selector", actions: []models.Action{{Type: models.ActionClick}}, wantErr: "selector is required"},
+		{name: "type without text", actions: []models.Action{{Type: models.ActionType, Selector: "input"}}, wantErr: "text is required"},
+		{name: "select without option", actions: []models.Action{{Type: models.ActionSelect, Selector: "select"}}, wantErr: "option to choose"},
+		{name: "empty wait", actions: []models.Action{{Type: models.ActionWait}}, wantErr: "selector or delay"},
+		{name: "arbitrary script", actions: []models.Action{{Type: models.ActionEvaluate, Script: "fetch('https://evil.example')"}}, wantErr: "unknown script"},
+		{name: "repeat too high", actions: []models.Action{{Type: models.ActionScrollToBottom, Repeat: MaxRepeat + 1}}, wantErr: "repeat"},
+		{name: "delay too long", actions: []models.Action{{Type: models.ActionWait, Delay: 60000}}, wantErr: "delay"},
+		{name: "timeout too long", actions: []models.Action{{Type: models.ActionWait, Selector: "a", Timeout: 60000}}, wantErr: "timeout"},
+		{name: "too many actions", actions: make([]models.Action, MaxActions+1), wantErr: "too many actions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.actions)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsActionIndex(t *testing.T) {
+	err := Validate([]models.Action{
+		{Type: models.ActionScrollToBottom},
+		{Type: models.ActionClick},
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "action 2 (click)") {
+		t.Errorf("Validate() error = %v, want prefix %q", err, "action 2 (click)")
+	}
+}
+
+func TestSnippetNames(t *testing.T) {
+	names := SnippetNames()
+	if len(names) != len(Snippets) {
+		t.Fatalf("SnippetNames() returned %d names, want %d", len(names), len(Snippets))
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Errorf("SnippetNames() not sorted: %v", names)
+		}
+	}
+}
+
+func TestRepeat_WaitsOncePerRun(t *testing.T) {
+	const wait = 100 * time.Millisecond
+
+	var runs []time.Duration
+	start := time.Now()
+	err := repeat(context.Background(), 3, wait, func(int) (bool, error) {
+		runs = append(runs, time.Since(start))
+		return true, nil
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("repeat() error = %v", err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("step ran %d times, want 3", len(runs))
+	}
+	if runs[1] < wait || runs[2] < 2*wait {
+		t.Errorf("steps ran at %v, want %v apart", runs, wait)
+	}
+	// The last run is followed by one wait, not a second delay on top
+	if elapsed < 3*wait || elapsed >= 4*wait {
+		t.Errorf("repeat() took %v, want between %v and %v", elapsed, 3*wait, 4*wait)
+	}
+}
+
+func TestRepeat_StopsWithoutWaiting(t *testing.T) {
+	start := time.Now()
+	runs := 0
+	err := repeat(context.Background(), 5, time.Second, func(i int) (bool, error) {
+		runs++
+		return i == 0, nil
+	})
+	if err != nil || runs != 2 {
+		t.Fatalf("repeat() = %v after %d runs, want nil after 2", err, runs)
+	}
+	if elapsed := time.Since(start); elapsed >= 2*time.Second {
+		t.Errorf("repeat() took %v, want one wait after the first run only", elapsed)
+	}
+}
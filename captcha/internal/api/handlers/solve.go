@@ -5,18 +5,26 @@ import (
 	"context"
 	"encoding/base64"
 	"log/slog"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 
+	"github.com/jmylchreest/refyne-api/captcha/internal/actions"
 	"github.com/jmylchreest/refyne-api/captcha/internal/browser"
 	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+	"github.com/jmylchreest/refyne-api/captcha/internal/clearance"
 	"github.com/jmylchreest/refyne-api/captcha/internal/config"
 	"github.com/jmylchreest/refyne-api/captcha/internal/consent"
+	"github.com/jmylchreest/refyne-api/captcha/internal/fingerprint"
 	"github.com/jmylchreest/refyne-api/captcha/internal/http/mw"
 	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+	"github.com/jmylchreest/refyne-api/captcha/internal/network"
+	"github.com/jmylchreest/refyne-api/captcha/internal/proxy"
+	"github.com/jmylchreest/refyne-api/captcha/internal/resources"
+	"github.com/jmylchreest/refyne-api/captcha/internal/screenshot"
 	"github.com/jmylchreest/refyne-api/captcha/internal/session"
 	"github.com/jmylchreest/refyne-api/captcha/internal/solver"
 	"github.com/jmylchreest/refyne-api/captcha/internal/version"
@@ -29,6 +37,9 @@ type SolveHandler struct {
 	detector       *challenge.Detector
 	solver         solver.Solver
 	consentDismiss *consent.Dismisser
+	actions        *actions.Runner
+	clearances     *clearance.Store
+	profiles       *fingerprint.Rotator
 	cfg            *config.Config
 	logger         *slog.Logger
 }
@@ -48,6 +59,9 @@ func NewSolveHandler(
 		detector:       detector,
 		solver:         solverChain,
 		consentDismiss: consent.NewDismisser(logger),
+		actions:        actions.NewRunner(logger),
+		clearances:     clearance.NewStore(cfg.ClearanceTTL),
+		profiles:       fingerprint.NewRotator(cfg.FingerprintProfile, cfg.FingerprintRotateAfter),
 		cfg:            cfg,
 		logger:         logger,
 	}
@@ -216,6 +230,51 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 	if req.URL == "" {
 		return models.NewErrorResponse("URL required", startTime, time.Now().UnixMilli(), ver, "")
 	}
+	if err := actions.Validate(req.Actions); err != nil {
+		return models.NewErrorResponse("invalid actions: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+	}
+	if req.CaptureNetwork != nil {
+		if _, err := network.NewFilter(req.CaptureNetwork); err != nil {
+			return models.NewErrorResponse("invalid captureNetwork: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+	}
+	var policy *resources.Policy
+	if req.ResourcePolicy != nil {
+		var err error
+		if policy, err = resources.Compile(req.ResourcePolicy); err != nil {
+			return models.NewErrorResponse("invalid resourcePolicy: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+	}
+	var shotOpts *screenshot.Options
+	if req.CaptureScreenshots != nil {
+		var err error
+		if shotOpts, err = screenshot.Compile(req.CaptureScreenshots); err != nil {
+			return models.NewErrorResponse("invalid captureScreenshots: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+	}
+	if req.FingerprintProfile != "" {
+		// Sessions keep the profile they were created with
+		if req.Session != "" {
+			return models.NewErrorResponse("invalid fingerprintProfile: sessions use the profile they were created with", startTime, time.Now().UnixMilli(), ver, "")
+		}
+		if req.UserAgent != "" {
+			return models.NewErrorResponse("invalid fingerprintProfile: the profile sets the user agent, so userAgent can't also be set", startTime, time.Now().UnixMilli(), ver, "")
+		}
+		if _, err := fingerprint.Lookup(req.FingerprintProfile); err != nil {
+			return models.NewErrorResponse("invalid fingerprintProfile: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+	}
+	var prx *proxy.Proxy
+	if req.Proxy != nil {
+		// Sessions keep the proxy they were created with
+		if req.Session != "" {
+			return models.NewErrorResponse("invalid proxy: sessions use the proxy they were created with", startTime, time.Now().UnixMilli(), ver, "")
+		}
+		var err error
+		if prx, err = proxy.Parse(req.Proxy); err != nil {
+			return models.NewErrorResponse("invalid proxy: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+	}
 
 	// Set timeout from request or use default
 	timeout := h.cfg.ChallengeTimeout
@@ -268,19 +327,32 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 		}
 	} else {
 		var err error
-		managedBrowser, err = h.pool.Acquire(ctx)
+		managedBrowser, err = h.pool.Acquire(ctx, poolTenant(ctx, userID))
 		if err != nil {
 			return models.NewErrorResponse("failed to acquire browser: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
 		}
 
+		// Route the page through the requested proxy in its own browser context
+		pageBrowser := managedBrowser.Browser
+		closeProxy := func() {}
+		if prx != nil {
+			pageBrowser, closeProxy, err = proxy.Open(managedBrowser.Browser, prx)
+			if err != nil {
+				h.pool.Release(managedBrowser)
+				return models.NewErrorResponse("failed to open proxy context: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+			}
+		}
+
 		// Create page (stealth mode unless disabled for testing)
-		page, err = browser.CreatePage(managedBrowser.Browser, h.cfg.DisableStealth)
+		page, err = browser.CreatePage(pageBrowser, h.cfg.DisableStealth)
 		if err != nil {
+			closeProxy()
 			h.pool.Release(managedBrowser)
 			return models.NewErrorResponse("failed to create page: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
 		}
 		cleanup = func() {
 			page.Close()
+			closeProxy()
 			h.pool.Release(managedBrowser)
 		}
 	}
@@ -293,15 +365,98 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 		}
 	}
 
+	host := ""
+	if u, err := url.Parse(req.URL); err == nil {
+		host = u.Hostname()
+	}
+
+	// Present one consistent device: the session's profile, the requested one, or the
+	// host's current one, which rotates when the host keeps challenging it
+	profileName, profileUA, rotating := "", "", false
+	if sess != nil {
+		if sess.Profile != "" {
+			profileName, profileUA = sess.Profile, sess.UserAgent
+		}
+	} else if req.FingerprintProfile != "" || (req.UserAgent == "" && !h.cfg.DisableStealth) {
+		profileName = req.FingerprintProfile
+		if profileName == "" {
+			profileName, rotating = h.profiles.Profile(host), true
+		}
+		profile, err := fingerprint.Lookup(profileName)
+		if err == nil {
+			profileUA, err = fingerprint.Apply(page, profile)
+		}
+		if err != nil {
+			return models.NewErrorResponse("failed to apply fingerprint profile: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+	}
+
+	// Reuse a clearance another request got for this host through the same exit,
+	// unless the request brings its own clearance or a different user agent
+	pageUA := req.UserAgent
+	if profileUA != "" {
+		pageUA = profileUA
+	}
+	exit := clearanceExit(sess, prx)
+	cached, ok := h.clearances.Get(host, exit)
+	if ok && (hasClearanceCookie(req.Cookies) || (pageUA != "" && pageUA != cached.UserAgent)) {
+		cached, ok = nil, false
+	}
+	userAgentOverride := req.UserAgent
+	if ok {
+		if err := h.setCookies(page, req.URL, cached.Cookies); err != nil {
+			h.logger.Warn("failed to set clearance cookies", "error", err)
+			cached = nil
+		} else {
+			if profileUA == "" {
+				userAgentOverride = cached.UserAgent
+			}
+			h.logger.Debug("using cached clearance",
+				"user_id", userID,
+				"job_id", jobID,
+				"host", host,
+				"obtained_at", cached.ObtainedAt,
+				"expires_at", cached.ExpiresAt,
+			)
+		}
+	}
+
 	// Set custom user agent if provided
-	if req.UserAgent != "" {
+	if userAgentOverride != "" {
 		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
-			UserAgent: req.UserAgent,
+			UserAgent: userAgentOverride,
 		}); err != nil {
 			h.logger.Warn("failed to set user agent", "error", err)
 		}
 	}
 
+	// Record XHR/fetch responses from the page load (must start before navigating)
+	var recorder *network.Recorder
+	if req.CaptureNetwork != nil {
+		var err error
+		recorder, err = network.Start(ctx, page, req.CaptureNetwork, h.logger)
+		if err != nil {
+			return models.NewErrorResponse("failed to start network capture: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+		defer recorder.Stop()
+	}
+
+	// Block unneeded resources (images, fonts, trackers) for the page load and actions,
+	// answering proxy authentication challenges from the same interceptor
+	var creds *proxy.Credentials
+	if prx != nil {
+		creds = prx.Credentials
+	}
+	var blocker *resources.Blocker
+	if (policy != nil && !policy.Empty()) || creds != nil {
+		var err error
+		blocker, err = resources.Start(page, policy, creds, h.logger)
+		if err != nil {
+			return models.NewErrorResponse("failed to start resource blocking: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+		defer blocker.Stop()
+	}
+
 	// Navigate to URL
 	if err := page.Navigate(req.URL); err != nil {
 		return models.NewErrorResponse("failed to navigate: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
@@ -332,13 +487,41 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 	var (
 		challengeType string
 		solverUsed    string
+		solverCost    float64
 		challenged    bool
 		solved        bool
 		resolveMethod string
 	)
 
+	// Report whether the page got past any challenge, so hosts that keep rejecting
+	// a profile are moved to another
+	if rotating {
+		defer func() {
+			if next := h.profiles.Report(host, profileName, challenged && !solved); next != "" {
+				h.logger.Info("fingerprint profile rotated",
+					"user_id", userID,
+					"job_id", jobID,
+					"host", host,
+					"from", profileName,
+					"to", next,
+				)
+			}
+		}()
+	}
+
 	// Handle challenge if detected
 	if detection.Type != challenge.TypeNone {
+		if cached != nil {
+			// The clearance no longer gets through, so don't hand it to other requests
+			h.clearances.Invalidate(host, exit)
+			cached = nil
+			h.logger.Info("cached clearance rejected",
+				"user_id", userID,
+				"job_id", jobID,
+				"host", host,
+				"type", detection.Type,
+			)
+		}
 		challenged = true
 		challengeType = string(detection.Type)
 		h.logger.Info("challenge detected",
@@ -367,6 +550,7 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 				CData:   detection.CData,
 				Page:    page,
 				Timeout: timeout,
+				MaxCost: req.MaxSolverCostUSD,
 			})
 			if err != nil {
 				h.logger.Warn("challenge solve failed",
@@ -377,10 +561,19 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 					"session", req.Session,
 					"url", req.URL,
 				)
-				return models.NewErrorResponse("failed to solve challenge: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+				resp := models.NewErrorResponse("failed to solve challenge: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+				if result != nil && result.Cost > 0 {
+					// Failed paid attempts are still billed
+					resp.Usage = &models.UsageInfo{
+						ChallengeType: challengeType,
+						SolverCostUSD: result.Cost,
+					}
+				}
+				return resp
 			}
 
 			solverUsed = result.SolverName
+			solverCost = result.Cost
 			solved = true
 			resolveMethod = result.SolverName
 			h.logger.Info("challenge solved",
@@ -388,6 +581,7 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 				"job_id", jobID,
 				"type", detection.Type,
 				"solver", result.SolverName,
+				"cost_usd", result.Cost,
 				"session", req.Session,
 				"url", req.URL,
 			)
@@ -395,7 +589,15 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 			// Inject token if provided (external solvers return tokens, wait solver doesn't)
 			if result.Token != "" {
 				if err := h.injectCaptchaToken(page, detection, result.Token); err != nil {
-					return models.NewErrorResponse("failed to inject CAPTCHA token: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+					resp := models.NewErrorResponse("failed to inject CAPTCHA token: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+					if result.Cost > 0 {
+						resp.Usage = &models.UsageInfo{
+							SolverUsed:    result.SolverName,
+							ChallengeType: challengeType,
+							SolverCostUSD: result.Cost,
+						}
+					}
+					return resp
 				}
 				// Wait for page to process token
 				time.Sleep(2 * time.Second)
@@ -408,7 +610,17 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 		challenged = false
 		solved = false
 
-		if req.Session != "" && sess != nil && len(sess.Cookies) > 0 {
+		if cached != nil {
+			resolveMethod = "cached"
+			h.logger.Info("no challenge - cached clearance reused successfully",
+				"user_id", userID,
+				"job_id", jobID,
+				"session", req.Session,
+				"host", host,
+				"url", req.URL,
+				"method", resolveMethod,
+			)
+		} else if req.Session != "" && sess != nil && len(sess.Cookies) > 0 {
 			resolveMethod = "cached"
 			h.logger.Info("no challenge - session cookies reused successfully",
 				"user_id", userID,
@@ -436,6 +648,25 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 		}
 	}
 
+	// Run scripted actions (load more, scroll, fill forms) before capture
+	if len(req.Actions) > 0 {
+		if err := h.actions.Run(ctx, page, req.Actions); err != nil {
+			h.logger.Warn("browser actions failed",
+				"user_id", userID,
+				"job_id", jobID,
+				"url", req.URL,
+				"error", err,
+			)
+			return models.NewErrorResponse("browser actions failed: "+err.Error(), startTime, time.Now().UnixMilli(), ver, "")
+		}
+		h.logger.Debug("browser actions completed",
+			"user_id", userID,
+			"job_id", jobID,
+			"url", req.URL,
+			"count", len(req.Actions),
+		)
+	}
+
 	// Get final page info
 	info, err := page.Info()
 	if err != nil {
@@ -469,15 +700,57 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 		UserAgent: userAgent,
 		Response:  html,
 		Title:     info.Title,
+
+		FingerprintProfile: profileName,
+	}
+
+	// Keep the clearance from a passed Cloudflare/DDoS-Guard challenge for later requests
+	if solved && clearance.Grants(detection.Type) {
+		if c, ok := h.clearances.Put(host, exit, userAgent, solution.Cookies); ok {
+			cached = c
+			h.logger.Debug("clearance cached",
+				"user_id", userID,
+				"job_id", jobID,
+				"host", host,
+				"expires_at", c.ExpiresAt,
+			)
+		}
+	}
+	if cached != nil {
+		solution.Clearance = cached.Model()
+	}
+
+	if recorder != nil {
+		solution.Network = recorder.Stop()
+		h.logger.Debug("network responses captured",
+			"user_id", userID,
+			"job_id", jobID,
+			"url", req.URL,
+			"count", len(solution.Network),
+		)
 	}
 
 	// Take screenshot if requested
 	if req.Screenshot {
-		screenshot, err := page.Screenshot(false, nil)
+		shot, err := page.Screenshot(false, nil)
 		if err == nil {
-			solution.Screenshot = base64.StdEncoding.EncodeToString(screenshot)
+			solution.Screenshot = base64.StdEncoding.EncodeToString(shot)
 		}
 	}
+	if shotOpts != nil {
+		// A partial capture is still useful, so keep what was taken
+		shots, err := screenshot.Capture(page, shotOpts)
+		if err != nil {
+			h.logger.Warn("full-page screenshot failed",
+				"user_id", userID,
+				"job_id", jobID,
+				"url", req.URL,
+				"segments", len(shots),
+				"error", err,
+			)
+		}
+		solution.Screenshots = shots
+	}
 
 	resp := models.NewSuccessResponse(solution, startTime, time.Now().UnixMilli(), ver, "")
 	resp.ChallengeType = challengeType
@@ -486,9 +759,67 @@ func (h *SolveHandler) handleRequest(ctx context.Context, req *models.SolveReque
 	resp.Solved = solved
 	resp.Method = resolveMethod
 
+	if solverCost > 0 {
+		resp.Usage = &models.UsageInfo{
+			SolverUsed:    solverUsed,
+			ChallengeType: challengeType,
+			SolverCostUSD: solverCost,
+		}
+	}
+
+	if blocker != nil {
+		if resp.Usage == nil {
+			resp.Usage = &models.UsageInfo{}
+		}
+		resp.Usage.BlockedRequests = blocker.Stop()
+		h.logger.Debug("resource blocking finished",
+			"user_id", userID,
+			"job_id", jobID,
+			"url", req.URL,
+			"blocked_requests", resp.Usage.BlockedRequests,
+		)
+	}
+
 	return resp
 }
 
+// clearanceExit returns the proxy exit a request's page is loaded through, which
+// clearances are bound to along with the host.
+func clearanceExit(sess *session.Session, prx *proxy.Proxy) string {
+	if sess != nil {
+		if sess.Proxy == "" {
+			return ""
+		}
+		p, err := proxy.Parse(&models.ProxyConfig{URL: sess.Proxy})
+		if err != nil {
+			// Keep sessions on an unparseable proxy apart from direct requests
+			return sess.Proxy
+		}
+		return p.Exit()
+	}
+	return prx.Exit()
+}
+
+// hasClearanceCookie reports whether a request brings its own challenge clearance.
+func hasClearanceCookie(cookies []models.Cookie) bool {
+	for _, c := range cookies {
+		if clearance.IsCookie(c.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// poolTenant identifies the user a pooled browser is acquired for, so the pool can
+// queue users fairly by tier and cap the browsers each holds.
+func poolTenant(ctx context.Context, userID string) browser.Tenant {
+	tenant := browser.Tenant{UserID: userID}
+	if claims := mw.GetUserClaims(ctx); claims != nil {
+		tenant.Tier = claims.Tier
+	}
+	return tenant
+}
+
 // setCookies sets cookies on the page.
 func (h *SolveHandler) setCookies(page *rod.Page, url string, cookies []models.Cookie) error {
 	var cookieParams []*proto.NetworkCookieParam
@@ -5,10 +5,13 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/jmylchreest/refyne-api/captcha/internal/http/mw"
 	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+	"github.com/jmylchreest/refyne-api/captcha/internal/proxy"
+	"github.com/jmylchreest/refyne-api/captcha/internal/session"
 )
 
 // Note: Full integration tests for SolveHandler.Handle require:
@@ -219,6 +222,141 @@ func TestSolveHandler_RequestPostValidation(t *testing.T) {
 	})
 }
 
+func TestSolveHandler_RequestActionsValidation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	h := &SolveHandler{
+		logger: logger,
+	}
+
+	t.Run("invalid actions are rejected before acquiring a browser", func(t *testing.T) {
+		ctx := context.Background()
+		req := &models.SolveRequest{
+			Cmd: models.CmdRequestGet,
+			URL: "https://example.com",
+			Actions: []models.Action{
+				{Type: models.ActionEvaluate, Script: "document.cookie"},
+			},
+		}
+
+		resp := h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+
+		if resp.Status != "error" {
+			t.Errorf("expected error for invalid actions, got %q", resp.Status)
+		}
+		if !strings.HasPrefix(resp.Message, "invalid actions: action 1 (evaluate)") {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+	})
+
+	t.Run("invalid network capture is rejected before acquiring a browser", func(t *testing.T) {
+		ctx := context.Background()
+		req := &models.SolveRequest{
+			Cmd:            models.CmdRequestGet,
+			URL:            "https://example.com",
+			CaptureNetwork: &models.NetworkCapture{URLPatterns: []string{"("}},
+		}
+
+		resp := h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+
+		if resp.Status != "error" {
+			t.Errorf("expected error for invalid network capture, got %q", resp.Status)
+		}
+		if !strings.HasPrefix(resp.Message, "invalid captureNetwork: invalid url pattern") {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+	})
+
+	t.Run("invalid resource policy is rejected before acquiring a browser", func(t *testing.T) {
+		ctx := context.Background()
+		req := &models.SolveRequest{
+			Cmd:            models.CmdRequestGet,
+			URL:            "https://example.com",
+			ResourcePolicy: &models.ResourcePolicy{Profile: "minimal"},
+		}
+
+		resp := h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+
+		if resp.Status != "error" {
+			t.Errorf("expected error for invalid resource policy, got %q", resp.Status)
+		}
+		if !strings.HasPrefix(resp.Message, "invalid resourcePolicy: unknown profile") {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+	})
+
+	t.Run("invalid proxy is rejected before acquiring a browser", func(t *testing.T) {
+		ctx := context.Background()
+		req := &models.SolveRequest{
+			Cmd:   models.CmdRequestGet,
+			URL:   "https://example.com",
+			Proxy: &models.ProxyConfig{URL: "ftp://proxy.example.com"},
+		}
+
+		resp := h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+
+		if resp.Status != "error" {
+			t.Errorf("expected error for invalid proxy, got %q", resp.Status)
+		}
+		if !strings.HasPrefix(resp.Message, "invalid proxy: unsupported scheme") {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+	})
+
+	t.Run("proxy can't be set on a session request", func(t *testing.T) {
+		ctx := context.Background()
+		req := &models.SolveRequest{
+			Cmd:     models.CmdRequestGet,
+			URL:     "https://example.com",
+			Session: "sess_1",
+			Proxy:   &models.ProxyConfig{URL: "http://proxy.example.com:8080"},
+		}
+
+		resp := h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+
+		if resp.Message != "invalid proxy: sessions use the proxy they were created with" {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+	})
+
+	t.Run("unknown fingerprint profile is rejected before acquiring a browser", func(t *testing.T) {
+		ctx := context.Background()
+		req := &models.SolveRequest{
+			Cmd:                models.CmdRequestGet,
+			URL:                "https://example.com",
+			FingerprintProfile: "firefox-linux",
+		}
+
+		resp := h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+
+		if !strings.HasPrefix(resp.Message, `invalid fingerprintProfile: unknown fingerprint profile "firefox-linux"`) {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+	})
+
+	t.Run("fingerprint profile can't be set on a session request or with a user agent", func(t *testing.T) {
+		ctx := context.Background()
+		req := &models.SolveRequest{
+			Cmd:                models.CmdRequestGet,
+			URL:                "https://example.com",
+			Session:            "sess_1",
+			FingerprintProfile: "safari-mac",
+		}
+
+		resp := h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+		if resp.Message != "invalid fingerprintProfile: sessions use the profile they were created with" {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+
+		req.Session = ""
+		req.UserAgent = "Mozilla/5.0 (test)"
+		resp = h.handleRequestGet(ctx, req, 0, "1.0.0", "", "")
+		if !strings.HasPrefix(resp.Message, "invalid fingerprintProfile: the profile sets the user agent") {
+			t.Errorf("unexpected error message: %q", resp.Message)
+		}
+	})
+}
+
 func TestConvertCookies(t *testing.T) {
 	h := &SolveHandler{}
 
@@ -235,6 +373,37 @@ func TestConvertCookies(t *testing.T) {
 	})
 }
 
+func TestClearanceExit(t *testing.T) {
+	tests := []struct {
+		name string
+		sess *session.Session
+		prx  *proxy.Proxy
+		want string
+	}{
+		{"direct", nil, nil, ""},
+		{"request proxy", nil, &proxy.Proxy{Server: "http://proxy.example.com:8080", Credentials: &proxy.Credentials{Username: "user", Password: "pw"}}, "user@proxy.example.com:8080"},
+		{"session without proxy", &session.Session{}, nil, ""},
+		{"session proxy", &session.Session{Proxy: "http://user:pw@proxy.example.com:8080"}, nil, "user@proxy.example.com:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clearanceExit(tt.sess, tt.prx); got != tt.want {
+				t.Errorf("clearanceExit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasClearanceCookie(t *testing.T) {
+	if hasClearanceCookie([]models.Cookie{{Name: "session", Value: "x"}}) {
+		t.Error("hasClearanceCookie() = true for a login cookie")
+	}
+	if !hasClearanceCookie([]models.Cookie{{Name: "session"}, {Name: "cf_clearance"}}) {
+		t.Error("hasClearanceCookie() = false with cf_clearance")
+	}
+}
+
 // Integration tests would be added here with proper setup of:
 // - Browser pool
 // - Session manager
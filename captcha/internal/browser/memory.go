@@ -0,0 +1,67 @@
+package browser
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// availableMemory returns the memory available for new browsers in bytes: the host's
+// MemAvailable, lowered to the container's headroom when a cgroup v2 limit applies.
+// It reports false when neither is readable (e.g. not on Linux).
+func availableMemory() (uint64, bool) {
+	avail, ok := readMemInfoAvailable("/proc/meminfo")
+	if limit, used, cgOK := readCgroupMemory("/sys/fs/cgroup"); cgOK {
+		headroom := uint64(0)
+		if limit > used {
+			headroom = limit - used
+		}
+		if !ok || headroom < avail {
+			avail, ok = headroom, true
+		}
+	}
+	return avail, ok
+}
+
+// readMemInfoAvailable parses the MemAvailable line of /proc/meminfo.
+func readMemInfoAvailable(path string) (uint64, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemAvailable:" {
+			kb, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, false
+			}
+			return kb * 1024, true
+		}
+	}
+	return 0, false
+}
+
+// readCgroupMemory reads the cgroup v2 memory limit and usage in dir. It reports false
+// when there is no limit ("max") or the files don't exist.
+func readCgroupMemory(dir string) (limit, used uint64, ok bool) {
+	limit, ok = readUintFile(dir + "/memory.max")
+	if !ok {
+		return 0, 0, false
+	}
+	used, ok = readUintFile(dir + "/memory.current")
+	return limit, used, ok
+}
+
+func readUintFile(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	return v, err == nil
+}
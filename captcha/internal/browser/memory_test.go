@@ -0,0 +1,37 @@
+package browser
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMemoryReaders(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := dir + "/" + name
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	meminfo := write("meminfo", "MemTotal:       16384000 kB\nMemFree:         1024000 kB\nMemAvailable:    4096000 kB\n")
+	if got, ok := readMemInfoAvailable(meminfo); !ok || got != 4096000*1024 {
+		t.Errorf("readMemInfoAvailable() = %d, %v", got, ok)
+	}
+	if _, ok := readMemInfoAvailable(dir + "/missing"); ok {
+		t.Error("readMemInfoAvailable() of a missing file reported ok")
+	}
+
+	write("memory.max", "2147483648\n")
+	write("memory.current", "1073741824\n")
+	if limit, used, ok := readCgroupMemory(dir); !ok || limit != 2<<30 || used != 1<<30 {
+		t.Errorf("readCgroupMemory() = %d, %d, %v", limit, used, ok)
+	}
+
+	// An unlimited cgroup has no headroom to report
+	write("memory.max", "max\n")
+	if _, _, ok := readCgroupMemory(dir); ok {
+		t.Error("readCgroupMemory() without a limit reported ok")
+	}
+}
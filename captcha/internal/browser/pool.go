@@ -34,21 +34,48 @@ type ManagedBrowser struct {
 	RequestCount int
 	Proxy        string
 	UserAgent    string
+
+	owner *flow // User holding the browser (nil when idle)
 }
 
-// Pool manages a pool of browser instances.
+// autoscaleInterval is how often the autoscaler checks queue wait and memory.
+const autoscaleInterval = time.Second
+
+// waitSmoothing weights the latest queue wait in the moving average reported in stats.
+const waitSmoothing = 0.2
+
+// Pool manages a pool of browser instances. Requests that find every browser busy
+// are queued per user and served by weighted fair queuing (see fairQueue), each user
+// holds at most BrowserMaxPerUser browsers, and the pool's capacity scales between
+// BrowserPoolMinSize and BrowserPoolSize with queue wait and available memory.
 type Pool struct {
 	mu       sync.RWMutex
 	browsers map[string]*ManagedBrowser
-	waiting  []chan *ManagedBrowser
+	queue    *fairQueue
 	cfg      *config.Config
 	logger   *slog.Logger
 	closed   bool
 
+	// Autoscaling state
+	capacity   int       // Browsers the pool may currently hold
+	launching  int       // Slots reserved for browsers being launched
+	lastBusy   time.Time // Last time requests were queued (delays scale-down)
+	lastScaled time.Time
+
+	// Queue statistics
+	avgWait   time.Duration
+	served    uint64
+	abandoned uint64
+
 	// Launcher configuration
 	chromePath string
 	headless   bool
 
+	// Hooks swapped in tests
+	launch       func(ctx context.Context) (*ManagedBrowser, error)
+	ping         func(b *ManagedBrowser) bool
+	memAvailable func() (uint64, bool)
+
 	// Ready state for async warmup
 	ready     bool
 	readyChan chan struct{}
@@ -56,16 +83,21 @@ type Pool struct {
 
 // NewPool creates a new browser pool.
 func NewPool(cfg *config.Config, logger *slog.Logger) *Pool {
-	return &Pool{
-		browsers:   make(map[string]*ManagedBrowser),
-		waiting:    make([]chan *ManagedBrowser, 0),
-		cfg:        cfg,
-		logger:     logger,
-		chromePath: cfg.ChromePath,
-		headless:   true,
-		ready:      false,
-		readyChan:  make(chan struct{}),
+	p := &Pool{
+		browsers:     make(map[string]*ManagedBrowser),
+		queue:        newFairQueue(cfg.BrowserTierWeights),
+		cfg:          cfg,
+		logger:       logger,
+		capacity:     max(1, min(cfg.BrowserPoolMinSize, cfg.BrowserPoolSize)),
+		chromePath:   cfg.ChromePath,
+		headless:     true,
+		memAvailable: availableMemory,
+		ready:        false,
+		readyChan:    make(chan struct{}),
 	}
+	p.launch = p.createBrowser
+	p.ping = pingBrowser
+	return p
 }
 
 // Ready returns true if the pool has completed warmup.
@@ -105,13 +137,13 @@ func (p *Pool) Warmup(ctx context.Context, preCreate int) error {
 
 	// Pre-create browsers if requested
 	if preCreate > 0 {
-		if preCreate > p.cfg.BrowserPoolSize {
-			preCreate = p.cfg.BrowserPoolSize
-		}
+		p.mu.RLock()
+		preCreate = min(preCreate, p.capacity)
+		p.mu.RUnlock()
 		p.logger.Info("pre-creating browsers", "count", preCreate)
 
 		for i := 0; i < preCreate; i++ {
-			browser, err := p.createBrowser(ctx)
+			browser, err := p.launch(ctx)
 			if err != nil {
 				p.logger.Error("failed to pre-create browser", "error", err)
 				return err
@@ -133,9 +165,10 @@ func (p *Pool) Warmup(ctx context.Context, preCreate int) error {
 	return nil
 }
 
-// Acquire gets a browser from the pool, creating one if necessary.
-// Blocks if pool is full and all browsers are in use.
-func (p *Pool) Acquire(ctx context.Context) (*ManagedBrowser, error) {
+// Acquire gets a browser from the pool for a tenant, launching one if the pool has
+// capacity. If every browser is busy, or the tenant already holds its share, the
+// request is queued until a browser is released to it or ctx is done.
+func (p *Pool) Acquire(ctx context.Context, tenant Tenant) (*ManagedBrowser, error) {
 	p.mu.Lock()
 
 	if p.closed {
@@ -143,46 +176,54 @@ func (p *Pool) Acquire(ctx context.Context) (*ManagedBrowser, error) {
 		return nil, ErrPoolClosed
 	}
 
-	// Try to find an available browser
-	for _, b := range p.browsers {
-		if !b.InUse && p.isHealthy(b) {
-			b.InUse = true
-			b.LastUsedAt = time.Now()
+	f := p.queue.flow(tenant)
+	if p.underCap(f) {
+		// Try to find an available browser
+		if b := p.takeIdle(); b != nil {
+			p.assign(b, f)
 			p.mu.Unlock()
 			return b, nil
 		}
-	}
 
-	// Create new browser if pool has capacity
-	if len(p.browsers) < p.cfg.BrowserPoolSize {
-		browser, err := p.createBrowser(ctx)
-		if err != nil {
+		// Launch a new browser if the pool has capacity
+		if p.hasRoom() {
+			p.reserve(f)
 			p.mu.Unlock()
-			return nil, err
+			return p.launchFor(ctx, f)
 		}
-		p.browsers[browser.ID] = browser
-		p.mu.Unlock()
-		return browser, nil
 	}
 
-	// Pool is full - wait for a browser to become available
-	waitChan := make(chan *ManagedBrowser, 1)
-	p.waiting = append(p.waiting, waitChan)
+	// Wait for a browser to be released to this tenant
+	w := p.queue.push(f, time.Now())
 	p.mu.Unlock()
 
 	select {
-	case browser := <-waitChan:
-		return browser, nil
+	case g, ok := <-w.ch:
+		if !ok {
+			return nil, ErrPoolClosed
+		}
+		if g.launch {
+			return p.launchFor(ctx, f)
+		}
+		return g.browser, nil
 	case <-ctx.Done():
-		// Remove from waiting list
 		p.mu.Lock()
-		for i, ch := range p.waiting {
-			if ch == waitChan {
-				p.waiting = append(p.waiting[:i], p.waiting[i+1:]...)
-				break
+		defer p.mu.Unlock()
+		if p.queue.remove(w) {
+			p.abandoned++
+			return nil, ctx.Err()
+		}
+		// Served while giving up - hand the grant on
+		if g, ok := <-w.ch; ok {
+			if g.launch {
+				p.unreserve(f)
+			} else {
+				g.browser.InUse = false
+				g.browser.owner = nil
+				p.queue.released(f)
 			}
+			p.dispatch()
 		}
-		p.mu.Unlock()
 		return nil, ctx.Err()
 	}
 }
@@ -200,21 +241,21 @@ func (p *Pool) Release(browser *ManagedBrowser) {
 	browser.InUse = false
 	browser.RequestCount++
 	browser.LastUsedAt = time.Now()
+	if browser.owner != nil {
+		p.queue.released(browser.owner)
+		browser.owner = nil
+	}
 
-	// Check if browser needs to be recycled
+	// Close browsers that need recycling, or that the pool has scaled down past;
+	// waiters launch replacements in the freed slots
 	if p.needsRecycle(browser) {
-		p.recycleBrowser(browser)
-		return
+		p.logger.Info("recycling browser", "id", browser.ID, "age", time.Since(browser.CreatedAt), "requests", browser.RequestCount)
+		p.removeBrowser(browser)
+	} else if len(p.browsers)+p.launching > p.capacity {
+		p.removeBrowser(browser)
 	}
 
-	// Notify waiting goroutines
-	if len(p.waiting) > 0 {
-		waitChan := p.waiting[0]
-		p.waiting = p.waiting[1:]
-		browser.InUse = true
-		browser.LastUsedAt = time.Now()
-		waitChan <- browser
-	}
+	p.dispatch()
 }
 
 // Close shuts down all browsers and closes the pool.
@@ -234,22 +275,35 @@ func (p *Pool) Close() {
 	p.browsers = make(map[string]*ManagedBrowser)
 
 	// Cancel all waiting
-	for _, ch := range p.waiting {
-		close(ch)
-	}
-	p.waiting = nil
+	p.queue.closeAll()
 }
 
 // Stats returns current pool statistics.
 func (p *Pool) Stats() PoolStats {
+	mem, memKnown := p.memAvailable()
+
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	stats := PoolStats{
-		Total:   len(p.browsers),
-		MaxSize: p.cfg.BrowserPoolSize,
-		Waiting: len(p.waiting),
-		Ready:   p.ready,
+		Total:     len(p.browsers),
+		MaxSize:   p.cfg.BrowserPoolSize,
+		Waiting:   p.queue.waiting,
+		Ready:     p.ready,
+		MinSize:   p.minSize(),
+		Capacity:  p.capacity,
+		Launching: p.launching,
+		Queue: QueueStats{
+			Waiting:      p.queue.waiting,
+			OldestWaitMs: p.queue.oldestWait(time.Now(), func(*flow) bool { return true }).Milliseconds(),
+			AvgWaitMs:    p.avgWait.Milliseconds(),
+			Served:       p.served,
+			Abandoned:    p.abandoned,
+			MaxPerUser:   p.cfg.BrowserMaxPerUser,
+		},
+	}
+	if memKnown {
+		stats.MemoryAvailableMB = int(mem >> 20)
 	}
 
 	for _, b := range p.browsers {
@@ -260,6 +314,28 @@ func (p *Pool) Stats() PoolStats {
 		}
 	}
 
+	// Per-tier usage, without exposing user IDs
+	for _, f := range p.queue.flows {
+		if f.inUse > 0 {
+			stats.Queue.ActiveUsers++
+		}
+		if len(f.queue) > 0 {
+			stats.Queue.WaitingUsers++
+		}
+		tier := f.tier
+		if tier == "" {
+			tier = "anonymous"
+		}
+		if stats.Queue.Tiers == nil {
+			stats.Queue.Tiers = make(map[string]TierStats)
+		}
+		ts := stats.Queue.Tiers[tier]
+		ts.Weight = f.weight
+		ts.InUse += f.inUse
+		ts.Waiting += len(f.queue)
+		stats.Queue.Tiers[tier] = ts
+	}
+
 	return stats
 }
 
@@ -271,6 +347,200 @@ type PoolStats struct {
 	MaxSize   int  `json:"maxSize"`
 	Waiting   int  `json:"waiting"`
 	Ready     bool `json:"ready"`
+
+	MinSize           int        `json:"minSize"`
+	Capacity          int        `json:"capacity"`                    // Browsers the autoscaler currently allows
+	Launching         int        `json:"launching"`                   // Browsers being launched
+	MemoryAvailableMB int        `json:"memoryAvailableMb,omitempty"` // Memory available for new browsers
+	Queue             QueueStats `json:"queue"`
+}
+
+// QueueStats describes requests waiting for browsers.
+type QueueStats struct {
+	Waiting      int                  `json:"waiting"`
+	WaitingUsers int                  `json:"waitingUsers"`    // Users with queued requests
+	ActiveUsers  int                  `json:"activeUsers"`     // Users holding browsers
+	OldestWaitMs int64                `json:"oldestWaitMs"`    // Wait of the longest-queued request
+	AvgWaitMs    int64                `json:"avgWaitMs"`       // Moving average wait of served requests
+	Served       uint64               `json:"served"`          // Queued requests that got a browser
+	Abandoned    uint64               `json:"abandoned"`       // Queued requests that gave up
+	MaxPerUser   int                  `json:"maxPerUser"`      // Per-user browser cap (0 = unlimited)
+	Tiers        map[string]TierStats `json:"tiers,omitempty"` // Usage by tier
+}
+
+// TierStats is the pool usage of one tier's users.
+type TierStats struct {
+	Weight  int `json:"weight"`
+	InUse   int `json:"inUse"`
+	Waiting int `json:"waiting"`
+}
+
+// minSize returns the capacity the autoscaler keeps.
+func (p *Pool) minSize() int {
+	return max(1, min(p.cfg.BrowserPoolMinSize, p.cfg.BrowserPoolSize))
+}
+
+// underCap reports whether a flow may take another browser. Anonymous requests
+// (standalone mode) aren't capped.
+func (p *Pool) underCap(f *flow) bool {
+	return f.userID == "" || p.cfg.BrowserMaxPerUser <= 0 || f.inUse < p.cfg.BrowserMaxPerUser
+}
+
+// hasRoom reports whether another browser may be launched.
+func (p *Pool) hasRoom() bool {
+	return len(p.browsers)+p.launching < p.capacity
+}
+
+// takeIdle returns a healthy idle browser, closing unhealthy ones it finds.
+func (p *Pool) takeIdle() *ManagedBrowser {
+	for _, b := range p.browsers {
+		if b.InUse {
+			continue
+		}
+		if p.isHealthy(b) {
+			return b
+		}
+		p.removeBrowser(b)
+	}
+	return nil
+}
+
+// assign hands an idle browser to a flow.
+func (p *Pool) assign(b *ManagedBrowser, f *flow) {
+	b.InUse = true
+	b.LastUsedAt = time.Now()
+	b.owner = f
+	p.queue.acquired(f)
+}
+
+// reserve and unreserve account for a browser being launched for a flow.
+func (p *Pool) reserve(f *flow) {
+	p.launching++
+	p.queue.acquired(f)
+}
+
+func (p *Pool) unreserve(f *flow) {
+	p.launching--
+	p.queue.released(f)
+}
+
+// launchFor launches a browser in a slot reserved for a flow.
+func (p *Pool) launchFor(ctx context.Context, f *flow) (*ManagedBrowser, error) {
+	b, err := p.launch(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil && p.closed {
+		p.closeBrowser(b)
+		err = ErrPoolClosed
+	}
+	if err != nil {
+		p.unreserve(f)
+		p.dispatch()
+		return nil, err
+	}
+	p.launching--
+	b.InUse = true
+	b.owner = f
+	p.browsers[b.ID] = b
+	return b, nil
+}
+
+// dispatch serves queued requests while there are idle browsers or free slots,
+// in fair-queuing order among the users still under their cap.
+func (p *Pool) dispatch() {
+	for p.queue.waiting > 0 {
+		idle := p.takeIdle()
+		if idle == nil && !p.hasRoom() {
+			return
+		}
+		w := p.queue.next(p.underCap)
+		if w == nil {
+			return
+		}
+
+		if idle != nil {
+			p.assign(idle, w.flow)
+			w.ch <- grant{browser: idle}
+		} else {
+			p.reserve(w.flow)
+			w.ch <- grant{launch: true}
+		}
+
+		wait := time.Since(w.enqueuedAt)
+		p.avgWait += time.Duration(waitSmoothing * float64(wait-p.avgWait))
+		p.served++
+	}
+}
+
+// removeBrowser closes a browser and drops it from the pool.
+func (p *Pool) removeBrowser(b *ManagedBrowser) {
+	p.closeBrowser(b)
+	delete(p.browsers, b.ID)
+}
+
+// StartAutoscaler periodically resizes the pool until ctx is done.
+func (p *Pool) StartAutoscaler(ctx context.Context) {
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.autoscale(now)
+		}
+	}
+}
+
+// autoscale adds capacity while requests wait longer than BrowserScaleUpWait, and
+// removes it once nothing has queued for BrowserScaleDownDelay or when available
+// memory drops below BrowserMinFreeMemoryMB.
+func (p *Pool) autoscale(now time.Time) {
+	mem, memKnown := p.memAvailable()
+	lowMemory := memKnown && mem < uint64(p.cfg.BrowserMinFreeMemoryMB)<<20
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return
+	}
+	if p.queue.waiting > 0 {
+		p.lastBusy = now
+	}
+	oldestWait := p.queue.oldestWait(now, p.underCap)
+
+	switch {
+	case lowMemory:
+		if p.capacity > p.minSize() {
+			p.resize(p.capacity-1, now, "low memory", "memory_available_mb", mem>>20)
+		}
+	case oldestWait >= p.cfg.BrowserScaleUpWait && p.capacity < p.cfg.BrowserPoolSize:
+		p.resize(min(p.cfg.BrowserPoolSize, p.capacity+p.queue.waitingIn(p.underCap)), now, "queue wait", "oldest_wait", oldestWait)
+		p.dispatch()
+	case p.capacity > p.minSize() && now.Sub(p.lastBusy) >= p.cfg.BrowserScaleDownDelay && now.Sub(p.lastScaled) >= p.cfg.BrowserScaleDownDelay:
+		p.resize(p.capacity-1, now, "idle")
+	}
+}
+
+// resize sets the pool's capacity, closing idle browsers beyond it. Busy browsers
+// beyond it are closed when released.
+func (p *Pool) resize(capacity int, now time.Time, reason string, attrs ...any) {
+	p.logger.Info("resizing browser pool", append([]any{"from", p.capacity, "to", capacity, "reason", reason}, attrs...)...)
+	p.capacity = capacity
+	p.lastScaled = now
+
+	for _, b := range p.browsers {
+		if len(p.browsers)+p.launching <= p.capacity {
+			break
+		}
+		if !b.InUse {
+			p.removeBrowser(b)
+		}
+	}
 }
 
 // createBrowser creates a new browser instance.
@@ -342,10 +612,16 @@ func (p *Pool) isHealthy(b *ManagedBrowser) bool {
 		return false
 	}
 
-	// Try to ping the browser - simple health check
-	// Use MustPages with panic recovery
+	return p.ping(b)
+}
+
+// pingBrowser checks that a browser still responds.
+func pingBrowser(b *ManagedBrowser) (ok bool) {
+	// Use Pages with panic recovery
 	defer func() {
-		recover()
+		if recover() != nil {
+			ok = false
+		}
 	}()
 	_, err := b.Browser.Pages()
 	return err == nil
@@ -362,47 +638,6 @@ func (p *Pool) needsRecycle(b *ManagedBrowser) bool {
 	return false
 }
 
-// recycleBrowser closes an old browser and creates a new one.
-func (p *Pool) recycleBrowser(b *ManagedBrowser) {
-	p.logger.Info("recycling browser", "id", b.ID, "age", time.Since(b.CreatedAt), "requests", b.RequestCount)
-
-	// Close old browser
-	p.closeBrowser(b)
-	delete(p.browsers, b.ID)
-
-	// Create new browser (in background to not block)
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		newBrowser, err := p.createBrowser(ctx)
-		if err != nil {
-			p.logger.Error("failed to create replacement browser", "error", err)
-			return
-		}
-
-		p.mu.Lock()
-		defer p.mu.Unlock()
-
-		if p.closed {
-			p.closeBrowser(newBrowser)
-			return
-		}
-
-		newBrowser.InUse = false
-		p.browsers[newBrowser.ID] = newBrowser
-
-		// Notify waiting goroutines
-		if len(p.waiting) > 0 {
-			waitChan := p.waiting[0]
-			p.waiting = p.waiting[1:]
-			newBrowser.InUse = true
-			newBrowser.LastUsedAt = time.Now()
-			waitChan <- newBrowser
-		}
-	}()
-}
-
 // closeBrowser safely closes a browser.
 func (p *Pool) closeBrowser(b *ManagedBrowser) {
 	if b.Browser != nil {
@@ -0,0 +1,305 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/config"
+)
+
+// newTestPool returns a pool whose browsers are stubs, so no Chrome is launched.
+func newTestPool(t *testing.T, cfg *config.Config) (*Pool, *atomic.Int32) {
+	t.Helper()
+	if cfg.BrowserMaxAge == 0 {
+		cfg.BrowserMaxAge = time.Hour
+	}
+	if cfg.BrowserMaxRequests == 0 {
+		cfg.BrowserMaxRequests = 100
+	}
+	if cfg.BrowserIdleTimeout == 0 {
+		cfg.BrowserIdleTimeout = time.Hour
+	}
+
+	p := NewPool(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	launched := &atomic.Int32{}
+	p.launch = func(context.Context) (*ManagedBrowser, error) {
+		launched.Add(1)
+		now := time.Now()
+		return &ManagedBrowser{ID: ulid.Make().String(), InUse: true, CreatedAt: now, LastUsedAt: now}, nil
+	}
+	p.ping = func(*ManagedBrowser) bool { return true }
+	p.memAvailable = func() (uint64, bool) { return 8 << 30, true }
+	t.Cleanup(p.Close)
+	return p, launched
+}
+
+// acquireAsync acquires a browser in the background, waiting until the request has queued.
+func acquireAsync(t *testing.T, p *Pool, ctx context.Context, tenant Tenant) <-chan *ManagedBrowser {
+	t.Helper()
+	p.mu.RLock()
+	before := p.queue.waiting
+	p.mu.RUnlock()
+
+	ch := make(chan *ManagedBrowser, 1)
+	go func() {
+		b, err := p.Acquire(ctx, tenant)
+		if err != nil {
+			b = nil
+		}
+		ch <- b
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		p.mu.RLock()
+		queued := p.queue.waiting > before
+		p.mu.RUnlock()
+		if queued {
+			return ch
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("request never queued")
+	return nil
+}
+
+func receive(t *testing.T, ch <-chan *ManagedBrowser) *ManagedBrowser {
+	t.Helper()
+	select {
+	case b := <-ch:
+		return b
+	case <-time.After(time.Second):
+		t.Fatal("queued request wasn't served")
+		return nil
+	}
+}
+
+func TestPool_PerUserCap(t *testing.T) {
+	p, launched := newTestPool(t, &config.Config{BrowserPoolSize: 4, BrowserPoolMinSize: 4, BrowserMaxPerUser: 2})
+	ctx := context.Background()
+	alice := Tenant{UserID: "alice", Tier: "free"}
+
+	first, err := p.Acquire(ctx, alice)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	if _, err := p.Acquire(ctx, alice); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// A third browser would exceed alice's cap, so the request queues despite free capacity
+	third := acquireAsync(t, p, ctx, alice)
+	if _, err := p.Acquire(ctx, Tenant{UserID: "bob"}); err != nil {
+		t.Fatalf("Acquire() for another user error = %v", err)
+	}
+
+	p.Release(first)
+	if b := receive(t, third); b != first {
+		t.Errorf("queued request got %v, want the browser alice released", b)
+	}
+	if got := launched.Load(); got != 3 {
+		t.Errorf("launched %d browsers, want 3", got)
+	}
+}
+
+func TestPool_AnonymousUncapped(t *testing.T) {
+	p, _ := newTestPool(t, &config.Config{BrowserPoolSize: 3, BrowserPoolMinSize: 3, BrowserMaxPerUser: 1})
+	for range 3 {
+		if _, err := p.Acquire(context.Background(), Tenant{}); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+	}
+}
+
+func TestPool_ReleaseServesFairly(t *testing.T) {
+	p, _ := newTestPool(t, &config.Config{BrowserPoolSize: 1, BrowserPoolMinSize: 1, BrowserMaxPerUser: 5})
+	ctx := context.Background()
+
+	held, err := p.Acquire(ctx, Tenant{UserID: "holder"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	// The crawler queues first, but the extract's user gets the next browser
+	// after the crawler's first one
+	crawl1 := acquireAsync(t, p, ctx, Tenant{UserID: "crawler"})
+	crawl2 := acquireAsync(t, p, ctx, Tenant{UserID: "crawler"})
+	extract := acquireAsync(t, p, ctx, Tenant{UserID: "extract"})
+
+	p.Release(held)
+	b := receive(t, crawl1)
+	p.Release(b)
+	b = receive(t, extract)
+	p.Release(b)
+	receive(t, crawl2)
+
+	if stats := p.Stats(); stats.Queue.Served != 3 || stats.Queue.Waiting != 0 {
+		t.Errorf("queue stats = %+v, want 3 served and none waiting", stats.Queue)
+	}
+}
+
+func TestPool_CancelWhileQueued(t *testing.T) {
+	p, _ := newTestPool(t, &config.Config{BrowserPoolSize: 1, BrowserPoolMinSize: 1})
+	held, err := p.Acquire(context.Background(), Tenant{UserID: "holder"})
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queued := acquireAsync(t, p, ctx, Tenant{UserID: "impatient"})
+	cancel()
+	if b := receive(t, queued); b != nil {
+		t.Errorf("cancelled request got browser %v", b)
+	}
+
+	stats := p.Stats()
+	if stats.Queue.Abandoned != 1 || stats.Queue.Waiting != 0 {
+		t.Errorf("queue stats = %+v, want 1 abandoned and none waiting", stats.Queue)
+	}
+
+	// The browser goes back to the pool rather than to the abandoned request
+	p.Release(held)
+	if stats := p.Stats(); stats.Available != 1 {
+		t.Errorf("Available = %d, want 1", stats.Available)
+	}
+}
+
+func TestPool_CloseReleasesWaiters(t *testing.T) {
+	p, _ := newTestPool(t, &config.Config{BrowserPoolSize: 1, BrowserPoolMinSize: 1})
+	if _, err := p.Acquire(context.Background(), Tenant{UserID: "holder"}); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := p.Acquire(context.Background(), Tenant{UserID: "waiter"})
+		errCh <- err
+	}()
+	for p.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	p.Close()
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, ErrPoolClosed) {
+			t.Errorf("Acquire() error = %v, want ErrPoolClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter not released on Close")
+	}
+}
+
+func TestPool_AutoscaleUpOnQueueWait(t *testing.T) {
+	p, launched := newTestPool(t, &config.Config{
+		BrowserPoolSize: 4, BrowserPoolMinSize: 1, BrowserScaleUpWait: time.Second, BrowserScaleDownDelay: time.Minute,
+	})
+	ctx := context.Background()
+	if _, err := p.Acquire(ctx, Tenant{UserID: "a"}); err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	b := acquireAsync(t, p, ctx, Tenant{UserID: "b"})
+	c := acquireAsync(t, p, ctx, Tenant{UserID: "c"})
+
+	// Requests that have only just queued don't add capacity
+	p.autoscale(time.Now())
+	if got := p.Stats().Capacity; got != 1 {
+		t.Fatalf("Capacity = %d after a short wait, want 1", got)
+	}
+
+	p.autoscale(time.Now().Add(2 * time.Second))
+	if got := p.Stats().Capacity; got != 3 {
+		t.Errorf("Capacity = %d, want 3 (one more per waiting request)", got)
+	}
+	receive(t, b)
+	receive(t, c)
+	if got := launched.Load(); got != 3 {
+		t.Errorf("launched %d browsers, want 3", got)
+	}
+}
+
+func TestPool_AutoscaleDown(t *testing.T) {
+	cfg := &config.Config{
+		BrowserPoolSize: 4, BrowserPoolMinSize: 1, BrowserScaleUpWait: time.Second,
+		BrowserScaleDownDelay: time.Minute, BrowserMinFreeMemoryMB: 512,
+	}
+
+	t.Run("after idle delay", func(t *testing.T) {
+		p, _ := newTestPool(t, cfg)
+		p.capacity = 3
+		now := time.Now()
+		p.lastBusy, p.lastScaled = now, now
+
+		p.autoscale(now.Add(30 * time.Second))
+		if p.capacity != 3 {
+			t.Errorf("Capacity = %d before the delay, want 3", p.capacity)
+		}
+		p.autoscale(now.Add(time.Minute))
+		if p.capacity != 2 {
+			t.Errorf("Capacity = %d after the delay, want 2", p.capacity)
+		}
+	})
+
+	t.Run("on low memory", func(t *testing.T) {
+		p, _ := newTestPool(t, cfg)
+		ctx := context.Background()
+		p.capacity = 2
+		a, _ := p.Acquire(ctx, Tenant{UserID: "a"})
+		b, _ := p.Acquire(ctx, Tenant{UserID: "b"})
+		p.Release(a)
+
+		p.memAvailable = func() (uint64, bool) { return 256 << 20, true }
+		p.autoscale(time.Now())
+
+		stats := p.Stats()
+		if stats.Capacity != 1 || stats.Total != 1 {
+			t.Errorf("Capacity = %d, Total = %d, want the idle browser closed", stats.Capacity, stats.Total)
+		}
+		if stats.MemoryAvailableMB != 256 {
+			t.Errorf("MemoryAvailableMB = %d, want 256", stats.MemoryAvailableMB)
+		}
+
+		// Never below the minimum
+		p.autoscale(time.Now())
+		if p.capacity != 1 {
+			t.Errorf("Capacity = %d, want min 1", p.capacity)
+		}
+		p.Release(b)
+	})
+}
+
+func TestPool_StatsByTier(t *testing.T) {
+	p, _ := newTestPool(t, &config.Config{
+		BrowserPoolSize: 2, BrowserPoolMinSize: 2, BrowserMaxPerUser: 3,
+		BrowserTierWeights: map[string]int{"pro": 4},
+	})
+	ctx := context.Background()
+	for _, tenant := range []Tenant{{UserID: "p1", Tier: "pro"}, {}} {
+		if _, err := p.Acquire(ctx, tenant); err != nil {
+			t.Fatalf("Acquire() error = %v", err)
+		}
+	}
+	acquireAsync(t, p, ctx, Tenant{UserID: "f1", Tier: "free"})
+
+	stats := p.Stats()
+	if stats.InUse != 2 || stats.Queue.ActiveUsers != 2 || stats.Queue.WaitingUsers != 1 || stats.Queue.MaxPerUser != 3 {
+		t.Errorf("stats = %+v", stats)
+	}
+	want := map[string]TierStats{
+		"pro":       {Weight: 4, InUse: 1},
+		"anonymous": {Weight: 1, InUse: 1},
+		"free":      {Weight: 1, Waiting: 1},
+	}
+	for tier, ts := range want {
+		if got := stats.Queue.Tiers[tier]; got != ts {
+			t.Errorf("Tiers[%q] = %+v, want %+v", tier, got, ts)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package browser
+
+import "time"
+
+// Tenant identifies who a pooled browser is acquired for, from the request's signed
+// user claims. Requests without a user (standalone mode) share one anonymous tenant
+// that per-user caps don't apply to.
+type Tenant struct {
+	UserID string
+	Tier   string
+}
+
+// fairQueue orders requests waiting for a browser with start-time fair queuing: each
+// user is a flow weighted by its tier, and every request is tagged with the virtual
+// time at which its flow may next be served. Serving the lowest tag first shares
+// browsers between waiting users in proportion to their weights, however many
+// requests each has queued, so one large crawl can't starve other users' extracts.
+type fairQueue struct {
+	weights map[string]int   // Weight per tier (unlisted tiers weigh 1)
+	flows   map[string]*flow // Users holding or waiting for browsers
+	vtime   float64          // Start tag of the last request served
+	seq     uint64           // Tie-breaker keeping equal tags first-in-first-out
+	waiting int
+}
+
+// flow is a user's share of the pool.
+type flow struct {
+	userID     string
+	tier       string
+	weight     int
+	lastFinish float64   // Finish tag of the flow's last queued request
+	queue      []*waiter // Queued requests, oldest first
+	inUse      int       // Browsers held (or being launched) for the user
+}
+
+// waiter is a request queued for a browser.
+type waiter struct {
+	flow       *flow
+	start      float64
+	seq        uint64
+	enqueuedAt time.Time
+	ch         chan grant // Buffered; receives one grant, or is closed with the pool
+}
+
+// grant hands a waiter either an idle browser or a reserved slot to launch one in.
+type grant struct {
+	browser *ManagedBrowser
+	launch  bool
+}
+
+func newFairQueue(weights map[string]int) *fairQueue {
+	return &fairQueue{weights: weights, flows: make(map[string]*flow)}
+}
+
+// flow returns the tenant's flow, creating it if the user holds no browsers yet.
+func (q *fairQueue) flow(t Tenant) *flow {
+	if f, ok := q.flows[t.UserID]; ok {
+		f.tier = t.Tier
+		f.weight = q.weight(t.Tier)
+		return f
+	}
+	f := &flow{userID: t.UserID, tier: t.Tier, weight: q.weight(t.Tier)}
+	q.flows[t.UserID] = f
+	return f
+}
+
+func (q *fairQueue) weight(tier string) int {
+	if w := q.weights[tier]; w > 0 {
+		return w
+	}
+	return 1
+}
+
+// push queues a request for the flow. A flow that has been idle starts at the current
+// virtual time, so it doesn't get credit for the time it wasn't waiting.
+func (q *fairQueue) push(f *flow, now time.Time) *waiter {
+	start := max(q.vtime, f.lastFinish)
+	f.lastFinish = start + 1/float64(f.weight)
+	q.seq++
+	w := &waiter{flow: f, start: start, seq: q.seq, enqueuedAt: now, ch: make(chan grant, 1)}
+	f.queue = append(f.queue, w)
+	q.waiting++
+	return w
+}
+
+// next dequeues the request with the lowest start tag among flows that eligible
+// accepts, or returns nil if none can be served.
+func (q *fairQueue) next(eligible func(*flow) bool) *waiter {
+	var best *waiter
+	for _, f := range q.flows {
+		if len(f.queue) == 0 || !eligible(f) {
+			continue
+		}
+		if w := f.queue[0]; best == nil || w.start < best.start || (w.start == best.start && w.seq < best.seq) {
+			best = w
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	best.flow.queue = best.flow.queue[1:]
+	q.waiting--
+	q.vtime = best.start
+	return best
+}
+
+// remove dequeues a request that stopped waiting, reporting false if it was already
+// served.
+func (q *fairQueue) remove(w *waiter) bool {
+	f := w.flow
+	for i, queued := range f.queue {
+		if queued == w {
+			f.queue = append(f.queue[:i], f.queue[i+1:]...)
+			q.waiting--
+			q.forget(f)
+			return true
+		}
+	}
+	return false
+}
+
+// acquired and released track the browsers a flow holds.
+func (q *fairQueue) acquired(f *flow) { f.inUse++ }
+
+func (q *fairQueue) released(f *flow) {
+	f.inUse--
+	q.forget(f)
+}
+
+// forget drops a flow that neither holds nor waits for browsers.
+func (q *fairQueue) forget(f *flow) {
+	if f.inUse <= 0 && len(f.queue) == 0 && q.flows[f.userID] == f {
+		delete(q.flows, f.userID)
+	}
+}
+
+// waitingIn counts the requests queued by flows that eligible accepts.
+func (q *fairQueue) waitingIn(eligible func(*flow) bool) int {
+	n := 0
+	for _, f := range q.flows {
+		if eligible(f) {
+			n += len(f.queue)
+		}
+	}
+	return n
+}
+
+// oldestWait returns how long the longest-waiting request of an eligible flow has waited.
+func (q *fairQueue) oldestWait(now time.Time, eligible func(*flow) bool) time.Duration {
+	var oldest time.Duration
+	for _, f := range q.flows {
+		if len(f.queue) > 0 && eligible(f) {
+			oldest = max(oldest, now.Sub(f.queue[0].enqueuedAt))
+		}
+	}
+	return oldest
+}
+
+// closeAll releases every waiter with ErrPoolClosed.
+func (q *fairQueue) closeAll() {
+	for _, f := range q.flows {
+		for _, w := range f.queue {
+			close(w.ch)
+		}
+		f.queue = nil
+	}
+	q.flows = make(map[string]*flow)
+	q.waiting = 0
+}
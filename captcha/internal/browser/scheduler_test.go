@@ -0,0 +1,141 @@
+package browser
+
+import (
+	"testing"
+	"time"
+)
+
+func anyFlow(*flow) bool { return true }
+
+func TestFairQueue_WeightedShares(t *testing.T) {
+	q := newFairQueue(map[string]int{"free": 1, "pro": 2})
+	now := time.Now()
+
+	// A free user queues a large crawl before a pro user's requests arrive
+	crawler := q.flow(Tenant{UserID: "crawler", Tier: "free"})
+	for range 6 {
+		q.push(crawler, now)
+	}
+	pro := q.flow(Tenant{UserID: "pro", Tier: "pro"})
+	for range 6 {
+		q.push(pro, now)
+	}
+
+	served := map[string]int{}
+	for range 6 {
+		served[q.next(anyFlow).flow.userID]++
+	}
+	// Both are served straight away, in proportion to their weights
+	if served["pro"] != 4 || served["crawler"] != 2 {
+		t.Errorf("served = %v, want pro 4 and crawler 2", served)
+	}
+	if q.waiting != 6 {
+		t.Errorf("waiting = %d, want 6", q.waiting)
+	}
+}
+
+func TestFairQueue_FIFOWithinTies(t *testing.T) {
+	q := newFairQueue(nil)
+	now := time.Now()
+	a := q.flow(Tenant{UserID: "a"})
+	b := q.flow(Tenant{UserID: "b"})
+
+	first := q.push(b, now)
+	second := q.push(a, now)
+
+	if got := q.next(anyFlow); got != first {
+		t.Error("next() didn't serve the earlier of two equal tags")
+	}
+	if got := q.next(anyFlow); got != second {
+		t.Error("next() didn't serve the remaining waiter")
+	}
+	if q.next(anyFlow) != nil {
+		t.Error("next() on an empty queue returned a waiter")
+	}
+}
+
+func TestFairQueue_SkipsIneligibleFlows(t *testing.T) {
+	q := newFairQueue(nil)
+	now := time.Now()
+	capped := q.flow(Tenant{UserID: "capped"})
+	other := q.flow(Tenant{UserID: "other"})
+	q.push(capped, now.Add(-time.Minute))
+	q.push(other, now)
+
+	eligible := func(f *flow) bool { return f != capped }
+	if got := q.next(eligible); got == nil || got.flow != other {
+		t.Fatalf("next() = %+v, want the other user's waiter", got)
+	}
+	if q.next(eligible) != nil {
+		t.Error("next() served a capped user")
+	}
+	if got := q.oldestWait(now, eligible); got != 0 {
+		t.Errorf("oldestWait(eligible) = %v, want 0", got)
+	}
+	if got := q.oldestWait(now, anyFlow); got != time.Minute {
+		t.Errorf("oldestWait(any) = %v, want 1m", got)
+	}
+	if got := q.waitingIn(anyFlow); got != 1 {
+		t.Errorf("waitingIn(any) = %d, want 1", got)
+	}
+}
+
+func TestFairQueue_IdleFlowGetsNoCredit(t *testing.T) {
+	q := newFairQueue(nil)
+	now := time.Now()
+	busy := q.flow(Tenant{UserID: "busy"})
+	for range 3 {
+		q.push(busy, now)
+	}
+	q.next(anyFlow)
+	q.next(anyFlow)
+
+	// A user arriving late starts at the current virtual time rather than zero,
+	// so it alternates with the busy user instead of jumping ahead of everything
+	late := q.flow(Tenant{UserID: "late"})
+	w := q.push(late, now)
+	if w.start != q.vtime {
+		t.Errorf("start = %v, want current virtual time %v", w.start, q.vtime)
+	}
+}
+
+func TestFairQueue_RemoveAndForget(t *testing.T) {
+	q := newFairQueue(nil)
+	f := q.flow(Tenant{UserID: "user"})
+	w := q.push(f, time.Now())
+
+	if !q.remove(w) {
+		t.Fatal("remove() = false for a queued waiter")
+	}
+	if q.remove(w) {
+		t.Error("remove() = true for a waiter no longer queued")
+	}
+	if _, ok := q.flows["user"]; ok {
+		t.Error("flow kept after its only waiter was removed")
+	}
+
+	// A flow holding browsers is kept until it releases them
+	f = q.flow(Tenant{UserID: "user"})
+	q.acquired(f)
+	q.forget(f)
+	if _, ok := q.flows["user"]; !ok {
+		t.Fatal("flow holding a browser was forgotten")
+	}
+	q.released(f)
+	if _, ok := q.flows["user"]; ok {
+		t.Error("flow kept after releasing its browsers")
+	}
+}
+
+func TestFairQueue_CloseAll(t *testing.T) {
+	q := newFairQueue(nil)
+	w := q.push(q.flow(Tenant{UserID: "user"}), time.Now())
+
+	q.closeAll()
+	if _, ok := <-w.ch; ok {
+		t.Error("waiter channel not closed")
+	}
+	if q.waiting != 0 || len(q.flows) != 0 {
+		t.Errorf("queue not emptied: waiting = %d, flows = %d", q.waiting, len(q.flows))
+	}
+}
@@ -323,7 +323,11 @@ func CreateStealthPage(browser *rod.Browser) (*rod.Page, error) {
 // When disableStealth is true, creates a plain page without any evasion techniques.
 func CreatePage(browser *rod.Browser, disableStealth bool) (*rod.Page, error) {
 	if disableStealth {
-		// Create a plain page without stealth
+		// Create a plain page without stealth, in its own context unless the
+		// browser is already scoped to one (e.g. a proxy context)
+		if browser.BrowserContextID != "" {
+			return browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+		}
 		return browser.MustIncognito().Page(proto.TargetCreateTarget{URL: "about:blank"})
 	}
 	return CreateStealthPage(browser)
@@ -0,0 +1,152 @@
+// Package clearance caches the cookies a site sets once its bot challenge has been
+// passed, so later requests to the same host through the same exit skip the challenge.
+package clearance
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+// Clearance is the proof a challenge was passed for a host. Sites bind it to the
+// user agent and IP address that passed the challenge, so it is only reused with
+// the same user agent through the same exit.
+type Clearance struct {
+	Host       string
+	Exit       string          // Proxy exit the challenge was passed through ("" for direct)
+	Cookies    []models.Cookie // Clearance cookies only
+	UserAgent  string
+	ObtainedAt time.Time
+	ExpiresAt  time.Time
+}
+
+// Model returns the clearance as reported in a solution.
+func (c *Clearance) Model() *models.Clearance {
+	return &models.Clearance{
+		Cookies:    c.Cookies,
+		UserAgent:  c.UserAgent,
+		ObtainedAt: c.ObtainedAt.UnixMilli(),
+		ExpiresAt:  c.ExpiresAt.UnixMilli(),
+	}
+}
+
+// Grants reports whether passing a challenge of type t yields a reusable clearance.
+// CAPTCHA tokens are single use, so only Cloudflare and DDoS-Guard challenges do.
+func Grants(t challenge.Type) bool {
+	switch t {
+	case challenge.TypeCloudflareJS, challenge.TypeCloudflareInterstitial,
+		challenge.TypeCloudflareTurnstile, challenge.TypeDDoSGuard:
+		return true
+	}
+	return false
+}
+
+// IsCookie reports whether a cookie is set by a challenge platform to record
+// that its challenge was passed.
+func IsCookie(name string) bool {
+	return name == "cf_clearance" || strings.HasPrefix(name, "__ddg")
+}
+
+type key struct {
+	host string
+	exit string
+}
+
+// Store holds clearances keyed by host and proxy exit. It is safe for concurrent use.
+type Store struct {
+	ttl     time.Duration
+	nowFunc func() time.Time
+
+	mu      sync.Mutex
+	entries map[key]*Clearance
+}
+
+// NewStore creates a store whose clearances live for at most ttl.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{
+		ttl:     ttl,
+		nowFunc: time.Now,
+		entries: make(map[key]*Clearance),
+	}
+}
+
+// Get returns the unexpired clearance for host through exit.
+func (s *Store) Get(host, exit string) (*Clearance, bool) {
+	if s == nil {
+		return nil, false
+	}
+	k := key{strings.ToLower(host), exit}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.entries[k]
+	if !ok {
+		return nil, false
+	}
+	if !s.nowFunc().Before(c.ExpiresAt) {
+		delete(s.entries, k)
+		return nil, false
+	}
+	return c, true
+}
+
+// Put stores the clearance cookies and user agent a page was cleared with, and
+// returns the clearance. Other cookies are left out, as they may belong to the
+// requester (logins, carts) and clearances are shared between requesters. It
+// expires after the store's TTL or when its first cookie does, whichever is
+// sooner. Nothing is stored when no clearance cookie was set.
+func (s *Store) Put(host, exit, userAgent string, cookies []models.Cookie) (*Clearance, bool) {
+	if s == nil || s.ttl <= 0 {
+		return nil, false
+	}
+	now := s.nowFunc()
+	expiresAt := now.Add(s.ttl)
+	var kept []models.Cookie
+	for _, c := range cookies {
+		if !IsCookie(c.Name) {
+			continue
+		}
+		kept = append(kept, c)
+		if c.Expires > 0 {
+			if exp := time.Unix(c.Expires, 0); exp.Before(expiresAt) {
+				expiresAt = exp
+			}
+		}
+	}
+	if len(kept) == 0 || !now.Before(expiresAt) {
+		return nil, false
+	}
+
+	c := &Clearance{
+		Host:       strings.ToLower(host),
+		Exit:       exit,
+		Cookies:    kept,
+		UserAgent:  userAgent,
+		ObtainedAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Clearances are only written after a challenge, so sweeping here is cheap
+	for k, e := range s.entries {
+		if !now.Before(e.ExpiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key{c.Host, exit}] = c
+	return c, true
+}
+
+// Invalidate drops the clearance for host through exit, for when a page was
+// challenged again despite it.
+func (s *Store) Invalidate(host, exit string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	delete(s.entries, key{strings.ToLower(host), exit})
+	s.mu.Unlock()
+}
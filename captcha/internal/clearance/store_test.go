@@ -0,0 +1,157 @@
+package clearance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+const testUA = "Mozilla/5.0 (test)"
+
+func newTestStore(ttl time.Duration) (*Store, *time.Time) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	s := NewStore(ttl)
+	s.nowFunc = func() time.Time { return now }
+	return s, &now
+}
+
+func TestStore_PutGet(t *testing.T) {
+	s, now := newTestStore(30 * time.Minute)
+	cookies := []models.Cookie{
+		{Name: "cf_clearance", Value: "abc", Domain: ".example.com"},
+		{Name: "session", Value: "xyz"},
+	}
+
+	c, ok := s.Put("Example.com", "", testUA, cookies)
+	if !ok {
+		t.Fatal("Put() stored nothing")
+	}
+	if want := now.Add(30 * time.Minute); !c.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", c.ExpiresAt, want)
+	}
+
+	got, ok := s.Get("example.com", "")
+	if !ok {
+		t.Fatal("Get() found nothing")
+	}
+	if got.UserAgent != testUA {
+		t.Errorf("UserAgent = %q, want %q", got.UserAgent, testUA)
+	}
+	// Only clearance cookies are shared; the rest may belong to the requester
+	if len(got.Cookies) != 1 || got.Cookies[0].Name != "cf_clearance" {
+		t.Errorf("Cookies = %+v, want only cf_clearance", got.Cookies)
+	}
+
+	// Clearances are bound to the exit they were obtained through
+	if _, ok := s.Get("example.com", "proxy.example.net:8080"); ok {
+		t.Error("Get() through another exit found the direct clearance")
+	}
+	if _, ok := s.Get("other.com", ""); ok {
+		t.Error("Get() for another host found a clearance")
+	}
+}
+
+func TestStore_ExpiresWithCookie(t *testing.T) {
+	s, now := newTestStore(30 * time.Minute)
+	expires := now.Add(10 * time.Minute)
+	cookies := []models.Cookie{
+		{Name: "__ddg1_", Value: "a", Expires: expires.Unix()},
+		{Name: "__ddg2_", Value: "b"},                                        // Session cookies don't shorten the clearance
+		{Name: "tracking", Value: "c", Expires: now.Add(time.Minute).Unix()}, // Not kept
+	}
+
+	c, ok := s.Put("example.com", "", testUA, cookies)
+	if !ok {
+		t.Fatal("Put() stored nothing")
+	}
+	if !c.ExpiresAt.Equal(expires) {
+		t.Errorf("ExpiresAt = %v, want %v (clearance cookie expiry)", c.ExpiresAt, expires)
+	}
+
+	*now = expires.Add(-time.Second)
+	if _, ok := s.Get("example.com", ""); !ok {
+		t.Error("Get() before expiry found nothing")
+	}
+	*now = expires
+	if _, ok := s.Get("example.com", ""); ok {
+		t.Error("Get() after expiry found a clearance")
+	}
+}
+
+func TestStore_RequiresClearanceCookie(t *testing.T) {
+	s, now := newTestStore(30 * time.Minute)
+
+	if _, ok := s.Put("example.com", "", testUA, []models.Cookie{{Name: "session", Value: "x"}}); ok {
+		t.Error("Put() stored a clearance without clearance cookies")
+	}
+
+	expired := []models.Cookie{{Name: "cf_clearance", Value: "x", Expires: now.Add(-time.Minute).Unix()}}
+	if _, ok := s.Put("example.com", "", testUA, expired); ok {
+		t.Error("Put() stored an already expired clearance")
+	}
+
+	disabled, _ := newTestStore(0)
+	if _, ok := disabled.Put("example.com", "", testUA, []models.Cookie{{Name: "cf_clearance", Value: "x"}}); ok {
+		t.Error("Put() stored a clearance with the cache disabled")
+	}
+}
+
+func TestStore_Invalidate(t *testing.T) {
+	s, _ := newTestStore(30 * time.Minute)
+	cookies := []models.Cookie{{Name: "cf_clearance", Value: "x"}}
+	s.Put("example.com", "", testUA, cookies)
+	s.Put("example.com", "user@proxy.example.net:8080", testUA, cookies)
+
+	s.Invalidate("EXAMPLE.com", "")
+	if _, ok := s.Get("example.com", ""); ok {
+		t.Error("Get() found an invalidated clearance")
+	}
+	if _, ok := s.Get("example.com", "user@proxy.example.net:8080"); !ok {
+		t.Error("Invalidate() dropped the clearance for another exit")
+	}
+}
+
+func TestStore_SweepsExpired(t *testing.T) {
+	s, now := newTestStore(time.Minute)
+	cookies := []models.Cookie{{Name: "cf_clearance", Value: "x"}}
+	s.Put("a.com", "", testUA, cookies)
+
+	*now = now.Add(time.Hour)
+	s.Put("b.com", "", testUA, cookies)
+	if len(s.entries) != 1 {
+		t.Errorf("entries = %d, want 1 (expired clearance swept)", len(s.entries))
+	}
+}
+
+func TestStore_Nil(t *testing.T) {
+	var s *Store
+	if _, ok := s.Get("example.com", ""); ok {
+		t.Error("nil Store Get() found a clearance")
+	}
+	if _, ok := s.Put("example.com", "", testUA, []models.Cookie{{Name: "cf_clearance"}}); ok {
+		t.Error("nil Store Put() stored a clearance")
+	}
+	s.Invalidate("example.com", "")
+}
+
+func TestGrants(t *testing.T) {
+	tests := []struct {
+		challenge challenge.Type
+		want      bool
+	}{
+		{challenge.TypeCloudflareJS, true},
+		{challenge.TypeCloudflareInterstitial, true},
+		{challenge.TypeCloudflareTurnstile, true},
+		{challenge.TypeDDoSGuard, true},
+		{challenge.TypeHCaptcha, false},
+		{challenge.TypeReCaptchaV2, false},
+		{challenge.TypeNone, false},
+	}
+	for _, tt := range tests {
+		if got := Grants(tt.challenge); got != tt.want {
+			t.Errorf("Grants(%s) = %v, want %v", tt.challenge, got, tt.want)
+		}
+	}
+}
@@ -21,13 +21,23 @@ type Config struct {
 	BrowserMaxAge      time.Duration
 	ChromePath         string
 
+	// Browser pool scheduling and autoscaling (BrowserPoolSize is the maximum)
+	BrowserPoolMinSize     int            // Capacity the autoscaler never goes below
+	BrowserMaxPerUser      int            // Pooled browsers one user may hold at once (0 = unlimited)
+	BrowserTierWeights     map[string]int // Fair-queuing weight per tier (unlisted tiers weigh 1)
+	BrowserScaleUpWait     time.Duration  // Queue wait after which capacity is added
+	BrowserScaleDownDelay  time.Duration  // Time without queueing before capacity is removed
+	BrowserMinFreeMemoryMB int            // Available memory below which the pool shrinks instead of growing
+
 	// Challenge solving settings
 	ChallengeTimeout  time.Duration
 	ChallengeWaitTime time.Duration
+	ClearanceTTL      time.Duration // How long passed Cloudflare/DDoS-Guard challenges are reused (0 = disabled)
 
 	// CAPTCHA solver settings
-	TwoCaptchaAPIKey string
-	CapSolverAPIKey  string
+	TwoCaptchaAPIKey  string
+	CapSolverAPIKey   string
+	AntiCaptchaAPIKey string
 
 	// Authentication
 	RefyneAPISecret      string // HMAC secret for signed headers from refyne-api
@@ -48,6 +58,10 @@ type Config struct {
 
 	// Debug settings
 	DisableStealth bool // Disable stealth mode for testing CAPTCHA solving
+
+	// Fingerprint profile settings
+	FingerprintProfile     string // Profile pages use unless the request picks one
+	FingerprintRotateAfter int    // Consecutive failed challenges on a host before its profile rotates (0 = never)
 }
 
 // Load creates a Config from environment variables with sensible defaults.
@@ -62,8 +76,10 @@ func Load() *Config {
 		ChromePath:           getEnv("CHROME_PATH", ""),
 		ChallengeTimeout:     getEnvDuration("CHALLENGE_TIMEOUT", 60*time.Second),
 		ChallengeWaitTime:    getEnvDuration("CHALLENGE_WAIT_TIME", 30*time.Second),
+		ClearanceTTL:         getEnvDuration("CLEARANCE_TTL", 30*time.Minute),
 		TwoCaptchaAPIKey:     getEnv("TWOCAPTCHA_API_KEY", ""),
 		CapSolverAPIKey:      getEnv("CAPSOLVER_API_KEY", ""),
+		AntiCaptchaAPIKey:    getEnv("ANTICAPTCHA_API_KEY", ""),
 		RefyneAPISecret:      getEnv("REFYNE_API_SECRET", ""),
 		ClerkIssuer:          getEnv("CLERK_ISSUER", ""),
 		RequiredFeature:      getEnv("REQUIRED_FEATURE", "content_dynamic"),
@@ -74,6 +90,16 @@ func Load() *Config {
 		SessionDBPath:        getEnv("SESSION_DB_PATH", ""),
 		IdleTimeout:          getEnvDuration("IDLE_TIMEOUT", 0), // 0 = disabled
 		DisableStealth:       getEnvBool("DISABLE_STEALTH", false),
+
+		BrowserPoolMinSize:     getEnvInt("BROWSER_POOL_MIN_SIZE", 2),
+		BrowserMaxPerUser:      getEnvInt("BROWSER_MAX_PER_USER", 3),
+		BrowserTierWeights:     getEnvWeights("BROWSER_TIER_WEIGHTS", "free=1,standard=2,pro=4,selfhosted=4"),
+		BrowserScaleUpWait:     getEnvDuration("BROWSER_SCALE_UP_WAIT", 2*time.Second),
+		BrowserScaleDownDelay:  getEnvDuration("BROWSER_SCALE_DOWN_DELAY", 2*time.Minute),
+		BrowserMinFreeMemoryMB: getEnvInt("BROWSER_MIN_FREE_MEMORY_MB", 512),
+
+		FingerprintProfile:     getEnv("FINGERPRINT_PROFILE", "chrome-windows"),
+		FingerprintRotateAfter: getEnvInt("FINGERPRINT_ROTATE_AFTER", 3),
 	}
 }
 
@@ -101,6 +127,21 @@ func getEnvBool(key string, defaultVal bool) bool {
 	return defaultVal
 }
 
+// getEnvWeights parses comma-separated name=weight pairs, skipping malformed ones.
+func getEnvWeights(key, defaultVal string) map[string]int {
+	weights := make(map[string]int)
+	for _, pair := range strings.Split(getEnv(key, defaultVal), ",") {
+		name, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		if w, err := strconv.Atoi(strings.TrimSpace(val)); err == nil && w > 0 {
+			weights[strings.TrimSpace(name)] = w
+		}
+	}
+	return weights
+}
+
 func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
 	if val := os.Getenv(key); val != "" {
 		if d, err := time.ParseDuration(val); err == nil {
@@ -12,10 +12,12 @@ func TestLoad(t *testing.T) {
 	envVars := []string{
 		"PORT", "LOG_LEVEL", "BROWSER_POOL_SIZE", "BROWSER_IDLE_TIMEOUT",
 		"BROWSER_MAX_REQUESTS", "BROWSER_MAX_AGE", "CHROME_PATH",
-		"CHALLENGE_TIMEOUT", "CHALLENGE_WAIT_TIME", "TWOCAPTCHA_API_KEY",
-		"CAPSOLVER_API_KEY", "REFYNE_API_SECRET", "CLERK_ISSUER",
+		"CHALLENGE_TIMEOUT", "CHALLENGE_WAIT_TIME", "CLEARANCE_TTL", "TWOCAPTCHA_API_KEY",
+		"CAPSOLVER_API_KEY", "ANTICAPTCHA_API_KEY", "REFYNE_API_SECRET", "CLERK_ISSUER",
 		"REQUIRED_FEATURE", "ALLOW_UNAUTHENTICATED", "PROXY_ENABLED",
-		"PROXY_URL", "SESSION_MAX_IDLE",
+		"PROXY_URL", "SESSION_MAX_IDLE", "BROWSER_POOL_MIN_SIZE", "BROWSER_MAX_PER_USER",
+		"BROWSER_TIER_WEIGHTS", "BROWSER_SCALE_UP_WAIT", "BROWSER_SCALE_DOWN_DELAY",
+		"BROWSER_MIN_FREE_MEMORY_MB", "FINGERPRINT_PROFILE", "FINGERPRINT_ROTATE_AFTER",
 	}
 
 	for _, v := range envVars {
@@ -63,6 +65,33 @@ func TestLoad(t *testing.T) {
 		if cfg.ChallengeWaitTime != 30*time.Second {
 			t.Errorf("ChallengeWaitTime = %v, want 30s", cfg.ChallengeWaitTime)
 		}
+		if cfg.ClearanceTTL != 30*time.Minute {
+			t.Errorf("ClearanceTTL = %v, want 30m", cfg.ClearanceTTL)
+		}
+		if cfg.BrowserPoolMinSize != 2 {
+			t.Errorf("BrowserPoolMinSize = %d, want 2", cfg.BrowserPoolMinSize)
+		}
+		if cfg.BrowserMaxPerUser != 3 {
+			t.Errorf("BrowserMaxPerUser = %d, want 3", cfg.BrowserMaxPerUser)
+		}
+		if cfg.BrowserTierWeights["pro"] != 4 || cfg.BrowserTierWeights["free"] != 1 {
+			t.Errorf("BrowserTierWeights = %v, want free=1 and pro=4", cfg.BrowserTierWeights)
+		}
+		if cfg.BrowserScaleUpWait != 2*time.Second {
+			t.Errorf("BrowserScaleUpWait = %v, want 2s", cfg.BrowserScaleUpWait)
+		}
+		if cfg.BrowserScaleDownDelay != 2*time.Minute {
+			t.Errorf("BrowserScaleDownDelay = %v, want 2m", cfg.BrowserScaleDownDelay)
+		}
+		if cfg.BrowserMinFreeMemoryMB != 512 {
+			t.Errorf("BrowserMinFreeMemoryMB = %d, want 512", cfg.BrowserMinFreeMemoryMB)
+		}
+		if cfg.FingerprintProfile != "chrome-windows" {
+			t.Errorf("FingerprintProfile = %q, want %q", cfg.FingerprintProfile, "chrome-windows")
+		}
+		if cfg.FingerprintRotateAfter != 3 {
+			t.Errorf("FingerprintRotateAfter = %d, want 3", cfg.FingerprintRotateAfter)
+		}
 		if cfg.SessionMaxIdle != 10*time.Minute {
 			t.Errorf("SessionMaxIdle = %v, want 10m", cfg.SessionMaxIdle)
 		}
@@ -87,6 +116,7 @@ func TestLoad(t *testing.T) {
 		os.Setenv("CHALLENGE_TIMEOUT", "120s")
 		os.Setenv("TWOCAPTCHA_API_KEY", "test-2captcha-key")
 		os.Setenv("CAPSOLVER_API_KEY", "test-capsolver-key")
+		os.Setenv("ANTICAPTCHA_API_KEY", "test-anticaptcha-key")
 		os.Setenv("REFYNE_API_SECRET", "secret-key")
 		os.Setenv("CLERK_ISSUER", "https://test.clerk.accounts.dev")
 		os.Setenv("REQUIRED_FEATURE", "premium_captcha")
@@ -123,6 +153,9 @@ func TestLoad(t *testing.T) {
 		if cfg.CapSolverAPIKey != "test-capsolver-key" {
 			t.Errorf("CapSolverAPIKey = %q, want %q", cfg.CapSolverAPIKey, "test-capsolver-key")
 		}
+		if cfg.AntiCaptchaAPIKey != "test-anticaptcha-key" {
+			t.Errorf("AntiCaptchaAPIKey = %q, want %q", cfg.AntiCaptchaAPIKey, "test-anticaptcha-key")
+		}
 		if cfg.RefyneAPISecret != "secret-key" {
 			t.Errorf("RefyneAPISecret = %q, want %q", cfg.RefyneAPISecret, "secret-key")
 		}
@@ -189,6 +222,20 @@ func TestGetEnvInt(t *testing.T) {
 	}
 }
 
+func TestGetEnvWeights(t *testing.T) {
+	os.Setenv("TEST_WEIGHTS", " free=1, pro = 5,bogus,zero=0,bad=x")
+	defer os.Unsetenv("TEST_WEIGHTS")
+
+	got := getEnvWeights("TEST_WEIGHTS", "")
+	if len(got) != 2 || got["free"] != 1 || got["pro"] != 5 {
+		t.Errorf("getEnvWeights() = %v, want free=1 and pro=5", got)
+	}
+
+	if got := getEnvWeights("NONEXISTENT_WEIGHTS", "standard=2"); got["standard"] != 2 {
+		t.Errorf("getEnvWeights(default) = %v, want standard=2", got)
+	}
+}
+
 func TestGetEnvDuration(t *testing.T) {
 	os.Setenv("TEST_DUR", "5m")
 	defer os.Unsetenv("TEST_DUR")
@@ -0,0 +1,170 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// fallbackVersion is the Chrome version reported when the browser's version can't be read.
+const fallbackVersion = "131.0.0.0"
+
+// profileScript overrides the navigator and WebGL properties the emulation domain
+// doesn't cover. It runs after the stealth scripts, so its values win where both set
+// one. %s is the profile's settings as JSON.
+const profileScript = `
+(function() {
+    'use strict';
+    const fp = %s;
+
+    const define = (obj, prop, value) => {
+        try {
+            Object.defineProperty(obj, prop, { get: () => value, configurable: true });
+        } catch (e) {}
+    };
+
+    define(navigator, 'platform', fp.platform);
+    define(navigator, 'vendor', fp.vendor);
+    define(navigator, 'languages', Object.freeze(fp.languages.slice()));
+    define(navigator, 'language', fp.languages[0]);
+    define(navigator, 'hardwareConcurrency', fp.hardwareConcurrency);
+    define(navigator, 'maxTouchPoints', fp.maxTouchPoints);
+    if (fp.deviceMemory > 0) {
+        define(navigator, 'deviceMemory', fp.deviceMemory);
+    } else {
+        try { delete Navigator.prototype.deviceMemory; } catch (e) {}
+        try { delete navigator.deviceMemory; } catch (e) {}
+    }
+
+    // Browsers without client hints have no navigator.userAgentData
+    if (!fp.clientHints) {
+        try { delete Navigator.prototype.userAgentData; } catch (e) {}
+    }
+
+    const getParameterProxyHandler = {
+        apply: function(target, ctx, args) {
+            if (args[0] === 37445) {
+                return fp.webglVendor;
+            }
+            if (args[0] === 37446) {
+                return fp.webglRenderer;
+            }
+            return Reflect.apply(target, ctx, args);
+        }
+    };
+    try {
+        WebGLRenderingContext.prototype.getParameter = new Proxy(WebGLRenderingContext.prototype.getParameter, getParameterProxyHandler);
+    } catch (e) {}
+    try {
+        WebGL2RenderingContext.prototype.getParameter = new Proxy(WebGL2RenderingContext.prototype.getParameter, getParameterProxyHandler);
+    } catch (e) {}
+})();
+`
+
+// Apply makes the page present itself as the profile's device, and returns the user
+// agent it reports. Call it before navigating: the script overrides only apply to
+// documents loaded afterwards.
+func Apply(page *rod.Page, p *Profile) (string, error) {
+	version := fallbackVersion
+	if v, err := page.Browser().Version(); err == nil {
+		version = browserVersion(v.Product)
+	}
+	userAgent := p.userAgent(version)
+
+	if err := p.userAgentOverride(userAgent, version).Call(page); err != nil {
+		return "", fmt.Errorf("set user agent: %w", err)
+	}
+	if err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+		Width:             p.Width,
+		Height:            p.Height,
+		DeviceScaleFactor: p.DeviceScaleFactor,
+		Mobile:            p.Mobile,
+		ScreenWidth:       &p.Width,
+		ScreenHeight:      &p.Height,
+	}); err != nil {
+		return "", fmt.Errorf("set viewport: %w", err)
+	}
+	if p.MaxTouchPoints > 0 {
+		if err := (proto.EmulationSetTouchEmulationEnabled{Enabled: true, MaxTouchPoints: &p.MaxTouchPoints}).Call(page); err != nil {
+			return "", fmt.Errorf("enable touch: %w", err)
+		}
+	}
+	if err := (proto.EmulationSetTimezoneOverride{TimezoneID: p.Timezone}).Call(page); err != nil {
+		return "", fmt.Errorf("set timezone: %w", err)
+	}
+	if err := (proto.EmulationSetLocaleOverride{Locale: p.Locale()}).Call(page); err != nil {
+		return "", fmt.Errorf("set locale: %w", err)
+	}
+	if _, err := page.EvalOnNewDocument(p.script()); err != nil {
+		return "", fmt.Errorf("add profile script: %w", err)
+	}
+	return userAgent, nil
+}
+
+// userAgent returns the profile's user agent for a browser version.
+func (p *Profile) userAgent(version string) string {
+	if !strings.Contains(p.UserAgent, "%s") {
+		return p.UserAgent
+	}
+	major, _, _ := strings.Cut(version, ".")
+	return fmt.Sprintf(p.UserAgent, major)
+}
+
+// userAgentOverride returns the user agent, Accept-Language and client hints override.
+// Without client hint metadata the browser sends no Sec-CH-UA headers, as Safari doesn't.
+func (p *Profile) userAgentOverride(userAgent, version string) proto.EmulationSetUserAgentOverride {
+	override := proto.EmulationSetUserAgentOverride{
+		UserAgent:      userAgent,
+		AcceptLanguage: p.AcceptLanguage(),
+		Platform:       p.Platform,
+	}
+	if ch := p.ClientHints; ch != nil {
+		major, _, _ := strings.Cut(version, ".")
+		override.UserAgentMetadata = &proto.EmulationUserAgentMetadata{
+			Brands: []*proto.EmulationUserAgentBrandVersion{
+				{Brand: "Google Chrome", Version: major},
+				{Brand: "Chromium", Version: major},
+				{Brand: "Not_A Brand", Version: "24"},
+			},
+			FullVersionList: []*proto.EmulationUserAgentBrandVersion{
+				{Brand: "Google Chrome", Version: version},
+				{Brand: "Chromium", Version: version},
+				{Brand: "Not_A Brand", Version: "24.0.0.0"},
+			},
+			Platform:        ch.Platform,
+			PlatformVersion: ch.PlatformVersion,
+			Architecture:    ch.Architecture,
+			Bitness:         ch.Bitness,
+			Model:           ch.Model,
+			Mobile:          p.Mobile,
+		}
+	}
+	return override
+}
+
+// script returns the profile's navigator and WebGL overrides.
+func (p *Profile) script() string {
+	settings, _ := json.Marshal(map[string]any{
+		"platform":            p.Platform,
+		"vendor":              p.Vendor,
+		"languages":           p.Languages,
+		"hardwareConcurrency": p.HardwareConcurrency,
+		"deviceMemory":        p.DeviceMemory,
+		"maxTouchPoints":      p.MaxTouchPoints,
+		"clientHints":         p.ClientHints != nil,
+		"webglVendor":         p.WebGLVendor,
+		"webglRenderer":       p.WebGLRenderer,
+	})
+	return fmt.Sprintf(profileScript, settings)
+}
+
+// browserVersion extracts the version from a product string like "HeadlessChrome/131.0.6778.85".
+func browserVersion(product string) string {
+	if _, version, ok := strings.Cut(product, "/"); ok && version != "" {
+		return version
+	}
+	return fallbackVersion
+}
@@ -0,0 +1,177 @@
+// Package fingerprint provides named browser fingerprint profiles. A profile sets the
+// user agent, client hints, viewport, timezone, languages and hardware properties of a
+// page together, so they describe one plausible device instead of a mix that bot
+// detection can flag.
+package fingerprint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Profile names.
+const (
+	ChromeWindows = "chrome-windows" // Desktop Chrome on Windows 10/11
+	SafariMac     = "safari-mac"     // Safari on macOS (Chrome engine, Safari-like surface)
+	ChromeAndroid = "chrome-android" // Chrome on an Android phone
+)
+
+// Default is the profile used when neither the request nor the configuration picks one.
+const Default = ChromeWindows
+
+// ClientHints are the User-Agent Client Hints (Sec-CH-UA-* headers and
+// navigator.userAgentData) a profile reports. Brands and versions come from the
+// running browser.
+type ClientHints struct {
+	Platform        string
+	PlatformVersion string
+	Architecture    string
+	Bitness         string
+	Model           string
+}
+
+// Profile describes the device a page presents itself as.
+type Profile struct {
+	Name      string
+	UserAgent string // %s is replaced with the browser's major version
+	Platform  string // navigator.platform
+	Vendor    string // navigator.vendor
+
+	// ClientHints is nil for browsers that don't send client hints (Safari)
+	ClientHints *ClientHints
+
+	Width             int
+	Height            int
+	DeviceScaleFactor float64
+	Mobile            bool
+	MaxTouchPoints    int
+
+	Timezone  string   // IANA timezone
+	Languages []string // navigator.languages; the first is also the locale
+
+	HardwareConcurrency int
+	DeviceMemory        int // GB; 0 for browsers without navigator.deviceMemory (Safari)
+
+	WebGLVendor   string // UNMASKED_VENDOR_WEBGL
+	WebGLRenderer string // UNMASKED_RENDERER_WEBGL
+}
+
+// profiles are ordered for rotation: a host that keeps challenging one profile is
+// moved to the next.
+var profiles = []*Profile{
+	{
+		Name:      ChromeWindows,
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s.0.0.0 Safari/537.36",
+		Platform:  "Win32",
+		Vendor:    "Google Inc.",
+		ClientHints: &ClientHints{
+			Platform:        "Windows",
+			PlatformVersion: "15.0.0",
+			Architecture:    "x86",
+			Bitness:         "64",
+		},
+		Width:               1920,
+		Height:              1080,
+		DeviceScaleFactor:   1,
+		Timezone:            "America/New_York",
+		Languages:           []string{"en-US", "en"},
+		HardwareConcurrency: 8,
+		DeviceMemory:        8,
+		WebGLVendor:         "Google Inc. (Intel)",
+		WebGLRenderer:       "ANGLE (Intel, Intel(R) UHD Graphics 630 (0x00003E9B) Direct3D11 vs_5_0 ps_5_0, D3D11)",
+	},
+	{
+		Name:                SafariMac,
+		UserAgent:           "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.6 Safari/605.1.15",
+		Platform:            "MacIntel",
+		Vendor:              "Apple Computer, Inc.",
+		Width:               1440,
+		Height:              900,
+		DeviceScaleFactor:   2,
+		Timezone:            "America/Los_Angeles",
+		Languages:           []string{"en-US"},
+		HardwareConcurrency: 8,
+		WebGLVendor:         "Apple Inc.",
+		WebGLRenderer:       "Apple GPU",
+	},
+	{
+		Name:      ChromeAndroid,
+		UserAgent: "Mozilla/5.0 (Linux; Android 10; K) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s.0.0.0 Mobile Safari/537.36",
+		Platform:  "Linux armv81",
+		Vendor:    "Google Inc.",
+		ClientHints: &ClientHints{
+			Platform:        "Android",
+			PlatformVersion: "14.0.0",
+			Model:           "Pixel 8",
+		},
+		Width:               412,
+		Height:              915,
+		DeviceScaleFactor:   2.625,
+		Mobile:              true,
+		MaxTouchPoints:      5,
+		Timezone:            "America/Chicago",
+		Languages:           []string{"en-US", "en"},
+		HardwareConcurrency: 8,
+		DeviceMemory:        8,
+		WebGLVendor:         "ARM",
+		WebGLRenderer:       "Mali-G715",
+	},
+}
+
+// Get returns the named profile.
+func Get(name string) (*Profile, bool) {
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Lookup returns the named profile, or an error listing the known profiles.
+func Lookup(name string) (*Profile, error) {
+	if p, ok := Get(name); ok {
+		return p, nil
+	}
+	return nil, fmt.Errorf("unknown fingerprint profile %q (known: %s)", name, strings.Join(Names(), ", "))
+}
+
+// Names returns the profile names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// next returns the profile after name in rotation order.
+func next(name string) string {
+	for i, p := range profiles {
+		if p.Name == name {
+			return profiles[(i+1)%len(profiles)].Name
+		}
+	}
+	return Default
+}
+
+// Locale returns the profile's ICU locale (e.g. "en_US").
+func (p *Profile) Locale() string {
+	return strings.ReplaceAll(p.Languages[0], "-", "_")
+}
+
+// AcceptLanguage returns the Accept-Language header matching the profile's languages.
+func (p *Profile) AcceptLanguage() string {
+	parts := make([]string, len(p.Languages))
+	for i, lang := range p.Languages {
+		if i == 0 {
+			parts[i] = lang
+			continue
+		}
+		// Decreasing weights as browsers send them: q=0.9, q=0.8, ...
+		parts[i] = fmt.Sprintf("%s;q=%.1f", lang, max(0.1, 1-0.1*float64(i)))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,102 @@
+package fingerprint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProfiles_Consistent(t *testing.T) {
+	for _, name := range Names() {
+		p, err := Lookup(name)
+		if err != nil {
+			t.Fatalf("Lookup(%q) error = %v", name, err)
+		}
+		ua := p.userAgent("131.0.6778.85")
+
+		// Every property describes the same device as the user agent
+		switch {
+		case strings.Contains(ua, "Windows"):
+			if p.Platform != "Win32" || p.ClientHints == nil || p.ClientHints.Platform != "Windows" || p.Mobile {
+				t.Errorf("%s: Windows user agent with platform %q, hints %+v", name, p.Platform, p.ClientHints)
+			}
+		case strings.Contains(ua, "Macintosh"):
+			if p.Platform != "MacIntel" || p.ClientHints != nil || p.DeviceMemory != 0 || !strings.HasPrefix(p.Vendor, "Apple") {
+				t.Errorf("%s: Safari user agent with platform %q, vendor %q, hints %+v", name, p.Platform, p.Vendor, p.ClientHints)
+			}
+		case strings.Contains(ua, "Android"):
+			if !p.Mobile || p.MaxTouchPoints == 0 || p.ClientHints == nil || p.ClientHints.Platform != "Android" || p.Width > 600 {
+				t.Errorf("%s: Android user agent on a non-mobile device: %+v", name, p)
+			}
+		default:
+			t.Errorf("%s: unexpected user agent %q", name, ua)
+		}
+		if p.Timezone == "" || len(p.Languages) == 0 || p.HardwareConcurrency == 0 || p.WebGLRenderer == "" {
+			t.Errorf("%s: incomplete profile %+v", name, p)
+		}
+	}
+}
+
+func TestLookup_Unknown(t *testing.T) {
+	_, err := Lookup("firefox-linux")
+	if err == nil || !strings.Contains(err.Error(), "chrome-windows") {
+		t.Errorf("Lookup() error = %v, want one listing the known profiles", err)
+	}
+}
+
+func TestProfile_UserAgent(t *testing.T) {
+	p, _ := Get(ChromeWindows)
+	if got := p.userAgent("131.0.6778.85"); !strings.Contains(got, "Chrome/131.0.0.0 ") {
+		t.Errorf("userAgent() = %q, want the reduced Chrome 131 version", got)
+	}
+
+	safari, _ := Get(SafariMac)
+	if got := safari.userAgent("131.0.6778.85"); strings.Contains(got, "Chrome") {
+		t.Errorf("Safari userAgent() = %q, mentions Chrome", got)
+	}
+
+	if got := browserVersion("HeadlessChrome/131.0.6778.85"); got != "131.0.6778.85" {
+		t.Errorf("browserVersion() = %q", got)
+	}
+	if got := browserVersion("garbage"); got != fallbackVersion {
+		t.Errorf("browserVersion(garbage) = %q, want fallback", got)
+	}
+}
+
+func TestProfile_UserAgentOverride(t *testing.T) {
+	android, _ := Get(ChromeAndroid)
+	o := android.userAgentOverride("ua", "131.0.6778.85")
+	if o.UserAgentMetadata == nil || !o.UserAgentMetadata.Mobile || o.UserAgentMetadata.Model != "Pixel 8" {
+		t.Fatalf("metadata = %+v, want mobile Pixel 8 hints", o.UserAgentMetadata)
+	}
+	if got := o.UserAgentMetadata.Brands[0].Version; got != "131" {
+		t.Errorf("brand version = %q, want major version 131", got)
+	}
+	if got := o.UserAgentMetadata.FullVersionList[0].Version; got != "131.0.6778.85" {
+		t.Errorf("full version = %q", got)
+	}
+	if o.AcceptLanguage != "en-US,en;q=0.9" || o.Platform != "Linux armv81" {
+		t.Errorf("AcceptLanguage = %q, Platform = %q", o.AcceptLanguage, o.Platform)
+	}
+
+	// Safari sends no client hints
+	safari, _ := Get(SafariMac)
+	if o := safari.userAgentOverride("ua", "131.0.6778.85"); o.UserAgentMetadata != nil {
+		t.Errorf("Safari metadata = %+v, want none", o.UserAgentMetadata)
+	}
+}
+
+func TestProfile_Script(t *testing.T) {
+	p, _ := Get(SafariMac)
+	script := p.script()
+	for _, want := range []string{`"platform":"MacIntel"`, `"clientHints":false`, `"webglRenderer":"Apple GPU"`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %s", want)
+		}
+	}
+	if strings.Contains(script, "%!") {
+		t.Error("script has a formatting error")
+	}
+	if got := p.Locale(); got != "en_US" {
+		t.Errorf("Locale() = %q, want en_US", got)
+	}
+}
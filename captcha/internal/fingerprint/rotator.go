@@ -0,0 +1,90 @@
+package fingerprint
+
+import (
+	"strings"
+	"sync"
+)
+
+// maxHosts bounds the hosts a Rotator tracks. Only hosts that challenged a profile
+// are tracked, so the bound is rarely reached.
+const maxHosts = 10000
+
+// Rotator picks the profile for pooled requests to each host. It starts every host
+// on a default profile and moves a host to the next profile after a number of
+// consecutive requests that couldn't get past its challenge. It is safe for
+// concurrent use.
+type Rotator struct {
+	defaultProfile string
+	after          int // Consecutive failures before rotating (0 disables rotation)
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	profile  string
+	failures int
+}
+
+// NewRotator creates a rotator starting hosts on defaultProfile and rotating after
+// the given number of consecutive failures.
+func NewRotator(defaultProfile string, after int) *Rotator {
+	return &Rotator{
+		defaultProfile: defaultProfile,
+		after:          after,
+		hosts:          make(map[string]*hostState),
+	}
+}
+
+// Profile returns the profile to load host with.
+func (r *Rotator) Profile(host string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.hosts[strings.ToLower(host)]; ok {
+		return s.profile
+	}
+	return r.defaultProfile
+}
+
+// Report records whether a request to host with profile got past any challenge,
+// and returns the profile the host moved to if this failure rotated it ("" if not).
+// Reports for a profile the host has already moved on from are ignored.
+func (r *Rotator) Report(host, profile string, failed bool) string {
+	host = strings.ToLower(host)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.hosts[host]
+	if !ok {
+		s = &hostState{profile: r.defaultProfile}
+	}
+	if s.profile != profile {
+		return ""
+	}
+
+	if !failed {
+		s.failures = 0
+		if s.profile == r.defaultProfile {
+			delete(r.hosts, host)
+		}
+		return ""
+	}
+
+	if !ok {
+		if len(r.hosts) >= maxHosts {
+			for h := range r.hosts {
+				delete(r.hosts, h)
+				break
+			}
+		}
+		r.hosts[host] = s
+	}
+	s.failures++
+	if r.after <= 0 || s.failures < r.after {
+		return ""
+	}
+	s.profile = next(s.profile)
+	s.failures = 0
+	return s.profile
+}
@@ -0,0 +1,65 @@
+package fingerprint
+
+import "testing"
+
+func TestRotator_RotatesAfterRepeatedFailures(t *testing.T) {
+	r := NewRotator(ChromeWindows, 2)
+
+	if got := r.Profile("Example.com"); got != ChromeWindows {
+		t.Fatalf("Profile() = %q, want default", got)
+	}
+	if next := r.Report("example.com", ChromeWindows, true); next != "" {
+		t.Errorf("Report() rotated after one failure to %q", next)
+	}
+	if next := r.Report("example.com", ChromeWindows, true); next != SafariMac {
+		t.Errorf("Report() = %q, want rotation to %q", next, SafariMac)
+	}
+	if got := r.Profile("example.com"); got != SafariMac {
+		t.Errorf("Profile() = %q after rotation, want %q", got, SafariMac)
+	}
+
+	// Other hosts keep the default
+	if got := r.Profile("other.com"); got != ChromeWindows {
+		t.Errorf("Profile(other) = %q, want default", got)
+	}
+
+	// Reports from requests that still used the old profile don't count
+	r.Report("example.com", ChromeWindows, true)
+	r.Report("example.com", ChromeWindows, true)
+	if got := r.Profile("example.com"); got != SafariMac {
+		t.Errorf("Profile() = %q after stale reports, want %q", got, SafariMac)
+	}
+}
+
+func TestRotator_SuccessResetsFailures(t *testing.T) {
+	r := NewRotator(ChromeWindows, 2)
+
+	r.Report("example.com", ChromeWindows, true)
+	r.Report("example.com", ChromeWindows, false)
+	if next := r.Report("example.com", ChromeWindows, true); next != "" {
+		t.Errorf("Report() rotated to %q, want failures reset by the success", next)
+	}
+	r.Report("example.com", ChromeWindows, false)
+	if len(r.hosts) != 0 {
+		t.Errorf("tracking %d hosts, want none once the default profile gets through", len(r.hosts))
+	}
+}
+
+func TestRotator_Disabled(t *testing.T) {
+	r := NewRotator(SafariMac, 0)
+	for range 5 {
+		if next := r.Report("example.com", SafariMac, true); next != "" {
+			t.Fatalf("Report() rotated to %q with rotation disabled", next)
+		}
+	}
+	if got := r.Profile("example.com"); got != SafariMac {
+		t.Errorf("Profile() = %q, want %q", got, SafariMac)
+	}
+}
+
+func TestRotator_WrapsAround(t *testing.T) {
+	r := NewRotator(ChromeAndroid, 1)
+	if next := r.Report("example.com", ChromeAndroid, true); next != ChromeWindows {
+		t.Errorf("Report() = %q, want rotation back to %q", next, ChromeWindows)
+	}
+}
@@ -39,6 +39,64 @@ type WaitCondition struct {
 	Load        bool   `json:"load,omitempty"`        // Wait for page load event
 }
 
+// Browser action types.
+const (
+	ActionClick          = "click"            // Click an element (repeat to click "Load more" until it's gone)
+	ActionScrollToBottom = "scroll_to_bottom" // Scroll to the bottom, repeating while the page grows (infinite scroll)
+	ActionType           = "type"             // Type text into an input
+	ActionSelect         = "select"           // Choose an option in a <select>
+	ActionHover          = "hover"            // Hover over an element
+	ActionWait           = "wait"             // Wait for a selector to appear or for a delay
+	ActionEvaluate       = "evaluate"         // Run a safe-listed JavaScript snippet by name
+)
+
+// Action is a scripted browser interaction run after the page loads and before capture.
+type Action struct {
+	Type     string `json:"type"`               // One of the Action* constants
+	Selector string `json:"selector,omitempty"` // CSS selector (click, type, select, hover, wait)
+	Text     string `json:"text,omitempty"`     // Text to type (type) or option text to choose (select)
+	Script   string `json:"script,omitempty"`   // Safe-listed snippet name (evaluate)
+	Repeat   int    `json:"repeat,omitempty"`   // Max repetitions (click, scroll_to_bottom); click stops when the element is gone
+	Delay    int    `json:"delay,omitempty"`    // Delay in ms after the action (after each repetition when repeating)
+	Timeout  int    `json:"timeout,omitempty"`  // Max time in ms to wait for the selector
+	Optional bool   `json:"optional,omitempty"` // Continue if the action fails (e.g., a modal that isn't always shown)
+}
+
+// NetworkCapture selects XHR/fetch responses to record while the page loads.
+// A response is recorded when its URL matches one of URLPatterns (regular expressions)
+// or its content type contains one of ContentTypes. With neither set, JSON responses are recorded.
+type NetworkCapture struct {
+	URLPatterns  []string `json:"urlPatterns,omitempty"`  // Regular expressions matched against the response URL
+	ContentTypes []string `json:"contentTypes,omitempty"` // Substrings matched against the response MIME type (e.g., "json")
+	MaxResponses int      `json:"maxResponses,omitempty"` // Max responses to record (default 50)
+	MaxBodySize  int      `json:"maxBodySize,omitempty"`  // Max bytes of each response body to keep (default 1MB)
+}
+
+// ScreenshotCapture requests full-page screenshots split into segments, so long pages
+// stay legible to vision models that downscale large images.
+type ScreenshotCapture struct {
+	SegmentHeight int    `json:"segmentHeight,omitempty"` // CSS pixels per segment (default 2000)
+	MaxSegments   int    `json:"maxSegments,omitempty"`   // Max segments from the top of the page (default 8)
+	Format        string `json:"format,omitempty"`        // "jpeg" (default) or "png"
+	Quality       int    `json:"quality,omitempty"`       // JPEG quality 1-100 (default 80)
+}
+
+// Resource policy profiles.
+const (
+	ResourceProfileFull     = "full"      // Load everything (default)
+	ResourceProfileNoMedia  = "no_media"  // Block images, media, fonts and ad/analytics domains
+	ResourceProfileTextOnly = "text_only" // Also block stylesheets and other non-content resources
+)
+
+// ResourcePolicy selects page resources to block while rendering. Blocked requests
+// fail as if blocked by an ad blocker, which speeds up rendering considerably.
+type ResourcePolicy struct {
+	Profile            string   `json:"profile,omitempty"`            // One of the ResourceProfile* constants
+	BlockResourceTypes []string `json:"blockResourceTypes,omitempty"` // Additional resource types to block (image, font, script, ...)
+	BlockDomains       []string `json:"blockDomains,omitempty"`       // Hosts to block, including their subdomains
+	BlockURLPatterns   []string `json:"blockUrlPatterns,omitempty"`   // URL globs to block (* matches anything, ? one character)
+}
+
 // SessionOptions specifies options for creating a browser session.
 type SessionOptions struct {
 	Headless     *bool        `json:"headless,omitempty"`
@@ -47,6 +105,8 @@ type SessionOptions struct {
 	UserAgent    string       `json:"userAgent,omitempty"`
 	Proxy        *ProxyConfig `json:"proxy,omitempty"`
 	UserID       string       `json:"-"` // Set from auth context, not request body
+
+	FingerprintProfile string `json:"fingerprintProfile,omitempty"` // Fingerprint profile for the session's lifetime
 }
 
 // SolveRequest is a FlareSolverr-compatible request.
@@ -65,7 +125,17 @@ type SolveRequest struct {
 	UserAgent  string            `json:"userAgent,omitempty"`  // Custom user agent
 	Headers    map[string]string `json:"headers,omitempty"`    // Custom headers
 	WaitFor    *WaitCondition    `json:"waitFor,omitempty"`    // Wait condition
+	Actions    []Action          `json:"actions,omitempty"`    // Browser actions run before capture
 	Screenshot bool              `json:"screenshot,omitempty"` // Capture screenshot
+
+	CaptureNetwork *NetworkCapture `json:"captureNetwork,omitempty"` // Record matching XHR/fetch responses
+	ResourcePolicy *ResourcePolicy `json:"resourcePolicy,omitempty"` // Resources to block while rendering
+
+	CaptureScreenshots *ScreenshotCapture `json:"captureScreenshots,omitempty"` // Full-page segmented screenshots
+
+	MaxSolverCostUSD *float64 `json:"maxSolverCostUsd,omitempty"` // Most all paid CAPTCHA solve attempts may cost together (omit for no limit)
+
+	FingerprintProfile string `json:"fingerprintProfile,omitempty"` // Fingerprint profile to load the page with (default rotates per host)
 }
 
 // HumaSolveRequest wraps SolveRequest for Huma API.
@@ -10,6 +10,48 @@ type Solution struct {
 	Response   string            `json:"response"`             // HTML content
 	Title      string            `json:"title,omitempty"`      // Page title
 	Screenshot string            `json:"screenshot,omitempty"` // Base64-encoded screenshot
+
+	Network []NetworkResponse `json:"network,omitempty"` // Recorded XHR/fetch responses (when captureNetwork is set)
+
+	Screenshots []Screenshot `json:"screenshots,omitempty"` // Full-page segments, top to bottom (when captureScreenshots is set)
+
+	Clearance *Clearance `json:"clearance,omitempty"` // Challenge clearance the page was loaded with (Refyne extension)
+
+	FingerprintProfile string `json:"fingerprintProfile,omitempty"` // Fingerprint profile the page was loaded with (Refyne extension)
+}
+
+// Clearance is the cookie set and user agent that passed a Cloudflare or DDoS-Guard
+// challenge. Requests to the same host through the same exit can reuse it until it expires.
+type Clearance struct {
+	Cookies    []Cookie `json:"cookies"`
+	UserAgent  string   `json:"userAgent"`
+	ObtainedAt int64    `json:"obtainedAt"` // Unix timestamp ms
+	ExpiresAt  int64    `json:"expiresAt"`  // Unix timestamp ms
+}
+
+// Screenshot is one segment of a full-page screenshot.
+type Screenshot struct {
+	Data     string `json:"data"`     // Base64-encoded image
+	MimeType string `json:"mimeType"` // image/jpeg or image/png
+	Offset   int    `json:"offset"`   // CSS pixels from the top of the page
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// NetworkResponse is an XHR/fetch response recorded during the page load.
+type NetworkResponse struct {
+	URL             string            `json:"url"`
+	Method          string            `json:"method"`
+	Status          int               `json:"status"`
+	StatusText      string            `json:"statusText,omitempty"`
+	MimeType        string            `json:"mimeType,omitempty"`
+	RequestHeaders  map[string]string `json:"requestHeaders,omitempty"`
+	RequestBody     string            `json:"requestBody,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	Body            string            `json:"body"`
+	BodyTruncated   bool              `json:"bodyTruncated,omitempty"` // Body was cut at maxBodySize
+	StartedAt       int64             `json:"startedAt"`               // Unix timestamp ms when the request was sent
+	DurationMS      int64             `json:"durationMs"`              // Time from request to response end
 }
 
 // UsageInfo contains usage tracking data (Refyne extension).
@@ -19,6 +61,8 @@ type UsageInfo struct {
 	ChallengeType  string  `json:"challengeType,omitempty"`
 	BrowserCostUSD float64 `json:"browserCostUsd,omitempty"`
 	SolverCostUSD  float64 `json:"solverCostUsd,omitempty"`
+
+	BlockedRequests int `json:"blockedRequests,omitempty"` // Requests blocked by the resource policy
 }
 
 // SolveResponse is a FlareSolverr-compatible response.
@@ -52,6 +96,8 @@ type SessionInfo struct {
 	RequestCount int    `json:"requestCount"`       // Number of requests made
 	UserAgent    string `json:"userAgent,omitempty"` // User agent if set
 	Proxy        string `json:"proxy,omitempty"`     // Proxy URL if set (masked)
+
+	FingerprintProfile string `json:"fingerprintProfile,omitempty"` // Fingerprint profile the session presents
 }
 
 // SessionsResponse is returned for session management commands.
@@ -0,0 +1,251 @@
+// Package network records XHR/fetch responses made by a page while it loads.
+package network
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+// Limits on recorded responses, so a chatty page can't bloat the solve response.
+const (
+	DefaultMaxResponses = 50
+	MaxResponses        = 200
+	DefaultMaxBodySize  = 1 << 20 // 1MB
+	MaxBodySize         = 5 << 20 // 5MB
+	MaxURLPatterns      = 20
+)
+
+// defaultContentTypes are recorded when no URL patterns or content types are given.
+var defaultContentTypes = []string{"json"}
+
+// Filter decides which responses are recorded.
+type Filter struct {
+	patterns     []*regexp.Regexp
+	contentTypes []string
+}
+
+// NewFilter compiles the capture configuration into a filter.
+func NewFilter(cfg *models.NetworkCapture) (*Filter, error) {
+	if len(cfg.URLPatterns) > MaxURLPatterns {
+		return nil, fmt.Errorf("too many url patterns: %d (max %d)", len(cfg.URLPatterns), MaxURLPatterns)
+	}
+	if cfg.MaxResponses < 0 || cfg.MaxResponses > MaxResponses {
+		return nil, fmt.Errorf("maxResponses must be between 0 and %d", MaxResponses)
+	}
+	if cfg.MaxBodySize < 0 || cfg.MaxBodySize > MaxBodySize {
+		return nil, fmt.Errorf("maxBodySize must be between 0 and %d", MaxBodySize)
+	}
+
+	f := &Filter{}
+	for _, p := range cfg.URLPatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url pattern %q: %w", p, err)
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	for _, ct := range cfg.ContentTypes {
+		if ct = strings.ToLower(strings.TrimSpace(ct)); ct != "" {
+			f.contentTypes = append(f.contentTypes, ct)
+		}
+	}
+	if len(f.patterns) == 0 && len(f.contentTypes) == 0 {
+		f.contentTypes = defaultContentTypes
+	}
+	return f, nil
+}
+
+// Match reports whether a response with this URL and MIME type should be recorded.
+func (f *Filter) Match(url, mimeType string) bool {
+	for _, re := range f.patterns {
+		if re.MatchString(url) {
+			return true
+		}
+	}
+	mimeType = strings.ToLower(mimeType)
+	for _, ct := range f.contentTypes {
+		if strings.Contains(mimeType, ct) {
+			return true
+		}
+	}
+	return false
+}
+
+// pending is a request seen on the page that hasn't finished loading yet.
+type pending struct {
+	request   *proto.NetworkRequest
+	startedAt proto.TimeSinceEpoch
+	sentAt    proto.MonotonicTime
+	response  *proto.NetworkResponse
+}
+
+// Recorder records matching XHR/fetch responses on a page until stopped.
+type Recorder struct {
+	page         *rod.Page
+	filter       *Filter
+	maxResponses int
+	maxBodySize  int
+	logger       *slog.Logger
+
+	mu        sync.Mutex
+	pending   map[proto.NetworkRequestID]*pending
+	responses []models.NetworkResponse
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Start begins recording on page. It must be called before navigating so
+// requests made during the page load are seen. Call Stop to get the responses.
+func Start(ctx context.Context, page *rod.Page, cfg *models.NetworkCapture, logger *slog.Logger) (*Recorder, error) {
+	filter, err := NewFilter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	r := &Recorder{
+		page:         page.Context(ctx),
+		filter:       filter,
+		maxResponses: cfg.MaxResponses,
+		maxBodySize:  cfg.MaxBodySize,
+		logger:       logger,
+		pending:      make(map[proto.NetworkRequestID]*pending),
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+	if r.maxResponses == 0 {
+		r.maxResponses = DefaultMaxResponses
+	}
+	if r.maxBodySize == 0 {
+		r.maxBodySize = DefaultMaxBodySize
+	}
+
+	// EachEvent enables the Network domain now, and restores it when the wait ends
+	wait := r.page.EachEvent(
+		r.onRequest,
+		r.onResponse,
+		r.onFinished,
+		func(e *proto.NetworkLoadingFailed) { r.forget(e.RequestID) },
+	)
+	go func() {
+		defer close(r.done)
+		wait()
+	}()
+	return r, nil
+}
+
+// Stop ends recording and returns the recorded responses in request order.
+func (r *Recorder) Stop() []models.NetworkResponse {
+	r.cancel()
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sort.SliceStable(r.responses, func(i, j int) bool {
+		return r.responses[i].StartedAt < r.responses[j].StartedAt
+	})
+	return r.responses
+}
+
+func (r *Recorder) onRequest(e *proto.NetworkRequestWillBeSent) {
+	if e.Type != proto.NetworkResourceTypeXHR && e.Type != proto.NetworkResourceTypeFetch {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending[e.RequestID] = &pending{request: e.Request, startedAt: e.WallTime, sentAt: e.Timestamp}
+}
+
+func (r *Recorder) onResponse(e *proto.NetworkResponseReceived) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pending[e.RequestID]
+	if !ok {
+		return
+	}
+	if !r.filter.Match(e.Response.URL, e.Response.MIMEType) {
+		delete(r.pending, e.RequestID)
+		return
+	}
+	p.response = e.Response
+}
+
+func (r *Recorder) onFinished(e *proto.NetworkLoadingFinished) {
+	r.mu.Lock()
+	p, ok := r.pending[e.RequestID]
+	delete(r.pending, e.RequestID)
+	full := len(r.responses) >= r.maxResponses
+	r.mu.Unlock()
+	if !ok || p.response == nil || full {
+		return
+	}
+
+	body, err := proto.NetworkGetResponseBody{RequestID: e.RequestID}.Call(r.page)
+	if err != nil {
+		r.logger.Debug("failed to read response body", "url", p.response.URL, "error", err)
+		return
+	}
+	content := body.Body
+	if body.Base64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			r.logger.Debug("failed to decode response body", "url", p.response.URL, "error", err)
+			return
+		}
+		content = string(decoded)
+	}
+
+	resp := models.NetworkResponse{
+		URL:             p.response.URL,
+		Method:          p.request.Method,
+		Status:          p.response.Status,
+		StatusText:      p.response.StatusText,
+		MimeType:        p.response.MIMEType,
+		RequestHeaders:  headers(p.request.Headers),
+		RequestBody:     p.request.PostData,
+		ResponseHeaders: headers(p.response.Headers),
+		Body:            content,
+		StartedAt:       p.startedAt.Time().UnixMilli(),
+		DurationMS:      int64((e.Timestamp - p.sentAt) * 1000),
+	}
+	if len(resp.Body) > r.maxBodySize {
+		resp.Body = resp.Body[:r.maxBodySize]
+		resp.BodyTruncated = true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.responses) < r.maxResponses {
+		r.responses = append(r.responses, resp)
+	}
+}
+
+func (r *Recorder) forget(id proto.NetworkRequestID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, id)
+}
+
+// headers flattens CDP headers into a string map.
+func headers(h proto.NetworkHeaders) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		out[k] = v.Str()
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+func TestNewFilter_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.NetworkCapture
+		wantErr bool
+	}{
+		{"empty", models.NetworkCapture{}, false},
+		{"patterns and types", models.NetworkCapture{URLPatterns: []string{`/api/`}, ContentTypes: []string{"json"}}, false},
+		{"invalid pattern", models.NetworkCapture{URLPatterns: []string{`(`}}, true},
+		{"too many responses", models.NetworkCapture{MaxResponses: MaxResponses + 1}, true},
+		{"negative body size", models.NetworkCapture{MaxBodySize: -1}, true},
+		{"body size too large", models.NetworkCapture{MaxBodySize: MaxBodySize + 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFilter(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      models.NetworkCapture
+		url      string
+		mimeType string
+		want     bool
+	}{
+		{"default records json", models.NetworkCapture{}, "https://example.com/x", "application/json", true},
+		{"default records json variants", models.NetworkCapture{}, "https://example.com/x", "application/vnd.api+json", true},
+		{"default skips html", models.NetworkCapture{}, "https://example.com/x", "text/html", false},
+		{"pattern match", models.NetworkCapture{URLPatterns: []string{`/graphql$`}}, "https://example.com/graphql", "text/plain", true},
+		{"pattern replaces default", models.NetworkCapture{URLPatterns: []string{`/graphql$`}}, "https://example.com/other", "application/json", false},
+		{"content type match is case insensitive", models.NetworkCapture{ContentTypes: []string{"Text/CSV"}}, "https://example.com/x", "text/csv; charset=utf-8", true},
+		{"pattern or content type", models.NetworkCapture{URLPatterns: []string{`/api/`}, ContentTypes: []string{"xml"}}, "https://example.com/feed", "application/xml", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(&tt.cfg)
+			if err != nil {
+				t.Fatalf("NewFilter() error = %v", err)
+			}
+			if got := f.Match(tt.url, tt.mimeType); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.url, tt.mimeType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	if got := headers(nil); got != nil {
+		t.Errorf("headers(nil) = %v, want nil", got)
+	}
+
+	got := headers(proto.NetworkHeaders{"Content-Type": gson.New("application/json")})
+	if got["Content-Type"] != "application/json" {
+		t.Errorf("headers() = %v", got)
+	}
+}
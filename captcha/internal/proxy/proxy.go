@@ -0,0 +1,90 @@
+// Package proxy routes pooled browser pages through a per-request proxy.
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+// Credentials authenticate with a proxy when it challenges a request.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Proxy is a validated proxy configuration.
+type Proxy struct {
+	// Server is the proxy in Chrome's --proxy-server form (scheme://host:port), without credentials.
+	Server string
+
+	// Credentials are nil when the proxy doesn't need authentication.
+	Credentials *Credentials
+}
+
+// Parse validates a proxy configuration. Credentials may be given in the URL
+// or as separate fields; the fields take precedence.
+func Parse(cfg *models.ProxyConfig) (*Proxy, error) {
+	u, err := url.Parse(strings.TrimSpace(cfg.URL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	switch scheme {
+	case "http", "https", "socks4", "socks5":
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q (use http, https, socks4 or socks5)", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("missing host")
+	}
+
+	p := &Proxy{Server: scheme + "://" + u.Host}
+
+	username, password := cfg.Username, cfg.Password
+	if username == "" && u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+	}
+	if username != "" {
+		// Chrome only answers authentication challenges from HTTP proxies
+		if strings.HasPrefix(scheme, "socks") {
+			return nil, errors.New("socks proxies with credentials aren't supported by the browser")
+		}
+		p.Credentials = &Credentials{Username: username, Password: password}
+	}
+	return p, nil
+}
+
+// Exit identifies the address a site sees requests through the proxy come from.
+// Rotating proxies commonly pick the exit IP from the username, so it is included.
+func (p *Proxy) Exit() string {
+	if p == nil {
+		return ""
+	}
+	_, host, _ := strings.Cut(p.Server, "://")
+	if p.Credentials != nil {
+		return p.Credentials.Username + "@" + host
+	}
+	return host
+}
+
+// Open creates an isolated browser context whose requests go through the proxy.
+// Pages created from the returned browser use the proxy; call close to dispose
+// of the context and its cookies.
+func Open(b *rod.Browser, p *Proxy) (*rod.Browser, func(), error) {
+	res, err := proto.TargetCreateBrowserContext{ProxyServer: p.Server}.Call(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proxied := *b
+	proxied.BrowserContextID = res.BrowserContextID
+	return &proxied, func() { _ = proxied.Close() }, nil
+}
@@ -0,0 +1,258 @@
+// Package resources blocks unneeded page resources (images, fonts, ads, analytics)
+// during rendering, so pages load faster and use less browser time.
+package resources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+	"github.com/jmylchreest/refyne-api/captcha/internal/proxy"
+)
+
+// Limits on custom block lists.
+const (
+	MaxBlockDomains     = 200
+	MaxBlockURLPatterns = 50
+)
+
+// resourceTypes maps the resource type names accepted in requests to CDP resource types.
+// Documents can't be blocked, as that would block the page itself.
+var resourceTypes = map[string]proto.NetworkResourceType{
+	"stylesheet":  proto.NetworkResourceTypeStylesheet,
+	"image":       proto.NetworkResourceTypeImage,
+	"media":       proto.NetworkResourceTypeMedia,
+	"font":        proto.NetworkResourceTypeFont,
+	"script":      proto.NetworkResourceTypeScript,
+	"texttrack":   proto.NetworkResourceTypeTextTrack,
+	"xhr":         proto.NetworkResourceTypeXHR,
+	"fetch":       proto.NetworkResourceTypeFetch,
+	"prefetch":    proto.NetworkResourceTypePrefetch,
+	"eventsource": proto.NetworkResourceTypeEventSource,
+	"websocket":   proto.NetworkResourceTypeWebSocket,
+	"manifest":    proto.NetworkResourceTypeManifest,
+	"ping":        proto.NetworkResourceTypePing,
+	"other":       proto.NetworkResourceTypeOther,
+}
+
+// trackerDomains are ad, analytics and tag manager hosts blocked by the
+// text_only and no_media profiles. They never carry page content.
+var trackerDomains = []string{
+	"google-analytics.com",
+	"googletagmanager.com",
+	"googlesyndication.com",
+	"googleadservices.com",
+	"doubleclick.net",
+	"adservice.google.com",
+	"connect.facebook.net",
+	"analytics.tiktok.com",
+	"bat.bing.com",
+	"clarity.ms",
+	"hotjar.com",
+	"segment.io",
+	"cdn.segment.com",
+	"mixpanel.com",
+	"amplitude.com",
+	"fullstory.com",
+	"newrelic.com",
+	"nr-data.net",
+	"scorecardresearch.com",
+	"quantserve.com",
+	"criteo.com",
+	"criteo.net",
+	"taboola.com",
+	"outbrain.com",
+	"adnxs.com",
+	"amazon-adsystem.com",
+	"ads-twitter.com",
+	"static.ads-twitter.com",
+}
+
+// profiles are the built-in resource policies.
+var profiles = map[string]struct {
+	types    []string
+	trackers bool
+}{
+	models.ResourceProfileFull:     {},
+	models.ResourceProfileNoMedia:  {types: []string{"image", "media", "font"}, trackers: true},
+	models.ResourceProfileTextOnly: {types: []string{"image", "media", "font", "stylesheet", "texttrack", "manifest", "ping", "prefetch"}, trackers: true},
+}
+
+// Policy decides which requests are blocked.
+type Policy struct {
+	types    map[proto.NetworkResourceType]bool
+	domains  []string
+	patterns []*regexp.Regexp
+}
+
+// Compile validates a resource policy and merges its profile with the custom block lists.
+func Compile(cfg *models.ResourcePolicy) (*Policy, error) {
+	profileName := cfg.Profile
+	if profileName == "" {
+		profileName = models.ResourceProfileFull
+	}
+	profile, ok := profiles[profileName]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", cfg.Profile)
+	}
+	if len(cfg.BlockDomains) > MaxBlockDomains {
+		return nil, fmt.Errorf("too many block domains: %d (max %d)", len(cfg.BlockDomains), MaxBlockDomains)
+	}
+	if len(cfg.BlockURLPatterns) > MaxBlockURLPatterns {
+		return nil, fmt.Errorf("too many block url patterns: %d (max %d)", len(cfg.BlockURLPatterns), MaxBlockURLPatterns)
+	}
+
+	p := &Policy{types: make(map[proto.NetworkResourceType]bool)}
+	for _, name := range profile.types {
+		p.types[resourceTypes[name]] = true
+	}
+	for _, name := range cfg.BlockResourceTypes {
+		t, ok := resourceTypes[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource type %q", name)
+		}
+		p.types[t] = true
+	}
+	if profile.trackers {
+		p.domains = append(p.domains, trackerDomains...)
+	}
+	for _, d := range cfg.BlockDomains {
+		d = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(d)), "*.")
+		if d == "" || strings.ContainsAny(d, "/:*") {
+			return nil, fmt.Errorf("invalid block domain %q", d)
+		}
+		p.domains = append(p.domains, d)
+	}
+	for _, g := range cfg.BlockURLPatterns {
+		if strings.TrimSpace(g) == "" {
+			return nil, fmt.Errorf("empty block url pattern")
+		}
+		p.patterns = append(p.patterns, globToRegexp(g))
+	}
+	return p, nil
+}
+
+// Empty reports whether the policy blocks nothing.
+func (p *Policy) Empty() bool {
+	return len(p.types) == 0 && len(p.domains) == 0 && len(p.patterns) == 0
+}
+
+// Blocks reports whether a request for rawURL of resourceType should be blocked.
+func (p *Policy) Blocks(rawURL string, resourceType proto.NetworkResourceType) bool {
+	if resourceType == proto.NetworkResourceTypeDocument {
+		return false
+	}
+	if p.types[resourceType] {
+		return true
+	}
+	if len(p.domains) > 0 {
+		if u, err := url.Parse(rawURL); err == nil {
+			host := strings.ToLower(u.Hostname())
+			for _, d := range p.domains {
+				if host == d || strings.HasSuffix(host, "."+d) {
+					return true
+				}
+			}
+		}
+	}
+	for _, re := range p.patterns {
+		if re.MatchString(rawURL) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp converts a URL glob, where * matches any run of characters
+// and ? matches one character, to an anchored regular expression.
+func globToRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// Blocker intercepts a page's requests and fails the ones its policy blocks.
+// Because a page has a single request interceptor, it also answers proxy
+// authentication challenges when the page is routed through a proxy.
+type Blocker struct {
+	page    *rod.Page
+	cancel  context.CancelFunc
+	blocked atomic.Int64
+	stopped atomic.Bool
+}
+
+// Start begins intercepting requests on page. It must be called before navigating.
+// policy may be nil to only answer proxy authentication with creds.
+// Call Stop to end interception and get the number of blocked requests.
+func Start(page *rod.Page, policy *Policy, creds *proxy.Credentials, logger *slog.Logger) (*Blocker, error) {
+	ctx, cancel := context.WithCancel(page.GetContext())
+	p := page.Context(ctx)
+	b := &Blocker{page: page, cancel: cancel}
+
+	wait := p.EachEvent(func(e *proto.FetchRequestPaused) {
+		go func() {
+			if policy != nil && policy.Blocks(e.Request.URL, e.ResourceType) {
+				b.blocked.Add(1)
+				_ = proto.FetchFailRequest{RequestID: e.RequestID, ErrorReason: proto.NetworkErrorReasonBlockedByClient}.Call(p)
+				return
+			}
+			_ = proto.FetchContinueRequest{RequestID: e.RequestID}.Call(p)
+		}()
+	}, func(e *proto.FetchAuthRequired) {
+		go func() {
+			resp := &proto.FetchAuthChallengeResponse{Response: proto.FetchAuthChallengeResponseResponseCancelAuth}
+			if creds != nil && e.AuthChallenge.Source == proto.FetchAuthChallengeSourceProxy {
+				resp = &proto.FetchAuthChallengeResponse{
+					Response: proto.FetchAuthChallengeResponseResponseProvideCredentials,
+					Username: creds.Username,
+					Password: creds.Password,
+				}
+			}
+			_ = proto.FetchContinueWithAuth{RequestID: e.RequestID, AuthChallengeResponse: resp}.Call(p)
+		}()
+	})
+
+	err := proto.FetchEnable{
+		Patterns:           []*proto.FetchRequestPattern{{URLPattern: "*"}},
+		HandleAuthRequests: creds != nil,
+	}.Call(page)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	go wait()
+
+	if policy != nil {
+		logger.Debug("resource blocking started", "resource_types", len(policy.types), "domains", len(policy.domains), "url_patterns", len(policy.patterns), "proxy_auth", creds != nil)
+	}
+	return b, nil
+}
+
+// Stop ends interception and returns the number of blocked requests.
+// It is safe to call more than once.
+func (b *Blocker) Stop() int {
+	if b.stopped.CompareAndSwap(false, true) {
+		_ = proto.FetchDisable{}.Call(b.page)
+		b.cancel()
+	}
+	return int(b.blocked.Load())
+}
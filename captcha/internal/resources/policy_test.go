@@ -0,0 +1,96 @@
+package resources
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+func TestCompile_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.ResourcePolicy
+		wantErr string
+	}{
+		{name: "empty policy", cfg: models.ResourcePolicy{}},
+		{name: "text_only", cfg: models.ResourcePolicy{Profile: models.ResourceProfileTextOnly}},
+		{name: "custom lists", cfg: models.ResourcePolicy{BlockResourceTypes: []string{"Script"}, BlockDomains: []string{"*.ads.example"}, BlockURLPatterns: []string{"*/pixel?*"}}},
+		{name: "unknown profile", cfg: models.ResourcePolicy{Profile: "minimal"}, wantErr: "unknown profile"},
+		{name: "unknown resource type", cfg: models.ResourcePolicy{BlockResourceTypes: []string{"video"}}, wantErr: "unknown resource type"},
+		{name: "document can't be blocked", cfg: models.ResourcePolicy{BlockResourceTypes: []string{"document"}}, wantErr: "unknown resource type"},
+		{name: "domain with path", cfg: models.ResourcePolicy{BlockDomains: []string{"example.com/ads"}}, wantErr: "invalid block domain"},
+		{name: "empty url pattern", cfg: models.ResourcePolicy{BlockURLPatterns: []string{" "}}, wantErr: "empty block url pattern"},
+		{name: "too many domains", cfg: models.ResourcePolicy{BlockDomains: make([]string, MaxBlockDomains+1)}, wantErr: "too many block domains"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(&tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Compile() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicy_Blocks(t *testing.T) {
+	mustCompile := func(cfg models.ResourcePolicy) *Policy {
+		t.Helper()
+		p, err := Compile(&cfg)
+		if err != nil {
+			t.Fatalf("Compile() error = %v", err)
+		}
+		return p
+	}
+
+	full := mustCompile(models.ResourcePolicy{Profile: models.ResourceProfileFull})
+	noMedia := mustCompile(models.ResourcePolicy{Profile: models.ResourceProfileNoMedia})
+	textOnly := mustCompile(models.ResourcePolicy{Profile: models.ResourceProfileTextOnly})
+	custom := mustCompile(models.ResourcePolicy{
+		BlockDomains:     []string{"cdn.ads.example"},
+		BlockURLPatterns: []string{"https://example.com/track/*"},
+	})
+
+	tests := []struct {
+		name   string
+		policy *Policy
+		url    string
+		typ    proto.NetworkResourceType
+		want   bool
+	}{
+		{"full blocks nothing", full, "https://example.com/a.png", proto.NetworkResourceTypeImage, false},
+		{"no_media blocks images", noMedia, "https://example.com/a.png", proto.NetworkResourceTypeImage, true},
+		{"no_media keeps stylesheets", noMedia, "https://example.com/a.css", proto.NetworkResourceTypeStylesheet, false},
+		{"no_media keeps scripts", noMedia, "https://example.com/app.js", proto.NetworkResourceTypeScript, false},
+		{"no_media blocks trackers", noMedia, "https://www.google-analytics.com/analytics.js", proto.NetworkResourceTypeScript, true},
+		{"text_only blocks stylesheets", textOnly, "https://example.com/a.css", proto.NetworkResourceTypeStylesheet, true},
+		{"text_only keeps xhr", textOnly, "https://example.com/api/items", proto.NetworkResourceTypeXHR, false},
+		{"documents are never blocked", textOnly, "https://doubleclick.net/", proto.NetworkResourceTypeDocument, false},
+		{"custom domain", custom, "https://cdn.ads.example/x.js", proto.NetworkResourceTypeScript, true},
+		{"custom subdomain", custom, "https://eu.cdn.ads.example/x.js", proto.NetworkResourceTypeScript, true},
+		{"custom domain suffix only on label boundary", custom, "https://notcdn.ads.example.org/x.js", proto.NetworkResourceTypeScript, false},
+		{"custom glob", custom, "https://example.com/track/pixel?id=1", proto.NetworkResourceTypeImage, true},
+		{"custom glob is anchored", custom, "https://example.com/page/track/x", proto.NetworkResourceTypeImage, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Blocks(tt.url, tt.typ); got != tt.want {
+				t.Errorf("Blocks(%q, %s) = %v, want %v", tt.url, tt.typ, got, tt.want)
+			}
+		})
+	}
+
+	if !full.Empty() || noMedia.Empty() {
+		t.Error("Empty() should only be true for policies that block nothing")
+	}
+}
@@ -0,0 +1,157 @@
+// Package screenshot captures full-page screenshots as a series of segments, so
+// content drawn into images or canvas elements can be read by vision models.
+package screenshot
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+// Limits on captured segments, so a very long page can't bloat the solve response.
+const (
+	DefaultSegmentHeight = 2000
+	MinSegmentHeight     = 200
+	MaxSegmentHeight     = 8000
+	DefaultMaxSegments   = 8
+	MaxSegments          = 20
+	DefaultQuality       = 80
+)
+
+// scrollSettle is how long each segment is scrolled into view before capture, so
+// lazy-loaded images below the fold start loading.
+const scrollSettle = 150 * time.Millisecond
+
+// Options are validated capture settings.
+type Options struct {
+	segmentHeight int
+	maxSegments   int
+	format        proto.PageCaptureScreenshotFormat
+	quality       int
+}
+
+// Compile validates the capture configuration and fills in defaults.
+func Compile(cfg *models.ScreenshotCapture) (*Options, error) {
+	o := &Options{
+		segmentHeight: cfg.SegmentHeight,
+		maxSegments:   cfg.MaxSegments,
+		format:        proto.PageCaptureScreenshotFormatJpeg,
+		quality:       cfg.Quality,
+	}
+	if o.segmentHeight == 0 {
+		o.segmentHeight = DefaultSegmentHeight
+	}
+	if o.segmentHeight < MinSegmentHeight || o.segmentHeight > MaxSegmentHeight {
+		return nil, fmt.Errorf("segmentHeight must be between %d and %d", MinSegmentHeight, MaxSegmentHeight)
+	}
+	if o.maxSegments == 0 {
+		o.maxSegments = DefaultMaxSegments
+	}
+	if o.maxSegments < 1 || o.maxSegments > MaxSegments {
+		return nil, fmt.Errorf("maxSegments must be between 1 and %d", MaxSegments)
+	}
+	switch strings.ToLower(cfg.Format) {
+	case "", "jpeg", "jpg":
+	case "png":
+		o.format = proto.PageCaptureScreenshotFormatPng
+	default:
+		return nil, fmt.Errorf("unknown format %q", cfg.Format)
+	}
+	if o.quality == 0 {
+		o.quality = DefaultQuality
+	}
+	if o.quality < 1 || o.quality > 100 {
+		return nil, fmt.Errorf("quality must be between 1 and 100")
+	}
+	return o, nil
+}
+
+// mimeType returns the MIME type of the captured images.
+func (o *Options) mimeType() string {
+	if o.format == proto.PageCaptureScreenshotFormatPng {
+		return "image/png"
+	}
+	return "image/jpeg"
+}
+
+// segment is a horizontal band of the page in CSS pixels.
+type segment struct {
+	offset int
+	height int
+}
+
+// segments splits a page of contentHeight into bands of at most segmentHeight,
+// from the top, keeping at most maxSegments.
+func segments(contentHeight, segmentHeight, maxSegments int) []segment {
+	var out []segment
+	for offset := 0; offset < contentHeight && len(out) < maxSegments; offset += segmentHeight {
+		out = append(out, segment{offset: offset, height: min(segmentHeight, contentHeight-offset)})
+	}
+	return out
+}
+
+// Capture takes the page's full-page screenshot segments, top to bottom. The page
+// is scrolled through first, so lazy-loaded content is rendered in the capture.
+func Capture(page *rod.Page, opts *Options) ([]models.Screenshot, error) {
+	metrics, err := proto.PageGetLayoutMetrics{}.Call(page)
+	if err != nil {
+		return nil, fmt.Errorf("get layout metrics: %w", err)
+	}
+	if metrics.CSSContentSize == nil || metrics.CSSLayoutViewport == nil {
+		return nil, fmt.Errorf("page has no layout metrics")
+	}
+	for _, s := range segments(int(math.Ceil(metrics.CSSContentSize.Height)), opts.segmentHeight, opts.maxSegments) {
+		if _, err := page.Eval(`y => window.scrollTo(0, y)`, s.offset); err != nil {
+			break
+		}
+		time.Sleep(scrollSettle)
+	}
+	_, _ = page.Eval(`() => window.scrollTo(0, 0)`)
+
+	// Lazy-loaded content may have grown the page, so measure again
+	if metrics, err = (proto.PageGetLayoutMetrics{}).Call(page); err != nil {
+		return nil, fmt.Errorf("get layout metrics: %w", err)
+	}
+	if metrics.CSSContentSize == nil || metrics.CSSLayoutViewport == nil {
+		return nil, fmt.Errorf("page has no layout metrics")
+	}
+	// Clip to the viewport width, as horizontal overflow is rarely content
+	width := metrics.CSSLayoutViewport.ClientWidth
+	height := int(math.Ceil(metrics.CSSContentSize.Height))
+
+	var shots []models.Screenshot
+	for _, s := range segments(height, opts.segmentHeight, opts.maxSegments) {
+		req := proto.PageCaptureScreenshot{
+			Format: opts.format,
+			Clip: &proto.PageViewport{
+				Y:      float64(s.offset),
+				Width:  float64(width),
+				Height: float64(s.height),
+				Scale:  1,
+			},
+			CaptureBeyondViewport: true,
+		}
+		if opts.format == proto.PageCaptureScreenshotFormatJpeg {
+			req.Quality = &opts.quality
+		}
+		res, err := req.Call(page)
+		if err != nil {
+			return shots, fmt.Errorf("capture segment at %d: %w", s.offset, err)
+		}
+		shots = append(shots, models.Screenshot{
+			Data:     base64.StdEncoding.EncodeToString(res.Data),
+			MimeType: opts.mimeType(),
+			Offset:   s.offset,
+			Width:    width,
+			Height:   s.height,
+		})
+	}
+	return shots, nil
+}
@@ -0,0 +1,77 @@
+package screenshot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/models"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     models.ScreenshotCapture
+		wantErr bool
+	}{
+		{"defaults", models.ScreenshotCapture{}, false},
+		{"png", models.ScreenshotCapture{Format: "PNG", SegmentHeight: 1200, MaxSegments: 4}, false},
+		{"segment too short", models.ScreenshotCapture{SegmentHeight: MinSegmentHeight - 1}, true},
+		{"segment too tall", models.ScreenshotCapture{SegmentHeight: MaxSegmentHeight + 1}, true},
+		{"too many segments", models.ScreenshotCapture{MaxSegments: MaxSegments + 1}, true},
+		{"negative segments", models.ScreenshotCapture{MaxSegments: -1}, true},
+		{"unknown format", models.ScreenshotCapture{Format: "gif"}, true},
+		{"quality too high", models.ScreenshotCapture{Quality: 101}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Compile(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompile_Defaults(t *testing.T) {
+	o, err := Compile(&models.ScreenshotCapture{})
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	want := &Options{
+		segmentHeight: DefaultSegmentHeight,
+		maxSegments:   DefaultMaxSegments,
+		format:        proto.PageCaptureScreenshotFormatJpeg,
+		quality:       DefaultQuality,
+	}
+	if !reflect.DeepEqual(o, want) {
+		t.Errorf("Compile() = %+v, want %+v", o, want)
+	}
+	if got := o.mimeType(); got != "image/jpeg" {
+		t.Errorf("mimeType() = %q, want image/jpeg", got)
+	}
+}
+
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentHeight int
+		segmentHeight int
+		maxSegments   int
+		want          []segment
+	}{
+		{"empty page", 0, 1000, 8, nil},
+		{"shorter than a segment", 600, 1000, 8, []segment{{0, 600}}},
+		{"exact multiple", 2000, 1000, 8, []segment{{0, 1000}, {1000, 1000}}},
+		{"partial last segment", 2500, 1000, 8, []segment{{0, 1000}, {1000, 1000}, {2000, 500}}},
+		{"capped", 10000, 1000, 3, []segment{{0, 1000}, {1000, 1000}, {2000, 1000}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := segments(tt.contentHeight, tt.segmentHeight, tt.maxSegments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
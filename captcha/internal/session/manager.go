@@ -17,6 +17,7 @@ import (
 
 	"github.com/jmylchreest/refyne-api/captcha/internal/browser"
 	"github.com/jmylchreest/refyne-api/captcha/internal/config"
+	"github.com/jmylchreest/refyne-api/captcha/internal/fingerprint"
 	"github.com/jmylchreest/refyne-api/captcha/internal/models"
 )
 
@@ -35,12 +36,17 @@ type Session struct {
 	Browser      *rod.Browser
 	Page         *rod.Page
 	UserAgent    string
+	Profile      string // Fingerprint profile, fixed for the session's lifetime
 	Proxy        string
 	CreatedAt    time.Time
 	LastUsedAt   time.Time
 	RequestCount int
 	InUse        bool
 	Cookies      []*proto.NetworkCookie
+
+	// Requested window size, restored with the session (0 for the default or the profile's viewport)
+	WindowWidth  int
+	WindowHeight int
 }
 
 // Manager manages browser sessions.
@@ -101,6 +107,11 @@ func (m *Manager) Create(ctx context.Context, sessionID string, opts *models.Ses
 		}
 	}
 
+	profile, err := m.sessionProfile(opts)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure launcher
 	l := launcher.New()
 
@@ -137,6 +148,10 @@ func (m *Manager) Create(ctx context.Context, sessionID string, opts *models.Ses
 		}
 	}
 	l = l.Set("window-size", fmt.Sprintf("%d,%d", width, height))
+	requestedWidth, requestedHeight := 0, 0
+	if opts != nil && (opts.WindowWidth > 0 || opts.WindowHeight > 0) {
+		requestedWidth, requestedHeight = width, height
+	}
 
 	// Configure proxy if specified
 	var proxyURL string
@@ -167,9 +182,20 @@ func (m *Manager) Create(ctx context.Context, sessionID string, opts *models.Ses
 		return nil, err
 	}
 
-	// Set user agent if specified
+	// Present the profile's device for the session's lifetime, or set the user agent if specified
 	userAgent := ""
-	if opts != nil && opts.UserAgent != "" {
+	profileName := ""
+	if profile != nil {
+		if requestedWidth > 0 {
+			// An explicit window size replaces the profile's viewport
+			profile = sizedProfile(profile, width, height)
+		}
+		if userAgent, err = fingerprint.Apply(page, profile); err != nil {
+			b.Close()
+			return nil, err
+		}
+		profileName = profile.Name
+	} else if opts != nil && opts.UserAgent != "" {
 		userAgent = opts.UserAgent
 		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
 			UserAgent: userAgent,
@@ -189,15 +215,19 @@ func (m *Manager) Create(ctx context.Context, sessionID string, opts *models.Ses
 		Browser:      b,
 		Page:         page,
 		UserAgent:    userAgent,
+		Profile:      profileName,
 		Proxy:        proxyURL,
 		CreatedAt:    time.Now(),
 		LastUsedAt:   time.Now(),
 		RequestCount: 0,
 		InUse:        false,
+
+		WindowWidth:  requestedWidth,
+		WindowHeight: requestedHeight,
 	}
 
 	m.sessions[id] = session
-	m.logger.Info("session created", "id", id, "proxy", proxyURL != "")
+	m.logger.Info("session created", "id", id, "proxy", proxyURL != "", "profile", profileName)
 
 	// Persist to store if available
 	if m.store != nil {
@@ -209,11 +239,15 @@ func (m *Manager) Create(ctx context.Context, sessionID string, opts *models.Ses
 			ID:           session.ID,
 			UserID:       userID,
 			UserAgent:    session.UserAgent,
+			Profile:      session.Profile,
 			Proxy:        session.Proxy,
 			CreatedAt:    session.CreatedAt,
 			LastUsedAt:   session.LastUsedAt,
 			RequestCount: session.RequestCount,
 			Cookies:      session.Cookies,
+
+			WindowWidth:  session.WindowWidth,
+			WindowHeight: session.WindowHeight,
 		}); err != nil {
 			m.logger.Error("failed to persist session", "id", id, "error", err)
 		}
@@ -222,6 +256,29 @@ func (m *Manager) Create(ctx context.Context, sessionID string, opts *models.Ses
 	return session, nil
 }
 
+// sessionProfile returns the fingerprint profile a new session presents: the one
+// requested, or the default unless the session sets its own user agent or stealth
+// is disabled.
+func (m *Manager) sessionProfile(opts *models.SessionOptions) (*fingerprint.Profile, error) {
+	name := ""
+	if opts != nil {
+		if opts.FingerprintProfile != "" && opts.UserAgent != "" {
+			return nil, errors.New("userAgent and fingerprintProfile can't both be set")
+		}
+		if opts.UserAgent != "" {
+			return nil, nil
+		}
+		name = opts.FingerprintProfile
+	}
+	if name == "" {
+		if m.cfg.DisableStealth {
+			return nil, nil
+		}
+		name = m.cfg.FingerprintProfile
+	}
+	return fingerprint.Lookup(name)
+}
+
 // Get retrieves a session by ID.
 func (m *Manager) Get(id string) (*Session, error) {
 	m.mu.RLock()
@@ -297,6 +354,13 @@ func (m *Manager) Acquire(ctx context.Context, id string) (*Session, error) {
 	}
 }
 
+// sizedProfile returns a copy of profile with its viewport replaced by an explicit window size.
+func sizedProfile(profile *fingerprint.Profile, width, height int) *fingerprint.Profile {
+	sized := *profile
+	sized.Width, sized.Height = width, height
+	return &sized
+}
+
 // restoreFromStore restores a session from the persistence store.
 // It creates a new browser instance and injects the saved cookies.
 func (m *Manager) restoreFromStore(ctx context.Context, id string) (*Session, error) {
@@ -321,6 +385,10 @@ func (m *Manager) restoreFromStore(ctx context.Context, id string) (*Session, er
 	}
 
 	// Create new browser with saved settings
+	width, height := 1920, 1080
+	if persisted.WindowWidth > 0 && persisted.WindowHeight > 0 {
+		width, height = persisted.WindowWidth, persisted.WindowHeight
+	}
 	l := launcher.New()
 	if m.cfg.ChromePath != "" {
 		l = l.Bin(m.cfg.ChromePath)
@@ -334,7 +402,7 @@ func (m *Manager) restoreFromStore(ctx context.Context, id string) (*Session, er
 		Set("disable-setuid-sandbox").
 		Set("disable-infobars").
 		Set("lang", "en-US,en").
-		Set("window-size", "1920,1080")
+		Set("window-size", fmt.Sprintf("%d,%d", width, height))
 
 	if persisted.Proxy != "" {
 		l = l.Proxy(persisted.Proxy)
@@ -356,8 +424,20 @@ func (m *Manager) restoreFromStore(ctx context.Context, id string) (*Session, er
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
 
-	// Restore user agent
-	if persisted.UserAgent != "" {
+	// Restore the fingerprint profile, or the user agent of sessions without one
+	if persisted.Profile != "" {
+		if profile, ok := fingerprint.Get(persisted.Profile); !ok {
+			m.logger.Warn("unknown fingerprint profile, not restored", "id", id, "profile", persisted.Profile)
+		} else {
+			if persisted.WindowWidth > 0 && persisted.WindowHeight > 0 {
+				profile = sizedProfile(profile, width, height)
+			}
+			if _, err := fingerprint.Apply(page, profile); err != nil {
+				b.Close()
+				return nil, fmt.Errorf("failed to restore fingerprint profile: %w", err)
+			}
+		}
+	} else if persisted.UserAgent != "" {
 		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
 			UserAgent: persisted.UserAgent,
 		}); err != nil {
@@ -404,12 +484,16 @@ func (m *Manager) restoreFromStore(ctx context.Context, id string) (*Session, er
 		Browser:      b,
 		Page:         page,
 		UserAgent:    persisted.UserAgent,
+		Profile:      persisted.Profile,
 		Proxy:        persisted.Proxy,
 		CreatedAt:    persisted.CreatedAt,
 		LastUsedAt:   time.Now(),
 		RequestCount: persisted.RequestCount,
 		InUse:        false,
 		Cookies:      persisted.Cookies,
+
+		WindowWidth:  persisted.WindowWidth,
+		WindowHeight: persisted.WindowHeight,
 	}
 
 	m.sessions[id] = session
@@ -504,11 +588,15 @@ func (m *Manager) Release(id string) {
 		if err := m.store.Save(&PersistedSession{
 			ID:           session.ID,
 			UserAgent:    session.UserAgent,
+			Profile:      session.Profile,
 			Proxy:        session.Proxy,
 			CreatedAt:    session.CreatedAt,
 			LastUsedAt:   session.LastUsedAt,
 			RequestCount: session.RequestCount,
 			Cookies:      session.Cookies,
+
+			WindowWidth:  session.WindowWidth,
+			WindowHeight: session.WindowHeight,
 		}); err != nil {
 			m.logger.Error("failed to persist session state", "id", id, "error", err)
 		}
@@ -585,12 +673,13 @@ func (m *Manager) GetInfo(id string) (*models.SessionInfo, error) {
 	}
 
 	return &models.SessionInfo{
-		ID:           session.ID,
-		CreatedAt:    session.CreatedAt.UnixMilli(),
-		LastUsedAt:   session.LastUsedAt.UnixMilli(),
-		RequestCount: session.RequestCount,
-		UserAgent:    session.UserAgent,
-		Proxy:        maskedProxy,
+		ID:                 session.ID,
+		CreatedAt:          session.CreatedAt.UnixMilli(),
+		LastUsedAt:         session.LastUsedAt.UnixMilli(),
+		RequestCount:       session.RequestCount,
+		UserAgent:          session.UserAgent,
+		Proxy:              maskedProxy,
+		FingerprintProfile: session.Profile,
 	}, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod/lib/proto"
@@ -28,11 +29,16 @@ type PersistedSession struct {
 	ID           string                 `json:"id"`
 	UserID       string                 `json:"user_id"`
 	UserAgent    string                 `json:"user_agent"`
+	Profile      string                 `json:"profile"` // Fingerprint profile ("" for sessions with a custom user agent)
 	Proxy        string                 `json:"proxy"`
 	CreatedAt    time.Time              `json:"created_at"`
 	LastUsedAt   time.Time              `json:"last_used_at"`
 	RequestCount int                    `json:"request_count"`
 	Cookies      []*proto.NetworkCookie `json:"cookies"`
+
+	// Requested window size (0 when the session uses the default or the profile's viewport)
+	WindowWidth  int `json:"window_width"`
+	WindowHeight int `json:"window_height"`
 }
 
 // NewSQLiteStore creates a new SQLite-backed session store.
@@ -88,6 +94,9 @@ func (s *SQLiteStore) migrate() error {
 		id TEXT PRIMARY KEY,
 		user_id TEXT NOT NULL DEFAULT '',
 		user_agent TEXT NOT NULL DEFAULT '',
+		profile TEXT NOT NULL DEFAULT '',
+		window_width INTEGER NOT NULL DEFAULT 0,
+		window_height INTEGER NOT NULL DEFAULT 0,
 		proxy TEXT NOT NULL DEFAULT '',
 		cookies_json TEXT NOT NULL DEFAULT '[]',
 		request_count INTEGER NOT NULL DEFAULT 0,
@@ -98,8 +107,21 @@ func (s *SQLiteStore) migrate() error {
 	CREATE INDEX IF NOT EXISTS idx_sessions_last_used_at ON sessions(last_used_at);
 	`
 
-	_, err := s.db.Exec(schema)
-	return err
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+
+	// Databases created before fingerprint profiles lack the profile and window size columns
+	for _, column := range []string{
+		`profile TEXT NOT NULL DEFAULT ''`,
+		`window_width INTEGER NOT NULL DEFAULT 0`,
+		`window_height INTEGER NOT NULL DEFAULT 0`,
+	} {
+		if _, err := s.db.Exec(`ALTER TABLE sessions ADD COLUMN ` + column); err != nil && !strings.Contains(err.Error(), "duplicate column") {
+			return err
+		}
+	}
+	return nil
 }
 
 // Save persists a session to the database.
@@ -110,10 +132,13 @@ func (s *SQLiteStore) Save(session *PersistedSession) error {
 	}
 
 	query := `
-	INSERT INTO sessions (id, user_id, user_agent, proxy, cookies_json, request_count, created_at, last_used_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	INSERT INTO sessions (id, user_id, user_agent, profile, window_width, window_height, proxy, cookies_json, request_count, created_at, last_used_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(id) DO UPDATE SET
 		user_agent = excluded.user_agent,
+		profile = excluded.profile,
+		window_width = excluded.window_width,
+		window_height = excluded.window_height,
 		proxy = excluded.proxy,
 		cookies_json = excluded.cookies_json,
 		request_count = excluded.request_count,
@@ -124,6 +149,9 @@ func (s *SQLiteStore) Save(session *PersistedSession) error {
 		session.ID,
 		session.UserID,
 		session.UserAgent,
+		session.Profile,
+		session.WindowWidth,
+		session.WindowHeight,
 		session.Proxy,
 		string(cookiesJSON),
 		session.RequestCount,
@@ -142,7 +170,7 @@ func (s *SQLiteStore) Save(session *PersistedSession) error {
 // Load retrieves a session from the database.
 func (s *SQLiteStore) Load(id string) (*PersistedSession, error) {
 	query := `
-	SELECT id, user_id, user_agent, proxy, cookies_json, request_count, created_at, last_used_at
+	SELECT id, user_id, user_agent, profile, window_width, window_height, proxy, cookies_json, request_count, created_at, last_used_at
 	FROM sessions
 	WHERE id = ?
 	`
@@ -155,6 +183,9 @@ func (s *SQLiteStore) Load(id string) (*PersistedSession, error) {
 		&session.ID,
 		&session.UserID,
 		&session.UserAgent,
+		&session.Profile,
+		&session.WindowWidth,
+		&session.WindowHeight,
 		&session.Proxy,
 		&cookiesJSON,
 		&session.RequestCount,
@@ -195,7 +226,7 @@ func (s *SQLiteStore) Delete(id string) error {
 // ListByUser returns all sessions for a given user.
 func (s *SQLiteStore) ListByUser(userID string) ([]*PersistedSession, error) {
 	query := `
-	SELECT id, user_id, user_agent, proxy, cookies_json, request_count, created_at, last_used_at
+	SELECT id, user_id, user_agent, profile, window_width, window_height, proxy, cookies_json, request_count, created_at, last_used_at
 	FROM sessions
 	WHERE user_id = ?
 	ORDER BY last_used_at DESC
@@ -217,6 +248,9 @@ func (s *SQLiteStore) ListByUser(userID string) ([]*PersistedSession, error) {
 			&session.ID,
 			&session.UserID,
 			&session.UserAgent,
+			&session.Profile,
+			&session.WindowWidth,
+			&session.WindowHeight,
 			&session.Proxy,
 			&cookiesJSON,
 			&session.RequestCount,
@@ -241,7 +275,7 @@ func (s *SQLiteStore) ListByUser(userID string) ([]*PersistedSession, error) {
 // ListAll returns all sessions.
 func (s *SQLiteStore) ListAll() ([]*PersistedSession, error) {
 	query := `
-	SELECT id, user_id, user_agent, proxy, cookies_json, request_count, created_at, last_used_at
+	SELECT id, user_id, user_agent, profile, window_width, window_height, proxy, cookies_json, request_count, created_at, last_used_at
 	FROM sessions
 	ORDER BY last_used_at DESC
 	`
@@ -262,6 +296,9 @@ func (s *SQLiteStore) ListAll() ([]*PersistedSession, error) {
 			&session.ID,
 			&session.UserID,
 			&session.UserAgent,
+			&session.Profile,
+			&session.WindowWidth,
+			&session.WindowHeight,
 			&session.Proxy,
 			&cookiesJSON,
 			&session.RequestCount,
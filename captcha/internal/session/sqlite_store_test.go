@@ -0,0 +1,83 @@
+package session
+
+import (
+	"database/sql"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLiteStore_PersistsProfileAndWindowSize(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "sessions.db"), slog.Default())
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := store.Save(&PersistedSession{
+		ID:           "s1",
+		UserID:       "user-1",
+		Profile:      "chrome-windows",
+		CreatedAt:    now,
+		LastUsedAt:   now,
+		WindowWidth:  1280,
+		WindowHeight: 720,
+	}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	got, err := store.Load("s1")
+	if err != nil || got == nil {
+		t.Fatalf("Load() = %v, %v", got, err)
+	}
+	if got.Profile != "chrome-windows" || got.WindowWidth != 1280 || got.WindowHeight != 720 {
+		t.Errorf("Load() = profile %q, window %dx%d; want chrome-windows, 1280x720", got.Profile, got.WindowWidth, got.WindowHeight)
+	}
+
+	listed, err := store.ListByUser("user-1")
+	if err != nil || len(listed) != 1 || listed[0].WindowWidth != 1280 {
+		t.Errorf("ListByUser() = %v, %v", listed, err)
+	}
+}
+
+func TestSQLiteStore_MigratesOldDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.db")
+
+	// A database created before fingerprint profiles and window sizes were persisted
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	if _, err := db.Exec(`
+	CREATE TABLE sessions (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL DEFAULT '',
+		user_agent TEXT NOT NULL DEFAULT '',
+		proxy TEXT NOT NULL DEFAULT '',
+		cookies_json TEXT NOT NULL DEFAULT '[]',
+		request_count INTEGER NOT NULL DEFAULT 0,
+		created_at TEXT NOT NULL,
+		last_used_at TEXT NOT NULL
+	);
+	INSERT INTO sessions (id, created_at, last_used_at) VALUES ('old', '2026-01-01T00:00:00Z', '2026-01-01T00:00:00Z');
+	`); err != nil {
+		t.Fatalf("creating old schema: %v", err)
+	}
+	db.Close()
+
+	store, err := NewSQLiteStore(path, slog.Default())
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	defer store.Close()
+
+	got, err := store.Load("old")
+	if err != nil || got == nil {
+		t.Fatalf("Load() = %v, %v", got, err)
+	}
+	if got.Profile != "" || got.WindowWidth != 0 || got.WindowHeight != 0 {
+		t.Errorf("migrated session = %+v, want no profile or window size", got)
+	}
+}
@@ -0,0 +1,130 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+)
+
+const (
+	antiCaptchaBaseURL = "https://api.anti-captcha.com"
+	// Pricing per 1000 solves (as of 2025)
+	antiCaptchaTurnstilePrice   = 0.002   // $2.00/1000
+	antiCaptchaHCaptchaPrice    = 0.002   // $2.00/1000
+	antiCaptchaReCaptchaV2Price = 0.00095 // $0.95/1000
+	antiCaptchaReCaptchaV3Price = 0.002   // $2.00/1000
+
+	// antiCaptchaMinScore is the reCAPTCHA v3 score requested from Anti-Captcha's workers.
+	antiCaptchaMinScore = 0.7
+)
+
+// AntiCaptcha implements the Solver interface using Anti-Captcha's API.
+type AntiCaptcha struct {
+	api *taskAPI
+}
+
+// NewAntiCaptcha creates a new Anti-Captcha solver.
+func NewAntiCaptcha(apiKey string) *AntiCaptcha {
+	return &AntiCaptcha{api: newTaskAPI("anticaptcha", antiCaptchaBaseURL, apiKey)}
+}
+
+// Name returns "anticaptcha".
+func (a *AntiCaptcha) Name() string {
+	return "anticaptcha"
+}
+
+// CanSolve returns true for supported challenge types.
+func (a *AntiCaptcha) CanSolve(challengeType challenge.Type) bool {
+	switch challengeType {
+	case challenge.TypeCloudflareTurnstile,
+		challenge.TypeHCaptcha,
+		challenge.TypeReCaptchaV2,
+		challenge.TypeReCaptchaV3:
+		return true
+	default:
+		return false
+	}
+}
+
+// Solve submits a CAPTCHA task to Anti-Captcha and waits for the solution.
+func (a *AntiCaptcha) Solve(ctx context.Context, params SolveParams) (*SolveResult, error) {
+	task := map[string]any{
+		"websiteURL": params.PageURL,
+		"websiteKey": params.SiteKey,
+	}
+
+	proxied := params.Proxy != nil
+	switch params.Type {
+	case challenge.TypeCloudflareTurnstile:
+		task["type"] = antiCaptchaTaskType("TurnstileTask", proxied)
+		if params.Action != "" {
+			task["action"] = params.Action
+		}
+		if params.CData != "" {
+			task["turnstileCData"] = params.CData
+		}
+	case challenge.TypeHCaptcha:
+		task["type"] = antiCaptchaTaskType("HCaptchaTask", proxied)
+	case challenge.TypeReCaptchaV2:
+		task["type"] = antiCaptchaTaskType("RecaptchaV2Task", proxied)
+	case challenge.TypeReCaptchaV3:
+		// reCAPTCHA v3 is only solved proxyless
+		proxied = false
+		task["type"] = "RecaptchaV3TaskProxyless"
+		task["minScore"] = antiCaptchaMinScore
+		if params.Action != "" {
+			task["pageAction"] = params.Action
+		}
+	default:
+		return nil, fmt.Errorf("unsupported challenge type: %s", params.Type)
+	}
+
+	if proxied {
+		for k, v := range proxyTaskFields(params.Proxy) {
+			task[k] = v
+		}
+	}
+
+	token, err := a.api.solve(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SolveResult{
+		Token:      token,
+		Valid:      2 * time.Minute,
+		Cost:       a.Cost(params.Type),
+		SolverName: a.Name(),
+	}, nil
+}
+
+// Cost returns the cost per solve for the given challenge type.
+func (a *AntiCaptcha) Cost(challengeType challenge.Type) float64 {
+	switch challengeType {
+	case challenge.TypeCloudflareTurnstile:
+		return antiCaptchaTurnstilePrice
+	case challenge.TypeHCaptcha:
+		return antiCaptchaHCaptchaPrice
+	case challenge.TypeReCaptchaV2:
+		return antiCaptchaReCaptchaV2Price
+	case challenge.TypeReCaptchaV3:
+		return antiCaptchaReCaptchaV3Price
+	default:
+		return 0
+	}
+}
+
+// Balance returns the current account balance.
+func (a *AntiCaptcha) Balance(ctx context.Context) (float64, error) {
+	return a.api.balance(ctx)
+}
+
+// antiCaptchaTaskType returns the proxied task type, or its proxyless variant without a proxy.
+func antiCaptchaTaskType(base string, proxied bool) string {
+	if !proxied {
+		return base + "Proxyless"
+	}
+	return base
+}
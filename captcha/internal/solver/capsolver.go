@@ -0,0 +1,129 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+)
+
+const (
+	capSolverBaseURL = "https://api.capsolver.com"
+	// Pricing per 1000 solves (as of 2025)
+	capSolverTurnstilePrice   = 0.0012 // $1.20/1000
+	capSolverReCaptchaV2Price = 0.0008 // $0.80/1000
+	capSolverReCaptchaV3Price = 0.001  // $1.00/1000
+)
+
+// CapSolver implements the Solver interface using CapSolver's API.
+type CapSolver struct {
+	api *taskAPI
+}
+
+// NewCapSolver creates a new CapSolver solver.
+func NewCapSolver(apiKey string) *CapSolver {
+	return &CapSolver{api: newTaskAPI("capsolver", capSolverBaseURL, apiKey)}
+}
+
+// Name returns "capsolver".
+func (c *CapSolver) Name() string {
+	return "capsolver"
+}
+
+// CanSolve returns true for supported challenge types.
+func (c *CapSolver) CanSolve(challengeType challenge.Type) bool {
+	switch challengeType {
+	case challenge.TypeCloudflareTurnstile,
+		challenge.TypeReCaptchaV2,
+		challenge.TypeReCaptchaV3:
+		return true
+	default:
+		return false
+	}
+}
+
+// Solve submits a CAPTCHA task to CapSolver and waits for the solution.
+func (c *CapSolver) Solve(ctx context.Context, params SolveParams) (*SolveResult, error) {
+	task := map[string]any{
+		"websiteURL": params.PageURL,
+		"websiteKey": params.SiteKey,
+	}
+
+	switch params.Type {
+	case challenge.TypeCloudflareTurnstile:
+		// Turnstile is only solved proxyless
+		task["type"] = "AntiTurnstileTaskProxyLess"
+		metadata := map[string]string{}
+		if params.Action != "" {
+			metadata["action"] = params.Action
+		}
+		if params.CData != "" {
+			metadata["cdata"] = params.CData
+		}
+		if len(metadata) > 0 {
+			task["metadata"] = metadata
+		}
+	case challenge.TypeReCaptchaV2:
+		task["type"] = capSolverTaskType("ReCaptchaV2Task", params.Proxy)
+	case challenge.TypeReCaptchaV3:
+		task["type"] = capSolverTaskType("ReCaptchaV3Task", params.Proxy)
+		if params.Action != "" {
+			task["pageAction"] = params.Action
+		}
+	default:
+		return nil, fmt.Errorf("unsupported challenge type: %s", params.Type)
+	}
+
+	if params.Proxy != nil && params.Type != challenge.TypeCloudflareTurnstile {
+		task["proxy"] = capSolverProxy(params.Proxy)
+	}
+
+	token, err := c.api.solve(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SolveResult{
+		Token:      token,
+		Valid:      2 * time.Minute,
+		Cost:       c.Cost(params.Type),
+		SolverName: c.Name(),
+	}, nil
+}
+
+// Cost returns the cost per solve for the given challenge type.
+func (c *CapSolver) Cost(challengeType challenge.Type) float64 {
+	switch challengeType {
+	case challenge.TypeCloudflareTurnstile:
+		return capSolverTurnstilePrice
+	case challenge.TypeReCaptchaV2:
+		return capSolverReCaptchaV2Price
+	case challenge.TypeReCaptchaV3:
+		return capSolverReCaptchaV3Price
+	default:
+		return 0
+	}
+}
+
+// Balance returns the current account balance.
+func (c *CapSolver) Balance(ctx context.Context) (float64, error) {
+	return c.api.balance(ctx)
+}
+
+// capSolverTaskType returns the proxied task type, or its proxyless variant without a proxy.
+func capSolverTaskType(base string, proxy *ProxyConfig) string {
+	if proxy == nil {
+		return base + "ProxyLess"
+	}
+	return base
+}
+
+// capSolverProxy formats a proxy as CapSolver's "type:host:port[:user:pass]" string.
+func capSolverProxy(proxy *ProxyConfig) string {
+	s := fmt.Sprintf("%s:%s:%d", proxy.Type, proxy.Host, proxy.Port)
+	if proxy.Username != "" {
+		s += ":" + proxy.Username + ":" + proxy.Password
+	}
+	return s
+}
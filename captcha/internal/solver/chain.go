@@ -0,0 +1,288 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+)
+
+const (
+	// chainBalanceTTL is how long a solver's balance is cached between lookups.
+	chainBalanceTTL = 5 * time.Minute
+
+	// chainBalanceTimeout bounds a balance lookup made while ranking solvers.
+	chainBalanceTimeout = 5 * time.Second
+)
+
+// Chain is a solver that tries multiple solvers, cheapest expected cost first.
+//
+// For each challenge the solvers that can solve it are ranked by their cost per
+// successful solve: Cost divided by their success rate on that challenge type so
+// far. Ties go to the higher success rate, then the larger balance. Paid solvers
+// whose balance can't cover a solve, or whose price would take the attempts so far
+// over the request's MaxCost, are skipped.
+type Chain struct {
+	solvers []Solver
+	nowFunc func() time.Time
+
+	mu       sync.Mutex
+	stats    map[statsKey]*solveStats
+	balances map[string]cachedBalance
+}
+
+// statsKey identifies a solver's record on one challenge type.
+type statsKey struct {
+	solver        string
+	challengeType challenge.Type
+}
+
+// solveStats counts a solver's attempts and successes on one challenge type.
+type solveStats struct {
+	attempts  int
+	successes int
+}
+
+// cachedBalance is a solver's last known balance (-1 when unknown).
+type cachedBalance struct {
+	amount    float64
+	fetchedAt time.Time
+}
+
+// NewChain creates a new solver chain.
+func NewChain(solvers ...Solver) *Chain {
+	return &Chain{
+		solvers:  solvers,
+		nowFunc:  time.Now,
+		stats:    make(map[statsKey]*solveStats),
+		balances: make(map[string]cachedBalance),
+	}
+}
+
+// Name returns "chain".
+func (c *Chain) Name() string {
+	return "chain"
+}
+
+// CanSolve returns true if any solver in the chain can solve the challenge.
+func (c *Chain) CanSolve(challengeType challenge.Type) bool {
+	for _, s := range c.solvers {
+		if s.CanSolve(challengeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Solve tries each eligible solver, cheapest expected cost first, until one succeeds.
+//
+// MaxCost bounds the whole chain rather than each attempt: every paid attempt counts
+// against it, and a solver is skipped once its price would take the total over. The
+// result's Cost is the total of all paid attempts. When every attempt fails after
+// paid attempts were made, a result carrying that total is returned with the error.
+func (c *Chain) Solve(ctx context.Context, params SolveParams) (*SolveResult, error) {
+	solvers, skipErr := c.rank(ctx, params)
+
+	var spent float64
+	var lastErr error
+	for _, s := range solvers {
+		cost := s.Cost(params.Type)
+		if params.MaxCost != nil && cost > 0 && spent+cost > *params.MaxCost {
+			if skipErr == nil {
+				skipErr = ErrBudgetExceeded
+			}
+			continue
+		}
+
+		result, err := s.Solve(ctx, params)
+		c.record(ctx, s, params.Type, result, err)
+		spent += attemptCost(cost, result, err)
+		if err == nil {
+			result.Cost = spent
+			return result, nil
+		}
+		lastErr = err
+	}
+
+	var spentResult *SolveResult
+	if spent > 0 {
+		spentResult = &SolveResult{Cost: spent}
+	}
+	if lastErr != nil {
+		return spentResult, lastErr
+	}
+	if skipErr != nil {
+		return spentResult, skipErr
+	}
+	return nil, ErrNoSolverAvailable
+}
+
+// attemptCost returns what a solve attempt cost. Services may bill tasks they
+// accepted but couldn't solve, so a failed attempt counts at the solver's listed
+// price unless it was refused for lack of funds.
+func attemptCost(cost float64, result *SolveResult, err error) float64 {
+	switch {
+	case err == nil && result != nil:
+		return result.Cost
+	case err != nil && !errors.Is(err, ErrInsufficientFunds):
+		return cost
+	}
+	return 0
+}
+
+// rank returns the solvers to try for a challenge in order. When solvers were
+// skipped for budget or balance, the reason is returned alongside.
+func (c *Chain) rank(ctx context.Context, params SolveParams) ([]Solver, error) {
+	type candidate struct {
+		solver   Solver
+		expected float64 // Cost per successful solve
+		rate     float64
+		balance  float64
+	}
+
+	var candidates []candidate
+	var skipErr error
+	for _, s := range c.solvers {
+		if !s.CanSolve(params.Type) {
+			continue
+		}
+		cost := s.Cost(params.Type)
+		if params.MaxCost != nil && cost > *params.MaxCost {
+			skipErr = ErrBudgetExceeded
+			continue
+		}
+		balance := float64(-1)
+		if cost > 0 {
+			balance = c.balance(ctx, s)
+			if balance >= 0 && balance < cost {
+				if skipErr == nil {
+					skipErr = ErrInsufficientFunds
+				}
+				continue
+			}
+		}
+		rate := c.successRate(s.Name(), params.Type)
+		candidates = append(candidates, candidate{
+			solver:   s,
+			expected: cost / rate,
+			rate:     rate,
+			balance:  balance,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.expected != b.expected {
+			return a.expected < b.expected
+		}
+		if a.rate != b.rate {
+			return a.rate > b.rate
+		}
+		return a.balance > b.balance
+	})
+
+	solvers := make([]Solver, len(candidates))
+	for i, cand := range candidates {
+		solvers[i] = cand.solver
+	}
+	return solvers, skipErr
+}
+
+// successRate returns a solver's smoothed success rate on a challenge type.
+// Solvers without attempts start at 0.5 and move towards their observed rate.
+func (c *Chain) successRate(name string, challengeType challenge.Type) float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st := c.stats[statsKey{name, challengeType}]
+	if st == nil {
+		return 0.5
+	}
+	return float64(st.successes+1) / float64(st.attempts+2)
+}
+
+// record updates a solver's stats and cached balance after an attempt.
+// Attempts cut short by the request's context are not counted against the solver.
+func (c *Chain) record(ctx context.Context, s Solver, challengeType challenge.Type, result *SolveResult, err error) {
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := statsKey{s.Name(), challengeType}
+	st := c.stats[key]
+	if st == nil {
+		st = &solveStats{}
+		c.stats[key] = st
+	}
+	st.attempts++
+	if err == nil {
+		st.successes++
+	}
+
+	switch {
+	case errors.Is(err, ErrInsufficientFunds):
+		c.balances[s.Name()] = cachedBalance{amount: 0, fetchedAt: c.nowFunc()}
+	case err == nil && result != nil && result.Cost > 0:
+		if b, ok := c.balances[s.Name()]; ok && b.amount >= 0 {
+			b.amount = max(b.amount-result.Cost, 0)
+			c.balances[s.Name()] = b
+		}
+	}
+}
+
+// balance returns a solver's cached balance, looking it up when the cache has expired.
+// Failed lookups are cached as unknown (-1) so a failing API isn't queried on every solve.
+func (c *Chain) balance(ctx context.Context, s Solver) float64 {
+	now := c.nowFunc()
+	c.mu.Lock()
+	cached, ok := c.balances[s.Name()]
+	c.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < chainBalanceTTL {
+		return cached.amount
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, chainBalanceTimeout)
+	defer cancel()
+	amount, err := s.Balance(lookupCtx)
+	if err != nil {
+		amount = -1
+	}
+
+	c.mu.Lock()
+	c.balances[s.Name()] = cachedBalance{amount: amount, fetchedAt: now}
+	c.mu.Unlock()
+	return amount
+}
+
+// Cost returns the lowest cost among solvers that can solve the challenge.
+func (c *Chain) Cost(challengeType challenge.Type) float64 {
+	minCost := float64(-1)
+	for _, s := range c.solvers {
+		if s.CanSolve(challengeType) {
+			cost := s.Cost(challengeType)
+			if minCost < 0 || cost < minCost {
+				minCost = cost
+			}
+		}
+	}
+	return minCost
+}
+
+// Balance returns the minimum balance across all solvers.
+func (c *Chain) Balance(ctx context.Context) (float64, error) {
+	minBalance := float64(-1)
+	for _, s := range c.solvers {
+		balance, err := s.Balance(ctx)
+		if err == nil && balance >= 0 {
+			if minBalance < 0 || balance < minBalance {
+				minBalance = balance
+			}
+		}
+	}
+	return minBalance, nil
+}
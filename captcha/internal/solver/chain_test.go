@@ -0,0 +1,312 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+)
+
+// stubSolver is a solver with a fixed cost and balance that fails on demand.
+type stubSolver struct {
+	name       string
+	cost       float64
+	balance    float64
+	balanceErr error
+	err        error
+
+	calls        int
+	balanceCalls int
+}
+
+func (s *stubSolver) Name() string                 { return s.name }
+func (s *stubSolver) CanSolve(challenge.Type) bool { return true }
+func (s *stubSolver) Cost(challenge.Type) float64  { return s.cost }
+
+func (s *stubSolver) Solve(ctx context.Context, params SolveParams) (*SolveResult, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &SolveResult{Token: s.name, Cost: s.cost, SolverName: s.name}, nil
+}
+
+func (s *stubSolver) Balance(ctx context.Context) (float64, error) {
+	s.balanceCalls++
+	if s.balanceErr != nil {
+		return -1, s.balanceErr
+	}
+	return s.balance, nil
+}
+
+var turnstile = SolveParams{Type: challenge.TypeCloudflareTurnstile}
+
+func TestChain_CheapestFirst(t *testing.T) {
+	expensive := &stubSolver{name: "expensive", cost: 0.003, balance: 10}
+	cheap := &stubSolver{name: "cheap", cost: 0.001, balance: 10}
+	chain := NewChain(expensive, cheap)
+
+	result, err := chain.Solve(context.Background(), turnstile)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.SolverName != "cheap" {
+		t.Errorf("SolverName = %q, want cheap", result.SolverName)
+	}
+	if expensive.calls != 0 {
+		t.Errorf("expensive solver called %d times, want 0", expensive.calls)
+	}
+}
+
+func TestChain_FreeSolverFirst(t *testing.T) {
+	paid := &stubSolver{name: "paid", cost: 0.001, balance: 10}
+	free := &stubSolver{name: "wait"}
+	chain := NewChain(paid, free)
+
+	result, err := chain.Solve(context.Background(), turnstile)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.SolverName != "wait" {
+		t.Errorf("SolverName = %q, want wait", result.SolverName)
+	}
+	if free.balanceCalls != 0 {
+		t.Error("balance looked up for a free solver")
+	}
+}
+
+func TestChain_FallsBackOnFailure(t *testing.T) {
+	cheap := &stubSolver{name: "cheap", cost: 0.001, balance: 10, err: &SolverError{Message: "unsolvable"}}
+	expensive := &stubSolver{name: "expensive", cost: 0.003, balance: 10}
+	chain := NewChain(cheap, expensive)
+
+	result, err := chain.Solve(context.Background(), turnstile)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.SolverName != "expensive" {
+		t.Errorf("SolverName = %q, want expensive", result.SolverName)
+	}
+}
+
+func TestChain_SuccessRateReorders(t *testing.T) {
+	flaky := &stubSolver{name: "flaky", cost: 0.001, balance: 10, err: &SolverError{Message: "unsolvable"}}
+	reliable := &stubSolver{name: "reliable", cost: 0.0015, balance: 10}
+	chain := NewChain(flaky, reliable)
+
+	// flaky is cheaper, so it is tried first until its failures outweigh its price
+	for i := 0; i < 5; i++ {
+		if _, err := chain.Solve(context.Background(), turnstile); err != nil {
+			t.Fatalf("Solve() error = %v", err)
+		}
+	}
+	calls := flaky.calls
+	if calls == 0 || calls == 5 {
+		t.Fatalf("flaky solver called %d times, want it tried then demoted", calls)
+	}
+
+	if _, err := chain.Solve(context.Background(), turnstile); err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if flaky.calls != calls {
+		t.Error("flaky solver still tried first after repeated failures")
+	}
+
+	// Success rates are tracked per challenge type
+	if _, err := chain.Solve(context.Background(), SolveParams{Type: challenge.TypeHCaptcha}); err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if flaky.calls != calls+1 {
+		t.Error("flaky solver's turnstile failures demoted it for hcaptcha")
+	}
+}
+
+func TestChain_TieBreaksOnBalance(t *testing.T) {
+	low := &stubSolver{name: "low", cost: 0.001, balance: 1}
+	high := &stubSolver{name: "high", cost: 0.001, balance: 5}
+	chain := NewChain(low, high)
+
+	result, err := chain.Solve(context.Background(), turnstile)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.SolverName != "high" {
+		t.Errorf("SolverName = %q, want high", result.SolverName)
+	}
+}
+
+func TestChain_SkipsInsufficientBalance(t *testing.T) {
+	broke := &stubSolver{name: "broke", cost: 0.001, balance: 0.0005}
+	funded := &stubSolver{name: "funded", cost: 0.003, balance: 10}
+	chain := NewChain(broke, funded)
+
+	result, err := chain.Solve(context.Background(), turnstile)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.SolverName != "funded" {
+		t.Errorf("SolverName = %q, want funded", result.SolverName)
+	}
+	if broke.calls != 0 {
+		t.Error("solver without balance was tried")
+	}
+
+	onlyBroke := NewChain(&stubSolver{name: "broke", cost: 0.001, balance: 0})
+	if _, err := onlyBroke.Solve(context.Background(), turnstile); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Solve() error = %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestChain_UnknownBalanceIsTried(t *testing.T) {
+	s := &stubSolver{name: "unknown", cost: 0.001, balanceErr: errors.New("unavailable")}
+	chain := NewChain(s)
+
+	if _, err := chain.Solve(context.Background(), turnstile); err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if _, err := chain.Solve(context.Background(), turnstile); err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if s.balanceCalls != 1 {
+		t.Errorf("balance looked up %d times, want 1 (cached)", s.balanceCalls)
+	}
+}
+
+func TestChain_BalanceCache(t *testing.T) {
+	s := &stubSolver{name: "paid", cost: 0.4, balance: 1}
+	chain := NewChain(s)
+	now := time.Now()
+	chain.nowFunc = func() time.Time { return now }
+
+	// Cached balance is reduced by each solve: 1 -> 0.6 -> 0.2
+	for i := 0; i < 2; i++ {
+		if _, err := chain.Solve(context.Background(), turnstile); err != nil {
+			t.Fatalf("Solve() %d error = %v", i, err)
+		}
+	}
+	if _, err := chain.Solve(context.Background(), turnstile); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Solve() error = %v, want ErrInsufficientFunds from the cached balance", err)
+	}
+	if s.balanceCalls != 1 {
+		t.Errorf("balance looked up %d times, want 1", s.balanceCalls)
+	}
+
+	// The balance is looked up again once the cache expires
+	now = now.Add(chainBalanceTTL)
+	if _, err := chain.Solve(context.Background(), turnstile); err != nil {
+		t.Fatalf("Solve() after expiry error = %v", err)
+	}
+	if s.balanceCalls != 2 {
+		t.Errorf("balance looked up %d times, want 2", s.balanceCalls)
+	}
+}
+
+func TestChain_OutOfFundsOnSolve(t *testing.T) {
+	s := &stubSolver{name: "paid", cost: 0.001, balance: 10, err: &SolverError{Message: "zero balance", Cause: ErrInsufficientFunds}}
+	chain := NewChain(s)
+
+	if _, err := chain.Solve(context.Background(), turnstile); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Solve() error = %v, want ErrInsufficientFunds", err)
+	}
+	if _, err := chain.Solve(context.Background(), turnstile); !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Solve() error = %v, want ErrInsufficientFunds", err)
+	}
+	if s.calls != 1 {
+		t.Errorf("solver called %d times, want 1 (skipped until its balance is looked up again)", s.calls)
+	}
+}
+
+func TestChain_MaxCost(t *testing.T) {
+	cheap := &stubSolver{name: "cheap", cost: 0.001, balance: 10}
+	expensive := &stubSolver{name: "expensive", cost: 0.003, balance: 10}
+	chain := NewChain(cheap, expensive)
+
+	limit := 0.002
+	cheap.err = &SolverError{Message: "unsolvable"}
+	_, err := chain.Solve(context.Background(), SolveParams{Type: challenge.TypeCloudflareTurnstile, MaxCost: &limit})
+	if err == nil {
+		t.Fatal("Solve() expected error")
+	}
+	if expensive.calls != 0 {
+		t.Error("solver over the budget was tried")
+	}
+
+	zero := 0.0
+	_, err = chain.Solve(context.Background(), SolveParams{Type: challenge.TypeCloudflareTurnstile, MaxCost: &zero})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Solve() error = %v, want ErrBudgetExceeded", err)
+	}
+
+	free := &stubSolver{name: "wait"}
+	result, err := NewChain(expensive, free).Solve(context.Background(), SolveParams{Type: challenge.TypeCloudflareTurnstile, MaxCost: &zero})
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.SolverName != "wait" {
+		t.Errorf("SolverName = %q, want wait", result.SolverName)
+	}
+}
+
+func TestChain_MaxCostCoversAllAttempts(t *testing.T) {
+	newChain := func() (*Chain, *stubSolver) {
+		expensive := &stubSolver{name: "expensive", cost: 0.003, balance: 10}
+		return NewChain(
+			&stubSolver{name: "cheap", cost: 0.001, balance: 10, err: &SolverError{Message: "unsolvable"}},
+			&stubSolver{name: "mid", cost: 0.0015, balance: 10, err: &SolverError{Message: "unsolvable"}},
+			expensive,
+		), expensive
+	}
+
+	// Without a limit every attempt is paid for and reported
+	chain, _ := newChain()
+	result, err := chain.Solve(context.Background(), turnstile)
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if math.Abs(result.Cost-0.0055) > 1e-9 {
+		t.Errorf("Cost = %v, want 0.0055 for all three attempts", result.Cost)
+	}
+
+	// Each solver fits the limit, but not after the failed attempts before it
+	chain, expensive := newChain()
+	limit := 0.003
+	result, err = chain.Solve(context.Background(), SolveParams{Type: challenge.TypeCloudflareTurnstile, MaxCost: &limit})
+	if err == nil {
+		t.Fatal("Solve() expected error")
+	}
+	if expensive.calls != 0 {
+		t.Error("solver past the remaining budget was tried")
+	}
+	if result == nil || math.Abs(result.Cost-0.0025) > 1e-9 {
+		t.Errorf("result = %+v, want the cost of the failed attempts", result)
+	}
+}
+
+func TestChain_RefusedAttemptCostsNothing(t *testing.T) {
+	s := &stubSolver{name: "paid", cost: 0.001, balance: 10, err: &SolverError{Message: "zero balance", Cause: ErrInsufficientFunds}}
+	if result, err := NewChain(s).Solve(context.Background(), turnstile); err == nil || result != nil {
+		t.Errorf("Solve() = %+v, %v, want an error without cost", result, err)
+	}
+}
+
+func TestChain_CancelledAttemptNotCounted(t *testing.T) {
+	s := &stubSolver{name: "paid", cost: 0.001, balance: 10, err: context.Canceled}
+	chain := NewChain(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _ = chain.Solve(ctx, turnstile)
+
+	if rate := chain.successRate("paid", challenge.TypeCloudflareTurnstile); rate != 0.5 {
+		t.Errorf("successRate = %v, want 0.5 (cancelled attempt not counted)", rate)
+	}
+}
+
+func TestChain_NoSolver(t *testing.T) {
+	if _, err := NewChain().Solve(context.Background(), turnstile); !errors.Is(err, ErrNoSolverAvailable) {
+		t.Errorf("Solve() error = %v, want ErrNoSolverAvailable", err)
+	}
+}
@@ -0,0 +1,172 @@
+package solver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSolverServer is a local stand-in for the paid solver APIs. It speaks both the
+// createTask/getTaskResult protocol (CapSolver, Anti-Captcha) and 2Captcha's
+// in.php/res.php protocol, and solves every task after a configurable number of polls.
+type fakeSolverServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	balance   float64
+	token     string
+	errorCode string // Returned on task creation when set
+	polls     int    // Polls answered "processing" before a task is ready
+	tasks     []map[string]any
+	pending   map[string]int
+	nextID    int
+}
+
+// newFakeSolverServer starts a fake solver server that is closed when the test ends.
+func newFakeSolverServer(t *testing.T) *fakeSolverServer {
+	t.Helper()
+	f := &fakeSolverServer{
+		balance: 10,
+		token:   "fake-token",
+		pending: make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/createTask", f.createTask)
+	mux.HandleFunc("/getTaskResult", f.getTaskResult)
+	mux.HandleFunc("/getBalance", f.getBalance)
+	mux.HandleFunc("/in.php", f.twoCaptchaIn)
+	mux.HandleFunc("/res.php", f.twoCaptchaRes)
+
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// lastTask returns the most recently submitted task.
+func (f *fakeSolverServer) lastTask() map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.tasks) == 0 {
+		return nil
+	}
+	return f.tasks[len(f.tasks)-1]
+}
+
+// newTask records a task and returns its ID, or the configured error code.
+func (f *fakeSolverServer) newTask(task map[string]any) (string, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tasks = append(f.tasks, task)
+	if f.errorCode != "" {
+		return "", f.errorCode
+	}
+	f.nextID++
+	id := strconv.Itoa(f.nextID)
+	f.pending[id] = f.polls
+	return id, ""
+}
+
+// poll reports whether a task is ready, counting down its remaining polls.
+func (f *fakeSolverServer) poll(id string) (ready, ok bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	remaining, ok := f.pending[id]
+	if !ok {
+		return false, false
+	}
+	if remaining > 0 {
+		f.pending[id] = remaining - 1
+		return false, true
+	}
+	return true, true
+}
+
+func (f *fakeSolverServer) createTask(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Task map[string]any `json:"task"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	id, errCode := f.newTask(req.Task)
+	if errCode != "" {
+		writeJSON(w, map[string]any{"errorId": 1, "errorCode": errCode})
+		return
+	}
+	writeJSON(w, map[string]any{"errorId": 0, "taskId": id})
+}
+
+func (f *fakeSolverServer) getTaskResult(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TaskID string `json:"taskId"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	ready, ok := f.poll(req.TaskID)
+	switch {
+	case !ok:
+		writeJSON(w, map[string]any{"errorId": 1, "errorCode": "ERROR_NO_SUCH_CAPCHA_ID"})
+	case !ready:
+		writeJSON(w, map[string]any{"errorId": 0, "status": "processing"})
+	default:
+		writeJSON(w, map[string]any{"errorId": 0, "status": "ready", "solution": map[string]any{"token": f.token}})
+	}
+}
+
+func (f *fakeSolverServer) getBalance(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	balance := f.balance
+	f.mu.Unlock()
+	writeJSON(w, map[string]any{"errorId": 0, "balance": balance})
+}
+
+func (f *fakeSolverServer) twoCaptchaIn(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	task := make(map[string]any, len(r.Form))
+	for k := range r.Form {
+		task[k] = r.Form.Get(k)
+	}
+
+	id, errCode := f.newTask(task)
+	if errCode != "" {
+		writeJSON(w, map[string]any{"status": 0, "request": errCode})
+		return
+	}
+	writeJSON(w, map[string]any{"status": 1, "request": id})
+}
+
+func (f *fakeSolverServer) twoCaptchaRes(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	if r.Form.Get("action") == "getbalance" {
+		f.mu.Lock()
+		balance := f.balance
+		f.mu.Unlock()
+		writeJSON(w, map[string]any{"status": 1, "request": strconv.FormatFloat(balance, 'f', -1, 64)})
+		return
+	}
+
+	ready, ok := f.poll(r.Form.Get("id"))
+	switch {
+	case !ok:
+		writeJSON(w, map[string]any{"status": 0, "request": "ERROR_WRONG_CAPTCHA_ID"})
+	case !ready:
+		writeJSON(w, map[string]any{"status": 0, "request": "CAPCHA_NOT_READY"})
+	default:
+		writeJSON(w, map[string]any{"status": 1, "request": f.token})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// fastPoll points a task protocol client at the fake server and removes its poll delay.
+func fastPoll(api *taskAPI, f *fakeSolverServer) {
+	api.baseURL = f.URL
+	api.pollDelay = time.Millisecond
+}
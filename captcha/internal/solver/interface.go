@@ -53,6 +53,10 @@ type SolveParams struct {
 
 	// Timeout is the maximum time to wait for solving.
 	Timeout time.Duration
+
+	// MaxCost is the most a solve may cost in total (nil for no limit). Solvers
+	// costing more are skipped, so a zero budget only allows free solvers.
+	MaxCost *float64
 }
 
 // ProxyConfig contains proxy configuration for CAPTCHA solving.
@@ -72,94 +76,21 @@ type SolveResult struct {
 	// Valid is how long the token is valid for.
 	Valid time.Duration
 
-	// Cost is the actual cost incurred for this solve.
+	// Cost is the actual cost incurred for this solve, including any failed paid
+	// attempts made on the way to it.
 	Cost float64
 
 	// SolverName is the name of the solver that solved this.
 	SolverName string
 }
 
-// Chain is a solver that tries multiple solvers in order.
-type Chain struct {
-	solvers []Solver
-}
-
-// NewChain creates a new solver chain.
-func NewChain(solvers ...Solver) *Chain {
-	return &Chain{solvers: solvers}
-}
-
-// Name returns "chain".
-func (c *Chain) Name() string {
-	return "chain"
-}
-
-// CanSolve returns true if any solver in the chain can solve the challenge.
-func (c *Chain) CanSolve(challengeType challenge.Type) bool {
-	for _, s := range c.solvers {
-		if s.CanSolve(challengeType) {
-			return true
-		}
-	}
-	return false
-}
-
-// Solve tries each solver in order until one succeeds.
-func (c *Chain) Solve(ctx context.Context, params SolveParams) (*SolveResult, error) {
-	var lastErr error
-
-	for _, s := range c.solvers {
-		if !s.CanSolve(params.Type) {
-			continue
-		}
-
-		result, err := s.Solve(ctx, params)
-		if err == nil {
-			return result, nil
-		}
-		lastErr = err
-	}
-
-	if lastErr != nil {
-		return nil, lastErr
-	}
-	return nil, ErrNoSolverAvailable
-}
-
-// Cost returns the lowest cost among solvers that can solve the challenge.
-func (c *Chain) Cost(challengeType challenge.Type) float64 {
-	minCost := float64(-1)
-	for _, s := range c.solvers {
-		if s.CanSolve(challengeType) {
-			cost := s.Cost(challengeType)
-			if minCost < 0 || cost < minCost {
-				minCost = cost
-			}
-		}
-	}
-	return minCost
-}
-
-// Balance returns the minimum balance across all solvers.
-func (c *Chain) Balance(ctx context.Context) (float64, error) {
-	minBalance := float64(-1)
-	for _, s := range c.solvers {
-		balance, err := s.Balance(ctx)
-		if err == nil && balance >= 0 {
-			if minBalance < 0 || balance < minBalance {
-				minBalance = balance
-			}
-		}
-	}
-	return minBalance, nil
-}
-
 // Errors
 var (
 	ErrNoSolverAvailable = &SolverError{Message: "no solver available for this challenge type"}
 	ErrSolverTimeout     = &SolverError{Message: "solver timeout"}
 	ErrSolverFailed      = &SolverError{Message: "solver failed"}
 	ErrInsufficientFunds = &SolverError{Message: "insufficient funds"}
+	ErrBudgetExceeded    = &SolverError{Message: "solver spend budget exceeded"}
 )
 
 // SolverError represents a solver error.
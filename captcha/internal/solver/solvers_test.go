@@ -0,0 +1,227 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmylchreest/refyne-api/captcha/internal/challenge"
+)
+
+func TestCapSolver_Solve(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.polls = 2
+	s := NewCapSolver("key")
+	fastPoll(s.api, f)
+
+	result, err := s.Solve(context.Background(), SolveParams{
+		Type:    challenge.TypeCloudflareTurnstile,
+		SiteKey: "site-key",
+		PageURL: "https://example.com",
+		Action:  "login",
+	})
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.Token != "fake-token" {
+		t.Errorf("Token = %q, want %q", result.Token, "fake-token")
+	}
+	if result.SolverName != "capsolver" {
+		t.Errorf("SolverName = %q, want %q", result.SolverName, "capsolver")
+	}
+	if result.Cost != capSolverTurnstilePrice {
+		t.Errorf("Cost = %v, want %v", result.Cost, capSolverTurnstilePrice)
+	}
+
+	task := f.lastTask()
+	if task["type"] != "AntiTurnstileTaskProxyLess" {
+		t.Errorf("task type = %v, want AntiTurnstileTaskProxyLess", task["type"])
+	}
+	if task["websiteKey"] != "site-key" {
+		t.Errorf("websiteKey = %v, want site-key", task["websiteKey"])
+	}
+}
+
+func TestCapSolver_SolveWithProxy(t *testing.T) {
+	f := newFakeSolverServer(t)
+	s := NewCapSolver("key")
+	fastPoll(s.api, f)
+
+	_, err := s.Solve(context.Background(), SolveParams{
+		Type:    challenge.TypeReCaptchaV2,
+		SiteKey: "site-key",
+		PageURL: "https://example.com",
+		Proxy:   &ProxyConfig{Type: "http", Host: "proxy.example.com", Port: 8080, Username: "u", Password: "p"},
+	})
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	task := f.lastTask()
+	if task["type"] != "ReCaptchaV2Task" {
+		t.Errorf("task type = %v, want ReCaptchaV2Task", task["type"])
+	}
+	if task["proxy"] != "http:proxy.example.com:8080:u:p" {
+		t.Errorf("proxy = %v, want http:proxy.example.com:8080:u:p", task["proxy"])
+	}
+}
+
+func TestCapSolver_Unsupported(t *testing.T) {
+	s := NewCapSolver("key")
+	if s.CanSolve(challenge.TypeHCaptcha) {
+		t.Error("CanSolve(hcaptcha) = true, want false")
+	}
+	if _, err := s.Solve(context.Background(), SolveParams{Type: challenge.TypeHCaptcha}); err == nil {
+		t.Error("Solve(hcaptcha) expected error")
+	}
+}
+
+func TestAntiCaptcha_Solve(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.polls = 1
+	s := NewAntiCaptcha("key")
+	fastPoll(s.api, f)
+
+	result, err := s.Solve(context.Background(), SolveParams{
+		Type:    challenge.TypeHCaptcha,
+		SiteKey: "site-key",
+		PageURL: "https://example.com",
+		Proxy:   &ProxyConfig{Type: "http", Host: "proxy.example.com", Port: 8080},
+	})
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.Token != "fake-token" {
+		t.Errorf("Token = %q, want %q", result.Token, "fake-token")
+	}
+	if result.SolverName != "anticaptcha" {
+		t.Errorf("SolverName = %q, want %q", result.SolverName, "anticaptcha")
+	}
+
+	task := f.lastTask()
+	if task["type"] != "HCaptchaTask" {
+		t.Errorf("task type = %v, want HCaptchaTask", task["type"])
+	}
+	if task["proxyAddress"] != "proxy.example.com" {
+		t.Errorf("proxyAddress = %v, want proxy.example.com", task["proxyAddress"])
+	}
+	if _, ok := task["proxyLogin"]; ok {
+		t.Error("proxyLogin set for a proxy without credentials")
+	}
+}
+
+func TestAntiCaptcha_ReCaptchaV3IsProxyless(t *testing.T) {
+	f := newFakeSolverServer(t)
+	s := NewAntiCaptcha("key")
+	fastPoll(s.api, f)
+
+	_, err := s.Solve(context.Background(), SolveParams{
+		Type:    challenge.TypeReCaptchaV3,
+		SiteKey: "site-key",
+		PageURL: "https://example.com",
+		Proxy:   &ProxyConfig{Type: "http", Host: "proxy.example.com", Port: 8080},
+	})
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+
+	task := f.lastTask()
+	if task["type"] != "RecaptchaV3TaskProxyless" {
+		t.Errorf("task type = %v, want RecaptchaV3TaskProxyless", task["type"])
+	}
+	if _, ok := task["proxyAddress"]; ok {
+		t.Error("proxyAddress set on a proxyless task")
+	}
+}
+
+func TestTaskAPI_ZeroBalance(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.errorCode = "ERROR_ZERO_BALANCE"
+	s := NewAntiCaptcha("key")
+	fastPoll(s.api, f)
+
+	_, err := s.Solve(context.Background(), SolveParams{Type: challenge.TypeCloudflareTurnstile})
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Solve() error = %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestTaskAPI_Balance(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.balance = 4.25
+	s := NewCapSolver("key")
+	fastPoll(s.api, f)
+
+	balance, err := s.Balance(context.Background())
+	if err != nil {
+		t.Fatalf("Balance() error = %v", err)
+	}
+	if balance != 4.25 {
+		t.Errorf("Balance() = %v, want 4.25", balance)
+	}
+}
+
+func TestTaskAPI_ContextCancelled(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.polls = 1000
+	s := NewCapSolver("key")
+	fastPoll(s.api, f)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := s.Solve(ctx, SolveParams{Type: challenge.TypeCloudflareTurnstile})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Solve() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestTwoCaptcha_Solve(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.polls = 1
+	s := NewTwoCaptcha("key")
+	s.baseURL = f.URL
+	s.pollDelay = time.Millisecond
+
+	result, err := s.Solve(context.Background(), SolveParams{
+		Type:    challenge.TypeCloudflareTurnstile,
+		SiteKey: "site-key",
+		PageURL: "https://example.com",
+	})
+	if err != nil {
+		t.Fatalf("Solve() error = %v", err)
+	}
+	if result.Token != "fake-token" {
+		t.Errorf("Token = %q, want %q", result.Token, "fake-token")
+	}
+	if task := f.lastTask(); task["method"] != "turnstile" {
+		t.Errorf("method = %v, want turnstile", task["method"])
+	}
+}
+
+func TestTwoCaptcha_ZeroBalance(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.errorCode = "ERROR_ZERO_BALANCE"
+	s := NewTwoCaptcha("key")
+	s.baseURL = f.URL
+
+	_, err := s.Solve(context.Background(), SolveParams{Type: challenge.TypeHCaptcha})
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Solve() error = %v, want ErrInsufficientFunds", err)
+	}
+}
+
+func TestTwoCaptcha_Balance(t *testing.T) {
+	f := newFakeSolverServer(t)
+	f.balance = 1.5
+	s := NewTwoCaptcha("key")
+	s.baseURL = f.URL
+
+	balance, err := s.Balance(context.Background())
+	if err != nil {
+		t.Fatalf("Balance() error = %v", err)
+	}
+	if balance != 1.5 {
+		t.Errorf("Balance() = %v, want 1.5", balance)
+	}
+}
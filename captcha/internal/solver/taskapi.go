@@ -0,0 +1,158 @@
+package solver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// taskAPI is a client for the createTask/getTaskResult JSON protocol shared by
+// CapSolver and Anti-Captcha. Each service only differs in its base URL and task names.
+type taskAPI struct {
+	name       string
+	baseURL    string
+	apiKey     string
+	client     *http.Client
+	pollDelay  time.Duration
+	maxRetries int
+}
+
+// newTaskAPI creates a task protocol client.
+func newTaskAPI(name, baseURL, apiKey string) *taskAPI {
+	return &taskAPI{
+		name:    name,
+		baseURL: baseURL,
+		apiKey:  apiKey,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		pollDelay:  3 * time.Second,
+		maxRetries: 100, // 5 minutes max (100 * 3s)
+	}
+}
+
+// taskResponse is the common envelope of task protocol responses.
+type taskResponse struct {
+	ErrorID          int             `json:"errorId"`
+	ErrorCode        string          `json:"errorCode"`
+	ErrorDescription string          `json:"errorDescription"`
+	TaskID           json.RawMessage `json:"taskId"` // Number (Anti-Captcha) or string (CapSolver)
+	Status           string          `json:"status"` // "processing" or "ready"
+	Solution         struct {
+		Token              string `json:"token"`
+		GRecaptchaResponse string `json:"gRecaptchaResponse"`
+	} `json:"solution"`
+	Balance float64 `json:"balance"`
+}
+
+// err returns the response's error, or nil if the call succeeded.
+func (r *taskResponse) err(name string) error {
+	if r.ErrorID == 0 {
+		return nil
+	}
+	solverErr := &SolverError{Message: fmt.Sprintf("%s error: %s", name, r.ErrorCode)}
+	if r.ErrorDescription != "" {
+		solverErr.Message += " (" + r.ErrorDescription + ")"
+	}
+	if r.ErrorCode == "ERROR_ZERO_BALANCE" {
+		solverErr.Cause = ErrInsufficientFunds
+	}
+	return solverErr
+}
+
+// solve creates a task and polls until its token is ready.
+func (a *taskAPI) solve(ctx context.Context, task map[string]any) (string, error) {
+	var created taskResponse
+	if err := a.call(ctx, "/createTask", map[string]any{"clientKey": a.apiKey, "task": task}, &created); err != nil {
+		return "", err
+	}
+	if err := created.err(a.name); err != nil {
+		return "", err
+	}
+	if len(created.TaskID) == 0 {
+		return "", &SolverError{Message: a.name + " returned no task ID"}
+	}
+
+	for i := 0; i < a.maxRetries; i++ {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(a.pollDelay):
+		}
+
+		var result taskResponse
+		if err := a.call(ctx, "/getTaskResult", map[string]any{"clientKey": a.apiKey, "taskId": created.TaskID}, &result); err != nil {
+			continue
+		}
+		if err := result.err(a.name); err != nil {
+			return "", err
+		}
+		if result.Status != "ready" {
+			continue
+		}
+
+		token := result.Solution.Token
+		if token == "" {
+			token = result.Solution.GRecaptchaResponse
+		}
+		if token == "" {
+			return "", &SolverError{Message: a.name + " returned an empty solution"}
+		}
+		return token, nil
+	}
+
+	return "", ErrSolverTimeout
+}
+
+// balance returns the account balance.
+func (a *taskAPI) balance(ctx context.Context) (float64, error) {
+	var result taskResponse
+	if err := a.call(ctx, "/getBalance", map[string]any{"clientKey": a.apiKey}, &result); err != nil {
+		return -1, err
+	}
+	if err := result.err(a.name); err != nil {
+		return -1, err
+	}
+	return result.Balance, nil
+}
+
+// call posts a JSON request to the API and decodes the response.
+func (a *taskAPI) call(ctx context.Context, path string, body any, out *taskResponse) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse %s response (status %d): %w", a.name, resp.StatusCode, err)
+	}
+	return nil
+}
+
+// proxyTaskFields returns the task fields for solving through a proxy.
+func proxyTaskFields(proxy *ProxyConfig) map[string]any {
+	fields := map[string]any{
+		"proxyType":    proxy.Type,
+		"proxyAddress": proxy.Host,
+		"proxyPort":    proxy.Port,
+	}
+	if proxy.Username != "" {
+		fields["proxyLogin"] = proxy.Username
+		fields["proxyPassword"] = proxy.Password
+	}
+	return fields
+}
@@ -24,6 +24,7 @@ const (
 
 // TwoCaptcha implements the Solver interface using 2Captcha's API.
 type TwoCaptcha struct {
+	baseURL    string
 	apiKey     string
 	client     *http.Client
 	pollDelay  time.Duration
@@ -33,7 +34,8 @@ type TwoCaptcha struct {
 // NewTwoCaptcha creates a new 2Captcha solver.
 func NewTwoCaptcha(apiKey string) *TwoCaptcha {
 	return &TwoCaptcha{
-		apiKey: apiKey,
+		baseURL: twoCaptchaBaseURL,
+		apiKey:  apiKey,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -197,7 +199,11 @@ func (t *TwoCaptcha) submitTask(ctx context.Context, params SolveParams) (string
 	}
 
 	if result.Status != 1 {
-		return "", &SolverError{Message: fmt.Sprintf("2captcha error: %s", result.Request)}
+		solverErr := &SolverError{Message: fmt.Sprintf("2captcha error: %s", result.Request)}
+		if result.Request == "ERROR_ZERO_BALANCE" {
+			solverErr.Cause = ErrInsufficientFunds
+		}
+		return "", solverErr
 	}
 
 	return result.Request, nil
@@ -262,7 +268,7 @@ func (t *TwoCaptcha) pollResult(ctx context.Context, taskID string) (string, err
 
 // request makes an HTTP request to the 2Captcha API.
 func (t *TwoCaptcha) request(ctx context.Context, path string, values url.Values) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", twoCaptchaBaseURL+path+"?"+values.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", t.baseURL+path+"?"+values.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -17,6 +17,10 @@ const DEFAULT_MODELS: Record<string, string> = {
   openai: 'gpt-4o-mini',
   ollama: 'llama3.2',
   helicone: 'gpt-4o-mini',
+  gemini: 'gemini-2.5-flash',
+  mistral: 'mistral-small-latest',
+  groq: 'openai/gpt-oss-120b',
+  together: 'meta-llama/Llama-3.3-70B-Instruct-Turbo',
 };
 
 export interface ChainEntry {
@@ -14,6 +14,16 @@ import type {
   ApiKey,
   ApiKeyWithSecret,
   UsageSummary,
+  SpendCap,
+  SpendCapInput,
+  Proxy,
+  ProxyInput,
+  ProxyHealth,
+  AuthProfile,
+  AuthProfileInput,
+  CreditBalance,
+  CreditTransactionPage,
+  Statement,
   LLMConfig,
   LLMConfigInput,
   ServiceKey,
@@ -26,12 +36,14 @@ import type {
   FallbackChainEntryInput,
   UserFallbackChainEntry,
   UserFallbackChainEntryInput,
+  RoutingPolicy,
   ModelValidationRequest,
   ModelValidationResult,
   SubscriptionTier,
   TierValidationRequest,
   TierValidationResult,
   TierLimits,
+  License,
   Schema,
   CreateSchemaInput,
   UpdateSchemaInput,
@@ -229,6 +241,102 @@ export async function getUsage(period = 'month') {
   return request<UsageSummary>('GET', `/api/v1/usage?period=${period}`);
 }
 
+export async function listSpendCaps() {
+  return request<{ spend_caps: SpendCap[] }>('GET', '/api/v1/spend-caps');
+}
+
+export async function setSpendCap(input: SpendCapInput) {
+  return request<SpendCap>('PUT', '/api/v1/spend-caps', input);
+}
+
+export async function deleteSpendCap(id: string) {
+  return request<{ success: boolean }>('DELETE', `/api/v1/spend-caps/${id}`);
+}
+
+// ==================== Proxies ====================
+
+export async function listProxies() {
+  return request<{ proxies: Proxy[] }>('GET', '/api/v1/proxies');
+}
+
+export async function createProxy(input: ProxyInput) {
+  return request<Proxy>('POST', '/api/v1/proxies', input);
+}
+
+export async function updateProxy(id: string, input: ProxyInput) {
+  return request<Proxy>('PUT', `/api/v1/proxies/${id}`, input);
+}
+
+export async function deleteProxy(id: string) {
+  return request<{ success: boolean }>('DELETE', `/api/v1/proxies/${id}`);
+}
+
+export async function getProxyHealth(id: string) {
+  return request<{ health: ProxyHealth[] }>('GET', `/api/v1/proxies/${id}/health`);
+}
+
+// ==================== Auth Profiles ====================
+
+export async function listAuthProfiles() {
+  return request<{ auth_profiles: AuthProfile[] }>('GET', '/api/v1/auth-profiles');
+}
+
+export async function createAuthProfile(input: AuthProfileInput) {
+  return request<AuthProfile>('POST', '/api/v1/auth-profiles', input);
+}
+
+export async function updateAuthProfile(id: string, input: AuthProfileInput) {
+  return request<AuthProfile>('PUT', `/api/v1/auth-profiles/${id}`, input);
+}
+
+export async function deleteAuthProfile(id: string) {
+  return request<{ success: boolean }>('DELETE', `/api/v1/auth-profiles/${id}`);
+}
+
+export async function loginAuthProfile(id: string) {
+  return request<AuthProfile>('POST', `/api/v1/auth-profiles/${id}/login`);
+}
+
+// ==================== Billing ====================
+
+export async function getCreditBalance() {
+  return request<CreditBalance>('GET', '/api/v1/billing/balance');
+}
+
+export async function listCreditTransactions(limit = 50, offset = 0) {
+  return request<CreditTransactionPage>('GET', `/api/v1/billing/transactions?limit=${limit}&offset=${offset}`);
+}
+
+// Period is a YYYY-MM month or 'current' for the current subscription period
+export async function getStatement(period = 'current') {
+  return request<Statement>('GET', `/api/v1/billing/statements/${period}`);
+}
+
+// Returns the statement as CSV text for download
+export async function getStatementCSV(period = 'current'): Promise<string> {
+  const headers: Record<string, string> = {};
+
+  const tokenGetter = getTokenGetter();
+  if (tokenGetter) {
+    const token = await tokenGetter();
+    if (token) {
+      headers['Authorization'] = `Bearer ${token}`;
+    }
+  }
+
+  const response = await fetch(`${API_BASE_URL}/api/v1/billing/statements/${period}?format=csv`, {
+    method: 'GET',
+    headers,
+  });
+
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({}));
+    throw { error: error.error || `Request failed (${response.status})`, status: response.status };
+  }
+
+  return response.text();
+}
+
 // ==================== LLM Config ====================
 
 export async function getLLMConfig() {
@@ -257,13 +365,21 @@ export async function deleteServiceKey(provider: string) {
 
 export async function getFallbackChain(tier?: string) {
   const query = tier !== undefined ? `?tier=${encodeURIComponent(tier)}` : '';
-  return request<{ chain: FallbackChainEntry[]; tiers: string[] }>('GET', `/api/v1/admin/fallback-chain${query}`);
+  return request<{ chain: FallbackChainEntry[]; tiers: string[]; routing_policies: Record<string, RoutingPolicy> }>(
+    'GET',
+    `/api/v1/admin/fallback-chain${query}`
+  );
 }
 
-export async function setFallbackChain(chain: FallbackChainEntryInput[], tier?: string | null) {
+export async function setFallbackChain(
+  chain: FallbackChainEntryInput[],
+  tier?: string | null,
+  routingPolicy?: RoutingPolicy
+) {
   return request<{ chain: FallbackChainEntry[] }>('PUT', '/api/v1/admin/fallback-chain', {
     chain,
     tier: tier === 'default' ? null : tier,
+    routing_policy: routingPolicy,
   });
 }
 
@@ -290,11 +406,11 @@ export async function listLLMProviders() {
 // ==================== User Fallback Chain ====================
 
 export async function getUserFallbackChain() {
-  return request<{ chain: UserFallbackChainEntry[] }>('GET', '/api/v1/llm/chain');
+  return request<{ chain: UserFallbackChainEntry[]; routing_policy: RoutingPolicy }>('GET', '/api/v1/llm/chain');
 }
 
-export async function setUserFallbackChain(chain: UserFallbackChainEntryInput[]) {
-  return request<{ chain: UserFallbackChainEntry[] }>('PUT', '/api/v1/llm/chain', { chain });
+export async function setUserFallbackChain(chain: UserFallbackChainEntryInput[], routingPolicy?: RoutingPolicy) {
+  return request<{ chain: UserFallbackChainEntry[] }>('PUT', '/api/v1/llm/chain', { chain, routing_policy: routingPolicy });
 }
 
 // ==================== Provider Models ====================
@@ -329,6 +445,36 @@ export async function syncTiers() {
   return request<{ message: string }>('POST', '/api/v1/admin/tiers/sync');
 }
 
+// ==================== License (self-hosted) ====================
+
+export async function getLicense() {
+  return request<License>('GET', '/api/v1/admin/license');
+}
+
+export async function replaceLicense(licenseKey: string) {
+  return request<License>('PUT', '/api/v1/admin/license', { license_key: licenseKey });
+}
+
+export async function adminListProxies() {
+  return request<{ proxies: Proxy[] }>('GET', '/api/v1/admin/proxies');
+}
+
+export async function adminCreateProxy(input: ProxyInput) {
+  return request<Proxy>('POST', '/api/v1/admin/proxies', input);
+}
+
+export async function adminUpdateProxy(id: string, input: ProxyInput) {
+  return request<Proxy>('PUT', `/api/v1/admin/proxies/${id}`, input);
+}
+
+export async function adminDeleteProxy(id: string) {
+  return request<{ success: boolean }>('DELETE', `/api/v1/admin/proxies/${id}`);
+}
+
+export async function adminGetProxyHealth(id: string) {
+  return request<{ health: ProxyHealth[] }>('GET', `/api/v1/admin/proxies/${id}/health`);
+}
+
 // ==================== Schema Catalog ====================
 
 export async function listSchemas(category?: string, includePublic = true) {
@@ -141,6 +141,7 @@ export interface CreateCrawlJobInput {
     extract_from_seeds?: boolean;
     use_sitemap?: boolean;
     fetch_mode?: 'auto' | 'static' | 'dynamic';
+    auth_profile_id?: string;
   };
   webhook_url?: string;
 }
@@ -171,6 +172,170 @@ export interface UsageSummary {
   byok_jobs: number;
 }
 
+export type SpendCapPeriod = 'daily' | 'monthly';
+
+export interface SpendUsage {
+  cost_usd: number;
+  tokens: number;
+  pages: number;
+}
+
+export interface SpendCap {
+  id: string;
+  api_key_id?: string;
+  period: SpendCapPeriod;
+  max_cost_usd?: number;
+  max_tokens?: number;
+  max_pages?: number;
+  usage: SpendUsage;
+  period_start: string;
+  percent: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface SpendCapInput {
+  api_key_id?: string;
+  period: SpendCapPeriod;
+  max_cost_usd?: number;
+  max_tokens?: number;
+  max_pages?: number;
+}
+
+export interface Proxy {
+  id: string;
+  name: string;
+  endpoint: string;
+  country?: string;
+  is_enabled: boolean;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ProxyInput {
+  name: string;
+  url?: string;
+  country?: string;
+  is_enabled: boolean;
+}
+
+export interface ProxyHealth {
+  domain: string;
+  successes: number;
+  failures: number;
+  bans: number;
+  score: number;
+  banned: boolean;
+  last_banned_at?: string;
+  last_used_at: string;
+}
+
+export interface AuthProfile {
+  id: string;
+  name: string;
+  type: 'cookies' | 'login';
+  login_url?: string;
+  has_cookies: boolean;
+  cookies_updated_at?: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface AuthProfileCookie {
+  name: string;
+  value: string;
+  domain?: string;
+  path?: string;
+  expires?: number;
+  http_only?: boolean;
+  secure?: boolean;
+}
+
+export interface AuthLoginScript {
+  fields: { selector: string; value: string }[];
+  submit_selector: string;
+  success_selector: string;
+}
+
+export interface AuthProfileInput {
+  name: string;
+  type: 'cookies' | 'login';
+  login_url?: string;
+  cookies?: AuthProfileCookie[];
+  script?: AuthLoginScript;
+}
+
+export interface CreditBalance {
+  balance_usd: number;
+  available_balance_usd: number;
+  lifetime_added_usd: number;
+  lifetime_spent_usd: number;
+  monthly_spend_usd: number;
+  period_start?: string;
+  period_end?: string;
+}
+
+export type CreditTransactionType = 'subscription' | 'topup' | 'usage' | 'expiry' | 'refund' | 'adjustment';
+
+export interface CreditTransaction {
+  id: string;
+  type: CreditTransactionType;
+  amount_usd: number;
+  balance_after_usd: number;
+  expires_at?: string;
+  is_expired: boolean;
+  job_id?: string;
+  description: string;
+  created_at: string;
+}
+
+export interface CreditTransactionPage {
+  transactions: CreditTransaction[];
+  limit: number;
+  offset: number;
+  has_more: boolean;
+}
+
+export interface StatementLine {
+  date: string;
+  usage_id: string;
+  job_id?: string;
+  type: string;
+  status: string;
+  url?: string;
+  provider?: string;
+  model?: string;
+  byok: boolean;
+  tokens_input: number;
+  tokens_output: number;
+  llm_cost_usd: number;
+  markup_rate: number;
+  markup_usd: number;
+  per_transaction_usd: number;
+  charged_usd: number;
+  created_at: string;
+}
+
+export interface StatementTotals {
+  jobs: number;
+  byok_jobs: number;
+  tokens_input: number;
+  tokens_output: number;
+  llm_cost_usd: number;
+  markup_usd: number;
+  per_transaction_usd: number;
+  charged_usd: number;
+  byok_llm_cost_usd: number;
+}
+
+export interface Statement {
+  period: string;
+  period_start: string;
+  period_end: string;
+  lines: StatementLine[];
+  totals: StatementTotals;
+}
+
 // ==================== LLM Config Types ====================
 
 export interface LLMConfig {
@@ -248,6 +413,9 @@ export interface ProviderModel {
 
 // ==================== Fallback Chain Types ====================
 
+// How a fallback chain picks the model to try first
+export type RoutingPolicy = 'ordered' | 'cheapest_capable' | 'fastest' | 'weighted';
+
 export interface FallbackChainEntry {
   id: string;
   position: number;
@@ -255,6 +423,7 @@ export interface FallbackChainEntry {
   model: string;
   temperature?: number;
   max_tokens?: number;
+  weight?: number;
   is_enabled: boolean;
   created_at: string;
   updated_at: string;
@@ -265,6 +434,7 @@ export interface FallbackChainEntryInput {
   model: string;
   temperature?: number;
   max_tokens?: number;
+  weight?: number;
   is_enabled: boolean;
 }
 
@@ -275,6 +445,7 @@ export interface UserFallbackChainEntry {
   model: string;
   temperature?: number;
   max_tokens?: number;
+  weight?: number;
   is_enabled: boolean;
   created_at: string;
   updated_at: string;
@@ -285,6 +456,7 @@ export interface UserFallbackChainEntryInput {
   model: string;
   temperature?: number;
   max_tokens?: number;
+  weight?: number;
   is_enabled: boolean;
 }
 
@@ -323,6 +495,22 @@ export interface TierValidationResult {
   message?: string;
 }
 
+// Self-hosted license (admin)
+export interface License {
+  status: 'valid' | 'grace' | 'expired' | 'invalid' | 'missing';
+  license_id?: string;
+  organization_name?: string;
+  email?: string;
+  tier?: string;
+  max_users: number;
+  features: string[];
+  issued_at?: string;
+  expires_at?: string;
+  grace_ends_at?: string;
+  error?: string;
+  checked_at: string;
+}
+
 export interface TierLimits {
   name: string;
   display_name: string;
@@ -330,6 +518,8 @@ export interface TierLimits {
   max_concurrent_jobs: number;
   max_pages_per_crawl: number;
   requests_per_minute: number;
+  requests_per_hour: number;
+  requests_per_day: number;
   credit_allocation_usd: number;
   credit_rollover_months: number;
 }
@@ -16,6 +16,11 @@ export const PROVIDER_DEFAULTS: Record<string, ModelSettings> = {
   openrouter: { temperature: 0.2, maxTokens: 6144, strictMode: false },
   ollama: { temperature: 0.1, maxTokens: 4096, strictMode: false },
   helicone: { temperature: 0.2, maxTokens: 8192, strictMode: true }, // Helicone proxies OpenAI-compatible APIs
+  gemini: { temperature: 0.2, maxTokens: 16384, strictMode: false }, // Gemini uses responseJsonSchema, not strict
+  mistral: { temperature: 0.2, maxTokens: 16384, strictMode: true },
+  groq: { temperature: 0.2, maxTokens: 8192, strictMode: false }, // json_schema on a subset of models only
+  together: { temperature: 0.2, maxTokens: 8192, strictMode: false },
+  openai_compatible: { temperature: 0.1, maxTokens: 8192, strictMode: true }, // vLLM, LM Studio, llama.cpp
 };
 
 // Model-specific overrides for models that need different defaults